@@ -79,6 +79,8 @@ const (
 	caCertFileKey               = "caCertFile"
 	tlsModeFlag                 = "tls-mode"
 	tlsModeKey                  = "tlsMode"
+	nmaTLSVerifyFlag            = "nma-tls-verify-mode"
+	httpsTLSVerifyFlag          = "https-tls-verify-mode"
 	passwordFlag                = "password"
 	passwordKey                 = "password"
 	passwordFileFlag            = "password-file"
@@ -89,6 +91,7 @@ const (
 	configKey                   = "config"
 	verboseFlag                 = "verbose"
 	verboseKey                  = "verbose"
+	syslogFlag                  = "syslog"
 	outputFileFlag              = "output-file"
 	outputFileKey               = "outputFile"
 	subclusterFlag              = "subcluster"
@@ -129,12 +132,18 @@ const (
 	targetCaCertFileKey    = "targetCaCertFile"
 	targetTLSModeFlag      = "target-tls-mode"
 	targetTLSModeKey       = "targetTLSMode"
+	targetTLSVerifyFlag    = "target-tls-verify-mode"
 	targetIPv6Flag         = "target-ipv6"
 	targetIPv6Key          = "targetIPv6"
+	targetSOCKS5ProxyFlag  = "target-socks5-proxy"
+	targetSOCKS5ProxyKey   = "targetSOCKS5Proxy"
+	socks5ProxyFlag        = "socks5-proxy"
 	asyncFlag              = "async"
 	asyncKey               = "async"
 	sourceTLSConfigFlag    = "source-tlsconfig"
 	sourceTLSConfigKey     = "sourceTLSConfig"
+	targetTLSConfigFlag    = "target-tlsconfig"
+	targetTLSConfigKey     = "targetTLSConfig"
 	tableOrSchemaNameFlag  = "table-or-schema-name"
 	tableOrSchemaNameKey   = "tableOrSchemaName"
 	includePatternFlag     = "include-pattern"
@@ -145,6 +154,38 @@ const (
 	targetNamespaceKey     = "targetNamespace"
 	transactionIDFlag      = "transaction-id"
 	transactionIDKey       = "transactionID"
+	targetRoleFlag         = "target-role"
+	targetRoleKey          = "targetRole"
+	targetResourcePoolFlag = "target-resource-pool"
+	targetResourcePoolKey  = "targetResourcePool"
+	targetTrustAuthFlag    = "target-trust-auth"
+	targetTrustAuthKey     = "targetTrustAuth"
+	fixCredForwardingFlag  = "fix-credential-forwarding"
+	fixCredForwardingKey   = "fixCredentialForwarding"
+	revertCredForwardFlag  = "revert-credential-forwarding"
+	revertCredForwardKey   = "revertCredentialForwarding"
+	txnStateFileFlag       = "transaction-state-file"
+	txnStateFileKey        = "transactionStateFile"
+	lockWaitTimeoutFlag    = "lock-wait-timeout"
+	lockWaitTimeoutKey     = "lockWaitTimeout"
+	skipLockCheckFlag      = "skip-lock-check"
+	skipLockCheckKey       = "skipLockCheck"
+	sourceSnapshotFlag     = "source-snapshot"
+	sourceSnapshotKey      = "sourceSnapshot"
+	projectionModeFlag     = "projection-mode"
+	projectionModeKey      = "projectionMode"
+	dryRunFlag             = "dry-run"
+	dryRunKey              = "dryRun"
+	allowNoMatchFlag       = "allow-no-matching-objects"
+	allowNoMatchKey        = "allowNoMatchingObjects"
+	atEpochFlag            = "at-epoch"
+	atEpochKey             = "atEpoch"
+	atTimeFlag             = "at-time"
+	atTimeKey              = "atTime"
+	targetHostStrategyFlag = "target-host-strategy"
+	targetHostStrategyKey  = "targetHostStrategy"
+	renameObjectFlag       = "rename-object"
+	renameObjectKey        = "renameObject"
 )
 
 // flags to viper key map
@@ -181,13 +222,31 @@ var flagKeyMap = map[string]string{
 	targetCaCertFileFlag:        targetCaCertFileKey,
 	targetTLSModeFlag:           targetTLSModeKey,
 	targetIPv6Flag:              targetIPv6Key,
+	targetSOCKS5ProxyFlag:       targetSOCKS5ProxyKey,
 	asyncFlag:                   asyncKey,
 	sourceTLSConfigFlag:         sourceTLSConfigKey,
+	targetTLSConfigFlag:         targetTLSConfigKey,
 	tableOrSchemaNameFlag:       tableOrSchemaNameKey,
 	includePatternFlag:          includePatternKey,
 	excludePatternFlag:          excludePatternKey,
 	targetNamespaceFlag:         targetNamespaceKey,
 	transactionIDFlag:           transactionIDKey,
+	targetRoleFlag:              targetRoleKey,
+	targetResourcePoolFlag:      targetResourcePoolKey,
+	targetTrustAuthFlag:         targetTrustAuthKey,
+	fixCredForwardingFlag:       fixCredForwardingKey,
+	revertCredForwardFlag:       revertCredForwardKey,
+	txnStateFileFlag:            txnStateFileKey,
+	lockWaitTimeoutFlag:         lockWaitTimeoutKey,
+	skipLockCheckFlag:           skipLockCheckKey,
+	sourceSnapshotFlag:          sourceSnapshotKey,
+	projectionModeFlag:          projectionModeKey,
+	dryRunFlag:                  dryRunKey,
+	allowNoMatchFlag:            allowNoMatchKey,
+	atEpochFlag:                 atEpochKey,
+	atTimeFlag:                  atTimeKey,
+	targetHostStrategyFlag:      targetHostStrategyKey,
+	renameObjectFlag:            renameObjectKey,
 }
 
 // target database flags to viper key map
@@ -201,6 +260,7 @@ var targetFlagKeyMap = map[string]string{
 	targetCaCertFileFlag:   targetCaCertFileKey,
 	targetTLSModeFlag:      targetTLSModeKey,
 	targetIPv6Flag:         targetIPv6Key,
+	targetSOCKS5ProxyFlag:  targetSOCKS5ProxyKey,
 }
 
 // map of viper keys to environment variables
@@ -240,6 +300,8 @@ const (
 	scrutinizeSubCmd        = "scrutinize"
 	showRestorePointsSubCmd = "show_restore_points"
 	installPkgSubCmd        = "install_packages"
+	supportBundleSubCmd     = "support_bundle"
+	doctorSubCmd            = "doctor"
 	// hidden Cmds (for internal testing only)
 	promoteSandboxSubCmd    = "promote_sandbox"
 	createArchiveCmd        = "create_archive"
@@ -251,11 +313,16 @@ const (
 // commands
 type cmdGlobals struct {
 	verbose    bool
+	syslog     bool
 	file       *os.File
 	keyFile    string
 	certFile   string
 	caCertFile string
 	tlsMode    string
+	// the TLS verification modes of the NMA and HTTPS clients, which take
+	// precedence over the TLS mode
+	nmaTLSVerifyMode   string
+	httpsTLSVerifyMode string
 
 	// Global variables for targetDB are used for the replication subcommand
 	targetHosts        []string
@@ -268,6 +335,9 @@ type cmdGlobals struct {
 	targetCaCertFile   string
 	targetTLSMode      string
 	targetIPv6         bool
+	targetSOCKS5Proxy  string
+	// the TLS verification mode of the HTTPS client of the target database
+	targetTLSVerifyMode string
 }
 
 var (
@@ -324,6 +394,15 @@ func initVcc(cmd *cobra.Command) vclusterops.VClusterCommands {
 	// setup logs
 	logger := vlog.Printer{ForCli: true}
 	logger.SetupOrDie(dbOptions.LogPath)
+	if globals.syslog {
+		// the connection to syslog is closed when the process exits
+		syslogLogger, _, err := logger.WithSyslog(vlog.SyslogConfig{})
+		if err != nil {
+			logger.PrintWarning("fail to connect to syslog, logging to the log file only: %v", err)
+		} else {
+			logger = syslogLogger
+		}
+	}
 
 	vcc := vclusterops.VClusterCommands{
 		VClusterCommandsLogger: vclusterops.VClusterCommandsLogger{
@@ -399,6 +478,8 @@ func setTargetDBOptionsUsingViper(flag string) error {
 		globals.targetTLSMode = viper.GetString(targetTLSModeKey)
 	case targetIPv6Flag:
 		globals.targetIPv6 = viper.GetBool(targetIPv6Key)
+	case targetSOCKS5ProxyFlag:
+		globals.targetSOCKS5Proxy = viper.GetString(targetSOCKS5ProxyKey)
 	case verboseFlag:
 		globals.verbose = viper.GetBool(verboseKey)
 	default:
@@ -425,8 +506,10 @@ func configViper(cmd *cobra.Command, flagsInConfig []string) error {
 	// - manage_config
 	// - manage_config show
 	// - create_connection
+	// - support_bundle
 	if cmd.CalledAs() != manageConfigSubCmd &&
-		cmd.CalledAs() != configShowSubCmd && cmd.CalledAs() != createConnectionSubCmd {
+		cmd.CalledAs() != configShowSubCmd && cmd.CalledAs() != createConnectionSubCmd &&
+		cmd.CalledAs() != supportBundleSubCmd {
 		flagsInConfig = append(flagsInConfig, certFileFlag, keyFileFlag, caCertFileFlag, tlsModeFlag)
 	}
 
@@ -469,11 +552,12 @@ func bindKeysToEnv() error {
 func loadConfig(cmd *cobra.Command) (err error) {
 	// load db options from config file to viper
 	// note: config file is not available for create_db and revive_db
-	//       manage_config does not need viper to load config file info
+	//       manage_config and support_bundle do not need viper to load config file info
 	if cmd.CalledAs() != createDBSubCmd &&
 		cmd.CalledAs() != reviveDBSubCmd &&
 		cmd.CalledAs() != configRecoverSubCmd &&
-		cmd.CalledAs() != configShowSubCmd {
+		cmd.CalledAs() != configShowSubCmd &&
+		cmd.CalledAs() != supportBundleSubCmd {
 		err := loadConfigToViper()
 		if err != nil {
 			return err
@@ -621,6 +705,8 @@ func constructCmds() []*cobra.Command {
 		makeCmdRemoveNode(),
 		// others
 		makeCmdScrutinize(),
+		makeCmdSupportBundle(),
+		makeCmdDoctor(),
 		makeCmdManageConfig(),
 		makeCmdReplication(),
 		makeCmdGetReplicationStatus(),
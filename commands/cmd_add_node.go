@@ -89,6 +89,18 @@ func (c *CmdAddNode) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Whether to delete any existing database directories in the new hosts before attempting to add them.",
 	)
+	cmd.Flags().BoolVar(
+		&c.addNodeOptions.SkipLicenseCheck,
+		"skip-license-check",
+		false,
+		"Whether to skip checking that the license limits allow the new nodes.",
+	)
+	cmd.Flags().BoolVar(
+		&c.addNodeOptions.HardwareCheck.SkipHardwareCheck,
+		"skip-hardware-check",
+		false,
+		"Whether to skip warning about new nodes whose hardware is unlike the other nodes of the subcluster.",
+	)
 	cmd.Flags().BoolVar(
 		c.addNodeOptions.SkipRebalanceShards,
 		"skip-rebalance-shards",
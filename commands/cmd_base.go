@@ -75,6 +75,8 @@ func (c *CmdBase) ValidateParseBaseOptions(opt *vclusterops.DatabaseOptions) err
 	if err != nil {
 		return err
 	}
+	opt.NMATLSVerifyMode = globals.nmaTLSVerifyMode
+	opt.HTTPSTLSVerifyMode = globals.httpsTLSVerifyMode
 
 	return nil
 }
@@ -95,6 +97,7 @@ func (c *CmdBase) ValidateParseBaseTargetOptions(opt *vclusterops.DatabaseOption
 	if err != nil {
 		return err
 	}
+	opt.HTTPSTLSVerifyMode = globals.targetTLSVerifyMode
 
 	return nil
 }
@@ -155,9 +158,17 @@ func (c *CmdBase) setCommonFlags(cmd *cobra.Command, flags []string) {
 		"Whether show the details of VCluster run in the console",
 	)
 
+	// syslog is a flag that all the subcommands need
+	cmd.Flags().BoolVar(
+		&globals.syslog,
+		syslogFlag,
+		false,
+		"Whether to also send the log entries to the local syslog daemon or journald",
+	)
+
 	// TLS related flags are allowed by all subcommands,
-	// except for create_connection and manage_config show.
-	if cmd.Name() != configShowSubCmd && cmd.Name() != createConnectionSubCmd {
+	// except for create_connection, manage_config show and support_bundle.
+	if cmd.Name() != configShowSubCmd && cmd.Name() != createConnectionSubCmd && cmd.Name() != supportBundleSubCmd {
 		c.setTLSFlags(cmd)
 	}
 
@@ -211,7 +222,8 @@ func (c *CmdBase) setConfigFlags(cmd *cobra.Command, flags []string) {
 			&dbOptions.RawHosts,
 			hostsFlag,
 			[]string{},
-			"A comma-separated list of hosts in database.")
+			"A comma-separated list of hosts in database. "+
+				"Use host:port, or [host]:port for IPv6, for a host whose HTTPS port is not the default one.")
 	}
 	if util.StringInArray(catalogPathFlag, flags) {
 		cmd.Flags().StringVar(
@@ -313,6 +325,22 @@ func (c *CmdBase) setTLSFlags(cmd *cobra.Command) {
 		fmt.Sprintf("Mode for TLS validation. Allowed values '%s', '%s', and '%s'. Default value is '%s'.",
 			tlsModeEnable, tlsModeVerifyCA, tlsModeVerifyFull, tlsModeEnable),
 	)
+	cmd.Flags().StringVar(
+		&globals.nmaTLSVerifyMode,
+		nmaTLSVerifyFlag,
+		"",
+		fmt.Sprintf("How the certificates of the NMA servers are verified, in place of --%s. "+
+			"Allowed values '%s', '%s', '%s', and '%s'.", tlsModeFlag, vclusterops.TLSVerifyFull,
+			vclusterops.TLSVerifyCAOnly, vclusterops.TLSVerifyCAOrSystem, vclusterops.TLSVerifyInsecure),
+	)
+	cmd.Flags().StringVar(
+		&globals.httpsTLSVerifyMode,
+		httpsTLSVerifyFlag,
+		"",
+		fmt.Sprintf("How the certificates of the HTTPS servers are verified, in place of --%s. "+
+			"Allowed values '%s', '%s', '%s', and '%s'.", tlsModeFlag, vclusterops.TLSVerifyFull,
+			vclusterops.TLSVerifyCAOnly, vclusterops.TLSVerifyCAOrSystem, vclusterops.TLSVerifyInsecure),
+	)
 }
 
 func (c *CmdBase) setTargetDBFlags(cmd *cobra.Command) {
@@ -320,7 +348,8 @@ func (c *CmdBase) setTargetDBFlags(cmd *cobra.Command) {
 		&globals.targetHosts,
 		targetHostsFlag,
 		[]string{},
-		"A comma-separated list of hosts in target database.",
+		"A comma-separated list of hosts in target database. "+
+			"Use host:port, or [host]:port for IPv6, for a host whose HTTPS port is not the default one.",
 	)
 	cmd.Flags().StringVar(
 		&globals.targetUserName,
@@ -381,11 +410,25 @@ func (c *CmdBase) setTargetDBFlags(cmd *cobra.Command) {
 			"Allowed values '%s', '%s', and '%s'. Default value is '%s'.",
 			tlsModeEnable, tlsModeVerifyCA, tlsModeVerifyFull, tlsModeEnable),
 	)
+	cmd.Flags().StringVar(
+		&globals.targetTLSVerifyMode,
+		targetTLSVerifyFlag,
+		"",
+		fmt.Sprintf("How the certificates of the HTTPS servers of the target database are verified, in place of --%s. "+
+			"Allowed values '%s', '%s', '%s', and '%s'.", targetTLSModeFlag, vclusterops.TLSVerifyFull,
+			vclusterops.TLSVerifyCAOnly, vclusterops.TLSVerifyCAOrSystem, vclusterops.TLSVerifyInsecure),
+	)
 	cmd.Flags().BoolVar(
 		&globals.targetIPv6,
 		targetIPv6Flag,
 		false,
 		"Whether the target hosts use IPv6 addresses. Hostnames resolve to IPv4 by default.")
+	cmd.Flags().StringVar(
+		&globals.targetSOCKS5Proxy,
+		targetSOCKS5ProxyFlag,
+		"",
+		"The SOCKS5 proxy to reach the target hosts through, in the form of socks5://[user:password@]host:port.",
+	)
 }
 
 func (c *CmdBase) initConfigParam() error {
@@ -32,6 +32,9 @@ import (
 
 type CmdCreateDB struct {
 	createDBOptions *vclusterops.VCreateDatabaseOptions
+	// the creation profile to use, read from the profile file
+	profileFile string
+	profileName string
 	CmdBase
 }
 
@@ -165,6 +168,43 @@ func (c *CmdCreateDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Skips installing the packages in /opt/vertica/packages.",
 	)
+	cmd.Flags().BoolVar(
+		&c.createDBOptions.SkipLicenseCheck,
+		"skip-license-check",
+		false,
+		"Skips checking that the license limits allow the nodes of the database.",
+	)
+	cmd.Flags().BoolVar(
+		&c.createDBOptions.HardwareCheck.SkipHardwareCheck,
+		"skip-hardware-check",
+		false,
+		"Skips warning about nodes whose hardware is unlike the bootstrap node.",
+	)
+	cmd.Flags().BoolVar(
+		&c.createDBOptions.SmokeTest.RunSmokeTest,
+		"smoke-test",
+		false,
+		"Checks that every node can query a test table after the database is created.",
+	)
+	cmd.Flags().IntVar(
+		&c.createDBOptions.KSafety,
+		"k-safety",
+		util.DefaultKSafety,
+		"The K-safety of the database design, 0 or 1. By default, 1 if the database has at least 3 nodes.",
+	)
+	cmd.Flags().StringVar(
+		&c.profileFile,
+		"profile-file",
+		"",
+		"Path to a JSON file of creation profiles.",
+	)
+	cmd.Flags().StringVar(
+		&c.profileName,
+		"profile",
+		"",
+		"Name of the creation profile in --profile-file whose settings are used for the options that are not set.",
+	)
+	cmd.MarkFlagsRequiredTogether("profile-file", "profile")
 	cmd.Flags().IntVar(
 		&c.createDBOptions.TimeoutNodeStartupSeconds,
 		"startup-timeout",
@@ -269,6 +309,13 @@ func (c *CmdCreateDB) validateParse(logger vlog.Printer) error {
 	if err != nil {
 		return err
 	}
+
+	if c.profileName != "" {
+		c.createDBOptions.Profile, err = vclusterops.ReadCreateDBProfile(c.profileFile, c.profileName)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,171 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdDoctor
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdDoctor struct {
+	doctorOptions *vclusterops.VDoctorOptions
+
+	CmdBase
+}
+
+func makeCmdDoctor() *cobra.Command {
+	newCmd := &CmdDoctor{}
+
+	opt := vclusterops.VDoctorOptionsFactory()
+	newCmd.doctorOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		doctorSubCmd,
+		"Runs all the checks of a database and reports the problems found.",
+		`Runs the following checks of a database, and outputs their findings as JSON,
+the most severe first:
+- health: the database is reachable and its nodes are up
+- connectivity: the node management agent responds on every host
+- certificates: the TLS certificates are valid and do not expire soon
+- clock: the clocks of the hosts are synchronized and in the same time zone
+- disk_space: the storage locations are not close to full
+- config_drift: the hosts of the config file are the nodes of the database
+
+Each finding has a machine-readable code and a suggested remediation. The
+command fails if any finding is critical.
+
+Examples:
+  # Check the database of the config file
+  vcluster doctor --password "PASSWORD" \
+    --config /opt/vertica/config/vertica_cluster.yaml
+
+  # Check the database without the clock check, and save the findings to a file
+  vcluster doctor --db-name test_db --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --skip-checks clock --output-file /tmp/doctor.json
+`,
+		[]string{dbNameFlag, hostsFlag, passwordFlag, ipv6Flag, catalogPathFlag, configFlag, outputFileFlag},
+	)
+
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdDoctor) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(
+		&c.doctorOptions.SkipChecks,
+		"skip-checks",
+		[]string{},
+		"Comma-separated list of the checks not to run.",
+	)
+	cmd.Flags().IntVar(
+		&c.doctorOptions.CertExpiryDays,
+		"cert-expiry-days",
+		vclusterops.DefaultDoctorCertExpiryDays,
+		"Report the certificates that expire within this number of days.",
+	)
+	cmd.Flags().IntVar(
+		&c.doctorOptions.DiskWarningPercent,
+		"disk-warning-percent",
+		vclusterops.DefaultDoctorDiskWarningPercent,
+		"Report the storage locations that are at least this percent full as a warning.",
+	)
+	cmd.Flags().IntVar(
+		&c.doctorOptions.DiskCriticalPercent,
+		"disk-critical-percent",
+		vclusterops.DefaultDoctorDiskCriticalPercent,
+		"Report the storage locations that are at least this percent full as critical.",
+	)
+}
+
+func (c *CmdDoctor) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.doctorOptions.DatabaseOptions)
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdDoctor) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()", "command", doctorSubCmd)
+	if !c.usePassword() {
+		err := c.getCertFilesFromCertPaths(&c.doctorOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.ValidateParseBaseOptions(&c.doctorOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.doctorOptions.DatabaseOptions)
+}
+
+func (c *CmdDoctor) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.V(1).Info("Called method Run()")
+
+	report, err := vcc.VDoctor(c.doctorOptions)
+	if err != nil {
+		vcc.LogError(err, "failed to run the doctor checks")
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the doctor report: %w", err)
+	}
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	vcc.LogInfo("Doctor report: ", "report", string(bytes))
+	// if writing into stdout, add a new line
+	// otherwise, the successful message may be wrapped into the same line of the report
+	if c.output == "" {
+		fmt.Println("")
+	}
+
+	if report.HasCritical() {
+		var codes []string
+		for i := range report.Findings {
+			if report.Findings[i].Severity == vclusterops.DoctorSeverityCritical {
+				codes = append(codes, report.Findings[i].Code)
+			}
+		}
+		return fmt.Errorf("the doctor found critical problems: %s", strings.Join(codes, ", "))
+	}
+	vcc.DisplayInfo("Found %d problem(s) in database %s, none of them critical", len(report.Findings),
+		c.doctorOptions.DBName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdDoctor
+func (c *CmdDoctor) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.doctorOptions.DatabaseOptions = *opt
+}
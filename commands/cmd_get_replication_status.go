@@ -17,6 +17,7 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -34,6 +35,10 @@ type CmdGetReplicationStatus struct {
 	replicationStatusOptions *vclusterops.VReplicationStatusDatabaseOptions
 	CmdBase
 	targetPasswordFile string
+	wait               bool
+	waitTimeout        int
+	stallTimeout       int
+	failOnStall        bool
 }
 
 func makeCmdGetReplicationStatus() *cobra.Command {
@@ -57,6 +62,15 @@ Examples:
   vcluster replication status --target-db-name platform_db --target-hosts 10.20.30.43 \
     --target-db-user dbadmin --target-password-file /path/to/password-file \
     --transaction-id 12345678901234567
+
+  # Wait for the replication job to finish, for at most one hour
+  vcluster replication status --target-conn /opt/vertica/config/target_connection.yaml \
+    --transaction-id 12345678901234567 --wait --wait-timeout 3600
+
+  # Wait for the replication job to finish, and collect diagnostics if it
+  # makes no progress for ten minutes
+  vcluster replication status --target-conn /opt/vertica/config/target_connection.yaml \
+    --transaction-id 12345678901234567 --wait --stall-timeout 600 --fail-on-stall
 `,
 		[]string{outputFileFlag, targetIPv6Flag, targetHostsFlag, targetUserNameFlag, targetPasswordFileFlag, targetConnFlag,
 			targetKeyFileFlag, targetCertFileFlag, targetCaCertFileFlag, targetTLSModeFlag},
@@ -91,6 +105,34 @@ func (c *CmdGetReplicationStatus) setLocalFlags(cmd *cobra.Command) {
 		0,
 		"[Required] The transaction ID of the asynchronous replication job output by the replication start command.",
 	)
+	cmd.Flags().BoolVar(
+		&c.wait,
+		"wait",
+		false,
+		"Wait for the replication job to finish. If a target host goes down while waiting, "+
+			"the status is retrieved from the other target hosts.",
+	)
+	cmd.Flags().IntVar(
+		&c.waitTimeout,
+		"wait-timeout",
+		0,
+		"The timeout in seconds to wait for the replication job to finish. "+
+			"A value <= 0 means waiting until the job finishes. Only used with --wait.",
+	)
+	cmd.Flags().IntVar(
+		&c.stallTimeout,
+		"stall-timeout",
+		vclusterops.DefaultReplicationStallTimeout,
+		"The time in seconds after which a replication job that made no progress is reported as stalled. "+
+			"A value <= 0 means stalls are not detected. Only used with --wait.",
+	)
+	cmd.Flags().BoolVar(
+		&c.failOnStall,
+		"fail-on-stall",
+		false,
+		"Stop waiting for a stalled replication job, and output the diagnostics of the target initiator. "+
+			"Only used with --wait.",
+	)
 }
 
 func (c *CmdGetReplicationStatus) Parse(inputArgv []string, logger vlog.Printer) error {
@@ -161,8 +203,43 @@ func (c *CmdGetReplicationStatus) Run(vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.replicationStatusOptions
-
-	replicationStatus, err := vcc.VReplicationStatus(options)
+	options.TargetDB.ConnectionMetrics = &vclusterops.ConnectionMetrics{}
+
+	var replicationStatus *vclusterops.ReplicationStatusResponse
+	var err error
+	if c.wait {
+		pollOptions := vclusterops.VPollReplicationStatusFactory()
+		pollOptions.VReplicationStatusDatabaseOptions = *options
+		pollOptions.PollingTimeout = c.waitTimeout
+		pollOptions.StallTimeout = c.stallTimeout
+		pollOptions.FailOnStall = c.failOnStall
+		pollOptions.OnProgress = func(progress vclusterops.ReplicationProgress) {
+			vcc.LogInfo("replication progress", "opName", progress.Status.OpName, "status", progress.Status.Status,
+				"sentBytes", progress.Status.SentBytes, "totalBytes", progress.Status.TotalBytes,
+				"bytesPerSecond", progress.BytesPerSecond, "rowsPerSecond", progress.RowsPerSecond,
+				"stalled", progress.Stalled)
+		}
+		replicationStatus, err = vcc.VPollReplicationStatus(&pollOptions)
+	} else {
+		replicationStatus, err = vcc.VReplicationStatus(options)
+	}
+	for host, metrics := range options.TargetDB.ConnectionMetrics.Hosts() {
+		vcc.LogInfo("target connection metrics", "host", host, "requests", metrics.Requests,
+			"connectionFailures", metrics.ConnectionFailures, "tlsErrors", metrics.TLSErrors,
+			"averageLatency", metrics.AverageLatency(), "lastError", metrics.LastError)
+		if err != nil && metrics.ConnectionFailures > 0 {
+			vcc.DisplayWarning("%d of %d requests to target host %s could not connect, last error: %s",
+				metrics.ConnectionFailures, metrics.Requests, host, metrics.LastError)
+		}
+	}
+	if stalledErr := (*vclusterops.ReplicationStalledError)(nil); errors.As(err, &stalledErr) &&
+		stalledErr.Diagnostics != nil {
+		bytes, marshalErr := json.MarshalIndent(stalledErr.Diagnostics, "", "  ")
+		if marshalErr != nil {
+			return marshalErr
+		}
+		c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+	}
 	if err != nil {
 		vcc.LogError(err, "failed to get replication status", "targetDB", options.TargetDB.DBName)
 		return err
@@ -191,5 +268,6 @@ func (c *CmdGetReplicationStatus) SetDatabaseOptions(_ *vclusterops.DatabaseOpti
 	c.replicationStatusOptions.TargetDB.DBName = globals.targetDB
 	c.replicationStatusOptions.TargetDB.Hosts = globals.targetHosts
 	c.replicationStatusOptions.TargetDB.IPv6 = globals.targetIPv6
+	c.replicationStatusOptions.TargetDB.SOCKS5Proxy = globals.targetSOCKS5Proxy
 	c.targetPasswordFile = globals.targetPasswordFile
 }
@@ -87,6 +87,12 @@ Examples:
 
 // setLocalFlags will set the local flags the command has
 func (c *CmdReviveDB) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&dbOptions.SOCKS5Proxy,
+		socks5ProxyFlag,
+		"",
+		"The SOCKS5 proxy to reach the hosts through, in the form of socks5://[user:password@]host:port.",
+	)
 	cmd.Flags().UintVar(
 		&c.reviveDBOptions.LoadCatalogTimeout,
 		"load-catalog-timeout",
@@ -143,6 +149,20 @@ func (c *CmdReviveDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Revive the database on main cluster, but do not touch any of the sandboxes",
 	)
+	cmd.Flags().StringSliceVar(
+		&c.reviveDBOptions.Namespaces,
+		"namespaces",
+		[]string{},
+		"A comma-separated list of namespaces to revive. If you specify this option or --schemas,\n"+
+			"only these namespaces and schemas are revived, if the hosts support it.",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.reviveDBOptions.Schemas,
+		"schemas",
+		[]string{},
+		"A comma-separated list of schemas to revive, qualified by their namespace, e.g., ns1.s1,\n"+
+			"unless they are in the default namespace.",
+	)
 	// only one of restore-point-index or restore-point-id" will be required
 	cmd.MarkFlagsMutuallyExclusive("restore-point-index", "restore-point-id")
 }
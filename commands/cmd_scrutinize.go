@@ -119,6 +119,19 @@ func (c *CmdScrutinize) setLocalFlags(cmd *cobra.Command) {
 			"to avoid shell substitution.",
 	)
 
+	cmd.Flags().StringVar(
+		&dbOptions.HostSelector,
+		"host-selector",
+		"",
+		"Expression that selects the nodes to scrutinize, "+
+			"e.g., \"subcluster == 'etl' && state == 'UP'\"",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.sOptions.Sandboxes,
+		"sandboxes",
+		[]string{},
+		"Comma-separated list of sandboxes whose nodes are collected along with the main cluster.",
+	)
 	cmd.Flags().StringVar(
 		&c.sOptions.TarballName,
 		"tarball-name",
@@ -186,6 +199,45 @@ func (c *CmdScrutinize) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Skip gathering linked and catalog-shared libraries.",
 	)
+	cmd.Flags().BoolVar(
+		&c.sOptions.IncludeContainerDiagnostics,
+		"include-container-diagnostics",
+		isK8sEnvironment(),
+		"Include pod logs, resource limits, and OOM events of the nodes that run in Kubernetes.\n"+
+			"This option is enabled by default when running in Kubernetes.",
+	)
+	cmd.Flags().BoolVar(
+		&c.sOptions.Anonymize,
+		"anonymize",
+		false,
+		"Replace the hosts, IP addresses, database and user names in all collected files with pseudonyms,\n"+
+			"so that the bundle can be shared under data-privacy constraints.",
+	)
+	cmd.Flags().StringSliceVar(
+		&c.sOptions.AnonymizeTerms,
+		"anonymize-terms",
+		[]string{},
+		"Comma-separated list of additional names to replace with pseudonyms when --anonymize is set.",
+	)
+	cmd.Flags().StringVar(
+		&c.sOptions.AnonymizationMapPath,
+		"anonymization-map",
+		"",
+		"Path of a file to save the pseudonyms to when --anonymize is set. Do not share this file with the bundle.",
+	)
+	cmd.Flags().BoolVar(
+		&c.sOptions.CleanupStagingData,
+		"cleanup-staging-data",
+		false,
+		"Delete the staging data of older scrutinize collections from /tmp/scrutinize on the hosts once the collection is done.",
+	)
+	cmd.Flags().IntVar(
+		&c.sOptions.StagingRetentionHours,
+		"staging-retention-hours",
+		vclusterops.ScrutinizeStagingRetentionHoursDefault,
+		"The age, in hours, above which the staging data is deleted when --cleanup-staging-data is set."+
+			util.Default+fmt.Sprint(vclusterops.ScrutinizeStagingRetentionHoursDefault),
+	)
 }
 
 func (c *CmdScrutinize) Parse(inputArgv []string, logger vlog.Printer) error {
@@ -536,6 +588,14 @@ func lookupAndCheckSecretEnvVars(nameEnv, namespaceEnv string) (*types.Namespace
 	if !nameSpaceSet {
 		return nil, nil
 	}
+	err := util.ValidateK8sDNSLabel(secretNameSpace, "secret namespace")
+	if err != nil {
+		return nil, err
+	}
+	err = util.ValidateK8sDNSSubdomain(secretName, "secret")
+	if err != nil {
+		return nil, err
+	}
 	secret := &types.NamespacedName{
 		Name:      secretName,
 		Namespace: secretNameSpace,
@@ -93,6 +93,12 @@ Examples:
 
 // setLocalFlags will set the local flags the command has
 func (c *CmdShowRestorePoints) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&dbOptions.SOCKS5Proxy,
+		socks5ProxyFlag,
+		"",
+		"The SOCKS5 proxy to reach the hosts through, in the form of socks5://[user:password@]host:port.",
+	)
 	cmd.Flags().StringVar(
 		&c.showRestorePointsOptions.FilterOptions.ArchiveName,
 		"restore-point-archive",
@@ -111,6 +111,12 @@ func (c *CmdStartDB) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Starts the database on a main cluster and does not start any sandboxes.",
 	)
+	cmd.Flags().BoolVar(
+		&c.startDBOptions.SmokeTest.RunSmokeTest,
+		"smoke-test",
+		false,
+		"Checks that every node can query a test table after the database is started.",
+	)
 }
 
 // setHiddenFlags will set the hidden flags the command has.
@@ -33,6 +33,7 @@ type CmdStartReplication struct {
 	startRepOptions *vclusterops.VReplicationDatabaseOptions
 	CmdBase
 	targetPasswordFile string
+	atEpoch            int64
 }
 
 func makeCmdStartReplication() *cobra.Command {
@@ -46,10 +47,15 @@ func makeCmdStartReplication() *cobra.Command {
 		"Starts database replication",
 		`Replicates a table or schema from a source database to a target database. 
 		
-The options --target-db-user and --target-password-file can be omitted 
-if any one of the following conditions are met:
-  - The source database has EnableConnectCredentialForwarding enabled.
-  - The target database uses trust authentication.
+The source user is replicated as the same user in the target database, unless
+--target-db-user maps it to a different target user. The target user can run
+under a specific role with --target-role. The target user is authenticated
+with one of the following:
+  - --target-password-file, the target user's password.
+  - --source-tlsconfig, a TLS configuration for the target database.
+  - --target-trust-auth, when the target database uses trust authentication.
+  - The credentials of the source user, when the target user is the source
+    user and the source database has EnableConnectCredentialForwarding enabled.
 
 Examples:
   # Start database replication with config and connection file
@@ -93,9 +99,11 @@ Examples:
 	// tableOrSchema or pattern can not be accepted together
 	cmd.MarkFlagsMutuallyExclusive(tableOrSchemaNameFlag, includePatternFlag)
 	cmd.MarkFlagsMutuallyExclusive(tableOrSchemaNameFlag, excludePatternFlag)
+	cmd.MarkFlagsMutuallyExclusive(asyncFlag, fixCredForwardingFlag)
+	cmd.MarkFlagsMutuallyExclusive(lockWaitTimeoutFlag, skipLockCheckFlag)
 
 	// hide eon mode flag since we expect it to come from config file, not from user input
-	hideLocalFlags(cmd, []string{eonModeFlag, asyncFlag, tableOrSchemaNameFlag,
+	hideLocalFlags(cmd, []string{eonModeFlag, asyncFlag, txnStateFileFlag, tableOrSchemaNameFlag,
 		includePatternFlag, excludePatternFlag, targetNamespaceFlag})
 	return cmd
 }
@@ -121,6 +129,123 @@ func (c *CmdStartReplication) setLocalFlags(cmd *cobra.Command) {
 		"The TLS configuration to use when connecting to the target database.\n "+
 			"This TLS configuration must also exist in the source database.",
 	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.TargetTLSConfig,
+		targetTLSConfigFlag,
+		"",
+		"The TLS configuration of the target database to use for the replication, if it differs from the source one.\n "+
+			"This TLS configuration must exist in the target database.",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.TargetRole,
+		targetRoleFlag,
+		"",
+		"The role of the target user to replicate under. If omitted, the default roles of the target user are used.",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.TargetResourcePool,
+		targetResourcePoolFlag,
+		"",
+		"The resource pool of the target database to apply the replicated data in. "+
+			"If omitted, the default pool of the target user is used.",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.TargetTrustAuth,
+		targetTrustAuthFlag,
+		false,
+		"Set if the target database uses trust authentication for the target user, "+
+			"so no target password or TLS configuration is required.",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.FixCredentialForwarding,
+		fixCredForwardingFlag,
+		false,
+		"If the replication fails because EnableConnectCredentialForwarding is disabled in the source database, "+
+			"enable it and retry the replication.",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.RevertCredentialForwarding,
+		revertCredForwardFlag,
+		false,
+		"Disable EnableConnectCredentialForwarding again after the retried replication. "+
+			"Only used with --"+fixCredForwardingFlag+".",
+	)
+	cmd.Flags().IntVar(
+		&c.startRepOptions.LockWaitTimeout,
+		lockWaitTimeoutFlag,
+		0,
+		"The seconds to wait for DDL and catalog locks on the objects to replicate to be released "+
+			"before replication starts. If 0, replication fails right away and lists the blocking sessions.",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.SkipLockCheck,
+		skipLockCheckFlag,
+		false,
+		"Start replication without checking for DDL and catalog locks on the objects to replicate.",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.SourceSnapshot,
+		sourceSnapshotFlag,
+		"",
+		"The source snapshot to replicate data as of: "+vclusterops.ReplicationSnapshotLatestCommitted+
+			" to copy the data last committed when each object is replicated, or "+
+			vclusterops.ReplicationSnapshotCurrentEpoch+" to copy all objects as of the current epoch. "+
+			"Default value is "+vclusterops.ReplicationSnapshotLatestCommitted+".",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.ProjectionMode,
+		projectionModeFlag,
+		"",
+		"How the projections of the replicated tables are handled in the target database: "+
+			vclusterops.ReplicationProjectionsReplicate+" to copy them as they are, or "+
+			vclusterops.ReplicationProjectionsRebuild+" to rebuild them after the data is copied. "+
+			"Default value is "+vclusterops.ReplicationProjectionsReplicate+".",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.TargetHostStrategy,
+		targetHostStrategyFlag,
+		"",
+		"How the target host that the source database connects to is picked: "+
+			vclusterops.ReplicationTargetHostFirst+" for the first target host, "+
+			vclusterops.ReplicationTargetHostFirstUp+" for the first target host whose NMA responds, "+
+			vclusterops.ReplicationTargetHostRoundRobin+" to rotate through the target hosts across replications, or "+
+			vclusterops.ReplicationTargetHostLowestLatency+" for the target host whose NMA responds the fastest. "+
+			"Default value is "+vclusterops.ReplicationTargetHostFirst+".",
+	)
+	cmd.Flags().StringToStringVar(
+		&c.startRepOptions.ObjectRenames,
+		renameObjectFlag,
+		map[string]string{},
+		"A comma-separated list of source=target pairs of schemas or tables to replicate under another name "+
+			"in the target database, e.g., prod=prod_copy. A schema can only be renamed to a schema, and a table to a table.",
+	)
+	cmd.Flags().Int64Var(
+		&c.atEpoch,
+		atEpochFlag,
+		0,
+		"The epoch of the source database to replicate as of, so that several replications copy the same snapshot. "+
+			"Cannot be used with --"+atTimeFlag+" or --"+sourceSnapshotFlag+".",
+	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.AtTime,
+		atTimeFlag,
+		"",
+		"The time to replicate the source database as of, e.g., \"2024-06-01 12:00:00\". "+
+			"Cannot be used with --"+atEpochFlag+" or --"+sourceSnapshotFlag+".",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.DryRun,
+		dryRunFlag,
+		false,
+		"List the objects of the source database that would be replicated, without replicating them.",
+	)
+	cmd.Flags().BoolVar(
+		&c.startRepOptions.AllowNoMatchingObjects,
+		allowNoMatchFlag,
+		false,
+		"Warn instead of failing when the table or schema name or the include pattern matches no objects "+
+			"of the source database.",
+	)
 	cmd.Flags().BoolVar(
 		&c.startRepOptions.Async,
 		asyncFlag,
@@ -128,6 +253,13 @@ func (c *CmdStartReplication) setLocalFlags(cmd *cobra.Command) {
 		"If set to true, will run the replicate operation asynchronously. "+
 			"Default value is false.",
 	)
+	cmd.Flags().StringVar(
+		&c.startRepOptions.TransactionStateFile,
+		txnStateFileFlag,
+		"",
+		"(only async replication)The file to save the transaction ID of the replication job to, "+
+			"so its status can still be retrieved if this command exits before the job finishes.",
+	)
 	cmd.Flags().StringVar(
 		&c.startRepOptions.TableOrSchemaName,
 		tableOrSchemaNameFlag,
@@ -136,21 +268,22 @@ func (c *CmdStartReplication) setLocalFlags(cmd *cobra.Command) {
 			" types are: namespace, schema, table. If this is omitted, the operator"+
 			" will replicate all namespaces in the source database.",
 	)
-	cmd.Flags().StringVar(
-		&c.startRepOptions.IncludePattern,
+	cmd.Flags().StringArrayVar(
+		&c.startRepOptions.IncludePatterns,
 		includePatternFlag,
-		"",
+		[]string{},
 		"(only async replication)A string containing a wildcard pattern of the schemas and/or tables to"+
-			"include in the replication. Namespace names must be front-qualified "+
-			"with a period.",
+			" include in the replication. Namespace names must be front-qualified "+
+			"with a period. Repeat the option to include the objects matching any of several patterns.",
 	)
-	cmd.Flags().StringVar(
-		&c.startRepOptions.ExcludePattern,
+	cmd.Flags().StringArrayVar(
+		&c.startRepOptions.ExcludePatterns,
 		excludePatternFlag,
-		"",
+		[]string{},
 		"(only async replication)A string containing a wildcard pattern of the schemas and/or tables"+
-			" to exclude from the set of tables matched by the include pattern. "+
-			"Namespace names must be front-qualified with a period.",
+			" to exclude from the set of tables matched by the include patterns. "+
+			"Namespace names must be front-qualified with a period. "+
+			"Repeat the option to exclude the objects matching any of several patterns.",
 	)
 	cmd.Flags().StringVar(
 		&c.startRepOptions.TargetNamespace,
@@ -210,6 +343,10 @@ func (c *CmdStartReplication) validateParse(logger vlog.Printer) error {
 		return err
 	}
 
+	if c.parser.Changed(atEpochFlag) {
+		c.startRepOptions.Epoch = &c.atEpoch
+	}
+
 	err = c.ValidateParseBaseTargetOptions(&c.startRepOptions.TargetDB)
 	if err != nil {
 		return err
@@ -259,6 +396,7 @@ func (c *CmdStartReplication) Run(vcc vclusterops.ClusterCommands) error {
 	vcc.LogInfo("Called method Run()")
 
 	options := c.startRepOptions
+	options.ReplicatedEpoch = new(int64)
 
 	transactionID, err := vcc.VReplicateDatabase(options)
 	if err != nil {
@@ -266,11 +404,23 @@ func (c *CmdStartReplication) Run(vcc vclusterops.ClusterCommands) error {
 		return err
 	}
 
+	if options.DryRun {
+		vcc.DisplayInfo("Dry run: %d objects would be replicated to database %s", len(options.MatchedObjects),
+			options.TargetDB.DBName)
+		for _, object := range options.MatchedObjects {
+			vcc.DisplayInfo("  %s", object)
+		}
+		return nil
+	}
 	if options.Async {
 		vcc.DisplayInfo("Successfully started replication to database %s. Transaction ID: %d", options.TargetDB.DBName, transactionID)
 	} else {
 		vcc.DisplayInfo("Successfully replicated to database %s", options.TargetDB.DBName)
 	}
+	// the epoch is only reported by newer versions of the source database
+	if *options.ReplicatedEpoch > 0 {
+		vcc.DisplayInfo("Replicated data as of source epoch %d", *options.ReplicatedEpoch)
+	}
 
 	return nil
 }
@@ -282,5 +432,6 @@ func (c *CmdStartReplication) SetDatabaseOptions(opt *vclusterops.DatabaseOption
 	c.startRepOptions.TargetDB.DBName = globals.targetDB
 	c.startRepOptions.TargetDB.Hosts = globals.targetHosts
 	c.startRepOptions.TargetDB.IPv6 = globals.targetIPv6
+	c.startRepOptions.TargetDB.SOCKS5Proxy = globals.targetSOCKS5Proxy
 	c.targetPasswordFile = globals.targetPasswordFile
 }
@@ -54,6 +54,10 @@ Examples:
   vcluster stop_subcluster --subcluster sc1 --drain-seconds 10 \
     --config /opt/vertica/config/vertica_cluster.yaml --password "PASSWORD"
 
+  # Stop a subcluster and clear its depot with config file
+  vcluster stop_subcluster --subcluster sc1 --clear-depot \
+    --config /opt/vertica/config/vertica_cluster.yaml --password "PASSWORD"
+
   # Forcibly stop a subcluster with config file
   vcluster stop_subcluster --subcluster sc1 --force \
     --config /opt/vertica/config/vertica_cluster.yaml --password "PASSWORD"
@@ -102,6 +106,12 @@ func (c *CmdStopSubcluster) setLocalFlags(cmd *cobra.Command) {
 		false,
 		"Force the subcluster to shut down immediately even if users are connected.",
 	)
+	cmd.Flags().BoolVar(
+		&c.stopSCOptions.ClearDepot,
+		"clear-depot",
+		false,
+		"Clear the depot of the subcluster when it stops. The depot is kept by default.",
+	)
 	cmd.MarkFlagsMutuallyExclusive("drain-seconds", "force")
 }
 
@@ -143,6 +153,9 @@ func (c *CmdStopSubcluster) Run(vcc vclusterops.ClusterCommands) error {
 	options := c.stopSCOptions
 
 	err := vcc.VStopSubcluster(options)
+	for _, outcome := range options.NodeOutcomes {
+		vcc.LogInfo("node outcome", "node", outcome.Name, "address", outcome.Address, "outcome", outcome.Outcome)
+	}
 	if err != nil {
 		vcc.LogError(err, "failed to stop the subcluster", "Subcluster", options.SCName)
 		return err
@@ -0,0 +1,126 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdSupportBundle
+ *
+ * A subcommand packaging the diagnostics of vcluster
+ * itself, rather than of the database, into a bundle.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdSupportBundle struct {
+	bundleOptions vclusterops.VSupportBundleOptions
+	CmdBase
+}
+
+func makeCmdSupportBundle() *cobra.Command {
+	newCmd := &CmdSupportBundle{}
+	newCmd.bundleOptions = vclusterops.VSupportBundleFactory()
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		supportBundleSubCmd,
+		"Packages the diagnostics of vcluster into a support bundle.",
+		`Packages the diagnostics of vcluster itself into a small support bundle:
+its log and rotated logs, the configuration file with its secrets redacted,
+the logs of the most recent commands, and a summary of its version and
+environment.
+
+Unlike scrutinize, this command does not connect to the database. Use it to
+troubleshoot issues of vcluster rather than of the database.
+
+By default, the bundle is stored in ./vcluster-support.timestamp.tar.gz.
+
+Examples:
+  # Create a support bundle with the default log and config file
+  vcluster support_bundle
+
+  # Create a support bundle including the logs of the last 5 commands
+  vcluster support_bundle --config /opt/vertica/config/vertica_cluster.yaml \
+    --command-log-dir /opt/vertica/log/commands --max-command-logs 5 \
+    --output /tmp/vcluster-support.tar.gz
+`,
+		[]string{configFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdSupportBundle) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.bundleOptions.OutputPath,
+		"output",
+		"",
+		"Path of the support bundle (default: ./vcluster-support.timestamp.tar.gz)",
+	)
+	cmd.Flags().StringVar(
+		&c.bundleOptions.CommandLogDir,
+		"command-log-dir",
+		"",
+		"Directory of the logs of each command to include in the bundle",
+	)
+	cmd.Flags().IntVar(
+		&c.bundleOptions.MaxCommandLogs,
+		"max-command-logs",
+		vclusterops.DefaultSupportBundleCommandLogs,
+		"The number of the most recent command logs to include in the bundle",
+	)
+}
+
+func (c *CmdSupportBundle) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogArgParse(&c.argv)
+
+	if c.bundleOptions.OutputPath == "" {
+		c.bundleOptions.OutputPath = fmt.Sprintf("vcluster-support.%s.tar.gz", time.Now().Format("20060102150405"))
+	}
+	return nil
+}
+
+func (c *CmdSupportBundle) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.bundleOptions
+	options.LogPath = dbOptions.LogPath
+	options.ConfigPath = dbOptions.ConfigPath
+	options.Version = CLIVersion
+
+	manifest, err := vcc.VCreateSupportBundle(&options)
+	if err != nil {
+		vcc.LogError(err, "failed to create the support bundle")
+		return err
+	}
+	vcc.DisplayInfo("Successfully created the support bundle %s with %d files", options.OutputPath, len(manifest.Files))
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance
+func (c *CmdSupportBundle) SetDatabaseOptions(_ *vclusterops.DatabaseOptions) {
+}
@@ -145,6 +145,13 @@ func TestNMACertLookupFromK8sSecret(t *testing.T) {
 	assert.Error(t, err)
 	assert.False(t, ok)
 
+	// The secret must have a valid Kubernetes name
+	os.Setenv(secretNameEnvVar, "nma_certs")
+	c = &CmdScrutinize{}
+	ok, err = c.nmaCertLookupFromSecretStore(vlog.Printer{})
+	assert.ErrorContains(t, err, `secret name "nma_certs" is invalid`)
+	assert.False(t, ok)
+
 	// If the nma env vars aren't set, then we go onto the next retrieval method
 	os.Clearenv()
 	os.Setenv("KUBERNETES_PORT", randomBytes)
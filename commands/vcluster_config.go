@@ -65,6 +65,9 @@ type NodeConfig struct {
 	DataPath    string `yaml:"dataPath" mapstructure:"dataPath"`
 	DepotPath   string `yaml:"depotPath" mapstructure:"depotPath"`
 	Sandbox     string `yaml:"sandbox" mapstructure:"sandbox"` // Name of the sandbox the node belongs to
+	// Ports of the node when they are not the default ones, e.g., behind NAT
+	NMAPort   int `yaml:"nmaPort,omitempty" mapstructure:"nmaPort"`
+	HTTPSPort int `yaml:"httpsPort,omitempty" mapstructure:"httpsPort"`
 }
 
 // MakeDatabaseConfig() can create an instance of DatabaseConfig
@@ -176,6 +179,7 @@ func loadConfigToViper() error {
 	if !viper.IsSet(hostsKey) {
 		viper.Set(hostsKey, dbConfig.getHosts())
 	}
+	dbOptions.HostPorts = dbConfig.getHostPorts()
 	catalogPrefix, dataPrefix, depotPrefix := dbConfig.getPathPrefixes()
 	if !viper.IsSet(catalogPathKey) {
 		viper.Set(catalogPathKey, catalogPrefix)
@@ -238,6 +242,10 @@ func readVDBToDBConfig(vdb *vclusterops.VCoordinationDatabase) (DatabaseConfig,
 		}
 
 		nodeConfig := BuildNodeConfig(vnode, vdb)
+		// keep the port overrides that were read from the config file
+		ports := dbOptions.HostPorts[host]
+		nodeConfig.NMAPort = ports.NMAPort
+		nodeConfig.HTTPSPort = ports.HTTPSPort
 		dbConfig.Nodes = append(dbConfig.Nodes, &nodeConfig)
 	}
 
@@ -386,6 +394,19 @@ func (c *DatabaseConfig) getHosts() []string {
 	return hostList
 }
 
+// getHostPorts returns the port overrides of all nodes in database
+func (c *DatabaseConfig) getHostPorts() map[string]vclusterops.HostPorts {
+	hostPorts := make(map[string]vclusterops.HostPorts)
+
+	for _, vnode := range c.Nodes {
+		if vnode.NMAPort != 0 || vnode.HTTPSPort != 0 {
+			hostPorts[vnode.Address] = vclusterops.HostPorts{NMAPort: vnode.NMAPort, HTTPSPort: vnode.HTTPSPort}
+		}
+	}
+
+	return hostPorts
+}
+
 // getPathPrefix returns catalog, data, and depot prefixes
 func (c *DatabaseConfig) getPathPrefixes() (catalogPrefix string,
 	dataPrefix string, depotPrefix string) {
@@ -14,6 +14,7 @@ type DatabaseConnection struct {
 	TargetHosts        []string `yaml:"targetHosts" mapstructure:"targetHosts"`
 	TargetDBName       string   `yaml:"targetDBName" mapstructure:"targetDBName"`
 	TargetDBUser       string   `yaml:"targetDBUser" mapstructure:"targetDBUser"`
+	TargetSOCKS5Proxy  string   `yaml:"targetSOCKS5Proxy,omitempty" mapstructure:"targetSOCKS5Proxy"`
 }
 
 func MakeTargetDatabaseConn() DatabaseConnection {
@@ -56,6 +57,7 @@ func readTargetDBToDBConn(cnn *vclusterops.VReplicationDatabaseOptions) Database
 	targetDBconn.TargetHosts = cnn.TargetDB.Hosts
 	targetDBconn.TargetPasswordFile = *cnn.TargetDB.Password
 	targetDBconn.TargetDBUser = cnn.TargetDB.UserName
+	targetDBconn.TargetSOCKS5Proxy = cnn.TargetDB.SOCKS5Proxy
 	return targetDBconn
 }
 
@@ -0,0 +1,191 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package server
+
+import (
+	"github.com/vertica/vcluster/vclusterops"
+)
+
+// The requests hold the options that a remote caller may set. The other
+// options of the commands, e.g., the paths of the files that the server reads
+// or writes, keep their defaults.
+
+// databaseRequest is the database and the credentials of a request
+type databaseRequest struct {
+	DBName                  string
+	RawHosts                []string
+	IPv6                    bool
+	IsEon                   bool
+	CommunalStorageLocation string
+	UserName                string
+	Password                *string
+	RequestID               string
+	ReadOnly                bool
+}
+
+func (r *databaseRequest) apply(options *vclusterops.DatabaseOptions) {
+	options.DBName = r.DBName
+	options.RawHosts = r.RawHosts
+	options.IPv6 = r.IPv6
+	options.IsEon = r.IsEon
+	options.CommunalStorageLocation = r.CommunalStorageLocation
+	options.UserName = r.UserName
+	options.Password = r.Password
+	options.RequestID = r.RequestID
+	options.ReadOnly = r.ReadOnly
+}
+
+type healthRequest struct {
+	databaseRequest
+	GetVersion bool
+}
+
+func (r *healthRequest) toOptions() vclusterops.VFetchNodeStateOptions {
+	options := vclusterops.VFetchNodeStateOptionsFactory()
+	r.apply(&options.DatabaseOptions)
+	options.GetVersion = r.GetVersion
+	return options
+}
+
+type startDBRequest struct {
+	databaseRequest
+	StatePollingTimeout   int
+	TrimHostList          bool
+	Sandbox               string
+	MainCluster           bool
+	HostsInSandbox        bool
+	FirstStartAfterRevive bool
+}
+
+func (r *startDBRequest) toOptions() vclusterops.VStartDatabaseOptions {
+	options := vclusterops.VStartDatabaseOptionsFactory()
+	r.apply(&options.DatabaseOptions)
+	options.StatePollingTimeout = r.StatePollingTimeout
+	options.TrimHostList = r.TrimHostList
+	options.Sandbox = r.Sandbox
+	options.MainCluster = r.MainCluster
+	options.HostsInSandbox = r.HostsInSandbox
+	options.FirstStartAfterRevive = r.FirstStartAfterRevive
+	return options
+}
+
+type stopDBRequest struct {
+	databaseRequest
+	DrainSeconds  *int
+	SandboxName   string
+	MainCluster   bool
+	CheckUserConn bool
+	ForceKill     bool
+}
+
+func (r *stopDBRequest) toOptions() vclusterops.VStopDatabaseOptions {
+	options := vclusterops.VStopDatabaseOptionsFactory()
+	r.apply(&options.DatabaseOptions)
+	options.DrainSeconds = r.DrainSeconds
+	options.SandboxName = r.SandboxName
+	options.MainCluster = r.MainCluster
+	options.CheckUserConn = r.CheckUserConn
+	options.ForceKill = r.ForceKill
+	return options
+}
+
+type replicateRequest struct {
+	databaseRequest
+	TargetDB               databaseRequest
+	SourceTLSConfig        string
+	TargetTLSConfig        string
+	SandboxName            string
+	Async                  bool
+	TargetRole             string
+	TargetResourcePool     string
+	TargetTrustAuth        bool
+	LockWaitTimeout        int
+	SkipLockCheck          bool
+	SourceSnapshot         string
+	ProjectionMode         string
+	DryRun                 bool
+	AllowNoMatchingObjects bool
+	TableOrSchemaName      string
+	IncludePatterns        []string
+	ExcludePatterns        []string
+	TargetNamespace        string
+	ObjectRenames          map[string]string
+	Epoch                  *int64
+	AtTime                 string
+}
+
+func (r *replicateRequest) toOptions() vclusterops.VReplicationDatabaseOptions {
+	options := vclusterops.VReplicationDatabaseFactory()
+	r.apply(&options.DatabaseOptions)
+	r.TargetDB.apply(&options.TargetDB)
+	options.SourceTLSConfig = r.SourceTLSConfig
+	options.TargetTLSConfig = r.TargetTLSConfig
+	options.SandboxName = r.SandboxName
+	options.Async = r.Async
+	options.TargetRole = r.TargetRole
+	options.TargetResourcePool = r.TargetResourcePool
+	options.TargetTrustAuth = r.TargetTrustAuth
+	options.LockWaitTimeout = r.LockWaitTimeout
+	options.SkipLockCheck = r.SkipLockCheck
+	options.SourceSnapshot = r.SourceSnapshot
+	options.ProjectionMode = r.ProjectionMode
+	options.DryRun = r.DryRun
+	options.AllowNoMatchingObjects = r.AllowNoMatchingObjects
+	options.TableOrSchemaName = r.TableOrSchemaName
+	options.IncludePatterns = r.IncludePatterns
+	options.ExcludePatterns = r.ExcludePatterns
+	options.TargetNamespace = r.TargetNamespace
+	options.ObjectRenames = r.ObjectRenames
+	options.Epoch = r.Epoch
+	options.AtTime = r.AtTime
+	return options
+}
+
+type scrutinizeRequest struct {
+	databaseRequest
+	ExcludeContainers           bool
+	ExcludeActiveQueries        bool
+	IncludeRos                  bool
+	IncludeExternalTableDetails bool
+	IncludeUDXDetails           bool
+	SkipCollectLibs             bool
+	IncludeContainerDiagnostics bool
+	LogAgeOldestTime            string
+	LogAgeNewestTime            string
+	LogAgeHours                 int
+	Anonymize                   bool
+	AnonymizeTerms              []string
+	Sandboxes                   []string
+}
+
+func (r *scrutinizeRequest) toOptions() vclusterops.VScrutinizeOptions {
+	options := vclusterops.VScrutinizeOptionsFactory()
+	r.apply(&options.DatabaseOptions)
+	options.ExcludeContainers = r.ExcludeContainers
+	options.ExcludeActiveQueries = r.ExcludeActiveQueries
+	options.IncludeRos = r.IncludeRos
+	options.IncludeExternalTableDetails = r.IncludeExternalTableDetails
+	options.IncludeUDXDetails = r.IncludeUDXDetails
+	options.SkipCollectLibs = r.SkipCollectLibs
+	options.IncludeContainerDiagnostics = r.IncludeContainerDiagnostics
+	options.LogAgeOldestTime = r.LogAgeOldestTime
+	options.LogAgeNewestTime = r.LogAgeNewestTime
+	options.LogAgeHours = r.LogAgeHours
+	options.Anonymize = r.Anonymize
+	options.AnonymizeTerms = r.AnonymizeTerms
+	options.Sandboxes = r.Sandboxes
+	return options
+}
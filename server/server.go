@@ -0,0 +1,147 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package server exposes the main vclusterops commands over a REST API, so
+// that vcluster can run as a daemon and be driven by tools that are not
+// written in Go.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vertica/vcluster/vclusterops"
+)
+
+// the largest request body that is read, the options of a command are small
+const maxRequestBodyBytes = 1 << 20
+
+// Authenticator checks that a request may run a command. It returns an error
+// to reject the request with 401 Unauthorized.
+type Authenticator func(r *http.Request, command string) error
+
+// Server is an http.Handler that runs a command for each request. The body of
+// a request is the JSON form of the options of the command that a remote
+// caller may set, and the response is the JSON form of its result.
+//
+//	POST /v1/health      healthRequest      -> []NodeInfo
+//	POST /v1/start-db    startDBRequest     -> VCoordinationDatabase
+//	POST /v1/stop-db     stopDBRequest      -> nil
+//	POST /v1/replicate   replicateRequest   -> transaction ID
+//	POST /v1/scrutinize  scrutinizeRequest  -> nil
+type Server struct {
+	commands     vclusterops.ClusterCommands
+	authenticate Authenticator
+	mux          *http.ServeMux
+}
+
+// Response is the body of a response
+type Response struct {
+	Result    any    `json:"result,omitempty"`
+	Error     string `json:"error,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// MakeServer returns a server that runs the commands after authenticate
+// accepts the request. The server does not run without an authenticator.
+func MakeServer(commands vclusterops.ClusterCommands, authenticate Authenticator) (*Server, error) {
+	if authenticate == nil {
+		return nil, errors.New("an authenticator is required to serve the commands")
+	}
+	s := &Server{commands: commands, authenticate: authenticate, mux: http.NewServeMux()}
+
+	s.mux.Handle("/v1/health", makeHandler(s, "health",
+		func(request *healthRequest) (any, error) {
+			options := request.toOptions()
+			return s.commands.VFetchNodeState(&options)
+		}))
+	s.mux.Handle("/v1/start-db", makeHandler(s, "start_db",
+		func(request *startDBRequest) (any, error) {
+			options := request.toOptions()
+			return s.commands.VStartDatabase(&options)
+		}))
+	s.mux.Handle("/v1/stop-db", makeHandler(s, "stop_db",
+		func(request *stopDBRequest) (any, error) {
+			options := request.toOptions()
+			return nil, s.commands.VStopDatabase(&options)
+		}))
+	s.mux.Handle("/v1/replicate", makeHandler(s, "replicate",
+		func(request *replicateRequest) (any, error) {
+			options := request.toOptions()
+			return s.commands.VReplicateDatabase(&options)
+		}))
+	s.mux.Handle("/v1/scrutinize", makeHandler(s, "scrutinize",
+		func(request *scrutinizeRequest) (any, error) {
+			options := request.toOptions()
+			return nil, s.commands.VScrutinize(&options)
+		}))
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// makeHandler returns the handler of a command. The request only takes the
+// options that a remote caller may set, any other field is rejected.
+func makeHandler[R any](s *Server, command string, run func(request *R) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeResponse(w, http.StatusMethodNotAllowed, &Response{Error: fmt.Sprintf("method %s is not allowed", r.Method)})
+			return
+		}
+		if err := s.authenticate(r, command); err != nil {
+			writeResponse(w, http.StatusUnauthorized, &Response{Error: err.Error()})
+			return
+		}
+
+		var request R
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{Error: fmt.Sprintf("invalid options of %s: %s", command, err)})
+			return
+		}
+
+		result, err := run(&request)
+		if err != nil {
+			requestID, _ := vclusterops.GetRequestID(err)
+			writeResponse(w, getErrorStatus(err), &Response{Error: err.Error(), RequestID: requestID})
+			return
+		}
+		writeResponse(w, http.StatusOK, &Response{Result: result})
+	})
+}
+
+// getErrorStatus returns the status of a failed command
+func getErrorStatus(err error) int {
+	readOnlyErr := &vclusterops.ReadOnlyModeError{}
+	maintenanceErr := &vclusterops.OutsideMaintenanceWindowError{}
+	divergedErr := &vclusterops.PlanDivergedError{}
+	if errors.As(err, &readOnlyErr) || errors.As(err, &maintenanceErr) || errors.As(err, &divergedErr) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func writeResponse(w http.ResponseWriter, status int, response *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// the status is already sent, an encoding failure can only be seen by the client
+	_ = json.NewEncoder(w).Encode(response)
+}
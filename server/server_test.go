@@ -0,0 +1,116 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops"
+)
+
+// fakeCommands implements the commands that the tests call, the others panic
+type fakeCommands struct {
+	vclusterops.ClusterCommands
+	stopOptions *vclusterops.VStopDatabaseOptions
+	stopErr     error
+}
+
+func (f *fakeCommands) VStopDatabase(options *vclusterops.VStopDatabaseOptions) error {
+	f.stopOptions = options
+	return f.stopErr
+}
+
+func (f *fakeCommands) VFetchNodeState(_ *vclusterops.VFetchNodeStateOptions) ([]vclusterops.NodeInfo, error) {
+	return []vclusterops.NodeInfo{{Address: "192.168.1.101", State: "UP"}}, nil
+}
+
+func post(s *Server, path, body string) (*httptest.ResponseRecorder, Response) {
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	var response Response
+	_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+	return recorder, response
+}
+
+func TestServer(t *testing.T) {
+	commands := &fakeCommands{}
+	s, err := MakeServer(commands, func(_ *http.Request, _ string) error { return nil })
+	assert.NoError(t, err)
+
+	// the options are decoded on top of the defaults of the command
+	recorder, _ := post(s, "/v1/stop-db", `{"DBName": "test_db", "RawHosts": ["192.168.1.101"]}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "test_db", commands.stopOptions.DBName)
+
+	recorder, response := post(s, "/v1/health", `{"DBName": "test_db"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []any{map[string]any{"address": "192.168.1.101", "name": "", "state": "UP", "catalog_path": "",
+		"subcluster": "", "sandbox": "", "is_primary": false, "version": ""}}, response.Result)
+
+	// the errors of a command are returned with its request ID
+	commands.stopErr = &vclusterops.RequestIDError{RequestID: "vc-1234", Err: errors.New("nodes are down")}
+	recorder, response = post(s, "/v1/stop-db", `{"DBName": "test_db"}`)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "vc-1234", response.RequestID)
+	commands.stopErr = &vclusterops.ReadOnlyModeError{Plan: []vclusterops.PlannedOp{{Name: "stop-op"}}}
+	recorder, _ = post(s, "/v1/stop-db", `{"DBName": "test_db"}`)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	// negative: unknown options, or a method other than POST
+	recorder, response = post(s, "/v1/stop-db", `{"Database": "test_db"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Contains(t, response.Error, "invalid options of stop_db")
+	// negative: the options that a remote caller may not set, e.g., server-side paths
+	recorder, _ = post(s, "/v1/stop-db", `{"DBName": "test_db", "LogPath": "/etc/passwd"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder, _ = post(s, "/v1/scrutinize", `{"DBName": "test_db", "TarballName": "../../etc/cron.d/x"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/health", http.NoBody))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestServerAuthentication(t *testing.T) {
+	// negative: the commands are not served without authentication
+	_, err := MakeServer(&fakeCommands{}, nil)
+	assert.ErrorContains(t, err, "an authenticator is required")
+
+	commands := &fakeCommands{}
+	s, err := MakeServer(commands, func(r *http.Request, command string) error {
+		if r.Header.Get("Authorization") != "Bearer token" || command == "scrutinize" {
+			return errors.New("not allowed")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	recorder, response := post(s, "/v1/stop-db", `{"DBName": "test_db"}`)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Equal(t, "not allowed", response.Error)
+	assert.Nil(t, commands.stopOptions)
+
+	recorder = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/v1/stop-db", strings.NewReader(`{"DBName": "test_db"}`))
+	request.Header.Set("Authorization", "Bearer token")
+	s.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,131 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type ActivityStatus = vtypes.ActivityStatus
+
+const (
+	ActivityPhasePrepare  = "prepare"
+	ActivityPhaseExecute  = "execute"
+	ActivityPhaseFinalize = "finalize"
+)
+
+// CommandActivity tracks what a command is running: its current op, the
+// hosts with a request in flight, and for how long. It is safe to read with
+// Status from another goroutine while the command runs. The methods that
+// update it do nothing on a nil CommandActivity, so that the op engine calls
+// them whether or not the activity is tracked.
+type CommandActivity struct {
+	mu            sync.Mutex
+	status        ActivityStatus
+	hostsInFlight map[string]int
+}
+
+// Status returns what the command is running now
+func (a *CommandActivity) Status() ActivityStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	status := a.status
+	status.HostsInFlight = make([]string, 0, len(a.hostsInFlight))
+	for host := range a.hostsInFlight {
+		status.HostsInFlight = append(status.HostsInFlight, host)
+	}
+	slices.Sort(status.HostsInFlight)
+	if status.Running && !status.OpStartedAt.IsZero() {
+		status.OpElapsed = time.Since(status.OpStartedAt)
+	}
+	return status
+}
+
+func (a *CommandActivity) beginEngine(command string, opCount int) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status = ActivityStatus{Command: command, Running: true, OpCount: opCount, StartedAt: time.Now()}
+	a.hostsInFlight = make(map[string]int)
+}
+
+func (a *CommandActivity) endEngine() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.status.OpStartedAt.IsZero() {
+		a.status.OpElapsed = time.Since(a.status.OpStartedAt)
+	}
+	a.status.Running = false
+	a.hostsInFlight = nil
+}
+
+func (a *CommandActivity) beginOp(index int, name, description string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status.Op = name
+	a.status.OpDescription = description
+	a.status.OpIndex = index
+	a.status.Phase = ""
+	a.status.OpStartedAt = time.Now()
+}
+
+func (a *CommandActivity) setPhase(phase string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.status.Phase = phase
+}
+
+// requestStarted and requestDone count the requests in flight to a host, an
+// op can send more than one request to a host at a time
+func (a *CommandActivity) requestStarted(host string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.hostsInFlight == nil {
+		a.hostsInFlight = make(map[string]int)
+	}
+	a.hostsInFlight[host]++
+}
+
+func (a *CommandActivity) requestDone(host string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.hostsInFlight[host] <= 1 {
+		delete(a.hostsInFlight, host)
+		return
+	}
+	a.hostsInFlight[host]--
+}
@@ -0,0 +1,82 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// activityOp is a mockOp that records the activity of the command while it
+// executes
+type activityOp struct {
+	mockOp
+	activity *CommandActivity
+	status   ActivityStatus
+}
+
+func (m *activityOp) execute(execContext *opEngineExecContext) error {
+	m.activity.requestStarted("host1")
+	m.status = m.activity.Status()
+	m.activity.requestDone("host1")
+	return m.mockOp.execute(execContext)
+}
+
+func TestCommandActivity(t *testing.T) {
+	activity := &CommandActivity{}
+	firstOp := makeMockOp(false)
+	secondOp := activityOp{mockOp: makeMockOp(false), activity: activity}
+	secondOp.name = "second-op"
+	secondOp.description = "Second op"
+	options := &DatabaseOptions{commandName: "start_db", Activity: activity}
+
+	opEngn := makeClusterOpEngine([]clusterOp{&firstOp, &secondOp}, options)
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+
+	// the status while the second op executes
+	status := secondOp.status
+	assert.True(t, status.Running)
+	assert.Equal(t, "start_db", status.Command)
+	assert.Equal(t, "second-op", status.Op)
+	assert.Equal(t, "Second op", status.OpDescription)
+	assert.Equal(t, 1, status.OpIndex)
+	assert.Equal(t, 2, status.OpCount)
+	assert.Equal(t, ActivityPhaseExecute, status.Phase)
+	assert.Equal(t, []string{"host1"}, status.HostsInFlight)
+	assert.False(t, status.OpStartedAt.Before(status.StartedAt))
+
+	// the status once the engine is done
+	status = activity.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, "second-op", status.Op)
+	assert.Equal(t, ActivityPhaseFinalize, status.Phase)
+	assert.Empty(t, status.HostsInFlight)
+
+	// the requests in flight to a host are counted
+	activity.requestStarted("host1")
+	activity.requestStarted("host1")
+	activity.requestDone("host1")
+	assert.Equal(t, []string{"host1"}, activity.Status().HostsInFlight)
+	activity.requestDone("host1")
+	assert.Empty(t, activity.Status().HostsInFlight)
+
+	// an untracked activity is ignored
+	var untracked *CommandActivity
+	untracked.beginOp(0, "op", "")
+	untracked.requestStarted("host1")
+}
@@ -18,7 +18,6 @@ package vclusterops
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/theckman/yacspin"
@@ -31,28 +30,9 @@ type adapterPool struct {
 	connections map[string]adapter
 }
 
-var (
-	poolInstance adapterPool
-	once         sync.Once
-)
-
-// return a new instance of an adapterPool. The adapterPool cannot be shared
-// between Go routines. Otherwise, they will clobber each other state causing
-// HTTP request errors. It is the callers responsibility to ensure it doesn't
-// get shared.
-func getPoolInstance(logger vlog.Printer) adapterPool {
-	/* if once.Do(f) is called multiple times,
-	 * only the first call will invoke f,
-	 * even if f has a different value in each invocation.
-	 * Reference: https://pkg.go.dev/sync#Once
-	 */
-	once.Do(func() {
-		poolInstance = makeAdapterPool(logger)
-	})
-
-	return poolInstance
-}
-
+// makeAdapterPool returns a new adapterPool. An adapterPool cannot be shared
+// between Go routines, otherwise they will clobber each other's state causing
+// HTTP request errors, so every request dispatcher makes its own pool.
 func makeAdapterPool(logger vlog.Printer) adapterPool {
 	newAdapterPool := adapterPool{}
 	newAdapterPool.connections = make(map[string]adapter)
@@ -94,6 +74,9 @@ func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *y
 		defer cancelCtx()
 	}
 
+	// the requests are sent at the same time, so the time until the result of
+	// a host is received is the duration of the request to that host
+	start := time.Now()
 	for i := 0; i < len(adapterToRequestCollection); i++ {
 		ar := adapterToRequestCollection[i]
 		// send request to the hosts
@@ -109,6 +92,10 @@ func (pool *adapterPool) sendRequest(httpRequest *clusterHTTPRequest, spinner *y
 	for i := 0; i < hostCount; i++ {
 		result, ok := <-resultChannel
 		if ok {
+			result.duration = time.Since(start)
+			if httpRequest.resultHandler != nil {
+				result = httpRequest.resultHandler(result)
+			}
 			httpRequest.ResultCollection[result.host] = result
 		}
 	}
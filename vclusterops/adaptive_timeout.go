@@ -0,0 +1,78 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"time"
+)
+
+const (
+	// the default timeouts are tuned for clusters of up to this many nodes
+	adaptiveTimeoutBaseNodeCount = 3
+	// each node beyond the base node count adds this percent to a default timeout
+	adaptiveTimeoutPercentPerNode = 5
+	// a default timeout is scaled to at most this many times its value
+	maxAdaptiveTimeoutFactor = 5
+	// a request of a polling op may take this many times the slowest request
+	// observed earlier in the command
+	adaptiveLatencyFactor = 10
+	// the request timeout of a polling op is never raised above this many seconds
+	maxAdaptiveRequestTimeoutSeconds = 300
+)
+
+// scaleTimeout scales a default timeout, in seconds, with the number of nodes
+// the command works on
+func scaleTimeout(timeout, nodeCount int) int {
+	extraNodes := nodeCount - adaptiveTimeoutBaseNodeCount
+	if timeout <= 0 || extraNodes <= 0 {
+		return timeout
+	}
+	scaled := timeout + timeout*extraNodes*adaptiveTimeoutPercentPerNode/100
+	return min(scaled, timeout*maxAdaptiveTimeoutFactor)
+}
+
+// adaptTimeout returns the timeout to use for a command with nodeCount nodes.
+// A timeout that differs from its default value was set by the caller, so it
+// is used as is.
+func (opt *DatabaseOptions) adaptTimeout(timeout, defaultTimeout, nodeCount int) int {
+	if opt.DisableAdaptiveTimeouts || timeout != defaultTimeout {
+		return timeout
+	}
+	return scaleTimeout(timeout, nodeCount)
+}
+
+func (opt *DatabaseOptions) useAdaptiveTimeouts() bool {
+	return !opt.DisableAdaptiveTimeouts
+}
+
+// observeLatency keeps the slowest request latency seen so far by the command
+func (execContext *opEngineExecContext) observeLatency(results map[string]hostHTTPResult) {
+	for _, result := range results {
+		if !result.isTimeout() {
+			execContext.maxRequestLatency = max(execContext.maxRequestLatency, result.duration)
+		}
+	}
+}
+
+// adaptRequestTimeout raises the request timeout, in seconds, of a polling op
+// on a cluster whose earlier requests were slow
+func (execContext *opEngineExecContext) adaptRequestTimeout(timeout int) int {
+	if !execContext.adaptiveTimeouts {
+		return timeout
+	}
+	latencyTimeout := int((adaptiveLatencyFactor * execContext.maxRequestLatency).Round(time.Second) / time.Second)
+	return max(timeout, min(latencyTimeout, maxAdaptiveRequestTimeoutSeconds))
+}
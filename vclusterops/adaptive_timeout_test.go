@@ -0,0 +1,73 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestScaleTimeout(t *testing.T) {
+	// the default timeouts are kept for small clusters
+	assert.Equal(t, 300, scaleTimeout(300, 1))
+	assert.Equal(t, 300, scaleTimeout(300, 3))
+	// and grow with each extra node, up to a limit
+	assert.Equal(t, 330, scaleTimeout(300, 5))
+	assert.Equal(t, 1200+1200*57*5/100, scaleTimeout(1200, 60))
+	assert.Equal(t, 1500, scaleTimeout(300, 200))
+	// no timeout stays the same
+	assert.Equal(t, 0, scaleTimeout(0, 60))
+}
+
+func TestAdaptTimeout(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	assert.Equal(t, scaleTimeout(util.DefaultStatePollingTimeout, 60),
+		options.adaptTimeout(util.DefaultStatePollingTimeout, util.DefaultStatePollingTimeout, 60))
+
+	// a timeout set by the caller is used as is
+	assert.Equal(t, 600, options.adaptTimeout(600, util.DefaultStatePollingTimeout, 60))
+
+	// the default timeouts can be kept as is
+	options.DisableAdaptiveTimeouts = true
+	assert.Equal(t, util.DefaultStatePollingTimeout,
+		options.adaptTimeout(util.DefaultStatePollingTimeout, util.DefaultStatePollingTimeout, 60))
+}
+
+func TestAdaptRequestTimeout(t *testing.T) {
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	execContext.observeLatency(map[string]hostHTTPResult{
+		"host1": {status: SUCCESS, duration: 4 * time.Second},
+		"host2": {status: SUCCESS, duration: time.Second},
+		// a request that timed out says nothing about the latency
+		"host3": {status: EXCEPTION, err: &net.DNSError{IsTimeout: true}, duration: time.Minute},
+	})
+	assert.Equal(t, 4*time.Second, execContext.maxRequestLatency)
+
+	// the request timeout is only raised with adaptive timeouts
+	assert.Equal(t, defaultHTTPSRequestTimeoutSeconds, execContext.adaptRequestTimeout(defaultHTTPSRequestTimeoutSeconds))
+	execContext.adaptiveTimeouts = true
+	assert.Equal(t, 40, execContext.adaptRequestTimeout(defaultHTTPSRequestTimeoutSeconds))
+	// and never lowered, or raised beyond a limit
+	execContext.maxRequestLatency = time.Second
+	assert.Equal(t, defaultHTTPSRequestTimeoutSeconds, execContext.adaptRequestTimeout(defaultHTTPSRequestTimeoutSeconds))
+	execContext.maxRequestLatency = time.Hour
+	assert.Equal(t, maxAdaptiveRequestTimeoutSeconds, execContext.adaptRequestTimeout(defaultHTTPSRequestTimeoutSeconds))
+}
@@ -50,6 +50,10 @@ type VAddNodeOptions struct {
 	// Name of the compute group for the new node(s). If provided, this indicates the new nodes
 	// will be compute nodes.
 	ComputeGroup string
+	// Skip checking that the license limits allow the new nodes if true
+	SkipLicenseCheck bool
+	// options of the check that warns about new nodes with unlike hardware
+	HardwareCheck HardwareCheckOptions
 
 	// timeout for polling nodes in seconds when we add Nodes
 	TimeOut int
@@ -67,6 +71,7 @@ func (options *VAddNodeOptions) setDefaultValues() {
 	options.DatabaseOptions.setDefaultValues()
 
 	options.SkipRebalanceShards = new(bool)
+	options.HardwareCheck.setDefaultValues()
 
 	// try to retrieve the timeout from the environment variable
 	// otherwise, set the default value (300 seconds) to the timeout
@@ -75,7 +80,7 @@ func (options *VAddNodeOptions) setDefaultValues() {
 
 func (options *VAddNodeOptions) validateEonOptions() error {
 	if options.DepotPrefix != "" {
-		return util.ValidateRequiredAbsPath(options.DepotPrefix, "depot path")
+		return util.ValidateRemotePath(options.DepotPrefix, "depot path", options.AllowedPathRoots)
 	}
 	return nil
 }
@@ -91,7 +96,7 @@ func (options *VAddNodeOptions) validateRequiredOptions(logger vlog.Printer) err
 func (options *VAddNodeOptions) validateExtraOptions() error {
 	// data prefix
 	if options.DataPrefix != "" {
-		return util.ValidateRequiredAbsPath(options.DataPrefix, "data path")
+		return util.ValidateRemotePath(options.DataPrefix, "data path", options.AllowedPathRoots)
 	}
 
 	err := util.ValidateScName(options.SCName)
@@ -155,6 +160,12 @@ func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDat
 		return vdb, err
 	}
 
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, AddNodeCmd)
+	if err != nil {
+		return vdb, err
+	}
+	defer releaseLock()
+
 	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
 	if err != nil {
 		return vdb, err
@@ -194,7 +205,7 @@ func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDat
 		return vdb, err
 	}
 
-	err = vdb.addHosts(options.NewHosts, options.SCName, existingHostNodeMap)
+	err = vdb.addHosts(options.NewHosts, options.SCName, existingHostNodeMap, options.ControlAddresses)
 	if err != nil {
 		return vdb, err
 	}
@@ -208,6 +219,7 @@ func (vcc VClusterCommands) VAddNode(options *VAddNodeOptions) (VCoordinationDat
 	if runError := clusterOpEngine.run(vcc.Log); runError != nil {
 		return vdb, fmt.Errorf("fail to complete add node operation, %w", runError)
 	}
+	options.updateHostAliases(&vdb)
 	return vdb, nil
 }
 
@@ -360,6 +372,8 @@ func (vcc VClusterCommands) trimNodesInCatalog(vdb *VCoordinationDatabase,
 //   - If we have subcluster in the input, check if the subcluster exists. If not, we stop.
 //     If we do not have a subcluster in the input, fetch the current default subcluster name
 //   - Check NMA versions
+//   - Check that the license limits allow the new nodes, unless it is skipped
+//   - Warn about new nodes with hardware unlike the subcluster, unless it is skipped
 //   - Prepare directories
 //   - Get network profiles
 //   - Create the new node
@@ -397,15 +411,25 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 	nmaVerticaVersionOp := makeNMAVerticaVersionOpWithVDB(true /*hosts need to have the same Vertica version*/, vdb)
 	instructions = append(instructions, &nmaVerticaVersionOp)
 
+	preCheckOps, err := produceAddNodePreCheckOps(vdb, options)
+	if err != nil {
+		return instructions, err
+	}
+	instructions = append(instructions, preCheckOps...)
+
 	// this is a copy of the original HostNodeMap that only
 	// contains the hosts to add.
 	newHostNodeMap := vdb.copyHostNodeMap(options.NewHosts)
+	nmaCheckPathPermissionsOp, err := makeNMACheckPathPermissionsOp(newHostNodeMap)
+	if err != nil {
+		return instructions, err
+	}
 	nmaPrepareDirectoriesOp, err := makeNMAPrepareDirectoriesOp(newHostNodeMap,
 		options.ForceRemoval /*force cleanup*/, false /*for db revive*/)
 	if err != nil {
 		return instructions, err
 	}
-	nmaNetworkProfileOp := makeNMANetworkProfileOp(vdb.HostList)
+	nmaNetworkProfileOp := makeNMANetworkProfileOpWithControlAddresses(vdb.HostList, options.ControlAddresses)
 	httpsCreateNodeOp, err := makeHTTPSCreateNodeOp(newHosts, initiatorHost,
 		usePassword, username, password, vdb, options.SCName, options.ComputeGroup)
 	if err != nil {
@@ -420,6 +444,7 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 		return instructions, err
 	}
 	instructions = append(instructions,
+		&nmaCheckPathPermissionsOp,
 		&nmaPrepareDirectoriesOp,
 		&nmaNetworkProfileOp,
 		&httpsCreateNodeOp,
@@ -436,9 +461,10 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 
 	nmaStartNewNodesOp := makeNMAStartNodeOpWithVDB(newHosts, options.StartUpConf, vdb)
 	var pollNodeStateOp clusterOp
+	pollingTimeout := options.adaptTimeout(options.TimeOut, util.DefaultTimeoutSeconds, len(newHosts))
 	if options.ComputeGroup == "" {
 		// poll normally
-		httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOp(newHosts, usePassword, username, password, options.TimeOut)
+		httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOp(newHosts, usePassword, username, password, pollingTimeout)
 		if err != nil {
 			return instructions, err
 		}
@@ -447,7 +473,7 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 	} else {
 		// poll indirectly via nodes with catalog access
 		httpsPollComputeNodeStateOp, err := makeHTTPSPollComputeNodeStateOp(vdb.PrimaryUpNodes, newHosts, usePassword,
-			username, password, options.TimeOut)
+			username, password, pollingTimeout)
 		if err != nil {
 			return instructions, err
 		}
@@ -462,6 +488,30 @@ func (vcc VClusterCommands) produceAddNodeInstructions(vdb *VCoordinationDatabas
 		username, usePassword, initiatorHost, newHosts)
 }
 
+// produceAddNodePreCheckOps returns the ops that check the license limits and
+// the hardware of the new hosts, unless the checks are skipped
+func produceAddNodePreCheckOps(vdb *VCoordinationDatabase, options *VAddNodeOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+	if !options.SkipLicenseCheck {
+		licenseCheckOps, err := makeLicenseCheckOps([]string{options.Initiator}, options.NewHosts,
+			options.usePassword, options.UserName, options.Password)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, licenseCheckOps...)
+	}
+	if !options.HardwareCheck.SkipHardwareCheck {
+		referenceHosts := getHardwareReferenceHosts(vdb, options.SCName, options.NewHosts)
+		nmaCheckHardwareProfileOp, err := makeNMACheckHardwareProfileOp(referenceHosts, options.NewHosts,
+			&options.HardwareCheck)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaCheckHardwareProfileOp)
+	}
+	return instructions, nil
+}
+
 func (vcc VClusterCommands) prepareAdditionalEonInstructions(vdb *VCoordinationDatabase,
 	options *VAddNodeOptions,
 	instructions []clusterOp,
@@ -500,7 +550,7 @@ func (vcc VClusterCommands) prepareAdditionalEonInstructions(vdb *VCoordinationD
 
 // setInitiator sets the initiator as the first primary up node
 func (options *VAddNodeOptions) setInitiator(primaryUpNodes []string) error {
-	initiatorHost, err := getInitiatorHost(primaryUpNodes, []string{})
+	initiatorHost, err := getInitiatorHost(primaryUpNodes, []string{}, options.Initiators)
 	if err != nil {
 		return err
 	}
@@ -167,6 +167,12 @@ func (vcc VClusterCommands) VAddSubcluster(options *VAddSubclusterOptions) error
 		return err
 	}
 
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, AddSubclusterCmd)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
 	instructions, err := vcc.produceAddSubclusterInstructions(options)
 	if err != nil {
 		return fmt.Errorf("fail to produce instructions, %w", err)
@@ -0,0 +1,44 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+// catalogAddressResponse is a parsed response that holds node addresses from
+// the catalog. For hosts behind NAT, the catalog reports internal addresses,
+// which parseAndCheckResponse replaces with the external addresses of
+// DatabaseOptions.AddressMapping.
+type catalogAddressResponse interface {
+	mapAddresses(externalAddresses map[string]string)
+}
+
+// invertAddressMapping returns the external address of each internal address
+func invertAddressMapping(addressMapping map[string]string) map[string]string {
+	if len(addressMapping) == 0 {
+		return nil
+	}
+	externalAddresses := make(map[string]string, len(addressMapping))
+	for externalAddress, internalAddress := range addressMapping {
+		externalAddresses[internalAddress] = externalAddress
+	}
+	return externalAddresses
+}
+
+func (nodesStates *nodesStateInfo) mapAddresses(externalAddresses map[string]string) {
+	for _, node := range nodesStates.NodeList {
+		if externalAddress, ok := externalAddresses[node.Address]; ok {
+			node.Address = externalAddress
+		}
+	}
+}
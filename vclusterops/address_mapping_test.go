@@ -0,0 +1,55 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestDispatcherAddressMapping(t *testing.T) {
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher.setClientAddresses(nil)
+	dispatcher.addAddressMapping(map[string]string{"203.0.113.1": "10.0.0.101"})
+	dispatcher.setup([]string{"10.0.0.101", "203.0.113.2"})
+
+	// a host targeted by its internal address is reached through its external address
+	adapter, ok := dispatcher.pool.connections["10.0.0.101"].(*httpAdapter)
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.101", adapter.host)
+	assert.Equal(t, "203.0.113.1", adapter.address)
+
+	adapter, ok = dispatcher.pool.connections["203.0.113.2"].(*httpAdapter)
+	assert.True(t, ok)
+	assert.Equal(t, "", adapter.address)
+}
+
+func TestMapCatalogAddresses(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.AddressMapping = map[string]string{"203.0.113.1": "10.0.0.101"}
+	op := opBase{}
+	op.applyNetworkOptions(&opt)
+
+	nodesStates := nodesStateInfo{}
+	err := op.parseAndCheckResponse("203.0.113.1",
+		`{"node_list": [{"address": "10.0.0.101"}, {"address": "10.0.0.102"}]}`, &nodesStates)
+	assert.NoError(t, err)
+	assert.Equal(t, "203.0.113.1", nodesStates.NodeList[0].Address)
+	// addresses that are not mapped are kept
+	assert.Equal(t, "10.0.0.102", nodesStates.NodeList[1].Address)
+}
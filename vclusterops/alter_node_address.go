@@ -0,0 +1,249 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type VAlterNodeAddressOptions struct {
+	// basic db info, Hosts are the current addresses of all nodes
+	DatabaseOptions
+	// name of the node to change, it is looked up by NodeAddress if empty
+	NodeName string
+	// current address of the node to change, it must be the address of
+	// NodeName if both are set
+	NodeAddress string
+	// new address of the node
+	NewAddress string
+	// new control address of the node, NewAddress if empty
+	NewControlAddress string
+	// new control broadcast of the node, read from the network profile of
+	// NewAddress if empty
+	NewControlBroadcast string
+	// new export address of the node, the export address is not changed if empty
+	NewExportAddress string
+}
+
+func VAlterNodeAddressFactory() VAlterNodeAddressOptions {
+	options := VAlterNodeAddressOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VAlterNodeAddressOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(AlterNodeAddressCmd, logger)
+	if err != nil {
+		return err
+	}
+	err = options.validateCatalogPath()
+	if err != nil {
+		return err
+	}
+
+	if options.NodeName == "" && options.NodeAddress == "" {
+		return errors.New("must specify the name or the current address of the node")
+	}
+	if options.NewAddress == "" {
+		return errors.New("must specify the new address of the node")
+	}
+	if options.NewAddress == util.UnboundedIPv4 || options.NewAddress == util.UnboundedIPv6 {
+		return errors.New("the new address of the node should not be an unbound address")
+	}
+	for _, address := range []string{options.NodeAddress, options.NewAddress, options.NewControlAddress,
+		options.NewControlBroadcast, options.NewExportAddress} {
+		if address == "" {
+			continue
+		}
+		if err := util.AddressCheck(address, options.IPv6); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VAlterNodeAddressOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	if slices.Contains(options.Hosts, options.NewAddress) {
+		return fmt.Errorf("the new address %s is already used by a node of the database", options.NewAddress)
+	}
+	return nil
+}
+
+func (options *VAlterNodeAddressOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VAlterNodeAddress changes the addresses of one node of a stopped database.
+// Unlike VReIP, it only needs the new addresses of that node. After the
+// catalog is updated, the configuration files are synced to the other nodes,
+// and to the node at its new address.
+func (vcc VClusterCommands) VAlterNodeAddress(options *VAlterNodeAddressOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, AlterNodeAddressCmd)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
+	// update the addresses in the catalog
+	vdb := makeVCoordinationDatabase()
+	instructions, err := vcc.produceAlterNodeAddressInstructions(options, &vdb)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to alter node address: %w", runError)
+	}
+
+	// sync the configuration files, with the node at its new address
+	err = moveNodeToNewAddress(&vdb, options)
+	if err != nil {
+		return err
+	}
+	instructions, err = vcc.produceSyncConfigInstructions(&vdb)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine = makeClusterOpEngine(instructions, options)
+	runError = clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("node address is altered in the catalog, but fail to sync configuration files: %w", runError)
+	}
+
+	options.updateHostAliases(&vdb)
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful alter_node_address:
+//   - Check NMA connectivity, including at the new address
+//   - Check that the database is down
+//   - Get the network profile of the new address
+//   - Get the catalog path of every node
+//   - Read database info from catalog editor
+//   - Update the addresses of the node in the catalog
+func (vcc VClusterCommands) produceAlterNodeAddressInstructions(options *VAlterNodeAddressOptions,
+	vdb *VCoordinationDatabase) ([]clusterOp, error) {
+	err := options.setUsePasswordAndValidateUsernameIfNeeded(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	// the node may no longer be reachable at its current address
+	reachableHosts := util.SliceDiff(options.Hosts, []string{options.NodeAddress})
+	nmaHealthOp := makeNMAHealthOp(append(slices.Clone(reachableHosts), options.NewAddress))
+	checkDBRunningOp, err := makeHTTPSCheckRunningDBOp(reachableHosts,
+		options.usePassword, options.UserName, options.Password, ReIP)
+	if err != nil {
+		return nil, err
+	}
+	nmaNetworkProfileOp := makeNMANetworkProfileOp([]string{options.NewAddress})
+	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.CatalogPrefix,
+		false /* report all errors */, vdb)
+	nmaReadCatEdOp, err := makeNMAReadCatalogEditorOp(vdb)
+	if err != nil {
+		return nil, err
+	}
+
+	reIPInfo := ReIPInfo{
+		NodeName:               options.NodeName,
+		NodeAddress:            options.NodeAddress,
+		TargetAddress:          options.NewAddress,
+		TargetControlAddress:   options.NewControlAddress,
+		TargetControlBroadcast: options.NewControlBroadcast,
+		TargetExportAddress:    options.NewExportAddress,
+	}
+	nmaReIPOp := makeNMAReIPOp([]ReIPInfo{reIPInfo}, vdb, false /*trim re-ip list*/)
+
+	return []clusterOp{
+		&nmaHealthOp,
+		&checkDBRunningOp,
+		&nmaNetworkProfileOp,
+		&nmaGetNodesInfoOp,
+		&nmaReadCatEdOp,
+		&nmaReIPOp,
+	}, nil
+}
+
+// isAlteredNode returns whether a node is the one to alter, by its name and
+// its current address, whichever are set
+func (options *VAlterNodeAddressOptions) isAlteredNode(host string, vnode *VCoordinationNode) bool {
+	return (options.NodeName == "" || vnode.Name == options.NodeName) &&
+		(options.NodeAddress == "" || host == options.NodeAddress)
+}
+
+// moveNodeToNewAddress moves the altered node to its new address in vdb
+func moveNodeToNewAddress(vdb *VCoordinationDatabase, options *VAlterNodeAddressOptions) error {
+	for host, vnode := range vdb.HostNodeMap {
+		if !options.isAlteredNode(host, vnode) {
+			continue
+		}
+		delete(vdb.HostNodeMap, host)
+		vnode.Address = options.NewAddress
+		vdb.HostNodeMap[options.NewAddress] = vnode
+		return nil
+	}
+	return fmt.Errorf("node %s%s is not found in the database", options.NodeName, options.NodeAddress)
+}
+
+// The generated instructions will later perform the following operations necessary
+// to sync the configuration files after the catalog is updated:
+//   - Read database info from catalog editor, with the node at its new address
+//   - Sync vertica.conf and spread.conf from a node with the latest catalog to the others
+func (vcc VClusterCommands) produceSyncConfigInstructions(vdb *VCoordinationDatabase) ([]clusterOp, error) {
+	var instructions []clusterOp
+	nmaReadCatEdOp, err := makeNMAReadCatalogEditorOp(vdb)
+	if err != nil {
+		return nil, err
+	}
+	instructions = append(instructions, &nmaReadCatEdOp)
+
+	// the source host is the one with the latest catalog, found by the catalog editor
+	hosts := maps.Keys(vdb.HostNodeMap)
+	produceTransferConfigOps(&instructions, nil /*source hosts for transferring configuration files*/, hosts,
+		nil /*db configurations retrieved from a running db*/, nil /*sandbox*/)
+	return instructions, nil
+}
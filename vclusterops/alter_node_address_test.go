@@ -0,0 +1,106 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestVAlterNodeAddressOptions(t *testing.T) {
+	logger := vlog.Printer{}
+
+	opt := VAlterNodeAddressFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+	opt.CatalogPrefix = "/data"
+	err := opt.validateAnalyzeOptions(logger)
+	assert.ErrorContains(t, err, "must specify the name or the current address of the node")
+
+	opt.NodeAddress = "192.168.1.102"
+	err = opt.validateAnalyzeOptions(logger)
+	assert.ErrorContains(t, err, "must specify the new address of the node")
+
+	opt.NewAddress = "192.168.1.104"
+	assert.NoError(t, opt.validateAnalyzeOptions(logger))
+
+	// negative: invalid or used addresses
+	opt.NewControlBroadcast = "192.168.1.a"
+	assert.Error(t, opt.validateAnalyzeOptions(logger))
+	opt.NewControlBroadcast = ""
+	opt.NewAddress = "0.0.0.0"
+	assert.ErrorContains(t, opt.validateAnalyzeOptions(logger), "should not be an unbound address")
+	opt.NewAddress = "192.168.1.103"
+	assert.ErrorContains(t, opt.validateAnalyzeOptions(logger), "is already used by a node")
+}
+
+func TestMoveNodeToNewAddress(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_test_db_node0001", Address: "192.168.1.101"},
+		"192.168.1.102": {Name: "v_test_db_node0002", Address: "192.168.1.102"},
+	}
+
+	// the node can be found by name
+	opt := VAlterNodeAddressFactory()
+	opt.NodeName = "v_test_db_node0002"
+	opt.NewAddress = "192.168.1.104"
+	assert.NoError(t, moveNodeToNewAddress(&vdb, &opt))
+	assert.NotContains(t, vdb.HostNodeMap, "192.168.1.102")
+	assert.Equal(t, "v_test_db_node0002", vdb.HostNodeMap["192.168.1.104"].Name)
+	assert.Equal(t, "192.168.1.104", vdb.HostNodeMap["192.168.1.104"].Address)
+
+	// or by its current address
+	opt = VAlterNodeAddressFactory()
+	opt.NodeAddress = "192.168.1.101"
+	opt.NewAddress = "192.168.1.105"
+	assert.NoError(t, moveNodeToNewAddress(&vdb, &opt))
+	assert.Equal(t, "v_test_db_node0001", vdb.HostNodeMap["192.168.1.105"].Name)
+
+	// negative: unknown node
+	opt.NodeAddress = "192.168.1.110"
+	assert.ErrorContains(t, moveNodeToNewAddress(&vdb, &opt), "is not found in the database")
+
+	// negative: the name and the address refer to different nodes
+	opt.NodeName = "v_test_db_node0002"
+	opt.NodeAddress = "192.168.1.105"
+	assert.ErrorContains(t, moveNodeToNewAddress(&vdb, &opt), "is not found in the database")
+}
+
+func TestAlterNodeAddressReIPList(t *testing.T) {
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	execContext.nmaVDatabase.HostNodeMap = map[string]*nmaVNode{
+		"192.168.1.101": {Name: "v_test_db_node0001"},
+		"192.168.1.102": {Name: "v_test_db_node0002"},
+	}
+	makeOp := func(nodeName, nodeAddress string) nmaReIPOp {
+		reIPInfo := ReIPInfo{NodeName: nodeName, NodeAddress: nodeAddress, TargetAddress: "192.168.1.104",
+			TargetControlBroadcast: "192.168.1.255"}
+		return makeNMAReIPOp([]ReIPInfo{reIPInfo}, nil, false)
+	}
+
+	op := makeOp("", "192.168.1.102")
+	assert.NoError(t, op.updateReIPList(&execContext))
+	assert.Equal(t, "v_test_db_node0002", op.reIPList[0].NodeName)
+	op = makeOp("v_test_db_node0002", "192.168.1.102")
+	assert.NoError(t, op.updateReIPList(&execContext))
+
+	// negative: the name and the address refer to different nodes
+	op = makeOp("v_test_db_node0001", "192.168.1.102")
+	assert.ErrorContains(t, op.updateReIPList(&execContext), "is not the address of node v_test_db_node0001")
+}
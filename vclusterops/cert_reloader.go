@@ -0,0 +1,150 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// file names of the keys in a kubernetes.io/tls secret, as mounted by Kubernetes
+// and written by cert-manager
+const (
+	K8sSecretKeyFileName    = "tls.key"
+	K8sSecretCertFileName   = "tls.crt"
+	K8sSecretCACertFileName = "ca.crt"
+)
+
+// CertReloader loads the TLS key, certificate, and CA certificate used by the
+// NMA and HTTPS clients from files, and loads them again whenever the files
+// change. Set it in DatabaseOptions.CertReloader to pick up certificates that
+// are rotated in the middle of an operation, e.g., by cert-manager updating a
+// mounted Kubernetes secret, without rebuilding the options.
+//
+// A CertReloader is safe for concurrent use.
+type CertReloader struct {
+	KeyPath    string
+	CertPath   string
+	CACertPath string // optional
+
+	mu        sync.Mutex
+	certs     *httpsCerts
+	fileStamp string
+	lastErr   error
+}
+
+// MakeCertReloader returns a CertReloader for the given key, certificate, and
+// CA certificate files. The CA certificate path can be empty.
+func MakeCertReloader(keyPath, certPath, caCertPath string) *CertReloader {
+	return &CertReloader{KeyPath: keyPath, CertPath: certPath, CACertPath: caCertPath}
+}
+
+// MakeK8sSecretCertReloader returns a CertReloader for a kubernetes.io/tls
+// secret mounted at mountDir
+func MakeK8sSecretCertReloader(mountDir string) *CertReloader {
+	return MakeCertReloader(filepath.Join(mountDir, K8sSecretKeyFileName),
+		filepath.Join(mountDir, K8sSecretCertFileName),
+		filepath.Join(mountDir, K8sSecretCACertFileName))
+}
+
+// Reload loads the certificates if the files changed since they were last
+// loaded. If the new files cannot be loaded, e.g., because the key was updated
+// but its certificate not yet, the previously loaded certificates are kept and
+// the error is returned.
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastErr = r.reload()
+	return r.lastErr
+}
+
+// LastReloadError returns the error of the last failed attempt to load the
+// certificates, or nil if the last attempt succeeded
+func (r *CertReloader) LastReloadError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lastErr
+}
+
+func (r *CertReloader) reload() error {
+	fileStamp, err := r.getFileStamp()
+	if err != nil {
+		return err
+	}
+	if r.certs != nil && fileStamp == r.fileStamp {
+		return nil
+	}
+
+	key, err := os.ReadFile(r.KeyPath)
+	if err != nil {
+		return fmt.Errorf("fail to read TLS key from %s, details: %w", r.KeyPath, err)
+	}
+	cert, err := os.ReadFile(r.CertPath)
+	if err != nil {
+		return fmt.Errorf("fail to read TLS certificate from %s, details: %w", r.CertPath, err)
+	}
+	var caCert []byte
+	if r.CACertPath != "" {
+		caCert, err = os.ReadFile(r.CACertPath)
+		if err != nil {
+			return fmt.Errorf("fail to read TLS CA certificate from %s, details: %w", r.CACertPath, err)
+		}
+	}
+
+	// do not switch to a key and certificate that do not match
+	_, err = tls.X509KeyPair(cert, key)
+	if err != nil {
+		return fmt.Errorf("fail to load TLS certificate %s with key %s, details: %w", r.CertPath, r.KeyPath, err)
+	}
+
+	r.certs = &httpsCerts{key: string(key), cert: string(cert), caCert: string(caCert)}
+	r.fileStamp = fileStamp
+	return nil
+}
+
+// getFileStamp summarizes the size and modification time of the certificate
+// files. Stat follows symlinks, so the stamp changes when Kubernetes swaps the
+// ..data symlink of a mounted secret to a new version.
+func (r *CertReloader) getFileStamp() (string, error) {
+	var fileStamp string
+	for _, path := range []string{r.KeyPath, r.CertPath, r.CACertPath} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("fail to access %s, details: %w", path, err)
+		}
+		fileStamp += fmt.Sprintf("%s:%d:%s;", path, info.Size(), info.ModTime().Format(time.RFC3339Nano))
+	}
+	return fileStamp, nil
+}
+
+// getCerts returns the latest certificates that could be loaded, or nil if
+// they were never loaded
+func (r *CertReloader) getCerts() *httpsCerts {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastErr = r.reload()
+	return r.certs
+}
@@ -0,0 +1,100 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generateTestCert returns a PEM encoded self-signed certificate and its key
+func generateTestCert(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func writeTestSecret(t *testing.T, dir string, certPEM, keyPEM []byte, modTime time.Time) {
+	const filePerm = 0600
+	for name, content := range map[string][]byte{
+		K8sSecretCertFileName:   certPEM,
+		K8sSecretKeyFileName:    keyPEM,
+		K8sSecretCACertFileName: certPEM,
+	} {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, content, filePerm))
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	options := DatabaseOptions{}
+	options.CertReloader = MakeK8sSecretCertReloader(dir)
+
+	// the secret is not mounted yet
+	assert.True(t, options.hasCerts())
+	assert.Nil(t, options.getCerts())
+	assert.ErrorContains(t, options.CertReloader.LastReloadError(), "fail to access")
+
+	// the certificates are loaded from the secret
+	certPEM, keyPEM := generateTestCert(t, "dbadmin")
+	modTime := time.Now().Add(-time.Minute)
+	writeTestSecret(t, dir, certPEM, keyPEM, modTime)
+	certs := options.getCerts()
+	assert.NotNil(t, certs)
+	assert.Equal(t, string(certPEM), certs.cert)
+	assert.Equal(t, string(keyPEM), certs.key)
+	assert.Equal(t, string(certPEM), certs.caCert)
+	assert.NoError(t, options.CertReloader.LastReloadError())
+
+	// the rotated certificates are loaded
+	rotatedCertPEM, rotatedKeyPEM := generateTestCert(t, "dbadmin")
+	writeTestSecret(t, dir, rotatedCertPEM, rotatedKeyPEM, modTime.Add(time.Second))
+	certs = options.getCerts()
+	assert.Equal(t, string(rotatedCertPEM), certs.cert)
+	assert.Equal(t, string(rotatedKeyPEM), certs.key)
+
+	// a half rotated secret, whose key does not match its certificate, is not loaded
+	writeTestSecret(t, dir, certPEM, rotatedKeyPEM, modTime.Add(2*time.Second))
+	certs = options.getCerts()
+	assert.Equal(t, string(rotatedCertPEM), certs.cert)
+	assert.ErrorContains(t, options.CertReloader.Reload(), "fail to load TLS certificate")
+}
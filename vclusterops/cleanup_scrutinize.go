@@ -0,0 +1,121 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type ScrutinizeCleanup = vtypes.ScrutinizeCleanup
+
+// ScrutinizeStagingRetentionHoursDefault is how long the staging data of a
+// scrutinize collection is kept on the hosts by default
+const ScrutinizeStagingRetentionHoursDefault = 72
+
+type VCleanupScrutinizeOptions struct {
+	// basic db info, only the hosts are required
+	DatabaseOptions
+	// the staging data of the collections that are older than this is deleted,
+	// 0 deletes the staging data of all collections
+	RetentionHours int
+}
+
+func VCleanupScrutinizeOptionsFactory() VCleanupScrutinizeOptions {
+	options := VCleanupScrutinizeOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.RetentionHours = ScrutinizeStagingRetentionHoursDefault
+	return options
+}
+
+func (options *VCleanupScrutinizeOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(CleanupScrutinizeCmd, logger)
+	if err != nil {
+		return err
+	}
+	if options.RetentionHours < 0 {
+		return fmt.Errorf("retention hours must not be negative")
+	}
+	return nil
+}
+
+func (options *VCleanupScrutinizeOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VCleanupScrutinizeOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VCleanupScrutinize asks the NMA of the hosts to delete the data that
+// scrutinize staged under /tmp/scrutinize for the collections older than the
+// retention period, as nothing else deletes it. It returns the data deleted
+// from each host, sorted by host.
+func (vcc VClusterCommands) VCleanupScrutinize(options *VCleanupScrutinizeOptions) ([]ScrutinizeCleanup, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	hostCleanups := make(map[string]ScrutinizeCleanup)
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaCleanupScrutinizeOp, err := makeNMACleanupScrutinizeOp(options.Hosts, options.RetentionHours, hostCleanups)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	instructions := []clusterOp{&nmaHealthOp, &nmaCleanupScrutinizeOp}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return nil, fmt.Errorf("fail to delete stale scrutinize staging data: %w", runError)
+	}
+
+	return sortScrutinizeCleanups(hostCleanups), nil
+}
+
+func sortScrutinizeCleanups(hostCleanups map[string]ScrutinizeCleanup) []ScrutinizeCleanup {
+	cleanups := make([]ScrutinizeCleanup, 0, len(hostCleanups))
+	for _, cleanup := range hostCleanups {
+		cleanups = append(cleanups, cleanup)
+	}
+	sort.Slice(cleanups, func(i, j int) bool {
+		return cleanups[i].Host < cleanups[j].Host
+	})
+	return cleanups
+}
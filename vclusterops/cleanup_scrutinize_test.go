@@ -0,0 +1,89 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestNMACleanupScrutinizeOp(t *testing.T) {
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	hostCleanups := make(map[string]ScrutinizeCleanup)
+	op, err := makeNMACleanupScrutinizeOp(hosts, 48, hostCleanups)
+	assert.NoError(t, err)
+	op.setupBasicInfo()
+	assert.NoError(t, op.setupClusterHTTPRequest(hosts))
+	request := op.clusterHTTPRequest.RequestCollection[hosts[0]]
+	assert.Equal(t, DeleteMethod, request.Method)
+	assert.Equal(t, NMACurVersion+"scrutinize/staged", request.Endpoint)
+	assert.Equal(t, "48", request.QueryParams["retention_hours"])
+	assert.True(t, op.getClassification().Mutating)
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {host: hosts[0], status: SUCCESS, statusCode: SuccessCode,
+			content: `{"removed_ids": ["VerticaScrutinize.20240101000000"], "freed_bytes": 1024}`},
+		hosts[1]: {host: hosts[1], status: FAILURE, statusCode: 500, err: fmt.Errorf("disk error")},
+	}
+	err = op.processResult(nil)
+	assert.ErrorContains(t, err, "fail to delete stale scrutinize staging data on host 192.168.1.102")
+	assert.Equal(t, ScrutinizeCleanup{Host: hosts[0], RemovedIDs: []string{"VerticaScrutinize.20240101000000"},
+		FreedBytes: 1024}, hostCleanups[hosts[0]])
+
+	// the cleanup after a collection does not fail scrutinize
+	op.useBestEffort()
+	assert.NoError(t, op.processResult(nil))
+}
+
+func TestCleanupAfterScrutinize(t *testing.T) {
+	options := VScrutinizeOptionsFactory()
+	options.Hosts = []string{"192.168.1.101"}
+	options.manifest = &ScrutinizeManifest{}
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = vHostNodeMap{"192.168.1.101": {Name: "v_test_db_node0001", CatalogPath: "/catalog/node0001"}}
+	vcc := VClusterCommands{}
+
+	instructions, err := vcc.produceScrutinizeInstructions(&options, &vdb)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "NMACleanupScrutinizeOp", instructions[len(instructions)-1].getName())
+
+	options.CleanupStagingData = true
+	instructions, err = vcc.produceScrutinizeInstructions(&options, &vdb)
+	assert.NoError(t, err)
+	cleanupOp, ok := instructions[len(instructions)-1].(*nmaCleanupScrutinizeOp)
+	assert.True(t, ok)
+	assert.Equal(t, ScrutinizeStagingRetentionHoursDefault, cleanupOp.retentionHours)
+	assert.True(t, cleanupOp.bestEffort)
+}
+
+func TestValidateCleanupScrutinizeOptions(t *testing.T) {
+	options := VCleanupScrutinizeOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	assert.Equal(t, ScrutinizeStagingRetentionHoursDefault, options.RetentionHours)
+	assert.NoError(t, options.validateParseOptions(vlog.Printer{}))
+
+	options.RetentionHours = -1
+	assert.ErrorContains(t, options.validateParseOptions(vlog.Printer{}), "must not be negative")
+
+	cleanups := sortScrutinizeCleanups(map[string]ScrutinizeCleanup{
+		"192.168.1.102": {Host: "192.168.1.102"}, "192.168.1.101": {Host: "192.168.1.101"},
+	})
+	assert.Equal(t, "192.168.1.101", cleanups[0].Host)
+}
@@ -21,6 +21,7 @@
 package vclusterops
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -31,6 +32,7 @@ import (
 	"github.com/theckman/yacspin"
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
 /* Op and host http result status
@@ -75,6 +77,9 @@ const (
 	SuccessCode            = 200
 	MultipleChoiceCode     = 300
 	UnauthorizedCode       = 401
+	ForbiddenCode          = 403
+	NotFoundCode           = 404
+	ConflictCode           = 409
 	PreconditionFailedCode = 412
 	InternalErrorCode      = 500
 )
@@ -87,6 +92,16 @@ type hostHTTPResult struct {
 	host       string
 	content    string
 	err        error // This is set if the http response with a status code that is not 2XX
+	duration   time.Duration
+	// the size of the response body, including a downloaded file
+	bytesRead int64
+	// the file that holds the response body instead of content, if the body
+	// is spilled by an adapter set up with setupForSpilling
+	spillFile string
+	// the retries of the request, e.g., the resumes of an interrupted download
+	retries HostRetries
+	// how the certificate of the host was verified, one of the TLSVerify values
+	tlsVerifyMode string
 }
 
 type httpsResponseStatus struct {
@@ -103,6 +118,23 @@ func (hostResult *hostHTTPResult) isUnauthorizedRequest() bool {
 	return hostResult.statusCode == UnauthorizedCode
 }
 
+// The HTTP response with a 403 means the user is authenticated, but does not
+// have the privileges to call the endpoint
+func (hostResult *hostHTTPResult) isForbidden() bool {
+	return hostResult.statusCode == ForbiddenCode
+}
+
+// The HTTP response with a 404 means the endpoint does not exist, e.g., the
+// database is of a version that does not support a feature
+func (hostResult *hostHTTPResult) isNotFound() bool {
+	return hostResult.statusCode == NotFoundCode
+}
+
+// The HTTP response with a 409 means the object to create already exists
+func (hostResult *hostHTTPResult) isConflict() bool {
+	return hostResult.statusCode == ConflictCode
+}
+
 // The HTTP response with a 412 may happen if
 // the local node has not yet joined the cluster; the HTTP server will accept connections once the node joins the cluster.
 func (hostResult *hostHTTPResult) hasPreconditionFailed() bool {
@@ -129,6 +161,13 @@ func (hostResult *hostHTTPResult) isPasswordAndCertificateError(logger vlog.Prin
 	return false
 }
 
+// isInsufficientPrivilege returns whether the node refused a SQL statement
+// of the request as the user lacks a privilege
+func (hostResult *hostHTTPResult) isInsufficientPrivilege() bool {
+	serverErr := &ServerError{}
+	return errors.As(hostResult.err, &serverErr) && serverErr.IsInsufficientPrivilege()
+}
+
 func (hostResult *hostHTTPResult) isInternalError() bool {
 	return hostResult.statusCode == InternalErrorCode
 }
@@ -188,6 +227,7 @@ func (status resultStatus) getStatusString() string {
 // log* implemented by embedding OpBase, but overrideable
 type clusterOp interface {
 	getName() string
+	getDescription() string
 	setLogger(logger vlog.Printer)
 	setupSpinner()
 	startSpinner()
@@ -204,9 +244,16 @@ type clusterOp interface {
 	logFinalize()
 	setupBasicInfo()
 	applyTLSOptions(tlsOptions opTLSOptions) error
+	applyNetworkOptions(networkOptions opNetworkOptions)
 	isSkipExecute() bool
+	getClassification() OpClassification
 	filterUnreachableHosts(execContext *opEngineExecContext)
 	filterHostsBySandbox(execContext *opEngineExecContext)
+	releaseHTTPResults()
+	isAccessDenied() bool
+	getHostErrors() map[string]error
+	getRequestDurations() (time.Duration, map[string]time.Duration)
+	getRequestRetries() map[string]HostRetries
 }
 
 /* Cluster ops basic fields and functions
@@ -222,6 +269,14 @@ type opBase struct {
 	clusterHTTPRequest clusterHTTPRequest
 	skipExecute        bool // This can be set during prepare if we determine no work is needed
 	spinner            *yacspin.Spinner
+	// time spent waiting for the requests of the op, in total and per host
+	requestDuration      time.Duration
+	hostRequestDurations map[string]time.Duration
+	// the retries of the requests of the op to each host, if any
+	hostRetries map[string]HostRetries
+	// the external addresses of the hosts behind NAT, keyed by the internal
+	// addresses that the catalog reports
+	externalAddresses map[string]string
 }
 
 type opResponseMap map[string]string
@@ -230,6 +285,10 @@ func (op *opBase) getName() string {
 	return op.name
 }
 
+func (op *opBase) getDescription() string {
+	return op.description
+}
+
 func (op *opBase) setLogger(logger vlog.Printer) {
 	op.logger = logger.WithName(op.name)
 }
@@ -241,6 +300,9 @@ func (op *opBase) parseAndCheckResponse(host, responseContent string, responseOb
 		return err
 	}
 	op.logger.Info("JSON response", "host", host, "responseObj", responseObj)
+	if catalogResponse, ok := responseObj.(catalogAddressResponse); ok && len(op.externalAddresses) > 0 {
+		catalogResponse.mapAddresses(op.externalAddresses)
+	}
 	return nil
 }
 
@@ -381,7 +443,7 @@ func (op *opBase) logFinalize() {
 }
 
 func (op *opBase) runExecute(execContext *opEngineExecContext) error {
-	err := execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+	err := op.dispatchRequest(execContext)
 	if err != nil {
 		op.logger.Error(err, "Fail to dispatch request, detail", "dispatch request", op.clusterHTTPRequest)
 		return err
@@ -389,6 +451,47 @@ func (op *opBase) runExecute(execContext *opEngineExecContext) error {
 	return nil
 }
 
+// dispatchRequest sends the requests of the op, and keeps how long they took
+// for the timing of the op. Ops that send their requests more than once, e.g.,
+// for polling, add up the time of every round.
+func (op *opBase) dispatchRequest(execContext *opEngineExecContext) error {
+	start := time.Now()
+	err := execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+	op.requestDuration += time.Since(start)
+	if op.hostRequestDurations == nil {
+		op.hostRequestDurations = make(map[string]time.Duration)
+	}
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.hostRequestDurations[host] += result.duration
+		if result.retries.Attempts > 0 {
+			op.addHostRetries(host, &result.retries)
+		}
+	}
+	execContext.observeLatency(op.clusterHTTPRequest.ResultCollection)
+	return err
+}
+
+func (op *opBase) getRequestDurations() (time.Duration, map[string]time.Duration) {
+	return op.requestDuration, op.hostRequestDurations
+}
+
+// addHostRetries adds up the retries of the requests to a host over the rounds
+// of requests of the op
+func (op *opBase) addHostRetries(host string, retries *HostRetries) {
+	if op.hostRetries == nil {
+		op.hostRetries = make(map[string]HostRetries)
+	}
+	total := op.hostRetries[host]
+	total.Attempts += retries.Attempts
+	total.Reasons = append(total.Reasons, retries.Reasons...)
+	total.TotalDelay += retries.TotalDelay
+	op.hostRetries[host] = total
+}
+
+func (op *opBase) getRequestRetries() map[string]HostRetries {
+	return op.hostRetries
+}
+
 type opTLSOptions interface {
 	hasCerts() bool
 	getCerts() *httpsCerts
@@ -432,6 +535,68 @@ func (op *opBase) applyTLSOptions(tlsOptions opTLSOptions) error {
 	return nil
 }
 
+type opNetworkOptions interface {
+	getHostPorts() map[string]HostPorts
+	getProxyURL() string
+	getControlAddresses() map[string]string
+	getAddressMapping() map[string]string
+}
+
+// applyNetworkOptions sets the per-host port overrides on the requests of the
+// op, like applyTLSOptions does for TLS options. The proxy is set on the
+// dispatcher by the op engine instead, so that proxy credentials never end up
+// in the logged requests.
+func (op *opBase) applyNetworkOptions(networkOptions opNetworkOptions) {
+	op.externalAddresses = invertAddressMapping(networkOptions.getAddressMapping())
+
+	hostPorts := networkOptions.getHostPorts()
+	if len(hostPorts) == 0 {
+		return
+	}
+
+	for host := range op.clusterHTTPRequest.RequestCollection {
+		ports, ok := hostPorts[host]
+		if !ok {
+			continue
+		}
+		request := op.clusterHTTPRequest.RequestCollection[host]
+		request.setPort(ports)
+		op.clusterHTTPRequest.RequestCollection[host] = request
+	}
+}
+
+// releaseHTTPResults frees the host results of the op once the op engine
+// no longer needs them
+func (op *opBase) releaseHTTPResults() {
+	op.clusterHTTPRequest.releaseResults()
+}
+
+// getHostErrors returns the error of each host whose request failed
+func (op *opBase) getHostErrors() map[string]error {
+	hostErrors := make(map[string]error)
+	for host := range op.clusterHTTPRequest.ResultCollection {
+		if err := op.clusterHTTPRequest.ResultCollection[host].err; err != nil {
+			hostErrors[host] = err
+		}
+	}
+	return hostErrors
+}
+
+// isAccessDenied returns whether every host denied the requests of the op, as
+// happens when a user with fewer privileges calls a privileged endpoint
+func (op *opBase) isAccessDenied() bool {
+	if len(op.clusterHTTPRequest.ResultCollection) == 0 {
+		return false
+	}
+	for host := range op.clusterHTTPRequest.ResultCollection {
+		result := op.clusterHTTPRequest.ResultCollection[host]
+		if !result.isUnauthorizedRequest() && !result.isForbidden() && !result.isInsufficientPrivilege() {
+			return false
+		}
+	}
+	return true
+}
+
 // isSkipExecute will check state to see if the Execute() portion of the
 // operation should be skipped. Some operations can choose to implement this if
 // they can only determine at runtime where the operation is needed. One
@@ -441,6 +606,23 @@ func (op *opBase) isSkipExecute() bool {
 	return op.skipExecute
 }
 
+type OpClassification = vtypes.OpClassification
+
+var (
+	readOnlyClassification    = OpClassification{Idempotent: true}
+	mutatingClassification    = OpClassification{Mutating: true}
+	idempotentClassification  = OpClassification{Mutating: true, Idempotent: true}
+	destructiveClassification = OpClassification{Mutating: true, Destructive: true}
+)
+
+// getClassification returns the classification of the op. It is read before
+// the op is prepared, to plan the command and to check its policies, so the
+// ops that change the cluster declare it from what they are constructed with.
+// The other ops only read from the cluster.
+func (op *opBase) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
 // hasQuorum checks if we have enough working primary nodes to maintain data integrity
 // quorumCount = (1/2 * number of primary nodes) + 1
 func (op *opBase) hasQuorum(hostCount, primaryNodeCount uint) bool {
@@ -557,8 +739,10 @@ func (opb *opHTTPSBase) validateAndSetUsernameAndPassword(opName string, useHTTP
 	return nil
 }
 
+// ClusterCommands is the logger plus all the public commands of VClusterCommands.
 type ClusterCommands interface {
 	GetLog() vlog.Printer
+	GetWarnings() []vlog.Warning
 	V(int) logr.Logger
 	LogInfo(msg string, keysAndValues ...any)
 	LogError(err error, msg string, keysAndValues ...any)
@@ -569,29 +753,62 @@ type ClusterCommands interface {
 	DisplayWarning(msg string, v ...any)
 	DisplayError(msg string, v ...any)
 
+	VClusterProvider
+}
+
+// VClusterProvider covers every public command of VClusterCommands. Downstream
+// projects, such as the operator or the CLI, can depend on it instead of the
+// concrete type and mock the whole library in their tests.
+type VClusterProvider interface {
 	VAddNode(options *VAddNodeOptions) (VCoordinationDatabase, error)
 	VAddSubcluster(options *VAddSubclusterOptions) error
+	VAlterNodeAddress(options *VAlterNodeAddressOptions) error
 	VAlterSubclusterType(options *VAlterSubclusterTypeOptions) error
+	VCheckLicenseCompliance(options *VCheckLicenseComplianceOptions) error
+	VCleanupScrutinize(options *VCleanupScrutinizeOptions) ([]ScrutinizeCleanup, error)
+	VClearDataCollector(options *VClearDataCollectorOptions) error
 	VCheckVClusterServerPid(options *VCheckVClusterServerPidOptions) ([]string, error)
 	VCreateDatabase(options *VCreateDatabaseOptions) (VCoordinationDatabase, error)
 	VCreateArchive(options *VCreateArchiveOptions) error
+	VCreateUser(options *VCreateUserOptions) error
+	VCreateSupportBundle(options *VSupportBundleOptions) (*SupportBundleManifest, error)
+	VDoctor(options *VDoctorOptions) (*DoctorReport, error)
 	VDropDatabase(options *VDropDatabaseOptions) error
+	VExecutePlan(options *DatabaseOptions, plan *ExecutionPlan, key []byte, run func() error) error
 	VFetchCoordinationDatabase(options *VFetchCoordinationDatabaseOptions) (VCoordinationDatabase, error)
 	VFetchNodesDetails(options *VFetchNodesDetailsOptions) (NodesDetails, error)
 	VFetchNodeState(options *VFetchNodeStateOptions) ([]NodeInfo, error)
+	VGetDataCollectorPolicies(options *VGetDataCollectorPoliciesOptions) ([]DataCollectorPolicy, error)
 	VGetDrainingStatus(options *VGetDrainingStatusOptions) (DrainingStatusList, error)
+	VGetConfigurationParameters(options *VGetConfigurationParameterOptions) (string, error)
+	VGetHardwareInventory(options *VGetHardwareInventoryOptions) (HardwareInventory, error)
 	VInstallPackages(options *VInstallPackagesOptions) (*InstallPackageStatus, error)
+	VListSandboxes(options *VListSandboxesOptions) ([]SandboxInfo, error)
+	VManageConnectionDraining(options *VManageConnectionDrainingOptions) error
+	VMigrateSchema(options *VMigrateSchemaOptions) error
+	VNMALogLevel(options *VNMALogLevelOptions) (map[string]string, error)
+	VPlan(options *DatabaseOptions, key []byte, run func() error) (*ExecutionPlan, error)
 	VPollSubclusterState(options *VPollSubclusterStateOptions) error
 	VPromoteSandboxToMain(options *VPromoteSandboxToMainOptions) error
 	VReIP(options *VReIPOptions) error
+	VRefreshSandbox(options *VRefreshSandboxOptions) error
 	VRemoveNode(options *VRemoveNodeOptions) (VCoordinationDatabase, error)
 	VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VCoordinationDatabase, error)
+	VRenameDatabase(options *VRenameDatabaseOptions) error
 	VRenameSubcluster(options *VRenameSubclusterOptions) error
+	VRotateUserPassword(options *VRotateUserPasswordOptions) error
+	VRunSmokeTest(options *VRunSmokeTestOptions) (SmokeTestResult, error)
 	VReplicateDatabase(options *VReplicationDatabaseOptions) (int64, error)
+	VReplicateDatabaseToTargets(options *VReplicationDatabaseOptions) ([]ReplicationTargetResult, error)
 	VReplicationStatus(options *VReplicationStatusDatabaseOptions) (*ReplicationStatusResponse, error)
+	VGetReplicationStatus(options *VGetReplicationStatusOptions) ([]ReplicationJobStatus, error)
+	VReplicationCancel(options *VReplicationCancelOptions) (*ReplicationStatusResponse, error)
+	VPollReplicationStatus(options *VPollReplicationStatusOptions) (*ReplicationStatusResponse, error)
 	VReviveDatabase(options *VReviveDatabaseOptions) (dbInfo string, vdbPtr *VCoordinationDatabase, err error)
 	VSandbox(options *VSandboxOptions) error
 	VScrutinize(options *VScrutinizeOptions) error
+	VSetConfigurationParameters(options *VSetConfigurationParameterOptions) error
+	VSetDataCollectorPolicy(options *VSetDataCollectorPolicyOptions) error
 	VShowRestorePoints(options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error)
 	VSaveRestorePoint(options *VSaveRestorePointOptions) (err error)
 	VStartDatabase(options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, err error)
@@ -601,6 +818,7 @@ type ClusterCommands interface {
 	VStopNode(options *VStopNodeOptions) error
 	VStopSubcluster(options *VStopSubclusterOptions) error
 	VUnsandbox(options *VUnsandboxOptions) error
+	VWaitForReady(ctx context.Context, options *VWaitForReadyOptions) (*ClusterReadiness, error)
 }
 
 type VClusterCommandsLogger struct {
@@ -611,6 +829,15 @@ func (vcc VClusterCommandsLogger) GetLog() vlog.Printer {
 	return vcc.Log
 }
 
+// GetWarnings returns the non-fatal issues that the commands printed as warnings.
+// Warnings are only collected when Log.Warnings is set.
+func (vcc VClusterCommandsLogger) GetWarnings() []vlog.Warning {
+	if vcc.Log.Warnings == nil {
+		return nil
+	}
+	return vcc.Log.Warnings.Warnings()
+}
+
 func (vcc VClusterCommandsLogger) V(level int) logr.Logger {
 	return vcc.Log.V(level)
 }
@@ -649,6 +876,15 @@ func (vcc VClusterCommandsLogger) DisplayError(msg string, v ...any) {
 
 // VClusterCommands passes state around for all top-level administrator commands
 // (e.g. create db, add node, etc.).
+//
+// VClusterCommands is safe for concurrent use by multiple goroutines, e.g., to
+// manage several databases in parallel from one process, as long as each
+// command gets its own options. Every command runs its own op engine with its
+// own execution context and HTTP adapters. The logger and its Writer are shared
+// by the concurrent commands, so they must be safe for concurrent use.
 type VClusterCommands struct {
 	VClusterCommandsLogger
 }
+
+var _ VClusterProvider = VClusterCommands{}
+var _ ClusterCommands = VClusterCommands{}
@@ -16,18 +16,116 @@
 package vclusterops
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
+// VClusterOpEngine runs a list of instructions. An engine and its instructions
+// belong to a single run, so that concurrent runs share no mutable state.
 type VClusterOpEngine struct {
 	instructions []clusterOp
 	tlsOptions   opTLSOptions
 	execContext  *opEngineExecContext
+	// whether the health gate has been checked
+	healthChecked bool
+	// index of the instruction being run
+	current int
 }
 
+type opReadOnlyOptions interface {
+	isReadOnly() bool
+}
+
+type opRequestOptions interface {
+	getRequestID() string
+	getUserAgent() string
+}
+
+type opCommandLogOptions interface {
+	getCommandLogDir() string
+	getCommandName() string
+}
+
+type opMaintenanceOptions interface {
+	getMaintenancePolicy() *MaintenancePolicy
+	setMaintenanceAdmitted()
+}
+
+type opHealthGateOptions interface {
+	getHealthGate() *HealthGate
+	makeHealthCheckOp(health *ClusterHealth, isRunning *bool) (clusterOp, error)
+}
+
+type opTimingOptions interface {
+	getTimings() *CommandTimings
+}
+
+type opRetryOptions interface {
+	getRetryReport() *RetryReport
+}
+
+type opResultCacheOptions interface {
+	getResultCache() *ResultCache
+}
+
+type opInitiatorOptions interface {
+	getInitiators() []string
+}
+
+type opConnectionMetricsOptions interface {
+	getConnectionMetrics() *ConnectionMetrics
+}
+
+type opActivityOptions interface {
+	getActivity() *CommandActivity
+}
+
+type opNMARecoveryOptions interface {
+	getNMARecovery() *NMARecovery
+}
+
+type opTimeoutOptions interface {
+	useAdaptiveTimeouts() bool
+}
+
+type opMonitoringOptions interface {
+	isMonitoringOnly() bool
+}
+
+type opEndpointPolicyOptions interface {
+	getEndpointPolicy() *EndpointPolicy
+}
+
+type opBudgetOptions interface {
+	getBudget() *CommandBudget
+}
+
+type opPlanOptions interface {
+	// the approved plan that is not checked yet, if any
+	getApprovedPlan() *ExecutionPlan
+	setPlanChecked()
+}
+
+type opSpillOptions interface {
+	getSpillThreshold() int64
+	getSpillDir() string
+}
+
+type (
+	PlannedOp         = vtypes.PlannedOp
+	ReadOnlyModeError = vtypes.ReadOnlyModeError
+	ExecutionPlan     = vtypes.ExecutionPlan
+	PlanDivergedError = vtypes.PlanDivergedError
+)
+
+// errMutatingOpRefused is returned by runInstruction in read-only mode, the op
+// engine turns it into a ReadOnlyModeError with the plan
+var errMutatingOpRefused = errors.New("mutating op refused in read-only mode")
+
 func makeClusterOpEngine(instructions []clusterOp, tlsOptions opTLSOptions) VClusterOpEngine {
 	newClusterOpEngine := VClusterOpEngine{}
 	newClusterOpEngine.instructions = instructions
@@ -52,17 +150,88 @@ func (opEngine *VClusterOpEngine) runInSandbox(logger vlog.Printer,
 }
 
 func (opEngine *VClusterOpEngine) runWithExecContext(logger vlog.Printer, execContext *opEngineExecContext) error {
-	for _, op := range opEngine.instructions {
+	if networkOptions, ok := opEngine.tlsOptions.(opNetworkOptions); ok {
+		execContext.dispatcher.proxyURL = networkOptions.getProxyURL()
+		execContext.dispatcher.setClientAddresses(networkOptions.getControlAddresses())
+		execContext.dispatcher.addAddressMapping(networkOptions.getAddressMapping())
+	}
+
+	requestID, userAgent := generateRequestID(), DefaultUserAgent
+	if requestOptions, ok := opEngine.tlsOptions.(opRequestOptions); ok {
+		userAgent = requestOptions.getUserAgent()
+		if requestOptions.getRequestID() != "" {
+			requestID = requestOptions.getRequestID()
+		}
+	}
+	execContext.dispatcher.headers = map[string]string{RequestIDHeader: requestID, userAgentHeader: userAgent}
+	if timeoutOptions, ok := opEngine.tlsOptions.(opTimeoutOptions); ok {
+		execContext.adaptiveTimeouts = timeoutOptions.useAdaptiveTimeouts()
+	}
+	if policyOptions, ok := opEngine.tlsOptions.(opEndpointPolicyOptions); ok {
+		execContext.dispatcher.endpointPolicy = policyOptions.getEndpointPolicy()
+	}
+	if spillOptions, ok := opEngine.tlsOptions.(opSpillOptions); ok {
+		execContext.dispatcher.spillThreshold = spillOptions.getSpillThreshold()
+		execContext.dispatcher.spillDir = spillOptions.getSpillDir()
+	}
+	if cacheOptions, ok := opEngine.tlsOptions.(opResultCacheOptions); ok && !opEngine.hasMutatingOp() {
+		execContext.dispatcher.resultCache = cacheOptions.getResultCache()
+	}
+	if metricsOptions, ok := opEngine.tlsOptions.(opConnectionMetricsOptions); ok {
+		execContext.dispatcher.connMetrics = metricsOptions.getConnectionMetrics()
+	}
+	if activityOptions, ok := opEngine.tlsOptions.(opActivityOptions); ok {
+		execContext.dispatcher.activity = activityOptions.getActivity()
+	}
+	if recoveryOptions, ok := opEngine.tlsOptions.(opNMARecoveryOptions); ok {
+		execContext.nmaRecovery = recoveryOptions.getNMARecovery()
+	}
+	if initiatorOptions, ok := opEngine.tlsOptions.(opInitiatorOptions); ok {
+		execContext.initiators = initiatorOptions.getInitiators()
+	}
+	if budgetOptions, ok := opEngine.tlsOptions.(opBudgetOptions); ok && budgetOptions.getBudget() != nil {
+		execContext.dispatcher.budget = budgetOptions.getBudget()
+		execContext.dispatcher.budget.begin()
+	}
+	if logOptions, ok := opEngine.tlsOptions.(opCommandLogOptions); ok && logOptions.getCommandLogDir() != "" {
+		commandLogger, closer, err := openCommandLog(logger, logOptions, requestID)
+		if err != nil {
+			// the main log still has the entries of the command
+			logger.PrintWarning("fail to open the log file of the command: %v", err)
+		} else {
+			defer closer.Close()
+			logger = commandLogger
+			execContext.dispatcher.logger = logger.WithName(execContext.dispatcher.name)
+		}
+	}
+	logger = logger.WithValues("requestID", requestID)
+	execContext.dispatcher.logger = execContext.dispatcher.logger.WithValues("requestID", requestID)
+
+	return wrapWithRequestID(opEngine.runInstructions(logger, execContext), requestID)
+}
+
+func (opEngine *VClusterOpEngine) runInstructions(logger vlog.Printer, execContext *opEngineExecContext) error {
+	activity := execContext.dispatcher.activity
+	activity.beginEngine(opEngine.getCommandName(), len(opEngine.instructions))
+	defer activity.endEngine()
+
+	for i, op := range opEngine.instructions {
+		opEngine.current = i
+		activity.beginOp(i, op.getName(), op.getDescription())
 		err := opEngine.runInstruction(logger, execContext, op)
+		if errors.Is(err, errMutatingOpRefused) {
+			return &ReadOnlyModeError{Command: opEngine.getCommandName(), Plan: getPlannedOps(opEngine.instructions[i:]),
+				StateFingerprint: execContext.stateFingerprint()}
+		}
 		if err != nil {
 			return err
 		}
 	}
 
 	// display warning if any unreachable hosts detected
-	if len(opEngine.execContext.unreachableHosts) > 0 {
+	if len(execContext.unreachableHosts) > 0 {
 		logger.DisplayWarning("Unreachable host(s) detected, please check the NMA connectivity in %v",
-			opEngine.execContext.unreachableHosts)
+			execContext.unreachableHosts)
 	}
 
 	return nil
@@ -75,16 +244,52 @@ func (opEngine *VClusterOpEngine) runInstruction(
 	op.setupBasicInfo()
 	op.setupSpinner()
 	defer op.cleanupSpinner()
+	// the results of the op are never read once it is done, whether it
+	// succeeds or not, so we release them (and their spill files) instead of
+	// keeping every host's response body until the engine run ends
+	defer op.releaseHTTPResults()
 
 	op.filterUnreachableHosts(execContext)
 	op.filterHostsBySandbox(execContext)
 
+	// the op is timed whether or not it succeeds
+	var timer opTimer
+	if timings := opEngine.getTimings(); timings != nil {
+		defer timer.record(timings, op)
+	}
+	if report := opEngine.getRetryReport(); report != nil {
+		defer func() { report.add(op.getName(), op.getRequestRetries()) }()
+	}
+
+	activity := execContext.dispatcher.activity
+	activity.setPhase(ActivityPhasePrepare)
 	op.logPrepare()
+	timer.startPhase()
 	err := op.prepare(execContext)
+	timer.endPhase(&timer.timing.Prepare)
 	if err != nil {
 		return fmt.Errorf("prepare %s failed, details: %w", op.getName(), err)
 	}
 
+	if !op.isSkipExecute() && op.getClassification().Mutating {
+		if opEngine.isReadOnly() {
+			logger.PrintInfo("[%s] is not run in read-only mode", op.getName())
+			return errMutatingOpRefused
+		}
+		err = opEngine.checkApprovedPlan(logger, execContext)
+		if err != nil {
+			return err
+		}
+		err = opEngine.waitForMaintenanceWindow(logger, op)
+		if err != nil {
+			return err
+		}
+		err = opEngine.checkHealthGate(logger, execContext, op)
+		if err != nil {
+			return err
+		}
+	}
+
 	if !op.isSkipExecute() {
 		// start the progress spinner
 		op.startSpinner()
@@ -96,20 +301,32 @@ func (opEngine *VClusterOpEngine) runInstruction(
 			op.stopFailSpinnerWithMessage(err.Error())
 			return fmt.Errorf("applying TLS options for %s failed, details: %w", op.getName(), err)
 		}
+		if networkOptions, ok := opEngine.tlsOptions.(opNetworkOptions); ok {
+			op.applyNetworkOptions(networkOptions)
+		}
 
 		// execute an instruction
+		activity.setPhase(ActivityPhaseExecute)
 		op.logExecute()
+		timer.startPhase()
 		err = op.execute(execContext)
+		timer.endPhase(&timer.timing.Execute)
 		if err != nil {
 			// here we do not return an error as the spinner error does not
 			// affect the functionality
 			op.stopFailSpinner()
-			return fmt.Errorf("execute %s failed, details: %w", op.getName(), err)
+			if opEngine.skipDeniedOp(logger, op) {
+				return nil
+			}
+			return fmt.Errorf("execute %s failed, details: %w", op.getName(), collapseHostErrors(err, op.getHostErrors()))
 		}
 	}
 
+	activity.setPhase(ActivityPhaseFinalize)
 	op.logFinalize()
+	timer.startPhase()
 	err = op.finalize(execContext)
+	timer.endPhase(&timer.timing.Finalize)
 	if err != nil {
 		return fmt.Errorf("finalize %s failed, details: %w", op.getName(), err)
 	}
@@ -118,3 +335,112 @@ func (opEngine *VClusterOpEngine) runInstruction(
 
 	return nil
 }
+
+func (opEngine *VClusterOpEngine) getTimings() *CommandTimings {
+	if timingOptions, ok := opEngine.tlsOptions.(opTimingOptions); ok {
+		return timingOptions.getTimings()
+	}
+	return nil
+}
+
+func (opEngine *VClusterOpEngine) getRetryReport() *RetryReport {
+	if retryOptions, ok := opEngine.tlsOptions.(opRetryOptions); ok {
+		return retryOptions.getRetryReport()
+	}
+	return nil
+}
+
+// skipDeniedOp returns whether a failed op is skipped because the user is
+// denied access to it in monitoring mode. Only ops that do not change the
+// cluster run in monitoring mode, so the command goes on without the results
+// of the op.
+func (opEngine *VClusterOpEngine) skipDeniedOp(logger vlog.Printer, op clusterOp) bool {
+	monitoringOptions, ok := opEngine.tlsOptions.(opMonitoringOptions)
+	if !ok || !monitoringOptions.isMonitoringOnly() || !op.isAccessDenied() {
+		return false
+	}
+	logger.DisplayWarning("[%s] is skipped in monitoring mode because the user is denied access to it", op.getName())
+	return true
+}
+
+// hasMutatingOp returns whether any op of the command changes the cluster
+func (opEngine *VClusterOpEngine) hasMutatingOp() bool {
+	for _, op := range opEngine.instructions {
+		if op.getClassification().Mutating {
+			return true
+		}
+	}
+	return false
+}
+
+func (opEngine *VClusterOpEngine) isReadOnly() bool {
+	readOnlyOptions, ok := opEngine.tlsOptions.(opReadOnlyOptions)
+	return ok && readOnlyOptions.isReadOnly()
+}
+
+// waitForMaintenanceWindow checks the maintenance policy, if any, before the
+// first op that changes the cluster
+func (opEngine *VClusterOpEngine) waitForMaintenanceWindow(logger vlog.Printer, op clusterOp) error {
+	maintenanceOptions, ok := opEngine.tlsOptions.(opMaintenanceOptions)
+	if !ok {
+		return nil
+	}
+	policy := maintenanceOptions.getMaintenancePolicy()
+	if policy == nil {
+		return nil
+	}
+
+	err := policy.waitForWindow(logger, op.getName())
+	if err != nil {
+		return err
+	}
+	maintenanceOptions.setMaintenanceAdmitted()
+	return nil
+}
+
+// checkHealthGate checks the health gate, if any, before the first op that
+// changes the cluster. The health check runs with its own dispatcher pool, as
+// the op has already set up the pool for its requests.
+func (opEngine *VClusterOpEngine) checkHealthGate(logger vlog.Printer, execContext *opEngineExecContext, op clusterOp) error {
+	gateOptions, ok := opEngine.tlsOptions.(opHealthGateOptions)
+	if !ok || opEngine.healthChecked {
+		return nil
+	}
+	gate := gateOptions.getHealthGate()
+	if gate == nil {
+		return nil
+	}
+	if err := gate.validate(); err != nil {
+		return err
+	}
+
+	health, isRunning := ClusterHealth{}, false
+	healthCheckOp, err := gateOptions.makeHealthCheckOp(&health, &isRunning)
+	if err != nil {
+		return err
+	}
+	healthContext := makeOpEngineExecContext(logger)
+	healthContext.dispatcher = execContext.dispatcher
+	err = opEngine.runInstruction(logger, &healthContext, healthCheckOp)
+	if err != nil {
+		return err
+	}
+	opEngine.healthChecked = true
+	if !isRunning {
+		logger.PrintInfo("The database is not running, skipping the health gate before %s", op.getName())
+		return nil
+	}
+	return gate.check(logger, op.getName(), &health)
+}
+
+func getPlannedOps(instructions []clusterOp) []PlannedOp {
+	plan := make([]PlannedOp, 0, len(instructions))
+	for _, op := range instructions {
+		plan = append(plan, PlannedOp{
+			Name:           op.getName(),
+			Description:    op.getDescription(),
+			Classification: op.getClassification(),
+		})
+	}
+	return plan
+}
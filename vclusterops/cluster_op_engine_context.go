@@ -15,7 +15,11 @@
 
 package vclusterops
 
-import "github.com/vertica/vcluster/vclusterops/vlog"
+import (
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
 
 type opEngineExecContext struct {
 	dispatcher      requestDispatcher
@@ -32,10 +36,14 @@ type opEngineExecContext struct {
 	defaultSCName                 string            // store the default subcluster name of the database
 	hostsWithLatestCatalog        []string
 	primaryHostsWithLatestCatalog []string
-	startupCommandMap             map[string][]string // store start up command map to start nodes
-	dbInfo                        string              // store the db info that retrieved from communal storage
-	restorePoints                 []RestorePoint      // store list existing restore points that queried from an archive
-	systemTableList               systemTableListInfo // used for staging system tables
+	startupCommandMap             map[string][]string   // store start up command map to start nodes
+	dbInfo                        string                // store the db info that retrieved from communal storage
+	restorePoints                 []RestorePoint        // store list existing restore points that queried from an archive
+	systemTableList               systemTableListInfo   // used for staging system tables
+	licenseStatus                 *licenseStatus        // license limits and usage of a running database
+	omittedObjects                []ReviveOmittedObject // objects that a partial revive did not load
+	// objects that a replication did not copy
+	skippedObjects []ReplicationSkippedObject
 	// hosts on which the wrong authentication occurred
 	hostsWithWrongAuth []string
 
@@ -49,6 +57,16 @@ type opEngineExecContext struct {
 	sandbox string
 	// this vdb will only be used to get sandbox info of the nodes
 	vdbForSandboxInfo *VCoordinationDatabase
+
+	// whether the request timeouts of the polling ops follow the observed latency
+	adaptiveTimeouts bool
+	// the slowest request that did not time out so far
+	maxRequestLatency time.Duration
+	// optional, how the NMA health check restarts the NMA service on the hosts
+	// where it does not respond
+	nmaRecovery *NMARecovery
+	// the hosts that the ops pick their initiator from, any host if empty
+	initiators []string
 }
 
 func makeOpEngineExecContext(logger vlog.Printer) opEngineExecContext {
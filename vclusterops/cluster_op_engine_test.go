@@ -17,7 +17,10 @@ package vclusterops
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -28,6 +31,9 @@ type mockOp struct {
 	calledPrepare  bool
 	calledExecute  bool
 	calledFinalize bool
+	method         string
+	// whether the hosts deny access to the requests
+	denied bool
 }
 
 func makeMockOp(skipExecute bool) mockOp {
@@ -49,9 +55,27 @@ func (m *mockOp) prepare(_ *opEngineExecContext) error {
 
 func (m *mockOp) execute(_ *opEngineExecContext) error {
 	m.calledExecute = true
+	if m.denied {
+		err := fmt.Errorf("permission denied")
+		m.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+			"host1": {host: "host1", status: FAILURE, statusCode: ForbiddenCode, err: err},
+		}
+		return err
+	}
+	m.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"host1": {host: "host1", status: SUCCESS, content: "{}"},
+	}
 	return nil
 }
 
+// the mock op changes the cluster if it sends any request other than GET
+func (m *mockOp) getClassification() OpClassification {
+	if m.method != "" && m.method != GetMethod {
+		return mutatingClassification
+	}
+	return readOnlyClassification
+}
+
 func (m *mockOp) finalize(_ *opEngineExecContext) error {
 	m.calledFinalize = true
 	return nil
@@ -64,7 +88,7 @@ func (m *mockOp) processResult(_ *opEngineExecContext) error {
 func (m *mockOp) setupClusterHTTPRequest(hosts []string) error {
 	m.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
 	for i := range hosts {
-		m.clusterHTTPRequest.RequestCollection[hosts[i]] = hostHTTPRequest{}
+		m.clusterHTTPRequest.RequestCollection[hosts[i]] = hostHTTPRequest{Method: m.method}
 	}
 	return nil
 }
@@ -83,3 +107,269 @@ func TestSkipExecuteOp(t *testing.T) {
 	assert.False(t, opWithSkipEnabled.calledExecute)
 	assert.True(t, opWithSkipEnabled.calledFinalize)
 }
+
+func TestReleaseResults(t *testing.T) {
+	op := makeMockOp(false)
+	instructions := []clusterOp{&op}
+	opEngn := makeClusterOpEngine(instructions, nil)
+	err := opEngn.run(vlog.Printer{})
+	assert.NoError(t, err)
+	assert.True(t, op.calledFinalize)
+	// host results of a finalized op should not be retained by the engine
+	assert.Nil(t, op.clusterHTTPRequest.ResultCollection)
+
+	// the results of a failed op are released too
+	failedOp := makeMockOp(false)
+	failedOp.denied = true
+	opEngn = makeClusterOpEngine([]clusterOp{&failedOp}, nil)
+	err = opEngn.run(vlog.Printer{})
+	assert.ErrorContains(t, err, "permission denied")
+	assert.False(t, failedOp.calledFinalize)
+	assert.Nil(t, failedOp.clusterHTTPRequest.ResultCollection)
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	readOp := makeMockOp(false)
+	readOp.name = "read-op"
+	readOp.method = GetMethod
+	writeOp := makeMockOp(false)
+	writeOp.name = "write-op"
+	writeOp.description = "Change the cluster"
+	writeOp.method = PostMethod
+	skippedWriteOp := makeMockOp(true)
+	lastOp := makeMockOp(false)
+	lastOp.name = "last-op"
+	lastOp.method = GetMethod
+
+	// the ops that only read run, and the engine stops at the first op that writes
+	instructions := []clusterOp{&readOp, &skippedWriteOp, &writeOp, &lastOp}
+	opEngn := makeClusterOpEngine(instructions, &DatabaseOptions{ReadOnly: true})
+	err := opEngn.run(vlog.Printer{})
+	readOnlyErr := &ReadOnlyModeError{}
+	assert.ErrorAs(t, err, &readOnlyErr)
+	assert.Equal(t, []PlannedOp{
+		{Name: "write-op", Description: "Change the cluster", Classification: mutatingClassification},
+		{Name: "last-op", Classification: readOnlyClassification},
+	}, readOnlyErr.Plan)
+	assert.True(t, readOp.calledFinalize)
+	assert.True(t, skippedWriteOp.calledFinalize)
+	assert.True(t, writeOp.calledPrepare)
+	assert.False(t, writeOp.calledExecute)
+	assert.False(t, lastOp.calledPrepare)
+
+	// all ops run when read-only mode is off
+	writeOp.calledExecute = false
+	opEngn = makeClusterOpEngine([]clusterOp{&writeOp}, &DatabaseOptions{})
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+	assert.True(t, writeOp.calledExecute)
+}
+
+func TestMonitoringMode(t *testing.T) {
+	deniedOp := makeMockOp(false)
+	deniedOp.method = GetMethod
+	deniedOp.denied = true
+	readOp := makeMockOp(false)
+	readOp.method = GetMethod
+
+	// the ops that the user is denied access to are skipped
+	warnings := &vlog.WarningCollector{}
+	opEngn := makeClusterOpEngine([]clusterOp{&deniedOp, &readOp}, &DatabaseOptions{MonitoringOnly: true})
+	assert.NoError(t, opEngn.run(vlog.Printer{Warnings: warnings}))
+	assert.False(t, deniedOp.calledFinalize)
+	assert.Nil(t, deniedOp.clusterHTTPRequest.ResultCollection)
+	assert.True(t, readOp.calledFinalize)
+	assert.Len(t, warnings.Warnings(), 1)
+
+	// the ops that change the cluster are not run
+	writeOp := makeMockOp(false)
+	writeOp.method = PostMethod
+	opEngn = makeClusterOpEngine([]clusterOp{&writeOp}, &DatabaseOptions{MonitoringOnly: true})
+	readOnlyErr := &ReadOnlyModeError{}
+	assert.ErrorAs(t, opEngn.run(vlog.Printer{}), &readOnlyErr)
+	assert.False(t, writeOp.calledExecute)
+
+	// the command fails out of monitoring mode
+	opEngn = makeClusterOpEngine([]clusterOp{&deniedOp}, &DatabaseOptions{})
+	assert.ErrorContains(t, opEngn.run(vlog.Printer{}), "permission denied")
+}
+
+func TestOpClassification(t *testing.T) {
+	// ops that change the cluster declare their classification before prepare
+	dropNodeOp, err := makeHTTPSDropNodeOp("v_db_node0002", []string{"host1"}, false, "", nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, OpClassification{Mutating: true, Destructive: true}, dropNodeOp.getClassification())
+
+	prepareDirsOp, err := makeNMAPrepareDirectoriesOp(vHostNodeMap{}, false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, mutatingClassification, prepareDirsOp.getClassification())
+	prepareDirsOp.forceCleanup = true
+	assert.True(t, prepareDirsOp.getClassification().Destructive)
+
+	renameOp := makeNMARenameDatabaseOp(testDBName, "newdb", &VCoordinationDatabase{})
+	assert.Equal(t, mutatingClassification, renameOp.getClassification())
+
+	// other ops only read
+	healthOp := makeNMAHealthOp([]string{"host1"})
+	assert.Equal(t, readOnlyClassification, healthOp.getClassification())
+}
+
+func TestConcurrentEngineRuns(t *testing.T) {
+	const runCount = 8
+	errs := make(chan error, runCount)
+	ops := make([]mockOp, runCount)
+	for i := range ops {
+		ops[i] = makeMockOp(false)
+		go func(op *mockOp) {
+			opEngn := makeClusterOpEngine([]clusterOp{op}, nil)
+			errs <- opEngn.run(vlog.Printer{})
+		}(&ops[i])
+	}
+	for i := 0; i < runCount; i++ {
+		assert.NoError(t, <-errs)
+	}
+	for i := range ops {
+		assert.True(t, ops[i].calledFinalize)
+	}
+}
+
+func TestConcurrentDispatcherSetup(t *testing.T) {
+	const dispatcherCount = 8
+	dispatchers := make([]requestDispatcher, dispatcherCount)
+	done := make(chan struct{}, dispatcherCount)
+	for i := range dispatchers {
+		dispatchers[i] = makeHTTPRequestDispatcher(vlog.Printer{})
+		go func(dispatcher *requestDispatcher, host string) {
+			dispatcher.setup([]string{host})
+			dispatcher.setupForDownload([]string{host}, map[string]string{host: "/tmp/" + host})
+			done <- struct{}{}
+		}(&dispatchers[i], fmt.Sprintf("192.168.1.%d", i))
+	}
+	for i := 0; i < dispatcherCount; i++ {
+		<-done
+	}
+
+	// each dispatcher only has the adapter of its own host
+	for i := range dispatchers {
+		assert.Len(t, dispatchers[i].pool.connections, 1)
+		assert.Contains(t, dispatchers[i].pool.connections, fmt.Sprintf("192.168.1.%d", i))
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	writeOp := makeMockOp(false)
+	writeOp.name = "write-op"
+	writeOp.method = PostMethod
+
+	// the errors of a command include its correlation ID
+	options := &DatabaseOptions{ReadOnly: true, RequestID: "orchestrator-42", UserAgent: "operator/1.0"}
+	opEngn := makeClusterOpEngine([]clusterOp{&writeOp}, options)
+	err := opEngn.run(vlog.Printer{})
+	requestID, ok := GetRequestID(err)
+	assert.True(t, ok)
+	assert.Equal(t, "orchestrator-42", requestID)
+	assert.ErrorContains(t, err, "(request ID: orchestrator-42)")
+	readOnlyErr := &ReadOnlyModeError{}
+	assert.ErrorAs(t, err, &readOnlyErr)
+
+	// the correlation ID and the user agent are sent with every request
+	assert.Equal(t, map[string]string{RequestIDHeader: "orchestrator-42", userAgentHeader: "operator/1.0"},
+		opEngn.execContext.dispatcher.headers)
+
+	// a correlation ID is generated for a command that does not have one,
+	// without changing the options of the caller, and the sub-commands that
+	// copy its options share it
+	dbOptions := DatabaseOptionsFactory()
+	dbOptions.DBName = testDBName
+	dbOptions.RawHosts = []string{"192.168.1.101"}
+	assert.NoError(t, dbOptions.validateBaseOptions(StopDBCmd, vlog.Printer{}))
+	assert.Empty(t, dbOptions.RequestID)
+	firstRequestID := dbOptions.getRequestID()
+	assert.Regexp(t, "^vc-[0-9a-f]{16}$", firstRequestID)
+	subOptions := dbOptions
+	assert.NoError(t, subOptions.validateBaseOptions(StopDBCmd, vlog.Printer{}))
+	assert.Equal(t, firstRequestID, subOptions.getRequestID())
+
+	// the options run again get a new correlation ID
+	assert.NoError(t, dbOptions.validateBaseOptions(StopDBCmd, vlog.Printer{}))
+	assert.NotEqual(t, firstRequestID, dbOptions.getRequestID())
+}
+
+func TestOpTimings(t *testing.T) {
+	op := makeMockOp(false)
+	op.name = "timed-op"
+	skippedOp := makeMockOp(true)
+	timings := &CommandTimings{}
+	opEngn := makeClusterOpEngine([]clusterOp{&op, &skippedOp}, &DatabaseOptions{Timings: timings})
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+
+	// every op is timed in the order it is run
+	ops := timings.Ops()
+	assert.Len(t, ops, 2)
+	assert.Equal(t, "timed-op", ops[0].Name)
+	assert.Equal(t, skippedOp.getName(), ops[1].Name)
+	assert.Zero(t, ops[1].Execute)
+	assert.Equal(t, timings.Total(), ops[0].Prepare+ops[0].Execute+ops[0].Finalize+
+		ops[1].Prepare+ops[1].Finalize)
+
+	// the request time of an op is split from the rest of its execute time
+	op.requestDuration = 3 * time.Second
+	op.hostRequestDurations = map[string]time.Duration{"host1": 2 * time.Second, "host2": 3 * time.Second}
+	timer := opTimer{timing: OpTiming{Execute: 4 * time.Second}}
+	timings.Reset()
+	timer.record(timings, &op)
+	ops = timings.Ops()
+	assert.Len(t, ops, 1)
+	assert.Equal(t, 3*time.Second, ops[0].Request)
+	assert.Equal(t, time.Second, ops[0].Process)
+	assert.Equal(t, op.hostRequestDurations, ops[0].Hosts)
+
+	// the ops are not timed without a collector
+	timings.Reset()
+	opEngn = makeClusterOpEngine([]clusterOp{&op}, &DatabaseOptions{})
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+	assert.Empty(t, timings.Ops())
+}
+
+func TestRetryReport(t *testing.T) {
+	op := makeMockOp(false)
+	op.name = "retried-op"
+	op.addHostRetries("host1", &HostRetries{Attempts: 1, Reasons: []string{"unexpected EOF"}, TotalDelay: time.Second})
+	op.addHostRetries("host1", &HostRetries{Attempts: 2, Reasons: []string{"reset", "reset"}, TotalDelay: time.Second})
+	quietOp := makeMockOp(false)
+	report := &RetryReport{}
+	opEngn := makeClusterOpEngine([]clusterOp{&op, &quietOp}, &DatabaseOptions{Retries: report})
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+
+	// the retries are added up per host, and only hosts with retries are reported
+	hosts := report.Hosts()
+	assert.Len(t, hosts, 1)
+	assert.Equal(t, 3, hosts["host1"].Attempts)
+	assert.Equal(t, 2*time.Second, hosts["host1"].TotalDelay)
+	assert.Equal(t, []string{"[retried-op] unexpected EOF", "[retried-op] reset", "[retried-op] reset"},
+		hosts["host1"].Reasons)
+
+	report.Reset()
+	assert.Empty(t, report.Hosts())
+}
+
+func TestCommandLog(t *testing.T) {
+	op := makeMockOp(false)
+	dir := t.TempDir()
+	options := DatabaseOptions{CommandLogDir: dir, RequestID: "req-1", commandName: "start_db"}
+	opEngn := makeClusterOpEngine([]clusterOp{&op}, &options)
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+
+	// the log of the command is written to its own file
+	content, err := os.ReadFile(filepath.Join(dir, "start_db-req-1.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "is successfully completed")
+	assert.Contains(t, string(content), `"requestID": "req-1"`)
+
+	// the commands without a name are logged anyway
+	assert.Equal(t, filepath.Join(dir, "command-req-2.log"), getCommandLogPath(dir, "", "req-2"))
+
+	// negative: the directory does not exist, which does not fail the command
+	options.CommandLogDir = filepath.Join(dir, "missing")
+	opEngn = makeClusterOpEngine([]clusterOp{&op}, &options)
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+}
@@ -0,0 +1,137 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// ClusterConnection holds the settings to connect to a database cluster
+type ClusterConnection struct {
+	DBName   string
+	RawHosts []string
+	IPv6     bool
+	UserName string
+	Password *string
+	// in-memory TLS key and certificates, or a CertReloader to load them from files
+	Key          string
+	Cert         string
+	CaCert       string
+	CertReloader *CertReloader
+	HostPorts    map[string]HostPorts
+	SOCKS5Proxy  string
+}
+
+// ClusterRegistry holds the connection settings of multiple named clusters,
+// e.g., a source database, its DR database, and sandbox targets, so that tools
+// managing a fleet can refer to each cluster by name. A ClusterRegistry is safe
+// for concurrent use.
+type ClusterRegistry struct {
+	mu       sync.RWMutex
+	clusters map[string]ClusterConnection
+}
+
+type ClusterNotFoundError = vtypes.ClusterNotFoundError
+
+func MakeClusterRegistry() *ClusterRegistry {
+	return &ClusterRegistry{clusters: make(map[string]ClusterConnection)}
+}
+
+// Register adds the connection settings of a cluster, or replaces them if the
+// name is already registered
+func (r *ClusterRegistry) Register(name string, conn *ClusterConnection) error {
+	if name == "" {
+		return fmt.Errorf("must specify a cluster name")
+	}
+	err := util.ValidateName(name, "cluster", true)
+	if err != nil {
+		return err
+	}
+	err = util.ValidateDBName(conn.DBName)
+	if err != nil {
+		return fmt.Errorf("invalid connection settings of cluster %s: %w", name, err)
+	}
+	if len(conn.RawHosts) == 0 {
+		return fmt.Errorf("invalid connection settings of cluster %s: must specify a host or host list", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clusters[name] = *conn
+	return nil
+}
+
+// Remove removes a cluster from the registry
+func (r *ClusterRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clusters, name)
+}
+
+// Get returns the connection settings of a cluster
+func (r *ClusterRegistry) Get(name string) (ClusterConnection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	conn, ok := r.clusters[name]
+	if !ok {
+		return conn, &ClusterNotFoundError{Name: name}
+	}
+	return conn, nil
+}
+
+// Names returns the sorted names of the registered clusters
+func (r *ClusterRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clusters))
+	for name := range r.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTo sets the connection options of a command to the settings of a
+// registered cluster, e.g., the DatabaseOptions of a command, or the TargetDB of
+// a replication. Options that are not connection settings, like the catalog
+// path, are left unchanged.
+func (r *ClusterRegistry) ApplyTo(name string, opt *DatabaseOptions) error {
+	conn, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+
+	opt.DBName = conn.DBName
+	opt.RawHosts = append([]string{}, conn.RawHosts...)
+	// the hosts of a replication target are read from Hosts, which commands
+	// otherwise resolve from RawHosts
+	opt.Hosts = append([]string{}, conn.RawHosts...)
+	opt.IPv6 = conn.IPv6
+	opt.UserName = conn.UserName
+	opt.Password = conn.Password
+	opt.Key = conn.Key
+	opt.Cert = conn.Cert
+	opt.CaCert = conn.CaCert
+	opt.CertReloader = conn.CertReloader
+	opt.HostPorts = conn.HostPorts
+	opt.SOCKS5Proxy = conn.SOCKS5Proxy
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterRegistry(t *testing.T) {
+	registry := MakeClusterRegistry()
+	password := "secret"
+	assert.NoError(t, registry.Register("source", &ClusterConnection{DBName: "prod_db",
+		RawHosts: []string{"192.168.1.101", "192.168.1.102"}, UserName: "dbadmin", Password: &password}))
+	assert.NoError(t, registry.Register("dr", &ClusterConnection{DBName: "dr_db",
+		RawHosts: []string{"192.168.2.101"}, UserName: "repl_user", SOCKS5Proxy: "socks5://jump:1080"}))
+	assert.Equal(t, []string{"dr", "source"}, registry.Names())
+
+	// a replication refers to its source and target by name
+	options := VReplicationDatabaseFactory()
+	options.CatalogPrefix = "/catalog"
+	assert.NoError(t, registry.ApplyTo("source", &options.DatabaseOptions))
+	assert.NoError(t, registry.ApplyTo("dr", &options.TargetDB))
+	assert.Equal(t, "prod_db", options.DBName)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, options.RawHosts)
+	assert.Equal(t, &password, options.Password)
+	assert.Equal(t, "/catalog", options.CatalogPrefix)
+	assert.Equal(t, "dr_db", options.TargetDB.DBName)
+	assert.Equal(t, []string{"192.168.2.101"}, options.TargetDB.Hosts)
+	assert.Equal(t, "socks5://jump:1080", options.TargetDB.SOCKS5Proxy)
+
+	// the applied hosts do not alias the registry
+	options.RawHosts[0] = "192.168.1.201"
+	conn, err := registry.Get("source")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.101", conn.RawHosts[0])
+
+	// negative: unknown or removed cluster, and invalid settings
+	registry.Remove("dr")
+	err = registry.ApplyTo("dr", &options.TargetDB)
+	notFoundErr := &ClusterNotFoundError{}
+	assert.True(t, errors.As(err, &notFoundErr))
+	assert.Equal(t, "dr", notFoundErr.Name)
+	assert.ErrorContains(t, registry.Register("", &ClusterConnection{}), "must specify a cluster name")
+	assert.ErrorContains(t, registry.Register("sandbox1", &ClusterConnection{DBName: "sb_db"}),
+		"must specify a host or host list")
+	assert.ErrorContains(t, registry.Register("sandbox1", &ClusterConnection{DBName: "sb-db", RawHosts: []string{"h1"}}),
+		"invalid connection settings of cluster sandbox1")
+}
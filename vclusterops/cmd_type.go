@@ -43,6 +43,29 @@ const (
 	RemoveNodeSyncCat
 	CreateArchiveCmd
 	PollSubclusterStateCmd
+	RenameDBCmd
+	AlterNodeAddressCmd
+	NMALogLevelCmd
+	CheckLicenseComplianceCmd
+	GetHardwareInventoryCmd
+	GetDataCollectorPoliciesCmd
+	SetDataCollectorPolicyCmd
+	ClearDataCollectorCmd
+	RunSmokeTestCmd
+	CreateUserCmd
+	RotateUserPasswordCmd
+	FetchNodeStateCmd
+	ReplicationStatusCmd
+	ListSandboxesCmd
+	RefreshSandboxCmd
+	MigrateSchemaCmd
+	CleanupScrutinizeCmd
+	ReplicationCancelCmd
+	DoctorCmd
+	WaitForReadyCmd
+	GetNMALogLevelCmd
+	// the number of command types, keep it last
+	numCmdTypes
 )
 
 var cmdStringMap = map[CmdType]string{
@@ -84,6 +107,27 @@ var cmdStringMap = map[CmdType]string{
 	RemoveNodeSyncCat:            "remove_node_sync_cat",
 	CreateArchiveCmd:             "create_archive",
 	PollSubclusterStateCmd:       "poll_subcluster_state",
+	RenameDBCmd:                  "rename_db",
+	AlterNodeAddressCmd:          "alter_node_address",
+	NMALogLevelCmd:               "nma_log_level",
+	CheckLicenseComplianceCmd:    "check_license_compliance",
+	GetHardwareInventoryCmd:      "get_hardware_inventory",
+	GetDataCollectorPoliciesCmd:  "get_data_collector_policies",
+	SetDataCollectorPolicyCmd:    "set_data_collector_policy",
+	ClearDataCollectorCmd:        "clear_data_collector",
+	RunSmokeTestCmd:              "run_smoke_test",
+	CreateUserCmd:                "create_user",
+	RotateUserPasswordCmd:        "rotate_user_password",
+	FetchNodeStateCmd:            "fetch_node_state",
+	ReplicationStatusCmd:         "replication_status",
+	ListSandboxesCmd:             "list_sandboxes",
+	RefreshSandboxCmd:            "refresh_sandbox",
+	MigrateSchemaCmd:             "migrate_schema",
+	CleanupScrutinizeCmd:         "cleanup_scrutinize",
+	ReplicationCancelCmd:         "replication_cancel",
+	DoctorCmd:                    "doctor",
+	WaitForReadyCmd:              "wait_for_ready",
+	GetNMALogLevelCmd:            "get_nma_log_level",
 }
 
 func (cmd CmdType) CmdString() string {
@@ -92,3 +136,92 @@ func (cmd CmdType) CmdString() string {
 	}
 	return "unknown_operation"
 }
+
+// the effect of each command as a whole, every command type must be listed
+var cmdClassifications = map[CmdType]OpClassification{
+	CreateDBCmd:                  mutatingClassification,
+	DropDBCmd:                    destructiveClassification,
+	StopDBCmd:                    mutatingClassification,
+	StartDBCmd:                   mutatingClassification,
+	AddNodeCmd:                   mutatingClassification,
+	RemoveNodeCmd:                destructiveClassification,
+	StartNodeCmd:                 mutatingClassification,
+	StopNodeCmd:                  mutatingClassification,
+	RestartNodeCmd:               mutatingClassification,
+	AddSubclusterCmd:             mutatingClassification,
+	RemoveSubclusterCmd:          destructiveClassification,
+	StopSubclusterCmd:            mutatingClassification,
+	StartSubclusterCmd:           mutatingClassification,
+	SandboxSCCmd:                 mutatingClassification,
+	UnsandboxSCCmd:               mutatingClassification,
+	ShowRestorePointsCmd:         readOnlyClassification,
+	SaveRestorePointsCmd:         mutatingClassification,
+	InstallPackagesCmd:           mutatingClassification,
+	ConfigRecoverCmd:             mutatingClassification,
+	GetDrainingStatusCmd:         readOnlyClassification,
+	ManageConnectionDrainingCmd:  mutatingClassification,
+	SetConfigurationParameterCmd: mutatingClassification,
+	GetConfigurationParameterCmd: readOnlyClassification,
+	ReplicationStartCmd:          mutatingClassification,
+	PromoteSandboxToMainCmd:      mutatingClassification,
+	FetchNodesDetailsCmd:         readOnlyClassification,
+	AlterSubclusterTypeCmd:       mutatingClassification,
+	RenameScCmd:                  mutatingClassification,
+	ReIPCmd:                      mutatingClassification,
+	ScrutinizeCmd:                readOnlyClassification,
+	CreateDBSyncCat:              mutatingClassification,
+	StartDBSyncCat:               mutatingClassification,
+	StopDBSyncCat:                mutatingClassification,
+	StopSCSyncCat:                mutatingClassification,
+	AddNodeSyncCat:               mutatingClassification,
+	StartNodeSyncCat:             mutatingClassification,
+	RemoveNodeSyncCat:            mutatingClassification,
+	CreateArchiveCmd:             mutatingClassification,
+	PollSubclusterStateCmd:       readOnlyClassification,
+	RenameDBCmd:                  mutatingClassification,
+	AlterNodeAddressCmd:          mutatingClassification,
+	NMALogLevelCmd:               mutatingClassification,
+	CheckLicenseComplianceCmd:    readOnlyClassification,
+	GetHardwareInventoryCmd:      readOnlyClassification,
+	GetDataCollectorPoliciesCmd:  readOnlyClassification,
+	SetDataCollectorPolicyCmd:    mutatingClassification,
+	ClearDataCollectorCmd:        destructiveClassification,
+	RunSmokeTestCmd:              mutatingClassification,
+	CreateUserCmd:                mutatingClassification,
+	RotateUserPasswordCmd:        mutatingClassification,
+	FetchNodeStateCmd:            readOnlyClassification,
+	ReplicationStatusCmd:         readOnlyClassification,
+	ListSandboxesCmd:             readOnlyClassification,
+	RefreshSandboxCmd:            destructiveClassification,
+	MigrateSchemaCmd:             mutatingClassification,
+	CleanupScrutinizeCmd:         idempotentClassification,
+	ReplicationCancelCmd:         mutatingClassification,
+	DoctorCmd:                    readOnlyClassification,
+	WaitForReadyCmd:              readOnlyClassification,
+	GetNMALogLevelCmd:            readOnlyClassification,
+}
+
+// commands that a user with monitoring privileges only can run, see
+// DatabaseOptions.MonitoringOnly
+var monitoringCmds = map[CmdType]bool{
+	FetchNodeStateCmd:    true,
+	FetchNodesDetailsCmd: true,
+	ReplicationStatusCmd: true,
+	DoctorCmd:            true,
+	WaitForReadyCmd:      true,
+}
+
+// commands that select their hosts with DatabaseOptions.HostSelector
+var hostSelectorCmds = map[CmdType]bool{
+	ScrutinizeCmd:           true,
+	GetHardwareInventoryCmd: true,
+}
+
+// getClassification returns the effect of a command as a whole, for policies
+// that are decided before its ops are produced
+func (cmd CmdType) getClassification() OpClassification {
+	if classification, ok := cmdClassifications[cmd]; ok {
+		return classification
+	}
+	return mutatingClassification
+}
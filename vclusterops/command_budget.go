@@ -0,0 +1,122 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const (
+	BudgetRequests        = vtypes.BudgetRequests
+	BudgetBytesDownloaded = vtypes.BudgetBytesDownloaded
+	BudgetDuration        = vtypes.BudgetDuration
+)
+
+type CommandBudgetExceededError = vtypes.CommandBudgetExceededError
+
+// CommandBudget limits the resources that a command may use, so that a
+// command cannot run away on a huge cluster. A limit that is not positive is
+// not enforced. The budget is checked before the requests of every op are
+// sent, so the requests that cross a limit are completed, and the next ones
+// fail with a CommandBudgetExceededError. It is shared by all the ops of the
+// command, and should not be reused by another command.
+type CommandBudget struct {
+	// the number of HTTP requests, one per host of every op
+	MaxRequests int
+	// the size of the response bodies, including the downloaded files
+	MaxBytesDownloaded int64
+	// the time since the first op of the command started
+	MaxDuration time.Duration
+
+	mu              sync.Mutex
+	start           time.Time
+	requests        int
+	bytesDownloaded int64
+	// returns the current time, time.Now if nil
+	now func() time.Time
+}
+
+func (budget *CommandBudget) getNow() time.Time {
+	if budget.now != nil {
+		return budget.now()
+	}
+	return time.Now()
+}
+
+// begin starts the clock of the budget, once for the whole command
+func (budget *CommandBudget) begin() {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	if budget.start.IsZero() {
+		budget.start = budget.getNow()
+	}
+}
+
+// reserveRequests checks that the budget allows sending requestCount more
+// requests, and counts them if it does
+func (budget *CommandBudget) reserveRequests(requestCount int) error {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	if budget.MaxDuration > 0 {
+		elapsed := budget.getNow().Sub(budget.start)
+		if elapsed > budget.MaxDuration {
+			return &CommandBudgetExceededError{Resource: BudgetDuration,
+				Limit: int64(budget.MaxDuration), Used: int64(elapsed)}
+		}
+	}
+	if budget.MaxBytesDownloaded > 0 && budget.bytesDownloaded >= budget.MaxBytesDownloaded {
+		return &CommandBudgetExceededError{Resource: BudgetBytesDownloaded,
+			Limit: budget.MaxBytesDownloaded, Used: budget.bytesDownloaded}
+	}
+	if budget.MaxRequests > 0 && budget.requests+requestCount > budget.MaxRequests {
+		return &CommandBudgetExceededError{Resource: BudgetRequests,
+			Limit: int64(budget.MaxRequests), Used: int64(budget.requests)}
+	}
+	budget.requests += requestCount
+	return nil
+}
+
+// addResults counts the bytes downloaded by the requests of an op
+func (budget *CommandBudget) addResults(results map[string]hostHTTPResult) {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	for host := range results {
+		budget.bytesDownloaded += results[host].bytesRead
+	}
+}
+
+// Usage returns the requests sent and the bytes downloaded so far
+func (budget *CommandBudget) Usage() (requests int, bytesDownloaded int64) {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	return budget.requests, budget.bytesDownloaded
+}
+
+// byteCounter counts the bytes read from a response body
+type byteCounter struct {
+	reader io.Reader
+	count  int64
+}
+
+func (counter *byteCounter) Read(p []byte) (int, error) {
+	n, err := counter.reader.Read(p)
+	counter.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,75 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestCommandBudget(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	budget := CommandBudget{MaxRequests: 3, MaxBytesDownloaded: 100, MaxDuration: time.Minute}
+	budget.now = func() time.Time { return now }
+	budget.begin()
+
+	assert.NoError(t, budget.reserveRequests(2))
+	err := budget.reserveRequests(2)
+	budgetErr := &CommandBudgetExceededError{}
+	assert.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, BudgetRequests, budgetErr.Resource)
+	assert.EqualError(t, err, "the command exceeded its budget of 3 requests, used 2")
+	assert.NoError(t, budget.reserveRequests(1))
+
+	// the bytes are checked before the requests
+	budget.MaxRequests = 0
+	budget.addResults(map[string]hostHTTPResult{"192.168.1.101": {bytesRead: 60}, "192.168.1.102": {bytesRead: 40}})
+	requests, bytesDownloaded := budget.Usage()
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, int64(100), bytesDownloaded)
+	assert.ErrorContains(t, budget.reserveRequests(1), "budget of 100 bytes downloaded, used 100")
+
+	// the clock starts with the first op of the command
+	budget.MaxBytesDownloaded = 0
+	now = now.Add(2 * time.Minute)
+	budget.begin()
+	assert.EqualError(t, budget.reserveRequests(1), "the command exceeded its budget of 1m0s, ran for 2m0s")
+}
+
+func TestCommandBudgetBytesRead(t *testing.T) {
+	adapter := makeHTTPAdapter(vlog.Printer{})
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"healthy": "true"}`))}
+	result := adapter.generateResult(resp)
+	assert.True(t, result.isPassing())
+	assert.Equal(t, int64(len(`{"healthy": "true"}`)), result.bytesRead)
+
+	// no request is sent once the budget is used up
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher.budget = &CommandBudget{MaxRequests: 1}
+	dispatcher.setup([]string{"192.168.1.101", "192.168.1.102"})
+	httpRequest := clusterHTTPRequest{
+		RequestCollection: map[string]hostHTTPRequest{"192.168.1.101": {}, "192.168.1.102": {}},
+	}
+	assert.ErrorContains(t, dispatcher.sendRequest(&httpRequest, nil), "budget of 1 requests")
+	assert.Empty(t, httpRequest.ResultCollection)
+}
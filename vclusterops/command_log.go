@@ -0,0 +1,45 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// defaultCommandLogName names the log file of the commands that do not
+// validate their options with a command type
+const defaultCommandLogName = "command"
+
+// getCommandLogPath returns the log file of a command run in dir, named after
+// the command and its correlation ID, e.g., start_db-<request ID>.log. All the
+// op engines of the command write to the same file.
+func getCommandLogPath(dir, commandName, requestID string) string {
+	if commandName == "" {
+		commandName = defaultCommandLogName
+	}
+	return filepath.Join(dir, commandName+"-"+requestID+".log")
+}
+
+// openCommandLog returns a printer that writes to the log file of the command
+// in addition to the main log
+func openCommandLog(logger vlog.Printer, logOptions opCommandLogOptions,
+	requestID string) (vlog.Printer, io.Closer, error) {
+	path := getCommandLogPath(logOptions.getCommandLogDir(), logOptions.getCommandName(), requestID)
+	return logger.WithLogFile(path)
+}
@@ -0,0 +1,193 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// CommandQueue serializes the commands that conflict on the same database,
+// e.g., so that remove_node does not run while a replication changes the
+// database. Two commands conflict when both change the cluster; the commands
+// that only read can run at any time. Commands that wait are started in the
+// order in which they were queued. A CommandQueue is safe for concurrent use.
+type CommandQueue struct {
+	mu        sync.Mutex
+	databases map[string]*databaseQueue
+}
+
+type databaseQueue struct {
+	running []queuedCommand
+	waiting []*queuedCommand
+	// closed and replaced whenever a command of the database starts or ends
+	changed chan struct{}
+}
+
+type queuedCommand struct {
+	cmd            CmdType
+	classification OpClassification
+}
+
+type CommandConflictError = vtypes.CommandConflictError
+
+func MakeCommandQueue() *CommandQueue {
+	return &CommandQueue{databases: make(map[string]*databaseQueue)}
+}
+
+func (q *CommandQueue) getDatabaseQueue(dbName string) *databaseQueue {
+	key := strings.ToLower(dbName)
+	dbQueue, ok := q.databases[key]
+	if !ok {
+		dbQueue = &databaseQueue{changed: make(chan struct{})}
+		q.databases[key] = dbQueue
+	}
+	return dbQueue
+}
+
+// getConflicts returns the running commands that conflict with a command
+func (dbQueue *databaseQueue) getConflicts(command *queuedCommand) []string {
+	var conflicts []string
+	if !command.classification.Mutating {
+		return nil
+	}
+	for _, running := range dbQueue.running {
+		if running.classification.Mutating {
+			conflicts = append(conflicts, running.cmd.CmdString())
+		}
+	}
+	return conflicts
+}
+
+// getWaitingConflicts returns the commands that were queued before a command,
+// which change the cluster
+func (dbQueue *databaseQueue) getWaitingConflicts(command *queuedCommand) []string {
+	var conflicts []string
+	if !command.classification.Mutating {
+		return nil
+	}
+	for _, waiting := range dbQueue.waiting {
+		if waiting == command {
+			break
+		}
+		if waiting.classification.Mutating {
+			conflicts = append(conflicts, waiting.cmd.CmdString()+" (queued)")
+		}
+	}
+	return conflicts
+}
+
+func (dbQueue *databaseQueue) notify() {
+	close(dbQueue.changed)
+	dbQueue.changed = make(chan struct{})
+}
+
+func (dbQueue *databaseQueue) removeWaiting(command *queuedCommand) {
+	for i, waiting := range dbQueue.waiting {
+		if waiting == command {
+			dbQueue.waiting = append(dbQueue.waiting[:i], dbQueue.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// start records a command as running. The lock must be held.
+func (dbQueue *databaseQueue) start(command *queuedCommand) {
+	dbQueue.removeWaiting(command)
+	dbQueue.running = append(dbQueue.running, *command)
+	dbQueue.notify()
+}
+
+func (q *CommandQueue) finish(dbName string, command *queuedCommand) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	dbQueue := q.getDatabaseQueue(dbName)
+	for i := range dbQueue.running {
+		if dbQueue.running[i] == *command {
+			dbQueue.running = append(dbQueue.running[:i], dbQueue.running[i+1:]...)
+			break
+		}
+	}
+	dbQueue.notify()
+	q.dropIfIdle(dbName, dbQueue)
+}
+
+// dropIfIdle forgets a database once none of its commands run or wait. The
+// lock must be held.
+func (q *CommandQueue) dropIfIdle(dbName string, dbQueue *databaseQueue) {
+	if len(dbQueue.running) == 0 && len(dbQueue.waiting) == 0 {
+		delete(q.databases, strings.ToLower(dbName))
+	}
+}
+
+// TryRun runs a command on a database, or returns a CommandConflictError
+// without waiting if it conflicts with the running commands
+func (q *CommandQueue) TryRun(dbName string, cmd CmdType, run func() error) error {
+	command := &queuedCommand{cmd: cmd, classification: cmd.getClassification()}
+
+	q.mu.Lock()
+	dbQueue := q.getDatabaseQueue(dbName)
+	conflicts := append(dbQueue.getConflicts(command), dbQueue.getWaitingConflicts(command)...)
+	if len(conflicts) > 0 {
+		q.mu.Unlock()
+		return &CommandConflictError{DBName: dbName, Command: cmd.CmdString(), Conflicting: conflicts}
+	}
+	dbQueue.start(command)
+	q.mu.Unlock()
+
+	defer q.finish(dbName, command)
+	return run()
+}
+
+// Run runs a command on a database after the conflicting commands end. If ctx
+// is done while the command waits, the command is not run, and the error
+// wraps a CommandConflictError and the error of ctx.
+func (q *CommandQueue) Run(ctx context.Context, dbName string, cmd CmdType, run func() error) error {
+	command := &queuedCommand{cmd: cmd, classification: cmd.getClassification()}
+
+	q.mu.Lock()
+	dbQueue := q.getDatabaseQueue(dbName)
+	dbQueue.waiting = append(dbQueue.waiting, command)
+	for {
+		conflicts := append(dbQueue.getConflicts(command), dbQueue.getWaitingConflicts(command)...)
+		if len(conflicts) == 0 {
+			break
+		}
+		changed := dbQueue.changed
+		q.mu.Unlock()
+		select {
+		case <-changed:
+			q.mu.Lock()
+		case <-ctx.Done():
+			q.mu.Lock()
+			dbQueue.removeWaiting(command)
+			dbQueue.notify()
+			q.dropIfIdle(dbName, dbQueue)
+			q.mu.Unlock()
+			conflictErr := &CommandConflictError{DBName: dbName, Command: cmd.CmdString(), Conflicting: conflicts}
+			return errors.Join(conflictErr, ctx.Err())
+		}
+	}
+	dbQueue.start(command)
+	q.mu.Unlock()
+
+	defer q.finish(dbName, command)
+	return run()
+}
@@ -0,0 +1,123 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentCommandQueueTryRun(t *testing.T) {
+	queue := MakeCommandQueue()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, queue.TryRun(testDBName, ReplicationStartCmd, func() error {
+			close(started)
+			<-release
+			return nil
+		}))
+	}()
+	<-started
+
+	// a command that changes the same database conflicts
+	err := queue.TryRun(testDBName, RemoveNodeCmd, func() error { return nil })
+	conflictErr := &CommandConflictError{}
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, []string{"replication_start"}, conflictErr.Conflicting)
+
+	// commands that only read, and commands on other databases, do not conflict
+	assert.NoError(t, queue.TryRun(testDBName, GetDrainingStatusCmd, func() error { return nil }))
+	assert.NoError(t, queue.TryRun("other_db", RemoveNodeCmd, func() error { return nil }))
+
+	close(release)
+	wg.Wait()
+	assert.NoError(t, queue.TryRun(testDBName, RemoveNodeCmd, func() error { return nil }))
+}
+
+func TestConcurrentCommandQueueRun(t *testing.T) {
+	queue := MakeCommandQueue()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var order []CmdType
+	var mu sync.Mutex
+	record := func(cmd CmdType) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, cmd)
+			return nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, queue.Run(context.Background(), testDBName, StopDBCmd, func() error {
+			close(started)
+			<-release
+			return record(StopDBCmd)()
+		}))
+	}()
+	<-started
+
+	// a command that waits too long is not run
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := queue.Run(ctx, testDBName, AddNodeCmd, record(AddNodeCmd))
+	conflictErr := &CommandConflictError{}
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the conflicting command runs after the running one ends
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, queue.Run(context.Background(), testDBName, StartDBCmd, record(StartDBCmd)))
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []CmdType{StopDBCmd, StartDBCmd}, order)
+}
+
+func TestCommandQueueDropsIdleDatabases(t *testing.T) {
+	queue := MakeCommandQueue()
+	assert.NoError(t, queue.TryRun(testDBName, RemoveNodeCmd, func() error { return nil }))
+	assert.NoError(t, queue.Run(context.Background(), testDBName, AddNodeCmd, func() error { return nil }))
+	assert.Empty(t, queue.databases)
+}
+
+func TestCmdTypeClassification(t *testing.T) {
+	for cmd := CreateDBCmd; cmd < numCmdTypes; cmd++ {
+		_, ok := cmdClassifications[cmd]
+		assert.True(t, ok, "command type %d is not classified", cmd)
+	}
+	assert.False(t, GetNMALogLevelCmd.getClassification().Mutating)
+	assert.True(t, NMALogLevelCmd.getClassification().Mutating)
+	assert.True(t, ReplicationStartCmd.getClassification().Mutating)
+	assert.False(t, ReplicationStatusCmd.getClassification().Mutating)
+	assert.False(t, FetchNodeStateCmd.getClassification().Mutating)
+	assert.True(t, DropDBCmd.getClassification().Destructive)
+}
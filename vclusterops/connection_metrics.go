@@ -0,0 +1,107 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type HostConnectionMetrics = vtypes.HostConnectionMetrics
+
+// ConnectionMetrics keeps the metrics of the HTTP connections of commands per
+// host, e.g., of the target database of replication, whose networking issues
+// would otherwise look like failures of the replication. It is safe to share
+// between commands, e.g., the polls of a replication status, which add up
+// their requests.
+type ConnectionMetrics struct {
+	mu    sync.Mutex
+	hosts map[string]HostConnectionMetrics
+}
+
+// record adds a request to a host, which could not connect if err is not nil
+func (m *ConnectionMetrics) record(host, tlsVerifyMode string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hosts == nil {
+		m.hosts = make(map[string]HostConnectionMetrics)
+	}
+	metrics := m.hosts[host]
+	metrics.Requests++
+	if tlsVerifyMode != "" {
+		if metrics.TLSVerifyModes == nil {
+			metrics.TLSVerifyModes = make(map[string]int)
+		}
+		metrics.TLSVerifyModes[tlsVerifyMode]++
+	}
+	if err != nil {
+		metrics.ConnectionFailures++
+		if isTLSError(err) {
+			metrics.TLSErrors++
+		}
+		metrics.LastError = err.Error()
+	} else {
+		metrics.TotalLatency += latency
+		metrics.MaxLatency = max(metrics.MaxLatency, latency)
+	}
+	m.hosts[host] = metrics
+}
+
+// Hosts returns a copy of the metrics collected so far, keyed by host address
+func (m *ConnectionMetrics) Hosts() map[string]HostConnectionMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hosts := make(map[string]HostConnectionMetrics, len(m.hosts))
+	for host, metrics := range m.hosts {
+		if metrics.TLSVerifyModes != nil {
+			modes := make(map[string]int, len(metrics.TLSVerifyModes))
+			for mode, count := range metrics.TLSVerifyModes {
+				modes[mode] = count
+			}
+			metrics.TLSVerifyModes = modes
+		}
+		hosts[host] = metrics
+	}
+	return hosts
+}
+
+// Reset drops the metrics collected so far
+func (m *ConnectionMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hosts = nil
+}
+
+// isTLSError returns whether a request failed in the TLS handshake, e.g., on
+// an untrusted or expired certificate of the server
+func isTLSError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verificationErr) || errors.As(err, &recordHeaderErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) ||
+		// the HTTP client replaces the record header error of a service without TLS
+		strings.Contains(err.Error(), "server gave HTTP response to HTTPS client")
+}
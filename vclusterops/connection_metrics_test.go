@@ -0,0 +1,83 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestConnectionMetrics(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(`[]`))
+		assert.NoError(t, err)
+	})
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+	// a server without TLS fails the handshake
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+	// nothing listens on the port of a closed listener
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	metrics := &ConnectionMetrics{}
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher.connMetrics = metrics
+	dispatcher.setup([]string{"127.0.0.1"})
+	adapter, ok := dispatcher.pool.connections["127.0.0.1"].(*httpAdapter)
+	assert.True(t, ok)
+	password := "password"
+	send := func(serverURL string, port int) {
+		if serverURL != "" {
+			parsedURL, parseErr := url.Parse(serverURL)
+			assert.NoError(t, parseErr)
+			port, parseErr = strconv.Atoi(parsedURL.Port())
+			assert.NoError(t, parseErr)
+		}
+		request := hostHTTPRequest{Method: PostMethod, Port: port, Password: &password}
+		resultChannel := make(chan hostHTTPResult, 1)
+		adapter.sendRequest(&request, resultChannel)
+		<-resultChannel
+	}
+
+	send(tlsServer.URL, 0)
+	send(tlsServer.URL, 0)
+	send(plainServer.URL, 0)
+	send("", closedPort)
+
+	hostMetrics := metrics.Hosts()["127.0.0.1"]
+	assert.Equal(t, 4, hostMetrics.Requests)
+	assert.Equal(t, 2, hostMetrics.ConnectionFailures)
+	assert.Equal(t, 1, hostMetrics.TLSErrors)
+	assert.Positive(t, hostMetrics.MaxLatency)
+	assert.Equal(t, hostMetrics.TotalLatency/2, hostMetrics.AverageLatency())
+	assert.Contains(t, hostMetrics.LastError, "connection refused")
+	// the requests with a password do not verify the certificates
+	assert.Equal(t, map[string]int{TLSVerifyInsecure: 4}, hostMetrics.TLSVerifyModes)
+
+	metrics.Reset()
+	assert.Empty(t, metrics.Hosts())
+}
@@ -32,6 +32,8 @@ import (
 // an existing database (e.g. VStartDatabase) consume a VCoordinationDatabase struct.
 type VCoordinationDatabase struct {
 	Name string
+	// the unique ID of the database, as reported by the running database
+	UUID string
 	// processed path prefixes
 	CatalogPrefix string
 	DataPrefix    string
@@ -161,7 +163,7 @@ func (vdb *VCoordinationDatabase) addNode(vnode *VCoordinationNode) error {
 // and HostNodeMap. existingHostNodeMap contains entries for nodes
 // in all clusters (main and sandboxes)
 func (vdb *VCoordinationDatabase) addHosts(hosts []string, scName string,
-	existingHostNodeMap vHostNodeMap) error {
+	existingHostNodeMap vHostNodeMap, controlAddresses map[string]string) error {
 	totalHostCount := len(hosts) + len(existingHostNodeMap)
 	nodeNameToHost := genNodeNameToHostMap(existingHostNodeMap)
 	for _, host := range hosts {
@@ -172,6 +174,7 @@ func (vdb *VCoordinationDatabase) addHosts(hosts []string, scName string,
 		}
 		nodeNameToHost[name] = host
 		vNode.setNode(vdb, host, name, scName)
+		vNode.ControlAddress = controlAddresses[host]
 		err := vdb.addNode(&vNode)
 		if err != nil {
 			return err
@@ -187,6 +190,7 @@ func (vdb *VCoordinationDatabase) addHosts(hosts []string, scName string,
 func (vdb *VCoordinationDatabase) copy(targetHosts []string) VCoordinationDatabase {
 	v := VCoordinationDatabase{
 		Name:                    vdb.Name,
+		UUID:                    vdb.UUID,
 		CatalogPrefix:           vdb.CatalogPrefix,
 		DataPrefix:              vdb.DataPrefix,
 		IsEon:                   vdb.IsEon,
@@ -360,6 +364,8 @@ func (vdb *VCoordinationDatabase) hostIsUp(hostName string) bool {
 type VCoordinationNode struct {
 	Name    string `json:"name"`
 	Address string
+	// address on the private network between the nodes, Address if empty
+	ControlAddress string
 	// complete paths, not just prefix
 	CatalogPath          string `json:"catalog_path"`
 	StorageLocations     []string
@@ -384,6 +390,14 @@ func makeVCoordinationNode() VCoordinationNode {
 	return VCoordinationNode{}
 }
 
+// getControlAddress returns the address that the other nodes reach the node through
+func (vnode *VCoordinationNode) getControlAddress() string {
+	if vnode.ControlAddress != "" {
+		return vnode.ControlAddress
+	}
+	return vnode.Address
+}
+
 func (vnode *VCoordinationNode) setFromBasicDBOptions(
 	options *VCreateDatabaseOptions,
 	host string,
@@ -397,6 +411,7 @@ func (vnode *VCoordinationNode) setFromBasicDBOptions(
 		}
 
 		vnode.Address = host
+		vnode.ControlAddress = options.ControlAddresses[host]
 		vnode.Port = options.ClientPort
 		nodeNameSuffix := i + 1
 		vnode.Name = fmt.Sprintf("v_%s_node%04d", dbNameInNode, nodeNameSuffix)
@@ -181,7 +181,11 @@ func (vcc *VClusterCommands) produceCreateArchiveInstructions(options *VCreateAr
 	hosts := options.Hosts
 	// Trim host list
 	hosts = vdb.filterUpHostlist(hosts, options.Sandbox)
-	bootstrapHost := []string{getInitiator(hosts)}
+	initiator, err := options.pickInitiator(hosts)
+	if err != nil {
+		return instructions, err
+	}
+	bootstrapHost := []string{initiator}
 
 	httpsCreateArchiveOp, err := makeHTTPSCreateArchiveOp(bootstrapHost, options.usePassword,
 		options.UserName, options.Password, options.ArchiveName, options.NumRestorePoint)
@@ -45,7 +45,18 @@ type VCreateDatabaseOptions struct {
 	ForceRemovalAtCreation    bool // whether force remove existing directories before creating the database
 	ForceOverwriteFile        bool // whether force overwrite existing config and config param files
 	SkipPackageInstall        bool // whether skip package installation
+	SkipLicenseCheck          bool // whether skip checking the license limits before bootstrapping the catalog
 	TimeoutNodeStartupSeconds int  // timeout in seconds for polling node start up state
+	// options of the check that warns about nodes with unlike hardware
+	HardwareCheck HardwareCheckOptions
+	// K-safety of the design, 0 or 1. By default, the design is 1-safe when
+	// the database has enough nodes, and 0-safe otherwise.
+	KSafety int
+	// optional, the creation profile whose settings are used for the options
+	// left at their default values
+	Profile *CreateDBProfile
+	// the smoke test to run once the database is created
+	SmokeTest SmokeTestOptions
 
 	/* part 3: new params originally in installer generated admintools.conf, now in create db op */
 
@@ -88,6 +99,8 @@ func (options *VCreateDatabaseOptions) setDefaultValues() {
 
 	// optional info
 	options.TimeoutNodeStartupSeconds = util.DefaultTimeoutSeconds
+	options.HardwareCheck.setDefaultValues()
+	options.KSafety = util.DefaultKSafety
 
 	// new params originally in installer generated admintools.conf, now in create db op
 	options.P2p = util.DefaultP2p
@@ -162,19 +175,19 @@ func validateDepotSizeBytes(size string) (bool, error) {
 	if strings.Contains(size, "%") {
 		return true, nil
 	}
-	cleanSize := strings.TrimSpace(size)
+	cleanSize := strings.TrimPrefix(strings.TrimSpace(size), "+")
+	if strings.HasPrefix(cleanSize, "-") {
+		return false, fmt.Errorf("depot size %s is not a valid size because it is <= 0", size)
+	}
 
 	// example depot size: 1024K, 1024M, 2048G, 400T
-	r := regexp.MustCompile(`^([-+]?\d+)([KMGT])$`)
-	matches := r.FindAllStringSubmatch(cleanSize, -1)
-	if len(matches) != 1 {
+	if cleanSize == "" || !strings.Contains("KMGT", cleanSize[len(cleanSize)-1:]) {
 		return false, fmt.Errorf("%s is not a well-formatted whole-number size in bytes of the format <int>[KMGT]", size)
 	}
-
-	valueStr := matches[0][1]
-	value, err := strconv.Atoi(valueStr)
+	value, err := util.ParseSizeString(cleanSize)
 	if err != nil {
-		return false, fmt.Errorf("depot size %s is not a well-formatted whole-number size in bytes of the format <int>[KMGT]", size)
+		return false, fmt.Errorf("depot size %s is not a well-formatted whole-number size in bytes of the format <int>[KMGT]: %w",
+			size, err)
 	}
 	if value <= 0 {
 		return false, fmt.Errorf("depot size %s is not a valid size because it is <= 0", size)
@@ -196,12 +209,19 @@ func validateDepotSize(size string) (bool, error) {
 	return true, nil
 }
 
-func (options *VCreateDatabaseOptions) validateEonOptions() error {
+func (options *VCreateDatabaseOptions) validateEonOptions(logger vlog.Printer) error {
 	if options.CommunalStorageLocation != "" {
 		err := util.ValidateCommunalStorageLocation(options.CommunalStorageLocation)
 		if err != nil {
 			return err
 		}
+		// S3-compatible storage accepts bucket names that AWS does not, so
+		// the AWS naming rules are only a warning
+		if bucket, isS3 := util.GetS3BucketName(options.CommunalStorageLocation); isS3 {
+			if e := util.ValidateS3BucketName(bucket); e != nil {
+				logger.PrintWarning("%v, AWS S3 does not accept such a bucket name", e)
+			}
+		}
 		if options.DepotPrefix == "" {
 			return fmt.Errorf("must specify a depot path with commual storage location")
 		}
@@ -235,17 +255,42 @@ func (options *VCreateDatabaseOptions) validateExtraOptions() error {
 	if options.LargeCluster != util.DefaultLargeCluster && (options.LargeCluster < 1 || options.LargeCluster > util.MaxLargeCluster) {
 		return fmt.Errorf("must specify a valid large cluster value in range [1, 120]")
 	}
+	if options.KSafety != util.DefaultKSafety && options.KSafety != ksafeValueZero && options.KSafety != ksafeValueOne {
+		return fmt.Errorf("must specify a K-safety of %d or %d", ksafeValueZero, ksafeValueOne)
+	}
+	if options.KSafety == ksafeValueOne && len(options.RawHosts) < ksafetyThreshold {
+		return fmt.Errorf("a 1-safe database needs at least %d nodes", ksafetyThreshold)
+	}
+	if options.SmokeTest.RunSmokeTest && options.SkipStartupPolling {
+		return fmt.Errorf("cannot run the smoke test without waiting for the nodes to start up")
+	}
 	return nil
 }
 
+// getKSafety returns the K-safety to mark the design with
+func (options *VCreateDatabaseOptions) getKSafety(hostCount int) int {
+	if options.KSafety != util.DefaultKSafety {
+		return options.KSafety
+	}
+	if hostCount >= ksafetyThreshold {
+		return ksafeValueOne
+	}
+	return ksafeValueZero
+}
+
 func (options *VCreateDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
+	// the settings of the creation profile are validated along with the options
+	err := options.applyProfile()
+	if err != nil {
+		return err
+	}
 	// batch 1: validate required parameters without default values
-	err := options.validateRequiredOptions(logger)
+	err = options.validateRequiredOptions(logger)
 	if err != nil {
 		return err
 	}
 	// batch 2: validate eon params
-	err = options.validateEonOptions()
+	err = options.validateEonOptions(logger)
 	if err != nil {
 		return err
 	}
@@ -314,7 +359,11 @@ func (vcc VClusterCommands) VCreateDatabase(options *VCreateDatabaseOptions) (VC
 		vcc.Log.Error(err, "fail to create database")
 		return vdb, err
 	}
-	return vdb, nil
+	options.updateHostAliases(&vdb)
+
+	// a failed smoke test does not undo the database, it is returned with the error
+	err = vcc.runCommandSmokeTest(&options.SmokeTest, &options.DatabaseOptions, vdb.HostList, true /*use password*/)
+	return vdb, err
 }
 
 // produceCreateDBInstructions will build a list of instructions to execute for
@@ -325,6 +374,8 @@ func (vcc VClusterCommands) VCreateDatabase(options *VCreateDatabaseOptions) (VC
 //   - Check NMA connectivity
 //   - Check to see if any dbs running
 //   - Check NMA versions
+//   - Check that the license limits allow the nodes, unless it is skipped
+//   - Warn about nodes with hardware unlike the bootstrap node, unless it is skipped
 //   - Prepare directories
 //   - Get network profiles
 //   - Bootstrap the database
@@ -371,7 +422,11 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 	var instructions []clusterOp
 
 	hosts := vdb.HostList
-	initiator := getInitiator(hosts)
+	// the pinned initiators, if any, restrict the bootstrap host
+	initiator, err := options.pickInitiator(hosts)
+	if err != nil {
+		return instructions, err
+	}
 
 	nmaHealthOp := makeNMAHealthOp(hosts)
 
@@ -379,7 +434,7 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 	nmaVerticaVersionOp := makeNMACheckVerticaVersionOp(hosts, true, vdb.IsEon)
 
 	// need username for https operations
-	err := options.validateUserName(vcc.Log)
+	err = options.validateUserName(vcc.Log)
 	if err != nil {
 		return instructions, err
 	}
@@ -390,13 +445,19 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 		return instructions, err
 	}
 
+	// fail early if the directories cannot be written by the OS user
+	nmaCheckPathPermissionsOp, err := makeNMACheckPathPermissionsOp(vdb.HostNodeMap)
+	if err != nil {
+		return instructions, err
+	}
+
 	nmaPrepareDirectoriesOp, err := makeNMAPrepareDirectoriesOp(vdb.HostNodeMap,
 		options.ForceRemovalAtCreation, false /*for db revive*/)
 	if err != nil {
 		return instructions, err
 	}
 
-	nmaNetworkProfileOp := makeNMANetworkProfileOp(hosts)
+	nmaNetworkProfileOp := makeNMANetworkProfileOpWithControlAddresses(hosts, options.ControlAddresses)
 
 	// should be only one bootstrap host
 	// making it an array to follow the convention of passing a list of hosts to each operation
@@ -416,6 +477,14 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 		&nmaHealthOp,
 		&nmaVerticaVersionOp,
 		&checkDBRunningOp,
+	)
+	preCheckOps, err := produceCreateDBPreCheckOps(vdb, options)
+	if err != nil {
+		return instructions, err
+	}
+	instructions = append(instructions, preCheckOps...)
+	instructions = append(instructions,
+		&nmaCheckPathPermissionsOp,
 		&nmaPrepareDirectoriesOp,
 		&nmaNetworkProfileOp,
 		&nmaBootstrapCatalogOp,
@@ -445,6 +514,29 @@ func (vcc VClusterCommands) produceCreateDBBootstrapInstructions(
 	return instructions, nil
 }
 
+// produceCreateDBPreCheckOps returns the ops that check the license limits and
+// the hardware of the hosts, unless the checks are skipped
+func produceCreateDBPreCheckOps(vdb *VCoordinationDatabase, options *VCreateDatabaseOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+	if !options.SkipLicenseCheck {
+		nmaCheckLicenseComplianceOp, err := makeNMACheckLicenseComplianceOp(vdb.HostList, vdb.LicensePathOnNode)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaCheckLicenseComplianceOp)
+	}
+	if !options.HardwareCheck.SkipHardwareCheck {
+		// the hosts are compared with the bootstrap host
+		nmaCheckHardwareProfileOp, err := makeNMACheckHardwareProfileOp(options.bootstrapHost,
+			vdb.HostList, &options.HardwareCheck)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaCheckHardwareProfileOp)
+	}
+	return instructions, nil
+}
+
 // produceCreateDBWorkerNodesInstructions returns the workder nodes' instructions for create_db.
 func (vcc VClusterCommands) produceCreateDBWorkerNodesInstructions(
 	vdb *VCoordinationDatabase,
@@ -510,7 +602,7 @@ func (vcc VClusterCommands) produceAdditionalCreateDBInstructions(vdb *VCoordina
 
 	if !options.SkipStartupPolling {
 		httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOp(hosts, true, username, options.Password,
-			options.TimeoutNodeStartupSeconds)
+			options.adaptTimeout(options.TimeoutNodeStartupSeconds, util.DefaultTimeoutSeconds, len(hosts)))
 		if err != nil {
 			return instructions, err
 		}
@@ -526,9 +618,10 @@ func (vcc VClusterCommands) produceAdditionalCreateDBInstructions(vdb *VCoordina
 		instructions = append(instructions, &httpsCreateDepotOp)
 	}
 
-	if len(hosts) >= ksafetyThreshold {
+	// a new design is 0-safe
+	if kSafety := options.getKSafety(len(hosts)); kSafety != ksafeValueZero {
 		httpsMarkDesignKSafeOp, err := makeHTTPSMarkDesignKSafeOp(bootstrapHost, true, username,
-			options.Password, ksafeValueOne)
+			options.Password, kSafety)
 		if err != nil {
 			return instructions, err
 		}
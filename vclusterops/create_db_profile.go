@@ -0,0 +1,194 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type CreateDBProfileConflictError = vtypes.CreateDBProfileConflictError
+
+// the TLS modes of a creation profile
+const (
+	// TLS without verifying the certificates of the servers
+	TLSModeEnable = "enable"
+	// verify the certificates of the NMA and HTTPS service, but not their hostnames
+	TLSModeVerifyCA = "verify-ca"
+	// verify the certificates of the NMA and HTTPS service, and their hostnames
+	TLSModeVerifyFull = "verify-full"
+)
+
+// CreateDBProfile is a named set of create_db settings, so that databases can
+// be created the same way. A setting that is nil is left to the options.
+type CreateDBProfile struct {
+	Name         string  `json:"name"`
+	ShardCount   *int    `json:"shard_count,omitempty"`
+	DepotSize    *string `json:"depot_size,omitempty"`
+	KSafety      *int    `json:"k_safety,omitempty"`
+	TLSMode      *string `json:"tls_mode,omitempty"`
+	LargeCluster *int    `json:"large_cluster,omitempty"`
+}
+
+type createDBProfileFile struct {
+	Profiles []CreateDBProfile `json:"profiles"`
+}
+
+// ReadCreateDBProfiles reads the creation profiles of a JSON file, in the form
+// of {"profiles": [{"name": "small", "shard_count": 6, ...}, ...]}, keyed by name
+func ReadCreateDBProfiles(path string) (map[string]CreateDBProfile, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the creation profile file %s, details: %w", path, err)
+	}
+	profileFile := createDBProfileFile{}
+	err = json.Unmarshal(fileBytes, &profileFile)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse the creation profile file %s, details: %w", path, err)
+	}
+
+	profiles := make(map[string]CreateDBProfile, len(profileFile.Profiles))
+	for i := range profileFile.Profiles {
+		profile := &profileFile.Profiles[i]
+		if profile.Name == "" {
+			return nil, fmt.Errorf("creation profile %d in %s has no name", i+1, path)
+		}
+		if _, ok := profiles[profile.Name]; ok {
+			return nil, fmt.Errorf("creation profile %s is defined more than once in %s", profile.Name, path)
+		}
+		if err := profile.validate(); err != nil {
+			return nil, err
+		}
+		profiles[profile.Name] = *profile
+	}
+	return profiles, nil
+}
+
+// ReadCreateDBProfile reads one creation profile of a JSON file by name
+func ReadCreateDBProfile(path, name string) (*CreateDBProfile, error) {
+	profiles, err := ReadCreateDBProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("creation profile %s is not found in %s", name, path)
+	}
+	return &profile, nil
+}
+
+func (profile *CreateDBProfile) validate() error {
+	if profile.ShardCount != nil && *profile.ShardCount <= 0 {
+		return fmt.Errorf("creation profile %s must have a shard count greater than 0", profile.Name)
+	}
+	if profile.DepotSize != nil {
+		if valid, err := validateDepotSize(*profile.DepotSize); !valid {
+			return fmt.Errorf("creation profile %s has an invalid depot size: %w", profile.Name, err)
+		}
+	}
+	if profile.KSafety != nil && *profile.KSafety != ksafeValueZero && *profile.KSafety != ksafeValueOne {
+		return fmt.Errorf("creation profile %s must have a K-safety of %d or %d", profile.Name, ksafeValueZero, ksafeValueOne)
+	}
+	if profile.TLSMode != nil {
+		switch *profile.TLSMode {
+		case TLSModeEnable, TLSModeVerifyCA, TLSModeVerifyFull:
+		default:
+			return fmt.Errorf("creation profile %s has an invalid TLS mode %s, must be one of %s, %s or %s",
+				profile.Name, *profile.TLSMode, TLSModeEnable, TLSModeVerifyCA, TLSModeVerifyFull)
+		}
+	}
+	if profile.LargeCluster != nil && (*profile.LargeCluster < 1 || *profile.LargeCluster > util.MaxLargeCluster) {
+		return fmt.Errorf("creation profile %s must have a large cluster value in range [1, %d]",
+			profile.Name, util.MaxLargeCluster)
+	}
+	return nil
+}
+
+// mergeProfileSetting sets an option that is left at its default value to the
+// setting of the profile. An option that is set to another value conflicts
+// with the profile.
+func mergeProfileSetting[T comparable](profile *CreateDBProfile, option string, value *T, setting *T, defaultValue T) error {
+	if setting == nil || *value == *setting {
+		return nil
+	}
+	if *value != defaultValue {
+		return &CreateDBProfileConflictError{Profile: profile.Name, Option: option,
+			ProfileValue: fmt.Sprint(*setting), OptionValue: fmt.Sprint(*value)}
+	}
+	*value = *setting
+	return nil
+}
+
+// getTLSMode returns the TLS mode of the certificate verification options
+func (opt *DatabaseOptions) getTLSMode() string {
+	switch {
+	case opt.DoVerifyPeerCertHostname:
+		return TLSModeVerifyFull
+	case opt.DoVerifyNMAServerCert || opt.DoVerifyHTTPSServerCert:
+		return TLSModeVerifyCA
+	default:
+		return TLSModeEnable
+	}
+}
+
+func (opt *DatabaseOptions) setTLSMode(mode string) {
+	opt.DoVerifyNMAServerCert = mode != TLSModeEnable
+	opt.DoVerifyHTTPSServerCert = mode != TLSModeEnable
+	opt.DoVerifyPeerCertHostname = mode == TLSModeVerifyFull
+}
+
+// applyProfile merges the settings of the creation profile, if any, with the options
+func (options *VCreateDatabaseOptions) applyProfile() error {
+	profile := options.Profile
+	if profile == nil {
+		return nil
+	}
+	if err := profile.validate(); err != nil {
+		return err
+	}
+
+	err := mergeProfileSetting(profile, "shard count", &options.ShardCount, profile.ShardCount, 0)
+	if err != nil {
+		return err
+	}
+	err = mergeProfileSetting(profile, "depot size", &options.DepotSize, profile.DepotSize, "")
+	if err != nil {
+		return err
+	}
+	err = mergeProfileSetting(profile, "K-safety", &options.KSafety, profile.KSafety, util.DefaultKSafety)
+	if err != nil {
+		return err
+	}
+	err = mergeProfileSetting(profile, "large cluster", &options.LargeCluster, profile.LargeCluster, util.DefaultLargeCluster)
+	if err != nil {
+		return err
+	}
+
+	if profile.TLSMode == nil {
+		return nil
+	}
+	tlsMode := options.getTLSMode()
+	err = mergeProfileSetting(profile, "TLS mode", &tlsMode, profile.TLSMode, TLSModeEnable)
+	if err != nil {
+		return err
+	}
+	options.setTLSMode(tlsMode)
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+func TestReadCreateDBProfiles(t *testing.T) {
+	profilePath := path.Join(t.TempDir(), "profiles.json")
+	err := os.WriteFile(profilePath, []byte(`{"profiles": [
+		{"name": "small", "shard_count": 6, "depot_size": "20%", "k_safety": 0},
+		{"name": "large", "shard_count": 24, "tls_mode": "verify-ca", "large_cluster": 60}
+	]}`), 0600)
+	assert.NoError(t, err)
+
+	profiles, err := ReadCreateDBProfiles(profilePath)
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 2)
+	profile, err := ReadCreateDBProfile(profilePath, "large")
+	assert.NoError(t, err)
+	assert.Equal(t, 24, *profile.ShardCount)
+	assert.Equal(t, TLSModeVerifyCA, *profile.TLSMode)
+	assert.Nil(t, profile.DepotSize)
+	_, err = ReadCreateDBProfile(profilePath, "medium")
+	assert.ErrorContains(t, err, "creation profile medium is not found")
+
+	// the profiles are validated
+	err = os.WriteFile(profilePath, []byte(`{"profiles": [{"name": "bad", "tls_mode": "verify"}]}`), 0600)
+	assert.NoError(t, err)
+	_, err = ReadCreateDBProfiles(profilePath)
+	assert.ErrorContains(t, err, "invalid TLS mode verify")
+	err = os.WriteFile(profilePath, []byte(`{"profiles": [{"name": "small"}, {"name": "small"}]}`), 0600)
+	assert.NoError(t, err)
+	_, err = ReadCreateDBProfiles(profilePath)
+	assert.ErrorContains(t, err, "defined more than once")
+}
+
+func TestApplyCreateDBProfile(t *testing.T) {
+	shardCount, kSafety, largeCluster := 12, 1, 60
+	depotSize, tlsMode := "40%", TLSModeVerifyFull
+	profile := CreateDBProfile{Name: "standard", ShardCount: &shardCount, DepotSize: &depotSize,
+		KSafety: &kSafety, TLSMode: &tlsMode, LargeCluster: &largeCluster}
+
+	// the options left at their default values are set by the profile
+	options := VCreateDatabaseOptionsFactory()
+	options.Profile = &profile
+	assert.NoError(t, options.applyProfile())
+	assert.Equal(t, 12, options.ShardCount)
+	assert.Equal(t, "40%", options.DepotSize)
+	assert.Equal(t, 1, options.KSafety)
+	assert.Equal(t, 60, options.LargeCluster)
+	assert.True(t, options.DoVerifyNMAServerCert)
+	assert.True(t, options.DoVerifyHTTPSServerCert)
+	assert.True(t, options.DoVerifyPeerCertHostname)
+
+	// an option set to the same value as the profile is fine
+	options = VCreateDatabaseOptionsFactory()
+	options.Profile = &profile
+	options.ShardCount = 12
+	assert.NoError(t, options.applyProfile())
+
+	// an option set to another value conflicts with the profile
+	options.ShardCount = 6
+	err := options.applyProfile()
+	conflictErr := &CreateDBProfileConflictError{}
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, CreateDBProfileConflictError{Profile: "standard", Option: "shard count",
+		ProfileValue: "12", OptionValue: "6"}, *conflictErr)
+
+	options = VCreateDatabaseOptionsFactory()
+	options.Profile = &profile
+	options.DoVerifyNMAServerCert = true
+	err = options.applyProfile()
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "TLS mode", conflictErr.Option)
+}
+
+func TestCreateDBKSafety(t *testing.T) {
+	options := VCreateDatabaseOptionsFactory()
+	assert.Equal(t, util.DefaultKSafety, options.KSafety)
+	assert.Equal(t, ksafeValueZero, options.getKSafety(1))
+	assert.Equal(t, ksafeValueOne, options.getKSafety(3))
+	options.KSafety = ksafeValueZero
+	assert.Equal(t, ksafeValueZero, options.getKSafety(3))
+
+	// a 1-safe database needs enough nodes
+	options.KSafety = ksafeValueOne
+	options.RawHosts = []string{"192.168.1.101"}
+	assert.ErrorContains(t, options.validateExtraOptions(), "needs at least 3 nodes")
+	options.KSafety = 2
+	assert.ErrorContains(t, options.validateExtraOptions(), "must specify a K-safety of 0 or 1")
+}
@@ -50,4 +50,12 @@ func TestValidateDepotSize(t *testing.T) {
 	res, err = validateDepotSize("+119T")
 	assert.Equal(t, res, true)
 	assert.Nil(t, err)
+
+	res, err = validateDepotSize("0K")
+	assert.Equal(t, res, false)
+	assert.ErrorContains(t, err, "it is <= 0")
+
+	res, err = validateDepotSize("119KB")
+	assert.Equal(t, res, false)
+	assert.ErrorContains(t, err, "of the format <int>[KMGT]")
 }
@@ -0,0 +1,152 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VCreateUserOptions struct {
+	// basic db info, UserName and Password are the credentials of the
+	// admin that creates the user
+	DatabaseOptions
+	// name of the user to create
+	NewUserName string
+	// password of the user to create
+	NewUserPassword *string
+	// roles granted to the user, e.g., dbadmin or pseudosuperuser for an
+	// admin user
+	Roles []string
+	// do not fail if the user exists already. The existing user, including
+	// its password and roles, is not changed.
+	IfNotExists bool
+
+	// output: set if IfNotExists is set and the user exists already
+	UserExists bool
+}
+
+func VCreateUserOptionsFactory() VCreateUserOptions {
+	options := VCreateUserOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VCreateUserOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(CreateUserCmd, logger)
+	if err != nil {
+		return err
+	}
+	err = options.validateAuthOptions(CreateUserCmd.CmdString(), logger)
+	if err != nil {
+		return err
+	}
+
+	if options.NewUserName == "" {
+		return fmt.Errorf("must specify the name of the user to create")
+	}
+	err = util.ValidateUserName(options.NewUserName)
+	if err != nil {
+		return err
+	}
+	if options.NewUserPassword == nil || *options.NewUserPassword == "" {
+		return fmt.Errorf("must specify a password for user %s", options.NewUserName)
+	}
+	for _, role := range options.Roles {
+		err = util.ValidateRoleName(role)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VCreateUserOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VCreateUserOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	if err := options.setUsePassword(logger); err != nil {
+		return err
+	}
+	return options.validateUserName(logger)
+}
+
+// VCreateUser creates a database user with a password through the HTTPS
+// service, so that provisioning does not need vsql to create the admin or
+// service users of a new database
+func (vcc VClusterCommands) VCreateUser(options *VCreateUserOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	instructions, err := vcc.produceCreateUserInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to create user %s: %w", options.NewUserName, runError)
+	}
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful create_user:
+//   - Retrieve VDB from HTTP endpoints
+//   - Create the user on an up host of the main cluster
+func (vcc VClusterCommands) produceCreateUserInstructions(options *VCreateUserOptions) ([]clusterOp, error) {
+	// users are in the global catalog, so any up host of the main cluster can create them
+	initiator, err := vcc.getUpInitiatorFromRunningDB(&options.DatabaseOptions, util.MainClusterSandbox)
+	if err != nil {
+		return nil, err
+	}
+
+	httpsCreateUserOp, err := makeHTTPSCreateUserOp([]string{initiator}, options.usePassword,
+		options.UserName, options.Password, options.NewUserName, options.NewUserPassword,
+		options.Roles, options.IfNotExists, &options.UserExists)
+	if err != nil {
+		return nil, err
+	}
+	return []clusterOp{&httpsCreateUserOp}, nil
+}
@@ -0,0 +1,86 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestCreateUserOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	opt := VCreateUserOptionsFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"vnode1"}
+	opt.UserName = "dbadmin"
+	password := "password"
+	opt.Password = &password
+
+	// negative: no user name or password
+	err := opt.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "must specify the name of the user to create")
+	opt.NewUserName = "svc_admin"
+	err = opt.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "must specify a password for user svc_admin")
+
+	newPassword := "new-password"
+	opt.NewUserPassword = &newPassword
+	opt.Roles = []string{"dbadmin", "pseudosuperuser"}
+	assert.NoError(t, opt.validateParseOptions(logger))
+
+	// negative: invalid names
+	opt.Roles = []string{"db;admin"}
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "invalid character in role name: ;")
+	opt.NewUserName = "svc-admin"
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "invalid character in user name: -")
+}
+
+func TestCreateUserOp(t *testing.T) {
+	password := "password"
+	newPassword := "new-password"
+	hosts := []string{"192.168.1.101"}
+	userExists := false
+	op, err := makeHTTPSCreateUserOp(hosts, true, "dbadmin", &password, "svc_admin", &newPassword,
+		[]string{"dbadmin"}, false, &userExists)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"new-password","roles":["dbadmin"]}`, op.requestBody)
+
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(hosts))
+	request := op.clusterHTTPRequest.RequestCollection["192.168.1.101"]
+	assert.Equal(t, PostMethod, request.Method)
+	assert.Equal(t, "v1/users/svc_admin", request.Endpoint)
+	assert.Equal(t, "dbadmin", request.Username)
+
+	// the user exists already
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: FAILURE, statusCode: ConflictCode, err: assert.AnError},
+	}
+	assert.Error(t, op.processResult(&execContext))
+	assert.False(t, userExists)
+
+	op.ifNotExists = true
+	assert.NoError(t, op.processResult(&execContext))
+	assert.True(t, userExists)
+
+	// negative: no password for the new user
+	_, err = makeHTTPSCreateUserOp(hosts, true, "dbadmin", &password, "svc_admin", nil,
+		nil, false, &userExists)
+	assert.ErrorContains(t, err, "should provide a password for the new user")
+}
@@ -0,0 +1,227 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type DataCollectorPolicy = vtypes.DataCollectorPolicy
+
+type VGetDataCollectorPoliciesOptions struct {
+	DatabaseOptions
+	Sandbox string
+	// the components to get the policies of, all components if empty
+	Components []string
+}
+
+type VSetDataCollectorPolicyOptions struct {
+	DatabaseOptions
+	Sandbox string
+	// the components to set the policy of
+	Components []string
+	// the retention to set, the retention that is nil is not changed
+	MemoryBufferKB  *int64
+	DiskSizeKB      *int64
+	IntervalSeconds *int64
+}
+
+type VClearDataCollectorOptions struct {
+	DatabaseOptions
+	Sandbox string
+	// the components to clear the data of
+	Components []string
+	// whether to clear the data of all components, which Components must be empty for
+	AllComponents bool
+}
+
+func VGetDataCollectorPoliciesOptionsFactory() VGetDataCollectorPoliciesOptions {
+	opt := VGetDataCollectorPoliciesOptions{}
+	// set default values to the params
+	opt.setDefaultValues()
+	return opt
+}
+
+func VSetDataCollectorPolicyOptionsFactory() VSetDataCollectorPolicyOptions {
+	opt := VSetDataCollectorPolicyOptions{}
+	// set default values to the params
+	opt.setDefaultValues()
+	return opt
+}
+
+func VClearDataCollectorOptionsFactory() VClearDataCollectorOptions {
+	opt := VClearDataCollectorOptions{}
+	// set default values to the params
+	opt.setDefaultValues()
+	return opt
+}
+
+// validateAnalyzeSQLOptions validates and analyzes the options of the
+// commands that connect to the database through the NMA, e.g., the Data
+// Collector commands, so the username is always required
+func (opt *DatabaseOptions) validateAnalyzeSQLOptions(cmdType CmdType, log vlog.Printer) (err error) {
+	err = opt.validateBaseOptions(cmdType, log)
+	if err != nil {
+		return err
+	}
+	err = opt.validateAuthOptions(cmdType.CmdString(), log)
+	if err != nil {
+		return err
+	}
+
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(opt.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		opt.Hosts, err = util.ResolveRawHostsToAddresses(opt.RawHosts, opt.IPv6)
+		if err != nil {
+			return err
+		}
+		opt.normalizePaths()
+	}
+
+	if err := opt.setUsePassword(log); err != nil {
+		return err
+	}
+	return opt.validateUserName(log)
+}
+
+func (opt *VSetDataCollectorPolicyOptions) validateExtraOptions() error {
+	if len(opt.Components) == 0 {
+		return errors.New("must specify the Data Collector components to set the policy of")
+	}
+	if opt.MemoryBufferKB == nil && opt.DiskSizeKB == nil && opt.IntervalSeconds == nil {
+		return errors.New("must specify the memory buffer size, the disk size, or the interval to keep the data")
+	}
+	for _, value := range []*int64{opt.MemoryBufferKB, opt.DiskSizeKB, opt.IntervalSeconds} {
+		if value != nil && *value < 0 {
+			return fmt.Errorf("the retention of Data Collector components must not be negative, got %d", *value)
+		}
+	}
+	return nil
+}
+
+func (opt *VClearDataCollectorOptions) validateExtraOptions() error {
+	if len(opt.Components) == 0 && !opt.AllComponents {
+		return errors.New("must specify the Data Collector components to clear, or to clear all components")
+	}
+	if len(opt.Components) > 0 && opt.AllComponents {
+		return errors.New("cannot specify Data Collector components when clearing all components")
+	}
+	return nil
+}
+
+// VGetDataCollectorPolicies gets the retention policies of Data Collector components
+func (vcc VClusterCommands) VGetDataCollectorPolicies(options *VGetDataCollectorPoliciesOptions) ([]DataCollectorPolicy, error) {
+	err := options.validateAnalyzeSQLOptions(GetDataCollectorPoliciesCmd, vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []DataCollectorPolicy
+	nmaDataCollectorOp, err := makeNMAGetDataCollectorPoliciesOp(options.Hosts, options.UserName, options.DBName,
+		options.Sandbox, options.Components, &policies, options.Password, options.usePassword)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	err = vcc.runDataCollectorOp(&options.DatabaseOptions, GetDataCollectorPoliciesCmd, options.Sandbox, &nmaDataCollectorOp)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get Data Collector policies: %w", err)
+	}
+	return policies, nil
+}
+
+// VSetDataCollectorPolicy sets the retention policy of Data Collector
+// components on every node of the database
+func (vcc VClusterCommands) VSetDataCollectorPolicy(options *VSetDataCollectorPolicyOptions) error {
+	err := options.validateAnalyzeSQLOptions(SetDataCollectorPolicyCmd, vcc.Log)
+	if err != nil {
+		return err
+	}
+	err = options.validateExtraOptions()
+	if err != nil {
+		return err
+	}
+
+	nmaDataCollectorOp, err := makeNMASetDataCollectorPolicyOp(options.Hosts, options.UserName, options.DBName,
+		options.Sandbox, options.Components, options.MemoryBufferKB, options.DiskSizeKB, options.IntervalSeconds,
+		options.Password, options.usePassword)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	err = vcc.runDataCollectorOp(&options.DatabaseOptions, SetDataCollectorPolicyCmd, options.Sandbox, &nmaDataCollectorOp)
+	if err != nil {
+		return fmt.Errorf("fail to set Data Collector policy: %w", err)
+	}
+	return nil
+}
+
+// VClearDataCollector purges the data of Data Collector components on every
+// node of the database, the policies of the components are not changed
+func (vcc VClusterCommands) VClearDataCollector(options *VClearDataCollectorOptions) error {
+	err := options.validateAnalyzeSQLOptions(ClearDataCollectorCmd, vcc.Log)
+	if err != nil {
+		return err
+	}
+	err = options.validateExtraOptions()
+	if err != nil {
+		return err
+	}
+
+	nmaDataCollectorOp, err := makeNMAClearDataCollectorOp(options.Hosts, options.UserName, options.DBName,
+		options.Sandbox, options.Components, options.Password, options.usePassword)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	err = vcc.runDataCollectorOp(&options.DatabaseOptions, ClearDataCollectorCmd, options.Sandbox, &nmaDataCollectorOp)
+	if err != nil {
+		return fmt.Errorf("fail to clear Data Collector data: %w", err)
+	}
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful Data Collector action.
+//   - Check NMA connectivity
+//   - Check UP nodes and sandboxes info
+//   - Send the Data Collector request
+func (vcc VClusterCommands) runDataCollectorOp(options *DatabaseOptions, cmdType CmdType, sandbox string,
+	nmaDataCollectorOp *nmaDataCollectorOp) error {
+	assertMainClusterUpNodes := sandbox == ""
+
+	// get up hosts in all sandboxes/clusters
+	// exit early if specified sandbox has no up hosts
+	// up hosts will be filtered by sandbox name in prepare stage of nmaDataCollectorOp
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesWithSandboxOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password,
+		cmdType, sandbox, assertMainClusterUpNodes)
+	if err != nil {
+		return err
+	}
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+
+	instructions := []clusterOp{
+		&nmaHealthOp,
+		&httpsGetUpNodesOp,
+		nmaDataCollectorOp,
+	}
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	return clusterOpEngine.run(vcc.Log)
+}
@@ -0,0 +1,77 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataCollectorOptions(t *testing.T) {
+	setOpt := VSetDataCollectorPolicyOptionsFactory()
+	err := setOpt.validateExtraOptions()
+	assert.ErrorContains(t, err, "must specify the Data Collector components")
+	setOpt.Components = []string{"RequestsIssued"}
+	err = setOpt.validateExtraOptions()
+	assert.ErrorContains(t, err, "must specify the memory buffer size, the disk size, or the interval")
+	diskSizeKB := int64(-1)
+	setOpt.DiskSizeKB = &diskSizeKB
+	assert.ErrorContains(t, setOpt.validateExtraOptions(), "must not be negative")
+	diskSizeKB = 10000
+	assert.NoError(t, setOpt.validateExtraOptions())
+
+	clearOpt := VClearDataCollectorOptionsFactory()
+	assert.ErrorContains(t, clearOpt.validateExtraOptions(), "or to clear all components")
+	clearOpt.AllComponents = true
+	assert.NoError(t, clearOpt.validateExtraOptions())
+	clearOpt.Components = []string{"RequestsIssued"}
+	assert.ErrorContains(t, clearOpt.validateExtraOptions(), "cannot specify Data Collector components")
+}
+
+func TestNMADataCollectorOp(t *testing.T) {
+	const host = "192.168.1.101"
+	password := "test_password"
+	var policies []DataCollectorPolicy
+
+	op, err := makeNMAGetDataCollectorPoliciesOp([]string{host}, "dbadmin", testDBName, "", nil,
+		&policies, &password, true)
+	assert.NoError(t, err)
+	assert.False(t, op.getClassification().Mutating)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: {status: SUCCESS,
+		content: `[{"component": "RequestsIssued", "memory_buffer_kb": 1000, "disk_size_kb": 10000}]`}}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, []DataCollectorPolicy{{Component: "RequestsIssued", MemoryBufferKB: 1000, DiskSizeKB: 10000}}, policies)
+
+	// the retention that is not set is not sent
+	intervalSeconds := int64(86400)
+	op, err = makeNMASetDataCollectorPolicyOp([]string{host}, "dbadmin", testDBName, "", []string{"RequestsIssued"},
+		nil, nil, &intervalSeconds, &password, true)
+	assert.NoError(t, err)
+	assert.True(t, op.getClassification().Idempotent)
+	requestData := map[string]any{}
+	assert.NoError(t, json.Unmarshal([]byte(op.hostRequestBody), &requestData))
+	assert.Equal(t, map[string]any{"username": "dbadmin", "password": password, "dbname": testDBName,
+		"components": []any{"RequestsIssued"}, "interval_seconds": float64(intervalSeconds)}, requestData)
+
+	op, err = makeNMAClearDataCollectorOp([]string{host}, "dbadmin", testDBName, "", nil, &password, true)
+	assert.NoError(t, err)
+	assert.True(t, op.getClassification().Destructive)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(host))
+	assert.Equal(t, "v1/data-collector/clear", op.clusterHTTPRequest.RequestCollection[host].Endpoint)
+}
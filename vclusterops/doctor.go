@@ -0,0 +1,477 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	DoctorFinding = vtypes.DoctorFinding
+	DoctorReport  = vtypes.DoctorReport
+)
+
+// the severities of the findings, from the most severe
+const (
+	DoctorSeverityCritical = vtypes.DoctorSeverityCritical
+	DoctorSeverityWarning  = vtypes.DoctorSeverityWarning
+	DoctorSeverityInfo     = vtypes.DoctorSeverityInfo
+)
+
+// the checks of the doctor command
+const (
+	DoctorCheckHealth       = "health"
+	DoctorCheckConnectivity = "connectivity"
+	DoctorCheckCertificates = "certificates"
+	DoctorCheckClock        = "clock"
+	DoctorCheckDiskSpace    = "disk_space"
+	DoctorCheckConfigDrift  = "config_drift"
+)
+
+// the codes of the findings of the doctor command
+const (
+	DoctorCodeDatabaseUnreachable = "DB_UNREACHABLE"
+	DoctorCodeNodeDown            = "NODE_DOWN"
+	DoctorCodeNMAUnreachable      = "NMA_UNREACHABLE"
+	DoctorCodeCertInvalid         = "CERT_INVALID"
+	DoctorCodeCertExpired         = "CERT_EXPIRED"
+	DoctorCodeCertExpiring        = "CERT_EXPIRING"
+	DoctorCodeClockNotSynced      = "CLOCK_NOT_SYNCED"
+	DoctorCodeTimeZoneMismatch    = "TIME_ZONE_MISMATCH"
+	DoctorCodeDiskUsageHigh       = "DISK_USAGE_HIGH"
+	DoctorCodeUnknownNode         = "CONFIG_UNKNOWN_NODE"
+	DoctorCodeStaleHost           = "CONFIG_STALE_HOST"
+)
+
+const (
+	DefaultDoctorCertExpiryDays      = 30
+	DefaultDoctorDiskWarningPercent  = 80
+	DefaultDoctorDiskCriticalPercent = 90
+)
+
+var doctorChecks = []string{DoctorCheckHealth, DoctorCheckConnectivity, DoctorCheckCertificates,
+	DoctorCheckClock, DoctorCheckDiskSpace, DoctorCheckConfigDrift}
+
+type VDoctorOptions struct {
+	// the hosts are usually the ones of the config file, so that the nodes of
+	// the running database can be compared with them
+	DatabaseOptions
+	// the checks not to run, see the DoctorCheck values
+	SkipChecks []string
+	// a certificate that expires within this number of days is reported
+	CertExpiryDays int
+	// the used percent of a storage location above which it is reported as a
+	// warning, and as a critical finding
+	DiskWarningPercent  int
+	DiskCriticalPercent int
+}
+
+func VDoctorOptionsFactory() VDoctorOptions {
+	options := VDoctorOptions{}
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VDoctorOptions) setDefaultValues() {
+	options.DatabaseOptions.setDefaultValues()
+	options.CertExpiryDays = DefaultDoctorCertExpiryDays
+	options.DiskWarningPercent = DefaultDoctorDiskWarningPercent
+	options.DiskCriticalPercent = DefaultDoctorDiskCriticalPercent
+}
+
+func (options *VDoctorOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(DoctorCmd, logger)
+	if err != nil {
+		return err
+	}
+	for _, check := range options.SkipChecks {
+		if !slices.Contains(doctorChecks, check) {
+			return fmt.Errorf("unknown doctor check %q, the checks are %s", check, strings.Join(doctorChecks, ", "))
+		}
+	}
+	if options.CertExpiryDays < 0 {
+		return fmt.Errorf("the days before a certificate expires cannot be negative, got %d", options.CertExpiryDays)
+	}
+	if options.DiskWarningPercent <= 0 || options.DiskWarningPercent > options.DiskCriticalPercent ||
+		options.DiskCriticalPercent > 100 {
+		return fmt.Errorf("the disk usage thresholds must be such that 0 < warning (%d) <= critical (%d) <= 100",
+			options.DiskWarningPercent, options.DiskCriticalPercent)
+	}
+	return nil
+}
+
+func (options *VDoctorOptions) analyzeOptions() (err error) {
+	// resolve RawHosts to be IP addresses
+	if len(options.RawHosts) > 0 {
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VDoctorOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VDoctor runs the health, connectivity, certificate, clock, disk space, and
+// config drift checks of a database, and returns their findings, the most
+// severe first. A check that cannot run, e.g., because the database is down,
+// is reported in the skipped checks of the report instead of failing the
+// command. Only invalid options make it fail.
+func (vcc VClusterCommands) VDoctor(options *VDoctorOptions) (*DoctorReport, error) {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	doctor := &clusterDoctor{vcc: vcc, options: options,
+		report: &DoctorReport{CheckedAt: time.Now().UTC(), SkippedChecks: make(map[string]string)}}
+	doctor.run()
+	sortDoctorFindings(doctor.report.Findings)
+	return doctor.report, nil
+}
+
+// clusterDoctor runs the checks of VDoctor, the later checks reusing what the
+// earlier ones retrieved
+type clusterDoctor struct {
+	vcc     VClusterCommands
+	options *VDoctorOptions
+	report  *DoctorReport
+	// the running database, nil if it is unreachable
+	vdb *VCoordinationDatabase
+	// the hosts whose NMA responds
+	nmaHosts []string
+}
+
+func (d *clusterDoctor) run() {
+	d.runCheck(DoctorCheckHealth, d.checkHealth)
+	d.runCheck(DoctorCheckConnectivity, d.checkConnectivity)
+	d.runCheck(DoctorCheckCertificates, d.checkCertificates)
+	d.runCheck(DoctorCheckClock, d.checkClock)
+	d.runCheck(DoctorCheckDiskSpace, d.checkDiskSpace)
+	d.runCheck(DoctorCheckConfigDrift, d.checkConfigDrift)
+}
+
+func (d *clusterDoctor) runCheck(check string, run func() error) {
+	if slices.Contains(d.options.SkipChecks, check) {
+		d.report.SkippedChecks[check] = "skipped by the options"
+		return
+	}
+	d.vcc.Log.Info("running doctor check", "check", check)
+	if err := run(); err != nil {
+		d.vcc.Log.Info("doctor check could not run", "check", check, "error", err.Error())
+		d.report.SkippedChecks[check] = err.Error()
+	}
+}
+
+func (d *clusterDoctor) addFinding(finding DoctorFinding) {
+	sort.Strings(finding.Hosts)
+	d.report.Findings = append(d.report.Findings, finding)
+}
+
+// getRunningVDB returns the running database, retrieving it on first use
+func (d *clusterDoctor) getRunningVDB() (*VCoordinationDatabase, error) {
+	if d.vdb != nil {
+		return d.vdb, nil
+	}
+	vdb := makeVCoordinationDatabase()
+	err := d.vcc.getVDBFromMainRunningDBContainsSandbox(&vdb, &d.options.DatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+	d.vdb = &vdb
+	return d.vdb, nil
+}
+
+func (d *clusterDoctor) checkHealth() error {
+	vdb, err := d.getRunningVDB()
+	if err != nil {
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeDatabaseUnreachable,
+			Severity:    DoctorSeverityCritical,
+			Check:       DoctorCheckHealth,
+			Hosts:       slices.Clone(d.options.Hosts),
+			Message:     fmt.Sprintf("database %s is not reachable through HTTPS on any host: %s", d.options.DBName, err),
+			Remediation: fmt.Sprintf("vcluster start_db --db-name %s", d.options.DBName),
+		})
+		return nil
+	}
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.State != util.NodeDownState {
+			continue
+		}
+		severity := DoctorSeverityWarning
+		if vnode.IsPrimary {
+			severity = DoctorSeverityCritical
+		}
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeNodeDown,
+			Severity:    severity,
+			Check:       DoctorCheckHealth,
+			Hosts:       []string{host},
+			Message:     fmt.Sprintf("node %s of subcluster %s is down", vnode.Name, vnode.Subcluster),
+			Remediation: fmt.Sprintf("vcluster start_node --db-name %s --start-hosts %s", d.options.DBName, host),
+		})
+	}
+	return nil
+}
+
+func (d *clusterDoctor) checkConnectivity() error {
+	vdb := makeVCoordinationDatabase()
+	getHealthyNodesOp := makeNMAGetHealthyNodesOp(d.options.Hosts, &vdb)
+	err := d.options.runClusterOpEngine(d.vcc.Log, []clusterOp{&getHealthyNodesOp})
+	if err != nil {
+		return err
+	}
+	d.nmaHosts = vdb.HostList
+	var unreachable []string
+	for _, host := range d.options.Hosts {
+		if !slices.Contains(d.nmaHosts, host) {
+			unreachable = append(unreachable, host)
+		}
+	}
+	if len(unreachable) > 0 {
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeNMAUnreachable,
+			Severity:    DoctorSeverityCritical,
+			Check:       DoctorCheckConnectivity,
+			Hosts:       unreachable,
+			Message:     fmt.Sprintf("the node management agent does not respond on %d host(s)", len(unreachable)),
+			Remediation: "restart the node management agent on the hosts, and check that its port is open",
+		})
+	}
+	return nil
+}
+
+func (d *clusterDoctor) checkCertificates() error {
+	if !d.options.hasCerts() {
+		return fmt.Errorf("no TLS certificates are configured")
+	}
+	now := time.Now()
+	certs := []struct{ name, data string }{{"client certificate", d.options.Cert}, {"CA certificate", d.options.CaCert}}
+	for _, cert := range certs {
+		if cert.data != "" {
+			d.checkCertificate(cert.name, cert.data, now)
+		}
+	}
+	return nil
+}
+
+func (d *clusterDoctor) checkCertificate(name, data string, now time.Time) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		d.addFinding(DoctorFinding{Code: DoctorCodeCertInvalid, Severity: DoctorSeverityWarning,
+			Check: DoctorCheckCertificates, Message: fmt.Sprintf("the %s is not in PEM format", name)})
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		d.addFinding(DoctorFinding{Code: DoctorCodeCertInvalid, Severity: DoctorSeverityWarning,
+			Check: DoctorCheckCertificates, Message: fmt.Sprintf("the %s cannot be parsed: %s", name, err)})
+		return
+	}
+	expiry := cert.NotAfter.UTC().Format(time.RFC3339)
+	switch {
+	case now.After(cert.NotAfter):
+		d.addFinding(DoctorFinding{Code: DoctorCodeCertExpired, Severity: DoctorSeverityCritical,
+			Check:       DoctorCheckCertificates,
+			Message:     fmt.Sprintf("the %s %s expired at %s", name, cert.Subject.CommonName, expiry),
+			Remediation: "renew the certificate, and reload it with the certificate reloader or restart vcluster"})
+	case now.Add(time.Duration(d.options.CertExpiryDays) * 24 * time.Hour).After(cert.NotAfter):
+		d.addFinding(DoctorFinding{Code: DoctorCodeCertExpiring, Severity: DoctorSeverityWarning,
+			Check:       DoctorCheckCertificates,
+			Message:     fmt.Sprintf("the %s %s expires at %s", name, cert.Subject.CommonName, expiry),
+			Remediation: "renew the certificate before it expires"})
+	}
+}
+
+func (d *clusterDoctor) checkClock() error {
+	hosts := d.nmaHosts
+	if slices.Contains(d.options.SkipChecks, DoctorCheckConnectivity) {
+		hosts = d.options.Hosts
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("the node management agent does not respond on any host")
+	}
+	hwOptions := VGetHardwareInventoryOptionsFactory()
+	hwOptions.DatabaseOptions = d.options.DatabaseOptions
+	hwOptions.Hosts = hosts
+	hwOptions.RawHosts = hosts
+	hwOptions.HostSelector = ""
+	inventory, err := d.vcc.VGetHardwareInventory(&hwOptions)
+	if err != nil {
+		return err
+	}
+
+	var notSynced []string
+	for i := range inventory.Hosts {
+		if inventory.Hosts[i].Time.NTPSource == "" {
+			notSynced = append(notSynced, inventory.Hosts[i].Host)
+		}
+	}
+	if len(notSynced) > 0 {
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeClockNotSynced,
+			Severity:    DoctorSeverityWarning,
+			Check:       DoctorCheckClock,
+			Hosts:       notSynced,
+			Message:     "the clock of the hosts is not synchronized with an NTP source, so it can skew",
+			Remediation: "systemctl enable --now chronyd",
+		})
+	}
+	for _, difference := range inventory.Differences {
+		if difference.Property != vtypes.HardwareTimeZone {
+			continue
+		}
+		var hosts []string
+		for host := range difference.Values {
+			hosts = append(hosts, host)
+		}
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeTimeZoneMismatch,
+			Severity:    DoctorSeverityInfo,
+			Check:       DoctorCheckClock,
+			Hosts:       hosts,
+			Message:     "the hosts are not in the same time zone, which makes their logs harder to correlate",
+			Remediation: "timedatectl set-timezone <time zone>",
+		})
+	}
+	return nil
+}
+
+func (d *clusterDoctor) checkDiskSpace() error {
+	vdb, err := d.getRunningVDB()
+	if err != nil {
+		return fmt.Errorf("the database is not reachable")
+	}
+	var upHosts []string
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.State == util.NodeUpState {
+			upHosts = append(upHosts, host)
+		}
+	}
+	if len(upHosts) == 0 {
+		return fmt.Errorf("no node of the database is up")
+	}
+
+	detailsOptions := VFetchNodesDetailsOptionsFactory()
+	detailsOptions.DatabaseOptions = d.options.DatabaseOptions
+	detailsOptions.Hosts = upHosts
+	detailsOptions.RawHosts = upHosts
+	details, err := d.vcc.VFetchNodesDetails(&detailsOptions)
+	if err != nil {
+		return err
+	}
+	for i := range details {
+		for _, location := range details[i].StorageLocList {
+			percent, err := strconv.Atoi(strings.TrimSuffix(location.DiskPercent, "%"))
+			if err != nil || percent < d.options.DiskWarningPercent {
+				continue
+			}
+			severity := DoctorSeverityWarning
+			if percent >= d.options.DiskCriticalPercent {
+				severity = DoctorSeverityCritical
+			}
+			d.addFinding(DoctorFinding{
+				Code:     DoctorCodeDiskUsageHigh,
+				Severity: severity,
+				Check:    DoctorCheckDiskSpace,
+				Hosts:    []string{details[i].Address},
+				Message: fmt.Sprintf("storage location %s of node %s is %d%% full",
+					location.Path, details[i].Name, percent),
+				Remediation: fmt.Sprintf("free up space in %s, or add a storage location on another disk", location.Path),
+			})
+		}
+	}
+	return nil
+}
+
+// checkConfigDrift compares the hosts of the options, usually read from the
+// config file, with the nodes of the running database
+func (d *clusterDoctor) checkConfigDrift() error {
+	vdb, err := d.getRunningVDB()
+	if err != nil {
+		return fmt.Errorf("the database is not reachable")
+	}
+	var unknown, stale []string
+	for host := range vdb.HostNodeMap {
+		if !slices.Contains(d.options.Hosts, host) {
+			unknown = append(unknown, host)
+		}
+	}
+	for _, host := range d.options.Hosts {
+		if _, ok := vdb.HostNodeMap[host]; !ok {
+			stale = append(stale, host)
+		}
+	}
+	remediation := fmt.Sprintf("vcluster manage_config recover --db-name %s --hosts %s --catalog-path %s",
+		d.options.DBName, strings.Join(vdb.HostList, ","), d.options.CatalogPrefix)
+	if len(unknown) > 0 {
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeUnknownNode,
+			Severity:    DoctorSeverityWarning,
+			Check:       DoctorCheckConfigDrift,
+			Hosts:       unknown,
+			Message:     "the database has nodes that are not among the configured hosts",
+			Remediation: remediation,
+		})
+	}
+	if len(stale) > 0 {
+		d.addFinding(DoctorFinding{
+			Code:        DoctorCodeStaleHost,
+			Severity:    DoctorSeverityWarning,
+			Check:       DoctorCheckConfigDrift,
+			Hosts:       stale,
+			Message:     "the configured hosts include hosts that are not nodes of the database",
+			Remediation: remediation,
+		})
+	}
+	return nil
+}
+
+// sortDoctorFindings sorts the findings by severity, check, code, and hosts
+func sortDoctorFindings(findings []DoctorFinding) {
+	rank := map[string]int{DoctorSeverityCritical: 0, DoctorSeverityWarning: 1, DoctorSeverityInfo: 2}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := &findings[i], &findings[j]
+		if a.Severity != b.Severity {
+			return rank[a.Severity] < rank[b.Severity]
+		}
+		if a.Check != b.Check {
+			return slices.Index(doctorChecks, a.Check) < slices.Index(doctorChecks, b.Check)
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return strings.Join(a.Hosts, ",") < strings.Join(b.Hosts, ",")
+	})
+}
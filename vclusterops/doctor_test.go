@@ -0,0 +1,95 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func makeDoctorCert(t *testing.T, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "dbadmin"},
+		NotBefore: notAfter.Add(-365 * 24 * time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestDoctorOptions(t *testing.T) {
+	options := VDoctorOptionsFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	assert.NoError(t, options.validateAnalyzeOptions(vlog.Printer{}))
+
+	options.SkipChecks = []string{"bogus"}
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), `unknown doctor check "bogus"`)
+	options.SkipChecks = []string{DoctorCheckClock}
+
+	options.DiskWarningPercent = 95
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), "disk usage thresholds")
+}
+
+func TestDoctorChecks(t *testing.T) {
+	options := VDoctorOptionsFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.104"}
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = map[string]*VCoordinationNode{
+		"192.168.1.101": {Name: "v_test_db_node0001", State: "UP", IsPrimary: true},
+		"192.168.1.102": {Name: "v_test_db_node0002", State: "DOWN", IsPrimary: true},
+		"192.168.1.103": {Name: "v_test_db_node0003", State: "DOWN"},
+	}
+	vdb.HostList = []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+	doctor := &clusterDoctor{options: &options, vdb: &vdb, report: &DoctorReport{SkippedChecks: map[string]string{}}}
+
+	// down nodes, the primary ones being critical
+	assert.NoError(t, doctor.checkHealth())
+	// the nodes of the database and the configured hosts differ
+	assert.NoError(t, doctor.checkConfigDrift())
+	// certificates that expired or expire soon
+	now := time.Now()
+	doctor.checkCertificate("client certificate", makeDoctorCert(t, now.Add(-time.Hour)), now)
+	doctor.checkCertificate("CA certificate", makeDoctorCert(t, now.Add(24*time.Hour)), now)
+	doctor.checkCertificate("CA certificate", makeDoctorCert(t, now.Add(365*24*time.Hour)), now)
+	doctor.checkCertificate("CA certificate", "not a certificate", now)
+
+	sortDoctorFindings(doctor.report.Findings)
+	var codes []string
+	for _, finding := range doctor.report.Findings {
+		codes = append(codes, finding.Code)
+	}
+	assert.Equal(t, []string{DoctorCodeNodeDown, DoctorCodeCertExpired, DoctorCodeNodeDown, DoctorCodeCertExpiring,
+		DoctorCodeCertInvalid, DoctorCodeStaleHost, DoctorCodeUnknownNode}, codes)
+	assert.Equal(t, []string{"192.168.1.102"}, doctor.report.Findings[0].Hosts)
+	assert.Equal(t, "vcluster start_node --db-name test_db --start-hosts 192.168.1.102",
+		doctor.report.Findings[0].Remediation)
+	assert.Equal(t, []string{"192.168.1.104"}, doctor.report.Findings[5].Hosts)
+	assert.Equal(t, []string{"192.168.1.103"}, doctor.report.Findings[6].Hosts)
+	assert.True(t, doctor.report.HasCritical())
+}
@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// the number of times an interrupted download is resumed before giving up
+	maxDownloadResumes = 3
+	sha256DigestPrefix = "sha-256="
+)
+
+// downloadResumer requests the rest of a file from an offset, with a Range header
+type downloadResumer func(offset int64) (*http.Response, error)
+
+// downloadCheck holds what the server reported about a downloaded file, which
+// the file is verified against once it is complete
+type downloadCheck struct {
+	size int64
+	// optional, the SHA-256 digest of the file from the Digest header
+	digest []byte
+	hasher hash.Hash
+}
+
+func makeDownloadCheck(resp *http.Response) (check downloadCheck, err error) {
+	check.size = resp.ContentLength
+	for _, digest := range strings.Split(resp.Header.Get("Digest"), ",") {
+		encoded, found := strings.CutPrefix(strings.TrimSpace(digest), sha256DigestPrefix)
+		if !found {
+			continue
+		}
+		check.digest, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return check, fmt.Errorf("invalid SHA-256 digest %q: %w", encoded, err)
+		}
+		check.hasher = sha256.New()
+	}
+	return check, nil
+}
+
+// writer returns the writer that the downloaded bytes are written to, in order
+func (check *downloadCheck) writer(file io.Writer) io.Writer {
+	if check.hasher == nil {
+		return file
+	}
+	return io.MultiWriter(file, check.hasher)
+}
+
+func (check *downloadCheck) verify(bytesWritten int64) error {
+	if check.size >= 0 && bytesWritten != check.size {
+		return fmt.Errorf("downloaded %d bytes, but the server reported %d bytes", bytesWritten, check.size)
+	}
+	if check.hasher != nil && !bytes.Equal(check.hasher.Sum(nil), check.digest) {
+		return fmt.Errorf("the SHA-256 digest of the downloaded file does not match the digest reported by the server")
+	}
+	return nil
+}
+
+// isResumable returns whether an interrupted download of a response can be
+// resumed, which requires that the server accepts byte ranges and reports the
+// size of the file
+func isResumable(resp *http.Response) bool {
+	return resp.Header.Get("Accept-Ranges") == "bytes" && resp.ContentLength > 0
+}
+
+// resumeDownload requests the rest of the file from offset and appends it to w.
+// The server must answer with the expected range of the file, so that a file
+// that changed in the meantime is not pieced together.
+func (downloader *responseBodyDownloader) resumeDownload(w io.Writer, offset, size int64) (bytesWritten int64, err error) {
+	resp, err := downloader.resume(offset)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("the server did not resume the download, status code %d", resp.StatusCode)
+	}
+	expectedRange := fmt.Sprintf("bytes %d-%d/%d", offset, size-1, size)
+	if contentRange := resp.Header.Get("Content-Range"); contentRange != expectedRange {
+		return 0, fmt.Errorf("the server resumed the download with range %q rather than %q", contentRange, expectedRange)
+	}
+
+	counter := &byteCounter{reader: resp.Body}
+	bytesWritten, err = io.Copy(w, counter)
+	downloader.resumedBytes += counter.count
+	return bytesWritten, err
+}
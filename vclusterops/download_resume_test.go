@@ -0,0 +1,106 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+const resumeTestContent = "the system tables of a large cluster"
+
+// makeTruncatedResponse returns a response of the whole test content whose
+// body stops after the first n bytes
+func makeTruncatedResponse(n int, digest string) *http.Response {
+	resp := &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{},
+		ContentLength: int64(len(resumeTestContent)),
+		Body: io.NopCloser(io.MultiReader(strings.NewReader(resumeTestContent[:n]),
+			iotest.ErrReader(io.ErrUnexpectedEOF))),
+	}
+	resp.Header.Set("Accept-Ranges", "bytes")
+	if digest != "" {
+		resp.Header.Set("Digest", sha256DigestPrefix+digest)
+	}
+	return resp
+}
+
+func makeRangeResponse(offset int64) *http.Response {
+	size := len(resumeTestContent)
+	resp := &http.Response{
+		StatusCode: http.StatusPartialContent,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(resumeTestContent[offset:])),
+	}
+	resp.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, size-1, size))
+	return resp
+}
+
+func TestResumeDownload(t *testing.T) {
+	sum := sha256.Sum256([]byte(resumeTestContent))
+	digest := base64.StdEncoding.EncodeToString(sum[:])
+	destFilePath := filepath.Join(t.TempDir(), "batch.tgz")
+	downloader := responseBodyDownloader{logger: vlog.Printer{}, destFilePath: destFilePath}
+	var offsets []int64
+	downloader.resume = func(offset int64) (*http.Response, error) {
+		offsets = append(offsets, offset)
+		return makeRangeResponse(offset), nil
+	}
+
+	bytesWritten, err := downloader.downloadFile(makeTruncatedResponse(10, digest))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(resumeTestContent)), bytesWritten)
+	assert.Equal(t, []int64{10}, offsets)
+	assert.Equal(t, int64(len(resumeTestContent)-10), downloader.resumedBytes)
+	assert.Equal(t, 1, downloader.retries.Attempts)
+	assert.Equal(t, []string{io.ErrUnexpectedEOF.Error()}, downloader.retries.Reasons)
+	content, err := os.ReadFile(destFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, resumeTestContent, string(content))
+
+	// negative: the digest of the file does not match
+	_, err = downloader.downloadFile(makeTruncatedResponse(10, base64.StdEncoding.EncodeToString([]byte("wrong"))))
+	assert.ErrorContains(t, err, "does not match the digest reported by the server")
+
+	// negative: the server sends the whole file again
+	downloader.resume = func(offset int64) (*http.Response, error) {
+		resp := makeRangeResponse(0)
+		resp.StatusCode = http.StatusOK
+		return resp, nil
+	}
+	_, err = downloader.downloadFile(makeTruncatedResponse(10, ""))
+	assert.ErrorContains(t, err, "the server did not resume the download")
+	assert.Equal(t, maxDownloadResumes, downloader.retries.Attempts)
+
+	// negative: the server does not accept byte ranges
+	resp := makeTruncatedResponse(10, "")
+	resp.Header.Del("Accept-Ranges")
+	_, err = downloader.downloadFile(resp)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Zero(t, downloader.retries.Attempts)
+}
@@ -0,0 +1,67 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// EndpointPolicy restricts the NMA and HTTPS endpoints that the op engine may
+// call, e.g., in environments where only approved endpoints can be used. The
+// endpoints are paths without the API version, e.g., "nodes" or
+// "catalog/bootstrap". An entry that ends with * allows every path that starts
+// with the rest of it, e.g., "users/*". A service without allowed endpoints
+// is not restricted.
+type EndpointPolicy struct {
+	AllowedNMAEndpoints   []string
+	AllowedHTTPSEndpoints []string
+}
+
+type EndpointNotAllowedError = vtypes.EndpointNotAllowedError
+
+// checkRequest returns an EndpointNotAllowedError if the policy does not
+// allow the endpoint of a request
+func (policy *EndpointPolicy) checkRequest(host string, request *hostHTTPRequest) error {
+	service, version, allowedEndpoints := "HTTPS", HTTPCurVersion, policy.AllowedHTTPSEndpoints
+	if request.IsNMACommand {
+		service, version, allowedEndpoints = "NMA", NMACurVersion, policy.AllowedNMAEndpoints
+	}
+	if len(allowedEndpoints) == 0 {
+		return nil
+	}
+	endpoint := strings.TrimPrefix(request.Endpoint, version)
+	for _, allowed := range allowedEndpoints {
+		prefix, isPrefix := strings.CutSuffix(allowed, "*")
+		if endpoint == allowed || (isPrefix && strings.HasPrefix(endpoint, prefix)) {
+			return nil
+		}
+	}
+	return &EndpointNotAllowedError{Host: host, Service: service, Endpoint: endpoint}
+}
+
+// checkRequests checks the requests of an op against the policy, so that no
+// request is sent if any of them is not allowed
+func (policy *EndpointPolicy) checkRequests(httpRequest *clusterHTTPRequest) error {
+	for host := range httpRequest.RequestCollection {
+		request := httpRequest.RequestCollection[host]
+		if err := policy.checkRequest(host, &request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestEndpointPolicy(t *testing.T) {
+	policy := EndpointPolicy{
+		AllowedNMAEndpoints: []string{"health", "catalog/*"},
+	}
+	request := hostHTTPRequest{}
+
+	request.buildNMAEndpoint("health")
+	assert.NoError(t, policy.checkRequest("192.168.1.101", &request))
+	request.buildNMAEndpoint("catalog/bootstrap")
+	assert.NoError(t, policy.checkRequest("192.168.1.101", &request))
+
+	// the HTTPS service is not restricted
+	request.buildHTTPSEndpoint("nodes")
+	assert.NoError(t, policy.checkRequest("192.168.1.101", &request))
+
+	// negative: the endpoint is not in the allowlist
+	request.buildNMAEndpoint("files/upload")
+	err := policy.checkRequest("192.168.1.101", &request)
+	policyErr := &EndpointNotAllowedError{}
+	assert.True(t, errors.As(err, &policyErr))
+	assert.Equal(t, "NMA", policyErr.Service)
+	assert.Equal(t, "files/upload", policyErr.Endpoint)
+	assert.EqualError(t, err, "endpoint files/upload of the NMA service on host 192.168.1.101 is not allowed by the endpoint policy")
+
+	// negative: a prefix entry does not allow its parent path
+	policy.AllowedHTTPSEndpoints = []string{"users/*"}
+	request.buildHTTPSEndpoint("users")
+	assert.Error(t, policy.checkRequest("192.168.1.101", &request))
+}
+
+func TestEndpointPolicyBeforeSending(t *testing.T) {
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher.endpointPolicy = &EndpointPolicy{AllowedHTTPSEndpoints: []string{"nodes"}}
+	dispatcher.setup([]string{"192.168.1.101", "192.168.1.102"})
+
+	allowed, denied := hostHTTPRequest{}, hostHTTPRequest{}
+	allowed.buildHTTPSEndpoint("nodes")
+	denied.buildHTTPSEndpoint("cluster/shutdown")
+	httpRequest := clusterHTTPRequest{
+		RequestCollection: map[string]hostHTTPRequest{"192.168.1.101": allowed, "192.168.1.102": denied},
+	}
+
+	// no request is sent when one of them is not allowed
+	err := dispatcher.sendRequest(&httpRequest, nil)
+	assert.ErrorContains(t, err, "endpoint cluster/shutdown of the HTTPS service on host 192.168.1.102")
+	assert.Empty(t, httpRequest.ResultCollection)
+}
@@ -0,0 +1,184 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// planState is the state of the cluster that a command read before its first
+// op that changes the cluster, which the plan of the command depends on
+type planState struct {
+	UpHosts            []string          `json:"up_hosts"`
+	Nodes              []NodeInfo        `json:"nodes"`
+	SubclusterNodes    []NodeInfo        `json:"subcluster_nodes"`
+	DefaultSubcluster  string            `json:"default_subcluster"`
+	UpHostSubclusters  map[string]string `json:"up_host_subclusters"`
+	UpHostSandboxes    map[string]string `json:"up_host_sandboxes"`
+	LatestCatalogHosts []string          `json:"latest_catalog_hosts"`
+	Catalog            nmaVDatabase      `json:"catalog"`
+	NetworkAddresses   map[string]string `json:"network_addresses"`
+	RestorePoints      []RestorePoint    `json:"restore_points"`
+	UnreachableHosts   []string          `json:"unreachable_hosts"`
+}
+
+// stateFingerprint returns the SHA-256 of the state of the cluster read by
+// the ops run so far. The lists of hosts and nodes are sorted, as the order in
+// which the hosts respond changes between runs.
+func (execContext *opEngineExecContext) stateFingerprint() string {
+	state := planState{
+		UpHosts:            sortedCopy(execContext.upHosts),
+		Nodes:              sortedNodes(execContext.nodesInfo),
+		SubclusterNodes:    sortedNodes(execContext.scNodesInfo),
+		DefaultSubcluster:  execContext.defaultSCName,
+		UpHostSubclusters:  execContext.upScInfo,
+		UpHostSandboxes:    execContext.upHostsToSandboxes,
+		LatestCatalogHosts: sortedCopy(execContext.hostsWithLatestCatalog),
+		Catalog:            execContext.nmaVDatabase,
+		RestorePoints:      execContext.restorePoints,
+		UnreachableHosts:   sortedCopy(execContext.unreachableHosts),
+	}
+	if len(execContext.networkProfiles) > 0 {
+		state.NetworkAddresses = make(map[string]string, len(execContext.networkProfiles))
+		for host := range execContext.networkProfiles {
+			state.NetworkAddresses[host] = execContext.networkProfiles[host].Address
+		}
+	}
+	// the state only holds plain values, it always marshals
+	data, _ := json.Marshal(&state)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedCopy(hosts []string) []string {
+	sorted := slices.Clone(hosts)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func sortedNodes(nodes []NodeInfo) []NodeInfo {
+	sorted := slices.Clone(nodes)
+	slices.SortFunc(sorted, func(a, b NodeInfo) int { return strings.Compare(a.Name, b.Name) })
+	return sorted
+}
+
+// checkApprovedPlan checks the approved plan, if any, before the first op
+// that changes the cluster. The ops from that one on, and the state that the
+// command read so far, must be the ones of the plan.
+func (opEngine *VClusterOpEngine) checkApprovedPlan(logger vlog.Printer, execContext *opEngineExecContext) error {
+	planOptions, ok := opEngine.tlsOptions.(opPlanOptions)
+	if !ok {
+		return nil
+	}
+	plan := planOptions.getApprovedPlan()
+	if plan == nil {
+		return nil
+	}
+	planOptions.setPlanChecked()
+
+	diverged := &PlanDivergedError{Command: plan.Command}
+	ops := getPlannedOps(opEngine.instructions[opEngine.current:])
+	switch {
+	case plan.Command != opEngine.getCommandName():
+		diverged.Reason = fmt.Sprintf("the plan is of command %s, not of %s", plan.Command, opEngine.getCommandName())
+	case !slices.Equal(plan.Ops, ops):
+		diverged.Reason = fmt.Sprintf("the command would run %s, the plan has %s",
+			plannedOpNames(ops), plannedOpNames(plan.Ops))
+	case plan.StateFingerprint != execContext.stateFingerprint():
+		diverged.Reason = "the state of the cluster changed since the plan was computed"
+	default:
+		logger.PrintInfo("The ops and the state of the cluster match the approved plan of %s", plan.Command)
+		return nil
+	}
+	return diverged
+}
+
+func plannedOpNames(ops []PlannedOp) string {
+	names := make([]string, len(ops))
+	for i := range ops {
+		names[i] = ops[i].Name
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+func (opEngine *VClusterOpEngine) getCommandName() string {
+	if logOptions, ok := opEngine.tlsOptions.(opCommandLogOptions); ok {
+		return logOptions.getCommandName()
+	}
+	return ""
+}
+
+// VPlan computes the plan of a command for it to be approved before it is
+// executed with VExecutePlan, and signs it with key. run runs the command with
+// options, which are the database options of the command, in read-only mode:
+// the command reads the state of the cluster, and stops at its first op that
+// changes the cluster. The plan is empty for a command that does not change
+// the cluster.
+//
+// The plan holds the ops of the op engine that the command was stopped in. A
+// command that runs several op engines, e.g., one per step, is only checked
+// against the plan up to the end of that engine: the engines that it runs
+// after that one are not part of the plan, and are not checked.
+func (vcc VClusterCommands) VPlan(options *DatabaseOptions, key []byte, run func() error) (*ExecutionPlan, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("a key is required to sign the plan")
+	}
+	readOnly := options.ReadOnly
+	options.ReadOnly = true
+	defer func() { options.ReadOnly = readOnly }()
+
+	err := run()
+	readOnlyErr := &ReadOnlyModeError{Command: options.commandName}
+	if err != nil && !errors.As(err, &readOnlyErr) {
+		return nil, fmt.Errorf("fail to compute the plan: %w", err)
+	}
+	plan := readOnlyErr.ExecutionPlan()
+	if err := plan.Sign(key); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// VExecutePlan runs a command whose plan was computed by VPlan and approved.
+// run runs the command with options, and key is the one the plan was signed
+// with, as for VPlan. The command fails with a PlanDivergedError, before it
+// changes the cluster, if the plan is not signed with key or was edited, or
+// if the ops of the command or the state of the cluster it reads differ from
+// the ones of the plan. As for VPlan, only the op engine of the command that
+// first changes the cluster is checked against the plan.
+func (vcc VClusterCommands) VExecutePlan(options *DatabaseOptions, plan *ExecutionPlan, key []byte, run func() error) error {
+	if err := plan.Verify(key); err != nil {
+		return &PlanDivergedError{Command: plan.Command, Reason: err.Error()}
+	}
+	options.ApprovedPlan = plan
+	options.planChecked = false
+	defer func() { options.ApprovedPlan = nil }()
+
+	err := run()
+	if err == nil && !options.planChecked && len(plan.Ops) > 0 {
+		// the ops of the plan were expected to change the cluster
+		vcc.Log.PrintWarning("The command %s did not run the ops of its approved plan", plan.Command)
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// stateOp is a mockOp that reads the up hosts of the cluster
+type stateOp struct {
+	mockOp
+	upHosts []string
+}
+
+func (m *stateOp) execute(execContext *opEngineExecContext) error {
+	execContext.upHosts = m.upHosts
+	return m.mockOp.execute(execContext)
+}
+
+func TestExecutionPlan(t *testing.T) {
+	readOp := stateOp{mockOp: makeMockOp(false), upHosts: []string{"host2", "host1"}}
+	readOp.name = "read-op"
+	readOp.method = GetMethod
+	writeOp := makeMockOp(false)
+	writeOp.name = "write-op"
+	writeOp.method = PostMethod
+	options := &DatabaseOptions{commandName: "stop_db"}
+	run := func() error {
+		opEngn := makeClusterOpEngine([]clusterOp{&readOp, &writeOp}, options)
+		return opEngn.run(vlog.Printer{})
+	}
+	vcc := VClusterCommands{}
+
+	// the plan is computed in read-only mode
+	key := []byte("approval-key")
+	_, err := vcc.VPlan(options, nil, run)
+	assert.ErrorContains(t, err, "a key is required")
+	plan, err := vcc.VPlan(options, key, run)
+	assert.NoError(t, err)
+	assert.Equal(t, "stop_db", plan.Command)
+	assert.Equal(t, []PlannedOp{{Name: "write-op", Classification: mutatingClassification}}, plan.Ops)
+	assert.NoError(t, plan.Verify(key))
+	assert.Error(t, plan.Verify([]byte("other-key")))
+	assert.False(t, writeOp.calledExecute)
+	assert.False(t, options.ReadOnly)
+
+	// the plan is exported as JSON, and executed once approved
+	data, err := json.Marshal(plan)
+	assert.NoError(t, err)
+	approved := &ExecutionPlan{}
+	assert.NoError(t, json.Unmarshal(data, approved))
+	assert.NoError(t, vcc.VExecutePlan(options, approved, key, run))
+	assert.True(t, writeOp.calledExecute)
+	assert.Nil(t, options.ApprovedPlan)
+
+	// the hosts responding in another order is the same state
+	readOp.upHosts = []string{"host1", "host2"}
+	assert.NoError(t, vcc.VExecutePlan(options, approved, key, run))
+
+	// negative: the state of the cluster changed since the plan was computed
+	writeOp.calledExecute = false
+	readOp.upHosts = []string{"host1"}
+	err = vcc.VExecutePlan(options, approved, key, run)
+	divergedErr := &PlanDivergedError{}
+	assert.ErrorAs(t, err, &divergedErr)
+	assert.Contains(t, divergedErr.Reason, "state of the cluster changed")
+	assert.False(t, writeOp.calledExecute)
+
+	// negative: the command would run other ops
+	readOp.upHosts = []string{"host1", "host2"}
+	writeOp.name = "other-write-op"
+	err = vcc.VExecutePlan(options, approved, key, run)
+	assert.ErrorAs(t, err, &divergedErr)
+	assert.Contains(t, divergedErr.Reason, "other-write-op")
+	assert.False(t, writeOp.calledExecute)
+
+	// negative: the plan was edited after its approval
+	writeOp.name = "write-op"
+	approved.Ops[0].Classification.Destructive = true
+	err = vcc.VExecutePlan(options, approved, key, run)
+	assert.ErrorAs(t, err, &divergedErr)
+	assert.Contains(t, divergedErr.Reason, "does not match")
+	assert.False(t, writeOp.calledExecute)
+
+	// negative: the plan was edited and signed again without the key
+	approved.Signature = ""
+	unsigned, err := json.Marshal(approved)
+	assert.NoError(t, err)
+	sum := sha256.Sum256(unsigned)
+	approved.Signature = hex.EncodeToString(sum[:])
+	err = vcc.VExecutePlan(options, approved, key, run)
+	assert.ErrorAs(t, err, &divergedErr)
+	assert.Contains(t, divergedErr.Reason, "does not match")
+	assert.False(t, writeOp.calledExecute)
+
+	// negative: no key to verify the plan with
+	err = vcc.VExecutePlan(options, approved, nil, run)
+	assert.ErrorAs(t, err, &divergedErr)
+	assert.Contains(t, divergedErr.Reason, "a key is required")
+	assert.False(t, writeOp.calledExecute)
+}
@@ -34,6 +34,11 @@ func (options *VFetchNodeStateOptions) validateParseOptions(vcc VClusterCommands
 		return fmt.Errorf("must specify a host or host list")
 	}
 
+	err := options.validateMonitoringMode(FetchNodeStateCmd)
+	if err != nil {
+		return err
+	}
+
 	if options.Password == nil {
 		vcc.Log.PrintInfo("no password specified, using none")
 	}
@@ -81,6 +86,10 @@ func (vcc VClusterCommands) VFetchNodeState(options *VFetchNodeStateOptions) ([]
 	} else {
 		err = vcc.getVDBFromMainRunningDBContainsSandbox(&vdb, &options.DatabaseOptions)
 	}
+	if err == nil && options.MonitoringOnly && len(vdb.HostNodeMap) == 0 {
+		// the user is denied access to the nodes of the running database
+		return vcc.fetchNodeStateWithoutPrivileges(options)
+	}
 	if err != nil {
 		vcc.Log.PrintInfo("Error from vdb build: %s", err.Error())
 
@@ -156,6 +165,31 @@ func (vcc VClusterCommands) fetchNodeStateFromDownDB(options *VFetchNodeStateOpt
 	return nodeStates, nil
 }
 
+// fetchNodeStateWithoutPrivileges reads the nodes from the catalog editor,
+// when the user in monitoring mode is denied access to the node states of the
+// running database. The states of the nodes are unknown.
+func (vcc VClusterCommands) fetchNodeStateWithoutPrivileges(options *VFetchNodeStateOptions) ([]NodeInfo, error) {
+	vcc.Log.DisplayWarning("The states of the nodes are shown as UNKNOWN because user %s is denied access to them",
+		options.UserName)
+
+	var fetchDatabaseOptions VFetchCoordinationDatabaseOptions
+	fetchDatabaseOptions.DatabaseOptions = options.DatabaseOptions
+	// the catalog editor is read through the NMA, with no database privileges
+	fetchDatabaseOptions.MonitoringOnly = false
+	fetchDatabaseOptions.ReadOnly = true
+	fetchDatabaseOptions.readOnly = true
+	vdb, err := vcc.VFetchCoordinationDatabase(&fetchDatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeStates := buildNodeStateList(&vdb, true /*forDownDatabase*/)
+	for i := range nodeStates {
+		nodeStates[i].State = util.NodeUnknownState
+	}
+	return nodeStates, nil
+}
+
 // produceListAllNodesInstructions will build a list of instructions to execute for
 // the fetch node state operation.
 func (vcc VClusterCommands) produceListAllNodesInstructions(
@@ -20,51 +20,16 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
-type NodeState struct {
-	Name                     string   `json:"name"`
-	ID                       uint64   `json:"node_id"`
-	Address                  string   `json:"address"`
-	State                    string   `json:"state"`
-	Database                 string   `json:"database"`
-	IsPrimary                bool     `json:"is_primary"`
-	IsReadOnly               bool     `json:"is_readonly"`
-	CatalogPath              string   `json:"catalog_path"`
-	DataPath                 []string `json:"data_path"`
-	DepotPath                string   `json:"depot_path"`
-	SubclusterName           string   `json:"subcluster_name"`
-	SubclusterID             uint64   `json:"subcluster_id"`
-	LastMsgFromNodeAt        string   `json:"last_msg_from_node_at"`
-	DownSince                string   `json:"down_since"`
-	Version                  string   `json:"build_info"`
-	SandboxName              string   `json:"sandbox_name"`
-	NumberShardSubscriptions uint     `json:"number_shard_subscriptions"`
-}
-
-type StorageLocation struct {
-	Name        string `json:"name"`
-	ID          uint64 `json:"location_id"`
-	Label       string `json:"label"`
-	UsageType   string `json:"location_usage_type"`
-	Path        string `json:"location_path"`
-	SharingType string `json:"location_sharing_type"`
-	MaxSize     uint64 `json:"max_size"`
-	DiskPercent string `json:"disk_percent"`
-	HasCatalog  bool   `json:"has_catalog"`
-	Retired     bool   `json:"retired"`
-}
-
-type StorageLocations struct {
-	StorageLocList []StorageLocation `json:"storage_location_list"`
-}
-
-type NodeDetails struct {
-	NodeState
-	StorageLocations
-}
-
-type NodesDetails []NodeDetails
+type (
+	NodeState        = vtypes.NodeState
+	StorageLocation  = vtypes.StorageLocation
+	StorageLocations = vtypes.StorageLocations
+	NodeDetails      = vtypes.NodeDetails
+	NodesDetails     = vtypes.NodesDetails
+)
 
 type hostNodeDetailsMap map[string]*NodeDetails
 
@@ -0,0 +1,128 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FleetCommand runs a command on one cluster of a fleet. options holds the
+// connection settings of the cluster from the registry, for the command to
+// copy into its own options, e.g., VFetchNodeStateOptions.DatabaseOptions.
+type FleetCommand func(vcc VClusterCommands, clusterName string, options *DatabaseOptions) error
+
+// FleetResult is the outcome of a FleetCommand on one cluster
+type FleetResult struct {
+	ClusterName string
+	Err         error
+	Duration    time.Duration
+}
+
+// FleetRunner runs the same command, e.g., a health check, scrutinize, or a
+// settings change, across the clusters of a ClusterRegistry concurrently
+type FleetRunner struct {
+	Registry *ClusterRegistry
+	Commands VClusterCommands
+	// maximum number of clusters that run the command at the same time
+	MaxConcurrency int
+	// minimum time between starting the command on two clusters, to avoid
+	// flooding shared services, e.g., an identity provider; 0 means no limit
+	MinStartInterval time.Duration
+}
+
+const defaultFleetConcurrency = 4
+
+// MakeFleetRunner returns a FleetRunner for the clusters of registry
+func MakeFleetRunner(vcc VClusterCommands, registry *ClusterRegistry) FleetRunner {
+	return FleetRunner{
+		Registry:       registry,
+		Commands:       vcc,
+		MaxConcurrency: defaultFleetConcurrency,
+	}
+}
+
+// Run runs command on the named clusters, or on all registered clusters if no
+// name is given. It waits for all of them to finish, and returns their results
+// in the order of the names. A failure on one cluster does not stop the others.
+func (runner *FleetRunner) Run(clusterNames []string, command FleetCommand) []FleetResult {
+	if len(clusterNames) == 0 {
+		clusterNames = runner.Registry.Names()
+	}
+	concurrency := runner.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultFleetConcurrency
+	}
+
+	results := make([]FleetResult, len(clusterNames))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var lastStart time.Time
+	for i, clusterName := range clusterNames {
+		slots <- struct{}{}
+		if runner.MinStartInterval > 0 && !lastStart.IsZero() {
+			time.Sleep(time.Until(lastStart.Add(runner.MinStartInterval)))
+		}
+		lastStart = time.Now()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = runner.runOnCluster(clusterName, command)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (runner *FleetRunner) runOnCluster(clusterName string, command FleetCommand) (result FleetResult) {
+	result.ClusterName = clusterName
+	startTime := time.Now()
+	defer func() {
+		// a bug in the command of one cluster should not take down the fleet
+		if r := recover(); r != nil {
+			result.Err = fmt.Errorf("command panicked on cluster %s: %v", clusterName, r)
+		}
+		result.Duration = time.Since(startTime)
+	}()
+
+	options := DatabaseOptionsFactory()
+	err := runner.Registry.ApplyTo(clusterName, &options)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// tag the log of each cluster with its name
+	vcc := runner.Commands
+	vcc.Log = vcc.Log.WithName(clusterName)
+	result.Err = command(vcc, clusterName, &options)
+	return result
+}
+
+// FailedFleetResults returns the results of the clusters where the command failed
+func FailedFleetResults(results []FleetResult) []FleetResult {
+	var failed []FleetResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
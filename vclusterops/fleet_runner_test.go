@@ -0,0 +1,77 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestFleetRunner(t *testing.T) {
+	registry := MakeClusterRegistry()
+	for _, name := range []string{"east", "west", "dr"} {
+		assert.NoError(t, registry.Register(name, &ClusterConnection{DBName: name + "_db", RawHosts: []string{name + "-host"}}))
+	}
+	runner := MakeFleetRunner(VClusterCommands{VClusterCommandsLogger{Log: vlog.Printer{}}}, registry)
+	runner.MaxConcurrency = 2
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	command := func(_ VClusterCommands, clusterName string, options *DatabaseOptions) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		switch clusterName {
+		case "west":
+			return errors.New("west is down")
+		case "dr":
+			panic("unexpected state")
+		}
+		assert.Equal(t, clusterName+"_db", options.DBName)
+		return nil
+	}
+
+	// all registered clusters run, with results in the order of the names
+	results := runner.Run(nil, command)
+	assert.Equal(t, []string{"dr", "east", "west"},
+		[]string{results[0].ClusterName, results[1].ClusterName, results[2].ClusterName})
+	assert.ErrorContains(t, results[0].Err, "command panicked on cluster dr: unexpected state")
+	assert.NoError(t, results[1].Err)
+	assert.ErrorContains(t, results[2].Err, "west is down")
+	assert.LessOrEqual(t, maxRunning, 2)
+	assert.Len(t, FailedFleetResults(results), 2)
+
+	// the clusters can be named, and the starts rate limited
+	runner.MinStartInterval = 20 * time.Millisecond
+	startTime := time.Now()
+	results = runner.Run([]string{"east", "unknown"}, command)
+	assert.GreaterOrEqual(t, time.Since(startTime), runner.MinStartInterval)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "cluster unknown is not in the registry")
+}
@@ -20,17 +20,13 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
-type DrainingStatus struct {
-	SubclusterName string `json:"subcluster_name"`
-	Status         string `json:"drain_status"`
-	RedirectTo     string `json:"redirect_to"`
-}
-
-type DrainingStatusList struct {
-	StatusList []DrainingStatus `json:"draining_status_list"`
-}
+type (
+	DrainingStatus     = vtypes.DrainingStatus
+	DrainingStatusList = vtypes.DrainingStatusList
+)
 
 type VGetDrainingStatusOptions struct {
 	// basic db info
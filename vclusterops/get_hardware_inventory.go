@@ -0,0 +1,170 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	HardwareInventory  = vtypes.HardwareInventory
+	HostHardware       = vtypes.HostHardware
+	HardwareDifference = vtypes.HardwareDifference
+	HardwareThresholds = vtypes.HardwareThresholds
+	HardwareWarning    = vtypes.HardwareWarning
+)
+
+// DefaultHardwareThresholds are the thresholds of the hardware check of
+// add_node and create_db, unless they are set in the options
+var DefaultHardwareThresholds = HardwareThresholds{MemoryPercent: 10, CorePercent: 10}
+
+// HardwareCheckOptions are the options of the check of add_node and create_db
+// that warns about new hosts whose hardware or OS is unlike the other hosts
+type HardwareCheckOptions struct {
+	// whether to skip the check
+	SkipHardwareCheck  bool
+	HardwareThresholds HardwareThresholds
+	// optional, called with every warning, in addition to the warning being logged
+	OnHardwareWarning func(HardwareWarning)
+}
+
+func (options *HardwareCheckOptions) setDefaultValues() {
+	options.HardwareThresholds = DefaultHardwareThresholds
+}
+
+type VGetHardwareInventoryOptions struct {
+	// basic db info, only the hosts are required unless SCName is set
+	DatabaseOptions
+	// when set, the hardware of the hosts of this subcluster of the running
+	// database is collected, instead of the hardware of all hosts
+	SCName string
+}
+
+func VGetHardwareInventoryOptionsFactory() VGetHardwareInventoryOptions {
+	options := VGetHardwareInventoryOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VGetHardwareInventoryOptions) validateParseOptions(logger vlog.Printer) error {
+	return options.validateBaseOptions(GetHardwareInventoryCmd, logger)
+}
+
+func (options *VGetHardwareInventoryOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VGetHardwareInventoryOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	if options.SCName == "" {
+		return nil
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VGetHardwareInventory collects the CPU, memory, disks, NICs, NUMA topology,
+// time zone, locale, and NTP source of the hosts through their NMA. The
+// differences in the returned inventory are the hardware properties that are
+// not the same on all of the hosts, so setting SCName checks whether the hosts
+// of a subcluster are alike.
+func (vcc VClusterCommands) VGetHardwareInventory(options *VGetHardwareInventoryOptions) (HardwareInventory, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	inventory := HardwareInventory{}
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return inventory, err
+	}
+	err = vcc.selectHosts(&options.DatabaseOptions)
+	if err != nil {
+		return inventory, err
+	}
+
+	hosts := options.Hosts
+	vdb := makeVCoordinationDatabase()
+	if options.SCName != "" {
+		err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
+		if err != nil {
+			return inventory, err
+		}
+		hosts = nil
+		for host, vnode := range vdb.HostNodeMap {
+			if vnode.Subcluster == options.SCName {
+				hosts = append(hosts, host)
+			}
+		}
+		if len(hosts) == 0 {
+			return inventory, fmt.Errorf("subcluster %s is not found or has no nodes", options.SCName)
+		}
+	}
+
+	hostHardware := make(map[string]HostHardware)
+	nmaHealthOp := makeNMAHealthOp(hosts)
+	nmaGetHardwareInventoryOp, err := makeNMAGetHardwareInventoryOp(hosts, hostHardware)
+	if err != nil {
+		return inventory, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	instructions := []clusterOp{&nmaHealthOp, &nmaGetHardwareInventoryOp}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return inventory, fmt.Errorf("fail to collect hardware inventory: %w", runError)
+	}
+
+	return makeHardwareInventory(hostHardware, &vdb), nil
+}
+
+// makeHardwareInventory sorts the hardware of the hosts by host, and adds the
+// node of each host if the database info is known
+func makeHardwareInventory(hostHardware map[string]HostHardware, vdb *VCoordinationDatabase) HardwareInventory {
+	inventory := HardwareInventory{}
+	for host, hardware := range hostHardware {
+		if vnode, ok := vdb.HostNodeMap[host]; ok {
+			hardware.NodeName = vnode.Name
+			hardware.Subcluster = vnode.Subcluster
+		}
+		inventory.Hosts = append(inventory.Hosts, hardware)
+	}
+	sort.Slice(inventory.Hosts, func(i, j int) bool {
+		return inventory.Hosts[i].Host < inventory.Hosts[j].Host
+	})
+	inventory.Differences = vtypes.FindDifferences(inventory.Hosts)
+	return inventory
+}
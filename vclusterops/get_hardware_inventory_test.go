@@ -0,0 +1,143 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const testHardwareResponse = `{"cpu": {"model": "Intel(R) Xeon(R) Gold 6230", "sockets": 2, "cores": 40, "threads": 80},
+	"memory_bytes": 270582939648,
+	"disks": [{"device": "/dev/nvme0n1", "mount_point": "/data", "size_bytes": 1920383410176}],
+	"nics": [{"name": "eth0", "address": "192.168.1.101", "speed_mbps": 25000}],
+	"numa_nodes": [{"id": 0, "memory_bytes": 135291469824}, {"id": 1, "memory_bytes": 135291469824}],
+	"time": {"time_zone": "UTC", "locale": "en_US.UTF-8", "ntp_source": "0.pool.ntp.org"}}`
+
+func TestNMAGetHardwareInventoryOp(t *testing.T) {
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	hostHardware := make(map[string]HostHardware)
+	op, err := makeNMAGetHardwareInventoryOp(hosts, hostHardware)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	assert.False(t, op.getClassification().Mutating)
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {status: SUCCESS, content: testHardwareResponse},
+		hosts[1]: {status: SUCCESS, content: `{"cpu": {"model": "Intel(R) Xeon(R) Gold 6230"}}`},
+	}
+	err = op.processResult(nil)
+	assert.ErrorContains(t, err, "response of host 192.168.1.102 does not contain the CPU and memory")
+	assert.Len(t, hostHardware, 1)
+	hardware := hostHardware[hosts[0]]
+	assert.Equal(t, hosts[0], hardware.Host)
+	assert.Equal(t, 80, hardware.CPU.Threads)
+	assert.Len(t, hardware.NUMANodes, 2)
+	assert.Equal(t, 25000, hardware.NICs[0].SpeedMbps)
+	assert.Equal(t, vtypes.TimeSettings{TimeZone: "UTC", Locale: "en_US.UTF-8", NTPSource: "0.pool.ntp.org"}, hardware.Time)
+}
+
+func TestMakeHardwareInventory(t *testing.T) {
+	const gib = 1 << 30
+	hardware := HostHardware{
+		CPU:         vtypes.CPUInfo{Model: "EPYC 7763", Sockets: 1, Cores: 64, Threads: 128},
+		MemoryBytes: 512 * gib,
+		Disks:       []vtypes.DiskInfo{{Device: "/dev/nvme0n1", SizeBytes: 1024 * gib}},
+		NICs:        []vtypes.NICInfo{{Name: "eth0", SpeedMbps: 25000}},
+		NUMANodes:   []vtypes.NUMANode{{ID: 0}},
+		Time:        vtypes.TimeSettings{TimeZone: "UTC", Locale: "en_US.UTF-8"},
+	}
+	hostHardware := map[string]HostHardware{}
+	for _, host := range []string{"192.168.1.103", "192.168.1.101", "192.168.1.102"} {
+		hardware.Host = host
+		hostHardware[host] = hardware
+	}
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = vHostNodeMap{"192.168.1.101": {Name: "v_test_db_node0001", Subcluster: "sc1"}}
+
+	inventory := makeHardwareInventory(hostHardware, &vdb)
+	assert.Equal(t, "192.168.1.101", inventory.Hosts[0].Host)
+	assert.Equal(t, "v_test_db_node0001", inventory.Hosts[0].NodeName)
+	assert.Equal(t, "sc1", inventory.Hosts[0].Subcluster)
+	assert.Empty(t, inventory.Differences)
+
+	// a few MiB of memory less does not make a host differ
+	hardware = hostHardware["192.168.1.102"]
+	hardware.MemoryBytes -= 1 << 20
+	hardware.NICs = []vtypes.NICInfo{{Name: "eth0", SpeedMbps: 10000}}
+	hostHardware["192.168.1.102"] = hardware
+	inventory = makeHardwareInventory(hostHardware, &vdb)
+	assert.Equal(t, []HardwareDifference{{
+		Property: vtypes.HardwareNICSpeed,
+		Values:   map[string]string{"192.168.1.101": "25000", "192.168.1.102": "10000", "192.168.1.103": "25000"},
+	}}, inventory.Differences)
+
+	// a node in another time zone is reported
+	hardware = hostHardware["192.168.1.103"]
+	hardware.Time.TimeZone = "America/New_York"
+	hostHardware["192.168.1.103"] = hardware
+	inventory = makeHardwareInventory(hostHardware, &vdb)
+	assert.Len(t, inventory.Differences, 2)
+	assert.Equal(t, HardwareDifference{
+		Property: vtypes.HardwareTimeZone,
+		Values:   map[string]string{"192.168.1.101": "UTC", "192.168.1.102": "UTC", "192.168.1.103": "America/New_York"},
+	}, inventory.Differences[1])
+}
+
+func TestNMACheckHardwareProfileOp(t *testing.T) {
+	const gib = 1 << 30
+	reference := HostHardware{
+		OS:          vtypes.OSInfo{Name: "Rocky Linux", Version: "9.3"},
+		CPU:         vtypes.CPUInfo{Cores: 64},
+		MemoryBytes: 512 * gib,
+		Disks:       []vtypes.DiskInfo{{Device: "/dev/nvme0n1"}},
+	}
+	similar := reference
+	similar.CPU.Cores = 60
+	similar.MemoryBytes = 480 * gib
+	unlike := reference
+	unlike.MemoryBytes = 256 * gib
+	unlike.Disks = []vtypes.DiskInfo{{Device: "/dev/sda", Rotational: true}}
+
+	var warnings []HardwareWarning
+	check := HardwareCheckOptions{HardwareThresholds: DefaultHardwareThresholds,
+		OnHardwareWarning: func(warning HardwareWarning) { warnings = append(warnings, warning) }}
+	op, err := makeNMACheckHardwareProfileOp([]string{"192.168.1.101"}, []string{"192.168.1.102", "192.168.1.103"}, &check)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}, op.hosts)
+
+	for host, hardware := range map[string]HostHardware{"192.168.1.101": reference,
+		"192.168.1.102": similar, "192.168.1.103": unlike} {
+		hardware.Host = host
+		op.hostHardware[host] = hardware
+	}
+	for _, warning := range op.findHardwareWarnings() {
+		check.OnHardwareWarning(warning)
+	}
+	assert.Equal(t, []HardwareWarning{
+		{Host: "192.168.1.103", ReferenceHost: "192.168.1.101", Property: vtypes.HardwareMemory, Value: "256", Reference: "512"},
+		{Host: "192.168.1.103", ReferenceHost: "192.168.1.101", Property: vtypes.HardwareDiskType,
+			Value: vtypes.DiskTypeHDD, Reference: vtypes.DiskTypeSSD},
+	}, warnings)
+	assert.Equal(t, "host 192.168.1.103 has memory_gib 256, but host 192.168.1.101 has 512", warnings[0].String())
+
+	// no warning when the reference host did not report its hardware
+	delete(op.hostHardware, "192.168.1.101")
+	assert.Empty(t, op.findHardwareWarnings())
+}
@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	ReplicationJobStatus = vtypes.ReplicationJobStatus
+	ReplicationJobError  = vtypes.ReplicationJobError
+)
+
+type VGetReplicationStatusOptions struct {
+	// TransactionID is the job to get the status of, 0 for all the jobs
+	// known by the target database
+	VReplicationStatusDatabaseOptions
+}
+
+func VGetReplicationStatusFactory() VGetReplicationStatusOptions {
+	options := VGetReplicationStatusOptions{}
+	options.VReplicationStatusDatabaseOptions = VReplicationStatusFactory()
+	return options
+}
+
+func (options *VGetReplicationStatusOptions) validateAnalyzeOptions(_ vlog.Printer) error {
+	err := options.validateTargetOptions()
+	if err != nil {
+		return err
+	}
+	if options.TransactionID < 0 {
+		return fmt.Errorf("must specify a valid transaction ID, or 0 for all the replication jobs")
+	}
+	return options.analyzeOptions()
+}
+
+// VGetReplicationStatus returns the status of one or all of the asynchronous
+// replication jobs of the target database, sorted by transaction ID, so that
+// operators can build their own monitoring. Unlike VReplicationStatus, the
+// failed ops of each job are reported along with its current stage.
+func (vcc VClusterCommands) VGetReplicationStatus(options *VGetReplicationStatusOptions) ([]ReplicationJobStatus, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	// produce database replication status instructions
+	replicationStatus := []ReplicationStatusResponse{}
+	instructions, err := vcc.produceReplicationStatusInstructions(&options.VReplicationStatusDatabaseOptions,
+		&replicationStatus)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := makeClusterOpEngine(instructions, &options.TargetDB)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return nil, fmt.Errorf("fail to get replication status: %w", runError)
+	}
+
+	jobs := summarizeReplicationJobs(replicationStatus)
+	if options.TransactionID != 0 && len(jobs) == 0 {
+		return nil, fmt.Errorf("invalid transaction ID")
+	}
+	return jobs, nil
+}
+
+// summarizeReplicationJobs groups the op statuses by job and summarizes each job
+func summarizeReplicationJobs(replicationStatus []ReplicationStatusResponse) []ReplicationJobStatus {
+	opsByJob := make(map[int64][]ReplicationStatusResponse)
+	for i := range replicationStatus {
+		txnID := replicationStatus[i].TransactionID
+		opsByJob[txnID] = append(opsByJob[txnID], replicationStatus[i])
+	}
+
+	jobs := make([]ReplicationJobStatus, 0, len(opsByJob))
+	for txnID, ops := range opsByJob {
+		final := getFinalReplicationStatus(ops)
+		job := ReplicationJobStatus{
+			TransactionID:    txnID,
+			StartTime:        final.StartTime,
+			EndTime:          final.EndTime,
+			Stage:            final.OpName,
+			Status:           final.Status,
+			NodeName:         final.NodeName,
+			SentBytes:        final.SentBytes,
+			TotalBytes:       final.TotalBytes,
+			SentRows:         final.SentRows,
+			CompletedObjects: final.RebuiltProjections,
+			TotalObjects:     final.TotalProjections,
+		}
+		// the ops are sorted chronologically by getFinalReplicationStatus
+		for i := range ops {
+			if ops[i].Status == replicationStatusFailed {
+				job.Errors = append(job.Errors, ReplicationJobError{
+					NodeName:  ops[i].NodeName,
+					Stage:     ops[i].OpName,
+					StartTime: ops[i].StartTime,
+					EndTime:   ops[i].EndTime,
+				})
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].TransactionID < jobs[j].TransactionID
+	})
+	return jobs
+}
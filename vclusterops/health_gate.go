@@ -0,0 +1,74 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	ClusterHealth         = vtypes.ClusterHealth
+	UnhealthyClusterError = vtypes.UnhealthyClusterError
+)
+
+// HealthGate refuses the commands that change a running cluster when the
+// cluster is below a health threshold, so that the topology of an already
+// degraded cluster is not changed. It is checked by the op engine before the
+// first op that changes the cluster. The gate is skipped when the database is
+// not running, e.g., for start_db.
+type HealthGate struct {
+	// the most nodes that may be down, 0 if no node may be down
+	MaxDownNodes int
+	// whether up nodes whose catalog is behind the latest version make the
+	// cluster unhealthy
+	RequireCatalogSync bool
+	// whether to only warn about an unhealthy cluster instead of refusing
+	WarnOnly bool
+}
+
+func (gate *HealthGate) validate() error {
+	if gate.MaxDownNodes < 0 {
+		return fmt.Errorf("the max down nodes of a health gate cannot be negative, got %d", gate.MaxDownNodes)
+	}
+	return nil
+}
+
+// check returns an UnhealthyClusterError if the cluster is below the
+// threshold of the gate, or logs a warning instead if the gate only warns
+func (gate *HealthGate) check(logger vlog.Printer, opName string, health *ClusterHealth) error {
+	var reasons []string
+	if len(health.DownNodes) > gate.MaxDownNodes {
+		reasons = append(reasons, fmt.Sprintf("%d of %d nodes are down (%s), at most %d may be",
+			len(health.DownNodes), health.TotalNodes, strings.Join(health.DownNodes, ", "), gate.MaxDownNodes))
+	}
+	if gate.RequireCatalogSync && len(health.UnsyncedNodes) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the catalog of nodes %s is not in sync",
+			strings.Join(health.UnsyncedNodes, ", ")))
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	if gate.WarnOnly {
+		logger.DisplayWarning("Running %s on an unhealthy cluster: %s", opName, strings.Join(reasons, "; "))
+		return nil
+	}
+	return &UnhealthyClusterError{OpName: opName, Health: *health, Reasons: reasons}
+}
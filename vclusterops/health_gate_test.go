@@ -0,0 +1,98 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// healthGateTestOptions reports a fixed health instead of checking the cluster
+type healthGateTestOptions struct {
+	DatabaseOptions
+	health    ClusterHealth
+	isRunning bool
+}
+
+func (opt *healthGateTestOptions) makeHealthCheckOp(health *ClusterHealth, isRunning *bool) (clusterOp, error) {
+	*health, *isRunning = opt.health, opt.isRunning
+	op := makeMockOp(false)
+	op.method = GetMethod
+	return &op, nil
+}
+
+func TestMakeClusterHealth(t *testing.T) {
+	nodesStates := nodesStateInfo{NodeList: []*nodeStateInfo{
+		{Name: "v_test_db_node0001", State: "UP", CatalogVersion: 12},
+		{Name: "v_test_db_node0002", State: "UP", CatalogVersion: 11},
+		{Name: "v_test_db_node0003", State: "DOWN", CatalogVersion: 9},
+	}}
+	health := makeClusterHealth(&nodesStates)
+	assert.Equal(t, 3, health.TotalNodes)
+	assert.Equal(t, []string{"v_test_db_node0003"}, health.DownNodes)
+	assert.Equal(t, []string{"v_test_db_node0002"}, health.UnsyncedNodes)
+}
+
+func TestHealthGate(t *testing.T) {
+	options := healthGateTestOptions{
+		DatabaseOptions: DatabaseOptions{HealthGate: &HealthGate{RequireCatalogSync: true}},
+		health:          ClusterHealth{TotalNodes: 3, DownNodes: []string{"v_test_db_node0003"}},
+		isRunning:       true,
+	}
+	runOp := func(op clusterOp) error {
+		opEngn := makeClusterOpEngine([]clusterOp{op}, &options)
+		return opEngn.run(vlog.Printer{})
+	}
+
+	// the ops that only read run on an unhealthy cluster
+	readOp := makeMockOp(false)
+	readOp.method = GetMethod
+	assert.NoError(t, runOp(&readOp))
+
+	// the ops that change the cluster are refused
+	writeOp := makeMockOp(false)
+	writeOp.name = "write-op"
+	writeOp.method = PostMethod
+	err := runOp(&writeOp)
+	unhealthyErr := &UnhealthyClusterError{}
+	assert.ErrorAs(t, err, &unhealthyErr)
+	assert.Equal(t, "write-op", unhealthyErr.OpName)
+	assert.ErrorContains(t, err, "1 of 3 nodes are down (v_test_db_node0003), at most 0 may be")
+	assert.False(t, writeOp.calledExecute)
+
+	// unless the threshold allows it
+	options.HealthGate.MaxDownNodes = 1
+	assert.NoError(t, runOp(&writeOp))
+	assert.True(t, writeOp.calledExecute)
+
+	// negative: the catalog of an up node is not in sync
+	options.health.UnsyncedNodes = []string{"v_test_db_node0002"}
+	assert.ErrorContains(t, runOp(&writeOp), "the catalog of nodes v_test_db_node0002 is not in sync")
+
+	// the gate can only warn, or be overridden
+	options.HealthGate.WarnOnly = true
+	assert.NoError(t, runOp(&writeOp))
+	options.HealthGate.WarnOnly = false
+	options.IgnoreHealthGate = true
+	assert.NoError(t, runOp(&writeOp))
+
+	// the gate is skipped when the database is not running
+	options.IgnoreHealthGate = false
+	options.isRunning = false
+	assert.NoError(t, runOp(&writeOp))
+}
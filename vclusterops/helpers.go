@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -111,6 +112,7 @@ type nodeStateInfo struct {
 	Version          string   `json:"build_info"`
 	IsControlNode    bool     `json:"is_control_node"`
 	ControlNode      string   `json:"control_node"`
+	CatalogVersion   int64    `json:"catalog_version"`
 }
 
 func (node *nodeStateInfo) asNodeInfo() (n NodeInfo, err error) {
@@ -145,19 +147,23 @@ type nodesStateInfo struct {
 }
 
 // getInitiatorHost returns as initiator the first primary up node that is not
-// in the list of hosts to skip.
-func getInitiatorHost(primaryUpNodes, hostsToSkip []string) (string, error) {
+// in the list of hosts to skip, and is pinned if any hosts are pinned.
+func getInitiatorHost(primaryUpNodes, hostsToSkip, pinned []string) (string, error) {
 	initiatorHosts := util.SliceDiff(primaryUpNodes, hostsToSkip)
 	if len(initiatorHosts) == 0 {
 		return "", fmt.Errorf("could not find any primary up nodes")
 	}
+	initiatorHosts, err := pinInitiators(initiatorHosts, pinned)
+	if err != nil {
+		return "", err
+	}
 
 	return initiatorHosts[0], nil
 }
 
 // getInitiatorHostInCluster returns an initiator that is the first up node of a subcluster in the main cluster
-// or a sandbox other than the target subcluster
-func getInitiatorHostInCluster(name, sandbox, scname string, vdb *VCoordinationDatabase) ([]string, error) {
+// or a sandbox other than the target subcluster, and is pinned if any hosts are pinned
+func getInitiatorHostInCluster(name, sandbox, scname string, vdb *VCoordinationDatabase, pinned []string) ([]string, error) {
 	// up hosts will be :
 	// 1. up hosts from the main subcluster if the sandbox is empty
 	// 2. up hosts from the sandbox if the sandbox is specified
@@ -169,10 +175,19 @@ func getInitiatorHostInCluster(name, sandbox, scname string, vdb *VCoordinationD
 		// the up host is used to promote/demote subcluster
 		// should not be a part of this subcluster
 		if node.Sandbox == sandbox && node.Subcluster != scname {
-			upHost = node.Address
-			break
+			if len(pinned) == 0 {
+				upHost = node.Address
+				break
+			}
+			// the most preferred pinned host
+			if i := slices.Index(pinned, node.Address); i >= 0 && (upHost == "" || i < slices.Index(pinned, upHost)) {
+				upHost = node.Address
+			}
 		}
 	}
+	if upHost == "" && len(pinned) > 0 {
+		return nil, fmt.Errorf("[%s] none of the pinned initiators %v is an up host outside of subcluster %s", name, pinned, scname)
+	}
 	if upHost == "" {
 		if sandbox == "" {
 			return nil, fmt.Errorf(`[%s] cannot find any up hosts for subcluster %s in main subcluster`, name, scname)
@@ -185,8 +200,9 @@ func getInitiatorHostInCluster(name, sandbox, scname string, vdb *VCoordinationD
 }
 
 // getInitiatorHostForReplication returns an initiator that is the first up source host in the main cluster
-// or a sandbox
-func getInitiatorHostForReplication(name, sandbox string, hosts []string, vdb *VCoordinationDatabase) ([]string, error) {
+// or a sandbox, and is pinned if any hosts are pinned
+func getInitiatorHostForReplication(name, sandbox string, hosts []string, vdb *VCoordinationDatabase,
+	pinned []string) ([]string, error) {
 	// the k8s operator uses a service hostname, not an ip address of a node in the cluster
 	// since the hostname will not match any node in the cluster we need to skip the below logic
 	// this is ok since the operator has already chosen an appropriate "initiator"
@@ -210,6 +226,11 @@ func getInitiatorHostForReplication(name, sandbox string, hosts []string, vdb *V
 		return nil, fmt.Errorf("[%s] cannot find any up hosts in the sandbox %s", name, sandbox)
 	}
 
+	sourceHosts, err := pinInitiators(sourceHosts, pinned)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", name, err)
+	}
+
 	initiatorHost := []string{getInitiator(sourceHosts)}
 	return initiatorHost, nil
 }
@@ -274,6 +295,21 @@ func (vcc VClusterCommands) getVDBFromRunningDBImpl(vdb *VCoordinationDatabase,
 	return nil
 }
 
+// getUpInitiatorFromRunningDB picks an up host of the main cluster or of a
+// sandbox among the hosts of the options, using the node states of the running db
+func (vcc VClusterCommands) getUpInitiatorFromRunningDB(options *DatabaseOptions, sandbox string) (string, error) {
+	vdb := makeVCoordinationDatabase()
+	err := vcc.getVDBFromRunningDBIncludeSandbox(&vdb, options, sandbox)
+	if err != nil {
+		return "", err
+	}
+	upHosts := vdb.filterUpHostlist(options.Hosts, sandbox)
+	if len(upHosts) == 0 {
+		return "", fmt.Errorf("cannot find any up host of database %s in %v", options.DBName, options.Hosts)
+	}
+	return options.pickInitiator(upHosts)
+}
+
 // getClusterInfoFromRunningDB will retrieve db configurations by calling https endpoints of a running db
 func (vcc VClusterCommands) getClusterInfoFromRunningDB(vdb *VCoordinationDatabase, options *DatabaseOptions) error {
 	err := options.setUsePasswordAndValidateUsernameIfNeeded(vcc.Log)
@@ -79,16 +79,21 @@ func TestForGetInitiatorHostInMainCluster(t *testing.T) {
 		"192.168.1.103": {Address: "192.168.1.103", State: "UP", Sandbox: "", Subcluster: "default_subcluster"},
 		"192.168.1.104": {Address: "192.168.1.104", State: "UP", Sandbox: "", Subcluster: "sc4"}}
 	vdb := VCoordinationDatabase{HostNodeMap: mockHostNodeMap}
-	initiatorHost, _ := getInitiatorHostInCluster("", "sand", "sc1", &vdb)
+	initiatorHost, _ := getInitiatorHostInCluster("", "sand", "sc1", &vdb, nil)
 	assert.Equal(t, initiatorHost, []string{"192.168.1.102"})
 	// successfully get an initiator host for default_subcluster to promote/demote in the main subcluster
-	initiatorHost, _ = getInitiatorHostInCluster("", "", "default_subcluster", &vdb)
+	initiatorHost, _ = getInitiatorHostInCluster("", "", "default_subcluster", &vdb, nil)
 	assert.Equal(t, initiatorHost, []string{"192.168.1.104"})
+	// only a pinned host outside of the subcluster is picked
+	initiatorHost, _ = getInitiatorHostInCluster("", "", "sc4", &vdb, []string{"192.168.1.104", "192.168.1.103"})
+	assert.Equal(t, initiatorHost, []string{"192.168.1.103"})
+	_, err := getInitiatorHostInCluster("", "", "sc4", &vdb, []string{"192.168.1.104"})
+	assert.ErrorContains(t, err, "none of the pinned initiators [192.168.1.104] is an up host outside of subcluster sc4")
 	// unable to find any up hosts for default_subcluster in the main subcluster
 	mockHostNodeMap = map[string]*VCoordinationNode{
 		"192.168.1.103": {Address: "192.168.1.103", State: "UP", Sandbox: "", Subcluster: "default_subcluster"}}
 	vdb = VCoordinationDatabase{HostNodeMap: mockHostNodeMap}
-	_, err := getInitiatorHostInCluster("", "", "default_subcluster", &vdb)
+	_, err = getInitiatorHostInCluster("", "", "default_subcluster", &vdb, nil)
 	assert.ErrorContains(t, err, "cannot find any up hosts for subcluster default_subcluster in main subcluster")
 }
 
@@ -98,15 +103,20 @@ func TestForgetInitiatorHost(t *testing.T) {
 	hostsToSkip2 := []string{"10.0.0.0", "10.0.0.1"}
 
 	// successfully picks an initiator
-	initiatorHost, _ := getInitiatorHost(nodesList1, hostsToSkip1)
+	initiatorHost, _ := getInitiatorHost(nodesList1, hostsToSkip1, nil)
 	assert.Equal(t, initiatorHost, "10.0.0.0")
-	initiatorHost, _ = getInitiatorHost(nodesList1, hostsToSkip2)
+	initiatorHost, _ = getInitiatorHost(nodesList1, hostsToSkip2, nil)
 	assert.Equal(t, initiatorHost, "10.0.0.2")
+	// picks the most preferred pinned initiator
+	initiatorHost, _ = getInitiatorHost(nodesList1, hostsToSkip1, []string{"10.0.0.5", "10.0.0.1", "10.0.0.0"})
+	assert.Equal(t, initiatorHost, "10.0.0.1")
+	_, err := getInitiatorHost(nodesList1, hostsToSkip2, []string{"10.0.0.1"})
+	assert.ErrorContains(t, err, "none of the pinned initiators [10.0.0.1] is among the eligible hosts [10.0.0.2]")
 
 	// returns empty string because there is no primary up node that is not
 	// in the list of hosts to skip.
 	hostsToSkip1 = nodesList1
-	initiatorHost, _ = getInitiatorHost(nodesList1, hostsToSkip1)
+	initiatorHost, _ = getInitiatorHost(nodesList1, hostsToSkip1, nil)
 	assert.Equal(t, initiatorHost, "")
 }
 
@@ -121,21 +131,29 @@ func TestForGetSourceHostForReplication(t *testing.T) {
 	// successfully find source hosts from sandbox sand
 	vdb := VCoordinationDatabase{HostNodeMap: mockHostNodeMap}
 	hosts := []string{"192.168.1.102"}
-	sourceHosts, err := getInitiatorHostForReplication("", "sand", hosts, &vdb)
+	sourceHosts, err := getInitiatorHostForReplication("", "sand", hosts, &vdb, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, sourceHosts, hosts)
 
 	// successfully find source hosts from main cluster
 	vdb = VCoordinationDatabase{HostNodeMap: mockHostNodeMap}
 	hosts = []string{"192.168.1.103"}
-	sourceHosts, err = getInitiatorHostForReplication("", "", hosts, &vdb)
+	sourceHosts, err = getInitiatorHostForReplication("", "", hosts, &vdb, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, sourceHosts, hosts)
 
+	// the pinned source host is picked among the up hosts
+	hosts = []string{"192.168.1.103", "192.168.1.104"}
+	sourceHosts, err = getInitiatorHostForReplication("", "", hosts, &vdb, []string{"192.168.1.104"})
+	assert.NoError(t, err)
+	assert.Equal(t, sourceHosts, []string{"192.168.1.104"})
+	_, err = getInitiatorHostForReplication("", "", hosts, &vdb, []string{"192.168.1.101"})
+	assert.ErrorContains(t, err, "none of the pinned initiators")
+
 	// unable to find any up hosts from main cluster
 	vdb = VCoordinationDatabase{HostNodeMap: mockHostNodeMap}
 	hosts = []string{}
-	_, err = getInitiatorHostForReplication("", "", hosts, &vdb)
+	_, err = getInitiatorHostForReplication("", "", hosts, &vdb, nil)
 	assert.ErrorContains(t, err, "cannot find any up hosts from source database")
 }
 
@@ -223,3 +241,25 @@ func TestExtractCatalogPrefix(t *testing.T) {
 	assert.False(t, found)
 	assert.Equal(t, catalogPrefix, expected)
 }
+
+func TestPickPinnedInitiator(t *testing.T) {
+	hosts := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	options := DatabaseOptions{}
+	initiator, err := options.pickInitiator(hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", initiator)
+
+	// the pinned initiators are picked in order of preference
+	options.Initiators = []string{"10.0.0.9", "10.0.0.3", "10.0.0.2"}
+	initiator, err = options.pickInitiator(hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.3", initiator)
+
+	// no other host may be picked
+	options.Initiators = []string{"10.0.0.9"}
+	_, err = options.pickInitiator(hosts)
+	assert.ErrorContains(t, err, "none of the pinned initiators [10.0.0.9]")
+
+	options.Initiators = []string{"admin-node"}
+	assert.ErrorContains(t, options.validateInitiators(), "invalid pinned initiator")
+}
@@ -0,0 +1,203 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// HostAlias maps an external host identifier, e.g., a Kubernetes pod name or a
+// cloud instance ID, to a Vertica node
+type HostAlias struct {
+	Alias    string
+	NodeName string
+	Address  string
+}
+
+// HostAliasMap holds the host aliases of a database. The topology commands,
+// such as VCreateDatabase, VAddNode, VRemoveNode, VReIP, and VAlterNodeAddress,
+// keep the node names and addresses up to date when the map is passed in
+// DatabaseOptions.HostAliases. A HostAliasMap is safe for concurrent use.
+type HostAliasMap struct {
+	mu      sync.RWMutex
+	aliases map[string]HostAlias
+}
+
+type HostAliasNotFoundError = vtypes.HostAliasNotFoundError
+
+func MakeHostAliasMap() *HostAliasMap {
+	return &HostAliasMap{aliases: make(map[string]HostAlias)}
+}
+
+// SetAddress maps an alias to the node at an address. The node name is filled
+// in by the next topology command, e.g., VAddNode for a new host.
+func (m *HostAliasMap) SetAddress(alias, address string) error {
+	if alias == "" || address == "" {
+		return fmt.Errorf("must specify both a host alias and an address")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aliases[alias] = HostAlias{Alias: alias, Address: address}
+	return nil
+}
+
+// SetNodeName maps an alias to a node by its name. The address is filled in by
+// the next topology command.
+func (m *HostAliasMap) SetNodeName(alias, nodeName string) error {
+	if alias == "" || nodeName == "" {
+		return fmt.Errorf("must specify both a host alias and a node name")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.aliases[alias] = HostAlias{Alias: alias, NodeName: nodeName}
+	return nil
+}
+
+// Remove removes an alias from the map
+func (m *HostAliasMap) Remove(alias string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.aliases, alias)
+}
+
+// Get returns the node of an alias
+func (m *HostAliasMap) Get(alias string) (HostAlias, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	hostAlias, ok := m.aliases[alias]
+	return hostAlias, ok
+}
+
+// GetByNodeName returns the alias of a node
+func (m *HostAliasMap) GetByNodeName(nodeName string) (HostAlias, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, hostAlias := range m.aliases {
+		if hostAlias.NodeName == nodeName {
+			return hostAlias, true
+		}
+	}
+	return HostAlias{}, false
+}
+
+// List returns all aliases, sorted by alias
+func (m *HostAliasMap) List() []HostAlias {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	aliases := make([]HostAlias, 0, len(m.aliases))
+	for _, hostAlias := range m.aliases {
+		aliases = append(aliases, hostAlias)
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+	return aliases
+}
+
+// GetNodeNameAddressMap returns the node names and addresses of aliases, in the
+// form of options like VStartNodesOptions.Nodes. All aliases are returned if
+// none are given.
+func (m *HostAliasMap) GetNodeNameAddressMap(aliases ...string) (map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(aliases) == 0 {
+		for alias := range m.aliases {
+			aliases = append(aliases, alias)
+		}
+		// report the first alias that is not mapped in a stable order
+		sort.Strings(aliases)
+	}
+	nodeNameAddressMap := make(map[string]string)
+	for _, alias := range aliases {
+		hostAlias, ok := m.aliases[alias]
+		if !ok || hostAlias.NodeName == "" || hostAlias.Address == "" {
+			return nil, &HostAliasNotFoundError{Alias: alias}
+		}
+		nodeNameAddressMap[hostAlias.NodeName] = hostAlias.Address
+	}
+	return nodeNameAddressMap, nil
+}
+
+// GetAddresses returns the addresses of aliases, e.g., to use as the hosts of
+// a command
+func (m *HostAliasMap) GetAddresses(aliases []string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	addresses := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		hostAlias, ok := m.aliases[alias]
+		if !ok || hostAlias.Address == "" {
+			return nil, &HostAliasNotFoundError{Alias: alias}
+		}
+		addresses = append(addresses, hostAlias.Address)
+	}
+	return addresses, nil
+}
+
+// updateFromVDB fills in the node names and addresses of the aliases from the
+// nodes of a database
+func (m *HostAliasMap) updateFromVDB(vdb *VCoordinationDatabase) {
+	nodeNameAddressMap := make(map[string]string)
+	for _, vnode := range vdb.HostNodeMap {
+		nodeNameAddressMap[vnode.Name] = vnode.Address
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for alias, hostAlias := range m.aliases {
+		if hostAlias.NodeName == "" {
+			if vnode, ok := vdb.HostNodeMap[hostAlias.Address]; ok {
+				hostAlias.NodeName = vnode.Name
+			}
+		} else if address, ok := nodeNameAddressMap[hostAlias.NodeName]; ok {
+			hostAlias.Address = address
+		}
+		m.aliases[alias] = hostAlias
+	}
+}
+
+// updateFromReIPList moves the aliases of re-ipped nodes to their new addresses
+func (m *HostAliasMap) updateFromReIPList(reIPList []ReIPInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for alias, hostAlias := range m.aliases {
+		for _, info := range reIPList {
+			if (info.NodeName != "" && info.NodeName == hostAlias.NodeName) ||
+				(info.NodeName == "" && info.NodeAddress == hostAlias.Address) {
+				hostAlias.Address = info.TargetAddress
+				m.aliases[alias] = hostAlias
+				break
+			}
+		}
+	}
+}
+
+// removeHosts removes the aliases of the nodes at the given addresses
+func (m *HostAliasMap) removeHosts(hosts []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, host := range hosts {
+		for alias, hostAlias := range m.aliases {
+			if hostAlias.Address == host {
+				delete(m.aliases, alias)
+			}
+		}
+	}
+}
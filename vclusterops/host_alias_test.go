@@ -0,0 +1,61 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostAliasMap(t *testing.T) {
+	aliases := MakeHostAliasMap()
+	assert.NoError(t, aliases.SetNodeName("pod-0", "v_test_db_node0001"))
+	assert.NoError(t, aliases.SetAddress("pod-1", "192.168.1.102"))
+	assert.Error(t, aliases.SetAddress("pod-2", ""))
+
+	// the node of pod-0 has no address yet
+	_, err := aliases.GetNodeNameAddressMap()
+	assert.ErrorContains(t, err, "host alias pod-0 is not mapped to a node")
+
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101"}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102"}
+	aliases.updateFromVDB(&vdb)
+
+	nodes, err := aliases.GetNodeNameAddressMap()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"v_test_db_node0001": "192.168.1.101",
+		"v_test_db_node0002": "192.168.1.102",
+	}, nodes)
+	hostAlias, ok := aliases.GetByNodeName("v_test_db_node0002")
+	assert.True(t, ok)
+	assert.Equal(t, "pod-1", hostAlias.Alias)
+
+	// re-ip moves the alias to the new address
+	aliases.updateFromReIPList([]ReIPInfo{{NodeName: "v_test_db_node0001", TargetAddress: "192.168.1.201"}})
+	addresses, err := aliases.GetAddresses([]string{"pod-0", "pod-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.201", "192.168.1.102"}, addresses)
+
+	// removing a node removes its alias
+	aliases.removeHosts([]string{"192.168.1.102"})
+	assert.Len(t, aliases.List(), 1)
+	_, ok = aliases.Get("pod-1")
+	assert.False(t, ok)
+}
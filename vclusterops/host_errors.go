@@ -0,0 +1,116 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	HostErrors     = vtypes.HostErrors
+	HostErrorGroup = vtypes.HostErrorGroup
+)
+
+// hostPlaceholder replaces the host in the messages of host errors, so that
+// the errors that only differ by their host have the same message
+const hostPlaceholder = "<host>"
+
+// collapseHostErrors collapses the errors joined by an op, one per failed
+// host, into a HostErrors whose groups are the errors that only differ by
+// their host. err is returned as is if it is not a joined error, or if no two
+// hosts failed the same way. An error of the op that is not the error of a
+// host is a group of its own.
+func collapseHostErrors(err error, hostErrors map[string]error) error {
+	errs := flattenJoinedErrors(err)
+	if len(errs) < 2 || len(hostErrors) < 2 {
+		return err
+	}
+
+	hosts := make([]string, 0, len(hostErrors))
+	for host := range hostErrors {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+
+	collapsed := &HostErrors{Errors: hostErrors}
+	groupIndex := make(map[string]int)
+	assigned := make(map[string]bool)
+	for _, e := range errs {
+		host := findErrorHost(e, hosts, hostErrors, assigned)
+		key := e.Error()
+		if host != "" {
+			assigned[host] = true
+			key = strings.ReplaceAll(key, host, hostPlaceholder)
+		}
+		if i, found := groupIndex[key]; found && host != "" {
+			collapsed.Groups[i].Hosts = append(collapsed.Groups[i].Hosts, host)
+			continue
+		}
+		group := HostErrorGroup{Err: e}
+		if host != "" {
+			group.Hosts = []string{host}
+		}
+		groupIndex[key] = len(collapsed.Groups)
+		collapsed.Groups = append(collapsed.Groups, group)
+	}
+
+	if len(collapsed.Groups) == len(errs) {
+		return err
+	}
+	for i := range collapsed.Groups {
+		slices.Sort(collapsed.Groups[i].Hosts)
+	}
+	return collapsed
+}
+
+// flattenJoinedErrors returns the errors joined by errors.Join, which an op
+// calls once per host, so the joined errors nest
+func flattenJoinedErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		if err == nil {
+			return nil
+		}
+		return []error{err}
+	}
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenJoinedErrors(e)...)
+	}
+	return errs
+}
+
+// findErrorHost returns the host whose error is e, or else the first host
+// that the message of e names, or "" if e is not the error of a host. The
+// hosts already assigned an error are skipped.
+func findErrorHost(e error, hosts []string, hostErrors map[string]error, assigned map[string]bool) string {
+	for _, host := range hosts {
+		if !assigned[host] && (errors.Is(hostErrors[host], e) || errors.Is(e, hostErrors[host])) {
+			return host
+		}
+	}
+	msg := e.Error()
+	for _, host := range hosts {
+		if !assigned[host] && strings.Contains(msg, host) {
+			return host
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/rfc7807"
+)
+
+func TestCollapseHostErrors(t *testing.T) {
+	hosts := []string{"192.168.1.101", "192.168.1.102", "192.168.1.103"}
+	wrongPassword := func(host string) error {
+		return fmt.Errorf("status code 401 returned from host %s: wrong password", host)
+	}
+
+	// every host fails with a wrong password
+	hostErrors := make(map[string]error)
+	var allErrs error
+	for _, host := range hosts {
+		hostErrors[host] = wrongPassword(host)
+		allErrs = errors.Join(allErrs, hostErrors[host])
+	}
+	err := collapseHostErrors(allErrs, hostErrors)
+	collapsed := &HostErrors{}
+	assert.ErrorAs(t, err, &collapsed)
+	assert.Len(t, collapsed.Groups, 1)
+	assert.Equal(t, hosts, collapsed.Groups[0].Hosts)
+	assert.Equal(t, hostErrors, collapsed.Errors)
+	assert.Contains(t, err.Error(), "(on 3 hosts: 192.168.1.101, 192.168.1.102, 192.168.1.103)")
+
+	// the errors of another cause, and the errors of the op, are groups of their own
+	problem := rfc7807.New(rfc7807.CommunalAccessError).WithHost(hosts[2])
+	hostErrors[hosts[2]] = problem
+	opErr := errors.New("fail to parse the response")
+	allErrs = errors.Join(hostErrors[hosts[0]], hostErrors[hosts[1]], problem, opErr)
+	err = collapseHostErrors(allErrs, hostErrors)
+	assert.ErrorAs(t, err, &collapsed)
+	assert.Len(t, collapsed.Groups, 3)
+	assert.Equal(t, hosts[:2], collapsed.Groups[0].Hosts)
+	assert.Equal(t, []string{hosts[2]}, collapsed.Groups[1].Hosts)
+	assert.Empty(t, collapsed.Groups[2].Hosts)
+	resultProblem := &rfc7807.VProblem{}
+	assert.ErrorAs(t, err, &resultProblem)
+	assert.ErrorIs(t, err, opErr)
+
+	// the errors are kept as is when no two hosts failed the same way
+	allErrs = errors.Join(hostErrors[hosts[0]], problem)
+	assert.Equal(t, allErrs, collapseHostErrors(allErrs, hostErrors))
+	single := wrongPassword(hosts[0])
+	assert.Equal(t, single, collapseHostErrors(single, hostErrors))
+}
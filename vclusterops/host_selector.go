@@ -0,0 +1,352 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type HostSelectorSyntaxError = vtypes.HostSelectorSyntaxError
+
+// the fields of a node that a host selector can compare
+var hostSelectorFields = map[string]func(vnode *VCoordinationNode) string{
+	"name":         func(vnode *VCoordinationNode) string { return vnode.Name },
+	"address":      func(vnode *VCoordinationNode) string { return vnode.Address },
+	"subcluster":   func(vnode *VCoordinationNode) string { return vnode.Subcluster },
+	"sandbox":      func(vnode *VCoordinationNode) string { return vnode.Sandbox },
+	"state":        func(vnode *VCoordinationNode) string { return vnode.State },
+	"version":      func(vnode *VCoordinationNode) string { return vnode.Version },
+	"is_primary":   func(vnode *VCoordinationNode) string { return strconv.FormatBool(vnode.IsPrimary) },
+	"control_node": func(vnode *VCoordinationNode) string { return strconv.FormatBool(vnode.IsControlNode) },
+}
+
+var hostSelectorBoolFields = map[string]bool{"is_primary": true, "control_node": true}
+
+// HostSelector is a parsed expression that selects the nodes of a database,
+// e.g., "subcluster == 'etl' && state == 'UP'". A selector compares the fields
+// name, address, subcluster, sandbox, state, version, is_primary and
+// control_node with ==, != and in ('a', 'b'), and combines the comparisons
+// with &&, || and !, grouped by parentheses. A boolean field alone, e.g.,
+// "is_primary", is true if the field is. The values are quoted strings, or
+// bare words such as true, false and numbers, and are compared as strings.
+type HostSelector struct {
+	expr string
+	root hostSelectorNode
+}
+
+// ParseHostSelector parses a host selector, and returns a
+// HostSelectorSyntaxError if it is not valid
+func ParseHostSelector(expr string) (*HostSelector, error) {
+	p := hostSelectorParser{expr: expr}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+	}
+	return &HostSelector{expr: expr, root: root}, nil
+}
+
+func (s *HostSelector) String() string {
+	return s.expr
+}
+
+// Match returns whether the node is selected
+func (s *HostSelector) Match(vnode *VCoordinationNode) bool {
+	return s.root.eval(vnode)
+}
+
+// Select returns the sorted addresses of the nodes of the database that are selected
+func (s *HostSelector) Select(vdb *VCoordinationDatabase) []string {
+	var hosts []string
+	for host, vnode := range vdb.HostNodeMap {
+		if s.Match(vnode) {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+type hostSelectorNode interface {
+	eval(vnode *VCoordinationNode) bool
+}
+
+type orSelector struct{ left, right hostSelectorNode }
+
+func (n orSelector) eval(vnode *VCoordinationNode) bool {
+	return n.left.eval(vnode) || n.right.eval(vnode)
+}
+
+type andSelector struct{ left, right hostSelectorNode }
+
+func (n andSelector) eval(vnode *VCoordinationNode) bool {
+	return n.left.eval(vnode) && n.right.eval(vnode)
+}
+
+type notSelector struct{ operand hostSelectorNode }
+
+func (n notSelector) eval(vnode *VCoordinationNode) bool {
+	return !n.operand.eval(vnode)
+}
+
+// fieldSelector compares a field of a node with a list of values. The field
+// must equal one of them, or none of them if negated.
+type fieldSelector struct {
+	field   func(vnode *VCoordinationNode) string
+	values  []string
+	negated bool
+}
+
+func (n fieldSelector) eval(vnode *VCoordinationNode) bool {
+	value := n.field(vnode)
+	for _, v := range n.values {
+		if v == value {
+			return !n.negated
+		}
+	}
+	return n.negated
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+)
+
+type selectorToken struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+type hostSelectorParser struct {
+	expr   string
+	tokens []selectorToken
+	pos    int
+}
+
+func (p *hostSelectorParser) errorAt(tok selectorToken, reason string) error {
+	return &HostSelectorSyntaxError{Selector: p.expr, Offset: tok.offset, Reason: reason}
+}
+
+func (p *hostSelectorParser) tokenize() error {
+	expr := p.expr
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexRune(expr[i+1:], c)
+			if end < 0 {
+				return &HostSelectorSyntaxError{Selector: expr, Offset: i, Reason: "unterminated string"}
+			}
+			p.tokens = append(p.tokens, selectorToken{kind: tokenString, text: expr[i+1 : i+1+end], offset: i})
+			i += end + 2
+		case strings.HasPrefix(expr[i:], "==") || strings.HasPrefix(expr[i:], "!=") ||
+			strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			p.tokens = append(p.tokens, selectorToken{kind: tokenOperator, text: expr[i : i+2], offset: i})
+			i += 2
+		case strings.ContainsRune("!(),", c):
+			p.tokens = append(p.tokens, selectorToken{kind: tokenOperator, text: string(c), offset: i})
+			i++
+		case c == '_' || c == '.' || c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i < len(expr) && (expr[i] == '_' || expr[i] == '.' || expr[i] == '-' ||
+				unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i]))) {
+				i++
+			}
+			p.tokens = append(p.tokens, selectorToken{kind: tokenWord, text: expr[start:i], offset: start})
+		default:
+			return &HostSelectorSyntaxError{Selector: expr, Offset: i, Reason: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	p.tokens = append(p.tokens, selectorToken{kind: tokenEnd, offset: len(expr)})
+	return nil
+}
+
+func (p *hostSelectorParser) peek() selectorToken {
+	return p.tokens[p.pos]
+}
+
+func (p *hostSelectorParser) next() selectorToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *hostSelectorParser) isOperator(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokenOperator && tok.text == text
+}
+
+func (p *hostSelectorParser) expect(text string) error {
+	if !p.isOperator(text) {
+		tok := p.peek()
+		return p.errorAt(tok, fmt.Sprintf("expected %q", text))
+	}
+	p.next()
+	return nil
+}
+
+func (p *hostSelectorParser) parseOr() (hostSelectorNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orSelector{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *hostSelectorParser) parseAnd() (hostSelectorNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andSelector{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *hostSelectorParser) parseUnary() (hostSelectorNode, error) {
+	if p.isOperator("!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notSelector{operand: operand}, nil
+	}
+	if p.isOperator("(") {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses "field == value", "field != value",
+// "field in (value, ...)", or a boolean field alone
+func (p *hostSelectorParser) parseComparison() (hostSelectorNode, error) {
+	tok := p.next()
+	if tok.kind != tokenWord {
+		if tok.kind == tokenEnd {
+			return nil, p.errorAt(tok, "expected a field")
+		}
+		return nil, p.errorAt(tok, fmt.Sprintf("expected a field, found %q", tok.text))
+	}
+	field, ok := hostSelectorFields[strings.ToLower(tok.text)]
+	if !ok {
+		return nil, p.errorAt(tok, fmt.Sprintf("unknown field %q", tok.text))
+	}
+
+	switch {
+	case p.isOperator("==") || p.isOperator("!="):
+		negated := p.next().text == "!="
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return fieldSelector{field: field, values: []string{value}, negated: negated}, nil
+	case p.peek().kind == tokenWord && strings.EqualFold(p.peek().text, "in"):
+		p.next()
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		var values []string
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+		return fieldSelector{field: field, values: values}, p.expect(")")
+	case hostSelectorBoolFields[strings.ToLower(tok.text)]:
+		// a boolean field alone
+		return fieldSelector{field: field, values: []string{"true"}}, nil
+	default:
+		return nil, p.errorAt(p.peek(), fmt.Sprintf("expected ==, != or in after field %q", tok.text))
+	}
+}
+
+func (p *hostSelectorParser) parseValue() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenString && tok.kind != tokenWord {
+		return "", p.errorAt(tok, "expected a value")
+	}
+	return tok.text, nil
+}
+
+// selectHosts replaces the hosts of the options with the nodes of the running
+// database that the host selector of the options selects
+func (vcc VClusterCommands) selectHosts(options *DatabaseOptions) error {
+	if options.HostSelector == "" {
+		return nil
+	}
+	selector, err := ParseHostSelector(options.HostSelector)
+	if err != nil {
+		return err
+	}
+	vdb := makeVCoordinationDatabase()
+	err = vcc.getVDBFromMainRunningDBContainsSandbox(&vdb, options)
+	if err != nil {
+		return fmt.Errorf("fail to retrieve the nodes to select hosts from, %w", err)
+	}
+	hosts := selector.Select(&vdb)
+	if len(hosts) == 0 {
+		return fmt.Errorf("no nodes of database %s match the host selector %q", options.DBName, options.HostSelector)
+	}
+	vcc.Log.Info("selected hosts", "selector", options.HostSelector, "hosts", hosts)
+	options.Hosts = hosts
+	return nil
+}
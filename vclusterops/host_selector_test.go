@@ -0,0 +1,88 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeHostSelectorVDB() VCoordinationDatabase {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = map[string]*VCoordinationNode{
+		"10.0.0.1": {Name: "v_db_node0001", Address: "10.0.0.1", Subcluster: "default", State: "UP", IsPrimary: true},
+		"10.0.0.2": {Name: "v_db_node0002", Address: "10.0.0.2", Subcluster: "etl", State: "UP"},
+		"10.0.0.3": {Name: "v_db_node0003", Address: "10.0.0.3", Subcluster: "etl", State: "DOWN"},
+		"10.0.0.4": {Name: "v_db_node0004", Address: "10.0.0.4", Subcluster: "etl", State: "UP", Sandbox: "sand"},
+	}
+	return vdb
+}
+
+func TestHostSelector(t *testing.T) {
+	vdb := makeHostSelectorVDB()
+	tests := map[string][]string{
+		"subcluster == 'etl' && state == 'UP'":                {"10.0.0.2", "10.0.0.4"},
+		`subcluster == "etl" && state == UP && sandbox == ''`: {"10.0.0.2"},
+		"is_primary": {"10.0.0.1"},
+		"!is_primary && !(state == 'DOWN' || sandbox != '')":    {"10.0.0.2"},
+		"name in ('v_db_node0001', v_db_node0003)":              {"10.0.0.1", "10.0.0.3"},
+		"is_primary == false && address != 10.0.0.2":            {"10.0.0.3", "10.0.0.4"},
+		"state == 'UP' || subcluster == 'etl' && sandbox == ''": {"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"},
+		"subcluster == 'analytics'":                             nil,
+	}
+	for expr, expected := range tests {
+		selector, err := ParseHostSelector(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, expected, selector.Select(&vdb), expr)
+	}
+}
+
+func TestHostSelectorSyntaxErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                         "expected a field",
+		"subcluster = 'etl'":       "unexpected character '='",
+		"subcluster == 'etl":       "unterminated string",
+		"zone == 'a'":              `unknown field "zone"`,
+		"subcluster":               `expected ==, != or in after field "subcluster"`,
+		"(state == 'UP'":           `expected ")"`,
+		"state == 'UP' is_primary": `unexpected "is_primary"`,
+		"name in 'a'":              `expected "("`,
+		"state ==":                 "expected a value",
+	}
+	for expr, reason := range tests {
+		_, err := ParseHostSelector(expr)
+		syntaxErr := &HostSelectorSyntaxError{}
+		require.True(t, errors.As(err, &syntaxErr), expr)
+		assert.Contains(t, syntaxErr.Reason, reason, expr)
+	}
+}
+
+func TestValidateHostSelector(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	assert.NoError(t, opt.validateHostSelector(StopDBCmd))
+
+	opt.HostSelector = "state == 'UP'"
+	assert.NoError(t, opt.validateHostSelector(ScrutinizeCmd))
+	assert.NoError(t, opt.validateHostSelector(GetHardwareInventoryCmd))
+	assert.EqualError(t, opt.validateHostSelector(StopDBCmd), "stop_db does not support a host selector")
+
+	opt.HostSelector = "state =="
+	syntaxErr := &HostSelectorSyntaxError{}
+	assert.ErrorAs(t, opt.validateHostSelector(ScrutinizeCmd), &syntaxErr)
+}
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -31,12 +32,32 @@ import (
 	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
+type ServerError = vtypes.ServerError
+
 type httpAdapter struct {
 	opBase
 	host            string
 	respBodyHandler responseBodyHandler
+	// optional, the SOCKS5 proxy to send requests through
+	proxyURL string
+	// optional, the address to send requests to instead of host, e.g., the
+	// host address of a node that is targeted by its control address
+	address string
+	// the headers to attach to every request, e.g., the correlation ID
+	headers map[string]string
+	// optional, the services that accept gzip-compressed request bodies
+	compression *compressionSupport
+	// optional, the cached responses of GET requests
+	resultCache *ResultCache
+	// optional, the metrics of the connections to the hosts
+	connMetrics *ConnectionMetrics
+	// optional, the hosts with a request in flight
+	activity *CommandActivity
+	// how the certificate of the host is verified by the current request
+	tlsVerifyMode string
 }
 
 func makeHTTPAdapter(logger vlog.Printer) httpAdapter {
@@ -54,12 +75,30 @@ func makeHTTPDownloadAdapter(logger vlog.Printer,
 	destFilePath string) httpAdapter {
 	newHTTPAdapter := makeHTTPAdapter(logger)
 	newHTTPAdapter.respBodyHandler = &responseBodyDownloader{
+		logger:       logger,
+		destFilePath: destFilePath,
+	}
+	return newHTTPAdapter
+}
+
+// makeHTTPStreamAdapter creates an HTTP adapter which will
+// decode a response body of a JSON array one item at a time,
+// passing each item to itemHandler, rather than copying the
+// whole body to memory.
+func makeHTTPStreamAdapter(logger vlog.Printer,
+	itemHandler jsonItemHandler) httpAdapter {
+	newHTTPAdapter := makeHTTPAdapter(logger)
+	newHTTPAdapter.respBodyHandler = &responseBodyStreamer{
 		logger,
-		destFilePath,
+		itemHandler,
 	}
 	return newHTTPAdapter
 }
 
+// jsonItemHandler handles one item of a streamed JSON array. Returning an
+// error stops the streaming and fails the request.
+type jsonItemHandler func(item json.RawMessage) error
+
 type responseBodyHandler interface {
 	processResponseBody(resp *http.Response) (string, error)
 }
@@ -71,6 +110,18 @@ type responseBodyReader struct{}
 type responseBodyDownloader struct {
 	logger       vlog.Printer
 	destFilePath string
+	// optional, resumes an interrupted download with a Range request
+	resume downloadResumer
+	// the bytes read from the responses of resumed downloads
+	resumedBytes int64
+	// the resumes of the download, for the retry report of the command
+	retries HostRetries
+}
+
+// for decoding a JSON array response body item by item instead of reading into memory
+type responseBodyStreamer struct {
+	logger      vlog.Printer
+	itemHandler jsonItemHandler
 }
 
 const (
@@ -87,20 +138,29 @@ type certificatePaths struct {
 }
 
 func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	adapter.activity.requestStarted(adapter.host)
+	defer adapter.activity.requestDone(adapter.host)
+
 	// build query params
 	queryParams := buildQueryParamString(request.QueryParams)
 
 	// set up the request URL
 	var port int
-	if request.IsNMACommand {
+	if request.Port > 0 {
+		port = request.Port
+	} else if request.IsNMACommand {
 		port = nmaPort
 	} else {
 		port = httpsPort
 	}
 
-	requestURL := fmt.Sprintf("https://%s:%d/%s%s",
-		adapter.host,
-		port,
+	address := adapter.host
+	if adapter.address != "" {
+		address = adapter.address
+	}
+	service := fmt.Sprintf("%s:%d", address, port)
+	requestURL := fmt.Sprintf("https://%s/%s%s",
+		service,
 		request.Endpoint,
 		queryParams)
 	adapter.logger.Info("Request URL", "URL", requestURL)
@@ -120,11 +180,10 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 	}
 
 	// set up request body
-	var requestBody io.Reader
-	if request.RequestData == "" {
-		requestBody = http.NoBody
-	} else {
-		requestBody = bytes.NewBuffer([]byte(request.RequestData))
+	requestBody, compressed, err := adapter.buildRequestBody(request, service)
+	if err != nil {
+		resultChannel <- adapter.makeExceptionResult(err)
+		return
 	}
 
 	// build HTTP request
@@ -135,20 +194,47 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 		resultChannel <- adapter.makeExceptionResult(err)
 		return
 	}
+	if compressed {
+		req.Header.Set("Content-Encoding", gzipEncoding)
+	}
+	if downloader, ok := adapter.respBodyHandler.(*responseBodyDownloader); ok {
+		downloader.resume = func(offset int64) (*http.Response, error) {
+			resumeReq := req.Clone(req.Context())
+			resumeReq.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			return client.Do(resumeReq)
+		}
+	}
 	// close the connection after sending the request (for clients)
 	req.Close = true
+	for key, value := range adapter.headers {
+		req.Header.Set(key, value)
+	}
 
 	// set username and password
 	// which is only used for HTTPS endpoints
 	if usePassword {
 		req.SetBasicAuth(request.Username, *request.Password)
 	}
+	var cached *cachedRequest
+	if adapter.isCacheableRequest(request) {
+		var cachedResult hostHTTPResult
+		var hit bool
+		cached, cachedResult, hit = adapter.lookupCachedResult(client, req, service, request.Username)
+		if hit {
+			resultChannel <- cachedResult
+			return
+		}
+	}
 
 	// send HTTP request
+	start := time.Now()
 	resp, err := client.Do(req)
+	if adapter.connMetrics != nil {
+		adapter.connMetrics.record(adapter.host, adapter.tlsVerifyMode, time.Since(start), err)
+	}
 	if err != nil {
-		err = fmt.Errorf("fail to send request %v on host %s, details %w",
-			request.Endpoint, adapter.host, err)
+		err = fmt.Errorf("fail to send request %v on host %s with TLS verification mode %s, details %w",
+			request.Endpoint, adapter.host, adapter.tlsVerifyMode, err)
 		if errors.Is(err, io.EOF) {
 			resultChannel <- adapter.makeEOFResult(err)
 		} else {
@@ -157,12 +243,54 @@ func (adapter *httpAdapter) sendRequest(request *hostHTTPRequest, resultChannel
 		return
 	}
 	defer resp.Body.Close()
+	if adapter.compression != nil {
+		adapter.compression.update(service, resp.Header)
+	}
 
 	// generate and return the result
+	if cached != nil {
+		resultChannel <- adapter.generateCachedResult(resp, cached)
+		return
+	}
 	resultChannel <- adapter.generateResult(resp)
 }
 
-func (adapter *httpAdapter) generateResult(resp *http.Response) hostHTTPResult {
+// buildRequestBody returns the body of a request, which is compressed with gzip
+// if it is large and the service advertised that it accepts compressed bodies
+func (adapter *httpAdapter) buildRequestBody(request *hostHTTPRequest, service string) (body io.Reader, compressed bool, err error) {
+	if request.RequestData == "" {
+		return http.NoBody, false, nil
+	}
+	if len(request.RequestData) < minCompressedBodySize || !adapter.compression.acceptsGzip(service) {
+		return bytes.NewBufferString(request.RequestData), false, nil
+	}
+	body, err = compressRequestData(request.RequestData)
+	if err != nil {
+		return nil, false, fmt.Errorf("fail to compress request %v on host %s, details %w",
+			request.Endpoint, adapter.host, err)
+	}
+	adapter.logger.Info("Request body is compressed", "endpoint", request.Endpoint, "size", len(request.RequestData))
+	return body, true, nil
+}
+
+func (adapter *httpAdapter) generateResult(resp *http.Response) (result hostHTTPResult) {
+	// count the bytes of the body for the budget of the command
+	counter := &byteCounter{reader: resp.Body}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{counter, resp.Body}
+	defer func() {
+		result.bytesRead = counter.count
+		if downloader, ok := adapter.respBodyHandler.(*responseBodyDownloader); ok {
+			result.bytesRead += downloader.resumedBytes
+			result.retries = downloader.retries
+		}
+		if spiller, ok := adapter.respBodyHandler.(*responseBodySpiller); ok && result.err == nil {
+			result.spillFile = spiller.spillFile
+		}
+	}()
+
 	bodyString, err := adapter.respBodyHandler.processResponseBody(resp)
 	if err != nil {
 		return adapter.makeExceptionResult(err)
@@ -192,14 +320,82 @@ func (downloader *responseBodyDownloader) processResponseBody(resp *http.Respons
 	return "", err
 }
 
-// downloadFile uses buffered read/writes to download the http response body to a file
+func (streamer *responseBodyStreamer) processResponseBody(resp *http.Response) (bodyString string, err error) {
+	if !isSuccess(resp) {
+		// in case of error, we get an RFC7807 error, not a JSON array
+		return readResponseBody(resp)
+	}
+
+	itemCount, err := decodeJSONArray(resp.Body, streamer.itemHandler)
+	if err != nil {
+		return "", fmt.Errorf("fail to stream the response body: %w", err)
+	}
+	streamer.logger.Info("Response body streamed", "Items", itemCount)
+	return "", nil
+}
+
+// decodeJSONArray decodes a JSON array from r, passing its items to itemHandler
+// in order, so that only one item is held in memory at a time
+func decodeJSONArray(r io.Reader, itemHandler jsonItemHandler) (itemCount int, err error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return 0, fmt.Errorf("expected a JSON array, but got %v", token)
+	}
+
+	for decoder.More() {
+		var item json.RawMessage
+		if err = decoder.Decode(&item); err != nil {
+			return itemCount, err
+		}
+		if err = itemHandler(item); err != nil {
+			return itemCount, err
+		}
+		itemCount++
+	}
+
+	// consume the closing bracket to detect a truncated body
+	_, err = decoder.Token()
+	return itemCount, err
+}
+
+// downloadFile uses buffered read/writes to download the http response body to a file.
+// If the server accepts byte ranges, an interrupted download is resumed from
+// where it stopped rather than restarted. The file is verified against the
+// size and the digest, if any, that the server reported.
 func (downloader *responseBodyDownloader) downloadFile(resp *http.Response) (bytesWritten int64, err error) {
+	downloader.resumedBytes = 0
+	downloader.retries = HostRetries{}
+	check, err := makeDownloadCheck(resp)
+	if err != nil {
+		return 0, err
+	}
 	file, err := os.Create(downloader.destFilePath)
 	if err != nil {
 		return 0, err
 	}
 	defer file.Close()
-	return io.Copy(file, resp.Body)
+	w := check.writer(file)
+
+	bytesWritten, err = io.Copy(w, resp.Body)
+	canResume := downloader.resume != nil && isResumable(resp)
+	for attempt := 1; err != nil && canResume && attempt <= maxDownloadResumes; attempt++ {
+		downloader.logger.Info("Resuming interrupted download", "File", downloader.destFilePath,
+			"Offset", bytesWritten, "Attempt", attempt, "Error", err.Error())
+		reason := err
+		start := time.Now()
+		var resumedBytes int64
+		resumedBytes, err = downloader.resumeDownload(w, bytesWritten, check.size)
+		bytesWritten += resumedBytes
+		addRetry(&downloader.retries, reason, time.Since(start))
+	}
+	if err != nil {
+		return bytesWritten, err
+	}
+	return bytesWritten, check.verify(bytesWritten)
 }
 
 // readResponseBody attempts to read the entire contents of the http response into bodyString
@@ -221,10 +417,11 @@ func isSuccess(resp *http.Response) bool {
 // response comes back from a REST endpoints.
 func (adapter *httpAdapter) makeSuccessResult(content string, statusCode int) hostHTTPResult {
 	return hostHTTPResult{
-		host:       adapter.host,
-		status:     SUCCESS,
-		statusCode: statusCode,
-		content:    content,
+		host:          adapter.host,
+		tlsVerifyMode: adapter.tlsVerifyMode,
+		status:        SUCCESS,
+		statusCode:    statusCode,
+		content:       content,
 	}
 }
 
@@ -234,9 +431,10 @@ func (adapter *httpAdapter) makeSuccessResult(content string, statusCode int) ho
 // process of communicating.
 func (adapter *httpAdapter) makeExceptionResult(err error) hostHTTPResult {
 	return hostHTTPResult{
-		host:   adapter.host,
-		status: EXCEPTION,
-		err:    err,
+		host:          adapter.host,
+		tlsVerifyMode: adapter.tlsVerifyMode,
+		status:        EXCEPTION,
+		err:           err,
 	}
 }
 
@@ -244,11 +442,12 @@ func (adapter *httpAdapter) makeExceptionResult(err error) hostHTTPResult {
 // is received from a REST endpoint.
 func (adapter *httpAdapter) makeFailResult(header http.Header, respBody string, statusCode int) hostHTTPResult {
 	return hostHTTPResult{
-		host:       adapter.host,
-		status:     FAILURE,
-		statusCode: statusCode,
-		content:    respBody,
-		err:        adapter.extractErrorFromResponse(header, respBody, statusCode),
+		host:          adapter.host,
+		tlsVerifyMode: adapter.tlsVerifyMode,
+		status:        FAILURE,
+		statusCode:    statusCode,
+		content:       respBody,
+		err:           adapter.extractErrorFromResponse(header, respBody, statusCode),
 	}
 }
 
@@ -256,9 +455,10 @@ func (adapter *httpAdapter) makeFailResult(header http.Header, respBody string,
 // is received from a REST endpoint.
 func (adapter *httpAdapter) makeEOFResult(err error) hostHTTPResult {
 	return hostHTTPResult{
-		host:   adapter.host,
-		status: EOFEXCEPTION,
-		err:    err,
+		host:          adapter.host,
+		tlsVerifyMode: adapter.tlsVerifyMode,
+		status:        EOFEXCEPTION,
+		err:           err,
 	}
 }
 
@@ -266,12 +466,59 @@ func (adapter *httpAdapter) makeEOFResult(err error) hostHTTPResult {
 // call. We will look at the headers and response body to decide what error
 // object to create.
 func (adapter *httpAdapter) extractErrorFromResponse(header http.Header, respBody string, statusCode int) error {
+	var problem error
 	if header.Get("Content-Type") == rfc7807.ContentType {
-		return rfc7807.GenerateErrorFromResponse(respBody)
+		problem = rfc7807.GenerateErrorFromResponse(respBody)
+	}
+	if serverErr := adapter.decodeServerError(respBody, statusCode); serverErr != nil {
+		serverErr.Problem = problem
+		return serverErr
+	}
+	if problem != nil {
+		return problem
 	}
 	return fmt.Errorf("status code %d returned from host %s: %s", statusCode, adapter.host, respBody)
 }
 
+// serverErrorBody is the error body of the HTTPS service of a node that a SQL
+// statement failed in, which is a RFC 7807 problem with the details of the
+// error, e.g.,
+//
+//	{
+//	  "type": "https://integrators.vertica.com/rest/errors/...",
+//	  "title": "...",
+//	  "detail": "Table t1 does not exist",
+//	  "sql_state": "42V01",
+//	  "error_code": 4566,
+//	  "node": "v_db_node0001"
+//	}
+type serverErrorBody struct {
+	Detail    string `json:"detail"`
+	SQLState  string `json:"sql_state"`
+	ErrorCode int    `json:"error_code"`
+	Node      string `json:"node"`
+}
+
+// decodeServerError returns the ServerError of a response body with a SQL
+// state or an error code, and nil for any other body
+func (adapter *httpAdapter) decodeServerError(respBody string, statusCode int) *ServerError {
+	var body serverErrorBody
+	if err := json.Unmarshal([]byte(respBody), &body); err != nil {
+		return nil
+	}
+	if body.SQLState == "" && body.ErrorCode == 0 {
+		return nil
+	}
+	return &ServerError{
+		Host:       adapter.host,
+		StatusCode: statusCode,
+		SQLState:   body.SQLState,
+		ErrorCode:  body.ErrorCode,
+		Node:       body.Node,
+		Message:    body.Detail,
+	}
+}
+
 func whetherUsePassword(request *hostHTTPRequest) (bool, error) {
 	if request.IsNMACommand {
 		return false, nil
@@ -339,6 +586,29 @@ func (adapter *httpAdapter) buildCertsFromMemory(key, cert, caCert string) (tls.
 	return certificate, caCertPool, nil
 }
 
+// buildCACertPool returns the CA certificates that the certificate of the
+// server is verified with when the client authenticates with a password
+func (adapter *httpAdapter) buildCACertPool(request *hostHTTPRequest) (*x509.CertPool, error) {
+	caCertPool := x509.NewCertPool()
+	if request.UseCertsInOptions {
+		if request.Certs.caCert != "" && !caCertPool.AppendCertsFromPEM([]byte(request.Certs.caCert)) {
+			return nil, fmt.Errorf("fail to load HTTPS CA certificates")
+		}
+		return caCertPool, nil
+	}
+
+	certPaths, err := getCertFilePathsFn()
+	if err != nil {
+		return nil, fmt.Errorf("fail to get paths for certificates, details %w", err)
+	}
+	caCert, err := os.ReadFile(certPaths.caFile)
+	if err != nil {
+		return nil, fmt.Errorf("fail to load HTTPS CA certificates, details %w", err)
+	}
+	caCertPool.AppendCertsFromPEM(caCert)
+	return caCertPool, nil
+}
+
 func (adapter *httpAdapter) setupHTTPClient(
 	request *hostHTTPRequest,
 	usePassword bool,
@@ -352,19 +622,25 @@ func (adapter *httpAdapter) setupHTTPClient(
 	}
 
 	client := &http.Client{Timeout: time.Second * requestTimeout}
-	var config *tls.Config
 
+	// by default, skip peer certificate validation, but allow overrides
+	//nolint:gosec
+	config := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	var caCertPool *x509.CertPool
+	var err error
 	if usePassword {
-		// TODO: we have to use `InsecureSkipVerify: true` here,
-		//       as password is used
-		//nolint:gosec
-		config = &tls.Config{
-			InsecureSkipVerify: true,
+		// the password authenticates the client, but the certificate of the
+		// server is still verified in the requested mode
+		if request.TLSVerifyMode != "" && request.TLSVerifyMode != TLSVerifyInsecure {
+			caCertPool, err = adapter.buildCACertPool(request)
+			if err != nil {
+				return client, err
+			}
 		}
 	} else {
 		var cert tls.Certificate
-		var caCertPool *x509.CertPool
-		var err error
 		if request.UseCertsInOptions {
 			cert, caCertPool, err = adapter.buildCertsFromMemory(request.Certs.key, request.Certs.cert, request.Certs.caCert)
 		} else {
@@ -373,29 +649,40 @@ func (adapter *httpAdapter) setupHTTPClient(
 		if err != nil {
 			return client, err
 		}
-
-		// by default, skip peer certificate validation, but allow overrides
-		//nolint:gosec
-		config = &tls.Config{
-			Certificates:       []tls.Certificate{cert},
-			RootCAs:            caCertPool,
-			InsecureSkipVerify: true,
-		}
-		if request.TLSDoVerify {
-			if request.TLSDoVerifyHostname {
-				// use the built-in golang verification process to validate certificate signer chain
-				// and hostname
-				config.InsecureSkipVerify = false
-			} else {
-				// Note that hosts at this point are IP addresses, so verify-full may be impractical
-				// or impossible due to the complications of issuing certificates valid for IPs.
-				// Hence the custom validator skipping hostname validation.
-				config.VerifyPeerCertificate = util.GenerateTLSVerifyFunc(caCertPool)
-			}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	config.RootCAs = caCertPool
+
+	adapter.tlsVerifyMode = TLSVerifyInsecure
+	switch request.TLSVerifyMode {
+	case TLSVerifyFull:
+		// use the built-in golang verification process to validate certificate signer chain
+		// and hostname
+		config.InsecureSkipVerify = false
+	case TLSVerifyCAOnly:
+		// Note that hosts at this point are IP addresses, so verify-full may be impractical
+		// or impossible due to the complications of issuing certificates valid for IPs.
+		// Hence the custom validator skipping hostname validation.
+		config.VerifyPeerCertificate = util.GenerateTLSVerifyFunc(caCertPool)
+	case TLSVerifyCAOrSystem:
+		config.VerifyPeerCertificate = util.GenerateTLSVerifyFuncWithSystemRoots(caCertPool)
+	}
+	if request.TLSVerifyMode != "" {
+		adapter.tlsVerifyMode = request.TLSVerifyMode
+	}
+
+	// the transport asks for gzip-compressed responses, and decompresses them
+	// transparently
+	transport := &http.Transport{TLSClientConfig: config}
+	if adapter.proxyURL != "" {
+		proxyURL, err := url.Parse(adapter.proxyURL)
+		if err != nil {
+			// the parse error is not wrapped as it contains the proxy credentials
+			return client, fmt.Errorf("invalid proxy URL for host %s", adapter.host)
 		}
+		transport.Proxy = http.ProxyURL(proxyURL)
 	}
-
-	client.Transport = &http.Transport{TLSClientConfig: config}
+	client.Transport = transport
 	return client, nil
 }
 
@@ -0,0 +1,84 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	gzipEncoding = "gzip"
+	// request bodies smaller than this are not worth compressing
+	minCompressedBodySize = 64 * 1024
+)
+
+// compressionSupport keeps the services that advertised that they accept
+// gzip-compressed request bodies, keyed by address and port. It is shared by
+// the adapters of a dispatcher, which learn it from the responses they receive.
+type compressionSupport struct {
+	mu       sync.Mutex
+	services map[string]bool
+}
+
+func makeCompressionSupport() *compressionSupport {
+	return &compressionSupport{services: make(map[string]bool)}
+}
+
+// update records whether a service accepts gzip-compressed request bodies,
+// from the Accept-Encoding header of one of its responses
+func (c *compressionSupport) update(service string, header http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services[service] = acceptsGzip(header)
+}
+
+func (c *compressionSupport) acceptsGzip(service string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.services[service]
+}
+
+func acceptsGzip(header http.Header) bool {
+	for _, value := range header.Values("Accept-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding, _, _ = strings.Cut(encoding, ";")
+			if strings.EqualFold(strings.TrimSpace(encoding), gzipEncoding) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compressRequestData compresses a request body with gzip
+func compressRequestData(data string) (*bytes.Buffer, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return &buffer, nil
+}
@@ -25,12 +25,14 @@ type hostHTTPRequest struct {
 	// string pointer is used here as we need to check whether the password has been set
 	Password *string // optional, for HTTPS endpoints only
 	Timeout  int     // optional, set it if an Op needs longer time to complete
+	Port     int     // optional, overrides the default NMA or HTTPS port of the host
 
 	// optional, for calling NMA/Vertica HTTPS endpoints. If Username/Password is set, that takes precedence over this for HTTPS calls.
-	UseCertsInOptions   bool
-	Certs               httpsCerts
-	TLSDoVerify         bool
-	TLSDoVerifyHostname bool
+	UseCertsInOptions bool
+	Certs             httpsCerts
+	// how the server certificate is verified, one of the TLSVerify values.
+	// Empty means TLSVerifyInsecure.
+	TLSVerifyMode string
 }
 
 type httpsCerts struct {
@@ -40,9 +42,8 @@ type httpsCerts struct {
 }
 
 type tlsModes struct {
-	doVerifyNMAServerCert    bool
-	doVerifyHTTPSServerCert  bool
-	doVerifyPeerCertHostname bool
+	nmaVerifyMode   string
+	httpsVerifyMode string
 }
 
 func (req *hostHTTPRequest) setCerts(certs *httpsCerts) {
@@ -60,13 +61,17 @@ func (req *hostHTTPRequest) setTLSMode(modes *tlsModes) {
 		return
 	}
 	if req.IsNMACommand {
-		req.TLSDoVerify = modes.doVerifyNMAServerCert
+		req.TLSVerifyMode = modes.nmaVerifyMode
 	} else {
-		req.TLSDoVerify = modes.doVerifyHTTPSServerCert
+		req.TLSVerifyMode = modes.httpsVerifyMode
 	}
-	// only do hostname validation if regular validation is enabled
-	if req.TLSDoVerify {
-		req.TLSDoVerifyHostname = modes.doVerifyPeerCertHostname
+}
+
+func (req *hostHTTPRequest) setPort(ports HostPorts) {
+	if req.IsNMACommand {
+		req.Port = ports.NMAPort
+	} else {
+		req.Port = ports.HTTPSPort
 	}
 }
 
@@ -86,4 +91,25 @@ type clusterHTTPRequest struct {
 	ResultCollection  map[string]hostHTTPResult
 	SemVar            semVer
 	Name              string
+	// optional, called by the adapter pool on each host result as soon as
+	// it arrives; see hostResultHandler
+	resultHandler hostResultHandler
+}
+
+// hostResultHandler lets an op consume a host's response while the other hosts
+// are still being waited on. The returned result is the one kept in
+// ResultCollection, so a handler that has already aggregated what it needs from
+// the response body can drop the content instead of keeping it (possibly megabytes
+// per host on large clusters) until processResult runs.
+type hostResultHandler func(result hostHTTPResult) hostHTTPResult
+
+// releaseResults drops the collected host results. This is called by the op
+// engine once an op is finalized, so that the response bodies of previous ops
+// are not retained for the whole engine run.
+func (clusterRequest *clusterHTTPRequest) releaseResults() {
+	for host := range clusterRequest.ResultCollection {
+		result := clusterRequest.ResultCollection[host]
+		result.removeSpillFile()
+	}
+	clusterRequest.ResultCollection = nil
 }
@@ -16,6 +16,8 @@
 package vclusterops
 
 import (
+	"encoding/json"
+
 	"github.com/theckman/yacspin"
 	"github.com/vertica/vcluster/vclusterops/vlog"
 )
@@ -23,41 +25,138 @@ import (
 type requestDispatcher struct {
 	opBase
 	pool adapterPool
+	// optional, the SOCKS5 proxy that the adapters send requests through
+	proxyURL string
+	// optional, the host addresses of the nodes that ops can target by their
+	// control or internal addresses, e.g., when the hosts come from the
+	// catalog, keyed by control or internal address
+	clientAddresses map[string]string
+	// the headers that the adapters attach to every request
+	headers map[string]string
+	// the services that accept gzip-compressed request bodies, kept across the
+	// ops of a command
+	compression *compressionSupport
+	// optional, the endpoints that requests may be sent to
+	endpointPolicy *EndpointPolicy
+	// optional, limits the requests of the command
+	budget *CommandBudget
+	// optional, the size in bytes above which the adapters set up with
+	// setupForSpilling spill response bodies to files in spillDir
+	spillThreshold int64
+	spillDir       string
+	// optional, the cached responses of GET requests, only for the commands
+	// that do not change the cluster
+	resultCache *ResultCache
+	// optional, the metrics of the connections of the adapters
+	connMetrics *ConnectionMetrics
+	// optional, the live activity of the command
+	activity *CommandActivity
 }
 
 func makeHTTPRequestDispatcher(logger vlog.Printer) requestDispatcher {
 	newHTTPRequestDispatcher := requestDispatcher{}
 	newHTTPRequestDispatcher.name = "HTTPRequestDispatcher"
 	newHTTPRequestDispatcher.logger = logger.WithName(newHTTPRequestDispatcher.name)
+	newHTTPRequestDispatcher.compression = makeCompressionSupport()
 
 	return newHTTPRequestDispatcher
 }
 
+// setClientAddresses records the host address of each control address
+func (dispatcher *requestDispatcher) setClientAddresses(controlAddresses map[string]string) {
+	dispatcher.clientAddresses = make(map[string]string, len(controlAddresses))
+	for host, controlAddress := range controlAddresses {
+		dispatcher.clientAddresses[controlAddress] = host
+	}
+}
+
+// addAddressMapping records the external address of each internal address of
+// the hosts behind NAT, so that requests to an internal address are sent to
+// its external address
+func (dispatcher *requestDispatcher) addAddressMapping(addressMapping map[string]string) {
+	if dispatcher.clientAddresses == nil {
+		dispatcher.clientAddresses = make(map[string]string, len(addressMapping))
+	}
+	for externalAddress, internalAddress := range addressMapping {
+		dispatcher.clientAddresses[internalAddress] = externalAddress
+	}
+}
+
+// addConnection adds the adapter of a host to the pool. The results of the
+// host are keyed by host, even when requests are sent to its host address.
+func (dispatcher *requestDispatcher) addConnection(host string, adapter httpAdapter) {
+	adapter.host = host
+	adapter.address = dispatcher.clientAddresses[host]
+	adapter.proxyURL = dispatcher.proxyURL
+	adapter.headers = dispatcher.headers
+	adapter.compression = dispatcher.compression
+	adapter.resultCache = dispatcher.resultCache
+	adapter.connMetrics = dispatcher.connMetrics
+	adapter.activity = dispatcher.activity
+	dispatcher.pool.connections[host] = &adapter
+}
+
 // set up the pool connection for each host
 func (dispatcher *requestDispatcher) setup(hosts []string) {
-	dispatcher.pool = getPoolInstance(dispatcher.logger)
+	dispatcher.pool = makeAdapterPool(dispatcher.logger)
 
-	dispatcher.pool.connections = make(map[string]adapter)
 	for _, host := range hosts {
-		adapter := makeHTTPAdapter(dispatcher.logger)
-		adapter.host = host
-		dispatcher.pool.connections[host] = &adapter
+		dispatcher.addConnection(host, makeHTTPAdapter(dispatcher.logger))
 	}
 }
 
 // set up the pool connection for each host to download a file
 func (dispatcher *requestDispatcher) setupForDownload(hosts []string,
 	hostToFilePathsMap map[string]string) {
-	dispatcher.pool = getPoolInstance(dispatcher.logger)
+	dispatcher.pool = makeAdapterPool(dispatcher.logger)
+
+	for _, host := range hosts {
+		dispatcher.addConnection(host, makeHTTPDownloadAdapter(dispatcher.logger, hostToFilePathsMap[host]))
+	}
+}
+
+// set up the pool connection for each host to spill a large response body to a
+// file, if the command sets a spill threshold. The op reads the bodies with
+// openContent or parseAndCheckResultContent.
+func (dispatcher *requestDispatcher) setupForSpilling(hosts []string) {
+	if dispatcher.spillThreshold <= 0 {
+		dispatcher.setup(hosts)
+		return
+	}
+	dispatcher.pool = makeAdapterPool(dispatcher.logger)
+
+	for _, host := range hosts {
+		dispatcher.addConnection(host, makeHTTPSpillAdapter(dispatcher.logger, dispatcher.spillThreshold, dispatcher.spillDir))
+	}
+}
+
+// set up the pool connection for each host to stream a JSON array response,
+// itemHandler is called with the host and each item of the array
+func (dispatcher *requestDispatcher) setupForStreaming(hosts []string,
+	itemHandler func(host string, item json.RawMessage) error) {
+	dispatcher.pool = makeAdapterPool(dispatcher.logger)
 
 	for _, host := range hosts {
-		adapter := makeHTTPDownloadAdapter(dispatcher.logger, hostToFilePathsMap[host])
-		adapter.host = host
-		dispatcher.pool.connections[host] = &adapter
+		dispatcher.addConnection(host, makeHTTPStreamAdapter(dispatcher.logger, func(item json.RawMessage) error {
+			return itemHandler(host, item)
+		}))
 	}
 }
 
 func (dispatcher *requestDispatcher) sendRequest(httpRequest *clusterHTTPRequest, spinner *yacspin.Spinner) error {
 	dispatcher.logger.Info("HTTP request dispatcher's sendRequest is called")
-	return dispatcher.pool.sendRequest(httpRequest, spinner)
+	if dispatcher.endpointPolicy != nil {
+		if err := dispatcher.endpointPolicy.checkRequests(httpRequest); err != nil {
+			return err
+		}
+	}
+	if dispatcher.budget == nil {
+		return dispatcher.pool.sendRequest(httpRequest, spinner)
+	}
+	if err := dispatcher.budget.reserveRequests(len(httpRequest.RequestCollection)); err != nil {
+		return err
+	}
+	err := dispatcher.pool.sendRequest(httpRequest, spinner)
+	dispatcher.budget.addResults(httpRequest.ResultCollection)
+	return err
 }
@@ -150,6 +150,10 @@ func (op *httpsAddSubclusterOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsAddSubclusterOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsAddSubclusterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
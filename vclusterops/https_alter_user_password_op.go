@@ -0,0 +1,122 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsAlterUserPasswordOp struct {
+	opBase
+	opHTTPSBase
+	targetUserName string
+	requestBody    string
+}
+
+type alterUserPasswordRequestData struct {
+	Password string `json:"password"`
+}
+
+// makeHTTPSAlterUserPasswordOp will make an op that calls the vertica-http
+// service to change the password of a database user
+func makeHTTPSAlterUserPasswordOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, targetUserName string, newPassword *string) (httpsAlterUserPasswordOp, error) {
+	op := httpsAlterUserPasswordOp{}
+	op.name = "HTTPSAlterUserPasswordOp"
+	op.description = "Change password of database user"
+	op.hosts = hosts
+	op.targetUserName = targetUserName
+
+	if newPassword == nil {
+		return op, fmt.Errorf("[%s] should provide a new password for user %s", op.name, targetUserName)
+	}
+	dataBytes, err := json.Marshal(alterUserPasswordRequestData{Password: *newPassword})
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.requestBody = string(dataBytes)
+
+	err = op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsAlterUserPasswordOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PutMethod
+		httpRequest.buildHTTPSEndpoint(util.UsersEndpoint + op.targetUserName + "/password")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		httpRequest.RequestData = op.requestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsAlterUserPasswordOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsAlterUserPasswordOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsAlterUserPasswordOp) processResult(_ *opEngineExecContext) error {
+	// the request is sent to one host only
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
+				op.name, host)
+		}
+		if !result.isPassing() {
+			return result.err
+		}
+
+		/* decode the json-format response
+			The successful response object will be a dictionary like below:
+			{
+		  		"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+	}
+	return nil
+}
+
+func (op *httpsAlterUserPasswordOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
+func (op *httpsAlterUserPasswordOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
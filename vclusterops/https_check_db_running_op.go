@@ -24,6 +24,7 @@ import (
 
 	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 	"golang.org/x/exp/slices"
 )
 
@@ -65,18 +66,7 @@ func (op opType) String() string {
 
 var maskEOFOp = []opType{DropDB}
 
-// DBIsRunningError is an error to indicate we found the database still running.
-// This is emitted from this op. Callers can do type checking to perform an
-// action based on the error.
-type DBIsRunningError struct {
-	Detail string
-}
-
-// Error returns the message details. This is added so that it is compatible
-// with the error interface.
-func (e *DBIsRunningError) Error() string {
-	return e.Detail
-}
+type DBIsRunningError = vtypes.DBIsRunningError
 
 type httpsCheckRunningDBOp struct {
 	opBase
@@ -460,7 +450,7 @@ func (op *httpsCheckRunningDBOp) pollForDBDown(execContext *opEngineExecContext)
 		if count > 0 {
 			time.Sleep(PollingInterval * time.Second)
 		}
-		err = execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+		err = op.dispatchRequest(execContext)
 		if err != nil {
 			return fmt.Errorf("fail to dispatch request %v: %w", op.clusterHTTPRequest, err)
 		}
@@ -486,7 +476,7 @@ func (op *httpsCheckRunningDBOp) pollForDBDown(execContext *opEngineExecContext)
 }
 
 func (op *httpsCheckRunningDBOp) checkDBConnection(execContext *opEngineExecContext) error {
-	err := execContext.dispatcher.sendRequest(&op.clusterHTTPRequest, op.spinner)
+	err := op.dispatchRequest(execContext)
 	if err != nil {
 		return fmt.Errorf("fail to dispatch request %v: %w", op.clusterHTTPRequest, err)
 	}
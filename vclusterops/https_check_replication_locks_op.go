@@ -0,0 +1,183 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type BlockingSession = vtypes.BlockingSession
+
+type blockingSessionList struct {
+	SessionList []BlockingSession `json:"session_list"`
+}
+
+// httpsCheckReplicationLocksOp finds the sessions that hold catalog locks or
+// run DDL on the objects to replicate. Replication would stall on those locks,
+// so the op waits up to timeout seconds for them to be released, and fails
+// with a ReplicationBlockedError otherwise.
+type httpsCheckReplicationLocksOp struct {
+	opBase
+	opHTTPSBase
+	sandbox string
+	vdb     *VCoordinationDatabase
+	// the objects to replicate, empty for the whole database
+	objectParams map[string]string
+	timeout      int
+	sessions     []BlockingSession
+}
+
+func makeHTTPSCheckReplicationLocksOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, sandbox string, replicationOptions *ReplicationOptions, timeout int,
+	vdb *VCoordinationDatabase) (httpsCheckReplicationLocksOp, error) {
+	op := httpsCheckReplicationLocksOp{}
+	op.name = "HTTPSCheckReplicationLocksOp"
+	op.description = "Check for DDL and catalog locks on the objects to replicate"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+	op.sandbox = sandbox
+	op.timeout = timeout
+	op.vdb = vdb
+
+	op.objectParams = replicationObjectParams(replicationOptions)
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsCheckReplicationLocksOp) getPollingTimeout() int {
+	return op.timeout
+}
+
+func (op *httpsCheckReplicationLocksOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("locks")
+		httpRequest.QueryParams = op.objectParams
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsCheckReplicationLocksOp) prepare(execContext *opEngineExecContext) error {
+	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb, execContext.initiators)
+	if err != nil {
+		return err
+	}
+	// the locks are checked on an up host of the source database or sandbox
+	op.hosts = sourceHost
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsCheckReplicationLocksOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsCheckReplicationLocksOp) processResult(execContext *opEngineExecContext) error {
+	// without a timeout, the locks are checked only once
+	if op.timeout <= 0 {
+		noLocks, err := op.shouldStopPolling()
+		if err != nil {
+			return err
+		}
+		if !noLocks {
+			return &ReplicationBlockedError{Sessions: op.sessions}
+		}
+		return nil
+	}
+
+	op.logger.PrintInfo("[%s] waiting up to %d seconds for the locks on the objects to replicate to be released",
+		op.name, op.timeout)
+	err := pollState(op, execContext)
+	if errors.Is(err, errPollingTimeout) {
+		return &ReplicationBlockedError{Sessions: op.sessions, WaitedSeconds: op.timeout}
+	}
+	return err
+}
+
+func (op *httpsCheckReplicationLocksOp) shouldStopPolling() (bool, error) {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return true, result.err
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// the successful response lists the blocking sessions, e.g.,
+		/*
+			{
+				"session_list": [
+					{
+						"session_id": "v_test_db_node0001-12345:0x678",
+						"user_name": "dbadmin",
+						"object_name": "public.t1",
+						"lock_mode": "O",
+						"statement": "ALTER TABLE public.t1 ADD COLUMN c2 INT"
+					}
+				]
+			}
+		*/
+		sessionList := blockingSessionList{}
+		err := op.parseAndCheckResponse(host, result.content, &sessionList)
+		if err != nil {
+			return true, err
+		}
+		op.sessions = sessionList.SessionList
+		for i := range op.sessions {
+			op.logger.Info("Session blocks replication", "session", op.sessions[i].SessionID,
+				"object", op.sessions[i].ObjectName, "lock mode", op.sessions[i].LockMode)
+		}
+		return len(op.sessions) == 0, nil
+	}
+
+	if allErrs == nil {
+		allErrs = fmt.Errorf("[%s] empty result received from the provided hosts %v", op.name, op.hosts)
+	}
+	return true, allErrs
+}
+
+func (op *httpsCheckReplicationLocksOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
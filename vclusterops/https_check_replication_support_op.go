@@ -0,0 +1,143 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// httpsCheckReplicationSupportOp checks that the source database supports the
+// projection mode of a replication before the replication starts. Databases
+// that predate the capabilities endpoint only support replicating projections.
+type httpsCheckReplicationSupportOp struct {
+	opBase
+	opHTTPSBase
+	sandbox        string
+	projectionMode string
+	vdb            *VCoordinationDatabase
+}
+
+type replicationCapabilities struct {
+	ProjectionModes []string `json:"projection_modes"`
+}
+
+func makeHTTPSCheckReplicationSupportOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, sandbox, projectionMode string, vdb *VCoordinationDatabase) (httpsCheckReplicationSupportOp, error) {
+	op := httpsCheckReplicationSupportOp{}
+	op.name = "HTTPSCheckReplicationSupportOp"
+	op.description = "Check replication support of the source database"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+	op.sandbox = sandbox
+	op.projectionMode = projectionMode
+	op.vdb = vdb
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsCheckReplicationSupportOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("replicate/capabilities")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsCheckReplicationSupportOp) prepare(execContext *opEngineExecContext) error {
+	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb, execContext.initiators)
+	if err != nil {
+		return err
+	}
+	op.hosts = sourceHost
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsCheckReplicationSupportOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsCheckReplicationSupportOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return result.err
+		}
+		// the endpoint is missing in the versions without any projection mode
+		// other than the default
+		if result.isNotFound() {
+			return op.checkProjectionMode(&replicationCapabilities{
+				ProjectionModes: []string{ReplicationProjectionsReplicate},
+			})
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// the successful response lists the supported options, e.g.,
+		// {"projection_modes": ["replicate", "rebuild"]}
+		capabilities := replicationCapabilities{}
+		err := op.parseAndCheckResponse(host, result.content, &capabilities)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		return op.checkProjectionMode(&capabilities)
+	}
+
+	return allErrs
+}
+
+func (op *httpsCheckReplicationSupportOp) checkProjectionMode(capabilities *replicationCapabilities) error {
+	if !slices.Contains(capabilities.ProjectionModes, op.projectionMode) {
+		return fmt.Errorf("[%s] the source database does not support the projection mode %s, supported modes: %v",
+			op.name, op.projectionMode, capabilities.ProjectionModes)
+	}
+	return nil
+}
+
+func (op *httpsCheckReplicationSupportOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
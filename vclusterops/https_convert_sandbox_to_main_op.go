@@ -115,6 +115,10 @@ func (op *httpsConvertSandboxToMainOp) processResult(_ *opEngineExecContext) err
 	return allErrs
 }
 
+func (op *httpsConvertSandboxToMainOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsConvertSandboxToMainOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -147,6 +147,10 @@ func (op *httpsCreateArchiveOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsCreateArchiveOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsCreateArchiveOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
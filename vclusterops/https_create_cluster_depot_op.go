@@ -163,6 +163,10 @@ func (op *httpsCreateDepotOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsCreateDepotOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsCreateDepotOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
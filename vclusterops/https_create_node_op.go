@@ -46,6 +46,9 @@ func makeHTTPSCreateNodeOp(newNodeHosts []string, bootstrapHost []string,
 	op.RequestParams["catalog-prefix"] = vdb.CatalogPrefix + "/" + vdb.Name
 	op.RequestParams["data-prefix"] = vdb.DataPrefix + "/" + vdb.Name
 	op.RequestParams["hosts"] = util.ArrayToString(newNodeHosts, ",")
+	if controlHosts, ok := getControlHosts(newNodeHosts, vdb); ok {
+		op.RequestParams["control-hosts"] = util.ArrayToString(controlHosts, ",")
+	}
 	if scName != "" {
 		op.RequestParams[createNodeSCNameParam] = scName
 	}
@@ -58,6 +61,23 @@ func makeHTTPSCreateNodeOp(newNodeHosts []string, bootstrapHost []string,
 	return op, err
 }
 
+// getControlHosts returns the control addresses of the new nodes, in the
+// order of the hosts, if any of them has a control address
+func getControlHosts(newNodeHosts []string, vdb *VCoordinationDatabase) ([]string, bool) {
+	var controlHosts []string
+	hasControlAddress := false
+	for _, host := range newNodeHosts {
+		vnode, ok := vdb.HostNodeMap[host]
+		if !ok {
+			controlHosts = append(controlHosts, host)
+			continue
+		}
+		hasControlAddress = hasControlAddress || vnode.ControlAddress != ""
+		controlHosts = append(controlHosts, vnode.getControlAddress())
+	}
+	return controlHosts, hasControlAddress
+}
+
 func (op *httpsCreateNodeOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
@@ -116,6 +136,10 @@ func (op *httpsCreateNodeOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *httpsCreateNodeOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsCreateNodeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
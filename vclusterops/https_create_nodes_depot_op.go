@@ -125,6 +125,10 @@ func (op *httpsCreateNodesDepotOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsCreateNodesDepotOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsCreateNodesDepotOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsCreateUserOp struct {
+	opBase
+	opHTTPSBase
+	newUserName string
+	ifNotExists bool
+	requestBody string
+	// set if the user exists already and ifNotExists is set
+	userExists *bool
+}
+
+type createUserRequestData struct {
+	Password string   `json:"password"`
+	Roles    []string `json:"roles,omitempty"`
+}
+
+// makeHTTPSCreateUserOp will make an op that calls the vertica-http service to
+// create a database user with a password, and grant roles to it
+func makeHTTPSCreateUserOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, newUserName string, newUserPassword *string, roles []string,
+	ifNotExists bool, userExists *bool) (httpsCreateUserOp, error) {
+	op := httpsCreateUserOp{}
+	op.name = "HTTPSCreateUserOp"
+	op.description = "Create database user"
+	op.hosts = hosts
+	op.newUserName = newUserName
+	op.ifNotExists = ifNotExists
+	op.userExists = userExists
+
+	if newUserPassword == nil {
+		return op, fmt.Errorf("[%s] should provide a password for the new user", op.name)
+	}
+	dataBytes, err := json.Marshal(createUserRequestData{Password: *newUserPassword, Roles: roles})
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.requestBody = string(dataBytes)
+
+	err = op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsCreateUserOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildHTTPSEndpoint(util.UsersEndpoint + op.newUserName)
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		httpRequest.RequestData = op.requestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsCreateUserOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsCreateUserOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsCreateUserOp) processResult(_ *opEngineExecContext) error {
+	// the request is sent to one host only
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
+				op.name, host)
+		}
+		if result.isConflict() && op.ifNotExists {
+			op.logger.PrintInfo("[%s] user %s already exists, it is not changed", op.name, op.newUserName)
+			if op.userExists != nil {
+				*op.userExists = true
+			}
+			return nil
+		}
+		if !result.isPassing() {
+			return result.err
+		}
+
+		/* decode the json-format response
+			The successful response object will be a dictionary like below:
+			{
+		  		"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+	}
+	return nil
+}
+
+func (op *httpsCreateUserOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
+func (op *httpsCreateUserOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -73,7 +73,7 @@ func (op *httpsDemoteSubclusterOp) setupClusterHTTPRequest(hosts []string) error
 func (op *httpsDemoteSubclusterOp) prepare(execContext *opEngineExecContext) error {
 	// If no hosts passed in, we will find the hosts from execute-context
 	if len(op.hosts) == 0 {
-		upHosts, err := getInitiatorHostInCluster(op.name, op.sandbox, op.scName, op.vdb)
+		upHosts, err := getInitiatorHostInCluster(op.name, op.sandbox, op.scName, op.vdb, execContext.initiators)
 		if err != nil {
 			return fmt.Errorf(`[%s] cannot find initial up hosts in the subcluster %s`, op.name, op.scName)
 		}
@@ -126,6 +126,10 @@ func (op *httpsDemoteSubclusterOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsDemoteSubclusterOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsDemoteSubclusterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
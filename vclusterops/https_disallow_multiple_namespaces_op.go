@@ -68,7 +68,7 @@ func (op *httpsDisallowMultipleNamespacesOp) setupClusterHTTPRequest(hosts []str
 }
 
 func (op *httpsDisallowMultipleNamespacesOp) prepare(execContext *opEngineExecContext) error {
-	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb)
+	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb, execContext.initiators)
 	if err != nil {
 		return err
 	}
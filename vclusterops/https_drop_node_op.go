@@ -96,6 +96,10 @@ func (op *httpsDropNodeOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsDropNodeOp) getClassification() OpClassification {
+	return destructiveClassification
+}
+
 func (op *httpsDropNodeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -102,6 +102,10 @@ func (op *httpsDropSubclusterOp) processResult(_ *opEngineExecContext) error {
 	return appendHTTPSFailureError(allErrs)
 }
 
+func (op *httpsDropSubclusterOp) getClassification() OpClassification {
+	return destructiveClassification
+}
+
 func (op *httpsDropSubclusterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,125 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsGetClusterHealthOp struct {
+	opBase
+	opHTTPSBase
+	// out parameter, the health of the cluster
+	health *ClusterHealth
+	// out parameter, whether any host responded, i.e., the database is running
+	isRunning *bool
+}
+
+func makeHTTPSGetClusterHealthOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, health *ClusterHealth, isRunning *bool) (httpsGetClusterHealthOp, error) {
+	op := httpsGetClusterHealthOp{}
+	op.name = "HTTPSGetClusterHealthOp"
+	op.description = "Check the health of the cluster"
+	op.hosts = hosts
+	op.health = health
+	op.isRunning = isRunning
+
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsGetClusterHealthOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("nodes")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetClusterHealthOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetClusterHealthOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsGetClusterHealthOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+// processResult computes the health of the cluster from the first host that
+// responds, as every up node reports all of the nodes. The database is not
+// running if no host responds.
+func (op *httpsGetClusterHealthOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		nodesStates := nodesStateInfo{}
+		if err := op.parseAndCheckResponse(host, result.content, &nodesStates); err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		*op.health = makeClusterHealth(&nodesStates)
+		*op.isRunning = true
+		return nil
+	}
+	op.logger.Info("no host responded to the health check", "errors", allErrs)
+	return nil
+}
+
+// makeClusterHealth finds the down nodes, and the up nodes whose catalog
+// version is behind the latest one among the up nodes
+func makeClusterHealth(nodesStates *nodesStateInfo) ClusterHealth {
+	health := ClusterHealth{TotalNodes: len(nodesStates.NodeList)}
+	var latestCatalogVersion int64
+	for _, node := range nodesStates.NodeList {
+		if node.State == util.NodeUpState && node.CatalogVersion > latestCatalogVersion {
+			latestCatalogVersion = node.CatalogVersion
+		}
+	}
+	for _, node := range nodesStates.NodeList {
+		if node.State == util.NodeDownState {
+			health.DownNodes = append(health.DownNodes, node.Name)
+		} else if node.State == util.NodeUpState && node.CatalogVersion < latestCatalogVersion {
+			health.UnsyncedNodes = append(health.UnsyncedNodes, node.Name)
+		}
+	}
+	sort.Strings(health.DownNodes)
+	sort.Strings(health.UnsyncedNodes)
+	return health
+}
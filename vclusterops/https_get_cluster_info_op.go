@@ -85,6 +85,7 @@ func (op *httpsGetClusterInfoOp) execute(execContext *opEngineExecContext) error
 type clusterStateInfo struct {
 	IsEon                    bool     `json:"is_eon"`
 	DBName                   string   `json:"db_name"`
+	DBUUID                   string   `json:"db_uuid"`
 	CommunalStorageLocations []string `json:"commnual_storage_locations"`
 }
 
@@ -111,6 +112,7 @@ func (op *httpsGetClusterInfoOp) processResult(_ *opEngineExecContext) error {
 			op.vdb.IsEon = clusterState.IsEon
 			op.vdb.UseDepot = clusterState.IsEon
 			op.vdb.Name = clusterState.DBName
+			op.vdb.UUID = clusterState.DBUUID
 			if op.vdb.Name != op.dbName {
 				err = fmt.Errorf(`[%s] database %s is running on host %s, rather than database %s`, op.name, op.vdb.Name, host, op.dbName)
 				allErrs = errors.Join(allErrs, err)
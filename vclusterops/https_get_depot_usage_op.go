@@ -0,0 +1,124 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+)
+
+type httpsGetDepotUsageOp struct {
+	opBase
+	opHTTPSBase
+	// out parameter, the depot usage of the up nodes
+	depots *[]depotUsage
+}
+
+// The content of the response should look like
+/* "depot_list": [
+	{
+	  "node_name": "v_practice_db_node0001",
+	  "max_size_bytes": 107374182400,
+	  "used_bytes": 53687091200
+	},
+	...
+  ]
+*/
+type depotUsageList struct {
+	DepotList []depotUsage `json:"depot_list"`
+}
+
+type depotUsage struct {
+	NodeName     string `json:"node_name"`
+	MaxSizeBytes int64  `json:"max_size_bytes"`
+	UsedBytes    int64  `json:"used_bytes"`
+}
+
+// percent returns how full the depot is, 0 for a depot without a size
+func (d *depotUsage) percent() float64 {
+	const percent = 100
+	if d.MaxSizeBytes <= 0 {
+		return 0
+	}
+	return percent * float64(d.UsedBytes) / float64(d.MaxSizeBytes)
+}
+
+func makeHTTPSGetDepotUsageOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, depots *[]depotUsage) (httpsGetDepotUsageOp, error) {
+	op := httpsGetDepotUsageOp{}
+	op.name = "HTTPSGetDepotUsageOp"
+	op.description = "Get the depot usage of the nodes"
+	op.hosts = hosts
+	op.depots = depots
+
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsGetDepotUsageOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("cluster/depot")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetDepotUsageOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetDepotUsageOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsGetDepotUsageOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+// processResult reads the depots from the first host that responds, as every
+// up node reports the depots of all the up nodes
+func (op *httpsGetDepotUsageOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		depots := depotUsageList{}
+		if err := op.parseAndCheckResponse(host, result.content, &depots); err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		*op.depots = depots.DepotList
+		return nil
+	}
+	return fmt.Errorf("[%s] fail to get the depot usage from any host, details: %w", op.name, allErrs)
+}
@@ -0,0 +1,118 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// httpsGetLicenseOp reads the limits of the license of a running database,
+// and what the database counts against them, for the ops that check the
+// license compliance of a new topology
+type httpsGetLicenseOp struct {
+	opBase
+	opHTTPSBase
+}
+
+type licenseStatus struct {
+	LicenseLimits
+	LicenseUsage
+}
+
+func makeHTTPSGetLicenseOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string) (httpsGetLicenseOp, error) {
+	op := httpsGetLicenseOp{}
+	op.name = "HTTPSGetLicenseOp"
+	op.description = "Get license limits"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+func (op *httpsGetLicenseOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("license")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetLicenseOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetLicenseOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsGetLicenseOp) processResult(execContext *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
+				op.name, host)
+		}
+
+		if result.isPassing() {
+			// the response object will be a dictionary, e.g.,:
+			// {"node_limit": 3, "cpu_limit": 0, "size_limit_bytes": 1099511627776,
+			//  "node_count": 3, "cpu_count": 6, "raw_data_size_bytes": 52428800}
+			status := licenseStatus{}
+			err := op.parseAndCheckResponse(host, result.content, &status)
+			if err != nil {
+				allErrs = errors.Join(allErrs, err)
+				return appendHTTPSFailureError(allErrs)
+			}
+			execContext.licenseStatus = &status
+			return nil
+		}
+		allErrs = errors.Join(allErrs, result.err)
+	}
+	return appendHTTPSFailureError(allErrs)
+}
+
+func (op *httpsGetLicenseOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
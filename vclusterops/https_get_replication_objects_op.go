@@ -0,0 +1,155 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type replicationObjectList struct {
+	ObjectList []string `json:"object_list"`
+}
+
+// httpsGetReplicationObjectsOp resolves the table or schema name and the
+// include and exclude patterns of a replication against the source catalog,
+// so that a replication that would copy nothing can be caught before it starts
+type httpsGetReplicationObjectsOp struct {
+	opBase
+	opHTTPSBase
+	sandbox string
+	vdb     *VCoordinationDatabase
+	// the objects to replicate, empty for the whole database
+	objectParams map[string]string
+	objects      *[]string
+}
+
+func makeHTTPSGetReplicationObjectsOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, sandbox string, replicationOptions *ReplicationOptions,
+	vdb *VCoordinationDatabase, objects *[]string) (httpsGetReplicationObjectsOp, error) {
+	op := httpsGetReplicationObjectsOp{}
+	op.name = "HTTPSGetReplicationObjectsOp"
+	op.description = "Resolve the objects to replicate"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+	op.sandbox = sandbox
+	op.vdb = vdb
+	op.objectParams = replicationObjectParams(replicationOptions)
+	op.objects = objects
+
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+
+	return op, nil
+}
+
+// replicationObjectParams returns the query parameters that select the
+// objects to replicate
+func replicationObjectParams(replicationOptions *ReplicationOptions) map[string]string {
+	objectParams := make(map[string]string)
+	if replicationOptions.TableOrSchemaName != "" {
+		objectParams["object_name"] = util.NormalizeObjectNamePattern(replicationOptions.TableOrSchemaName)
+	}
+	if len(replicationOptions.IncludePatterns) > 0 {
+		objectParams["include_pattern"] = joinReplicationPatterns(replicationOptions.IncludePatterns)
+	}
+	if len(replicationOptions.ExcludePatterns) > 0 {
+		objectParams["exclude_pattern"] = joinReplicationPatterns(replicationOptions.ExcludePatterns)
+	}
+	return objectParams
+}
+
+func (op *httpsGetReplicationObjectsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("replicate/objects")
+		httpRequest.QueryParams = op.objectParams
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetReplicationObjectsOp) prepare(execContext *opEngineExecContext) error {
+	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb, execContext.initiators)
+	if err != nil {
+		return err
+	}
+	// the objects are resolved on an up host of the source database or sandbox
+	op.hosts = sourceHost
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetReplicationObjectsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsGetReplicationObjectsOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return result.err
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		// the successful response lists the qualified names of the matched objects, e.g.,
+		/*
+			{
+				"object_list": ["public.t1", "public.t2", ".ns1.sales.orders"]
+			}
+		*/
+		objectList := replicationObjectList{}
+		err := op.parseAndCheckResponse(host, result.content, &objectList)
+		if err != nil {
+			return err
+		}
+		*op.objects = objectList.ObjectList
+		return nil
+	}
+
+	if allErrs == nil {
+		allErrs = fmt.Errorf("[%s] empty result received from the provided hosts %v", op.name, op.hosts)
+	}
+	return allErrs
+}
+
+func (op *httpsGetReplicationObjectsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
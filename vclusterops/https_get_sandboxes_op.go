@@ -0,0 +1,163 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsGetSandboxesOp struct {
+	opBase
+	opHTTPSBase
+	// the sandbox of each host, util.MainClusterSandbox for the main cluster
+	hostSandboxes map[string]string
+	sandboxes     *[]SandboxInfo
+}
+
+// makeHTTPSGetSandboxesOp will make an op that reads the sandboxes from the
+// catalog of the main cluster, and the current catalog version of each
+// sandbox from the catalog of the sandbox, using one up host of each
+func makeHTTPSGetSandboxesOp(hostSandboxes map[string]string, useHTTPPassword bool,
+	userName string, httpsPassword *string, sandboxes *[]SandboxInfo) (httpsGetSandboxesOp, error) {
+	op := httpsGetSandboxesOp{}
+	op.name = "HTTPSGetSandboxesOp"
+	op.description = "Get sandboxes from catalog"
+	op.hostSandboxes = hostSandboxes
+	for host := range hostSandboxes {
+		op.hosts = append(op.hosts, host)
+	}
+	op.sandboxes = sandboxes
+
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsGetSandboxesOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("sandboxes")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetSandboxesOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetSandboxesOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+type sandboxesResponse struct {
+	// the current catalog version of the cluster of the host
+	CatalogVersion int64 `json:"catalog_version"`
+	// the sandboxes, only in the catalog of the main cluster
+	Sandboxes []struct {
+		Name                    string    `json:"name"`
+		CreateTime              time.Time `json:"create_time"`
+		SandboxedCatalogVersion int64     `json:"sandboxed_catalog_version"`
+		Subclusters             []string  `json:"subclusters"`
+	} `json:"sandboxes"`
+}
+
+func (op *httpsGetSandboxesOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	// the sandboxes of the main cluster, and the catalog versions of the sandboxes
+	var mainResponse *sandboxesResponse
+	catalogVersions := make(map[string]int64)
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
+				op.name, host)
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		/* decode the json-format response
+		The successful response object will be a dictionary like below:
+		{
+			"catalog_version": 2051,
+			"sandboxes": [
+				{
+					"name": "sand1",
+					"create_time": "2024-05-01T10:02:03Z",
+					"sandboxed_catalog_version": 1876,
+					"subclusters": ["sc1"]
+				}
+			]
+		}
+		*/
+		response := sandboxesResponse{}
+		err := op.parseAndCheckResponse(host, result.content, &response)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		sandbox := op.hostSandboxes[host]
+		if sandbox == util.MainClusterSandbox {
+			mainResponse = &response
+		} else {
+			catalogVersions[sandbox] = response.CatalogVersion
+		}
+	}
+	if mainResponse == nil {
+		return errors.Join(allErrs, fmt.Errorf("[%s] fail to get the sandboxes from the main cluster", op.name))
+	}
+	if allErrs != nil {
+		// the catalog versions of the sandboxes that failed are unknown
+		op.logger.PrintWarning("[%s] fail to get the catalog versions of some sandboxes, details: %v", op.name, allErrs)
+	}
+
+	for _, s := range mainResponse.Sandboxes {
+		sandbox := SandboxInfo{
+			Name:                    s.Name,
+			CreateTime:              s.CreateTime,
+			SandboxedCatalogVersion: s.SandboxedCatalogVersion,
+			CatalogVersion:          catalogVersions[s.Name],
+		}
+		for _, scName := range s.Subclusters {
+			sandbox.Subclusters = append(sandbox.Subclusters, SandboxSubcluster{Name: scName})
+		}
+		*op.sandboxes = append(*op.sandboxes, sandbox)
+	}
+	return nil
+}
+
+func (op *httpsGetSandboxesOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
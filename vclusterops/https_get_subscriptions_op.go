@@ -0,0 +1,95 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+)
+
+type httpsGetSubscriptionsOp struct {
+	opBase
+	opHTTPSBase
+	// out parameter, the shard subscriptions of all the nodes
+	subscriptions *[]subscriptionInfo
+}
+
+func makeHTTPSGetSubscriptionsOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, subscriptions *[]subscriptionInfo) (httpsGetSubscriptionsOp, error) {
+	op := httpsGetSubscriptionsOp{}
+	op.name = "HTTPSGetSubscriptionsOp"
+	op.description = "Get the shard subscriptions"
+	op.hosts = hosts
+	op.subscriptions = subscriptions
+
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsGetSubscriptionsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildHTTPSEndpoint("subscriptions")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsGetSubscriptionsOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsGetSubscriptionsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsGetSubscriptionsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+// processResult reads the subscriptions from the first host that responds,
+// as every up node reports the subscriptions of all the nodes
+func (op *httpsGetSubscriptionsOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		subscriptions := subscriptionList{}
+		if err := op.parseAndCheckResponse(host, result.content, &subscriptions); err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		*op.subscriptions = subscriptions.SubscriptionList
+		return nil
+	}
+	return fmt.Errorf("[%s] fail to get the shard subscriptions from any host, details: %w", op.name, allErrs)
+}
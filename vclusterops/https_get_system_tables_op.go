@@ -67,7 +67,11 @@ func (op *httpsGetSystemTablesOp) setupClusterHTTPRequest(hosts []string) error
 }
 
 func (op *httpsGetSystemTablesOp) prepare(execContext *opEngineExecContext) error {
-	host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	host := getInitiatorFromUpHosts(execContext.upHosts, candidates)
 	if host == "" {
 		op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
 		op.skipExecute = true
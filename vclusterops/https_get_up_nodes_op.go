@@ -210,6 +210,9 @@ func isCompleteScanRequired(cmdType CmdType) bool {
 		cmdType == ManageConnectionDrainingCmd ||
 		cmdType == SetConfigurationParameterCmd ||
 		cmdType == GetConfigurationParameterCmd ||
+		cmdType == GetDataCollectorPoliciesCmd ||
+		cmdType == SetDataCollectorPolicyCmd ||
+		cmdType == ClearDataCollectorCmd ||
 		cmdType == GetDrainingStatusCmd ||
 		// need to find an up node from the sandbox if we're starting sandbox
 		// nodes, to handle identifying compute nodes in the sandbox
@@ -374,6 +377,9 @@ func (op *httpsGetUpNodesOp) requiresSandboxInfo() bool {
 	return op.cmdType == ManageConnectionDrainingCmd ||
 		op.cmdType == SetConfigurationParameterCmd ||
 		op.cmdType == GetConfigurationParameterCmd ||
+		op.cmdType == GetDataCollectorPoliciesCmd ||
+		op.cmdType == SetDataCollectorPolicyCmd ||
+		op.cmdType == ClearDataCollectorCmd ||
 		op.cmdType == StopDBCmd ||
 		op.cmdType == GetDrainingStatusCmd
 }
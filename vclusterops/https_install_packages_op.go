@@ -21,6 +21,7 @@ import (
 	"strconv"
 
 	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
 type httpsInstallPackagesOp struct {
@@ -91,6 +92,10 @@ func (op *httpsInstallPackagesOp) execute(execContext *opEngineExecContext) erro
 	return op.processResult(execContext)
 }
 
+func (op *httpsInstallPackagesOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsInstallPackagesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -114,19 +119,10 @@ structs, will look like this:
 }
 */
 
-// InstallPackageStatus provides status for each package install attempted.
-type InstallPackageStatus struct {
-	Packages []PackageStatus `json:"packages"`
-}
-
-// PackageStatus has install status for a single package.
-type PackageStatus struct {
-	// Name of the package this status is for
-	PackageName string `json:"package_name"`
-	// One word outcome of the install status:
-	// Skipped, Success or Failure
-	InstallStatus string `json:"install_status"`
-}
+type (
+	InstallPackageStatus = vtypes.InstallPackageStatus
+	PackageStatus        = vtypes.PackageStatus
+)
 
 func (op *httpsInstallPackagesOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
@@ -149,6 +149,10 @@ func (op *httpsMarkDesignKSafeOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsMarkDesignKSafeOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsMarkDesignKSafeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
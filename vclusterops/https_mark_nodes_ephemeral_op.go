@@ -97,6 +97,10 @@ func (op *httpsMarkEphemeralNodeOp) processResult(_ *opEngineExecContext) error
 	return allErrs
 }
 
+func (op *httpsMarkEphemeralNodeOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsMarkEphemeralNodeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
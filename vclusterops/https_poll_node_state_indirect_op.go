@@ -137,6 +137,7 @@ func (op *httpsPollNodeStateIndirectOp) prepare(execContext *opEngineExecContext
 		}
 		op.hosts = execContext.upHosts
 	}
+	op.httpRequestTimeout = execContext.adaptRequestTimeout(op.httpRequestTimeout)
 	execContext.dispatcher.setup(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
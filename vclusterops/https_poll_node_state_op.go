@@ -135,6 +135,7 @@ func (op *httpsPollNodeStateOp) prepare(execContext *opEngineExecContext) error
 		}
 	}
 
+	op.httpRequestTimeout = execContext.adaptRequestTimeout(op.httpRequestTimeout)
 	execContext.dispatcher.setup(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
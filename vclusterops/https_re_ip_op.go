@@ -20,19 +20,10 @@ import (
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
-// ReIPNoClusterQuorumError is an error to indicate
-// that cluster quorum was lost before a re-ip.
-// This is emitted from this op. Callers can do type checking to perform an
-// action based on the error.
-type ReIPNoClusterQuorumError struct {
-	Detail string
-}
-
-func (e *ReIPNoClusterQuorumError) Error() string {
-	return e.Detail
-}
+type ReIPNoClusterQuorumError = vtypes.ReIPNoClusterQuorumError
 
 type httpsReIPOp struct {
 	opBase
@@ -178,6 +169,10 @@ func (op *httpsReIPOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsReIPOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsReIPOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -123,6 +123,10 @@ func (op *httpsRebalanceClusterOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsRebalanceClusterOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsRebalanceClusterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
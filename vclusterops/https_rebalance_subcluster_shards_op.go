@@ -130,6 +130,10 @@ func (op *httpsRebalanceSubclusterShardsOp) processResult(_ *opEngineExecContext
 	return allErrs
 }
 
+func (op *httpsRebalanceSubclusterShardsOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsRebalanceSubclusterShardsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,114 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+)
+
+type httpsRefreshSandboxOp struct {
+	opBase
+	opHTTPSBase
+	sandbox string
+	// set to false if the database cannot refresh the sandbox incrementally
+	supported *bool
+}
+
+// makeHTTPSRefreshSandboxOp will make an op that calls the vertica-http
+// service of the main cluster to bring the data of a sandbox up to date
+func makeHTTPSRefreshSandboxOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, sandbox string, supported *bool) (httpsRefreshSandboxOp, error) {
+	op := httpsRefreshSandboxOp{}
+	op.name = "HTTPSRefreshSandboxOp"
+	op.description = "Refresh sandbox from main cluster"
+	op.hosts = hosts
+	op.sandbox = sandbox
+	op.supported = supported
+
+	err := op.validateAndSetUsernameAndPassword(op.name, useHTTPPassword, userName, httpsPassword)
+	return op, err
+}
+
+func (op *httpsRefreshSandboxOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildHTTPSEndpoint("sandboxes/" + op.sandbox + "/refresh")
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsRefreshSandboxOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsRefreshSandboxOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsRefreshSandboxOp) processResult(_ *opEngineExecContext) error {
+	// the request is sent to one host of the main cluster only
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
+				op.name, host)
+		}
+		if result.isNotFound() {
+			// the caller decides whether to recreate the sandbox
+			op.logger.PrintInfo("[%s] host %s cannot refresh sandbox %s incrementally", op.name, host, op.sandbox)
+			*op.supported = false
+			return nil
+		}
+		if !result.isPassing() {
+			return result.err
+		}
+
+		/* decode the json-format response
+			The successful response object will be a dictionary like below:
+			{
+		  		"detail": ""
+			}
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+		*op.supported = true
+	}
+	return nil
+}
+
+func (op *httpsRefreshSandboxOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
+func (op *httpsRefreshSandboxOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -114,6 +114,10 @@ func (op *httpsReloadSpreadOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsReloadSpreadOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsReloadSpreadOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
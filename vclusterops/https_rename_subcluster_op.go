@@ -161,6 +161,10 @@ func (op *httpsRenameSubclusterOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsRenameSubclusterOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsRenameSubclusterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -157,6 +157,10 @@ func (op *httpsSandboxingOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsSandboxingOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsSandboxingOp) finalize(execContext *opEngineExecContext) error {
 	for _, vnode := range execContext.scNodesInfo {
 		*op.sbHosts = append(*op.sbHosts, vnode.Address)
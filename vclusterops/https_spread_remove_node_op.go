@@ -111,6 +111,10 @@ func (op *httpsSpreadRemoveNodeOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsSpreadRemoveNodeOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsSpreadRemoveNodeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
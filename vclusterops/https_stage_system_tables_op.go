@@ -193,7 +193,11 @@ func (op *httpsStageSystemTablesOp) setupClusterHTTPRequest(hosts []string, sche
 }
 
 func (op *httpsStageSystemTablesOp) prepare(execContext *opEngineExecContext) error {
-	host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	host := getInitiatorFromUpHosts(execContext.upHosts, candidates)
 	if host == "" {
 		op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
 		op.skipExecute = true
@@ -259,6 +263,10 @@ func (op *httpsStageSystemTablesOp) processResult(_ *opEngineExecContext) error
 	return allErrs
 }
 
+func (op *httpsStageSystemTablesOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsStageSystemTablesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -30,15 +30,25 @@ type httpsStartReplicationOp struct {
 	hostRequestBodyMap map[string]string
 	sourceDB           string
 	targetHost         string
+	targetRole         string
+	targetPool         string
 	sandbox            string
 	tlsConfig          string
+	targetTLSConfig    string
+	epoch              *int64
+	atTime             string
+	objectRenames      map[string]string
+	sourceSnapshot     string
+	projectionMode     string
 	vdb                *VCoordinationDatabase
+	// set to the source epoch that the replication copies data as of, if reported
+	replicatedEpoch *int64
 }
 
 func makeHTTPSStartReplicationOp(dbName string, sourceHosts []string,
 	sourceUseHTTPPassword bool, sourceUserName string,
 	sourceHTTPPassword *string, targetUseHTTPPassword bool, targetDBOpt *DatabaseOptions,
-	targetHost string, tlsConfig, sandbox string, vdb *VCoordinationDatabase) (httpsStartReplicationOp, error) {
+	targetHost, targetRole, targetPool, tlsConfig, sandbox string, vdb *VCoordinationDatabase) (httpsStartReplicationOp, error) {
 	op := httpsStartReplicationOp{}
 	op.name = "HTTPSStartReplicationOp"
 	op.description = "Start database replication"
@@ -47,6 +57,8 @@ func makeHTTPSStartReplicationOp(dbName string, sourceHosts []string,
 	op.useHTTPPassword = sourceUseHTTPPassword
 	op.TargetDB.DBName = targetDBOpt.DBName
 	op.targetHost = targetHost
+	op.targetRole = targetRole
+	op.targetPool = targetPool
 	op.tlsConfig = tlsConfig
 	op.sandbox = sandbox
 	op.vdb = vdb
@@ -76,7 +88,27 @@ type replicateRequestData struct {
 	TargetDB       string  `json:"dbname"`
 	TargetUserName string  `json:"user,omitempty"`
 	TargetPassword *string `json:"password,omitempty"`
+	TargetRole     string  `json:"role,omitempty"`
+	TargetPool     string  `json:"resource_pool,omitempty"`
 	TLSConfig      string  `json:"tls_config,omitempty"`
+	SourceSnapshot string  `json:"snapshot,omitempty"`
+	ProjectionMode string  `json:"projection_mode,omitempty"`
+	// the TLS configuration of the target database, if it differs from the
+	// source one in TLSConfig
+	TargetTLSConfig string `json:"target_tls_config,omitempty"`
+	// the point in time to replicate the source database as of, if any
+	Epoch  *int64 `json:"epoch,omitempty"`
+	AtTime string `json:"at_time,omitempty"`
+	// the source objects to replicate under another name in the target database
+	ObjectRenames map[string]string `json:"object_renames,omitempty"`
+}
+
+type startReplicationResponse struct {
+	Detail string `json:"detail"`
+	// the source epoch that the replication copies data as of
+	Epoch *int64 `json:"epoch"`
+	// the objects that the replication does not copy
+	SkippedObjects []ReplicationSkippedObject `json:"skipped_objects"`
 }
 
 func (op *httpsStartReplicationOp) setupRequestBody(hosts []string) error {
@@ -88,7 +120,15 @@ func (op *httpsStartReplicationOp) setupRequestBody(hosts []string) error {
 		replicateData.TargetDB = op.TargetDB.DBName
 		replicateData.TargetUserName = op.TargetDB.UserName
 		replicateData.TargetPassword = op.TargetDB.Password
+		replicateData.TargetRole = op.targetRole
+		replicateData.TargetPool = op.targetPool
 		replicateData.TLSConfig = op.tlsConfig
+		replicateData.TargetTLSConfig = op.targetTLSConfig
+		replicateData.Epoch = op.epoch
+		replicateData.AtTime = op.atTime
+		replicateData.ObjectRenames = op.objectRenames
+		replicateData.SourceSnapshot = op.sourceSnapshot
+		replicateData.ProjectionMode = op.projectionMode
 
 		dataBytes, err := json.Marshal(replicateData)
 		if err != nil {
@@ -117,7 +157,7 @@ func (op *httpsStartReplicationOp) setupClusterHTTPRequest(hosts []string) error
 }
 
 func (op *httpsStartReplicationOp) prepare(execContext *opEngineExecContext) error {
-	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb)
+	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb, execContext.initiators)
 	if err != nil {
 		return err
 	}
@@ -140,7 +180,7 @@ func (op *httpsStartReplicationOp) execute(execContext *opEngineExecContext) err
 	return op.processResult(execContext)
 }
 
-func (op *httpsStartReplicationOp) processResult(_ *opEngineExecContext) error {
+func (op *httpsStartReplicationOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
@@ -157,8 +197,11 @@ func (op *httpsStartReplicationOp) processResult(_ *opEngineExecContext) error {
 
 		// decode the json-format response
 		// The successful response object will be a dictionary as below:
-		// {"detail": "REPLICATE"}
-		startRepRsp, err := op.parseAndCheckMapResponse(host, result.content)
+		// {"detail": "REPLICATE", "epoch": 12345,
+		//  "skipped_objects": [{"type": "table", "name": "public.t1", "reason": "permission_denied"}]}
+		// where the epoch and the skipped objects are only reported by newer versions
+		var startRepRsp startReplicationResponse
+		err := op.parseAndCheckResponse(host, result.content, &startRepRsp)
 		if err != nil {
 			err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
 			allErrs = errors.Join(allErrs, err)
@@ -167,15 +210,24 @@ func (op *httpsStartReplicationOp) processResult(_ *opEngineExecContext) error {
 
 		// verify if the response's content is correct
 		const startReplicationOpSuccMsg = "REPLICATE"
-		if startRepRsp["detail"] != startReplicationOpSuccMsg {
-			err = fmt.Errorf(`[%s] response detail should be '%s' but got '%s'`, op.name, startReplicationOpSuccMsg, startRepRsp["detail"])
+		if startRepRsp.Detail != startReplicationOpSuccMsg {
+			err = fmt.Errorf(`[%s] response detail should be '%s' but got '%s'`, op.name, startReplicationOpSuccMsg, startRepRsp.Detail)
 			allErrs = errors.Join(allErrs, err)
+			continue
 		}
+		if startRepRsp.Epoch != nil && op.replicatedEpoch != nil {
+			*op.replicatedEpoch = *startRepRsp.Epoch
+		}
+		execContext.skippedObjects = append(execContext.skippedObjects, startRepRsp.SkippedObjects...)
 	}
 
 	return allErrs
 }
 
+func (op *httpsStartReplicationOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsStartReplicationOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
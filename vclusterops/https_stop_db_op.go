@@ -186,6 +186,10 @@ func (op *httpsStopDBOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsStopDBOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsStopDBOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -159,6 +159,10 @@ func (op *httpsStopNodeOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsStopNodeOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsStopNodeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -33,7 +33,7 @@ type httpsStopSCOp struct {
 }
 
 func makeHTTPSStopSCOp(useHTTPPassword bool, userName string,
-	httpsPassword *string, scName string, timeout int, force bool) (httpsStopSCOp, error) {
+	httpsPassword *string, scName string, timeout int, force, clearDepot bool) (httpsStopSCOp, error) {
 	op := httpsStopSCOp{}
 	op.name = "HTTPSStopSCOp"
 	op.description = "Stop subcluster"
@@ -44,10 +44,14 @@ func makeHTTPSStopSCOp(useHTTPPassword bool, userName string,
 	// set the query params
 	// If this is a force shutdown, we do not set "timeout" to make a shutdown without draining.
 	// Otherwise, we set "timeout" to make a shutdown with draining.
+	op.requestParams = make(map[string]string)
 	if !op.force {
-		op.requestParams = make(map[string]string)
 		op.requestParams["timeout"] = strconv.Itoa(timeout)
 	}
+	// the depot of the subcluster is kept unless it is cleared before the shutdown
+	if clearDepot {
+		op.requestParams["clear_depot"] = "true"
+	}
 
 	if useHTTPPassword {
 		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
@@ -155,6 +159,10 @@ func (op *httpsStopSCOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsStopSCOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsStopSCOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
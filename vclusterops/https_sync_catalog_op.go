@@ -131,6 +131,10 @@ func (op *httpsSyncCatalogOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsSyncCatalogOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *httpsSyncCatalogOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
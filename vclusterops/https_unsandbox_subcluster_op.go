@@ -145,6 +145,10 @@ func (op *httpsUnsandboxingOp) processResult(_ *opEngineExecContext) error {
 	return allErrs
 }
 
+func (op *httpsUnsandboxingOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *httpsUnsandboxingOp) finalize(execContext *opEngineExecContext) error {
 	*op.scHosts = []string{}
 	for _, vnode := range execContext.scNodesInfo {
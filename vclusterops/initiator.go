@@ -0,0 +1,74 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// pinInitiators returns the candidate initiators that are among the pinned
+// hosts, in the order of the pinned hosts, or all candidates if no hosts are
+// pinned. It fails if none of the pinned hosts is a candidate, as the commands
+// must not send their control requests through any other host.
+func pinInitiators(candidates, pinned []string) ([]string, error) {
+	if len(pinned) == 0 {
+		return candidates, nil
+	}
+	var hosts []string
+	for _, host := range pinned {
+		if util.StringInArray(host, candidates) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("none of the pinned initiators %v is among the eligible hosts %v", pinned, candidates)
+	}
+	return hosts, nil
+}
+
+// pickInitiator returns the initiator among the hosts, which must be one of
+// the pinned initiators of the options if they are set
+func (opt *DatabaseOptions) pickInitiator(hosts []string) (string, error) {
+	candidates, err := pinInitiators(hosts, opt.Initiators)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no hosts to pick an initiator from")
+	}
+	return getInitiator(candidates), nil
+}
+
+func (opt *DatabaseOptions) validateInitiators() error {
+	for _, host := range opt.Initiators {
+		if err := util.AddressCheck(host, opt.IPv6); err != nil {
+			return fmt.Errorf("invalid pinned initiator: %w", err)
+		}
+	}
+	return nil
+}
+
+func (opt *DatabaseOptions) getInitiators() []string {
+	return opt.Initiators
+}
+
+// initiatorCandidates returns the hosts that the ops may pick their initiator
+// from, i.e., the pinned initiators of the command among the hosts
+func (execContext *opEngineExecContext) initiatorCandidates(hosts []string) ([]string, error) {
+	return pinInitiators(hosts, execContext.initiators)
+}
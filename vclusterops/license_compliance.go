@@ -0,0 +1,161 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+	"golang.org/x/exp/slices"
+)
+
+type (
+	LicenseLimits             = vtypes.LicenseLimits
+	LicenseUsage              = vtypes.LicenseUsage
+	LicenseLimitExceededError = vtypes.LicenseLimitExceededError
+)
+
+// the limits of a license that a LicenseLimitExceededError can report
+const (
+	LicenseNodeLimit = vtypes.LicenseNodeLimit
+	LicenseCPULimit  = vtypes.LicenseCPULimit
+	LicenseSizeLimit = vtypes.LicenseSizeLimit
+)
+
+type VCheckLicenseComplianceOptions struct {
+	// basic db info, Hosts are the hosts of the running database
+	DatabaseOptions
+	// hosts that would be added to the database
+	NewHosts []string
+}
+
+func VCheckLicenseComplianceOptionsFactory() VCheckLicenseComplianceOptions {
+	options := VCheckLicenseComplianceOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VCheckLicenseComplianceOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(CheckLicenseComplianceCmd, logger)
+	if err != nil {
+		return err
+	}
+	if len(options.NewHosts) == 0 {
+		return errors.New("must specify the hosts that would be added")
+	}
+	return nil
+}
+
+func (options *VCheckLicenseComplianceOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	options.NewHosts, err = util.ResolveRawHostsToAddresses(options.NewHosts, options.IPv6)
+	if err != nil {
+		return err
+	}
+	for _, host := range options.NewHosts {
+		if slices.Contains(options.Hosts, host) {
+			return fmt.Errorf("%s is already a host of the database", host)
+		}
+	}
+	return nil
+}
+
+func (options *VCheckLicenseComplianceOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+// VCheckLicenseCompliance checks whether the license of a running database
+// allows to add the new hosts, which add_node also checks before it changes
+// the database. It fails with a LicenseLimitExceededError that tells the
+// limit that would be exceeded.
+func (vcc VClusterCommands) VCheckLicenseCompliance(options *VCheckLicenseComplianceOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	nmaHealthOp := makeNMAHealthOp(options.NewHosts)
+	instructions := []clusterOp{&nmaHealthOp}
+	licenseCheckOps, err := makeLicenseCheckOps(options.Hosts, options.NewHosts,
+		options.usePassword, options.UserName, options.Password)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	instructions = append(instructions, licenseCheckOps...)
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to check license compliance: %w", runError)
+	}
+	return nil
+}
+
+// makeLicenseCheckOps returns the ops that check that the license of a running
+// database allows to add the new hosts
+func makeLicenseCheckOps(hosts, newHosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string) ([]clusterOp, error) {
+	httpsGetLicenseOp, err := makeHTTPSGetLicenseOp(hosts, useHTTPPassword, userName, httpsPassword)
+	if err != nil {
+		return nil, err
+	}
+	nmaCheckLicenseComplianceOp, err := makeNMACheckLicenseComplianceOp(newHosts, "" /*license path*/)
+	if err != nil {
+		return nil, err
+	}
+	return []clusterOp{&httpsGetLicenseOp, &nmaCheckLicenseComplianceOp}, nil
+}
+
+// checkLicenseCompliance returns a LicenseLimitExceededError for the first
+// limit of the license that the usage exceeds
+func checkLicenseCompliance(limits LicenseLimits, usage LicenseUsage) error {
+	if limits.NodeLimit > 0 && usage.NodeCount > limits.NodeLimit {
+		return &LicenseLimitExceededError{Limit: LicenseNodeLimit,
+			Allowed: int64(limits.NodeLimit), Required: int64(usage.NodeCount)}
+	}
+	if limits.CPULimit > 0 && usage.CPUCount > limits.CPULimit {
+		return &LicenseLimitExceededError{Limit: LicenseCPULimit,
+			Allowed: int64(limits.CPULimit), Required: int64(usage.CPUCount)}
+	}
+	if limits.SizeLimitBytes > 0 && usage.RawDataSizeBytes > limits.SizeLimitBytes {
+		return &LicenseLimitExceededError{Limit: LicenseSizeLimit,
+			Allowed: limits.SizeLimitBytes, Required: usage.RawDataSizeBytes}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestCheckLicenseCompliance(t *testing.T) {
+	limits := LicenseLimits{NodeLimit: 3, CPULimit: 8, SizeLimitBytes: 1024}
+	assert.NoError(t, checkLicenseCompliance(limits, LicenseUsage{NodeCount: 3, CPUCount: 8, RawDataSizeBytes: 1024}))
+	// 0 means unlimited
+	assert.NoError(t, checkLicenseCompliance(LicenseLimits{}, LicenseUsage{NodeCount: 100, CPUCount: 200}))
+
+	err := checkLicenseCompliance(limits, LicenseUsage{NodeCount: 4, CPUCount: 10})
+	limitErr := &LicenseLimitExceededError{}
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, LicenseLimitExceededError{Limit: LicenseNodeLimit, Allowed: 3, Required: 4}, *limitErr)
+	assert.EqualError(t, err, "the license allows 3 nodes, but the database would have 4")
+
+	err = checkLicenseCompliance(limits, LicenseUsage{NodeCount: 3, CPUCount: 10})
+	assert.EqualError(t, err, "the license allows 8 CPU sockets, but the database would have 10")
+	err = checkLicenseCompliance(limits, LicenseUsage{NodeCount: 3, RawDataSizeBytes: 2048})
+	assert.EqualError(t, err, "the license allows 1024 bytes of raw data, but the database would have 2048")
+}
+
+func TestNMACheckLicenseComplianceOp(t *testing.T) {
+	newHosts := []string{"192.168.1.104", "192.168.1.105"}
+	results := map[string]hostHTTPResult{
+		newHosts[0]: {status: SUCCESS, content: `{"cpu_sockets": 2, "node_limit": 3}`},
+		newHosts[1]: {status: SUCCESS, content: `{"cpu_sockets": 2, "node_limit": 3}`},
+	}
+
+	// create_db: the limits are read from the license file on the hosts
+	op, err := makeNMACheckLicenseComplianceOp(newHosts, "/opt/vertica/config/license.key")
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	assert.JSONEq(t, `{"license_path": "/opt/vertica/config/license.key"}`,
+		op.clusterHTTPRequest.RequestCollection[newHosts[0]].RequestData)
+	assert.False(t, op.getClassification().Mutating)
+	op.clusterHTTPRequest.ResultCollection = results
+	assert.NoError(t, op.processResult(&opEngineExecContext{}))
+
+	// add_node: the limits and usage are read from the running database
+	op, err = makeNMACheckLicenseComplianceOp(newHosts, "")
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.ResultCollection = results
+	execContext := opEngineExecContext{licenseStatus: &licenseStatus{
+		LicenseLimits: LicenseLimits{NodeLimit: 4},
+		LicenseUsage:  LicenseUsage{NodeCount: 3, CPUCount: 6},
+	}}
+	err = op.processResult(&execContext)
+	assert.EqualError(t, err, "the license allows 4 nodes, but the database would have 5")
+
+	execContext.licenseStatus.LicenseLimits = LicenseLimits{NodeLimit: 5, CPULimit: 8}
+	err = op.processResult(&execContext)
+	assert.EqualError(t, err, "the license allows 8 CPU sockets, but the database would have 10")
+}
+
+func TestVCheckLicenseComplianceOptions(t *testing.T) {
+	logger := vlog.Printer{}
+
+	opt := VCheckLicenseComplianceOptionsFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"192.168.1.101"}
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "must specify the hosts that would be added")
+
+	opt.NewHosts = []string{"192.168.1.101"}
+	assert.NoError(t, opt.validateParseOptions(logger))
+	assert.ErrorContains(t, opt.analyzeOptions(), "192.168.1.101 is already a host of the database")
+
+	opt.NewHosts = []string{"192.168.1.102"}
+	assert.NoError(t, opt.analyzeOptions())
+}
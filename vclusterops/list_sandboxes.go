@@ -0,0 +1,184 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	SandboxInfo       = vtypes.SandboxInfo
+	SandboxSubcluster = vtypes.SandboxSubcluster
+	SandboxNode       = vtypes.SandboxNode
+)
+
+type VListSandboxesOptions struct {
+	DatabaseOptions
+}
+
+func VListSandboxesOptionsFactory() VListSandboxesOptions {
+	options := VListSandboxesOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VListSandboxesOptions) validateParseOptions(logger vlog.Printer) error {
+	return options.validateBaseOptions(ListSandboxesCmd, logger)
+}
+
+func (options *VListSandboxesOptions) analyzeOptions() (err error) {
+	// resolve RawHosts to be IP addresses
+	if len(options.RawHosts) > 0 {
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VListSandboxesOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VListSandboxes returns the sandboxes of a database, sorted by name, with
+// their subclusters and nodes. The sandboxes and their catalog versions are
+// read from the catalogs of the main cluster and of the sandboxes.
+func (vcc VClusterCommands) VListSandboxes(options *VListSandboxesOptions) ([]SandboxInfo, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	vdb := makeVCoordinationDatabase()
+	return vcc.listSandboxes(&options.DatabaseOptions, &vdb)
+}
+
+// listSandboxes returns the sandboxes of a database, and fills vdb with the
+// nodes of the main cluster and of the sandboxes
+func (vcc VClusterCommands) listSandboxes(options *DatabaseOptions, vdb *VCoordinationDatabase) ([]SandboxInfo, error) {
+	err := vcc.getVDBFromMainRunningDBContainsSandbox(vdb, options)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxes := []SandboxInfo{}
+	instructions, err := vcc.produceListSandboxesInstructions(options, vdb, &sandboxes)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return nil, fmt.Errorf("fail to list sandboxes: %w", runError)
+	}
+
+	addSandboxNodes(sandboxes, vdb)
+	return sandboxes, nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful list_sandboxes:
+//   - Get the sandboxes from an up host of the main cluster, and the catalog
+//     version of each sandbox from one of its up hosts
+func (vcc VClusterCommands) produceListSandboxesInstructions(options *DatabaseOptions,
+	vdb *VCoordinationDatabase, sandboxes *[]SandboxInfo) ([]clusterOp, error) {
+	err := options.setUsePassword(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	hostSandboxes := getUpHostOfEachCluster(vdb)
+	httpsGetSandboxesOp, err := makeHTTPSGetSandboxesOp(hostSandboxes, options.usePassword,
+		options.UserName, options.Password, sandboxes)
+	if err != nil {
+		return nil, err
+	}
+	return []clusterOp{&httpsGetSandboxesOp}, nil
+}
+
+// getUpHostOfEachCluster picks an up host of the main cluster and of each
+// sandbox, and returns the cluster of each picked host
+func getUpHostOfEachCluster(vdb *VCoordinationDatabase) map[string]string {
+	hostSandboxes := make(map[string]string)
+	pickedSandboxes := make(map[string]bool)
+	for _, host := range vdb.HostList {
+		vnode := vdb.HostNodeMap[host]
+		if vnode.State != util.NodeUpState || pickedSandboxes[vnode.Sandbox] {
+			continue
+		}
+		pickedSandboxes[vnode.Sandbox] = true
+		hostSandboxes[host] = vnode.Sandbox
+	}
+	return hostSandboxes
+}
+
+// addSandboxNodes adds the nodes of vdb to the subclusters of the sandboxes,
+// and sorts the sandboxes, subclusters and nodes by name
+func addSandboxNodes(sandboxes []SandboxInfo, vdb *VCoordinationDatabase) {
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Sandbox == util.MainClusterSandbox {
+			continue
+		}
+		for i := range sandboxes {
+			if sandboxes[i].Name != vnode.Sandbox {
+				continue
+			}
+			for j := range sandboxes[i].Subclusters {
+				subcluster := &sandboxes[i].Subclusters[j]
+				if subcluster.Name == vnode.Subcluster {
+					subcluster.Nodes = append(subcluster.Nodes, SandboxNode{
+						Name:    vnode.Name,
+						Address: vnode.Address,
+						State:   vnode.State,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(sandboxes, func(i, j int) bool {
+		return sandboxes[i].Name < sandboxes[j].Name
+	})
+	for i := range sandboxes {
+		subclusters := sandboxes[i].Subclusters
+		sort.Slice(subclusters, func(j, k int) bool {
+			return subclusters[j].Name < subclusters[k].Name
+		})
+		for j := range subclusters {
+			nodes := subclusters[j].Nodes
+			sort.Slice(nodes, func(k, l int) bool {
+				return nodes[k].Name < nodes[l].Name
+			})
+		}
+	}
+}
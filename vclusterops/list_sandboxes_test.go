@@ -0,0 +1,85 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestListSandboxes(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	nodes := []VCoordinationNode{
+		{Name: "v_db_node0001", Address: "192.168.1.101", Subcluster: "default", State: util.NodeUpState},
+		{Name: "v_db_node0003", Address: "192.168.1.103", Subcluster: "sc1", Sandbox: "sand1", State: util.NodeDownState},
+		{Name: "v_db_node0002", Address: "192.168.1.102", Subcluster: "sc1", Sandbox: "sand1", State: util.NodeUpState},
+		{Name: "v_db_node0004", Address: "192.168.1.104", Subcluster: "sc2", Sandbox: "sand2", State: util.NodeDownState},
+	}
+	for i := range nodes {
+		vdb.HostList = append(vdb.HostList, nodes[i].Address)
+		vdb.HostNodeMap[nodes[i].Address] = &nodes[i]
+	}
+
+	// one up host of the main cluster and of each sandbox
+	hostSandboxes := getUpHostOfEachCluster(&vdb)
+	assert.Equal(t, map[string]string{"192.168.1.101": "", "192.168.1.102": "sand1"}, hostSandboxes)
+
+	sandboxes := []SandboxInfo{}
+	op, err := makeHTTPSGetSandboxesOp(hostSandboxes, false, "", nil, &sandboxes)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"catalog_version": 2051, "sandboxes": [
+			{"name": "sand2", "create_time": "2024-05-02T10:00:00Z", "sandboxed_catalog_version": 1990, "subclusters": ["sc2"]},
+			{"name": "sand1", "create_time": "2024-05-01T10:00:00Z", "sandboxed_catalog_version": 1876, "subclusters": ["sc1"]}]}`},
+		"192.168.1.102": {status: SUCCESS, content: `{"catalog_version": 1902, "sandboxes": []}`},
+	}
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	assert.NoError(t, op.processResult(&execContext))
+
+	addSandboxNodes(sandboxes, &vdb)
+	assert.Equal(t, []SandboxInfo{
+		{
+			Name:                    "sand1",
+			CreateTime:              time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			SandboxedCatalogVersion: 1876,
+			CatalogVersion:          1902,
+			Subclusters: []SandboxSubcluster{{Name: "sc1", Nodes: []SandboxNode{
+				{Name: "v_db_node0002", Address: "192.168.1.102", State: util.NodeUpState},
+				{Name: "v_db_node0003", Address: "192.168.1.103", State: util.NodeDownState},
+			}}},
+		},
+		{
+			// no node of the sandbox is up
+			Name:                    "sand2",
+			CreateTime:              time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+			SandboxedCatalogVersion: 1990,
+			Subclusters: []SandboxSubcluster{{Name: "sc2", Nodes: []SandboxNode{
+				{Name: "v_db_node0004", Address: "192.168.1.104", State: util.NodeDownState},
+			}}},
+		},
+	}, sandboxes)
+
+	// negative: the main cluster does not respond
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.102": {status: SUCCESS, content: `{"catalog_version": 1902, "sandboxes": []}`},
+	}
+	assert.ErrorContains(t, op.processResult(&execContext), "fail to get the sandboxes from the main cluster")
+}
@@ -0,0 +1,180 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const hoursPerDay = 24
+
+// MaintenanceWindow is a recurring period of time in which the cluster can be
+// changed, e.g., Saturday and Sunday from 01:00 to 05:00 in America/New_York
+type MaintenanceWindow struct {
+	// days on which the window opens, every day if empty
+	Weekdays []time.Weekday
+	// offset from midnight at which the window opens
+	Start time.Duration
+	// length of the window, at most 24 hours
+	Duration time.Duration
+	// time zone of the window, UTC if nil
+	Location *time.Location
+}
+
+func (w *MaintenanceWindow) validate() error {
+	if w.Start < 0 || w.Start >= hoursPerDay*time.Hour {
+		return fmt.Errorf("the start of a maintenance window must be within a day, got %s", w.Start)
+	}
+	if w.Duration <= 0 || w.Duration > hoursPerDay*time.Hour {
+		return fmt.Errorf("the duration of a maintenance window must be positive and at most 24h, got %s", w.Duration)
+	}
+	return nil
+}
+
+func (w *MaintenanceWindow) opensOn(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range w.Weekdays {
+		if weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+// getOpenings returns the openings of the window from the day before t to
+// 7 days after it, which covers a window opened on the day before that is
+// still open at t and the next opening of a weekly window
+func (w *MaintenanceWindow) getOpenings(t time.Time) []time.Time {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	var openings []time.Time
+	const daysToCheck = 8
+	for i := -1; i < daysToCheck; i++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, loc)
+		if w.opensOn(day.Weekday()) {
+			openings = append(openings, day.Add(w.Start))
+		}
+	}
+	return openings
+}
+
+// Contains returns whether the window is open at t
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	for _, opening := range w.getOpenings(t) {
+		if !t.Before(opening) && t.Before(opening.Add(w.Duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpening returns the first time after t at which the window opens
+func (w *MaintenanceWindow) NextOpening(t time.Time) (time.Time, bool) {
+	for _, opening := range w.getOpenings(t) {
+		if opening.After(t) {
+			return opening, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// MaintenancePolicy restricts the commands that change the cluster to its
+// maintenance windows. It is checked by the op engine before the first op
+// that changes the cluster, so that commands that only read are never held up.
+type MaintenancePolicy struct {
+	Windows []MaintenanceWindow
+	// how long to wait for the next window to open when a command starts
+	// outside of the windows; the command is rejected right away if 0
+	MaxDelay time.Duration
+	// returns the current time, time.Now if nil
+	now func() time.Time
+	// waits for d, time.Sleep if nil
+	sleep func(d time.Duration)
+}
+
+type OutsideMaintenanceWindowError = vtypes.OutsideMaintenanceWindowError
+
+func (policy *MaintenancePolicy) validate() error {
+	if len(policy.Windows) == 0 {
+		return fmt.Errorf("a maintenance policy must have at least one window")
+	}
+	for i := range policy.Windows {
+		if err := policy.Windows[i].validate(); err != nil {
+			return err
+		}
+	}
+	if policy.MaxDelay < 0 {
+		return fmt.Errorf("the max delay of a maintenance policy cannot be negative, got %s", policy.MaxDelay)
+	}
+	return nil
+}
+
+func (policy *MaintenancePolicy) getNow() time.Time {
+	if policy.now != nil {
+		return policy.now()
+	}
+	return time.Now()
+}
+
+// getNextOpening returns the earliest time after t at which a window opens
+func (policy *MaintenancePolicy) getNextOpening(t time.Time) time.Time {
+	var next time.Time
+	for i := range policy.Windows {
+		opening, ok := policy.Windows[i].NextOpening(t)
+		if ok && (next.IsZero() || opening.Before(next)) {
+			next = opening
+		}
+	}
+	return next
+}
+
+// waitForWindow returns once a window is open, waiting up to MaxDelay for the
+// next one to open. It returns an OutsideMaintenanceWindowError if none does.
+func (policy *MaintenancePolicy) waitForWindow(logger vlog.Printer, opName string) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	now := policy.getNow()
+	for i := range policy.Windows {
+		if policy.Windows[i].Contains(now) {
+			return nil
+		}
+	}
+
+	next := policy.getNextOpening(now)
+	if next.IsZero() || next.Sub(now) > policy.MaxDelay {
+		return &OutsideMaintenanceWindowError{OpName: opName, NextOpening: next}
+	}
+
+	logger.PrintInfo("Waiting for the maintenance window that opens at %s to run %s",
+		next.Format(time.RFC3339), opName)
+	sleep := policy.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	sleep(next.Sub(now))
+	return nil
+}
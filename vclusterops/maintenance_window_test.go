@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	// Saturday from 22:00 to 02:00 on Sunday
+	window := MaintenanceWindow{
+		Weekdays: []time.Weekday{time.Saturday},
+		Start:    22 * time.Hour,
+		Duration: 4 * time.Hour,
+	}
+	saturday := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, window.Contains(saturday.Add(21*time.Hour)))
+	assert.True(t, window.Contains(saturday.Add(22*time.Hour)))
+	assert.True(t, window.Contains(saturday.Add(25*time.Hour)))
+	assert.False(t, window.Contains(saturday.Add(26*time.Hour)))
+
+	next, ok := window.NextOpening(saturday.Add(23 * time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, saturday.AddDate(0, 0, 7).Add(22*time.Hour), next)
+
+	// the window is in its own time zone
+	loc := time.FixedZone("UTC-4", -4*60*60)
+	window.Location = loc
+	assert.False(t, window.Contains(saturday.Add(22*time.Hour)))
+	assert.True(t, window.Contains(time.Date(2024, time.June, 1, 22, 30, 0, 0, loc)))
+
+	// negative: invalid windows
+	assert.Error(t, (&MaintenanceWindow{Start: 25 * time.Hour, Duration: time.Hour}).validate())
+	assert.Error(t, (&MaintenanceWindow{Start: time.Hour}).validate())
+}
+
+func TestMaintenancePolicy(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 21, 0, 0, 0, time.UTC)
+	var slept time.Duration
+	policy := MaintenancePolicy{
+		Windows: []MaintenanceWindow{{Start: 22 * time.Hour, Duration: 2 * time.Hour}},
+		now:     func() time.Time { return now },
+		sleep:   func(d time.Duration) { slept += d },
+	}
+	runOps := func(ops ...clusterOp) error {
+		opEngn := makeClusterOpEngine(ops, &DatabaseOptions{MaintenancePolicy: &policy})
+		return opEngn.run(vlog.Printer{})
+	}
+
+	// the ops that only read run outside of the windows
+	readOp := makeMockOp(false)
+	readOp.method = GetMethod
+	assert.NoError(t, runOps(&readOp))
+
+	// the ops that change the cluster are rejected outside of the windows
+	writeOp := makeMockOp(false)
+	writeOp.name = "write-op"
+	writeOp.method = PostMethod
+	err := runOps(&writeOp)
+	outsideErr := &OutsideMaintenanceWindowError{}
+	assert.ErrorAs(t, err, &outsideErr)
+	assert.Equal(t, "write-op", outsideErr.OpName)
+	assert.Equal(t, now.Add(time.Hour), outsideErr.NextOpening)
+	assert.False(t, writeOp.calledExecute)
+
+	// unless the override is set
+	opEngn := makeClusterOpEngine([]clusterOp{&writeOp},
+		&DatabaseOptions{MaintenancePolicy: &policy, IgnoreMaintenanceWindow: true})
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+	assert.True(t, writeOp.calledExecute)
+
+	// or they are delayed until the next window opens
+	policy.MaxDelay = 2 * time.Hour
+	assert.NoError(t, runOps(&writeOp))
+	assert.Equal(t, time.Hour, slept)
+
+	// they run right away in a window
+	slept = 0
+	now = now.Add(90 * time.Minute)
+	assert.NoError(t, runOps(&writeOp))
+	assert.Zero(t, slept)
+
+	// a command that was admitted is not interrupted when the window closes,
+	// even in its later op engines
+	policy.MaxDelay = 0
+	options := DatabaseOptions{MaintenancePolicy: &policy}
+	opEngn = makeClusterOpEngine([]clusterOp{&writeOp}, &options)
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+	now = now.Add(2 * time.Hour)
+	opEngn = makeClusterOpEngine([]clusterOp{&writeOp}, &options)
+	assert.NoError(t, opEngn.run(vlog.Printer{}))
+	assert.ErrorAs(t, runOps(&writeOp), &outsideErr)
+}
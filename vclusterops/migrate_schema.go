@@ -0,0 +1,302 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// the steps of a schema migration, in order
+const (
+	SchemaMigrationApplyDDLStep  = "apply_ddl"
+	SchemaMigrationReplicateStep = "replicate"
+	SchemaMigrationVerifyStep    = "verify"
+)
+
+type (
+	SchemaRowCountMismatch           = vtypes.SchemaRowCountMismatch
+	SchemaMigrationVerificationError = vtypes.SchemaMigrationVerificationError
+)
+
+type VMigrateSchemaOptions struct {
+	// the replication of the schema from the source database to the target
+	// database, which always runs synchronously. TableOrSchemaName, and the
+	// include and exclude patterns, are not used.
+	VReplicationDatabaseOptions
+	// name of the schema to migrate
+	Schema string
+	// do not export the DDL of the schema from the source database, and
+	// apply it to the target database, before the replication
+	SkipDDL bool
+	// do not compare the row counts of the tables of the schema in both
+	// databases after the replication
+	SkipVerify bool
+	// where the completed steps of the migration are saved, so that running
+	// the migration again with the same file resumes it after the last
+	// completed step. The file is removed once the migration succeeds.
+	StateFile string
+}
+
+// SchemaMigrationState is the progress of a schema migration, saved in the
+// state file of the migration
+type SchemaMigrationState struct {
+	Schema         string   `json:"schema"`
+	SourceDBName   string   `json:"source_db_name"`
+	TargetDBName   string   `json:"target_db_name"`
+	CompletedSteps []string `json:"completed_steps"`
+}
+
+func VMigrateSchemaOptionsFactory() VMigrateSchemaOptions {
+	options := VMigrateSchemaOptions{}
+	options.VReplicationDatabaseOptions = VReplicationDatabaseFactory()
+	return options
+}
+
+func (options *VMigrateSchemaOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if options.Schema == "" {
+		return fmt.Errorf("must specify the schema to migrate")
+	}
+	err := util.ValidateName(options.Schema, "schema", true)
+	if err != nil {
+		return err
+	}
+	if options.TableOrSchemaName != "" || len(options.IncludePatterns) > 0 || len(options.ExcludePatterns) > 0 {
+		return fmt.Errorf("the objects to replicate are set by the schema to migrate")
+	}
+	if options.Async {
+		return fmt.Errorf("the replication of a schema migration is always synchronous")
+	}
+	// the hosts of both databases are resolved before the first step
+	return options.VReplicationDatabaseOptions.validateAnalyzeOptions(logger)
+}
+
+// VMigrateSchema moves a schema from the source database to the target
+// database. The DDL of the schema is applied to the target database, the
+// schema is replicated, and the row counts of its tables are compared in both
+// databases. With a state file, a failed migration can be resumed.
+func (vcc VClusterCommands) VMigrateSchema(options *VMigrateSchemaOptions) error {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+	state, err := options.readState()
+	if err != nil {
+		return err
+	}
+
+	steps := []struct {
+		name string
+		skip bool
+		run  func(*VMigrateSchemaOptions) error
+	}{
+		{SchemaMigrationApplyDDLStep, options.SkipDDL, vcc.migrateSchemaDDL},
+		{SchemaMigrationReplicateStep, false, vcc.replicateSchema},
+		{SchemaMigrationVerifyStep, options.SkipVerify, vcc.verifySchemaMigration},
+	}
+	for _, step := range steps {
+		if step.skip {
+			continue
+		}
+		if slices.Contains(state.CompletedSteps, step.name) {
+			vcc.Log.PrintInfo("Step %s of the migration of schema %s is already completed", step.name, options.Schema)
+			continue
+		}
+		err = step.run(options)
+		if err != nil {
+			return fmt.Errorf("fail to migrate schema %s at step %s: %w", options.Schema, step.name, err)
+		}
+		state.CompletedSteps = append(state.CompletedSteps, step.name)
+		err = options.saveState(&state)
+		if err != nil {
+			return err
+		}
+	}
+
+	return options.removeState()
+}
+
+// readState returns the progress of the migration saved in the state file.
+// A state file that does not exist has no completed steps.
+func (options *VMigrateSchemaOptions) readState() (SchemaMigrationState, error) {
+	state := SchemaMigrationState{
+		Schema:       options.Schema,
+		SourceDBName: options.DBName,
+		TargetDBName: options.TargetDB.DBName,
+	}
+	if options.StateFile == "" {
+		return state, nil
+	}
+	fileBytes, err := os.ReadFile(options.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("fail to read migration state file %s, details: %w", options.StateFile, err)
+	}
+	saved := SchemaMigrationState{}
+	err = json.Unmarshal(fileBytes, &saved)
+	if err != nil {
+		return state, fmt.Errorf("fail to parse migration state file %s, details: %w", options.StateFile, err)
+	}
+	if saved.Schema != state.Schema || saved.SourceDBName != state.SourceDBName || saved.TargetDBName != state.TargetDBName {
+		return state, fmt.Errorf("migration state file %s is for the migration of schema %s from database %s to %s",
+			options.StateFile, saved.Schema, saved.SourceDBName, saved.TargetDBName)
+	}
+	return saved, nil
+}
+
+func (options *VMigrateSchemaOptions) saveState(state *SchemaMigrationState) error {
+	if options.StateFile == "" {
+		return nil
+	}
+	fileBytes, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal migration state, details: %w", err)
+	}
+	return writeStateFile(options.StateFile, "migration", fileBytes)
+}
+
+func (options *VMigrateSchemaOptions) removeState() error {
+	if options.StateFile == "" {
+		return nil
+	}
+	err := os.Remove(options.StateFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("schema %s is migrated, but fail to remove migration state file %s, details: %w",
+			options.Schema, options.StateFile, err)
+	}
+	return nil
+}
+
+// getTargetOptions returns the options to connect to the target database,
+// as the source user if no target user is set
+func (options *VMigrateSchemaOptions) getTargetOptions() *DatabaseOptions {
+	targetOptions := options.TargetDB
+	if targetOptions.UserName == "" {
+		targetOptions.UserName = options.UserName
+	}
+	return &targetOptions
+}
+
+// The generated instructions will later perform the following operations necessary
+// to apply the DDL of the schema:
+//   - Export the DDL of the schema on an up host of the source database
+//   - Apply the DDL on an up host of the target database
+func (vcc VClusterCommands) migrateSchemaDDL(options *VMigrateSchemaOptions) error {
+	ddl := ""
+	sourceOptions := &options.DatabaseOptions
+	err := vcc.runSchemaDDLOp(sourceOptions, options.SandboxName, false /*apply*/, options.Schema, &ddl)
+	if err != nil {
+		return err
+	}
+	return vcc.runSchemaDDLOp(options.getTargetOptions(), util.MainClusterSandbox, true /*apply*/, options.Schema, &ddl)
+}
+
+func (vcc VClusterCommands) runSchemaDDLOp(options *DatabaseOptions, sandbox string, apply bool,
+	schema string, ddl *string) error {
+	err := options.setUsePassword(vcc.Log)
+	if err != nil {
+		return err
+	}
+	initiator, err := vcc.getUpInitiatorFromRunningDB(options, sandbox)
+	if err != nil {
+		return err
+	}
+	nmaSchemaDDLOp, err := makeNMASchemaDDLOp([]string{initiator}, apply, schema, options.UserName,
+		options.DBName, options.Password, options.usePassword, ddl)
+	if err != nil {
+		return err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaSchemaDDLOp}, options)
+	return clusterOpEngine.run(vcc.Log)
+}
+
+// replicateSchema replicates the schema synchronously
+func (vcc VClusterCommands) replicateSchema(options *VMigrateSchemaOptions) error {
+	replicationOptions := options.VReplicationDatabaseOptions
+	replicationOptions.TableOrSchemaName = options.Schema
+	replicationOptions.Async = false
+	_, err := vcc.VReplicateDatabase(&replicationOptions)
+	return err
+}
+
+// verifySchemaMigration compares the row counts of the tables of the schema in
+// the source and target databases
+func (vcc VClusterCommands) verifySchemaMigration(options *VMigrateSchemaOptions) error {
+	sourceRowCounts := make(map[string]int64)
+	err := vcc.runSchemaRowCountsOp(&options.DatabaseOptions, options.SandboxName, options.Schema, sourceRowCounts)
+	if err != nil {
+		return err
+	}
+	targetRowCounts := make(map[string]int64)
+	err = vcc.runSchemaRowCountsOp(options.getTargetOptions(), util.MainClusterSandbox, options.Schema, targetRowCounts)
+	if err != nil {
+		return err
+	}
+
+	mismatches := compareSchemaRowCounts(sourceRowCounts, targetRowCounts)
+	if len(mismatches) > 0 {
+		return &SchemaMigrationVerificationError{Schema: options.Schema, Mismatches: mismatches}
+	}
+	return nil
+}
+
+func (vcc VClusterCommands) runSchemaRowCountsOp(options *DatabaseOptions, sandbox, schema string,
+	rowCounts map[string]int64) error {
+	err := options.setUsePassword(vcc.Log)
+	if err != nil {
+		return err
+	}
+	initiator, err := vcc.getUpInitiatorFromRunningDB(options, sandbox)
+	if err != nil {
+		return err
+	}
+	nmaSchemaRowCountsOp, err := makeNMASchemaRowCountsOp([]string{initiator}, schema, options.UserName,
+		options.DBName, options.Password, options.usePassword, rowCounts)
+	if err != nil {
+		return err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaSchemaRowCountsOp}, options)
+	return clusterOpEngine.run(vcc.Log)
+}
+
+// compareSchemaRowCounts returns the tables of the source database, sorted by
+// name, that do not have the same row count in the target database
+func compareSchemaRowCounts(sourceRowCounts, targetRowCounts map[string]int64) []SchemaRowCountMismatch {
+	var mismatches []SchemaRowCountMismatch
+	for table, sourceRows := range sourceRowCounts {
+		targetRows, ok := targetRowCounts[table]
+		if !ok {
+			targetRows = -1
+		}
+		if targetRows != sourceRows {
+			mismatches = append(mismatches, SchemaRowCountMismatch{Table: table, SourceRows: sourceRows, TargetRows: targetRows})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Table < mismatches[j].Table
+	})
+	return mismatches
+}
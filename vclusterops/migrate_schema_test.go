@@ -0,0 +1,112 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestMigrateSchemaOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	opt := VMigrateSchemaOptionsFactory()
+	opt.DBName = testDBName
+
+	// negative: no schema
+	assert.ErrorContains(t, opt.validateAnalyzeOptions(logger), "must specify the schema to migrate")
+
+	// negative: the objects to replicate are set by the schema
+	opt.Schema = "sales"
+	opt.IncludePatterns = []string{"*.orders"}
+	assert.ErrorContains(t, opt.validateAnalyzeOptions(logger), "set by the schema to migrate")
+	opt.IncludePatterns = nil
+
+	// negative: asynchronous replication
+	opt.Async = true
+	assert.ErrorContains(t, opt.validateAnalyzeOptions(logger), "always synchronous")
+}
+
+func TestMigrateSchemaState(t *testing.T) {
+	opt := VMigrateSchemaOptionsFactory()
+	opt.DBName = "source_db"
+	opt.TargetDB.DBName = "target_db"
+	opt.Schema = "sales"
+	opt.StateFile = filepath.Join(t.TempDir(), "migration_state.json")
+
+	// a state file that does not exist has no completed steps
+	state, err := opt.readState()
+	assert.NoError(t, err)
+	assert.Empty(t, state.CompletedSteps)
+
+	state.CompletedSteps = append(state.CompletedSteps, SchemaMigrationApplyDDLStep)
+	assert.NoError(t, opt.saveState(&state))
+	state, err = opt.readState()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{SchemaMigrationApplyDDLStep}, state.CompletedSteps)
+
+	// negative: the state file is for another schema
+	opt.Schema = "hr"
+	_, err = opt.readState()
+	assert.ErrorContains(t, err, "is for the migration of schema sales from database source_db to target_db")
+
+	assert.NoError(t, opt.removeState())
+	state, err = opt.readState()
+	assert.NoError(t, err)
+	assert.Empty(t, state.CompletedSteps)
+}
+
+func TestCompareSchemaRowCounts(t *testing.T) {
+	sourceRowCounts := map[string]int64{"orders": 1200, "customers": 45, "items": 10}
+	targetRowCounts := map[string]int64{"orders": 1100, "customers": 45}
+	mismatches := compareSchemaRowCounts(sourceRowCounts, targetRowCounts)
+	assert.Equal(t, []SchemaRowCountMismatch{
+		{Table: "items", SourceRows: 10, TargetRows: -1},
+		{Table: "orders", SourceRows: 1200, TargetRows: 1100},
+	}, mismatches)
+
+	err := &SchemaMigrationVerificationError{Schema: "sales", Mismatches: mismatches}
+	assert.EqualError(t, err, "the row counts of 2 tables of schema sales differ between the source and target databases, "+
+		"the first one is table items with 10 source rows and -1 target rows")
+
+	assert.Empty(t, compareSchemaRowCounts(sourceRowCounts, sourceRowCounts))
+}
+
+func TestSchemaMigrationOps(t *testing.T) {
+	password := "password"
+	hosts := []string{"192.168.1.101"}
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+
+	ddl := ""
+	exportOp, err := makeNMASchemaDDLOp(hosts, false /*apply*/, "sales", "dbadmin", testDBName, &password, true, &ddl)
+	assert.NoError(t, err)
+	exportOp.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"ddl": "CREATE SCHEMA sales;"}`},
+	}
+	assert.NoError(t, exportOp.processResult(&execContext))
+	assert.Equal(t, "CREATE SCHEMA sales;", ddl)
+
+	rowCounts := map[string]int64{}
+	rowCountsOp, err := makeNMASchemaRowCountsOp(hosts, "sales", "dbadmin", testDBName, &password, true, rowCounts)
+	assert.NoError(t, err)
+	rowCountsOp.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"row_counts": {"orders": 1200, "customers": 45}}`},
+	}
+	assert.NoError(t, rowCountsOp.processResult(&execContext))
+	assert.Equal(t, map[string]int64{"orders": 1200, "customers": 45}, rowCounts)
+}
@@ -0,0 +1,251 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+)
+
+// asyncJob is a long-running job on the NMA that nmaAsyncJobOp waits for. A job
+// decides how its status is requested and read, while the op takes care of
+// polling, timeouts, and failing over between hosts.
+type asyncJob interface {
+	// getName returns what is polled, for messages, e.g., "replication status"
+	getName() string
+	// getStatusRequest returns the request that retrieves the status of the job
+	getStatusRequest() (hostHTTPRequest, error)
+	// checkStatus reads a status response of the job from host, and returns
+	// whether the job has finished. A job keeps its own result, e.g., in a
+	// pointer that the caller reads after the op. An error stops polling and
+	// fails the op.
+	checkStatus(op *nmaAsyncJobOp, host, responseContent string) (finished bool, err error)
+}
+
+// submittableAsyncJob is an asyncJob that nmaAsyncJobOp submits itself, on the
+// first host that is polled, before polling its status
+type submittableAsyncJob interface {
+	asyncJob
+	getSubmitRequest() (hostHTTPRequest, error)
+	// processSubmitResponse reads the response of the submit request, e.g., to
+	// keep the job ID for the status requests
+	processSubmitResponse(op *nmaAsyncJobOp, host, responseContent string) error
+}
+
+// nmaAsyncJobOp waits for an asyncJob to finish. Only one host is polled at a
+// time. When that host stops responding, e.g., its node is restarted during a
+// long job, polling fails over to the next host.
+type nmaAsyncJobOp struct {
+	opBase
+	job asyncJob
+	// index of the host being polled
+	hostIndex int
+	// number of consecutive hosts that failed to respond
+	failedHostCount int
+	// status requests of all hosts, only one of them is sent at a time
+	hostRequests map[string]hostHTTPRequest
+	timeout      int
+}
+
+func makeNMAAsyncJobOp(name, description string, hosts []string, job asyncJob, timeout int) nmaAsyncJobOp {
+	op := nmaAsyncJobOp{}
+	op.name = name
+	op.description = description
+	op.hosts = hosts
+	op.job = job
+	op.timeout = timeout
+	return op
+}
+
+func (op *nmaAsyncJobOp) setupClusterHTTPRequest(hosts []string) error {
+	statusRequest, err := op.job.getStatusRequest()
+	if err != nil {
+		return fmt.Errorf("[%s] fail to set up the job status request, details: %w", op.name, err)
+	}
+
+	for _, host := range hosts {
+		op.clusterHTTPRequest.RequestCollection[host] = statusRequest
+	}
+
+	return nil
+}
+
+func (op *nmaAsyncJobOp) prepare(execContext *opEngineExecContext) error {
+	if len(op.hosts) == 0 {
+		return fmt.Errorf("[%s] no host to poll the job status from", op.name)
+	}
+	// a job is submitted like the request of an initiator
+	if _, ok := op.job.(submittableAsyncJob); ok {
+		candidates, err := execContext.initiatorCandidates(op.hosts)
+		if err != nil {
+			return err
+		}
+		op.hosts = candidates
+	}
+
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaAsyncJobOp) execute(execContext *opEngineExecContext) error {
+	// requests of all hosts are set up in prepare so that the TLS options are
+	// applied to each of them, but only the current host is polled
+	op.hostRequests = op.clusterHTTPRequest.RequestCollection
+
+	if job, ok := op.job.(submittableAsyncJob); ok {
+		if err := op.submit(execContext, job); err != nil {
+			return err
+		}
+		// the status request can depend on the submit response, e.g., a job ID
+		if err := op.resetStatusRequests(); err != nil {
+			return err
+		}
+	}
+
+	op.pollHost(op.hosts[op.hostIndex])
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// getClassification returns whether the op submits the job; polling the
+// status of a job that is already running does not change the cluster
+func (op *nmaAsyncJobOp) getClassification() OpClassification {
+	if _, ok := op.job.(submittableAsyncJob); ok {
+		return mutatingClassification
+	}
+	return readOnlyClassification
+}
+
+func (op *nmaAsyncJobOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaAsyncJobOp) processResult(execContext *opEngineExecContext) error {
+	err := pollState(op, execContext)
+	if err != nil {
+		return fmt.Errorf("error polling %s, %w", op.job.getName(), err)
+	}
+	return nil
+}
+
+// withHostTLS returns jobRequest with the TLS options and credentials of the
+// status request of host, which were applied by the engine
+func (op *nmaAsyncJobOp) withHostTLS(host string, jobRequest *hostHTTPRequest) hostHTTPRequest {
+	request := op.hostRequests[host]
+	request.Method = jobRequest.Method
+	request.Endpoint = jobRequest.Endpoint
+	request.IsNMACommand = jobRequest.IsNMACommand
+	request.QueryParams = jobRequest.QueryParams
+	request.RequestData = jobRequest.RequestData
+	return request
+}
+
+func (op *nmaAsyncJobOp) resetStatusRequests() error {
+	statusRequest, err := op.job.getStatusRequest()
+	if err != nil {
+		return fmt.Errorf("[%s] fail to set up the job status request, details: %w", op.name, err)
+	}
+	for host := range op.hostRequests {
+		op.hostRequests[host] = op.withHostTLS(host, &statusRequest)
+	}
+	return nil
+}
+
+// sendToHost sends a single request to host and returns its result
+func (op *nmaAsyncJobOp) sendToHost(execContext *opEngineExecContext, host string,
+	jobRequest *hostHTTPRequest) (hostHTTPResult, error) {
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{
+		host: op.withHostTLS(host, jobRequest),
+	}
+	if err := op.runExecute(execContext); err != nil {
+		return hostHTTPResult{}, err
+	}
+	result := op.clusterHTTPRequest.ResultCollection[host]
+	op.logResponse(host, result)
+	if !result.isPassing() {
+		return result, result.err
+	}
+	return result, nil
+}
+
+func (op *nmaAsyncJobOp) submit(execContext *opEngineExecContext, job submittableAsyncJob) error {
+	submitRequest, err := job.getSubmitRequest()
+	if err != nil {
+		return fmt.Errorf("[%s] fail to set up the job submit request, details: %w", op.name, err)
+	}
+
+	host := op.hosts[op.hostIndex]
+	result, err := op.sendToHost(execContext, host, &submitRequest)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to submit the job on host %s, details: %w", op.name, host, err)
+	}
+	return job.processSubmitResponse(op, host, result.content)
+}
+
+func (op *nmaAsyncJobOp) pollHost(host string) {
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{
+		host: op.hostRequests[host],
+	}
+}
+
+// failover switches polling to the next host. It fails when none of the
+// hosts responded since the last successful poll.
+func (op *nmaAsyncJobOp) failover(host string, hostErr error) error {
+	op.failedHostCount++
+	if op.failedHostCount >= len(op.hosts) {
+		return fmt.Errorf("[%s] none of the target hosts responded, last error from host %s: %w",
+			op.name, host, hostErr)
+	}
+
+	op.hostIndex = (op.hostIndex + 1) % len(op.hosts)
+	nextHost := op.hosts[op.hostIndex]
+	op.logger.PrintWarning("[%s] host %s did not respond, polling %s from host %s instead",
+		op.name, host, op.job.getName(), nextHost)
+	op.pollHost(nextHost)
+	return nil
+}
+
+func (op *nmaAsyncJobOp) getPollingTimeout() int {
+	return op.timeout
+}
+
+func (op *nmaAsyncJobOp) shouldStopPolling() (bool, error) {
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return true, fmt.Errorf("[%s] wrong certificate for NMA service on host %s",
+				op.name, host)
+		}
+
+		if !result.isPassing() {
+			err := op.failover(host, result.err)
+			return err != nil, err
+		}
+		op.failedHostCount = 0
+
+		finished, err := op.job.checkStatus(op, host, result.content)
+		if err != nil {
+			return true, fmt.Errorf("[%s] %w", op.name, err)
+		}
+		return finished, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,108 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// mockJobAdapter answers the submit and status requests of mockAsyncJob
+type mockJobAdapter struct {
+	host     string
+	requests *[]string
+}
+
+func (adapter *mockJobAdapter) sendRequest(request *hostHTTPRequest, resultChannel chan<- hostHTTPResult) {
+	*adapter.requests = append(*adapter.requests, request.Method+" "+request.Endpoint)
+	resultChannel <- hostHTTPResult{host: adapter.host, status: SUCCESS, statusCode: SuccessCode,
+		content: `{"job_id": "job1", "finished": true}`}
+}
+
+func (adapter *mockJobAdapter) generateResult(_ *http.Response) hostHTTPResult {
+	return hostHTTPResult{}
+}
+
+type mockAsyncJob struct {
+	jobID string
+}
+
+func (job *mockAsyncJob) getName() string {
+	return "mock job status"
+}
+
+func (job *mockAsyncJob) getSubmitRequest() (hostHTTPRequest, error) {
+	request := hostHTTPRequest{Method: PostMethod}
+	request.buildNMAEndpoint("jobs")
+	return request, nil
+}
+
+func (job *mockAsyncJob) processSubmitResponse(op *nmaAsyncJobOp, host, responseContent string) error {
+	response := map[string]any{}
+	err := op.parseAndCheckResponse(host, responseContent, &response)
+	job.jobID, _ = response["job_id"].(string)
+	return err
+}
+
+func (job *mockAsyncJob) getStatusRequest() (hostHTTPRequest, error) {
+	request := hostHTTPRequest{Method: GetMethod}
+	request.buildNMAEndpoint("jobs/" + job.jobID)
+	return request, nil
+}
+
+func (job *mockAsyncJob) checkStatus(op *nmaAsyncJobOp, host, responseContent string) (bool, error) {
+	response := map[string]any{}
+	err := op.parseAndCheckResponse(host, responseContent, &response)
+	if err != nil {
+		return true, err
+	}
+	if response["error"] != nil {
+		return true, errors.New("the job failed")
+	}
+	return response["finished"] == true, nil
+}
+
+func TestAsyncJobOp(t *testing.T) {
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	job := &mockAsyncJob{}
+	op := makeNMAAsyncJobOp("NMAMockJobOp", "Wait for the mock job", hosts, job, 0)
+	op.setupBasicInfo()
+
+	var requests []string
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	assert.NoError(t, op.prepare(&execContext))
+	for _, host := range hosts {
+		execContext.dispatcher.pool.connections[host] = &mockJobAdapter{host: host, requests: &requests}
+	}
+
+	// the job is submitted, then its status is polled with the returned job ID
+	assert.NoError(t, op.execute(&execContext))
+	assert.Equal(t, "job1", job.jobID)
+	assert.Equal(t, []string{PostMethod + " " + NMACurVersion + "jobs", GetMethod + " " + NMACurVersion + "jobs/job1"},
+		requests)
+
+	// negative: the job fails
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {host: hosts[0], status: SUCCESS, statusCode: SuccessCode, content: `{"error": "out of disk"}`},
+	}
+	stop, err := op.shouldStopPolling()
+	assert.True(t, stop)
+	assert.ErrorContains(t, err, "[NMAMockJobOp] the job failed")
+}
@@ -90,7 +90,7 @@ func (op *nmaBootstrapCatalogOp) setupRequestBody(vdb *VCoordinationDatabase, op
 
 		// need to read network_profile info in execContext
 		// see execContext in nmaBootstrapCatalogOp:prepare()
-		bootstrapData.ControlAddr = vnode.Address
+		bootstrapData.ControlAddr = vnode.getControlAddress()
 
 		bootstrapData.LicenseKey = vdb.LicensePathOnNode
 		// large cluster mode temporariliy disabled
@@ -180,6 +180,10 @@ func (op *nmaBootstrapCatalogOp) execute(execContext *opEngineExecContext) error
 	return op.processResult(execContext)
 }
 
+func (op *nmaBootstrapCatalogOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *nmaBootstrapCatalogOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
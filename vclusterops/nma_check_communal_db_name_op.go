@@ -0,0 +1,130 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type DBNameInUseError = vtypes.DBNameInUseError
+
+// nmaCheckCommunalDBNameOp checks that no database with the given name has a
+// description file on the communal storage, by downloading the file through
+// the NMA and expecting it to be missing
+type nmaCheckCommunalDBNameOp struct {
+	opBase
+	dbName                  string
+	communalStorageLocation string
+	hostRequestBody         string
+}
+
+func makeNMACheckCommunalDBNameOp(hosts []string, dbName, communalStorageLocation string,
+	configurationParameters map[string]string) (nmaCheckCommunalDBNameOp, error) {
+	op := nmaCheckCommunalDBNameOp{}
+	op.name = "NMACheckCommunalDBNameOp"
+	op.description = "Check database name on communal storage"
+	op.hosts = hosts
+	op.dbName = dbName
+	op.communalStorageLocation = communalStorageLocation
+
+	descOptions := DatabaseOptions{DBName: dbName, CommunalStorageLocation: communalStorageLocation}
+	requestData := downloadFileRequestData{
+		SourceFilePath:      descOptions.getCurrConfigFilePath(""),
+		DestinationFilePath: currConfigFileDestPath,
+		CatalogPath:         catalogPath,
+		Parameters:          configurationParameters,
+	}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaCheckCommunalDBNameOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("vertica/download-file")
+		httpRequest.RequestData = op.hostRequestBody
+
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaCheckCommunalDBNameOp) prepare(execContext *opEngineExecContext) error {
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("[%s] no hosts to reach communal storage from", op.name)
+	}
+	// one NMA is enough to reach communal storage
+	initiator := []string{getInitiator(candidates)}
+	execContext.dispatcher.setup(initiator)
+
+	return op.setupClusterHTTPRequest(initiator)
+}
+
+func (op *nmaCheckCommunalDBNameOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the description file is only downloaded to a temporary path
+func (op *nmaCheckCommunalDBNameOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
+func (op *nmaCheckCommunalDBNameOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaCheckCommunalDBNameOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPassing() {
+			return &DBNameInUseError{DBName: op.dbName, CommunalStorageLocation: op.communalStorageLocation}
+		}
+
+		// a missing description file means that the name is free
+		problem := &rfc7807.VProblem{}
+		if errors.As(result.err, &problem) && problem.IsInstanceOf(rfc7807.UndefinedFile) {
+			return nil
+		}
+
+		httpsErr := errors.Join(fmt.Errorf("[%s] HTTPS call failed on host %s", op.name, host), result.err)
+		allErrs = errors.Join(allErrs, httpsErr)
+	}
+
+	return appendHTTPSFailureError(allErrs)
+}
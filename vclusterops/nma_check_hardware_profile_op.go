@@ -0,0 +1,121 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// nmaCheckHardwareProfileOp compares the hardware and OS of the hosts to check
+// with the ones of a reference host, and warns about the hosts that differ
+// beyond the thresholds. It never fails, as the nodes still work, only with
+// imbalanced performance.
+type nmaCheckHardwareProfileOp struct {
+	nmaGetHardwareInventoryOp
+	// the first of these hosts that reports its hardware is the reference host
+	referenceHosts []string
+	hostsToCheck   []string
+	check          *HardwareCheckOptions
+}
+
+func makeNMACheckHardwareProfileOp(referenceHosts, hostsToCheck []string,
+	check *HardwareCheckOptions) (nmaCheckHardwareProfileOp, error) {
+	op := nmaCheckHardwareProfileOp{}
+	hosts := append(slices.Clone(referenceHosts), hostsToCheck...)
+	slices.Sort(hosts)
+	inventoryOp, err := makeNMAGetHardwareInventoryOp(slices.Compact(hosts), make(map[string]HostHardware))
+	if err != nil {
+		return op, err
+	}
+	op.nmaGetHardwareInventoryOp = inventoryOp
+	op.name = "NMACheckHardwareProfileOp"
+	op.description = "Check hardware of the hosts"
+	op.referenceHosts = slices.Clone(referenceHosts)
+	slices.Sort(op.referenceHosts)
+	op.hostsToCheck = hostsToCheck
+	op.check = check
+	return op, nil
+}
+
+func (op *nmaCheckHardwareProfileOp) execute(execContext *opEngineExecContext) error {
+	err := op.runExecute(execContext)
+	if err == nil {
+		err = op.processResult(execContext)
+	}
+	if err != nil {
+		op.logger.PrintWarning("[%s] fail to collect the hardware of some hosts, they are not checked, details: %v",
+			op.name, err)
+	}
+
+	for _, warning := range op.findHardwareWarnings() {
+		op.logger.DisplayWarning("Heterogeneous node hardware: %s", warning.String())
+		op.logger.Info("heterogeneous node hardware", "host", warning.Host, "referenceHost", warning.ReferenceHost,
+			"property", warning.Property, "value", warning.Value, "reference", warning.Reference)
+		if op.check.OnHardwareWarning != nil {
+			op.check.OnHardwareWarning(warning)
+		}
+	}
+	return nil
+}
+
+func (op *nmaCheckHardwareProfileOp) findHardwareWarnings() []HardwareWarning {
+	var reference *HostHardware
+	for _, host := range op.referenceHosts {
+		if hardware, ok := op.hostHardware[host]; ok {
+			reference = &hardware
+			break
+		}
+	}
+	if reference == nil {
+		return nil
+	}
+
+	var hosts []HostHardware
+	for _, host := range op.hostsToCheck {
+		if hardware, ok := op.hostHardware[host]; ok {
+			hosts = append(hosts, hardware)
+		}
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Host < hosts[j].Host
+	})
+	return vtypes.CompareHardware(reference, hosts, op.check.HardwareThresholds)
+}
+
+// getHardwareReferenceHosts returns the existing hosts of a subcluster, or all
+// of the existing hosts if the subcluster has none, for add_node to compare
+// the new hosts with
+func getHardwareReferenceHosts(vdb *VCoordinationDatabase, scName string, newHosts []string) []string {
+	var referenceHosts []string
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.Subcluster == scName && !slices.Contains(newHosts, host) {
+			referenceHosts = append(referenceHosts, host)
+		}
+	}
+	if len(referenceHosts) > 0 {
+		return referenceHosts
+	}
+	for _, host := range maps.Keys(vdb.HostNodeMap) {
+		if !slices.Contains(newHosts, host) {
+			referenceHosts = append(referenceHosts, host)
+		}
+	}
+	return referenceHosts
+}
@@ -0,0 +1,131 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// nmaCheckLicenseComplianceOp checks that the database does not exceed the
+// limits of its license after new hosts are added. The NMA of every new host
+// reports the CPU sockets of the host, and the limits of the license file at
+// licensePath. The limits and usage of a running database are read by
+// httpsGetLicenseOp before this op, and the license file is only read when
+// there is no running database.
+type nmaCheckLicenseComplianceOp struct {
+	opBase
+	licensePath     string
+	hostRequestBody string
+}
+
+type licenseRequestData struct {
+	LicensePath string `json:"license_path,omitempty"`
+}
+
+type licenseResponse struct {
+	LicenseLimits
+	CPUSockets int `json:"cpu_sockets"`
+}
+
+func makeNMACheckLicenseComplianceOp(newHosts []string, licensePath string) (nmaCheckLicenseComplianceOp, error) {
+	op := nmaCheckLicenseComplianceOp{}
+	op.name = "NMACheckLicenseComplianceOp"
+	op.description = "Check license compliance"
+	op.hosts = newHosts
+	op.licensePath = licensePath
+
+	dataBytes, err := json.Marshal(licenseRequestData{LicensePath: licensePath})
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaCheckLicenseComplianceOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("license")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaCheckLicenseComplianceOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaCheckLicenseComplianceOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the license is read through a POST request
+func (op *nmaCheckLicenseComplianceOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
+func (op *nmaCheckLicenseComplianceOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaCheckLicenseComplianceOp) processResult(execContext *opEngineExecContext) error {
+	var allErrs error
+	status := licenseStatus{}
+	if execContext.licenseStatus != nil {
+		status = *execContext.licenseStatus
+	}
+	initiator := getInitiator(op.hosts)
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		// the response object will be a dictionary, e.g.,:
+		// {"cpu_sockets": 2, "node_limit": 3, "cpu_limit": 0, "size_limit_bytes": 1099511627776}
+		var response licenseResponse
+		err := op.parseAndCheckResponse(host, result.content, &response)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		status.NodeCount++
+		status.CPUCount += response.CPUSockets
+		// every host has the same license file before the database is created
+		if execContext.licenseStatus == nil && host == initiator {
+			status.LicenseLimits = response.LicenseLimits
+		}
+	}
+	if allErrs != nil {
+		return allErrs
+	}
+
+	return checkLicenseCompliance(status.LicenseLimits, status.LicenseUsage)
+}
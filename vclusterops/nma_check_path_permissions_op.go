@@ -0,0 +1,195 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+	"golang.org/x/exp/maps"
+)
+
+type PathPermissionError = vtypes.PathPermissionError
+
+type nmaCheckPathPermissionsOp struct {
+	opBase
+	hostRequestBodyMap map[string]string
+	// paths to check per host
+	hostPathsMap map[string][]string
+}
+
+type checkPathPermissionsRequestData struct {
+	Paths []string `json:"paths"`
+}
+
+type pathPermission struct {
+	Path string `json:"path"`
+	// the path itself if it exists, otherwise its nearest existing parent
+	ExistingPath string `json:"existing_path"`
+	OwnerUID     int    `json:"owner_uid"`
+	OwnerGID     int    `json:"owner_gid"`
+	Mode         string `json:"mode"`
+	Writable     bool   `json:"writable"`
+}
+
+type checkPathPermissionsResponse struct {
+	UserName string           `json:"user_name"`
+	UID      int              `json:"uid"`
+	GID      int              `json:"gid"`
+	Paths    []pathPermission `json:"paths"`
+}
+
+// makeNMACheckPathPermissionsOp checks on each host of hostNodeMap that the
+// catalog, depot and storage paths of the node can be written
+func makeNMACheckPathPermissionsOp(hostNodeMap vHostNodeMap) (nmaCheckPathPermissionsOp, error) {
+	hostPathsMap := make(map[string][]string)
+	for host, vnode := range hostNodeMap {
+		paths := []string{getCatalogPath(vnode.CatalogPath)}
+		if vnode.DepotPath != "" {
+			paths = append(paths, vnode.DepotPath)
+		}
+		paths = append(paths, vnode.StorageLocations...)
+		paths = append(paths, vnode.UserStorageLocations...)
+		hostPathsMap[host] = paths
+	}
+	return makeNMACheckPathPermissionsOpWithPaths(hostPathsMap)
+}
+
+// makeNMACheckPathPermissionsOpWithPaths checks that the given paths can be
+// written on each host
+func makeNMACheckPathPermissionsOpWithPaths(hostPathsMap map[string][]string) (nmaCheckPathPermissionsOp, error) {
+	op := nmaCheckPathPermissionsOp{}
+	op.name = "NMACheckPathPermissionsOp"
+	op.description = "Check permissions of directories on Vertica hosts"
+	op.hostPathsMap = hostPathsMap
+	op.hosts = maps.Keys(hostPathsMap)
+	sort.Strings(op.hosts)
+
+	err := op.setupRequestBody()
+	if err != nil {
+		return op, err
+	}
+
+	return op, nil
+}
+
+func (op *nmaCheckPathPermissionsOp) setupRequestBody() error {
+	op.hostRequestBodyMap = make(map[string]string)
+
+	for host, paths := range op.hostPathsMap {
+		dataBytes, err := json.Marshal(checkPathPermissionsRequestData{Paths: paths})
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", op.hostRequestBodyMap)
+
+	return nil
+}
+
+func (op *nmaCheckPathPermissionsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("directories/check-permissions")
+		httpRequest.RequestData = op.hostRequestBodyMap[host]
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaCheckPathPermissionsOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaCheckPathPermissionsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the check only reads the paths
+func (op *nmaCheckPathPermissionsOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
+func (op *nmaCheckPathPermissionsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaCheckPathPermissionsOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isNotFound() {
+			// an older agent without the check, the later steps report
+			// permission failures by themselves
+			op.logger.PrintWarning("[%s] cannot check directory permissions on host %s, "+
+				"the node management agent does not support the check", op.name, host)
+			continue
+		}
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		var response checkPathPermissionsResponse
+		err := op.parseAndCheckResponse(host, result.content, &response)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		allErrs = errors.Join(allErrs, checkPathPermissions(host, &response))
+	}
+
+	return allErrs
+}
+
+// checkPathPermissions returns a PathPermissionError for each path of the
+// response that cannot be written
+func checkPathPermissions(host string, response *checkPathPermissionsResponse) error {
+	var allErrs error
+	for i := range response.Paths {
+		p := &response.Paths[i]
+		if p.Writable {
+			continue
+		}
+		allErrs = errors.Join(allErrs, &PathPermissionError{
+			Host:         host,
+			Path:         p.Path,
+			BlockingPath: p.ExistingPath,
+			UserName:     response.UserName,
+			UID:          response.UID,
+			GID:          response.GID,
+			OwnerUID:     p.OwnerUID,
+			OwnerGID:     p.OwnerGID,
+			Mode:         strings.TrimSpace(p.Mode),
+		})
+	}
+	return allErrs
+}
@@ -0,0 +1,56 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPathPermissions(t *testing.T) {
+	hostNodeMap := vHostNodeMap{
+		"192.168.1.101": &VCoordinationNode{CatalogPath: "/data/test_db/v_test_db_node0001_catalog/Catalog",
+			DepotPath: "/depot/test_db", StorageLocations: []string{"/data/test_db/v_test_db_node0001_data"}},
+	}
+	op, err := makeNMACheckPathPermissionsOp(hostNodeMap)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/data/test_db/v_test_db_node0001_catalog", "/depot/test_db",
+		"/data/test_db/v_test_db_node0001_data"}, op.hostPathsMap["192.168.1.101"])
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, statusCode: SuccessCode, content: `{"user_name": "dbadmin", "uid": 1000, "gid": 1000,
+			"paths": [
+			{"path": "/data/test_db/v_test_db_node0001_catalog", "existing_path": "/data", "owner_uid": 1000,
+			 "owner_gid": 1000, "mode": "drwxr-xr-x", "writable": true},
+			{"path": "/depot/test_db", "existing_path": "/depot", "owner_uid": 0,
+			 "owner_gid": 0, "mode": "drwxr-xr-x", "writable": false}]}`},
+	}
+	err = op.processResult(nil)
+	assert.Error(t, err)
+	permissionErr := &PathPermissionError{}
+	assert.True(t, errors.As(err, &permissionErr))
+	assert.Equal(t, "/depot/test_db", permissionErr.Path)
+	assert.Equal(t, "user dbadmin (uid 1000, gid 1000) cannot write /depot/test_db on host 192.168.1.101: "+
+		"parent directory /depot is owned by uid 0, gid 0 with mode drwxr-xr-x", err.Error())
+
+	// an agent without the check is skipped
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: FAILURE, statusCode: NotFoundCode, err: errors.New("not found")},
+	}
+	assert.NoError(t, op.processResult(nil))
+}
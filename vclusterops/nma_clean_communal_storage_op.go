@@ -95,6 +95,10 @@ func (op *nmaCleanCommunalStorageOp) execute(execContext *opEngineExecContext) e
 	return op.processResult(execContext)
 }
 
+func (op *nmaCleanCommunalStorageOp) getClassification() OpClassification {
+	return OpClassification{Mutating: true, Destructive: true, Idempotent: true}
+}
+
 func (op *nmaCleanCommunalStorageOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,117 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+type nmaCleanupScrutinizeOp struct {
+	opBase
+	retentionHours int
+	// whether a failure to clean up a host should only be a warning, for the
+	// cleanup after a collection
+	bestEffort bool
+	// out parameter, the staging data deleted from each host
+	hostCleanups map[string]ScrutinizeCleanup
+}
+
+func makeNMACleanupScrutinizeOp(hosts []string, retentionHours int,
+	hostCleanups map[string]ScrutinizeCleanup) (nmaCleanupScrutinizeOp, error) {
+	op := nmaCleanupScrutinizeOp{}
+	op.name = "NMACleanupScrutinizeOp"
+	op.description = "Delete stale scrutinize staging data"
+	op.hosts = hosts
+	op.retentionHours = retentionHours
+	if hostCleanups == nil {
+		return op, errors.New("argument hostCleanups cannot be a nil map")
+	}
+	op.hostCleanups = hostCleanups
+	return op, nil
+}
+
+// useBestEffort indicates that a failure to clean up a host should only be a
+// warning, so that it does not fail the collection that ran before it
+func (op *nmaCleanupScrutinizeOp) useBestEffort() {
+	op.bestEffort = true
+}
+
+func (op *nmaCleanupScrutinizeOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = DeleteMethod
+		httpRequest.buildNMAEndpoint(scrutinizeURLPrefix + "staged")
+		httpRequest.QueryParams = map[string]string{"retention_hours": strconv.Itoa(op.retentionHours)}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaCleanupScrutinizeOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaCleanupScrutinizeOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaCleanupScrutinizeOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
+func (op *nmaCleanupScrutinizeOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaCleanupScrutinizeOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		// the response object will be a dictionary, e.g.,:
+		// {"removed_ids": ["VerticaScrutinize.20240101120000"], "freed_bytes": 1073741824}
+		var cleanup ScrutinizeCleanup
+		err := result.err
+		if result.isPassing() {
+			err = op.parseAndCheckResponse(host, result.content, &cleanup)
+		}
+		if err != nil {
+			if op.bestEffort {
+				op.logger.PrintWarning("Failed to delete stale scrutinize staging data on host %s. Skipping.", host)
+				continue
+			}
+			allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to delete stale scrutinize staging data on host %s, details: %w",
+				op.name, host, err))
+			continue
+		}
+		cleanup.Host = host
+		op.hostCleanups[host] = cleanup
+		op.logger.Info("Deleted stale scrutinize staging data", "Host", host,
+			"Collections", len(cleanup.RemovedIDs), "Bytes", cleanup.FreedBytes)
+	}
+
+	return allErrs
+}
@@ -0,0 +1,184 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// nmaDataCollectorOp reads or sets the retention policies of Data Collector
+// components, or clears their data. The policies are the same on every node,
+// so the request is sent to one up host of the sandbox or main cluster.
+type nmaDataCollectorOp struct {
+	opBase
+	endpoint        string
+	hostRequestBody string
+	sandbox         string
+	initiator       string
+	// out parameter of the get policies request
+	policies *[]DataCollectorPolicy
+}
+
+type dataCollectorRequestData struct {
+	sqlEndpointData
+	// all components if empty
+	Components      []string `json:"components,omitempty"`
+	MemoryBufferKB  *int64   `json:"memory_buffer_kb,omitempty"`
+	DiskSizeKB      *int64   `json:"disk_size_kb,omitempty"`
+	IntervalSeconds *int64   `json:"interval_seconds,omitempty"`
+}
+
+const (
+	dataCollectorGetPoliciesEndpoint = "data-collector/policies/get"
+	dataCollectorSetPolicyEndpoint   = "data-collector/policies/set"
+	dataCollectorClearEndpoint       = "data-collector/clear"
+)
+
+func makeNMAGetDataCollectorPoliciesOp(hosts []string, username, dbName, sandbox string, components []string,
+	policies *[]DataCollectorPolicy /* out parameter */, password *string, useHTTPPassword bool) (nmaDataCollectorOp, error) {
+	if policies == nil {
+		return nmaDataCollectorOp{}, errors.New("argument policies cannot be a nil pointer")
+	}
+	requestData := dataCollectorRequestData{Components: components}
+	op, err := makeNMADataCollectorOp(hosts, username, dbName, sandbox, dataCollectorGetPoliciesEndpoint,
+		&requestData, password, useHTTPPassword)
+	op.description = "Get Data Collector policies"
+	op.policies = policies
+	return op, err
+}
+
+func makeNMASetDataCollectorPolicyOp(hosts []string, username, dbName, sandbox string, components []string,
+	memoryBufferKB, diskSizeKB, intervalSeconds *int64, password *string, useHTTPPassword bool) (nmaDataCollectorOp, error) {
+	requestData := dataCollectorRequestData{
+		Components:      components,
+		MemoryBufferKB:  memoryBufferKB,
+		DiskSizeKB:      diskSizeKB,
+		IntervalSeconds: intervalSeconds,
+	}
+	op, err := makeNMADataCollectorOp(hosts, username, dbName, sandbox, dataCollectorSetPolicyEndpoint,
+		&requestData, password, useHTTPPassword)
+	op.description = "Set Data Collector policies"
+	return op, err
+}
+
+func makeNMAClearDataCollectorOp(hosts []string, username, dbName, sandbox string, components []string,
+	password *string, useHTTPPassword bool) (nmaDataCollectorOp, error) {
+	requestData := dataCollectorRequestData{Components: components}
+	op, err := makeNMADataCollectorOp(hosts, username, dbName, sandbox, dataCollectorClearEndpoint,
+		&requestData, password, useHTTPPassword)
+	op.description = "Clear Data Collector data"
+	return op, err
+}
+
+func makeNMADataCollectorOp(hosts []string, username, dbName, sandbox, endpoint string,
+	requestData *dataCollectorRequestData, password *string, useHTTPPassword bool) (nmaDataCollectorOp, error) {
+	op := nmaDataCollectorOp{}
+	op.name = "NMADataCollectorOp"
+	op.hosts = hosts
+	op.sandbox = sandbox
+	op.endpoint = endpoint
+
+	err := ValidateSQLEndpointData(op.name, useHTTPPassword, username, password, dbName)
+	if err != nil {
+		return op, err
+	}
+	requestData.sqlEndpointData = createSQLEndpointData(username, dbName, useHTTPPassword, password)
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaDataCollectorOp) setupClusterHTTPRequest(initiator string) error {
+	httpRequest := hostHTTPRequest{}
+	httpRequest.Method = PostMethod
+	httpRequest.buildNMAEndpoint(op.endpoint)
+	httpRequest.RequestData = op.hostRequestBody
+	op.clusterHTTPRequest.RequestCollection[initiator] = httpRequest
+
+	return nil
+}
+
+func (op *nmaDataCollectorOp) prepare(execContext *opEngineExecContext) error {
+	// select an up host in the sandbox or main cluster as the initiator
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	initiator, err := getInitiatorInCluster(op.sandbox, candidates, execContext.upHostsToSandboxes)
+	if err != nil {
+		return err
+	}
+	op.initiator = initiator
+	execContext.dispatcher.setup([]string{op.initiator})
+	return op.setupClusterHTTPRequest(op.initiator)
+}
+
+func (op *nmaDataCollectorOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the requests are POST requests, the policies are read through one as well
+func (op *nmaDataCollectorOp) getClassification() OpClassification {
+	switch op.endpoint {
+	case dataCollectorGetPoliciesEndpoint:
+		return readOnlyClassification
+	case dataCollectorClearEndpoint:
+		return OpClassification{Mutating: true, Destructive: true, Idempotent: true}
+	default:
+		return idempotentClassification
+	}
+}
+
+func (op *nmaDataCollectorOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaDataCollectorOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		if op.policies == nil {
+			_, err := op.parseAndCheckStringResponse(host, result.content)
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		// the response object will be a list of policies, e.g.,:
+		// [{"component": "RequestsIssued", "description": "Requests issued", "memory_buffer_kb": 1000,
+		//   "disk_size_kb": 10000, "interval_seconds": 0}]
+		err := op.parseAndCheckResponse(host, result.content, op.policies)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+		}
+	}
+
+	return allErrs
+}
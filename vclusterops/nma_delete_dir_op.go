@@ -143,6 +143,10 @@ func (op *nmaDeleteDirectoriesOp) execute(execContext *opEngineExecContext) erro
 	return op.processResult(execContext)
 }
 
+func (op *nmaDeleteDirectoriesOp) getClassification() OpClassification {
+	return OpClassification{Mutating: true, Destructive: true, Idempotent: true}
+}
+
 func (op *nmaDeleteDirectoriesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -22,6 +22,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
 type leaseCheckOption int
@@ -42,7 +44,7 @@ const (
 
 type nmaDownloadFileOp struct {
 	opBase
-	hostRequestBodyMap map[string]string
+	hostRequestBody string
 	// vdb will be used to save downloaded file info for revive_db
 	vdb *VCoordinationDatabase
 	// newNodes is used to verify node number in http response for revive_db
@@ -62,60 +64,32 @@ type downloadFileRequestData struct {
 	Parameters          map[string]string `json:"parameters,omitempty"`
 }
 
-// ClusterLeaseNotExpiredError is returned when you attempt to access a
-// communal storage location when there is an active cluster lease on it.
-type ClusterLeaseNotExpiredError struct {
-	Expiration string
-}
-
-func (e *ClusterLeaseNotExpiredError) Error() string {
-	return fmt.Sprintf("revive database cannot continue because the communal storage location might still be in use."+
-		" The cluster lease will expire at %s(UTC)."+
-		" Please ensure that the other cluster has stopped and try revive_db after the cluster lease expiration",
-		e.Expiration)
-}
-
-// ReviveDBNodeCountMismatchError is the error that is returned when the number of
-// nodes in the revived cluster does not match the number of nodes in the original cluster.
-type ReviveDBNodeCountMismatchError struct {
-	ReviveDBStep  string
-	FailureHost   string
-	NumOfNewNodes int
-	NumOfOldNodes int
-}
-
-func (e *ReviveDBNodeCountMismatchError) Error() string {
-	return fmt.Sprintf(`[%s] nodes mismatch found on host %s: the number of the new nodes in --hosts is %d,`+
-		` but the number of primary nodes in the description file is %d`,
-		e.ReviveDBStep, e.FailureHost, e.NumOfNewNodes, e.NumOfOldNodes)
-}
+type (
+	ClusterLeaseNotExpiredError    = vtypes.ClusterLeaseNotExpiredError
+	ReviveDBNodeCountMismatchError = vtypes.ReviveDBNodeCountMismatchError
+)
 
 func makeNMADownloadFileOp(newNodes []string, sourceFilePath, destinationFilePath, catalogPath string,
 	configurationParameters map[string]string, vdb *VCoordinationDatabase) (nmaDownloadFileOp, error) {
 	op := nmaDownloadFileOp{}
 	op.name = "NMADownloadFileOp"
 	op.description = fmt.Sprintf("Download %s", filepath.Base(sourceFilePath))
-	initiator := getInitiator(newNodes)
-	op.hosts = []string{initiator}
+	op.hosts = newNodes
 	op.vdb = vdb
 	op.newNodes = newNodes
 
 	// make https json data
-	op.hostRequestBodyMap = make(map[string]string)
-	for _, host := range op.hosts {
-		requestData := downloadFileRequestData{}
-		requestData.SourceFilePath = sourceFilePath
-		requestData.DestinationFilePath = destinationFilePath
-		requestData.CatalogPath = catalogPath
-		requestData.Parameters = configurationParameters
-
-		dataBytes, err := json.Marshal(requestData)
-		if err != nil {
-			return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
-		}
+	requestData := downloadFileRequestData{}
+	requestData.SourceFilePath = sourceFilePath
+	requestData.DestinationFilePath = destinationFilePath
+	requestData.CatalogPath = catalogPath
+	requestData.Parameters = configurationParameters
 
-		op.hostRequestBodyMap[host] = string(dataBytes)
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
 	}
+	op.hostRequestBody = string(dataBytes)
 
 	return op, nil
 }
@@ -165,7 +139,7 @@ func (op *nmaDownloadFileOp) setupClusterHTTPRequest(hosts []string) error {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
 		httpRequest.buildNMAEndpoint("vertica/download-file")
-		httpRequest.RequestData = op.hostRequestBodyMap[host]
+		httpRequest.RequestData = op.hostRequestBody
 
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
@@ -174,8 +148,18 @@ func (op *nmaDownloadFileOp) setupClusterHTTPRequest(hosts []string) error {
 }
 
 func (op *nmaDownloadFileOp) prepare(execContext *opEngineExecContext) error {
-	execContext.dispatcher.setup(op.hosts)
-	return op.setupClusterHTTPRequest(op.hosts)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("[%s] no hosts to download the file to", op.name)
+	}
+	// the file is downloaded to a single host
+	initiator := []string{getInitiator(candidates)}
+	execContext.dispatcher.setup(initiator)
+
+	return op.setupClusterHTTPRequest(initiator)
 }
 
 func (op *nmaDownloadFileOp) execute(execContext *opEngineExecContext) error {
@@ -186,6 +170,10 @@ func (op *nmaDownloadFileOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaDownloadFileOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaDownloadFileOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -93,7 +93,11 @@ func (op *nmaGetConfigurationParameterOp) setupClusterHTTPRequest(initiator stri
 
 func (op *nmaGetConfigurationParameterOp) prepare(execContext *opEngineExecContext) error {
 	// select an up host in the sandbox or main cluster as the initiator
-	initiator, err := getInitiatorInCluster(op.sandbox, op.hosts, execContext.upHostsToSandboxes)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	initiator, err := getInitiatorInCluster(op.sandbox, candidates, execContext.upHostsToSandboxes)
 	if err != nil {
 		return err
 	}
@@ -110,6 +114,11 @@ func (op *nmaGetConfigurationParameterOp) execute(execContext *opEngineExecConte
 	return op.processResult(execContext)
 }
 
+// the parameter is read through a POST request
+func (op *nmaGetConfigurationParameterOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
 func (op *nmaGetConfigurationParameterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+)
+
+type nmaGetHardwareInventoryOp struct {
+	opBase
+	// out parameter, the hardware of each host
+	hostHardware map[string]HostHardware
+}
+
+func makeNMAGetHardwareInventoryOp(hosts []string, hostHardware map[string]HostHardware) (nmaGetHardwareInventoryOp, error) {
+	op := nmaGetHardwareInventoryOp{}
+	op.name = "NMAGetHardwareInventoryOp"
+	op.description = "Collect hardware inventory"
+	op.hosts = hosts
+	if hostHardware == nil {
+		return op, errors.New("argument hostHardware cannot be a nil map")
+	}
+	op.hostHardware = hostHardware
+	return op, nil
+}
+
+func (op *nmaGetHardwareInventoryOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		httpRequest.buildNMAEndpoint("hardware")
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaGetHardwareInventoryOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaGetHardwareInventoryOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaGetHardwareInventoryOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaGetHardwareInventoryOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		// the response object will be a dictionary, e.g.,:
+		// {"cpu": {"model": "Intel(R) Xeon(R) Gold 6230", "sockets": 2, "cores": 40, "threads": 80},
+		//  "memory_bytes": 270582939648,
+		//  "disks": [{"device": "/dev/nvme0n1", "mount_point": "/data", "size_bytes": 1920383410176, "rotational": false}],
+		//  "nics": [{"name": "eth0", "address": "192.168.1.101", "speed_mbps": 25000}],
+		//  "numa_nodes": [{"id": 0, "cpus": [0, 1, ...], "memory_bytes": 135291469824}, ...],
+		//  "time": {"time_zone": "America/New_York", "locale": "en_US.UTF-8", "ntp_source": "0.pool.ntp.org"}}
+		var hardware HostHardware
+		err := op.parseAndCheckResponse(host, result.content, &hardware)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		if hardware.CPU.Threads == 0 || hardware.MemoryBytes == 0 {
+			err = fmt.Errorf(`[%s] response of host %s does not contain the CPU and memory of the host`, op.name, host)
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		hardware.Host = host
+		op.hostHardware[host] = hardware
+	}
+
+	return allErrs
+}
@@ -19,13 +19,17 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
 
 type nmaGetScrutinizeTarOp struct {
 	scrutinizeOpBase
-	useInitiator bool
+	useInitiator       bool
+	bestEffort         bool
+	manifest           *ScrutinizeManifest
+	hostToFilePathsMap map[string]string
 }
 
 func makeNMAGetScrutinizeTarOp(
@@ -60,6 +64,18 @@ func (op *nmaGetScrutinizeTarOp) useSingleHost() {
 	op.useInitiator = true
 }
 
+// useBestEffort indicates that a failure to retrieve the tarball from a host
+// should only be a warning, for batches that are not collected on every host
+func (op *nmaGetScrutinizeTarOp) useBestEffort() {
+	op.bestEffort = true
+}
+
+// useManifest records the retrieved tarballs and the failures in the manifest
+// of the bundle
+func (op *nmaGetScrutinizeTarOp) useManifest(manifest *ScrutinizeManifest) {
+	op.manifest = manifest
+}
+
 // createOutputDir creates a subdirectory {id} under /tmp/scrutinize/remote, which
 // may also be created by this function.  the "remote" subdirectory is created to
 // separate local scrutinize data staged by the NMA (placed in /tmp/scrutinize/) from
@@ -89,7 +105,11 @@ func (op *nmaGetScrutinizeTarOp) prepare(execContext *opEngineExecContext) error
 			return nil
 		}
 
-		host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+		candidates, err := execContext.initiatorCandidates(op.hosts)
+		if err != nil {
+			return err
+		}
+		host := getInitiatorFromUpHosts(execContext.upHosts, candidates)
 		if host == "" {
 			op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
 			op.skipExecute = true
@@ -99,27 +119,43 @@ func (op *nmaGetScrutinizeTarOp) prepare(execContext *opEngineExecContext) error
 		op.hosts = []string{host}
 	}
 
-	hostToFilePathsMap := map[string]string{}
+	op.hostToFilePathsMap = map[string]string{}
 	for _, host := range op.hosts {
-		hostToFilePathsMap[host] = fmt.Sprintf("%s/%s/%s-%s.tgz",
+		op.hostToFilePathsMap[host] = fmt.Sprintf("%s/%s/%s-%s.tgz",
 			scrutinizeRemoteOutputPath,
 			op.id,
 			op.hostNodeNameMap[host],
 			op.batch)
 	}
-	execContext.dispatcher.setupForDownload(op.hosts, hostToFilePathsMap)
+	execContext.dispatcher.setupForDownload(op.hosts, op.hostToFilePathsMap)
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
 
 func (op *nmaGetScrutinizeTarOp) execute(execContext *opEngineExecContext) error {
+	start := time.Now()
 	if err := op.runExecute(execContext); err != nil {
 		return err
 	}
+	op.recordInManifest(time.Since(start))
 
 	return op.processResult(execContext)
 }
 
+func (op *nmaGetScrutinizeTarOp) recordInManifest(duration time.Duration) {
+	if op.manifest == nil {
+		return
+	}
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		nodeName := op.hostNodeNameMap[host]
+		if result.isPassing() {
+			op.manifest.addBatch(op.batch, nodeName, host, op.hostToFilePathsMap[host], duration)
+		} else {
+			op.manifest.addError(op.batch, nodeName, host, result.err)
+		}
+	}
+}
+
 func (op *nmaGetScrutinizeTarOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -140,7 +176,7 @@ func (op *nmaGetScrutinizeTarOp) processResult(_ *opEngineExecContext) error {
 				"Host", host,
 				"Node", op.hostNodeNameMap[host],
 				"Batch", op.batch)
-			if result.isInternalError() {
+			if result.isInternalError() || op.bestEffort {
 				op.logger.PrintWarning("Failed to tar batch %s on host %s. Skipping.", op.batch, host)
 			} else {
 				err := fmt.Errorf("failed to retrieve tarball batch %s on host %s, details %w",
@@ -17,6 +17,7 @@ package vclusterops
 
 import (
 	"errors"
+	"fmt"
 )
 
 // we limit the health check timeout to 30 seconds
@@ -28,6 +29,8 @@ type nmaHealthOp struct {
 	// sometimes, we need to skip unreachable hosts
 	// e.g., list_all_nodes may need this when the host(s) are not connectable
 	skipUnreachableHost bool
+	// how long to wait for the restarted NMA services to respond
+	recoveryTimeout int
 }
 
 func makeNMAHealthOp(hosts []string) nmaHealthOp {
@@ -100,5 +103,79 @@ func (op *nmaHealthOp) processResult(execContext *opEngineExecContext) error {
 		return nil
 	}
 
+	if len(unreachableHosts) > 0 && execContext.nmaRecovery != nil {
+		return op.recoverHosts(execContext, unreachableHosts, allErrs)
+	}
 	return allErrs
 }
+
+// recoverHosts restarts the NMA service on the hosts that did not respond,
+// and waits for it to respond again. The health check fails with the errors of
+// the hosts if the service cannot be restarted or does not come back.
+func (op *nmaHealthOp) recoverHosts(execContext *opEngineExecContext, hosts []string, healthErr error) error {
+	recovery := execContext.nmaRecovery
+	if err := recovery.validate(); err != nil {
+		return errors.Join(healthErr, err)
+	}
+	op.logger.PrintWarning("[%s] NMA service does not respond on hosts %v, restarting it", op.name, hosts)
+
+	healthRequests := op.clusterHTTPRequest.RequestCollection
+	op.setupRestartRequests(hosts, recovery)
+	if err := op.runExecute(execContext); err != nil {
+		return errors.Join(healthErr, err)
+	}
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+		if !result.isPassing() {
+			return errors.Join(healthErr, fmt.Errorf("[%s] fail to restart NMA service on host %s: %w",
+				op.name, host, result.err))
+		}
+	}
+
+	// wait for the restarted services to respond
+	op.clusterHTTPRequest.RequestCollection = make(map[string]hostHTTPRequest, len(hosts))
+	for _, host := range hosts {
+		op.clusterHTTPRequest.RequestCollection[host] = healthRequests[host]
+	}
+	op.recoveryTimeout = recovery.getTimeout()
+	err := op.runExecute(execContext)
+	if err == nil {
+		err = pollState(op, execContext)
+	}
+	if err != nil {
+		return errors.Join(healthErr, fmt.Errorf("[%s] NMA service did not respond after its restart: %w", op.name, err))
+	}
+	op.logger.PrintWarning("[%s] NMA service was restarted on hosts %v", op.name, hosts)
+	return nil
+}
+
+// setupRestartRequests replaces the health check requests of hosts by the
+// requests to restart their NMA service, which keep the TLS options of the
+// health check requests
+func (op *nmaHealthOp) setupRestartRequests(hosts []string, recovery *NMARecovery) {
+	healthRequests := op.clusterHTTPRequest.RequestCollection
+	op.clusterHTTPRequest.RequestCollection = make(map[string]hostHTTPRequest, len(hosts))
+	for _, host := range hosts {
+		request := healthRequests[host]
+		request.Method = PostMethod
+		request.Endpoint = recovery.getRestartEndpoint()
+		request.Port = recovery.AgentPort
+		op.clusterHTTPRequest.RequestCollection[host] = request
+	}
+}
+
+func (op *nmaHealthOp) getPollingTimeout() int {
+	return op.recoveryTimeout
+}
+
+// shouldStopPolling returns whether the NMA service responds on every host
+// that it was restarted on
+func (op *nmaHealthOp) shouldStopPolling() (bool, error) {
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+		if !result.isPassing() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
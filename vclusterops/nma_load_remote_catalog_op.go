@@ -33,6 +33,9 @@ type nmaLoadRemoteCatalogOp struct {
 	primaryNodeCount        uint
 	restorePoint            *RestorePointPolicy
 	Sandbox                 string
+	// the namespaces and schemas to revive, all of them if both are empty
+	namespaces []string
+	schemas    []string
 }
 
 type loadRemoteCatalogRequestData struct {
@@ -50,6 +53,15 @@ type loadRemoteCatalogRequestData struct {
 	RestorePointIndex   int                 `json:"restore_point_index,omitempty"`
 	RestorePointID      string              `json:"restore_point_id,omitempty"`
 	Sandbox             string              `json:"sandbox,omitempty"`
+	Namespaces          []string            `json:"namespaces,omitempty"`
+	Schemas             []string            `json:"schemas,omitempty"`
+}
+
+type loadRemoteCatalogResponse struct {
+	httpsResponseStatus
+	// whether the host revived only the requested namespaces and schemas
+	PartialRevive  bool                  `json:"partial_revive"`
+	OmittedObjects []ReviveOmittedObject `json:"omitted_objects"`
 }
 
 func makeNMALoadRemoteCatalogOp(oldHosts []string, configurationParameters map[string]string,
@@ -120,6 +132,8 @@ func (op *nmaLoadRemoteCatalogOp) setupRequestBody(execContext *opEngineExecCont
 		if op.Sandbox != util.MainClusterSandbox {
 			requestData.Sandbox = op.Sandbox
 		}
+		requestData.Namespaces = op.namespaces
+		requestData.Schemas = op.schemas
 
 		dataBytes, err := json.Marshal(requestData)
 		if err != nil {
@@ -164,11 +178,36 @@ func (op *nmaLoadRemoteCatalogOp) execute(execContext *opEngineExecContext) erro
 	return op.processResult(execContext)
 }
 
+func (op *nmaLoadRemoteCatalogOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *nmaLoadRemoteCatalogOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
 
-func (op *nmaLoadRemoteCatalogOp) processResult(_ *opEngineExecContext) error {
+func (op *nmaLoadRemoteCatalogOp) isPartialRevive() bool {
+	return len(op.namespaces) > 0 || len(op.schemas) > 0
+}
+
+// checkPartialRevive checks that a host revived only the requested namespaces
+// and schemas, and keeps the objects that were omitted
+func (op *nmaLoadRemoteCatalogOp) checkPartialRevive(host string, response *loadRemoteCatalogResponse,
+	execContext *opEngineExecContext) error {
+	if !op.isPartialRevive() {
+		return nil
+	}
+	if !response.PartialRevive {
+		return &PartialReviveNotSupportedError{Host: host}
+	}
+	// every host loads the same catalog, so the omitted objects of one host are enough
+	if execContext.omittedObjects == nil {
+		execContext.omittedObjects = append([]ReviveOmittedObject{}, response.OmittedObjects...)
+	}
+	return nil
+}
+
+func (op *nmaLoadRemoteCatalogOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 	var successPrimaryNodeCount uint
 
@@ -176,14 +215,20 @@ func (op *nmaLoadRemoteCatalogOp) processResult(_ *opEngineExecContext) error {
 		op.logResponse(host, result)
 
 		if result.isPassing() {
-			response := httpsResponseStatus{}
+			response := loadRemoteCatalogResponse{}
 			err := op.parseAndCheckResponse(host, result.content, &response)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				continue
 			}
 
-			err = op.checkResponseStatusCode(response, host)
+			err = op.checkResponseStatusCode(response.httpsResponseStatus, host)
+			if err != nil {
+				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+
+			err = op.checkPartialRevive(host, &response, execContext)
 			if err != nil {
 				allErrs = errors.Join(allErrs, err)
 				continue
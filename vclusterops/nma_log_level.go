@@ -0,0 +1,117 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/slices"
+)
+
+// log levels of the NMA
+const (
+	NMALogLevelDebug   = "debug"
+	NMALogLevelInfo    = "info"
+	NMALogLevelWarning = "warning"
+	NMALogLevelError   = "error"
+)
+
+var nmaLogLevels = []string{NMALogLevelDebug, NMALogLevelInfo, NMALogLevelWarning, NMALogLevelError}
+
+type VNMALogLevelOptions struct {
+	// basic db info, only the hosts are required
+	DatabaseOptions
+	// the log level to set on the hosts, the log levels are only read if empty
+	LogLevel string
+}
+
+func VNMALogLevelOptionsFactory() VNMALogLevelOptions {
+	options := VNMALogLevelOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VNMALogLevelOptions) validateParseOptions(logger vlog.Printer) error {
+	cmdType := NMALogLevelCmd
+	if options.LogLevel == "" {
+		cmdType = GetNMALogLevelCmd
+	}
+	err := options.validateBaseOptions(cmdType, logger)
+	if err != nil {
+		return err
+	}
+
+	options.LogLevel = strings.ToLower(options.LogLevel)
+	if options.LogLevel != "" && !slices.Contains(nmaLogLevels, options.LogLevel) {
+		return fmt.Errorf("invalid NMA log level %s, it must be one of %v", options.LogLevel, nmaLogLevels)
+	}
+	return nil
+}
+
+func (options *VNMALogLevelOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VNMALogLevelOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VNMALogLevel gets or sets the log level of the NMA on the hosts at runtime,
+// e.g., to turn on debug logging before reproducing an issue. It returns the
+// log level of each host, after the log level is set.
+func (vcc VClusterCommands) VNMALogLevel(options *VNMALogLevelOptions) (map[string]string, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	hostLogLevels := make(map[string]string)
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaLogLevelOp, err := makeNMALogLevelOp(options.Hosts, options.LogLevel, hostLogLevels)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	instructions := []clusterOp{&nmaHealthOp, &nmaLogLevelOp}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return hostLogLevels, fmt.Errorf("fail to manage NMA log level: %w", runError)
+	}
+
+	return hostLogLevels, nil
+}
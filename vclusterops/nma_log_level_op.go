@@ -0,0 +1,129 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type nmaLogLevelOp struct {
+	opBase
+	// the log level to set, the log level is only read if empty
+	logLevel string
+	// out parameter, the log level of each host
+	hostLogLevels map[string]string
+}
+
+type nmaLogLevelData struct {
+	LogLevel string `json:"log_level"`
+}
+
+func makeNMALogLevelOp(hosts []string, logLevel string, hostLogLevels map[string]string) (nmaLogLevelOp, error) {
+	op := nmaLogLevelOp{}
+	op.name = "NMALogLevelOp"
+	op.hosts = hosts
+	op.logLevel = logLevel
+	if logLevel == "" {
+		op.description = "Get NMA log level"
+	} else {
+		op.description = fmt.Sprintf("Set NMA log level to %s", logLevel)
+	}
+	if hostLogLevels == nil {
+		return op, errors.New("argument hostLogLevels cannot be a nil map")
+	}
+	op.hostLogLevels = hostLogLevels
+	return op, nil
+}
+
+func (op *nmaLogLevelOp) setupClusterHTTPRequest(hosts []string) error {
+	var requestData string
+	if op.logLevel != "" {
+		dataBytes, err := json.Marshal(nmaLogLevelData{LogLevel: op.logLevel})
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+		requestData = string(dataBytes)
+	}
+
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = GetMethod
+		if op.logLevel != "" {
+			httpRequest.Method = PutMethod
+			httpRequest.RequestData = requestData
+		}
+		httpRequest.buildNMAEndpoint("log-level")
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaLogLevelOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaLogLevelOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaLogLevelOp) getClassification() OpClassification {
+	if op.logLevel == "" {
+		return readOnlyClassification
+	}
+	return idempotentClassification
+}
+
+func (op *nmaLogLevelOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaLogLevelOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPassing() {
+			// the response object will be a dictionary, e.g.,:
+			// {"log_level": "debug"}
+			var responseObj nmaLogLevelData
+			err := op.parseAndCheckResponse(host, result.content, &responseObj)
+			if err != nil {
+				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+			if responseObj.LogLevel == "" {
+				err = fmt.Errorf(`[%s] response does not contain field "log_level"`, op.name)
+				allErrs = errors.Join(allErrs, err)
+				continue
+			}
+			op.hostLogLevels[host] = responseObj.LogLevel
+		} else {
+			allErrs = errors.Join(allErrs, result.err)
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,71 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestVNMALogLevelOptions(t *testing.T) {
+	logger := vlog.Printer{}
+
+	opt := VNMALogLevelOptionsFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"192.168.1.101"}
+	assert.NoError(t, opt.validateAnalyzeOptions(logger))
+	opt.LogLevel = "DEBUG"
+	assert.NoError(t, opt.validateAnalyzeOptions(logger))
+	assert.Equal(t, NMALogLevelDebug, opt.LogLevel)
+
+	// negative: unknown log level
+	opt.LogLevel = "verbose"
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "invalid NMA log level verbose")
+}
+
+func TestNMALogLevelOp(t *testing.T) {
+	const host = "192.168.1.101"
+	hostLogLevels := make(map[string]string)
+
+	// the log level is read when no log level is given
+	op, err := makeNMALogLevelOp([]string{host}, "", hostLogLevels)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	assert.Equal(t, GetMethod, op.clusterHTTPRequest.RequestCollection[host].Method)
+	assert.False(t, op.getClassification().Mutating)
+
+	op, err = makeNMALogLevelOp([]string{host}, NMALogLevelDebug, hostLogLevels)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	request := op.clusterHTTPRequest.RequestCollection[host]
+	assert.Equal(t, PutMethod, request.Method)
+	assert.JSONEq(t, `{"log_level": "debug"}`, request.RequestData)
+	assert.True(t, op.getClassification().Mutating)
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		host: {status: SUCCESS, content: `{"log_level": "debug"}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, map[string]string{host: NMALogLevelDebug}, hostLogLevels)
+
+	// negative: the response has no log level
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: {status: SUCCESS, content: `{}`}}
+	assert.ErrorContains(t, op.processResult(nil), `response does not contain field "log_level"`)
+}
@@ -93,7 +93,11 @@ func (op *nmaManageConnectionsOp) setupClusterHTTPRequest(initiator string, acti
 
 func (op *nmaManageConnectionsOp) prepare(execContext *opEngineExecContext) error {
 	// select an up host in the sandbox or main cluster as the initiator
-	initiator, err := getInitiatorInCluster(op.sandbox, op.hosts, execContext.upHostsToSandboxes)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	initiator, err := getInitiatorInCluster(op.sandbox, candidates, execContext.upHostsToSandboxes)
 	if err != nil {
 		return err
 	}
@@ -110,6 +114,10 @@ func (op *nmaManageConnectionsOp) execute(execContext *opEngineExecContext) erro
 	return op.processResult(execContext)
 }
 
+func (op *nmaManageConnectionsOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaManageConnectionsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,116 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// nmaMoveCommunalMetadataOp moves the metadata directory of a database in
+// communal storage, {communal_storage_location}/metadata/{db_name}, to the
+// directory of another name, e.g., when the database is renamed. One NMA is
+// enough to reach communal storage.
+type nmaMoveCommunalMetadataOp struct {
+	opBase
+	hostRequestBody string
+}
+
+type moveCommunalMetadataRequestData struct {
+	SourcePath      string            `json:"source_path"`
+	DestinationPath string            `json:"destination_path"`
+	Parameters      map[string]string `json:"parameters,omitempty"`
+}
+
+func makeNMAMoveCommunalMetadataOp(hosts []string, communalStorageLocation, dbName, newDBName string,
+	configurationParameters map[string]string) (nmaMoveCommunalMetadataOp, error) {
+	op := nmaMoveCommunalMetadataOp{}
+	op.name = "NMAMoveCommunalMetadataOp"
+	op.description = fmt.Sprintf("Move communal metadata of database to %s", newDBName)
+	op.hosts = hosts
+
+	requestData := moveCommunalMetadataRequestData{
+		SourcePath:      getCommunalMetadataPath(communalStorageLocation, dbName),
+		DestinationPath: getCommunalMetadataPath(communalStorageLocation, newDBName),
+		Parameters:      configurationParameters,
+	}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaMoveCommunalMetadataOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("communal-storage/move-metadata")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaMoveCommunalMetadataOp) prepare(execContext *opEngineExecContext) error {
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("[%s] no hosts to reach communal storage from", op.name)
+	}
+	initiator := []string{getInitiator(candidates)}
+	execContext.dispatcher.setup(initiator)
+
+	return op.setupClusterHTTPRequest(initiator)
+}
+
+func (op *nmaMoveCommunalMetadataOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the metadata is no longer at its source path after the move
+func (op *nmaMoveCommunalMetadataOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
+func (op *nmaMoveCommunalMetadataOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaMoveCommunalMetadataOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPassing() {
+			return nil
+		}
+		allErrs = errors.Join(allErrs, result.err)
+	}
+
+	return allErrs
+}
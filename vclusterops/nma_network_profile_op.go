@@ -24,6 +24,9 @@ import (
 
 type nmaNetworkProfileOp struct {
 	opBase
+	// the profile of a host with a control address is the one of its control
+	// network, keyed by host
+	controlAddresses map[string]string
 }
 
 func makeNMANetworkProfileOp(hosts []string) nmaNetworkProfileOp {
@@ -34,12 +37,22 @@ func makeNMANetworkProfileOp(hosts []string) nmaNetworkProfileOp {
 	return op
 }
 
+func makeNMANetworkProfileOpWithControlAddresses(hosts []string, controlAddresses map[string]string) nmaNetworkProfileOp {
+	op := makeNMANetworkProfileOp(hosts)
+	op.controlAddresses = controlAddresses
+	return op
+}
+
 func (op *nmaNetworkProfileOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = GetMethod
 		httpRequest.buildNMAEndpoint("network-profiles")
-		httpRequest.QueryParams = map[string]string{"broadcast-hint": host}
+		broadcastHint := host
+		if controlAddress, ok := op.controlAddresses[host]; ok {
+			broadcastHint = controlAddress
+		}
+		httpRequest.QueryParams = map[string]string{"broadcast-hint": broadcastHint}
 
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
@@ -0,0 +1,191 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// replicationProgressJob is an asynchronous replication job, which is polled
+// until it completes or fails
+type replicationProgressJob struct {
+	nmaReplicationStatusRequestData
+	replicationStatus *ReplicationStatusResponse
+	// optional, the throughput of the job between polls
+	throughput *replicationThroughput
+}
+
+// makeNMAPollReplicationProgressOp makes an op that polls the status of an
+// asynchronous replication job until it completes or fails. When the polled
+// target host stops responding, polling fails over to the next target host.
+func makeNMAPollReplicationProgressOp(targetHosts []string, targetUsePassword bool,
+	replicationStatusData *nmaReplicationStatusRequestData, timeout int,
+	replicationStatus *ReplicationStatusResponse, throughput *replicationThroughput) (nmaAsyncJobOp, error) {
+	job := &replicationProgressJob{}
+	job.nmaReplicationStatusRequestData = *replicationStatusData
+	job.replicationStatus = replicationStatus
+	job.throughput = throughput
+	op := makeNMAAsyncJobOp("NMAPollReplicationProgressOp", "Wait for asynchronous replication to finish",
+		targetHosts, job, timeout)
+
+	if targetUsePassword {
+		err := util.ValidateUsernameAndPassword(op.name, targetUsePassword, replicationStatusData.UserName)
+		if err != nil {
+			return op, err
+		}
+	}
+
+	return op, nil
+}
+
+func (job *replicationProgressJob) getName() string {
+	return "replication status"
+}
+
+func (job *replicationProgressJob) getStatusRequest() (hostHTTPRequest, error) {
+	return makeReplicationStatusRequest(&job.nmaReplicationStatusRequestData)
+}
+
+func (job *replicationProgressJob) checkStatus(op *nmaAsyncJobOp, host, responseContent string) (bool, error) {
+	responseObj := []ReplicationStatusResponse{}
+	err := op.parseAndCheckResponse(host, responseContent, &responseObj)
+	if err != nil {
+		return true, err
+	}
+	if len(responseObj) == 0 {
+		return true, fmt.Errorf("invalid transaction ID %d", job.TransactionID)
+	}
+
+	status := getFinalReplicationStatus(responseObj)
+	*job.replicationStatus = *status
+	if job.throughput != nil {
+		progress := job.throughput.update(status, time.Now())
+		if progress.Stalled && !job.throughput.stallReported {
+			job.throughput.stallReported = true
+			op.logger.PrintWarning("[%s] replication with transaction ID %d made no progress for %s",
+				op.name, status.TransactionID, progress.StalledFor.Round(time.Second))
+		}
+		if job.throughput.onProgress != nil {
+			job.throughput.onProgress(progress)
+		}
+		if progress.Stalled && job.throughput.failOnStall && !isReplicationFinished(status) {
+			return true, &ReplicationStalledError{Status: *status, StalledFor: progress.StalledFor, Host: host}
+		}
+	}
+	return isReplicationFinished(status), nil
+}
+
+// replicationThroughput computes the throughput of a replication job between
+// the polls of its status, and detects a job that stopped making progress
+type replicationThroughput struct {
+	onProgress func(ReplicationProgress)
+	// no stall is detected if <= 0
+	stallTimeout time.Duration
+	// whether polling stops with a ReplicationStalledError on a stall
+	failOnStall bool
+	// when the job started, and the previous poll, and when the job last made
+	// progress
+	start         time.Time
+	last          *ReplicationStatusResponse
+	lastTime      time.Time
+	lastProgress  time.Time
+	stallReported bool
+}
+
+// update returns the progress of the job since the previous poll. Any change
+// of the op, its status, or its counters is progress. The counters restart
+// with each op, so a decrease is not counted as throughput.
+func (t *replicationThroughput) update(status *ReplicationStatusResponse, now time.Time) ReplicationProgress {
+	progress := ReplicationProgress{Status: *status, Percent: getReplicationPercent(status)}
+	if t.last == nil {
+		// the job is assumed to start with the first poll if its start time
+		// cannot be parsed
+		t.start = now
+		if start, err := time.Parse(time.UnixDate, status.StartTime); err == nil && start.Before(now) {
+			t.start = start
+		}
+		t.last, t.lastTime, t.lastProgress = status, now, now
+		progress.Elapsed = now.Sub(t.start)
+		return progress
+	}
+	progress.Elapsed = now.Sub(t.start)
+
+	progress.Interval = now.Sub(t.lastTime)
+	if status.OpName == t.last.OpName {
+		progress.IntervalBytes = max(status.SentBytes-t.last.SentBytes, 0)
+		progress.IntervalRows = max(status.SentRows-t.last.SentRows, 0)
+	}
+	if seconds := progress.Interval.Seconds(); seconds > 0 {
+		progress.BytesPerSecond = float64(progress.IntervalBytes) / seconds
+		progress.RowsPerSecond = float64(progress.IntervalRows) / seconds
+	}
+
+	if status.OpName != t.last.OpName || status.Status != t.last.Status || status.SentBytes != t.last.SentBytes ||
+		status.SentRows != t.last.SentRows || status.RebuiltProjections != t.last.RebuiltProjections {
+		t.lastProgress = now
+		t.stallReported = false
+	}
+	progress.StalledFor = now.Sub(t.lastProgress)
+	progress.Stalled = t.stallTimeout > 0 && progress.StalledFor >= t.stallTimeout
+	t.last, t.lastTime = status, now
+	return progress
+}
+
+// getReplicationPercent returns the percent of the current op of a job that
+// is done, or -1 if the op does not report its total
+func getReplicationPercent(status *ReplicationStatusResponse) float64 {
+	const percent = 100
+	switch {
+	case status.Status == replicationStatusCompleted:
+		return percent
+	case status.OpName == replicationRebuildOpName && status.TotalProjections > 0:
+		return percent * float64(status.RebuiltProjections) / float64(status.TotalProjections)
+	case status.TotalBytes > 0:
+		return percent * float64(status.SentBytes) / float64(status.TotalBytes)
+	}
+	return -1
+}
+
+// makeReplicationStatusRequest builds a request to the NMA replication status endpoint
+func makeReplicationStatusRequest(requestData *nmaReplicationStatusRequestData) (hostHTTPRequest, error) {
+	httpRequest := hostHTTPRequest{}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return httpRequest, fmt.Errorf("fail to marshal request data to JSON string, detail %w", err)
+	}
+
+	httpRequest.Method = PostMethod
+	httpRequest.buildNMAEndpoint("replicate/status")
+	httpRequest.RequestData = string(dataBytes)
+	return httpRequest, nil
+}
+
+// isReplicationFinished checks whether a replication job has either failed,
+// been canceled, or completed its last operation
+func isReplicationFinished(status *ReplicationStatusResponse) bool {
+	if status.Status == replicationStatusFailed || status.Status == replicationStatusCanceled {
+		return true
+	}
+	lastOpName := replicationLastOpName
+	if status.ProjectionMode == ReplicationProjectionsRebuild {
+		lastOpName = replicationRebuildOpName
+	}
+	return status.OpName == lastOpName && status.Status == replicationStatusCompleted
+}
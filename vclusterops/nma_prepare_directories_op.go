@@ -108,6 +108,11 @@ func (op *nmaPrepareDirectoriesOp) execute(execContext *opEngineExecContext) err
 	return op.processResult(execContext)
 }
 
+// existing directories are removed with force cleanup
+func (op *nmaPrepareDirectoriesOp) getClassification() OpClassification {
+	return OpClassification{Mutating: true, Destructive: op.forceCleanup}
+}
+
 func (op *nmaPrepareDirectoriesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -81,7 +81,11 @@ func (op *nmaPrepareScrutinizeDirectoriesOp) setupRequestBody(hostNodeNameMap ma
 }
 
 func (op *nmaPrepareScrutinizeDirectoriesOp) prepare(execContext *opEngineExecContext) error {
-	host := getInitiatorFromUpHosts(execContext.upHosts, op.hosts)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	host := getInitiatorFromUpHosts(execContext.upHosts, candidates)
 	if host == "" {
 		op.logger.PrintWarning("no up hosts among user specified hosts to collect system tables from, skipping the operation")
 		op.skipExecute = true
@@ -103,6 +107,10 @@ func (op *nmaPrepareScrutinizeDirectoriesOp) execute(execContext *opEngineExecCo
 	return op.processResult(execContext)
 }
 
+func (op *nmaPrepareScrutinizeDirectoriesOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaPrepareScrutinizeDirectoriesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
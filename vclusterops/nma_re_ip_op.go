@@ -51,6 +51,8 @@ type ReIPInfo struct {
 	TargetAddress          string `json:"address"`
 	TargetControlAddress   string `json:"control_address"`
 	TargetControlBroadcast string `json:"control_broadcast"`
+	// the export address is not changed if empty
+	TargetExportAddress string `json:"export_address,omitempty"`
 }
 
 type reIPParams struct {
@@ -104,6 +106,13 @@ func (op *nmaReIPOp) updateReIPList(execContext *opEngineExecContext) error {
 					info.NodeAddress)
 			}
 			info.NodeName = vnode.Name
+		} else if info.NodeAddress != "" {
+			// the name and the address must refer to the same node
+			vnode, ok := hostNodeMap[info.NodeAddress]
+			if !ok || vnode.Name != info.NodeName {
+				return fmt.Errorf("[%s] the provided IP %s is not the address of node %s in the database catalog",
+					op.name, info.NodeAddress, info.NodeName)
+			}
 		}
 		// update control address if not given
 		if info.TargetControlAddress == "" {
@@ -258,6 +267,10 @@ func (op *nmaReIPOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaReIPOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaReIPOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -38,6 +38,7 @@ type nmaReadCatalogEditorOp struct {
 	allErrs                error
 	hostsWithLatestCatalog []string
 	latestNmaVDB           nmaVDatabase
+	maxGlobalVersion       int64
 	bestHost               string
 	sandbox                string
 }
@@ -90,6 +91,9 @@ func (op *nmaReadCatalogEditorOp) setupClusterHTTPRequest(hosts []string) error
 
 		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
 	}
+	// every host returns the whole catalog, so we parse each response when it
+	// arrives and only keep the latest one
+	op.clusterHTTPRequest.resultHandler = op.aggregateHostResult
 
 	return nil
 }
@@ -123,7 +127,7 @@ func (op *nmaReadCatalogEditorOp) prepare(execContext *opEngineExecContext) erro
 		return nil
 	}
 
-	execContext.dispatcher.setup(op.hosts)
+	execContext.dispatcher.setupForSpilling(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
@@ -194,67 +198,82 @@ type nmaVDatabase struct {
 	PrimaryNodeCount uint `json:",omitempty"`
 }
 
-func (op *nmaReadCatalogEditorOp) processResult(_ *opEngineExecContext) error {
-	var maxGlobalVersion int64
-	for host, result := range op.clusterHTTPRequest.ResultCollection {
-		op.logResponse(host, result)
+// aggregateHostResult parses the catalog returned by one host and keeps it if
+// it is the latest one seen so far. The response body of a passing result is
+// dropped once parsed, as the catalog of a large cluster can be big and we
+// receive one copy from every host.
+func (op *nmaReadCatalogEditorOp) aggregateHostResult(result hostHTTPResult) hostHTTPResult {
+	host := result.host
+	op.logResponse(host, result)
 
-		if result.isPassing() {
-			nmaVDB := nmaVDatabase{}
-			err := op.parseAndCheckResponse(host, result.content, &nmaVDB)
-			if err != nil {
-				err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
-					op.name, host, err)
-				op.allErrs = errors.Join(op.allErrs, err)
-				continue
-			}
+	if !result.isPassing() {
+		return result
+	}
 
-			var primaryNodeCount uint
-			// build host to node map for NMAStartNodeOp
-			hostNodeMap := make(map[string]*nmaVNode)
-			for i := 0; i < len(nmaVDB.Nodes); i++ {
-				n := nmaVDB.Nodes[i]
-				hostNodeMap[n.Address] = &n
-				if n.IsPrimary {
-					primaryNodeCount++
-				}
-			}
-			nmaVDB.HostNodeMap = hostNodeMap
-			nmaVDB.PrimaryNodeCount = primaryNodeCount
-
-			// find hosts with latest catalog version
-			globalVersion, err := nmaVDB.Versions.Global.Int64()
-			if err != nil {
-				err = fmt.Errorf("[%s] fail to convert spread Version to integer %s, details: %w",
-					op.name, host, err)
-				op.allErrs = errors.Join(op.allErrs, err)
+	nmaVDB := nmaVDatabase{}
+	err := op.parseAndCheckResultContent(host, &result, &nmaVDB)
+	result.content = ""
+	result.removeSpillFile()
+	if err != nil {
+		err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+			op.name, host, err)
+		op.allErrs = errors.Join(op.allErrs, err)
+		return result
+	}
+
+	var primaryNodeCount uint
+	// build host to node map for NMAStartNodeOp
+	hostNodeMap := make(map[string]*nmaVNode)
+	for i := 0; i < len(nmaVDB.Nodes); i++ {
+		n := nmaVDB.Nodes[i]
+		hostNodeMap[n.Address] = &n
+		if n.IsPrimary {
+			primaryNodeCount++
+		}
+	}
+	nmaVDB.HostNodeMap = hostNodeMap
+	nmaVDB.PrimaryNodeCount = primaryNodeCount
+
+	// find hosts with latest catalog version
+	globalVersion, err := nmaVDB.Versions.Global.Int64()
+	if err != nil {
+		err = fmt.Errorf("[%s] fail to convert spread Version to integer %s, details: %w",
+			op.name, host, err)
+		op.allErrs = errors.Join(op.allErrs, err)
+		return result
+	}
+	if globalVersion > op.maxGlobalVersion {
+		op.hostsWithLatestCatalog = []string{host}
+		op.maxGlobalVersion = globalVersion
+		// save the latest NMAVDatabase to execContext
+		op.latestNmaVDB = nmaVDB
+		op.bestHost = host
+	} else if globalVersion == op.maxGlobalVersion {
+		op.hostsWithLatestCatalog = append(op.hostsWithLatestCatalog, host)
+	}
+	return result
+}
+
+func (op *nmaReadCatalogEditorOp) processResult(_ *opEngineExecContext) error {
+	// passing results have already been aggregated by aggregateHostResult
+	for _, result := range op.clusterHTTPRequest.ResultCollection {
+		if result.isPassing() {
+			continue
+		}
+		// if this is not the first time of start_db after revive_db,
+		// we ignore the error if the catalog directory is empty, because
+		// - we may send request to a secondary node right after revive
+		// - users may delete the catalog files
+		if !op.firstStartAfterRevive {
+			rfcError := &rfc7807.VProblem{}
+			if ok := errors.As(result.err, &rfcError); ok &&
+				(rfcError.ProblemID == rfc7807.CECatalogContentDirEmptyError ||
+					rfcError.ProblemID == rfc7807.CECatalogContentDirNotExistError) {
 				continue
 			}
-			if globalVersion > maxGlobalVersion {
-				op.hostsWithLatestCatalog = []string{host}
-				maxGlobalVersion = globalVersion
-				// save the latest NMAVDatabase to execContext
-				op.latestNmaVDB = nmaVDB
-				op.bestHost = host
-			} else if globalVersion == maxGlobalVersion {
-				op.hostsWithLatestCatalog = append(op.hostsWithLatestCatalog, host)
-			}
-		} else {
-			// if this is not the first time of start_db after revive_db,
-			// we ignore the error if the catalog directory is empty, because
-			// - we may send request to a secondary node right after revive
-			// - users may delete the catalog files
-			if !op.firstStartAfterRevive {
-				rfcError := &rfc7807.VProblem{}
-				if ok := errors.As(result.err, &rfcError); ok &&
-					(rfcError.ProblemID == rfc7807.CECatalogContentDirEmptyError ||
-						rfcError.ProblemID == rfc7807.CECatalogContentDirNotExistError) {
-					continue
-				}
-			}
-
-			op.allErrs = errors.Join(op.allErrs, result.err)
 		}
+
+		op.allErrs = errors.Join(op.allErrs, result.err)
 	}
 
 	// let finalize() handle error conditions, in case this function is skipped
@@ -0,0 +1,66 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"path"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+const (
+	// the systemd unit of the NMA service
+	DefaultNMAServiceUnit = "node_management_agent.service"
+	// the time in seconds to wait for a restarted NMA service to respond
+	DefaultNMARecoveryTimeout = 60
+
+	nmaRecoveryEndpointPrefix = "v1/systemd/units"
+)
+
+// NMARecovery is how a command restarts the NMA service on the hosts where
+// it does not respond to the health check, the most common transient failure,
+// instead of failing right away. The NMA service cannot restart itself, so a
+// companion agent on each host restarts its systemd unit.
+type NMARecovery struct {
+	// the port of the companion agent, which uses the TLS options of the NMA
+	AgentPort int
+	// the systemd unit of the NMA service, DefaultNMAServiceUnit if empty
+	ServiceUnit string
+	// the time in seconds to wait for the restarted service to respond,
+	// DefaultNMARecoveryTimeout if not positive
+	Timeout int
+}
+
+func (r *NMARecovery) validate() error {
+	return util.ValidatePort(r.AgentPort, "NMA recovery agent")
+}
+
+func (r *NMARecovery) getTimeout() int {
+	if r.Timeout <= 0 {
+		return DefaultNMARecoveryTimeout
+	}
+	return r.Timeout
+}
+
+// getRestartEndpoint returns the endpoint of the agent that restarts the NMA
+// service, e.g., v1/systemd/units/node_management_agent.service/restart
+func (r *NMARecovery) getRestartEndpoint() string {
+	unit := r.ServiceUnit
+	if unit == "" {
+		unit = DefaultNMAServiceUnit
+	}
+	return path.Join(nmaRecoveryEndpointPrefix, unit, "restart")
+}
@@ -0,0 +1,65 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNMARecovery(t *testing.T) {
+	recovery := &NMARecovery{}
+	assert.ErrorContains(t, recovery.validate(), "NMA recovery agent")
+	recovery.AgentPort = 5556
+	assert.NoError(t, recovery.validate())
+	assert.Equal(t, DefaultNMARecoveryTimeout, recovery.getTimeout())
+	assert.Equal(t, "v1/systemd/units/node_management_agent.service/restart", recovery.getRestartEndpoint())
+	recovery.ServiceUnit = "nma.service"
+	assert.Equal(t, "v1/systemd/units/nma.service/restart", recovery.getRestartEndpoint())
+
+	// the restart requests keep the TLS options of the health check requests
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	op := makeNMAHealthOp(hosts)
+	op.clusterHTTPRequest.RequestCollection = make(map[string]hostHTTPRequest)
+	assert.NoError(t, op.setupClusterHTTPRequest(hosts))
+	for host, request := range op.clusterHTTPRequest.RequestCollection {
+		request.UseCertsInOptions = true
+		op.clusterHTTPRequest.RequestCollection[host] = request
+	}
+	op.setupRestartRequests(hosts[1:], recovery)
+	assert.Len(t, op.clusterHTTPRequest.RequestCollection, 1)
+	request := op.clusterHTTPRequest.RequestCollection[hosts[1]]
+	assert.Equal(t, PostMethod, request.Method)
+	assert.Equal(t, 5556, request.Port)
+	assert.Equal(t, "v1/systemd/units/nma.service/restart", request.Endpoint)
+	assert.True(t, request.UseCertsInOptions)
+
+	// polling stops once the restarted service responds
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[1]: {host: hosts[1], status: EXCEPTION, err: errors.New("connection refused")},
+	}
+	stop, err := op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[1]: {host: hosts[1], status: SUCCESS, content: `{"healthy": "true"}`},
+	}
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+}
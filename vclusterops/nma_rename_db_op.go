@@ -0,0 +1,161 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/maps"
+)
+
+// nmaRenameDatabaseOp renames a stopped database in the catalog and the
+// configuration files of every node. The NMA also moves the database
+// directory under the catalog prefix to the new name.
+type nmaRenameDatabaseOp struct {
+	opBase
+	dbName             string
+	newDBName          string
+	vdb                *VCoordinationDatabase
+	hostRequestBodyMap map[string]string
+	// the catalog path of each node that is renamed, after the rename, so that
+	// the renamed nodes can be reverted if the others fail
+	renamedCatalogPaths map[string]string
+}
+
+type renameDatabaseRequestData struct {
+	CatalogPath string `json:"catalog_path"`
+	DBName      string `json:"db_name"`
+	NewDBName   string `json:"new_db_name"`
+}
+
+func makeNMARenameDatabaseOp(dbName, newDBName string, vdb *VCoordinationDatabase) nmaRenameDatabaseOp {
+	op := nmaRenameDatabaseOp{}
+	op.name = "NMARenameDatabaseOp"
+	op.description = fmt.Sprintf("Rename database to %s", newDBName)
+	op.dbName = dbName
+	op.newDBName = newDBName
+	op.vdb = vdb
+	return op
+}
+
+func (op *nmaRenameDatabaseOp) updateRequestBody() error {
+	op.hostRequestBodyMap = make(map[string]string)
+
+	for _, host := range op.hosts {
+		requestData := renameDatabaseRequestData{
+			CatalogPath: op.vdb.HostNodeMap[host].CatalogPath,
+			DBName:      op.dbName,
+			NewDBName:   op.newDBName,
+		}
+		dataBytes, err := json.Marshal(requestData)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+
+	op.logger.Info("request data", "op name", op.name, "hostRequestBodyMap", op.hostRequestBodyMap)
+	return nil
+}
+
+func (op *nmaRenameDatabaseOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PutMethod
+		httpRequest.buildNMAEndpoint("catalog/rename-database")
+		httpRequest.RequestData = op.hostRequestBodyMap[host]
+
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaRenameDatabaseOp) prepare(execContext *opEngineExecContext) error {
+	// every node has its own copy of the database name, so all of them are renamed
+	op.hosts = maps.Keys(op.vdb.HostNodeMap)
+	if len(op.hosts) == 0 {
+		return fmt.Errorf("[%s] no node information found for database %s", op.name, op.dbName)
+	}
+
+	err := op.updateRequestBody()
+	if err != nil {
+		return err
+	}
+
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaRenameDatabaseOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaRenameDatabaseOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
+// getRevertVDB returns the nodes that are renamed, at their catalog path
+// after the rename, to rename them back to the original name
+func (op *nmaRenameDatabaseOp) getRevertVDB() *VCoordinationDatabase {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	for host, catalogPath := range op.renamedCatalogPaths {
+		vnode := *op.vdb.HostNodeMap[host]
+		vnode.CatalogPath = catalogPath
+		vdb.HostNodeMap[host] = &vnode
+	}
+	return &vdb
+}
+
+func (op *nmaRenameDatabaseOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaRenameDatabaseOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+	op.renamedCatalogPaths = make(map[string]string)
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isPassing() {
+			// the node is renamed even if the response cannot be parsed
+			op.renamedCatalogPaths[host] = op.vdb.HostNodeMap[host].CatalogPath
+			// the response has the catalog path after the rename, e.g.,
+			// {"catalog_path": "/data/new_db/v_test_db_node0001_catalog"}
+			response, err := op.parseAndCheckMapResponse(host, result.content)
+			if err != nil {
+				allErrs = errors.Join(allErrs, fmt.Errorf("[%s] fail to parse result on host %s, details: %w",
+					op.name, host, err))
+				continue
+			}
+			if catalogPath, ok := response["catalog_path"]; ok && catalogPath != "" {
+				op.renamedCatalogPaths[host] = catalogPath
+			}
+			continue
+		}
+		allErrs = errors.Join(allErrs, result.err)
+	}
+
+	return allErrs
+}
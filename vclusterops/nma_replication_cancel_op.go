@@ -0,0 +1,84 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// replicationCancelJob cancels an asynchronous replication job through the
+// NMA of the target database, and polls the status of the job until it
+// stops. The cancel is sent to one target host, as any of them can cancel
+// the job.
+type replicationCancelJob struct {
+	replicationProgressJob
+	cancelRequestBody string
+}
+
+type nmaReplicationCancelRequestData struct {
+	DBName        string  `json:"dbname"`
+	TransactionID int64   `json:"txn_id"`
+	UserName      string  `json:"username"`
+	Password      *string `json:"password"`
+}
+
+// makeNMAReplicationCancelOp makes an op that cancels the replication job of
+// cancelData, and waits up to timeout seconds for the job to stop, keeping
+// its final status in replicationStatus
+func makeNMAReplicationCancelOp(targetHosts []string, targetUsePassword bool,
+	cancelData *nmaReplicationCancelRequestData, timeout int,
+	replicationStatus *ReplicationStatusResponse) (nmaAsyncJobOp, error) {
+	job := &replicationCancelJob{}
+	job.DBName = cancelData.DBName
+	job.ExcludedTransactionIDs = []int64{} // Doesn't matter since we specify a transaction ID
+	job.GetTransactionIDsOnly = false      // Get all replication status info
+	job.TransactionID = cancelData.TransactionID
+	job.UserName = cancelData.UserName
+	job.Password = cancelData.Password
+	job.replicationStatus = replicationStatus
+	op := makeNMAAsyncJobOp("NMAReplicationCancelOp", "Cancel asynchronous replication", targetHosts, job, timeout)
+
+	if targetUsePassword {
+		err := util.ValidateUsernameAndPassword(op.name, targetUsePassword, cancelData.UserName)
+		if err != nil {
+			return op, err
+		}
+	}
+	dataBytes, err := json.Marshal(cancelData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	job.cancelRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (job *replicationCancelJob) getSubmitRequest() (hostHTTPRequest, error) {
+	httpRequest := hostHTTPRequest{}
+	httpRequest.Method = PostMethod
+	httpRequest.buildNMAEndpoint("replicate/cancel")
+	httpRequest.RequestData = job.cancelRequestBody
+	return httpRequest, nil
+}
+
+// the job is identified by its transaction ID, so the response of the cancel
+// request has nothing for the status requests
+func (job *replicationCancelJob) processSubmitResponse(_ *nmaAsyncJobOp, _, _ string) error {
+	return nil
+}
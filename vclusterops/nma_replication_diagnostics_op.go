@@ -0,0 +1,151 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const (
+	ReplicationRoleSource = "source"
+	ReplicationRoleTarget = "target"
+
+	// the number of lines of vertica.log in the diagnostics of a host
+	replicationDiagnosticsLogTailLines = 200
+)
+
+type ReplicationDiagnostics = vtypes.ReplicationDiagnostics
+type ReplicationHostDiagnostics = vtypes.ReplicationHostDiagnostics
+type ReplicationSession = vtypes.ReplicationSession
+type NetworkProbe = vtypes.NetworkProbe
+type ReplicationStalledError = vtypes.ReplicationStalledError
+
+// nmaReplicationDiagnosticsOp collects the diagnostics of a replication job on
+// the initiator host of the source or the target database: the sessions of the
+// job, a probe of the network to the other initiator, and the tail of the log.
+// The diagnostics are best effort, a host that cannot be reached only records
+// the error.
+type nmaReplicationDiagnosticsOp struct {
+	opBase
+	hostRequestBody string
+	diagnostics     *ReplicationHostDiagnostics
+}
+
+type replicationDiagnosticsRequestData struct {
+	sqlEndpointData
+	TransactionID int64 `json:"txn_id"`
+	// the initiator to probe, empty to skip the probe
+	PeerHost     string `json:"peer_host"`
+	LogTailLines int    `json:"log_tail_lines"`
+}
+
+type replicationDiagnosticsResponse struct {
+	Sessions []ReplicationSession `json:"sessions"`
+	Probe    *NetworkProbe        `json:"probe"`
+	LogTail  []string             `json:"log_tail"`
+}
+
+func makeNMAReplicationDiagnosticsOp(host, peerHost, username, dbName string, password *string,
+	useDBPassword bool, transactionID int64, diagnostics *ReplicationHostDiagnostics) (nmaReplicationDiagnosticsOp, error) {
+	op := nmaReplicationDiagnosticsOp{}
+	op.name = "NMAReplicationDiagnosticsOp"
+	op.description = "Collect replication diagnostics"
+	op.hosts = []string{host}
+	op.diagnostics = diagnostics
+
+	err := ValidateSQLEndpointData(op.name, useDBPassword, username, password, dbName)
+	if err != nil {
+		return op, err
+	}
+	requestData := replicationDiagnosticsRequestData{
+		TransactionID: transactionID,
+		PeerHost:      peerHost,
+		LogTailLines:  replicationDiagnosticsLogTailLines,
+	}
+	requestData.sqlEndpointData = createSQLEndpointData(username, dbName, useDBPassword, password)
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaReplicationDiagnosticsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("replicate/diagnostics")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaReplicationDiagnosticsOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaReplicationDiagnosticsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaReplicationDiagnosticsOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
+func (op *nmaReplicationDiagnosticsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaReplicationDiagnosticsOp) processResult(_ *opEngineExecContext) error {
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+		if !result.isPassing() {
+			op.diagnostics.Error = result.err.Error()
+			continue
+		}
+
+		// the response object will be a dictionary like the following:
+		// {
+		//   "sessions": [{"session_id": "v_db_node0001-123:0x45", "user_name": "dbadmin",
+		//                 "client_hostname": "10.0.0.1:5433", "current_statement": "REPLICATE ...",
+		//                 "wait_reason": "network", "running_seconds": 600}],
+		//   "probe": {"peer": "10.0.1.1", "reachable": true, "latency_ms": 0.4},
+		//   "log_tail": ["2024-01-01 00:00:00.000 ..."]
+		// }
+		var response replicationDiagnosticsResponse
+		err := op.parseAndCheckResponse(host, result.content, &response)
+		if err != nil {
+			op.diagnostics.Error = err.Error()
+			continue
+		}
+		op.diagnostics.Sessions = response.Sessions
+		op.diagnostics.Probe = response.Probe
+		op.diagnostics.LogTail = response.LogTail
+	}
+	return nil
+}
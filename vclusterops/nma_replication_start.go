@@ -29,11 +29,13 @@ type nmaReplicationStartOp struct {
 	hostRequestBodyMap map[string]string
 	sandbox            string
 	vdb                *VCoordinationDatabase
+	// set to the source epoch that the replication copies data as of, if reported
+	replicatedEpoch *int64
 }
 
 func makeNMAReplicationStartOp(sourceHosts []string,
 	sourceUsePassword bool, targetUsePassword bool,
-	replicationRequestData *nmaStartReplicationRequestData,
+	replicationRequestData *nmaStartReplicationRequestData, replicatedEpoch *int64,
 	vdb *VCoordinationDatabase) (nmaReplicationStartOp, error) {
 	op := nmaReplicationStartOp{}
 	op.name = "NMAReplicationStartOp"
@@ -41,6 +43,7 @@ func makeNMAReplicationStartOp(sourceHosts []string,
 	op.hosts = sourceHosts
 	op.nmaStartReplicationRequestData = *replicationRequestData
 	op.vdb = vdb
+	op.replicatedEpoch = replicatedEpoch
 
 	if sourceUsePassword {
 		err := util.ValidateUsernameAndPassword(op.name, sourceUsePassword, replicationRequestData.Username)
@@ -74,7 +77,16 @@ type nmaStartReplicationRequestData struct {
 	TargetNamespace   string  `json:"target_namespace,omitempty"`
 	TargetUserName    string  `json:"target_username,omitempty"`
 	TargetPassword    *string `json:"target_password,omitempty"`
+	TargetRole        string  `json:"target_role,omitempty"`
+	TargetPool        string  `json:"target_resource_pool,omitempty"`
+	SourceSnapshot    string  `json:"source_snapshot,omitempty"`
+	ProjectionMode    string  `json:"projection_mode,omitempty"`
 	TLSConfig         string  `json:"tls_config,omitempty"`
+	TargetTLSConfig   string  `json:"target_tls_config,omitempty"`
+	Epoch             *int64  `json:"epoch,omitempty"`
+	AtTime            string  `json:"at_time,omitempty"`
+	// the source objects to replicate under another name in the target database
+	ObjectRenames map[string]string `json:"object_renames,omitempty"`
 }
 
 func (op *nmaReplicationStartOp) updateRequestBody(hosts []string) error {
@@ -105,7 +117,7 @@ func (op *nmaReplicationStartOp) setupClusterHTTPRequest(hosts []string) error {
 }
 
 func (op *nmaReplicationStartOp) prepare(execContext *opEngineExecContext) error {
-	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb)
+	sourceHost, err := getInitiatorHostForReplication(op.name, op.sandbox, op.hosts, op.vdb, execContext.initiators)
 	if err != nil {
 		return err
 	}
@@ -130,11 +142,15 @@ func (op *nmaReplicationStartOp) execute(execContext *opEngineExecContext) error
 	return op.processResult(execContext)
 }
 
+func (op *nmaReplicationStartOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *nmaReplicationStartOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
 
-func (op *nmaReplicationStartOp) processResult(_ *opEngineExecContext) error {
+func (op *nmaReplicationStartOp) processResult(execContext *opEngineExecContext) error {
 	var allErrs error
 
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
@@ -147,8 +163,34 @@ func (op *nmaReplicationStartOp) processResult(_ *opEngineExecContext) error {
 
 		if !result.isPassing() {
 			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+
+		err := op.processStartResponse(host, result.content, execContext)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
 		}
 	}
 
 	return allErrs
 }
+
+// processStartResponse reads the source epoch that the replication copies data
+// as of, and the objects that it skips, from the response, e.g.,
+// {"epoch": 12345, "skipped_objects": [{"type": "table", "name": "public.t1", "reason": "unsupported_type"}]}.
+// Both are optional, older NMA versions do not report them.
+func (op *nmaReplicationStartOp) processStartResponse(host, content string, execContext *opEngineExecContext) error {
+	if content == "" {
+		return nil
+	}
+	var response startReplicationResponse
+	err := op.parseAndCheckResponse(host, content, &response)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+	}
+	if response.Epoch != nil && op.replicatedEpoch != nil {
+		*op.replicatedEpoch = *response.Epoch
+	}
+	execContext.skippedObjects = append(execContext.skippedObjects, response.SkippedObjects...)
+	return nil
+}
@@ -111,6 +111,11 @@ func (op *nmaReplicationStatusOp) execute(execContext *opEngineExecContext) erro
 	return op.processResult(execContext)
 }
 
+// the status is read through a POST request
+func (op *nmaReplicationStatusOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
 func (op *nmaReplicationStatusOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
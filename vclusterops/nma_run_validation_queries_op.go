@@ -0,0 +1,142 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// nmaRunValidationQueriesOp runs validation queries on each host, e.g., on
+// the canary hosts of a configuration change. A failed query does not fail the
+// op, it is recorded in the results.
+type nmaRunValidationQueriesOp struct {
+	opBase
+	queries         []string
+	hostRequestBody string
+	results         *[]ConfigValidationResult
+}
+
+type validationQueriesRequestData struct {
+	sqlEndpointData
+	Queries []string `json:"queries"`
+}
+
+type validationQueryResult struct {
+	Query  string `json:"query"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error"`
+}
+
+func makeNMARunValidationQueriesOp(hosts, queries []string, username, dbName string,
+	password *string, useDBPassword bool, results *[]ConfigValidationResult) (nmaRunValidationQueriesOp, error) {
+	op := nmaRunValidationQueriesOp{}
+	op.name = "NMARunValidationQueriesOp"
+	op.description = "Run validation queries"
+	op.hosts = hosts
+	op.queries = queries
+	op.results = results
+
+	err := ValidateSQLEndpointData(op.name, useDBPassword, username, password, dbName)
+	if err != nil {
+		return op, err
+	}
+	requestData := validationQueriesRequestData{Queries: queries}
+	requestData.sqlEndpointData = createSQLEndpointData(username, dbName, useDBPassword, password)
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaRunValidationQueriesOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("queries/validate")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaRunValidationQueriesOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaRunValidationQueriesOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaRunValidationQueriesOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
+func (op *nmaRunValidationQueriesOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaRunValidationQueriesOp) processResult(_ *opEngineExecContext) error {
+	for _, host := range op.hosts {
+		result, ok := op.clusterHTTPRequest.ResultCollection[host]
+		if !ok {
+			continue
+		}
+		op.logResponse(host, result)
+		*op.results = append(*op.results, op.checkQueryResults(host, &result)...)
+	}
+	return nil
+}
+
+// checkQueryResults returns the result of every query on a host. All of the
+// queries failed if the host could not run them.
+func (op *nmaRunValidationQueriesOp) checkQueryResults(host string, result *hostHTTPResult) []ConfigValidationResult {
+	// the response object will be a list of the query results, e.g.,
+	// [{"query": "SELECT 1", "passed": true, "error": ""}]
+	var responses []validationQueryResult
+	var hostErr string
+	if !result.isPassing() {
+		hostErr = fmt.Sprintf("request failed with status code %d", result.statusCode)
+		if result.err != nil {
+			hostErr = result.err.Error()
+		}
+	} else if err := op.parseAndCheckResponse(host, result.content, &responses); err != nil {
+		hostErr = err.Error()
+	}
+
+	if hostErr != "" {
+		results := make([]ConfigValidationResult, 0, len(op.queries))
+		for _, query := range op.queries {
+			results = append(results, ConfigValidationResult{Host: host, Query: query, Error: hostErr})
+		}
+		return results
+	}
+	results := make([]ConfigValidationResult, 0, len(responses))
+	for _, response := range responses {
+		results = append(results, ConfigValidationResult{Host: host, Query: response.Query,
+			Passed: response.Passed, Error: response.Error})
+	}
+	return results
+}
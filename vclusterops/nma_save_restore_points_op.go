@@ -101,6 +101,10 @@ func (op *nmaSaveRestorePointsOp) execute(execContext *opEngineExecContext) erro
 	return op.processResult(execContext)
 }
 
+func (op *nmaSaveRestorePointsOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *nmaSaveRestorePointsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
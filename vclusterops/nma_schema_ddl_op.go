@@ -0,0 +1,144 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// nmaSchemaDDLOp exports the DDL of the objects of a schema from a database,
+// or applies exported DDL to a database, e.g., the views and sequences of a
+// schema that replication does not copy
+type nmaSchemaDDLOp struct {
+	opBase
+	apply       bool
+	requestData schemaDDLRequestData
+	// the exported DDL, which is applied by the apply op
+	ddl *string
+}
+
+type schemaDDLRequestData struct {
+	sqlEndpointData
+	Schema string `json:"schema"`
+	DDL    string `json:"ddl,omitempty"`
+}
+
+type schemaDDLResponse struct {
+	DDL string `json:"ddl"`
+}
+
+func makeNMASchemaDDLOp(hosts []string, apply bool, schema, username, dbName string,
+	password *string, useDBPassword bool, ddl *string) (nmaSchemaDDLOp, error) {
+	op := nmaSchemaDDLOp{}
+	op.name = "NMAExportSchemaDDLOp"
+	op.description = fmt.Sprintf("Export DDL of schema %s", schema)
+	if apply {
+		op.name = "NMAApplySchemaDDLOp"
+		op.description = fmt.Sprintf("Apply DDL of schema %s", schema)
+	}
+	op.hosts = hosts
+	op.apply = apply
+	op.ddl = ddl
+
+	err := ValidateSQLEndpointData(op.name, useDBPassword, username, password, dbName)
+	if err != nil {
+		return op, err
+	}
+	op.requestData.Schema = schema
+	op.requestData.sqlEndpointData = createSQLEndpointData(username, dbName, useDBPassword, password)
+	return op, nil
+}
+
+func (op *nmaSchemaDDLOp) setupClusterHTTPRequest(hosts []string) error {
+	// the DDL to apply is only known once it is exported
+	if op.apply {
+		op.requestData.DDL = *op.ddl
+	}
+	dataBytes, err := json.Marshal(op.requestData)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		if op.apply {
+			httpRequest.buildNMAEndpoint("schemas/ddl/apply")
+		} else {
+			httpRequest.buildNMAEndpoint("schemas/ddl/export")
+		}
+		httpRequest.RequestData = string(dataBytes)
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaSchemaDDLOp) prepare(execContext *opEngineExecContext) error {
+	if op.apply && *op.ddl == "" {
+		op.logger.PrintInfo("[%s] schema %s has no DDL to apply", op.name, op.requestData.Schema)
+		op.skipExecute = true
+		return nil
+	}
+
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaSchemaDDLOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// exporting the DDL only reads the catalog
+func (op *nmaSchemaDDLOp) getClassification() OpClassification {
+	if op.apply {
+		return mutatingClassification
+	}
+	return readOnlyClassification
+}
+
+func (op *nmaSchemaDDLOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaSchemaDDLOp) processResult(_ *opEngineExecContext) error {
+	// the request is sent to one host only
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			return result.err
+		}
+		if op.apply {
+			return nil
+		}
+
+		// the response object will be a dictionary, e.g.,:
+		// {"ddl": "CREATE VIEW sales.v1 AS SELECT ..."}
+		response := schemaDDLResponse{}
+		err := op.parseAndCheckResponse(host, result.content, &response)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+		}
+		*op.ddl = response.DDL
+	}
+	return nil
+}
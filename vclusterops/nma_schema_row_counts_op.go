@@ -0,0 +1,119 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// nmaSchemaRowCountsOp counts the rows of each table of a schema
+type nmaSchemaRowCountsOp struct {
+	opBase
+	hostRequestBody string
+	schema          string
+	rowCounts       map[string]int64
+}
+
+type schemaRowCountsRequestData struct {
+	sqlEndpointData
+	Schema string `json:"schema"`
+}
+
+type schemaRowCountsResponse struct {
+	RowCounts map[string]int64 `json:"row_counts"`
+}
+
+func makeNMASchemaRowCountsOp(hosts []string, schema, username, dbName string,
+	password *string, useDBPassword bool, rowCounts map[string]int64) (nmaSchemaRowCountsOp, error) {
+	op := nmaSchemaRowCountsOp{}
+	op.name = "NMASchemaRowCountsOp"
+	op.description = fmt.Sprintf("Count rows of the tables of schema %s", schema)
+	op.hosts = hosts
+	op.schema = schema
+	op.rowCounts = rowCounts
+
+	err := ValidateSQLEndpointData(op.name, useDBPassword, username, password, dbName)
+	if err != nil {
+		return op, err
+	}
+	requestData := schemaRowCountsRequestData{Schema: schema}
+	requestData.sqlEndpointData = createSQLEndpointData(username, dbName, useDBPassword, password)
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaSchemaRowCountsOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("schemas/row-counts")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaSchemaRowCountsOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaSchemaRowCountsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaSchemaRowCountsOp) getClassification() OpClassification {
+	return readOnlyClassification
+}
+
+func (op *nmaSchemaRowCountsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaSchemaRowCountsOp) processResult(_ *opEngineExecContext) error {
+	// the request is sent to one host only
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			return result.err
+		}
+
+		// the response object will be a dictionary, e.g.,:
+		// {"row_counts": {"orders": 1200, "customers": 45}}
+		response := schemaRowCountsResponse{}
+		err := op.parseAndCheckResponse(host, result.content, &response)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
+		}
+		for table, rows := range response.RowCounts {
+			op.rowCounts[table] = rows
+		}
+	}
+	return nil
+}
@@ -33,6 +33,8 @@ type setConfigurationParameterData struct {
 	ConfigParameter string `json:"config_parameter"`
 	Value           string `json:"value"`
 	Level           string `json:"level"`
+	// the nodes to set the parameter on, for the node level
+	NodeNames []string `json:"node_names,omitempty"`
 }
 
 func makeNMASetConfigurationParameterOp(hosts []string,
@@ -44,7 +46,27 @@ func makeNMASetConfigurationParameterOp(hosts []string,
 	op.hosts = hosts
 	op.sandbox = sandbox
 
-	err := op.setupRequestBody(username, dbName, configParameter, value, level, password, useHTTPPassword)
+	err := op.setupRequestBody(username, dbName, configParameter, value, level, nil, password, useHTTPPassword)
+	if err != nil {
+		return op, err
+	}
+
+	return op, nil
+}
+
+// makeNMASetNodesConfigurationParameterOp sets a configuration parameter on
+// the given nodes only, e.g., the canary nodes of a configuration change
+func makeNMASetNodesConfigurationParameterOp(hosts, nodeNames []string,
+	username, dbName, sandbox, configParameter, value string,
+	password *string, useHTTPPassword bool) (nmaSetConfigurationParameterOp, error) {
+	op := nmaSetConfigurationParameterOp{}
+	op.name = "NMASetNodesConfigurationParameterOp"
+	op.description = "Set configuration parameter value on nodes"
+	op.hosts = hosts
+	op.sandbox = sandbox
+
+	err := op.setupRequestBody(username, dbName, configParameter, value, ConfigParameterNodeLevel, nodeNames,
+		password, useHTTPPassword)
 	if err != nil {
 		return op, err
 	}
@@ -53,7 +75,7 @@ func makeNMASetConfigurationParameterOp(hosts []string,
 }
 
 func (op *nmaSetConfigurationParameterOp) setupRequestBody(
-	username, dbName, configParameter, value, level string, password *string,
+	username, dbName, configParameter, value, level string, nodeNames []string, password *string,
 	useDBPassword bool) error {
 	err := ValidateSQLEndpointData(op.name,
 		useDBPassword, username, password, dbName)
@@ -65,6 +87,7 @@ func (op *nmaSetConfigurationParameterOp) setupRequestBody(
 	setConfigData.ConfigParameter = configParameter
 	setConfigData.Value = value
 	setConfigData.Level = level
+	setConfigData.NodeNames = nodeNames
 
 	dataBytes, err := json.Marshal(setConfigData)
 	if err != nil {
@@ -90,7 +113,11 @@ func (op *nmaSetConfigurationParameterOp) setupClusterHTTPRequest(initiator stri
 
 func (op *nmaSetConfigurationParameterOp) prepare(execContext *opEngineExecContext) error {
 	// select an up host in the sandbox or main cluster as the initiator
-	initiator, err := getInitiatorInCluster(op.sandbox, op.hosts, execContext.upHostsToSandboxes)
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	initiator, err := getInitiatorInCluster(op.sandbox, candidates, execContext.upHostsToSandboxes)
 	if err != nil {
 		return err
 	}
@@ -107,6 +134,10 @@ func (op *nmaSetConfigurationParameterOp) execute(execContext *opEngineExecConte
 	return op.processResult(execContext)
 }
 
+func (op *nmaSetConfigurationParameterOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaSetConfigurationParameterOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
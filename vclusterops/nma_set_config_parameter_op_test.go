@@ -33,7 +33,7 @@ func TestNmaSetConfigurationParameterOp_SetupRequestBody(t *testing.T) {
 	password := "set-config-password-op" //nolint:gosec
 	useDBPassword := true
 
-	err := op.setupRequestBody(username, dbName, configParameter, value, level, &password, useDBPassword)
+	err := op.setupRequestBody(username, dbName, configParameter, value, level, nil, &password, useDBPassword)
 	assert.NoError(t, err)
 
 	expectedData := setConfigurationParameterData{
@@ -48,12 +48,12 @@ func TestNmaSetConfigurationParameterOp_SetupRequestBody(t *testing.T) {
 
 	assert.Equal(t, expectedRequestBody, op.hostRequestBody)
 
-	err = op.setupRequestBody("", dbName, configParameter, value, level, &password, useDBPassword)
+	err = op.setupRequestBody("", dbName, configParameter, value, level, nil, &password, useDBPassword)
 	assert.Error(t, err)
 
-	err = op.setupRequestBody(username, "", configParameter, value, level, &password, useDBPassword)
+	err = op.setupRequestBody(username, "", configParameter, value, level, nil, &password, useDBPassword)
 	assert.Error(t, err)
 
-	err = op.setupRequestBody(username, dbName, configParameter, value, level, nil, useDBPassword)
+	err = op.setupRequestBody(username, dbName, configParameter, value, level, nil, nil, useDBPassword)
 	assert.Error(t, err)
 }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
 type nmaShowRestorePointsOp struct {
@@ -29,6 +30,9 @@ type nmaShowRestorePointsOp struct {
 	communalLocation        string
 	configurationParameters map[string]string
 	filterOptions           ShowRestorePointFilterOptions
+	// optional, if set, the restore points are streamed to it instead of
+	// being stored in the execContext
+	restorePointHandler func(RestorePoint) error
 }
 
 // Optional arguments to list only restore points that
@@ -82,6 +86,12 @@ func makeNMAShowRestorePointsOpWithFilterOptions(logger vlog.Printer,
 	return op
 }
 
+// streamRestorePoints makes the op pass each restore point to handler as it is
+// received, rather than reading the whole list into memory
+func (op *nmaShowRestorePointsOp) streamRestorePoints(handler func(RestorePoint) error) {
+	op.restorePointHandler = handler
+}
+
 // make https json data
 func (op *nmaShowRestorePointsOp) setupRequestBody() (map[string]string, error) {
 	hostRequestBodyMap := make(map[string]string, len(op.hosts))
@@ -123,10 +133,23 @@ func (op *nmaShowRestorePointsOp) prepare(execContext *opEngineExecContext) erro
 		return err
 	}
 
-	execContext.dispatcher.setup(op.hosts)
+	if op.restorePointHandler != nil {
+		execContext.dispatcher.setupForStreaming(op.hosts, op.handleRestorePoint)
+	} else {
+		execContext.dispatcher.setup(op.hosts)
+	}
 	return op.setupClusterHTTPRequest(hostRequestBodyMap)
 }
 
+func (op *nmaShowRestorePointsOp) handleRestorePoint(host string, item json.RawMessage) error {
+	var restorePoint RestorePoint
+	err := json.Unmarshal(item, &restorePoint)
+	if err != nil {
+		return fmt.Errorf("[%s] fail to parse restore point %s on host %s, details: %w", op.name, item, host, err)
+	}
+	return op.restorePointHandler(restorePoint)
+}
+
 func (op *nmaShowRestorePointsOp) execute(execContext *opEngineExecContext) error {
 	if err := op.runExecute(execContext); err != nil {
 		return err
@@ -139,20 +162,7 @@ func (op *nmaShowRestorePointsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
 
-// RestorePoint contains information about a single restore point.
-type RestorePoint struct {
-	// Name of the archive that this restore point was created in.
-	Archive string `json:"archive,omitempty"`
-	// The ID of the restore point. This is a form of a UID that is static for the restore point.
-	ID string `json:"id,omitempty"`
-	// The current index of this restore point. Lower value means it was taken more recently.
-	// This changes when new restore points are created.
-	Index int `json:"index,omitempty"`
-	// The timestamp when the restore point was created.
-	Timestamp string `json:"timestamp,omitempty"`
-	// The version of Vertica running when the restore point was created.
-	VerticaVersion string `json:"vertica_version,omitempty"`
-}
+type RestorePoint = vtypes.RestorePoint
 
 /*
 Sample response from the NMA restore-points endpoint:
@@ -181,6 +191,10 @@ func (op *nmaShowRestorePointsOp) processResult(execContext *opEngineExecContext
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
 
+		if result.isPassing() && op.restorePointHandler != nil {
+			// the restore points were already passed to the handler
+			return nil
+		}
 		if result.isPassing() {
 			var responseObj []RestorePoint
 			err := op.parseAndCheckResponse(host, result.content, &responseObj)
@@ -83,3 +83,27 @@ func TestShowRestorePointsRequestBody(t *testing.T) {
 	assert.NotContains(t, hostReq, `"start_timestamp"`)
 	assert.NotContains(t, hostReq, `"end_timestamp"`)
 }
+
+func TestStreamRestorePoints(t *testing.T) {
+	const hostName = "host1"
+	op := makeNMAShowRestorePointsOp(vlog.Printer{}, []string{hostName}, "testDB", "/communal", nil)
+	var restorePoints []RestorePoint
+	op.streamRestorePoints(func(restorePoint RestorePoint) error {
+		restorePoints = append(restorePoints, restorePoint)
+		return nil
+	})
+
+	err := op.handleRestorePoint(hostName, []byte(`{"archive": "db", "id": "4ee4119b", "index": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []RestorePoint{{Archive: "db", ID: "4ee4119b", Index: 1}}, restorePoints)
+	err = op.handleRestorePoint(hostName, []byte(`{"index": "one"}`))
+	assert.ErrorContains(t, err, "fail to parse restore point")
+
+	// streamed restore points are not stored in the execContext
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hostName: {host: hostName, status: SUCCESS, statusCode: SuccessCode},
+	}
+	assert.NoError(t, op.processResult(&execContext))
+	assert.Nil(t, execContext.restorePoints)
+}
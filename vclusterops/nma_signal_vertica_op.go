@@ -83,6 +83,10 @@ func (op *nmaSignalVerticaOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaSignalVerticaOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *nmaSignalVerticaOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,174 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// the steps of the smoke test
+const (
+	smokeTestSetupStep   = "setup"
+	smokeTestQueryStep   = "query"
+	smokeTestCleanupStep = "cleanup"
+)
+
+// nmaSmokeTestOp runs a step of the smoke test. The setup step creates the
+// test table and loads it, the query step reads the table from every host,
+// and the cleanup step drops the table. A failed step does not fail the op,
+// it is recorded in the result, and the steps after it are skipped except for
+// the cleanup.
+type nmaSmokeTestOp struct {
+	opBase
+	step            string
+	hostRequestBody string
+	rowCount        int64
+	result          *SmokeTestResult
+}
+
+type smokeTestRequestData struct {
+	sqlEndpointData
+	Table    string `json:"table"`
+	RowCount int64  `json:"row_count,omitempty"`
+}
+
+type smokeTestResponse struct {
+	Rows int64 `json:"rows"`
+}
+
+func makeNMASmokeTestOp(hosts []string, step, table string, rowCount int64, username, dbName string,
+	password *string, useDBPassword bool, result *SmokeTestResult) (nmaSmokeTestOp, error) {
+	op := nmaSmokeTestOp{}
+	op.name = "NMASmokeTestOp"
+	op.description = fmt.Sprintf("Run the %s step of the smoke test", step)
+	op.hosts = hosts
+	op.step = step
+	op.rowCount = rowCount
+	op.result = result
+
+	err := ValidateSQLEndpointData(op.name, useDBPassword, username, password, dbName)
+	if err != nil {
+		return op, err
+	}
+	requestData := smokeTestRequestData{Table: table}
+	requestData.sqlEndpointData = createSQLEndpointData(username, dbName, useDBPassword, password)
+	if step == smokeTestSetupStep {
+		requestData.RowCount = rowCount
+	}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaSmokeTestOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		httpRequest.buildNMAEndpoint("smoke-test/" + op.step)
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaSmokeTestOp) prepare(execContext *opEngineExecContext) error {
+	// the table is queried only once it is loaded, and is dropped if it may
+	// have been created
+	if op.step == smokeTestQueryStep && !op.result.Passed {
+		op.skipExecute = true
+		return nil
+	}
+	if op.step == smokeTestCleanupStep && len(op.result.Steps) == 0 {
+		op.skipExecute = true
+		return nil
+	}
+
+	execContext.dispatcher.setup(op.hosts)
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaSmokeTestOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the query step only reads the test table
+func (op *nmaSmokeTestOp) getClassification() OpClassification {
+	if op.step == smokeTestQueryStep {
+		return readOnlyClassification
+	}
+	return mutatingClassification
+}
+
+func (op *nmaSmokeTestOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaSmokeTestOp) processResult(_ *opEngineExecContext) error {
+	if len(op.result.Steps) == 0 {
+		op.result.Passed = true
+	}
+	for _, host := range op.hosts {
+		result, ok := op.clusterHTTPRequest.ResultCollection[host]
+		if !ok {
+			continue
+		}
+		op.logResponse(host, result)
+		step := op.checkStepResult(host, &result)
+		if !step.Passed {
+			op.logger.PrintWarning("[%s] the %s step of the smoke test failed on host %s: %s",
+				op.name, op.step, host, step.Error)
+			op.result.Passed = false
+		}
+		op.result.Steps = append(op.result.Steps, step)
+	}
+	return nil
+}
+
+func (op *nmaSmokeTestOp) checkStepResult(host string, result *hostHTTPResult) SmokeTestStep {
+	step := SmokeTestStep{Name: op.step, Host: host, Duration: result.duration.Round(time.Millisecond)}
+	if !result.isPassing() {
+		step.Error = fmt.Sprintf("request failed with status code %d", result.statusCode)
+		if result.err != nil {
+			step.Error = result.err.Error()
+		}
+		return step
+	}
+	response := smokeTestResponse{}
+	err := op.parseAndCheckResponse(host, result.content, &response)
+	if err != nil {
+		step.Error = err.Error()
+		return step
+	}
+	step.Rows = response.Rows
+	// every node should read all rows that were loaded
+	if op.step != smokeTestCleanupStep && response.Rows != op.rowCount {
+		step.Error = fmt.Sprintf("expected %d rows, got %d", op.rowCount, response.Rows)
+		return step
+	}
+	step.Passed = true
+	return step
+}
@@ -122,6 +122,10 @@ func (op *nmaSpreadSecurityOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaSpreadSecurityOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaSpreadSecurityOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
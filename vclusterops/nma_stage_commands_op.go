@@ -92,7 +92,7 @@ func (op *nmaStageCommandsOp) prepare(execContext *opEngineExecContext) error {
 	if err != nil {
 		return err
 	}
-	execContext.dispatcher.setup(op.hosts)
+	execContext.dispatcher.setupForSpilling(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
@@ -105,6 +105,10 @@ func (op *nmaStageCommandsOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaStageCommandsOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaStageCommandsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -0,0 +1,131 @@
+/*
+ (c) Copyright [2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// nmaStageContainerDiagnosticsOp asks the NMA on each node to stage container
+// runtime diagnostics: the pod logs, resource limits, and OOM events of the
+// node's pod. Collection is best effort, as not every node may run in a container.
+type nmaStageContainerDiagnosticsOp struct {
+	scrutinizeOpBase
+	logSizeLimitBytes int64
+	logAgeHours       int
+}
+
+type stageContainerDiagnosticsRequestData struct {
+	CatalogPath       string `json:"catalog_path"`
+	LogSizeLimitBytes int64  `json:"log_size_limit_bytes"`
+	LogAgeHours       int    `json:"log_age_hours"`
+}
+
+type stageContainerDiagnosticsResponseData struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+func makeNMAStageContainerDiagnosticsOp(logger vlog.Printer,
+	id string,
+	hosts []string,
+	hostNodeNameMap, hostCatPathMap map[string]string,
+	logSizeLimitBytes int64,
+	logAgeHours int) (nmaStageContainerDiagnosticsOp, error) {
+	// base members
+	op := nmaStageContainerDiagnosticsOp{}
+	op.name = "NMAStageContainerDiagnosticsOp"
+	op.description = "Stage container diagnostics"
+	op.logger = logger.WithName(op.name)
+	op.hosts = hosts
+
+	// scrutinize members
+	op.id = id
+	op.batch = scrutinizeBatchContainer
+	op.hostNodeNameMap = hostNodeNameMap
+	op.hostCatPathMap = hostCatPathMap
+	op.httpMethod = PostMethod
+	op.urlSuffix = "/container"
+
+	// custom members
+	op.logSizeLimitBytes = logSizeLimitBytes
+	op.logAgeHours = logAgeHours
+
+	// the caller is responsible for making sure hosts and maps match up exactly
+	err := validateHostMaps(hosts, hostNodeNameMap, hostCatPathMap)
+	return op, err
+}
+
+func (op *nmaStageContainerDiagnosticsOp) setupRequestBody(hosts []string) error {
+	op.hostRequestBodyMap = make(map[string]string, len(hosts))
+	for _, host := range hosts {
+		requestData := stageContainerDiagnosticsRequestData{}
+		requestData.CatalogPath = op.hostCatPathMap[host]
+		requestData.LogSizeLimitBytes = op.logSizeLimitBytes
+		requestData.LogAgeHours = op.logAgeHours
+
+		dataBytes, err := json.Marshal(requestData)
+		if err != nil {
+			return fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+		}
+
+		op.hostRequestBodyMap[host] = string(dataBytes)
+	}
+
+	return nil
+}
+
+func (op *nmaStageContainerDiagnosticsOp) prepare(execContext *opEngineExecContext) error {
+	err := op.setupRequestBody(op.hosts)
+	if err != nil {
+		return err
+	}
+	execContext.dispatcher.setupForSpilling(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *nmaStageContainerDiagnosticsOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *nmaStageContainerDiagnosticsOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
+func (op *nmaStageContainerDiagnosticsOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+// processResult does not fail scrutinize when a host cannot collect container
+// diagnostics, because the database diagnostics are still worth delivering
+func (op *nmaStageContainerDiagnosticsOp) processResult(_ *opEngineExecContext) error {
+	diagnosticsList := make([]stageContainerDiagnosticsResponseData, 0)
+	err := processStagedItemsResult(&op.scrutinizeOpBase, diagnosticsList)
+	if err != nil {
+		op.logger.Error(err, "failed to stage container diagnostics")
+		op.logger.PrintWarning("Container diagnostics could not be collected from all hosts. " +
+			"The scrutinize output will not include the container context of those hosts.")
+	}
+	return nil
+}
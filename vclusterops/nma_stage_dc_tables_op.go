@@ -78,7 +78,7 @@ func (op *nmaStageDCTablesOp) prepare(execContext *opEngineExecContext) error {
 	if err != nil {
 		return err
 	}
-	execContext.dispatcher.setup(op.hosts)
+	execContext.dispatcher.setupForSpilling(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
@@ -91,6 +91,10 @@ func (op *nmaStageDCTablesOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaStageDCTablesOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaStageDCTablesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
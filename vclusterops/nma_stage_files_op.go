@@ -87,7 +87,7 @@ func (op *nmaStageFilesOp) prepare(execContext *opEngineExecContext) error {
 	if err != nil {
 		return err
 	}
-	execContext.dispatcher.setup(op.hosts)
+	execContext.dispatcher.setupForSpilling(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
@@ -100,6 +100,10 @@ func (op *nmaStageFilesOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaStageFilesOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaStageFilesOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -94,7 +94,7 @@ func (op *nmaStageVerticaLogsOp) prepare(execContext *opEngineExecContext) error
 	if err != nil {
 		return err
 	}
-	execContext.dispatcher.setup(op.hosts)
+	execContext.dispatcher.setupForSpilling(op.hosts)
 
 	return op.setupClusterHTTPRequest(op.hosts)
 }
@@ -107,6 +107,10 @@ func (op *nmaStageVerticaLogsOp) execute(execContext *opEngineExecContext) error
 	return op.processResult(execContext)
 }
 
+func (op *nmaStageVerticaLogsOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaStageVerticaLogsOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
@@ -155,6 +155,10 @@ func (op *nmaStartNodeOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaStartNodeOp) getClassification() OpClassification {
+	return mutatingClassification
+}
+
 func (op *nmaStartNodeOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
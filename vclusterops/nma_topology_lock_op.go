@@ -0,0 +1,153 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// nmaTopologyLockOp takes or releases the lease object of the topology lock
+// in communal storage. The NMA creates the lease only if there is none, the
+// existing one has expired, or it has the same token, and returns the lease
+// that is in communal storage after the request.
+type nmaTopologyLockOp struct {
+	opBase
+	dbName          string
+	lock            TopologyLock
+	release         bool
+	hostRequestBody string
+}
+
+type topologyLockRequestData struct {
+	LockPath   string            `json:"lock_path"`
+	Token      string            `json:"token"`
+	Lock       *TopologyLock     `json:"lock,omitempty"`
+	TTLSeconds int               `json:"ttl_seconds,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+func makeNMATopologyLockOp(hosts []string, dbName, lockPath string, lock *TopologyLock,
+	configurationParameters map[string]string, release bool) (nmaTopologyLockOp, error) {
+	op := nmaTopologyLockOp{}
+	op.name = "NMATopologyLockOp"
+	op.description = "Take topology lock"
+	if release {
+		op.description = "Release topology lock"
+	}
+	op.hosts = hosts
+	op.dbName = dbName
+	op.lock = *lock
+	op.release = release
+
+	requestData := topologyLockRequestData{
+		LockPath:   lockPath,
+		Token:      lock.Token,
+		Parameters: configurationParameters,
+	}
+	if !release {
+		requestData.Lock = lock
+		requestData.TTLSeconds = int(time.Until(lock.ExpiresAt).Seconds())
+	}
+	dataBytes, err := json.Marshal(requestData)
+	if err != nil {
+		return op, fmt.Errorf("[%s] fail to marshal request data to JSON string, detail %w", op.name, err)
+	}
+	op.hostRequestBody = string(dataBytes)
+
+	return op, nil
+}
+
+func (op *nmaTopologyLockOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = PostMethod
+		if op.release {
+			httpRequest.Method = DeleteMethod
+		}
+		httpRequest.buildNMAEndpoint("communal-storage/lock")
+		httpRequest.RequestData = op.hostRequestBody
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *nmaTopologyLockOp) prepare(execContext *opEngineExecContext) error {
+	candidates, err := execContext.initiatorCandidates(op.hosts)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("[%s] no hosts to reach communal storage from", op.name)
+	}
+	// one NMA is enough to reach communal storage
+	initiator := []string{getInitiator(candidates)}
+	execContext.dispatcher.setup(initiator)
+
+	return op.setupClusterHTTPRequest(initiator)
+}
+
+func (op *nmaTopologyLockOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+// the lease is written to communal storage. Taking it again with the same
+// token only extends it, as does releasing it again.
+func (op *nmaTopologyLockOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
+func (op *nmaTopologyLockOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
+
+func (op *nmaTopologyLockOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			continue
+		}
+		if op.release {
+			continue
+		}
+
+		// the response object is the lease in communal storage, e.g.,:
+		// {"token": "8c1f...", "owner": "dbadmin", "host": "admin-1", "pid": 4242,
+		//  "command": "add_node", "request_id": "vc-1f2e...", "start_time": "...", "expires_at": "..."}
+		var holder TopologyLock
+		err := op.parseAndCheckResponse(host, result.content, &holder)
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+		if holder.Token != op.lock.Token {
+			return &TopologyLockHeldError{DBName: op.dbName, Holder: holder}
+		}
+	}
+
+	return allErrs
+}
@@ -173,6 +173,10 @@ func (op *nmaUploadConfigOp) execute(execContext *opEngineExecContext) error {
 	return op.processResult(execContext)
 }
 
+func (op *nmaUploadConfigOp) getClassification() OpClassification {
+	return idempotentClassification
+}
+
 func (op *nmaUploadConfigOp) finalize(_ *opEngineExecContext) error {
 	return nil
 }
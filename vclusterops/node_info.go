@@ -15,28 +15,14 @@
 
 package vclusterops
 
-import mapset "github.com/deckarep/golang-set/v2"
+import "github.com/vertica/vcluster/vclusterops/vtypes"
 
-// NodeInfo represents information to identify a node.
-type NodeInfo struct {
-	Address     string `json:"address"`
-	Name        string `json:"name"` // vnode name, e.g., v_dbname_node0001
-	State       string `json:"state"`
-	CatalogPath string `json:"catalog_path"`
-	Subcluster  string `json:"subcluster"`
-	Sandbox     string `json:"sandbox"`
-	IsPrimary   bool   `json:"is_primary"`
-	Version     string `json:"version"`
-}
+import mapset "github.com/deckarep/golang-set/v2"
 
-// NodeInfo does not contain Eon specific information
-type NodeInfoEnterprise struct {
-	Address     string `json:"address"`
-	Name        string `json:"name"` // vnode name, e.g., v_dbname_node0001
-	State       string `json:"state"`
-	CatalogPath string `json:"catalog_path"`
-	Version     string `json:"version"`
-}
+type (
+	NodeInfo           = vtypes.NodeInfo
+	NodeInfoEnterprise = vtypes.NodeInfoEnterprise
+)
 
 type nodesInfo struct {
 	NodeList []NodeInfo `json:"node_list"`
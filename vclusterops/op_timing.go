@@ -0,0 +1,96 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type OpTiming = vtypes.OpTiming
+
+// CommandTimings keeps the timing of the ops run by commands, so that slow
+// phases can be found without trace logging. It is safe to share between
+// commands, which add their ops in the order they are run.
+type CommandTimings struct {
+	mu  sync.Mutex
+	ops []OpTiming
+}
+
+func (t *CommandTimings) add(timing *OpTiming) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ops = append(t.ops, *timing)
+}
+
+// Ops returns a copy of the op timings collected so far
+func (t *CommandTimings) Ops() []OpTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ops := make([]OpTiming, len(t.ops))
+	copy(ops, t.ops)
+	return ops
+}
+
+// Total returns the sum of the time of the ops collected so far
+func (t *CommandTimings) Total() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var total time.Duration
+	for i := range t.ops {
+		total += t.ops[i].Prepare + t.ops[i].Execute + t.ops[i].Finalize
+	}
+	return total
+}
+
+// Reset drops the op timings collected so far, e.g., before running the next command
+func (t *CommandTimings) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ops = nil
+}
+
+// opTimer measures the phases of an op run by the op engine
+type opTimer struct {
+	timing OpTiming
+	start  time.Time
+}
+
+func (timer *opTimer) startPhase() {
+	timer.start = time.Now()
+}
+
+func (timer *opTimer) endPhase(phase *time.Duration) {
+	*phase = time.Since(timer.start)
+}
+
+// record adds the timing of the op to the timings, after the request
+// durations of the op are known
+func (timer *opTimer) record(timings *CommandTimings, op clusterOp) {
+	requestDuration, hostDurations := op.getRequestDurations()
+	timer.timing.Name = op.getName()
+	timer.timing.Request = requestDuration
+	timer.timing.Process = max(timer.timing.Execute-requestDuration, 0)
+	if len(hostDurations) > 0 {
+		timer.timing.Hosts = make(map[string]time.Duration, len(hostDurations))
+		for host, duration := range hostDurations {
+			timer.timing.Hosts[host] = duration
+		}
+	}
+	timings.add(&timer.timing)
+}
@@ -0,0 +1,138 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// An options field is marked deprecated with the deprecated struct tag, whose
+// value is the path of the replacement field in the same options struct, e.g.,
+//
+//	// Deprecated: use NewField instead
+//	OldField string `deprecated:"NewField"`
+//	// Deprecated: use TargetDB.UserName instead
+//	TargetUserName string `deprecated:"TargetDB.UserName"`
+//
+// A deprecated field set by a caller is migrated to its replacement, unless the
+// replacement is set too. An empty tag value means the field has no replacement,
+// and its value is ignored. Either way, the field is reported in the deprecation
+// report of the options, so that callers can update their code.
+const deprecatedTag = "deprecated"
+
+// DeprecatedOption reports a deprecated option that a command was called with
+type DeprecatedOption struct {
+	Option      string `json:"option"`
+	Replacement string `json:"replacement,omitempty"`
+	// whether the value of the option was moved to the replacement
+	Migrated bool   `json:"migrated"`
+	Message  string `json:"message"`
+}
+
+// GetDeprecationReport returns the deprecated options set in the options,
+// found when a command validated the options
+func (opt *DatabaseOptions) GetDeprecationReport() []DeprecatedOption {
+	return slices.Clone(opt.deprecatedOptions)
+}
+
+// migrateDeprecatedOptions migrates the deprecated fields set in options, a
+// pointer to an options struct embedding opt, and records them in the
+// deprecation report of opt
+func (opt *DatabaseOptions) migrateDeprecatedOptions(options any, logger vlog.Printer) {
+	value := reflect.ValueOf(options)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	for _, deprecated := range migrateDeprecatedFields(value.Elem(), value.Elem()) {
+		logger.PrintWarning("%s", deprecated.Message)
+		// an options struct can be checked more than once, e.g., by the command and
+		// by the base options validation
+		opt.deprecatedOptions = slices.DeleteFunc(opt.deprecatedOptions, func(d DeprecatedOption) bool {
+			return d.Option == deprecated.Option
+		})
+		opt.deprecatedOptions = append(opt.deprecatedOptions, deprecated)
+	}
+}
+
+// migrateDeprecatedFields walks the fields of a struct value, including the
+// fields of embedded structs, and migrates the deprecated fields that are set.
+// Replacement fields are looked up in root, the outermost options struct.
+func migrateDeprecatedFields(root, value reflect.Value) []DeprecatedOption {
+	var deprecatedOptions []DeprecatedOption
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		if field.Anonymous && fieldValue.Kind() == reflect.Struct {
+			deprecatedOptions = append(deprecatedOptions, migrateDeprecatedFields(root, fieldValue)...)
+			continue
+		}
+
+		replacement, isDeprecated := field.Tag.Lookup(deprecatedTag)
+		if !isDeprecated || !field.IsExported() || fieldValue.IsZero() {
+			continue
+		}
+		deprecatedOptions = append(deprecatedOptions, migrateDeprecatedField(root, field.Name, fieldValue, replacement))
+	}
+	return deprecatedOptions
+}
+
+func migrateDeprecatedField(root reflect.Value, name string, fieldValue reflect.Value,
+	replacement string) DeprecatedOption {
+	deprecated := DeprecatedOption{Option: name, Replacement: replacement}
+	if replacement == "" {
+		deprecated.Message = fmt.Sprintf("option %s is deprecated and ignored", name)
+		return deprecated
+	}
+
+	replacementValue := fieldByPath(root, replacement)
+	switch {
+	case !replacementValue.IsValid() || !replacementValue.CanSet() ||
+		!fieldValue.Type().AssignableTo(replacementValue.Type()):
+		deprecated.Message = fmt.Sprintf("option %s is deprecated and cannot be migrated to option %s",
+			name, replacement)
+	case !replacementValue.IsZero():
+		deprecated.Message = fmt.Sprintf("option %s is deprecated and ignored as its replacement %s is set",
+			name, replacement)
+	default:
+		replacementValue.Set(fieldValue)
+		fieldValue.SetZero()
+		deprecated.Migrated = true
+		deprecated.Message = fmt.Sprintf("option %s is deprecated, its value is moved to option %s",
+			name, replacement)
+	}
+	return deprecated
+}
+
+// fieldByPath returns the field of a struct value at a dotted path, or the
+// zero Value if there is no such field
+func fieldByPath(value reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+		value = value.FieldByName(name)
+		if !value.IsValid() {
+			return value
+		}
+	}
+	return value
+}
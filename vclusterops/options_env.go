@@ -0,0 +1,89 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// environment variables that ApplyEnvDefaults reads
+const (
+	EnvDBName     = "VCLUSTER_DB_NAME"
+	EnvHosts      = "VCLUSTER_HOSTS" // comma-separated list of hosts
+	EnvUserName   = "VCLUSTER_DB_USER"
+	EnvIPv6       = "VCLUSTER_IPV6" // true or false
+	EnvKeyPath    = "VCLUSTER_TLS_KEY_PATH"
+	EnvCertPath   = "VCLUSTER_TLS_CERT_PATH"
+	EnvCACertPath = "VCLUSTER_TLS_CA_CERT_PATH"
+)
+
+// ApplyEnvDefaults populates the options that are not set yet from the
+// VCLUSTER_* environment variables, for services that are configured through
+// their environment. It is opt-in: options are never read from the environment
+// unless this is called.
+//
+// Values that are already set in the options always take precedence over the
+// environment, and empty environment variables are ignored. The TLS paths are
+// only used when no in-memory key and certificate or CertReloader is set, and
+// set a CertReloader so that rotated certificates are picked up.
+func (opt *DatabaseOptions) ApplyEnvDefaults() error {
+	if dbName := os.Getenv(EnvDBName); opt.DBName == "" && dbName != "" {
+		opt.DBName = dbName
+	}
+	if userName := os.Getenv(EnvUserName); opt.UserName == "" && userName != "" {
+		opt.UserName = userName
+	}
+	if hosts := os.Getenv(EnvHosts); len(opt.RawHosts) == 0 && hosts != "" {
+		for _, host := range strings.Split(hosts, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				opt.RawHosts = append(opt.RawHosts, host)
+			}
+		}
+	}
+
+	// IPv6 is a bool, so an explicit false cannot be told apart from unset,
+	// and the environment can only turn it on
+	if ipv6 := os.Getenv(EnvIPv6); ipv6 != "" {
+		useIPv6, err := strconv.ParseBool(ipv6)
+		if err != nil {
+			return fmt.Errorf("invalid value %q of environment variable %s, it must be true or false", ipv6, EnvIPv6)
+		}
+		opt.IPv6 = opt.IPv6 || useIPv6
+	}
+
+	return opt.applyTLSPathsFromEnv()
+}
+
+func (opt *DatabaseOptions) applyTLSPathsFromEnv() error {
+	keyPath := os.Getenv(EnvKeyPath)
+	certPath := os.Getenv(EnvCertPath)
+	caCertPath := os.Getenv(EnvCACertPath)
+	if keyPath == "" && certPath == "" && caCertPath == "" {
+		return nil
+	}
+	if opt.CertReloader != nil || opt.Key != "" || opt.Cert != "" {
+		return nil
+	}
+	if keyPath == "" || certPath == "" {
+		return fmt.Errorf("environment variables %s and %s must be set together", EnvKeyPath, EnvCertPath)
+	}
+
+	opt.CertReloader = MakeCertReloader(keyPath, certPath, caCertPath)
+	return nil
+}
@@ -0,0 +1,221 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type ReplicationProgress = vtypes.ReplicationProgress
+
+// DefaultReplicationStallTimeout is the time in seconds after which a
+// replication job that transferred nothing is reported as stalled
+const DefaultReplicationStallTimeout = 300
+
+type VPollReplicationStatusOptions struct {
+	VReplicationStatusDatabaseOptions
+	// Timeout in seconds to wait for the replication to finish,
+	// a value <= 0 means waiting until the replication finishes
+	PollingTimeout int
+	// optional, called with the progress of the job on every poll of its status
+	OnProgress func(ReplicationProgress)
+	// Time in seconds after which a job that made no progress is reported as
+	// stalled, a value <= 0 means stalls are not detected. A stalled job is
+	// still polled unless FailOnStall is set.
+	StallTimeout int
+	// Stop polling a stalled job, and return a ReplicationStalledError with the
+	// diagnostics of the initiators of the job
+	FailOnStall bool
+	// optional, the source database of the job, whose initiator is included in
+	// the diagnostics of a stalled job. Its first host is the initiator.
+	SourceDB *DatabaseOptions
+}
+
+func VPollReplicationStatusFactory() VPollReplicationStatusOptions {
+	options := VPollReplicationStatusOptions{}
+	options.VReplicationStatusDatabaseOptions = VReplicationStatusFactory()
+	options.StallTimeout = DefaultReplicationStallTimeout
+	return options
+}
+
+func (options *VPollReplicationStatusOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	err := options.VReplicationStatusDatabaseOptions.validateAnalyzeOptions(logger)
+	if err != nil {
+		return err
+	}
+	if options.FailOnStall && options.StallTimeout <= 0 {
+		return fmt.Errorf("must specify a stall timeout to fail on a stalled replication")
+	}
+	if options.SourceDB == nil {
+		return nil
+	}
+
+	if options.SourceDB.DBName == "" || len(options.SourceDB.Hosts) == 0 {
+		return fmt.Errorf("must specify the name and the hosts of the source database for the replication diagnostics")
+	}
+	options.SourceDB.Hosts, err = util.ResolveRawHostsToAddresses(options.SourceDB.Hosts, options.SourceDB.IPv6)
+	if err != nil {
+		return err
+	}
+	if options.SourceDB.UserName == "" {
+		options.SourceDB.UserName, err = util.GetCurrentUsername()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VPollReplicationStatus waits for an asynchronous replication job, identified by
+// its transaction ID, to finish and returns its final status. The status is
+// polled from one target host at a time. If that host goes down while polling,
+// for example because its node is restarted during a long copy, polling
+// continues from the other target hosts. A replication job that finished
+// with a failure is returned as a status with an error. With FailOnStall, a
+// job that made no progress for the stall timeout is returned as a status with
+// a ReplicationStalledError, which has the diagnostics of its initiators.
+func (vcc VClusterCommands) VPollReplicationStatus(options *VPollReplicationStatusOptions) (*ReplicationStatusResponse, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	// produce replication status polling instructions
+	replicationStatus := ReplicationStatusResponse{}
+	instructions, err := vcc.producePollReplicationStatusInstructions(options, &replicationStatus)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := makeClusterOpEngine(instructions, &options.TargetDB)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Log)
+	stalledErr := &ReplicationStalledError{}
+	if errors.As(runError, &stalledErr) {
+		stalledErr.Diagnostics = vcc.collectReplicationDiagnostics(options, stalledErr)
+		return &replicationStatus, stalledErr
+	}
+	if runError != nil {
+		return nil, fmt.Errorf("fail to poll replication status: %w", runError)
+	}
+
+	if replicationStatus.Status == replicationStatusFailed {
+		return &replicationStatus, fmt.Errorf("replication with transaction ID %d failed in op %s on node %s",
+			replicationStatus.TransactionID, replicationStatus.OpName, replicationStatus.NodeName)
+	}
+	if replicationStatus.Status == replicationStatusCanceled {
+		return &replicationStatus, fmt.Errorf("replication with transaction ID %d was canceled in op %s",
+			replicationStatus.TransactionID, replicationStatus.OpName)
+	}
+	return &replicationStatus, nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for waiting for a replication job to finish
+//   - Poll replication status, failing over to other target hosts if needed
+//
+// There is no NMA health check up front as some target hosts are allowed to be down.
+func (vcc VClusterCommands) producePollReplicationStatusInstructions(options *VPollReplicationStatusOptions,
+	replicationStatus *ReplicationStatusResponse) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	// verify the username for connecting to the target database
+	targetUsePassword := false
+	if options.TargetDB.Password != nil {
+		targetUsePassword = true
+		if options.TargetDB.UserName == "" {
+			username, e := util.GetCurrentUsername()
+			if e != nil {
+				return instructions, e
+			}
+			options.TargetDB.UserName = username
+		}
+		vcc.Log.Info("Current target username", "username", options.TargetDB.UserName)
+	}
+
+	nmaReplicationStatusData := nmaReplicationStatusRequestData{}
+	nmaReplicationStatusData.DBName = options.TargetDB.DBName
+	nmaReplicationStatusData.ExcludedTransactionIDs = []int64{} // Doesn't matter since we specify a transaction ID
+	nmaReplicationStatusData.GetTransactionIDsOnly = false      // Get all replication status info
+	nmaReplicationStatusData.TransactionID = options.TransactionID
+	nmaReplicationStatusData.UserName = options.TargetDB.UserName
+	nmaReplicationStatusData.Password = options.TargetDB.Password
+
+	throughput := &replicationThroughput{onProgress: options.OnProgress,
+		stallTimeout: time.Duration(options.StallTimeout) * time.Second, failOnStall: options.FailOnStall}
+	nmaPollReplicationProgressOp, err := makeNMAPollReplicationProgressOp(options.TargetDB.Hosts, targetUsePassword,
+		&nmaReplicationStatusData, options.PollingTimeout, replicationStatus, throughput)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions, &nmaPollReplicationProgressOp)
+
+	return instructions, nil
+}
+
+// collectReplicationDiagnostics collects the diagnostics of a stalled job on
+// the target host it was polled from, and on the source initiator if the source
+// database is known. Each initiator probes the network to the other one.
+func (vcc VClusterCommands) collectReplicationDiagnostics(options *VPollReplicationStatusOptions,
+	stalledErr *ReplicationStalledError) *ReplicationDiagnostics {
+	diagnostics := &ReplicationDiagnostics{CollectedAt: time.Now()}
+	sourceHost := ""
+	if options.SourceDB != nil {
+		sourceHost = options.SourceDB.Hosts[0]
+	}
+
+	vcc.Log.PrintInfo("Collecting the diagnostics of the stalled replication with transaction ID %d",
+		options.TransactionID)
+	diagnostics.Hosts = append(diagnostics.Hosts, vcc.collectReplicationHostDiagnostics(&options.TargetDB,
+		ReplicationRoleTarget, stalledErr.Host, sourceHost, options.TransactionID))
+	if options.SourceDB != nil {
+		diagnostics.Hosts = append(diagnostics.Hosts, vcc.collectReplicationHostDiagnostics(options.SourceDB,
+			ReplicationRoleSource, sourceHost, stalledErr.Host, options.TransactionID))
+	}
+	return diagnostics
+}
+
+func (vcc VClusterCommands) collectReplicationHostDiagnostics(dbOptions *DatabaseOptions, role, host, peerHost string,
+	transactionID int64) ReplicationHostDiagnostics {
+	diagnostics := ReplicationHostDiagnostics{Host: host, Role: role}
+	op, err := makeNMAReplicationDiagnosticsOp(host, peerHost, dbOptions.UserName, dbOptions.DBName,
+		dbOptions.Password, dbOptions.Password != nil, transactionID, &diagnostics)
+	if err == nil {
+		clusterOpEngine := makeClusterOpEngine([]clusterOp{&op}, dbOptions)
+		err = clusterOpEngine.run(vcc.Log)
+	}
+	if err != nil {
+		diagnostics.Error = err.Error()
+		vcc.Log.PrintWarning("fail to collect the replication diagnostics on %s host %s: %s", role, host, err)
+	}
+	return diagnostics
+}
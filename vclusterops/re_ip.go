@@ -58,7 +58,7 @@ func (options *VReIPOptions) validateRequiredOptions(logger vlog.Printer) error
 }
 
 func (options *VReIPOptions) validateExtraOptions() error {
-	err := util.ValidateRequiredAbsPath(options.CatalogPrefix, "catalog path")
+	err := util.ValidateSafePath(options.CatalogPrefix, "catalog path")
 	if err != nil {
 		return err
 	}
@@ -161,6 +161,12 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 		return err
 	}
 
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, ReIPCmd)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
 	// VER-93369 may improve this if the CLI knows which nodes are primary
 	// from the config file
 	var pVDB *VCoordinationDatabase
@@ -198,6 +204,9 @@ func (vcc VClusterCommands) VReIP(options *VReIPOptions) error {
 		return fmt.Errorf("fail to re-ip: %w", runError)
 	}
 
+	if options.HostAliases != nil {
+		options.HostAliases.updateFromReIPList(options.ReIPList)
+	}
 	return nil
 }
 
@@ -0,0 +1,233 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// how refresh_sandbox brings a sandbox up to date with the main cluster
+const (
+	// refresh the sandbox incrementally if the database supports it,
+	// otherwise recreate it
+	SandboxRefreshAuto = "auto"
+	// only refresh the sandbox incrementally
+	SandboxRefreshIncremental = "incremental"
+	// unsandbox the subclusters of the sandbox, and sandbox them again
+	SandboxRefreshRecreate = "recreate"
+)
+
+type SandboxRefreshNotSupportedError = vtypes.SandboxRefreshNotSupportedError
+
+type VRefreshSandboxOptions struct {
+	DatabaseOptions
+	// name of the sandbox to refresh
+	SandboxName string
+	// how to refresh the sandbox, SandboxRefreshAuto by default
+	RefreshMode string
+	// options used when the sandbox is recreated, see VSandboxOptions
+	SaveRp bool
+	Imeta  bool
+	Sls    bool
+
+	// output: whether the sandbox was refreshed incrementally, rather than
+	// recreated
+	Incremental bool
+}
+
+func VRefreshSandboxOptionsFactory() VRefreshSandboxOptions {
+	options := VRefreshSandboxOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VRefreshSandboxOptions) setDefaultValues() {
+	options.DatabaseOptions.setDefaultValues()
+	options.RefreshMode = SandboxRefreshAuto
+}
+
+func (options *VRefreshSandboxOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(RefreshSandboxCmd, logger)
+	if err != nil {
+		return err
+	}
+
+	if options.SandboxName == "" {
+		return fmt.Errorf("must specify a sandbox name")
+	}
+	err = util.ValidateSandboxName(options.SandboxName)
+	if err != nil {
+		return err
+	}
+
+	switch options.RefreshMode {
+	case SandboxRefreshAuto, SandboxRefreshIncremental, SandboxRefreshRecreate:
+	default:
+		return fmt.Errorf("invalid refresh mode %q, it must be %s, %s or %s", options.RefreshMode,
+			SandboxRefreshAuto, SandboxRefreshIncremental, SandboxRefreshRecreate)
+	}
+	return nil
+}
+
+func (options *VRefreshSandboxOptions) analyzeOptions() (err error) {
+	// resolve RawHosts to be IP addresses
+	if len(options.RawHosts) > 0 {
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRefreshSandboxOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VRefreshSandbox brings the data of a sandbox up to date with the main
+// cluster. Depending on RefreshMode, the sandbox is refreshed incrementally,
+// or recreated by unsandboxing its subclusters and sandboxing them again.
+func (vcc VClusterCommands) VRefreshSandbox(options *VRefreshSandboxOptions) error {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	vdb := makeVCoordinationDatabase()
+	sandboxes, err := vcc.listSandboxes(&options.DatabaseOptions, &vdb)
+	if err != nil {
+		return err
+	}
+	sandbox, found := findSandbox(sandboxes, options.SandboxName)
+	if !found {
+		return fmt.Errorf("sandbox %s does not exist", options.SandboxName)
+	}
+
+	if options.RefreshMode != SandboxRefreshRecreate {
+		supported, e := vcc.refreshSandboxIncrementally(options, &vdb)
+		if e != nil {
+			return e
+		}
+		if supported {
+			options.Incremental = true
+			return nil
+		}
+		if options.RefreshMode == SandboxRefreshIncremental {
+			return &SandboxRefreshNotSupportedError{Sandbox: options.SandboxName}
+		}
+		vcc.Log.PrintInfo("Sandbox %s cannot be refreshed incrementally, it is recreated", options.SandboxName)
+	}
+
+	return vcc.recreateSandbox(options, &sandbox)
+}
+
+// refreshSandboxIncrementally asks the main cluster to refresh the sandbox,
+// and returns whether the database supports it
+//
+// The generated instructions will later perform the following operations:
+//   - Refresh the sandbox using an up host of the main cluster
+func (vcc VClusterCommands) refreshSandboxIncrementally(options *VRefreshSandboxOptions,
+	vdb *VCoordinationDatabase) (bool, error) {
+	err := options.setUsePassword(vcc.Log)
+	if err != nil {
+		return false, err
+	}
+	var mainHosts []string
+	for host, sandbox := range getUpHostOfEachCluster(vdb) {
+		if sandbox == util.MainClusterSandbox {
+			mainHosts = append(mainHosts, host)
+		}
+	}
+	if len(mainHosts) == 0 {
+		return false, fmt.Errorf("cannot find any up host of the main cluster")
+	}
+
+	supported := false
+	httpsRefreshSandboxOp, err := makeHTTPSRefreshSandboxOp(mainHosts, options.usePassword,
+		options.UserName, options.Password, options.SandboxName, &supported)
+	if err != nil {
+		return false, err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&httpsRefreshSandboxOp}, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return false, fmt.Errorf("fail to refresh sandbox %s: %w", options.SandboxName, runError)
+	}
+	return supported, nil
+}
+
+// recreateSandbox unsandboxes all subclusters of the sandbox, and sandboxes
+// them again, so that the sandbox starts from the current data of the main
+// cluster
+func (vcc VClusterCommands) recreateSandbox(options *VRefreshSandboxOptions, sandbox *SandboxInfo) error {
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, RefreshSandboxCmd)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
+	var unsandboxed []string
+	for _, subcluster := range sandbox.Subclusters {
+		unsandboxOptions := VUnsandboxOptionsFactory()
+		unsandboxOptions.DatabaseOptions = options.DatabaseOptions
+		// the sandbox is recreated under a single topology lock
+		unsandboxOptions.UseTopologyLock = false
+		unsandboxOptions.SCName = subcluster.Name
+		err = vcc.VUnsandbox(&unsandboxOptions)
+		if err != nil {
+			if len(unsandboxed) > 0 {
+				return fmt.Errorf("subclusters %v are unsandboxed, but fail to unsandbox subcluster %s: %w",
+					unsandboxed, subcluster.Name, err)
+			}
+			return err
+		}
+		unsandboxed = append(unsandboxed, subcluster.Name)
+	}
+
+	for i, scName := range unsandboxed {
+		sandboxOptions := VSandboxOptionsFactory()
+		sandboxOptions.DatabaseOptions = options.DatabaseOptions
+		sandboxOptions.UseTopologyLock = false
+		sandboxOptions.SandboxName = options.SandboxName
+		sandboxOptions.SCName = scName
+		sandboxOptions.SaveRp = options.SaveRp
+		sandboxOptions.Imeta = options.Imeta
+		sandboxOptions.Sls = options.Sls
+		err = vcc.VSandbox(&sandboxOptions)
+		if err != nil {
+			return fmt.Errorf("subclusters %v are unsandboxed, but fail to sandbox subcluster %s again: %w",
+				unsandboxed[i:], scName, err)
+		}
+	}
+	return nil
+}
+
+func findSandbox(sandboxes []SandboxInfo, name string) (SandboxInfo, bool) {
+	for _, sandbox := range sandboxes {
+		if sandbox.Name == name {
+			return sandbox, true
+		}
+	}
+	return SandboxInfo{}, false
+}
@@ -0,0 +1,69 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestRefreshSandboxOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	opt := VRefreshSandboxOptionsFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"vnode1"}
+	assert.Equal(t, SandboxRefreshAuto, opt.RefreshMode)
+
+	// negative: no sandbox name
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "must specify a sandbox name")
+
+	opt.SandboxName = "sand1"
+	assert.NoError(t, opt.validateParseOptions(logger))
+
+	// negative: unknown refresh mode
+	opt.RefreshMode = "nightly"
+	assert.ErrorContains(t, opt.validateParseOptions(logger), `invalid refresh mode "nightly"`)
+}
+
+func TestRefreshSandboxOp(t *testing.T) {
+	supported := false
+	op, err := makeHTTPSRefreshSandboxOp([]string{"192.168.1.101"}, false, "", nil, "sand1", &supported)
+	assert.NoError(t, err)
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"detail": ""}`},
+	}
+	assert.NoError(t, op.processResult(&execContext))
+	assert.True(t, supported)
+
+	// the database cannot refresh the sandbox incrementally
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: FAILURE, statusCode: NotFoundCode, err: assert.AnError},
+	}
+	assert.NoError(t, op.processResult(&execContext))
+	assert.False(t, supported)
+
+	// a sandbox is found by name
+	sandboxes := []SandboxInfo{{Name: "sand1"}, {Name: "sand2"}}
+	sandbox, found := findSandbox(sandboxes, "sand2")
+	assert.True(t, found)
+	assert.Equal(t, "sand2", sandbox.Name)
+	_, found = findSandbox(sandboxes, "sand3")
+	assert.False(t, found)
+}
@@ -68,7 +68,7 @@ func (options *VRemoveNodeOptions) validateRequiredOptions(logger vlog.Printer)
 func (options *VRemoveNodeOptions) validateExtraOptions() error {
 	// data prefix
 	if options.DataPrefix != "" {
-		return util.ValidateRequiredAbsPath(options.DataPrefix, "data path")
+		return util.ValidateSafePath(options.DataPrefix, "data path")
 	}
 	return nil
 }
@@ -125,6 +125,12 @@ func (vcc VClusterCommands) VRemoveNode(options *VRemoveNodeOptions) (VCoordinat
 		return vdb, err
 	}
 
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, RemoveNodeCmd)
+	if err != nil {
+		return vdb, err
+	}
+	defer releaseLock()
+
 	err = vcc.getVDBFromRunningDB(&vdb, &options.DatabaseOptions)
 	if err != nil {
 		return vdb, err
@@ -158,11 +164,14 @@ func (vcc VClusterCommands) VRemoveNode(options *VRemoveNodeOptions) (VCoordinat
 	}
 
 	vdb, err = vcc.removeNodesInCatalog(options, &vdb)
-	if err != nil || len(hostsNotInCatalog) == 0 {
-		return vdb, err
+	if err == nil && len(hostsNotInCatalog) > 0 {
+		vdb, err = vcc.handleRemoveNodeForHostsNotInCatalog(&vdb, options, hostsNotInCatalog)
 	}
-
-	return vcc.handleRemoveNodeForHostsNotInCatalog(&vdb, options, hostsNotInCatalog)
+	if err == nil && options.HostAliases != nil {
+		options.HostAliases.removeHosts(options.HostsToRemove)
+		options.HostAliases.removeHosts(hostsNotInCatalog)
+	}
+	return vdb, err
 }
 
 // removeUnboundNodesInCatalog removes unbound nodes from the catalog
@@ -325,7 +334,7 @@ func (options *VRemoveNodeOptions) completeVDBSetting(vdb *VCoordinationDatabase
 		// checking this here because now we have got eon value from
 		// the running db. This will be removed once we are able to get
 		// the depot path from db through an https endpoint(VER-88122).
-		err := util.ValidateRequiredAbsPath(options.DepotPrefix, "depot path")
+		err := util.ValidateSafePath(options.DepotPrefix, "depot path")
 		if err != nil {
 			return err
 		}
@@ -631,7 +640,7 @@ func (vcc VClusterCommands) produceSpreadRemoveNodeOp(instructions *[]clusterOp,
 // setInitiator sets the initiator as the first primary up node that is not
 // in the list of hosts to remove.
 func (options *VRemoveNodeOptions) setInitiator(primaryUpNodes []string) error {
-	initiatorHost, err := getInitiatorHost(primaryUpNodes, options.HostsToRemove)
+	initiatorHost, err := getInitiatorHost(primaryUpNodes, options.HostsToRemove, options.Initiators)
 	if err != nil {
 		return err
 	}
@@ -89,13 +89,13 @@ func (options *VRemoveScOptions) validateExtraOptions() error {
 	// VER-88096 will get data path and depot path from /nodes
 	// so the validation below may be removed
 	// data prefix
-	err := util.ValidateRequiredAbsPath(options.DataPrefix, "data path")
+	err := util.ValidateSafePath(options.DataPrefix, "data path")
 	if err != nil {
 		return err
 	}
 
 	// depot path
-	return util.ValidateRequiredAbsPath(options.DepotPrefix, "depot path")
+	return util.ValidateSafePath(options.DepotPrefix, "depot path")
 }
 
 func (options *VRemoveScOptions) validateParseOptions(logger vlog.Printer) error {
@@ -157,6 +157,12 @@ func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VC
 		return vdb, err
 	}
 
+	releaseLock, err := vcc.takeTopologyLock(&removeScOpt.DatabaseOptions, RemoveSubclusterCmd)
+	if err != nil {
+		return vdb, err
+	}
+	defer releaseLock()
+
 	// If the users provide extra node information, we will check and do re-ip for the nodes in
 	// the subcluster if necessary. This is to address the case where catalog has stale IPs of the
 	// nodes in the subcluster, which would cause a node removal failure at delete-directory step.
@@ -195,6 +201,8 @@ func (vcc VClusterCommands) VRemoveSubcluster(removeScOpt *VRemoveScOptions) (VC
 		// Remove nodes from the target subcluster
 		removeNodeOpt := VRemoveNodeOptionsFactory()
 		removeNodeOpt.DatabaseOptions = removeScOpt.DatabaseOptions
+		// remove_subcluster already holds the topology lock
+		removeNodeOpt.UseTopologyLock = false
 		removeNodeOpt.HostsToRemove = hostsToRemove
 		removeNodeOpt.UnboundNodesToRemove = unboundNodesToRemove
 		removeNodeOpt.ForceDelete = removeScOpt.ForceDelete
@@ -332,7 +340,7 @@ func (vcc VClusterCommands) dropSubcluster(vdb *VCoordinationDatabase, options *
 	// the initiator is a list of one primary up host
 	// that will call the https /v1/subclusters/{scName}/drop endpoint
 	// as the endpoint will drop a subcluster, we only need one host to do so
-	initiator, err := getInitiatorHost(vdb.PrimaryUpNodes, []string{})
+	initiator, err := getInitiatorHost(vdb.PrimaryUpNodes, []string{}, options.Initiators)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,252 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"golang.org/x/exp/maps"
+)
+
+type VRenameDatabaseOptions struct {
+	// basic db info, DBName is the current name of the database
+	DatabaseOptions
+	// new name of the database
+	NewDBName string
+	// timeout for polling the states of all nodes when the database is restarted
+	StatePollingTimeout int
+}
+
+func VRenameDatabaseFactory() VRenameDatabaseOptions {
+	options := VRenameDatabaseOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	options.StatePollingTimeout = util.DefaultStatePollingTimeout
+	return options
+}
+
+func (options *VRenameDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(RenameDBCmd, logger)
+	if err != nil {
+		return err
+	}
+
+	if !options.IsEon {
+		return fmt.Errorf("rename database is only supported in Eon mode")
+	}
+	err = util.ValidateCommunalStorageLocation(options.CommunalStorageLocation)
+	if err != nil {
+		return err
+	}
+	err = options.validateCatalogPath()
+	if err != nil {
+		return err
+	}
+
+	if options.NewDBName == "" {
+		return fmt.Errorf("must specify a new database name")
+	}
+	err = util.ValidateDBName(options.NewDBName)
+	if err != nil {
+		return err
+	}
+	// database names are case insensitive
+	if strings.EqualFold(options.NewDBName, options.DBName) {
+		return fmt.Errorf("the new database name %s is the same as the current one", options.NewDBName)
+	}
+	return nil
+}
+
+func (options *VRenameDatabaseOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRenameDatabaseOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VRenameDatabase renames an Eon database. It checks that no database on the
+// communal storage has the new name, stops the database if it is running,
+// renames it in the catalog and the configuration files of every node, moves
+// its metadata in communal storage to the new name, and starts it with the new
+// name. If a node or the metadata fails to be renamed, the renamed nodes are
+// renamed back. On success, options.DBName is the new name.
+func (vcc VClusterCommands) VRenameDatabase(options *VRenameDatabaseOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// the lease is under the metadata of the database, it moves with it
+	lease, err := vcc.takeTopologyLease(&options.DatabaseOptions, RenameDBCmd)
+	if err != nil {
+		return err
+	}
+	defer lease.release()
+
+	// check the new name before the database is stopped
+	err = vcc.runRenameDBPrecheck(options)
+	if err != nil {
+		return fmt.Errorf("fail to rename database pre-checks: %w", err)
+	}
+
+	err = vcc.stopDBForRename(options)
+	if err != nil {
+		return err
+	}
+
+	// produce rename database instructions
+	instructions, nmaRenameDBOp := vcc.produceRenameDBInstructions(options)
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return vcc.revertRenameDB(options, nmaRenameDBOp, fmt.Errorf("fail to rename database: %w", runError))
+	}
+
+	// the database is started from the metadata under its new name
+	nmaMoveMetadataOp, err := makeNMAMoveCommunalMetadataOp(options.Hosts, options.CommunalStorageLocation,
+		options.DBName, options.NewDBName, options.ConfigurationParameters)
+	if err != nil {
+		return vcc.revertRenameDB(options, nmaRenameDBOp, err)
+	}
+	clusterOpEngine = makeClusterOpEngine([]clusterOp{&nmaMoveMetadataOp}, options)
+	runError = clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return vcc.revertRenameDB(options, nmaRenameDBOp,
+			fmt.Errorf("fail to move the communal metadata of database to %s: %w", options.NewDBName, runError))
+	}
+	lease.moveTo(options.NewDBName)
+
+	// start the database with its new name
+	startOptions := VStartDatabaseOptionsFactory()
+	startOptions.DatabaseOptions = options.DatabaseOptions
+	startOptions.DBName = options.NewDBName
+	startOptions.StatePollingTimeout = options.StatePollingTimeout
+	_, err = vcc.VStartDatabase(&startOptions)
+	if err != nil {
+		return fmt.Errorf("database is renamed to %s, but fail to start it: %w", options.NewDBName, err)
+	}
+
+	options.DBName = options.NewDBName
+	return nil
+}
+
+// runRenameDBPrecheck checks that the hosts are reachable, and that the new
+// name is not used by another database on the communal storage
+func (vcc VClusterCommands) runRenameDBPrecheck(options *VRenameDatabaseOptions) error {
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaCheckDBNameOp, err := makeNMACheckCommunalDBNameOp(options.Hosts, options.NewDBName,
+		options.CommunalStorageLocation, options.ConfigurationParameters)
+	if err != nil {
+		return err
+	}
+
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaHealthOp, &nmaCheckDBNameOp}, options)
+	return clusterOpEngine.run(vcc.Log)
+}
+
+// stopDBForRename stops the database if it is running. A database with
+// sandboxes cannot be renamed, as the sandboxes share its communal storage.
+func (vcc VClusterCommands) stopDBForRename(options *VRenameDatabaseOptions) error {
+	vdb := makeVCoordinationDatabase()
+	err := vcc.getVDBFromMainRunningDBContainsSandbox(&vdb, &options.DatabaseOptions)
+	if err != nil {
+		vcc.Log.Info("database is not running, no need to stop it", "details", err.Error())
+		return nil
+	}
+
+	for _, vnode := range vdb.HostNodeMap {
+		if vnode.Sandbox != util.MainClusterSandbox {
+			return fmt.Errorf("cannot rename database %s because node %s is in sandbox %s",
+				options.DBName, vnode.Name, vnode.Sandbox)
+		}
+	}
+
+	stopOptions := VStopDatabaseOptionsFactory()
+	stopOptions.DatabaseOptions = options.DatabaseOptions
+	err = vcc.VStopDatabase(&stopOptions)
+	if err != nil {
+		return fmt.Errorf("fail to stop database before renaming it: %w", err)
+	}
+	return nil
+}
+
+// revertRenameDB renames the nodes that are already renamed back to the
+// original name, and returns the error of the rename with the one of the
+// revert, if any
+func (vcc VClusterCommands) revertRenameDB(options *VRenameDatabaseOptions,
+	nmaRenameDBOp *nmaRenameDatabaseOp, renameErr error) error {
+	revertVDB := nmaRenameDBOp.getRevertVDB()
+	if len(revertVDB.HostNodeMap) == 0 {
+		return renameErr
+	}
+
+	vcc.Log.PrintWarning("Renaming nodes %v back to database %s", maps.Keys(revertVDB.HostNodeMap), options.DBName)
+	nmaRevertOp := makeNMARenameDatabaseOp(options.NewDBName, options.DBName, revertVDB)
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaRevertOp}, options)
+	err := clusterOpEngine.run(vcc.Log)
+	if err != nil {
+		return errors.Join(renameErr, fmt.Errorf("fail to rename nodes back to database %s, "+
+			"they must be renamed manually: %w", options.DBName, err))
+	}
+	return renameErr
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful rename_db:
+//   - Check NMA connectivity
+//   - Get the catalog path of every node
+//   - Rename the database on every node
+func (vcc VClusterCommands) produceRenameDBInstructions(options *VRenameDatabaseOptions) ([]clusterOp,
+	*nmaRenameDatabaseOp) {
+	vdb := makeVCoordinationDatabase()
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaGetNodesInfoOp := makeNMAGetNodesInfoOp(options.Hosts, options.DBName, options.CatalogPrefix,
+		false /* report all errors */, &vdb)
+	nmaRenameDBOp := makeNMARenameDatabaseOp(options.DBName, options.NewDBName, &vdb)
+
+	return []clusterOp{
+		&nmaHealthOp,
+		&nmaGetNodesInfoOp,
+		&nmaRenameDBOp,
+	}, &nmaRenameDBOp
+}
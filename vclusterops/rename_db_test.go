@@ -0,0 +1,115 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/rfc7807"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestVRenameDatabaseOptions(t *testing.T) {
+	logger := vlog.Printer{}
+
+	opt := VRenameDatabaseFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"192.168.1.101"}
+	opt.CatalogPrefix = "/data"
+	opt.IsEon = true
+	opt.CommunalStorageLocation = "s3://bucket/path"
+	opt.NewDBName = "new_db"
+	assert.NoError(t, opt.validateAnalyzeOptions(logger))
+
+	// negative: the new name is missing, illegal, or the same as the current one
+	opt.NewDBName = ""
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "must specify a new database name")
+	opt.NewDBName = "new-db"
+	assert.Error(t, opt.validateParseOptions(logger))
+	opt.NewDBName = strings.ToUpper(testDBName)
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "is the same as the current one")
+
+	// negative: Enterprise database
+	opt.NewDBName = "new_db"
+	opt.IsEon = false
+	assert.ErrorContains(t, opt.validateParseOptions(logger), "only supported in Eon mode")
+}
+
+func TestCheckCommunalDBName(t *testing.T) {
+	const host = "192.168.1.101"
+	op, err := makeNMACheckCommunalDBNameOp([]string{host}, "new_db", "s3://bucket/path", nil)
+	assert.NoError(t, err)
+	assert.Contains(t, op.hostRequestBody, "s3://bucket/path/metadata/new_db/cluster_config.json")
+	checkResult := func(result hostHTTPResult) error {
+		op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: result}
+		return op.processResult(nil)
+	}
+
+	// the name is free when the description file is missing
+	missingFile := rfc7807.New(rfc7807.UndefinedFile)
+	assert.NoError(t, checkResult(hostHTTPResult{status: FAILURE, err: missingFile}))
+
+	// the name is in use when the description file is downloaded
+	err = checkResult(hostHTTPResult{status: SUCCESS, content: `{"std_out": "Download successful"}`})
+	inUseErr := &DBNameInUseError{}
+	assert.ErrorAs(t, err, &inUseErr)
+	assert.Equal(t, "new_db", inUseErr.DBName)
+
+	// other failures are reported
+	err = checkResult(hostHTTPResult{status: FAILURE, err: rfc7807.New(rfc7807.CommunalAccessError)})
+	assert.ErrorContains(t, err, "HTTPS call failed on host 192.168.1.101")
+
+	// the check is sent from a pinned initiator
+	op, err = makeNMACheckCommunalDBNameOp([]string{host, "192.168.1.102"}, "new_db", "s3://bucket/path", nil)
+	assert.NoError(t, err)
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	execContext.initiators = []string{"192.168.1.102"}
+	op.clusterHTTPRequest.RequestCollection = make(map[string]hostHTTPRequest)
+	assert.NoError(t, op.prepare(&execContext))
+	assert.Len(t, op.clusterHTTPRequest.RequestCollection, 1)
+	assert.Contains(t, op.clusterHTTPRequest.RequestCollection, "192.168.1.102")
+}
+
+func TestRenameDBRevert(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Address: "192.168.1.101",
+		CatalogPath: "/data/test_db/v_test_db_node0001_catalog"}
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Address: "192.168.1.102",
+		CatalogPath: "/data/test_db/v_test_db_node0002_catalog"}
+	op := makeNMARenameDatabaseOp(testDBName, "new_db", &vdb)
+
+	// one node is renamed, the other fails
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"catalog_path": "/data/new_db/v_test_db_node0001_catalog"}`},
+		"192.168.1.102": {status: FAILURE, err: rfc7807.New(rfc7807.GenericBootstrapCatalogFailure)},
+	}
+	assert.Error(t, op.processResult(nil))
+
+	// only the renamed node is reverted, from its path after the rename
+	revertVDB := op.getRevertVDB()
+	assert.Len(t, revertVDB.HostNodeMap, 1)
+	assert.Equal(t, "/data/new_db/v_test_db_node0001_catalog", revertVDB.HostNodeMap["192.168.1.101"].CatalogPath)
+	assert.Equal(t, "/data/test_db/v_test_db_node0001_catalog", vdb.HostNodeMap["192.168.1.101"].CatalogPath)
+
+	// the communal metadata is moved to the new name
+	moveOp, err := makeNMAMoveCommunalMetadataOp([]string{"192.168.1.101"}, "s3://bucket/path", testDBName, "new_db", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"source_path":"s3://bucket/path/metadata/testdbname","destination_path":"s3://bucket/path/metadata/new_db"}`,
+		moveOp.hostRequestBody)
+}
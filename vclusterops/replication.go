@@ -18,18 +18,36 @@ package vclusterops
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
 type ReplicationOptions struct {
 	TableOrSchemaName string
-	IncludePattern    string
-	ExcludePattern    string
-	TargetNamespace   string
+	// the wildcard patterns of the objects to replicate, and of the objects
+	// among them not to replicate. An object is replicated if it matches any
+	// include pattern and no exclude pattern.
+	IncludePatterns []string
+	ExcludePatterns []string
+	TargetNamespace string
+	// optional, the objects to replicate under another name in the target
+	// database, from the source schema or table to the target one, e.g.,
+	// "prod" to "prod_copy". Both names must be of the same kind.
+	ObjectRenames map[string]string
+	// optional, replicate the source database as of this epoch, so that several
+	// replication runs copy the same consistent snapshot
+	Epoch *int64
+	// optional, replicate the source database as of the epoch that was current
+	// at this time, e.g., "2024-06-01 12:00:00" or in RFC 3339 format
+	AtTime string
 }
 
+// the layouts that the time of a point-in-time replication can be given in
+var replicationAtTimeLayouts = []string{time.RFC3339, time.DateTime, "2006-01-02 15:04:05.999999", time.DateOnly}
+
 type VReplicationDatabaseOptions struct {
 	/* part 1: basic db info */
 	DatabaseOptions
@@ -37,11 +55,131 @@ type VReplicationDatabaseOptions struct {
 	/* part 2: replication info */
 	TargetDB        DatabaseOptions
 	SourceTLSConfig string
-	SandboxName     string
-	Async           bool
+	// optional, the TLS configuration of the target database that it uses for
+	// the connections of the replication, if it differs from the source one
+	TargetTLSConfig string
+	// Deprecated: use TargetDB.UserName instead
+	TargetUserName string `deprecated:"TargetDB.UserName"`
+	// Deprecated: use TargetDB.Password instead
+	TargetPassword *string `deprecated:"TargetDB.Password"`
+	SandboxName    string
+	Async          bool
+	// optional, the target databases that VReplicateDatabaseToTargets
+	// replicates to, in place of TargetDB
+	TargetDBs []DatabaseOptions
+	// the most target databases that VReplicateDatabaseToTargets replicates
+	// to at the same time, DefaultReplicationTargetConcurrency if <= 0
+	MaxTargetConcurrency int
+	// Allow the target database to be the source database itself, e.g., to copy
+	// objects between namespaces of the same database. By default, replication
+	// fails with a ReplicationTargetIsSourceError in that case.
+	AllowSameCluster bool
+	// Start replication without comparing the UUIDs of the source and target
+	// databases, e.g., when the target does not expose its cluster information
+	// to the target user. Unlike AllowSameCluster, the host lists of the source
+	// and target databases are still required not to overlap.
+	SkipSameDatabaseCheck bool
+	// Identity the source user is mapped to in the target database. The target
+	// user is TargetDB.UserName if set, otherwise the source user is replicated
+	// as the user with the same name in the target database. TargetRole is the
+	// role the target user works under during replication, empty means its
+	// default roles.
+	TargetRole string
+	// Resource pool of the target database that applies the replicated data, so
+	// that large copies do not compete with the target workload in the general
+	// pool. Empty means the default pool of the target user.
+	TargetResourcePool string
+	// Set when the target database authenticates the target user with trust
+	// authentication, so that no password or TLS configuration is needed even
+	// if the target user differs from the source user
+	TargetTrustAuth bool
+	// When synchronous replication fails because EnableConnectCredentialForwarding
+	// is disabled in the source database, enable it and retry the replication
+	FixCredentialForwarding bool
+	// Disable EnableConnectCredentialForwarding again once the retried replication
+	// finishes, only works with FixCredentialForwarding
+	RevertCredentialForwarding bool
+	// Where the transaction ID of an asynchronous replication job is saved, so
+	// a caller that exits before the job finishes can resume monitoring it
+	TransactionStateFile string
+	// Called with the transaction ID of an asynchronous replication job once it is known
+	OnTransactionStarted ReplicationTransactionHandler
+	// optional, called with the progress of an asynchronous replication job on
+	// every poll of its status. When set, VReplicateDatabase waits for the job
+	// to finish, for at most ProgressTimeout seconds, instead of returning as
+	// soon as the job started. A value <= 0 means waiting until it finishes.
+	OnProgress      func(ReplicationProgress)
+	ProgressTimeout int
+	// How long, in seconds, to wait for in-flight DDL and catalog locks on the
+	// objects to replicate to be released before replication starts. With 0,
+	// replication fails right away with a ReplicationBlockedError instead.
+	LockWaitTimeout int
+	// Start replication without checking for DDL and catalog locks on the objects to replicate
+	SkipLockCheck bool
+	// The source snapshot that replication copies data as of, one of the
+	// ReplicationSnapshot values. Empty means ReplicationSnapshotLatestCommitted.
+	SourceSnapshot string
+	// optional, set to the source epoch that replication copies data as of, so
+	// that consistency checks can be anchored to it. It is left untouched if
+	// the source database does not report the epoch.
+	ReplicatedEpoch *int64
+	// How the projections of the replicated tables are handled in the target
+	// database, one of the ReplicationProjections values. Empty means
+	// ReplicationProjectionsReplicate. Other modes are checked to be supported
+	// by the source database before replication starts.
+	ProjectionMode string
+	// set by replication, the objects matched by the replication that are not
+	// replicated, e.g., because of an unsupported type or missing privileges
+	SkippedObjects []ReplicationSkippedObject
+	// Resolve the objects to replicate against the source catalog and return
+	// before replication starts. The objects are kept in MatchedObjects.
+	DryRun bool
+	// By default, replication fails with a ReplicationNoMatchingObjectsError
+	// when its table or schema name or include pattern matches no objects of
+	// the source database. If set, a warning is printed instead.
+	AllowNoMatchingObjects bool
+	// set by replication, the objects of the source database that the table or
+	// schema name and the include and exclude patterns resolve to
+	MatchedObjects []string
+	// How the target host that the source database connects to is picked, one
+	// of the ReplicationTargetHost values. Empty means ReplicationTargetHostFirst.
+	TargetHostStrategy string
 	ReplicationOptions
 }
 
+const credentialForwardingParam = "EnableConnectCredentialForwarding"
+
+type ReplicationSkippedObject = vtypes.ReplicationSkippedObject
+
+// the reasons why replication skips an object, see ReplicationSkippedObject
+const (
+	ReplicationSkipUnsupportedType   = "unsupported_type"
+	ReplicationSkipExcludedByPattern = "excluded_by_pattern"
+	ReplicationSkipPermissionDenied  = "permission_denied"
+)
+
+// the source snapshots that replication can copy data as of
+const (
+	// the data last committed when replication reaches each object
+	ReplicationSnapshotLatestCommitted = "latest_committed"
+	// a consistent snapshot of all objects as of the current epoch when replication starts
+	ReplicationSnapshotCurrentEpoch = "current_epoch"
+)
+
+// the ways replication can handle the projections of the replicated tables
+const (
+	// copy the projections of the source tables as they are
+	ReplicationProjectionsReplicate = "replicate"
+	// copy the table data only, and rebuild the projections in the target database
+	ReplicationProjectionsRebuild = "rebuild"
+)
+
+type (
+	ReplicationTargetIsSourceError    = vtypes.ReplicationTargetIsSourceError
+	ReplicationBlockedError           = vtypes.ReplicationBlockedError
+	ReplicationNoMatchingObjectsError = vtypes.ReplicationNoMatchingObjectsError
+)
+
 func VReplicationDatabaseFactory() VReplicationDatabaseOptions {
 	options := VReplicationDatabaseOptions{}
 	// set default values to the params
@@ -78,13 +216,66 @@ func (options *VReplicationDatabaseOptions) validateExtraOptions() error {
 		return err
 	}
 
-	// need to provide a password or TLSconfig if source and target username are different
-	if options.TargetDB.UserName != options.UserName {
-		if options.TargetDB.Password == nil && options.SourceTLSConfig == "" {
-			return fmt.Errorf("only trust authentication can support username without password or TLSConfig")
+	err = options.TargetDB.validateProxy()
+	if err != nil {
+		return err
+	}
+
+	err = options.TargetDB.validateInitiators()
+	if err != nil {
+		return err
+	}
+
+	err = options.TargetDB.validateTLSVerifyModes()
+	if err != nil {
+		return err
+	}
+
+	err = options.validateTargetCredentials()
+	if err != nil {
+		return err
+	}
+
+	if options.TargetResourcePool != "" {
+		err = util.ValidateName(options.TargetResourcePool, "target resource pool", false)
+		if err != nil {
+			return err
 		}
 	}
 
+	if options.RevertCredentialForwarding && !options.FixCredentialForwarding {
+		return fmt.Errorf("reverting %s requires the option to fix it", credentialForwardingParam)
+	}
+	if !options.Async && (options.TransactionStateFile != "" || options.OnTransactionStarted != nil) {
+		return fmt.Errorf("transaction IDs can only be persisted in asynchronous replication")
+	}
+	switch options.SourceSnapshot {
+	case "", ReplicationSnapshotLatestCommitted, ReplicationSnapshotCurrentEpoch:
+	default:
+		return fmt.Errorf("invalid source snapshot %q, must be %s or %s", options.SourceSnapshot,
+			ReplicationSnapshotLatestCommitted, ReplicationSnapshotCurrentEpoch)
+	}
+	err = options.validatePointInTimeOptions()
+	if err != nil {
+		return err
+	}
+	err = validateReplicationTargetHostStrategy(options.TargetHostStrategy)
+	if err != nil {
+		return err
+	}
+	switch options.ProjectionMode {
+	case "", ReplicationProjectionsReplicate, ReplicationProjectionsRebuild:
+	default:
+		return fmt.Errorf("invalid projection mode %q, must be %s or %s", options.ProjectionMode,
+			ReplicationProjectionsReplicate, ReplicationProjectionsRebuild)
+	}
+	if options.LockWaitTimeout < 0 {
+		return fmt.Errorf("the lock wait timeout must not be negative")
+	}
+	if options.FixCredentialForwarding && options.Async {
+		return fmt.Errorf("fixing %s is only supported in synchronous replication", credentialForwardingParam)
+	}
+
 	if options.SandboxName != "" {
 		err := util.ValidateSandboxName(options.SandboxName)
 		if err != nil {
@@ -95,6 +286,82 @@ func (options *VReplicationDatabaseOptions) validateExtraOptions() error {
 	return nil
 }
 
+// validatePointInTimeOptions checks that replication copies the source
+// database as of at most one point in time
+func (options *VReplicationDatabaseOptions) validatePointInTimeOptions() error {
+	if options.Epoch == nil && options.AtTime == "" {
+		return nil
+	}
+	if options.Epoch != nil && options.AtTime != "" {
+		return fmt.Errorf("cannot replicate as of both an epoch and a time")
+	}
+	if options.SourceSnapshot != "" {
+		return fmt.Errorf("cannot replicate as of an epoch or a time with source snapshot %s", options.SourceSnapshot)
+	}
+	if options.Epoch != nil && *options.Epoch < 0 {
+		return fmt.Errorf("the epoch to replicate as of must not be negative, got %d", *options.Epoch)
+	}
+	if options.AtTime != "" {
+		for _, layout := range replicationAtTimeLayouts {
+			if _, err := time.Parse(layout, options.AtTime); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid time %q to replicate as of, must be like %q or in RFC 3339 format",
+			options.AtTime, time.DateTime)
+	}
+	return nil
+}
+
+// validateTargetCredentials checks that the mapping from the source user to the
+// target user can be authenticated by the target database
+func (options *VReplicationDatabaseOptions) validateTargetCredentials() error {
+	if options.TargetRole != "" {
+		err := util.ValidateName(options.TargetRole, "target role", false)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the source user is replicated as the same user in the target database
+	if options.TargetDB.UserName == "" || options.TargetDB.UserName == options.UserName {
+		return nil
+	}
+
+	// a different target user needs either a password, a TLS configuration, or trust authentication
+	if options.usesForwardedTargetCredentials() {
+		return fmt.Errorf("cannot authenticate to the target database as user %s: "+
+			"provide the target user's password or a source TLS configuration, "+
+			"or set the target trust authentication option if the target database trusts that user",
+			options.TargetDB.UserName)
+	}
+	return nil
+}
+
+// usesForwardedTargetCredentials returns whether the source database authenticates
+// to the target database by forwarding the credentials of the source user, as
+// neither a target password, a TLS configuration, nor trust authentication is set
+func (options *VReplicationDatabaseOptions) usesForwardedTargetCredentials() bool {
+	return options.TargetDB.Password == nil && options.SourceTLSConfig == "" && !options.TargetTrustAuth
+}
+
+func validateReplicationPattern(pattern, kind string) error {
+	if pattern == "" {
+		return fmt.Errorf("the %s patterns must not be empty", kind)
+	}
+	return util.ValidateQualifiedObjectNamePattern(pattern, true)
+}
+
+// joinReplicationPatterns joins the patterns into the comma-separated list
+// that the source database takes as a single pattern
+func joinReplicationPatterns(patterns []string) string {
+	normalized := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		normalized[i] = util.NormalizeObjectNamePattern(pattern)
+	}
+	return strings.Join(normalized, ",")
+}
+
 func (options *VReplicationDatabaseOptions) validateFineGrainedReplicationOptions() error {
 	if options.TableOrSchemaName != "" {
 		err := util.ValidateQualifiedObjectNamePattern(options.TableOrSchemaName, false)
@@ -103,15 +370,15 @@ func (options *VReplicationDatabaseOptions) validateFineGrainedReplicationOption
 		}
 	}
 
-	if options.IncludePattern != "" {
-		err := util.ValidateQualifiedObjectNamePattern(options.IncludePattern, true)
+	for _, pattern := range options.IncludePatterns {
+		err := validateReplicationPattern(pattern, "include")
 		if err != nil {
 			return err
 		}
 	}
 
-	if options.ExcludePattern != "" {
-		err := util.ValidateQualifiedObjectNamePattern(options.ExcludePattern, true)
+	for _, pattern := range options.ExcludePatterns {
+		err := validateReplicationPattern(pattern, "exclude")
 		if err != nil {
 			return err
 		}
@@ -123,10 +390,52 @@ func (options *VReplicationDatabaseOptions) validateFineGrainedReplicationOption
 			return err
 		}
 	}
+	return validateReplicationRenames(options.ObjectRenames)
+}
+
+// validateReplicationRenames checks that each rename maps a schema to a schema
+// or a table to a table, and that no two objects get the same target name
+func validateReplicationRenames(renames map[string]string) error {
+	targets := make(map[string]string, len(renames))
+	for source, target := range renames {
+		sourceParts, err := util.SplitQualifiedObjectName(source)
+		if err != nil {
+			return fmt.Errorf("invalid source object of rename %s: %w", source, err)
+		}
+		targetParts, err := util.SplitQualifiedObjectName(target)
+		if err != nil {
+			return fmt.Errorf("invalid target object of rename %s: %w", source, err)
+		}
+		if len(sourceParts) != len(targetParts) {
+			return fmt.Errorf("cannot rename %s to %s: the source and target objects must both be "+
+				"schemas or both be tables", source, target)
+		}
+		normalizedTarget := util.NormalizeObjectNamePattern(target)
+		if other, found := targets[normalizedTarget]; found {
+			return fmt.Errorf("cannot rename both %s and %s to %s", other, source, target)
+		}
+		targets[normalizedTarget] = source
+	}
 	return nil
 }
 
+// normalizeReplicationRenames converts the names of the renames to the form
+// that the source database matches objects in
+func normalizeReplicationRenames(renames map[string]string) map[string]string {
+	if len(renames) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(renames))
+	for source, target := range renames {
+		normalized[util.NormalizeObjectNamePattern(source)] = util.NormalizeObjectNamePattern(target)
+	}
+	return normalized
+}
+
 func (options *VReplicationDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
+	// the target credentials used to be options of their own
+	options.migrateDeprecatedOptions(options, logger)
+
 	// batch 1: validate required params
 	err := options.validateRequiredOptions(logger)
 	if err != nil {
@@ -162,6 +471,11 @@ func (options *VReplicationDatabaseOptions) validateParseOptions(logger vlog.Pri
 // analyzeOptions will modify some options based on what is chosen
 func (options *VReplicationDatabaseOptions) analyzeOptions() (err error) {
 	if len(options.TargetDB.Hosts) > 0 {
+		// record the ports of host:port entries
+		options.TargetDB.Hosts, err = options.TargetDB.splitHostPorts(options.TargetDB.Hosts)
+		if err != nil {
+			return err
+		}
 		// resolve RawHosts to be IP addresses
 		options.TargetDB.Hosts, err = util.ResolveRawHostsToAddresses(options.TargetDB.Hosts, options.TargetDB.IPv6)
 		if err != nil {
@@ -177,6 +491,14 @@ func (options *VReplicationDatabaseOptions) analyzeOptions() (err error) {
 		}
 		options.Hosts = hostAddresses
 	}
+
+	// a host in both lists means the target is (at least partially) the source
+	// cluster, which is only allowed when replicating within the same cluster
+	overlappingHosts := util.SliceCommon(options.Hosts, options.TargetDB.Hosts)
+	if len(overlappingHosts) > 0 && !options.AllowSameCluster {
+		return fmt.Errorf("source and target host lists overlap on %v, "+
+			"the target database must be on a different cluster", overlappingHosts)
+	}
 	return nil
 }
 
@@ -195,21 +517,62 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 		return 0, err
 	}
 
+	vdb, err := vcc.prepareReplicationSource(options)
+	if err != nil {
+		return 0, err
+	}
+	return vcc.replicateToPreparedTarget(options, &vdb)
+}
+
+// prepareReplicationSource retrieves the nodes of the source database and
+// resolves the objects to replicate, which is done once for all targets
+func (vcc VClusterCommands) prepareReplicationSource(options *VReplicationDatabaseOptions) (VCoordinationDatabase, error) {
 	// retrieve information from the database to accurately determine the state of each node in both the main cluster and a given sandbox
 	vdb := makeVCoordinationDatabase()
-	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, options.SandboxName)
+	err := vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, options.SandboxName)
+	if err != nil {
+		return vdb, err
+	}
+
+	err = vcc.checkReplicationObjects(options, &vdb)
+	return vdb, err
+}
+
+// replicateToPreparedTarget runs the ops of the replication to the target
+// database of the options, once the source database is prepared
+func (vcc VClusterCommands) replicateToPreparedTarget(options *VReplicationDatabaseOptions,
+	vdb *VCoordinationDatabase) (int64, error) {
+	err := vcc.checkTargetIsNotSource(options)
 	if err != nil {
 		return 0, err
 	}
 
+	if options.DryRun {
+		vcc.Log.PrintInfo("Dry run: %d objects would be replicated to database %s",
+			len(options.MatchedObjects), options.TargetDB.DBName)
+		return 0, nil
+	}
+
 	asyncReplicationTransactionID := new(int64)
 	if options.Async {
-		err := vcc.replicateDatabaseAsync(options, &vdb, asyncReplicationTransactionID)
+		err := vcc.replicateDatabaseAsync(options, vdb, asyncReplicationTransactionID)
 		if err != nil {
 			return 0, err
 		}
+		// replication is running, so the transaction ID is returned even if it fails to be persisted
+		err = persistReplicationTransaction(options, *asyncReplicationTransactionID)
+		if err != nil {
+			return *asyncReplicationTransactionID, fmt.Errorf("replication started with transaction ID %d "+
+				"but fail to persist the transaction ID: %w", *asyncReplicationTransactionID, err)
+		}
+		if options.OnProgress != nil {
+			err = vcc.waitForAsyncReplication(options, *asyncReplicationTransactionID)
+			if err != nil {
+				return *asyncReplicationTransactionID, err
+			}
+		}
 	} else {
-		err := vcc.replicateDatabaseSync(options, &vdb)
+		err := vcc.replicateDatabaseSync(options, vdb)
 		if err != nil {
 			return 0, err
 		}
@@ -217,13 +580,112 @@ func (vcc VClusterCommands) VReplicateDatabase(options *VReplicationDatabaseOpti
 	return *asyncReplicationTransactionID, nil
 }
 
+// checkTargetIsNotSource fails when the target database turns out to be the source
+// database, by comparing the UUIDs of both databases. If the target database cannot
+// be queried, e.g., because the target user only exists in the source database,
+// a warning is logged and replication goes on. The check is skipped when the
+// source credentials are forwarded to the target database, as there are no
+// credentials to query the target database with.
+func (vcc VClusterCommands) checkTargetIsNotSource(options *VReplicationDatabaseOptions) error {
+	if options.AllowSameCluster || options.SkipSameDatabaseCheck {
+		return nil
+	}
+	if options.usesForwardedTargetCredentials() {
+		vcc.Log.Info("Skip comparing the source and target databases, as the source credentials are forwarded")
+		return nil
+	}
+
+	sourceVDB := makeVCoordinationDatabase()
+	sourceOptions := options.DatabaseOptions
+	err := vcc.getClusterInfoFromRunningDB(&sourceVDB, &sourceOptions)
+	if err != nil {
+		return fmt.Errorf("cannot verify that the target database is not the source database: %w", err)
+	}
+	targetVDB := makeVCoordinationDatabase()
+	targetOptions := options.TargetDB
+	err = vcc.getClusterInfoFromRunningDB(&targetVDB, &targetOptions)
+	if err != nil {
+		vcc.Log.PrintWarning("Cannot verify that the target database %s is not the source database: %v",
+			options.TargetDB.DBName, err)
+		return nil
+	}
+
+	return checkDifferentDatabases(vcc.Log, &sourceVDB, &targetVDB)
+}
+
+// checkReplicationObjects resolves the objects to replicate against the source
+// catalog in a dry run, or when the replication selects objects by name or
+// pattern, and fails if none of them match
+func (vcc VClusterCommands) checkReplicationObjects(options *VReplicationDatabaseOptions,
+	vdb *VCoordinationDatabase) error {
+	selectsObjects := options.TableOrSchemaName != "" || len(options.IncludePatterns) > 0
+	if !selectsObjects && !options.DryRun {
+		return nil
+	}
+
+	err := options.setUsePasswordAndValidateUsernameIfNeeded(vcc.Log)
+	if err != nil {
+		return err
+	}
+	var objects []string
+	httpsGetReplicationObjectsOp, err := makeHTTPSGetReplicationObjectsOp(options.Hosts, options.usePassword,
+		options.UserName, options.Password, options.SandboxName, &options.ReplicationOptions, vdb, &objects)
+	if err != nil {
+		return err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&httpsGetReplicationObjectsOp}, &options.DatabaseOptions)
+	err = clusterOpEngine.run(vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to resolve the objects to replicate: %w", err)
+	}
+	options.MatchedObjects = objects
+	vcc.Log.Info("Resolved the objects to replicate", "objects", objects)
+
+	if len(objects) > 0 || !selectsObjects {
+		return nil
+	}
+	noMatchErr := &ReplicationNoMatchingObjectsError{
+		TableOrSchemaName: options.TableOrSchemaName,
+		IncludePatterns:   options.IncludePatterns,
+		ExcludePatterns:   options.ExcludePatterns,
+	}
+	if options.AllowNoMatchingObjects {
+		vcc.Log.PrintWarning("%s, replication will copy nothing", noMatchErr.Error())
+		return nil
+	}
+	return noMatchErr
+}
+
+// checkDifferentDatabases fails if the source and target databases have the
+// same UUID. When either UUID is unknown, the communal storage locations of the
+// databases are compared instead, and the check is skipped with a warning if
+// these are unknown too.
+func checkDifferentDatabases(logger vlog.Printer, sourceVDB, targetVDB *VCoordinationDatabase) error {
+	if sourceVDB.UUID != "" && targetVDB.UUID != "" {
+		if sourceVDB.UUID == targetVDB.UUID {
+			return &ReplicationTargetIsSourceError{DBName: targetVDB.Name, DBUUID: targetVDB.UUID}
+		}
+		return nil
+	}
+	if sourceVDB.CommunalStorageLocation != "" && targetVDB.CommunalStorageLocation != "" {
+		if sourceVDB.CommunalStorageLocation == targetVDB.CommunalStorageLocation {
+			return &ReplicationTargetIsSourceError{DBName: targetVDB.Name}
+		}
+		return nil
+	}
+	logger.PrintWarning("Cannot verify that the target database %s is not the source database %s, "+
+		"as the UUID of a database is unknown", targetVDB.Name, sourceVDB.Name)
+	return nil
+}
+
 // Perform asynchronous database replication
 func (vcc VClusterCommands) replicateDatabaseAsync(options *VReplicationDatabaseOptions,
 	vdb *VCoordinationDatabase, asyncReplicationTransactionID *int64) error {
 	/*
 	 * Async replication steps:
 	 * - (on target) Run NMA health check, get a list of existing transaction IDs
-	 * - (on source) Run NMA health check, start asynchronous replication
+	 * - (on source) Run NMA health check, check support for the projection mode, check for DDL and
+	 *   catalog locks on the objects to replicate, start asynchronous replication
 	 * - (on target) Poll NMA for a new transaction ID - this is the ID for the new asynchronous replication operation
 	 *
 	 * Since source and target NMA certs can be different (VER-96992), we have to create multiple VClusterOpEngines to
@@ -283,9 +745,10 @@ func (vcc VClusterCommands) replicateDatabaseAsync(options *VReplicationDatabase
 	if runError != nil {
 		return fmt.Errorf("fail to start replication: %w", runError)
 	}
+	options.reportSkippedObjects(vcc, clusterOpEngine.execContext.skippedObjects)
 
 	// Produce instructions for getting a new transaction ID to identify the async replication operation
-	instructions, err = vcc.produceGetNewTransactionIDInstructions(options, vdb, targetUsePassword,
+	instructions, err = vcc.produceGetNewTransactionIDInstructions(options, targetUsePassword,
 		transactionIDs, asyncReplicationTransactionID)
 	if err != nil {
 		return fmt.Errorf("fail to produce instructions for getting transaction ID, %w", err)
@@ -336,45 +799,67 @@ func (vcc VClusterCommands) produceStartAsyncReplicationInstructions(options *VR
 	vdb *VCoordinationDatabase, targetUsePassword bool) ([]clusterOp, error) {
 	var instructions []clusterOp
 
-	initiatorTargetHost := getInitiator(options.TargetDB.Hosts)
+	initiatorTargetHost, err := vcc.pickReplicationTargetHost(options)
+	if err != nil {
+		return instructions, err
+	}
 
 	nmaHealthOp := makeNMAHealthOp(options.Hosts)
 
 	nmaReplicationData := nmaStartReplicationRequestData{}
 	nmaReplicationData.DBName = options.DBName
-	nmaReplicationData.ExcludePattern = options.ExcludePattern
-	nmaReplicationData.IncludePattern = options.IncludePattern
-	nmaReplicationData.TableOrSchemaName = options.TableOrSchemaName
+	nmaReplicationData.ExcludePattern = joinReplicationPatterns(options.ExcludePatterns)
+	nmaReplicationData.IncludePattern = joinReplicationPatterns(options.IncludePatterns)
+	nmaReplicationData.TableOrSchemaName = util.NormalizeObjectNamePattern(options.TableOrSchemaName)
 	nmaReplicationData.Username = options.UserName
 	nmaReplicationData.Password = options.Password
 	nmaReplicationData.TargetDBName = options.TargetDB.DBName
 	nmaReplicationData.TargetHost = initiatorTargetHost
 	nmaReplicationData.TargetNamespace = options.TargetNamespace
+	nmaReplicationData.ObjectRenames = normalizeReplicationRenames(options.ObjectRenames)
 	nmaReplicationData.TargetUserName = options.TargetDB.UserName
 	nmaReplicationData.TargetPassword = options.TargetDB.Password
+	nmaReplicationData.TargetRole = options.TargetRole
+	nmaReplicationData.TargetPool = options.TargetResourcePool
+	nmaReplicationData.SourceSnapshot = options.SourceSnapshot
+	nmaReplicationData.Epoch = options.Epoch
+	nmaReplicationData.AtTime = options.AtTime
+	nmaReplicationData.ProjectionMode = options.ProjectionMode
 	nmaReplicationData.TLSConfig = options.SourceTLSConfig
+	nmaReplicationData.TargetTLSConfig = options.TargetTLSConfig
 
 	nmaStartReplicationOp, err := makeNMAReplicationStartOp(options.Hosts, options.usePassword, targetUsePassword,
-		&nmaReplicationData, vdb)
+		&nmaReplicationData, options.ReplicatedEpoch, vdb)
 	if err != nil {
 		return instructions, err
 	}
 
-	instructions = append(instructions,
-		&nmaHealthOp,
-		&nmaStartReplicationOp,
-	)
+	instructions = append(instructions, &nmaHealthOp)
+	err = vcc.appendReplicationSupportCheck(options, vdb, &instructions)
+	if err != nil {
+		return instructions, err
+	}
+	if !options.SkipLockCheck {
+		httpsCheckReplicationLocksOp, e := makeHTTPSCheckReplicationLocksOp(options.Hosts, options.usePassword,
+			options.UserName, options.Password, options.SandboxName, &options.ReplicationOptions,
+			options.LockWaitTimeout, vdb)
+		if e != nil {
+			return instructions, e
+		}
+		instructions = append(instructions, &httpsCheckReplicationLocksOp)
+	}
+	instructions = append(instructions, &nmaStartReplicationOp)
 
 	return instructions, nil
 }
 
 func (vcc VClusterCommands) produceGetNewTransactionIDInstructions(options *VReplicationDatabaseOptions,
-	vdb *VCoordinationDatabase, targetUsePassword bool, transactionIDs *[]int64,
+	targetUsePassword bool, transactionIDs *[]int64,
 	asyncReplicationTransactionID *int64) ([]clusterOp, error) {
 	var instructions []clusterOp
 
 	nmaPollReplicationStatusOp, err := makeNMAPollReplicationStatusOp(&options.TargetDB, targetUsePassword,
-		options.SandboxName, vdb, transactionIDs, asyncReplicationTransactionID)
+		transactionIDs, asyncReplicationTransactionID)
 	if err != nil {
 		return instructions, err
 	}
@@ -386,6 +871,37 @@ func (vcc VClusterCommands) produceGetNewTransactionIDInstructions(options *VRep
 	return instructions, nil
 }
 
+// waitForAsyncReplication polls the status of the asynchronous replication job
+// until it finishes, reporting its progress to options.OnProgress
+func (vcc VClusterCommands) waitForAsyncReplication(options *VReplicationDatabaseOptions, transactionID int64) error {
+	nmaReplicationStatusData := nmaReplicationStatusRequestData{}
+	nmaReplicationStatusData.DBName = options.TargetDB.DBName
+	nmaReplicationStatusData.TransactionID = transactionID
+	nmaReplicationStatusData.UserName = options.TargetDB.UserName
+	nmaReplicationStatusData.Password = options.TargetDB.Password
+
+	replicationStatus := ReplicationStatusResponse{}
+	throughput := &replicationThroughput{onProgress: options.OnProgress,
+		stallTimeout: DefaultReplicationStallTimeout * time.Second}
+	nmaPollReplicationProgressOp, err := makeNMAPollReplicationProgressOp(options.TargetDB.Hosts,
+		options.TargetDB.Password != nil, &nmaReplicationStatusData, options.ProgressTimeout, &replicationStatus, throughput)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions for waiting for replication, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add target certs to the engine
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaPollReplicationProgressOp}, &options.TargetDB)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to wait for replication with transaction ID %d: %w", transactionID, runError)
+	}
+	if replicationStatus.Status != replicationStatusCompleted {
+		return fmt.Errorf("replication with transaction ID %d stopped with status %s in op %s",
+			transactionID, replicationStatus.Status, replicationStatus.OpName)
+	}
+	return nil
+}
+
 // Perform synchronous database replication
 func (vcc VClusterCommands) replicateDatabaseSync(options *VReplicationDatabaseOptions,
 	vdb *VCoordinationDatabase) error {
@@ -395,6 +911,27 @@ func (vcc VClusterCommands) replicateDatabaseSync(options *VReplicationDatabaseO
 	 *   - Give the instructions to the VClusterOpEngine to run
 	 */
 
+	runError := vcc.runSyncReplication(options, vdb)
+	if runError != nil && isCredentialForwardingDisabledError(runError) {
+		if options.FixCredentialForwarding {
+			runError = vcc.replicateDatabaseSyncWithCredentialForwarding(options, vdb)
+		} else {
+			runError = fmt.Errorf("target database authentication failed, need to do one of the following things: " +
+				"1. provide tlsconfig or target username with password " +
+				"2. set EnableConnectCredentialForwarding to True in source database using vsql, " +
+				"or enable the credential forwarding remediation option " +
+				"3. configure a Trust Authentication in target database using vsql")
+		}
+	}
+	if runError != nil {
+		return fmt.Errorf("fail to replicate database: %w", runError)
+	}
+
+	return nil
+}
+
+func (vcc VClusterCommands) runSyncReplication(options *VReplicationDatabaseOptions,
+	vdb *VCoordinationDatabase) error {
 	// produce database replication instructions
 	instructions, err := vcc.produceSyncDBReplicationInstructions(options, vdb)
 	if err != nil {
@@ -405,22 +942,76 @@ func (vcc VClusterCommands) replicateDatabaseSync(options *VReplicationDatabaseO
 	clusterOpEngine := makeClusterOpEngine(instructions, &options.DatabaseOptions)
 
 	// give the instructions to the VClusterOpEngine to run
-	runError := clusterOpEngine.run(vcc.Log)
-	if runError != nil {
-		if strings.Contains(runError.Error(), "EnableConnectCredentialForwarding is false") {
-			runError = fmt.Errorf("target database authentication failed, need to do one of the following things: " +
-				"1. provide tlsconfig or target username with password " +
-				"2. set EnableConnectCredentialForwarding to True in source database using vsql " +
-				"3. configure a Trust Authentication in target database using vsql")
+	err = clusterOpEngine.run(vcc.Log)
+	if err != nil {
+		return err
+	}
+	options.reportSkippedObjects(vcc, clusterOpEngine.execContext.skippedObjects)
+	return nil
+}
+
+// reportSkippedObjects keeps and displays the objects that a replication did not copy
+func (options *VReplicationDatabaseOptions) reportSkippedObjects(vcc VClusterCommands,
+	skippedObjects []ReplicationSkippedObject) {
+	options.SkippedObjects = skippedObjects
+	if len(skippedObjects) == 0 {
+		return
+	}
+	names := make([]string, 0, len(skippedObjects))
+	for _, object := range skippedObjects {
+		names = append(names, fmt.Sprintf("%s %s (%s)", object.Type, object.Name, object.Reason))
+	}
+	vcc.DisplayWarning("%d object(s) are not replicated: %s", len(skippedObjects), strings.Join(names, ", "))
+}
+
+func isCredentialForwardingDisabledError(err error) bool {
+	return strings.Contains(err.Error(), credentialForwardingParam+" is false")
+}
+
+// replicateDatabaseSyncWithCredentialForwarding enables credential forwarding in the
+// source database, retries the replication, and disables credential forwarding
+// afterwards if RevertCredentialForwarding is set
+func (vcc VClusterCommands) replicateDatabaseSyncWithCredentialForwarding(options *VReplicationDatabaseOptions,
+	vdb *VCoordinationDatabase) error {
+	vcc.Log.PrintInfo("Enabling %s in the source database to authenticate to the target database", credentialForwardingParam)
+	err := vcc.setCredentialForwarding(options, true)
+	if err != nil {
+		return fmt.Errorf("fail to enable %s in the source database: %w", credentialForwardingParam, err)
+	}
+
+	runError := vcc.runSyncReplication(options, vdb)
+
+	if options.RevertCredentialForwarding {
+		vcc.Log.PrintInfo("Disabling %s in the source database", credentialForwardingParam)
+		err = vcc.setCredentialForwarding(options, false)
+		if err != nil {
+			// replication result matters more than the revert, so only warn about it
+			vcc.Log.PrintWarning("fail to disable %s in the source database, please disable it using vsql, details: %v",
+				credentialForwardingParam, err)
 		}
-		return fmt.Errorf("fail to replicate database: %w", runError)
 	}
 
-	return nil
+	return runError
+}
+
+func (vcc VClusterCommands) setCredentialForwarding(options *VReplicationDatabaseOptions, enable bool) error {
+	setConfigOptions := VSetConfigurationParameterOptionsFactory()
+	setConfigOptions.DatabaseOptions = options.DatabaseOptions
+	// hosts are already resolved
+	setConfigOptions.RawHosts = options.Hosts
+	setConfigOptions.Sandbox = options.SandboxName
+	setConfigOptions.ConfigParameter = credentialForwardingParam
+	setConfigOptions.Value = "0"
+	if enable {
+		setConfigOptions.Value = "1"
+	}
+	return vcc.VSetConfigurationParameters(&setConfigOptions)
 }
 
 // The generated instructions will later perform the following operations necessary for synchronous replication:
 //   - Disallow multiple namespaces
+//   - Check that the source database supports the projection mode, unless it is the default
+//   - Check for DDL and catalog locks on the objects to replicate, unless skipped
 //   - Replicate database (synchronous)
 func (vcc VClusterCommands) produceSyncDBReplicationInstructions(options *VReplicationDatabaseOptions,
 	vdb *VCoordinationDatabase) ([]clusterOp, error) {
@@ -446,7 +1037,10 @@ func (vcc VClusterCommands) produceSyncDBReplicationInstructions(options *VRepli
 		vcc.Log.Info("Current target username", "username", options.TargetDB.UserName)
 	}
 
-	initiatorTargetHost := getInitiator(options.TargetDB.Hosts)
+	initiatorTargetHost, err := vcc.pickReplicationTargetHost(options)
+	if err != nil {
+		return instructions, err
+	}
 
 	httpsDisallowMultipleNamespacesOp, err := makeHTTPSDisallowMultipleNamespacesOp(options.Hosts,
 		options.usePassword, options.UserName, options.Password, options.SandboxName, vdb)
@@ -456,15 +1050,50 @@ func (vcc VClusterCommands) produceSyncDBReplicationInstructions(options *VRepli
 
 	httpsStartReplicationOp, err := makeHTTPSStartReplicationOp(options.DBName, options.Hosts, options.usePassword,
 		options.UserName, options.Password, targetUsePassword, &options.TargetDB, initiatorTargetHost,
-		options.SourceTLSConfig, options.SandboxName, vdb)
+		options.TargetRole, options.TargetResourcePool, options.SourceTLSConfig, options.SandboxName, vdb)
 	if err != nil {
 		return instructions, err
 	}
-
-	instructions = append(instructions,
-		&httpsDisallowMultipleNamespacesOp,
-		&httpsStartReplicationOp,
-	)
+	httpsStartReplicationOp.targetTLSConfig = options.TargetTLSConfig
+	httpsStartReplicationOp.sourceSnapshot = options.SourceSnapshot
+	httpsStartReplicationOp.epoch = options.Epoch
+	httpsStartReplicationOp.atTime = options.AtTime
+	httpsStartReplicationOp.objectRenames = normalizeReplicationRenames(options.ObjectRenames)
+	httpsStartReplicationOp.replicatedEpoch = options.ReplicatedEpoch
+	httpsStartReplicationOp.projectionMode = options.ProjectionMode
+
+	instructions = append(instructions, &httpsDisallowMultipleNamespacesOp)
+	err = vcc.appendReplicationSupportCheck(options, vdb, &instructions)
+	if err != nil {
+		return instructions, err
+	}
+	if !options.SkipLockCheck {
+		httpsCheckReplicationLocksOp, e := makeHTTPSCheckReplicationLocksOp(options.Hosts, options.usePassword,
+			options.UserName, options.Password, options.SandboxName, &options.ReplicationOptions,
+			options.LockWaitTimeout, vdb)
+		if e != nil {
+			return instructions, e
+		}
+		instructions = append(instructions, &httpsCheckReplicationLocksOp)
+	}
+	instructions = append(instructions, &httpsStartReplicationOp)
 
 	return instructions, nil
 }
+
+// appendReplicationSupportCheck appends an op that checks that the source
+// database supports the projection mode, which is skipped for the default mode
+// that every version supports
+func (vcc VClusterCommands) appendReplicationSupportCheck(options *VReplicationDatabaseOptions,
+	vdb *VCoordinationDatabase, instructions *[]clusterOp) error {
+	if options.ProjectionMode == "" || options.ProjectionMode == ReplicationProjectionsReplicate {
+		return nil
+	}
+	httpsCheckReplicationSupportOp, err := makeHTTPSCheckReplicationSupportOp(options.Hosts, options.usePassword,
+		options.UserName, options.Password, options.SandboxName, options.ProjectionMode, vdb)
+	if err != nil {
+		return err
+	}
+	*instructions = append(*instructions, &httpsCheckReplicationSupportOp)
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+const (
+	// the status of a replication job that was canceled
+	replicationStatusCanceled = "canceled"
+	// the default time in seconds to wait for a canceled replication job to stop
+	DefaultReplicationCancelTimeout = 300
+)
+
+type VReplicationCancelOptions struct {
+	VReplicationStatusDatabaseOptions
+	// Timeout in seconds to wait for the canceled job to stop,
+	// a value <= 0 means waiting until the job stops
+	PollingTimeout int
+}
+
+func VReplicationCancelFactory() VReplicationCancelOptions {
+	options := VReplicationCancelOptions{}
+	options.VReplicationStatusDatabaseOptions = VReplicationStatusFactory()
+	options.PollingTimeout = DefaultReplicationCancelTimeout
+	return options
+}
+
+func (options *VReplicationCancelOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	err := options.VReplicationStatusDatabaseOptions.validateAnalyzeOptions(logger)
+	if err != nil {
+		return err
+	}
+	// canceling a job needs more than the monitoring privileges
+	return options.TargetDB.validateMonitoringMode(ReplicationCancelCmd)
+}
+
+// VReplicationCancel cancels an asynchronous replication job, identified by its
+// transaction ID, through the NMA of the target database, and waits for the
+// job to stop. It returns the final status of the job. A job that finished
+// before it could be canceled is returned as a status with an error.
+func (vcc VClusterCommands) VReplicationCancel(options *VReplicationCancelOptions) (*ReplicationStatusResponse, error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	// produce replication cancel instructions
+	replicationStatus := ReplicationStatusResponse{}
+	instructions, err := vcc.produceReplicationCancelInstructions(options, &replicationStatus)
+	if err != nil {
+		return nil, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := makeClusterOpEngine(instructions, &options.TargetDB)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return nil, fmt.Errorf("fail to cancel replication: %w", runError)
+	}
+
+	if replicationStatus.Status != replicationStatusCanceled {
+		return &replicationStatus, fmt.Errorf("replication with transaction ID %d %s in op %s before it was canceled",
+			options.TransactionID, replicationStatus.Status, replicationStatus.OpName)
+	}
+	vcc.Log.PrintInfo("Canceled replication with transaction ID %d", options.TransactionID)
+	return &replicationStatus, nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful replication cancel
+//   - Check NMA connectivity
+//   - Cancel the replication job, and poll its status until it stops
+func (vcc VClusterCommands) produceReplicationCancelInstructions(options *VReplicationCancelOptions,
+	replicationStatus *ReplicationStatusResponse) ([]clusterOp, error) {
+	var instructions []clusterOp
+
+	// verify the username for connecting to the target database
+	targetUsePassword := false
+	if options.TargetDB.Password != nil {
+		targetUsePassword = true
+		if options.TargetDB.UserName == "" {
+			username, e := util.GetCurrentUsername()
+			if e != nil {
+				return instructions, e
+			}
+			options.TargetDB.UserName = username
+		}
+		vcc.Log.Info("Current target username", "username", options.TargetDB.UserName)
+	}
+
+	nmaHealthOp := makeNMAHealthOp(options.TargetDB.Hosts)
+
+	cancelData := nmaReplicationCancelRequestData{}
+	cancelData.DBName = options.TargetDB.DBName
+	cancelData.TransactionID = options.TransactionID
+	cancelData.UserName = options.TargetDB.UserName
+	cancelData.Password = options.TargetDB.Password
+	nmaReplicationCancelOp, err := makeNMAReplicationCancelOp(options.TargetDB.Hosts, targetUsePassword, &cancelData,
+		options.PollingTimeout, replicationStatus)
+	if err != nil {
+		return instructions, err
+	}
+
+	instructions = append(instructions,
+		&nmaHealthOp,
+		&nmaReplicationCancelOp,
+	)
+
+	return instructions, nil
+}
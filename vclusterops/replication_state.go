@@ -0,0 +1,171 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const stateFilePerm = 0600
+
+// replicationStateMu serializes the updates of the state files, e.g., when
+// the jobs of several target databases are started at the same time
+var replicationStateMu sync.Mutex
+
+// ReplicationTransaction identifies an asynchronous replication job, so that it
+// can still be monitored after the process that started it exits
+type ReplicationTransaction struct {
+	TransactionID int64    `json:"txn_id"`
+	SourceDBName  string   `json:"source_db_name"`
+	SandboxName   string   `json:"sandbox,omitempty"`
+	TargetDBName  string   `json:"target_db_name"`
+	TargetHosts   []string `json:"target_hosts"`
+	// time when the transaction ID was retrieved, in time.UnixDate format
+	StartTime string `json:"start_time"`
+}
+
+// ReplicationTransactionHandler is called once the transaction ID of an
+// asynchronous replication job is known
+type ReplicationTransactionHandler func(txn ReplicationTransaction) error
+
+func makeReplicationTransaction(options *VReplicationDatabaseOptions, transactionID int64) ReplicationTransaction {
+	return ReplicationTransaction{
+		TransactionID: transactionID,
+		SourceDBName:  options.DBName,
+		SandboxName:   options.SandboxName,
+		TargetDBName:  options.TargetDB.DBName,
+		TargetHosts:   slices.Clone(options.TargetDB.Hosts),
+		StartTime:     time.Now().Format(time.UnixDate),
+	}
+}
+
+// PollOptions returns the options to wait for the replication job to finish.
+// The target database credentials still need to be set by the caller.
+func (txn *ReplicationTransaction) PollOptions() VPollReplicationStatusOptions {
+	options := VPollReplicationStatusFactory()
+	options.TargetDB.DBName = txn.TargetDBName
+	options.TargetDB.Hosts = slices.Clone(txn.TargetHosts)
+	options.TransactionID = txn.TransactionID
+	return options
+}
+
+// ReadReplicationTransactions returns the replication transactions saved in a
+// state file. A state file that does not exist has no transactions.
+func ReadReplicationTransactions(stateFile string) ([]ReplicationTransaction, error) {
+	var txns []ReplicationTransaction
+	fileBytes, err := os.ReadFile(stateFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return txns, nil
+		}
+		return txns, fmt.Errorf("fail to read replication state file %s, details: %w", stateFile, err)
+	}
+	err = json.Unmarshal(fileBytes, &txns)
+	if err != nil {
+		return txns, fmt.Errorf("fail to parse replication state file %s, details: %w", stateFile, err)
+	}
+	return txns, nil
+}
+
+// SaveReplicationTransaction adds a replication transaction to a state file,
+// replacing the saved one with the same target database and transaction ID
+func SaveReplicationTransaction(stateFile string, txn *ReplicationTransaction) error {
+	replicationStateMu.Lock()
+	defer replicationStateMu.Unlock()
+	txns, err := ReadReplicationTransactions(stateFile)
+	if err != nil {
+		return err
+	}
+	txns = slices.DeleteFunc(txns, func(saved ReplicationTransaction) bool {
+		return saved.TransactionID == txn.TransactionID && saved.TargetDBName == txn.TargetDBName
+	})
+	txns = append(txns, *txn)
+	return writeReplicationTransactions(stateFile, txns)
+}
+
+// RemoveReplicationTransaction removes the replication transactions of a target
+// database from a state file, e.g., once the replication job has finished
+func RemoveReplicationTransaction(stateFile, targetDBName string, transactionID int64) error {
+	replicationStateMu.Lock()
+	defer replicationStateMu.Unlock()
+	txns, err := ReadReplicationTransactions(stateFile)
+	if err != nil {
+		return err
+	}
+	txns = slices.DeleteFunc(txns, func(saved ReplicationTransaction) bool {
+		return saved.TransactionID == transactionID && saved.TargetDBName == targetDBName
+	})
+	return writeReplicationTransactions(stateFile, txns)
+}
+
+// writeReplicationTransactions writes the state file through a temporary file,
+// so a crash while writing does not lose the transactions saved before
+func writeReplicationTransactions(stateFile string, txns []ReplicationTransaction) error {
+	fileBytes, err := json.MarshalIndent(txns, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal replication transactions, details: %w", err)
+	}
+	return writeStateFile(stateFile, "replication", fileBytes)
+}
+
+// writeStateFile replaces a state file of a command with a temporary file, so
+// a crash while writing does not lose the state saved before
+func writeStateFile(stateFile, command string, fileBytes []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(stateFile), filepath.Base(stateFile)+".tmp")
+	if err != nil {
+		return fmt.Errorf("fail to create %s state file %s, details: %w", command, stateFile, err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(fileBytes)
+	if err == nil {
+		err = tmpFile.Chmod(stateFilePerm)
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("fail to write %s state file %s, details: %w", command, stateFile, err)
+	}
+	err = os.Rename(tmpFile.Name(), stateFile)
+	if err != nil {
+		return fmt.Errorf("fail to write %s state file %s, details: %w", command, stateFile, err)
+	}
+	return nil
+}
+
+// persistReplicationTransaction saves a started asynchronous replication job to
+// the state file and passes it to the handler, whichever are set in the options
+func persistReplicationTransaction(options *VReplicationDatabaseOptions, transactionID int64) error {
+	txn := makeReplicationTransaction(options, transactionID)
+	if options.TransactionStateFile != "" {
+		err := SaveReplicationTransaction(options.TransactionStateFile, &txn)
+		if err != nil {
+			return err
+		}
+	}
+	if options.OnTransactionStarted != nil {
+		return options.OnTransactionStarted(txn)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicationStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "replication_state.json")
+
+	// a state file that does not exist has no transactions
+	txns, err := ReadReplicationTransactions(stateFile)
+	assert.NoError(t, err)
+	assert.Empty(t, txns)
+
+	options := VReplicationDatabaseFactory()
+	options.DBName = "source_db"
+	options.TargetDB.DBName = "target_db"
+	options.TargetDB.Hosts = []string{"192.168.1.201", "192.168.1.202"}
+	options.TransactionStateFile = stateFile
+	var handledTxn ReplicationTransaction
+	options.OnTransactionStarted = func(txn ReplicationTransaction) error {
+		handledTxn = txn
+		return nil
+	}
+
+	err = persistReplicationTransaction(&options, 101)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(101), handledTxn.TransactionID)
+	// saving the same transaction again does not duplicate it
+	err = persistReplicationTransaction(&options, 101)
+	assert.NoError(t, err)
+	err = persistReplicationTransaction(&options, 102)
+	assert.NoError(t, err)
+
+	txns, err = ReadReplicationTransactions(stateFile)
+	assert.NoError(t, err)
+	assert.Len(t, txns, 2)
+	assert.Equal(t, "source_db", txns[0].SourceDBName)
+
+	// resume monitoring from a saved transaction
+	pollOptions := txns[1].PollOptions()
+	assert.Equal(t, int64(102), pollOptions.TransactionID)
+	assert.Equal(t, "target_db", pollOptions.TargetDB.DBName)
+	assert.Equal(t, options.TargetDB.Hosts, pollOptions.TargetDB.Hosts)
+
+	err = RemoveReplicationTransaction(stateFile, "target_db", 101)
+	assert.NoError(t, err)
+	txns, err = ReadReplicationTransactions(stateFile)
+	assert.NoError(t, err)
+	assert.Len(t, txns, 1)
+	assert.Equal(t, int64(102), txns[0].TransactionID)
+}
@@ -22,42 +22,26 @@ import (
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const (
+	// the last op of a replication job, see ReplicationStatusResponse.OpName
+	replicationLastOpName = "load_snapshot"
+	// the op that follows the last op when the projections are rebuilt
+	replicationRebuildOpName   = "rebuild_projections"
+	replicationStatusCompleted = "completed"
+	replicationStatusFailed    = "failed"
 )
 
 type VReplicationStatusDatabaseOptions struct {
+	// set TargetDB.ConnectionMetrics to tell the networking issues of the
+	// target database apart from the failures of the replication
 	TargetDB      DatabaseOptions
 	TransactionID int64
 }
 
-type ReplicationStatusResponse struct {
-	// Time replication was started
-	StartTime string `json:"start_time"`
-
-	// End time, if replication has completed
-	EndTime string `json:"end_time"`
-
-	// Current replication operation name. Possible values in order:
-	// - 'load_snapshot_prep'
-	// - 'data_transfer' - optional if source and target communal storage
-	//    are the same
-	// - 'load_snapshot' - replication is complete if this op has a
-	//    status of 'completed'
-	OpName string `json:"op_name"`
-
-	// Current replication operation status. Possible values:
-	//   'started', 'failed', 'completed'
-	Status string `json:"status"`
-
-	// Node the current replication operation is on
-	NodeName string `json:"node_name"`
-
-	// Number of bytes transferred as part of replication
-	SentBytes int64 `json:"sent_bytes"`
-
-	// Total number of bytes to be transferred as part of replication
-	TotalBytes    int64 `json:"total_bytes"`
-	TransactionID int64 `json:"txn_id"`
-}
+type ReplicationStatusResponse = vtypes.ReplicationStatusResponse
 
 func VReplicationStatusFactory() VReplicationStatusDatabaseOptions {
 	options := VReplicationStatusDatabaseOptions{}
@@ -65,15 +49,35 @@ func VReplicationStatusFactory() VReplicationStatusDatabaseOptions {
 }
 
 func (options *VReplicationStatusDatabaseOptions) validateRequiredOptions(_ vlog.Printer) error {
+	err := options.validateTargetOptions()
+	if err != nil {
+		return err
+	}
+
+	if options.TransactionID <= 0 {
+		return fmt.Errorf("must specify a valid transaction ID")
+	}
+
+	return nil
+}
+
+// validateTargetOptions validates the options of the target database whose
+// replication jobs are monitored
+func (options *VReplicationStatusDatabaseOptions) validateTargetOptions() error {
 	if len(options.TargetDB.Hosts) == 0 {
 		return fmt.Errorf("must specify a target host or target host list")
 	}
 
+	err := options.TargetDB.validateMonitoringMode(ReplicationStatusCmd)
+	if err != nil {
+		return err
+	}
+
 	// validate target database
 	if options.TargetDB.DBName == "" {
 		return fmt.Errorf("must specify a target database name")
 	}
-	err := util.ValidateDBName(options.TargetDB.DBName)
+	err = util.ValidateDBName(options.TargetDB.DBName)
 	if err != nil {
 		return err
 	}
@@ -83,11 +87,7 @@ func (options *VReplicationStatusDatabaseOptions) validateRequiredOptions(_ vlog
 		return fmt.Errorf("must specify a target password")
 	}
 
-	if options.TransactionID <= 0 {
-		return fmt.Errorf("must specify a valid transaction ID")
-	}
-
-	return nil
+	return options.TargetDB.validateProxy()
 }
 
 func (options *VReplicationStatusDatabaseOptions) validateParseOptions(logger vlog.Printer) error {
@@ -103,6 +103,11 @@ func (options *VReplicationStatusDatabaseOptions) validateParseOptions(logger vl
 // analyzeOptions will modify some options based on what is chosen
 func (options *VReplicationStatusDatabaseOptions) analyzeOptions() (err error) {
 	if len(options.TargetDB.Hosts) > 0 {
+		// record the ports of host:port entries
+		options.TargetDB.Hosts, err = options.TargetDB.splitHostPorts(options.TargetDB.Hosts)
+		if err != nil {
+			return err
+		}
 		// resolve RawHosts to be IP addresses
 		options.TargetDB.Hosts, err = util.ResolveRawHostsToAddresses(options.TargetDB.Hosts, options.TargetDB.IPv6)
 		if err != nil {
@@ -211,7 +216,8 @@ func getFinalReplicationStatus(replicationStatus []ReplicationStatusResponse) *R
 	opOrder := make(map[string]int)
 	opOrder["load_snapshot_prep"] = 0
 	opOrder["data_transfer"] = 1
-	opOrder["load_snapshot"] = 2
+	opOrder[replicationLastOpName] = 2
+	opOrder[replicationRebuildOpName] = 3
 
 	// Sort statuses by start time, node name, then op name. This lets us search chronologically through the statuses
 	// to find the first failure or in-progress op if there is one
@@ -234,6 +240,7 @@ func getFinalReplicationStatus(replicationStatus []ReplicationStatusResponse) *R
 	finalReplicationStatus := ReplicationStatusResponse{}
 	finalReplicationStatus.TransactionID = firstOp.TransactionID
 	finalReplicationStatus.StartTime = firstOp.StartTime
+	finalReplicationStatus.ProjectionMode = firstOp.ProjectionMode
 
 	// Get the rest of the status info from the current op (the last op in the sorted list)
 	currentOp := replicationStatus[len(replicationStatus)-1]
@@ -242,8 +249,11 @@ func getFinalReplicationStatus(replicationStatus []ReplicationStatusResponse) *R
 	finalReplicationStatus.EndTime = currentOp.EndTime
 	finalReplicationStatus.OpName = currentOp.OpName
 	finalReplicationStatus.SentBytes = currentOp.SentBytes
+	finalReplicationStatus.SentRows = currentOp.SentRows
 	finalReplicationStatus.TotalBytes = currentOp.TotalBytes
 	finalReplicationStatus.NodeName = currentOp.NodeName
+	finalReplicationStatus.RebuiltProjections = currentOp.RebuiltProjections
+	finalReplicationStatus.TotalProjections = currentOp.TotalProjections
 
 	return &finalReplicationStatus
 }
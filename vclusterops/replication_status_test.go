@@ -16,9 +16,12 @@
 package vclusterops
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 const (
@@ -225,3 +228,270 @@ func TestGetFinalReplicationStatus(t *testing.T) {
 	actualStatus = getFinalReplicationStatus(replicationStatus)
 	assert.Equal(t, expectedStatus, *actualStatus)
 }
+
+func TestPollReplicationProgressFailover(t *testing.T) {
+	hosts := []string{"192.168.1.201", "192.168.1.202"}
+	replicationStatus := ReplicationStatusResponse{}
+	op, err := makeNMAPollReplicationProgressOp(hosts, false, &nmaReplicationStatusRequestData{TransactionID: 1},
+		0, &replicationStatus, nil)
+	assert.NoError(t, err)
+	op.hostRequests = map[string]hostHTTPRequest{hosts[0]: {}, hosts[1]: {}}
+	op.pollHost(hosts[0])
+
+	// the polled host goes down, fail over to the other host
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {host: hosts[0], status: EOFEXCEPTION, err: errors.New("EOF")},
+	}
+	stop, err := op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Contains(t, op.clusterHTTPRequest.RequestCollection, hosts[1])
+	assert.Len(t, op.clusterHTTPRequest.RequestCollection, 1)
+
+	// the replication is still in progress
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[1]: {host: hosts[1], status: SUCCESS, statusCode: SuccessCode,
+			content: `[{"op_name": "data_transfer", "status": "started", "txn_id": 1}]`},
+	}
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+	assert.Equal(t, dataTransferOp, replicationStatus.OpName)
+
+	// the replication completes
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[1]: {host: hosts[1], status: SUCCESS, statusCode: SuccessCode,
+			content: `[{"op_name": "load_snapshot", "status": "completed", "txn_id": 1}]`},
+	}
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.True(t, stop)
+
+	// negative: no target host responds
+	for _, host := range []string{hosts[1], hosts[0]} {
+		op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+			host: {host: host, status: EOFEXCEPTION, err: errors.New("EOF")},
+		}
+		stop, err = op.shouldStopPolling()
+	}
+	assert.True(t, stop)
+	assert.ErrorContains(t, err, "none of the target hosts responded")
+}
+
+func TestGetFinalReplicationStatusRebuild(t *testing.T) {
+	withRebuild := func(status ReplicationStatusResponse) ReplicationStatusResponse {
+		status.ProjectionMode = ReplicationProjectionsRebuild
+		return status
+	}
+	loadSnapshotCompleted := withRebuild(node1LoadSnapshotCompleted)
+	rebuildStarted := ReplicationStatusResponse{
+		OpName:             replicationRebuildOpName,
+		Status:             startedStatus,
+		NodeName:           node1,
+		StartTime:          "Mon Sep 23 16:08:11 EDT 2024",
+		TransactionID:      transactionID,
+		ProjectionMode:     ReplicationProjectionsRebuild,
+		RebuiltProjections: 3,
+		TotalProjections:   10,
+	}
+
+	// the job is not finished once the snapshot is loaded, as the projections are still rebuilt
+	assert.False(t, isReplicationFinished(&loadSnapshotCompleted))
+	assert.True(t, isReplicationFinished(&node1LoadSnapshotCompleted))
+
+	replicationStatus := []ReplicationStatusResponse{withRebuild(node1LoadSnapshotPrep), withRebuild(node1DataTransfer),
+		loadSnapshotCompleted, rebuildStarted}
+	actualStatus := getFinalReplicationStatus(replicationStatus)
+	assert.Equal(t, replicationRebuildOpName, actualStatus.OpName)
+	assert.Equal(t, ReplicationProjectionsRebuild, actualStatus.ProjectionMode)
+	assert.Equal(t, int64(3), actualStatus.RebuiltProjections)
+	assert.Equal(t, int64(10), actualStatus.TotalProjections)
+	assert.False(t, isReplicationFinished(actualStatus))
+
+	rebuildStarted.Status = completedStatus
+	rebuildStarted.RebuiltProjections = 10
+	assert.True(t, isReplicationFinished(&rebuildStarted))
+}
+
+func TestReplicationThroughput(t *testing.T) {
+	throughput := replicationThroughput{stallTimeout: time.Minute}
+	start := time.Now()
+
+	// no throughput for the first poll
+	status := &ReplicationStatusResponse{OpName: dataTransferOp, SentBytes: 100, SentRows: 10}
+	progress := throughput.update(status, start)
+	assert.Zero(t, progress.Interval)
+	assert.Zero(t, progress.BytesPerSecond)
+	assert.False(t, progress.Stalled)
+
+	assert.Equal(t, float64(-1), progress.Percent)
+	assert.Zero(t, progress.Elapsed)
+
+	status = &ReplicationStatusResponse{OpName: dataTransferOp, SentBytes: 1100, SentRows: 30, TotalBytes: 4400}
+	progress = throughput.update(status, start.Add(10*time.Second))
+	assert.Equal(t, 10*time.Second, progress.Interval)
+	assert.Equal(t, float64(25), progress.Percent)
+	assert.Equal(t, 10*time.Second, progress.Elapsed)
+	assert.Equal(t, int64(1000), progress.IntervalBytes)
+	assert.Equal(t, int64(20), progress.IntervalRows)
+	assert.Equal(t, float64(100), progress.BytesPerSecond)
+	assert.Equal(t, float64(2), progress.RowsPerSecond)
+
+	// no progress, but not for the stall timeout yet
+	progress = throughput.update(status, start.Add(40*time.Second))
+	assert.Zero(t, progress.BytesPerSecond)
+	assert.Equal(t, 30*time.Second, progress.StalledFor)
+	assert.False(t, progress.Stalled)
+
+	progress = throughput.update(status, start.Add(70*time.Second))
+	assert.True(t, progress.Stalled)
+	assert.Equal(t, time.Minute, progress.StalledFor)
+
+	// the counters restart with a new op, which is progress
+	status = &ReplicationStatusResponse{OpName: loadSnapshotOp, SentBytes: 50}
+	progress = throughput.update(status, start.Add(80*time.Second))
+	assert.Zero(t, progress.IntervalBytes)
+	assert.False(t, progress.Stalled)
+
+	// the percent of a rebuild is from the rebuilt projections, and the
+	// elapsed time from the start time of the job
+	rebuild := &ReplicationStatusResponse{OpName: replicationRebuildOpName, RebuiltProjections: 3, TotalProjections: 4,
+		StartTime: start.Add(-time.Minute).Format(time.UnixDate)}
+	progress = (&replicationThroughput{}).update(rebuild, start)
+	assert.Equal(t, float64(75), progress.Percent)
+	assert.Equal(t, time.Minute, progress.Elapsed.Round(time.Minute))
+
+	// no stall detection without a timeout
+	throughput.stallTimeout = 0
+	progress = throughput.update(status, start.Add(time.Hour))
+	assert.False(t, progress.Stalled)
+}
+
+func TestPollReplicationProgressFailOnStall(t *testing.T) {
+	hosts := []string{"192.168.1.201"}
+	replicationStatus := ReplicationStatusResponse{}
+	stalled := &ReplicationStatusResponse{OpName: dataTransferOp, Status: "started", SentBytes: 100, TransactionID: 1}
+	throughput := &replicationThroughput{stallTimeout: time.Minute, failOnStall: true,
+		last: stalled, lastTime: time.Now(), lastProgress: time.Now().Add(-2 * time.Minute)}
+	op, err := makeNMAPollReplicationProgressOp(hosts, false, &nmaReplicationStatusRequestData{TransactionID: 1},
+		0, &replicationStatus, throughput)
+	assert.NoError(t, err)
+	op.hostRequests = map[string]hostHTTPRequest{hosts[0]: {}}
+	op.pollHost(hosts[0])
+
+	// the job made no progress for the stall timeout, polling stops with the
+	// host to collect the diagnostics from
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {host: hosts[0], status: SUCCESS, statusCode: SuccessCode,
+			content: `[{"op_name": "data_transfer", "status": "started", "sent_bytes": 100, "txn_id": 1}]`},
+	}
+	stop, err := op.shouldStopPolling()
+	assert.True(t, stop)
+	stalledErr := &ReplicationStalledError{}
+	assert.ErrorAs(t, err, &stalledErr)
+	assert.Equal(t, hosts[0], stalledErr.Host)
+	assert.Equal(t, dataTransferOp, stalledErr.Status.OpName)
+	assert.GreaterOrEqual(t, stalledErr.StalledFor, 2*time.Minute)
+
+	// a stall is only reported without FailOnStall
+	throughput.failOnStall = false
+	stop, err = op.shouldStopPolling()
+	assert.NoError(t, err)
+	assert.False(t, stop)
+}
+
+func TestPollReplicationStatusOptions(t *testing.T) {
+	password := "password"
+	options := VPollReplicationStatusFactory()
+	options.TargetDB.Hosts = []string{"192.168.1.201"}
+	options.TargetDB.DBName = "target_db"
+	options.TargetDB.Password = &password
+	options.TransactionID = 1
+	assert.NoError(t, options.validateAnalyzeOptions(vlog.Printer{}))
+
+	// failing on a stall needs a stall timeout
+	options.FailOnStall = true
+	options.StallTimeout = 0
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), "stall timeout")
+	options.StallTimeout = DefaultReplicationStallTimeout
+
+	// the diagnostics need the name and the hosts of the source database
+	options.SourceDB = &DatabaseOptions{DBName: "source_db"}
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), "source database")
+	options.SourceDB.Hosts = []string{"192.168.1.101"}
+	options.SourceDB.UserName = "dbadmin"
+	assert.NoError(t, options.validateAnalyzeOptions(vlog.Printer{}))
+}
+
+func TestReplicationCancel(t *testing.T) {
+	password := "password"
+	options := VReplicationCancelFactory()
+	options.TargetDB.Hosts = []string{"192.168.1.201", "192.168.1.202"}
+	options.TargetDB.DBName = "target_db"
+	options.TargetDB.Password = &password
+	options.TransactionID = 1
+	assert.NoError(t, options.validateAnalyzeOptions(vlog.Printer{}))
+	assert.Equal(t, DefaultReplicationCancelTimeout, options.PollingTimeout)
+
+	// a monitoring user cannot cancel a job
+	options.TargetDB.MonitoringOnly = true
+	assert.Error(t, options.validateAnalyzeOptions(vlog.Printer{}))
+	options.TargetDB.MonitoringOnly = false
+
+	// the cancel is sent to a single pinned target host
+	cancelData := nmaReplicationCancelRequestData{DBName: "target_db", TransactionID: 1, UserName: "dbadmin", Password: &password}
+	replicationStatus := ReplicationStatusResponse{}
+	op, err := makeNMAReplicationCancelOp(options.TargetDB.Hosts, true, &cancelData, options.PollingTimeout,
+		&replicationStatus)
+	assert.NoError(t, err)
+	job, ok := op.job.(*replicationCancelJob)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"dbname": "target_db", "txn_id": 1, "username": "dbadmin", "password": "password"}`,
+		job.cancelRequestBody)
+	cancelRequest, err := job.getSubmitRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1/replicate/cancel", cancelRequest.Endpoint)
+	statusRequest, err := job.getStatusRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, "v1/replicate/status", statusRequest.Endpoint)
+	assert.Contains(t, statusRequest.RequestData, `"txn_id":1`)
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	execContext.initiators = []string{"192.168.1.202"}
+	op.clusterHTTPRequest.RequestCollection = make(map[string]hostHTTPRequest)
+	assert.NoError(t, op.prepare(&execContext))
+	assert.Equal(t, []string{"192.168.1.202"}, op.hosts)
+	assert.True(t, op.getClassification().Mutating)
+
+	// polling stops once the job is canceled
+	assert.True(t, isReplicationFinished(&ReplicationStatusResponse{OpName: dataTransferOp, Status: "canceled"}))
+	assert.False(t, isReplicationFinished(&ReplicationStatusResponse{OpName: dataTransferOp, Status: startedStatus}))
+}
+
+func TestGetReplicationStatus(t *testing.T) {
+	password := "password"
+	options := VGetReplicationStatusFactory()
+	options.TargetDB.Hosts = []string{"192.168.1.201"}
+	options.TargetDB.DBName = "target_db"
+	options.TargetDB.Password = &password
+	// all the jobs are returned without a transaction ID
+	assert.NoError(t, options.validateAnalyzeOptions(vlog.Printer{}))
+	options.TransactionID = -1
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), "valid transaction ID")
+
+	// the ops are grouped by job, and the failed ops are reported
+	otherJob := node1LoadSnapshotPrep
+	otherJob.TransactionID = transactionID + 1
+	failedOp := node1LoadSnapshotPrep
+	failedOp.NodeName = node2
+	failedOp.Status = failedStatus
+	jobs := summarizeReplicationJobs([]ReplicationStatusResponse{otherJob, node1LoadSnapshotPrep, failedOp})
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, int64(transactionID), jobs[0].TransactionID)
+	assert.Equal(t, loadSnapshotPrepOp, jobs[0].Stage)
+	assert.Equal(t, []ReplicationJobError{{NodeName: node2, Stage: loadSnapshotPrepOp,
+		StartTime: failedOp.StartTime, EndTime: failedOp.EndTime}}, jobs[0].Errors)
+	assert.Equal(t, int64(transactionID+1), jobs[1].TransactionID)
+	assert.Equal(t, completedStatus, jobs[1].Status)
+	assert.Empty(t, jobs[1].Errors)
+	assert.Empty(t, summarizeReplicationJobs(nil))
+}
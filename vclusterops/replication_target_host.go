@@ -0,0 +1,171 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// the strategies to pick the target host that the source database connects
+// to for replication, see VReplicationDatabaseOptions.TargetHostStrategy
+const (
+	// the first target host, whether it is up or not
+	ReplicationTargetHostFirst = "first"
+	// the first target host whose NMA responds
+	ReplicationTargetHostFirstUp = "first-up"
+	// the next target host whose NMA responds, starting after the one picked
+	// by the previous replication to the same target database
+	ReplicationTargetHostRoundRobin = "round-robin"
+	// the target host whose NMA responds the fastest
+	ReplicationTargetHostLowestLatency = "lowest-latency"
+)
+
+// probeTargetHost checks the health of the NMA of a target host, with the TLS
+// options and proxy that the target database is reached with, and returns how
+// long it took. It is a variable so that tests can replace it.
+var probeTargetHost = func(vcc VClusterCommands, targetDB *DatabaseOptions, host string) (time.Duration, error) {
+	probeOptions := targetDB.copyForSideOps()
+	nmaHealthOp := makeNMAHealthOp([]string{host})
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaHealthOp}, &probeOptions)
+	start := time.Now()
+	err := clusterOpEngine.run(vcc.Log)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// replicationTargetRotation keeps, for each target database, the index of the
+// target host that round-robin picked last
+var replicationTargetRotation = struct {
+	sync.Mutex
+	next map[string]int
+}{next: make(map[string]int)}
+
+func validateReplicationTargetHostStrategy(strategy string) error {
+	switch strategy {
+	case "", ReplicationTargetHostFirst, ReplicationTargetHostFirstUp,
+		ReplicationTargetHostRoundRobin, ReplicationTargetHostLowestLatency:
+		return nil
+	}
+	return fmt.Errorf("invalid target host strategy %q, must be %s, %s, %s, or %s", strategy,
+		ReplicationTargetHostFirst, ReplicationTargetHostFirstUp, ReplicationTargetHostRoundRobin,
+		ReplicationTargetHostLowestLatency)
+}
+
+// pickReplicationTargetHost picks the target host that the source database
+// connects to, among the pinned initiators of the target database if any. If
+// no target host can be probed, the first target host is picked as before.
+func (vcc VClusterCommands) pickReplicationTargetHost(options *VReplicationDatabaseOptions) (string, error) {
+	candidates, err := pinInitiators(options.TargetDB.Hosts, options.TargetDB.Initiators)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no hosts to pick an initiator from")
+	}
+	strategy := options.TargetHostStrategy
+	if strategy == "" || strategy == ReplicationTargetHostFirst || len(candidates) == 1 {
+		return getInitiator(candidates), nil
+	}
+
+	var host string
+	switch strategy {
+	case ReplicationTargetHostRoundRobin:
+		host, err = vcc.pickRoundRobinTargetHost(&options.TargetDB, candidates)
+	case ReplicationTargetHostLowestLatency:
+		host, err = vcc.pickLowestLatencyTargetHost(&options.TargetDB, candidates)
+	default:
+		host, err = vcc.pickFirstUpTargetHost(&options.TargetDB, candidates)
+	}
+	if err != nil {
+		vcc.Log.PrintWarning("cannot pick the target host by strategy %s, using target host %s, details: %v",
+			strategy, getInitiator(candidates), err)
+		return getInitiator(candidates), nil
+	}
+	vcc.Log.Info("Picked the target host for replication", "strategy", strategy, "host", host)
+	return host, nil
+}
+
+func (vcc VClusterCommands) pickFirstUpTargetHost(targetDB *DatabaseOptions, candidates []string) (string, error) {
+	var allErrs error
+	for _, host := range candidates {
+		_, err := probeTargetHost(vcc, targetDB, host)
+		if err == nil {
+			return host, nil
+		}
+		allErrs = errors.Join(allErrs, fmt.Errorf("target host %s: %w", host, err))
+	}
+	return "", allErrs
+}
+
+// pickRoundRobinTargetHost probes the target hosts without holding the
+// rotation, so that replications to other target databases are not held up
+// by the probes, and moves the rotation past the picked host
+func (vcc VClusterCommands) pickRoundRobinTargetHost(targetDB *DatabaseOptions, candidates []string) (string, error) {
+	replicationTargetRotation.Lock()
+	start := replicationTargetRotation.next[targetDB.DBName]
+	replicationTargetRotation.Unlock()
+
+	var allErrs error
+	for i := range candidates {
+		index := (start + i) % len(candidates)
+		_, err := probeTargetHost(vcc, targetDB, candidates[index])
+		if err == nil {
+			replicationTargetRotation.Lock()
+			replicationTargetRotation.next[targetDB.DBName] = index + 1
+			replicationTargetRotation.Unlock()
+			return candidates[index], nil
+		}
+		allErrs = errors.Join(allErrs, fmt.Errorf("target host %s: %w", candidates[index], err))
+	}
+	return "", allErrs
+}
+
+// pickLowestLatencyTargetHost probes the target hosts concurrently, and picks
+// the first of them in case of equal latencies
+func (vcc VClusterCommands) pickLowestLatencyTargetHost(targetDB *DatabaseOptions, candidates []string) (string, error) {
+	latencies := make([]time.Duration, len(candidates))
+	errs := make([]error, len(candidates))
+	var wg sync.WaitGroup
+	for i := range candidates {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			latencies[i], errs[i] = probeTargetHost(vcc, targetDB, candidates[i])
+		}(i)
+	}
+	wg.Wait()
+
+	best := -1
+	var allErrs error
+	for i := range candidates {
+		if errs[i] != nil {
+			allErrs = errors.Join(allErrs, fmt.Errorf("target host %s: %w", candidates[i], errs[i]))
+			continue
+		}
+		if best < 0 || latencies[i] < latencies[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", allErrs
+	}
+	return candidates[best], nil
+}
@@ -0,0 +1,199 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// DefaultReplicationTargetConcurrency is the default number of target
+// databases that VReplicateDatabaseToTargets replicates to at the same time
+const DefaultReplicationTargetConcurrency = 4
+
+// ReplicationTargetResult is the outcome of replicating to one of the target
+// databases of VReplicateDatabaseToTargets
+type ReplicationTargetResult struct {
+	TargetDBName string
+	// the resolved hosts of the target database
+	TargetHosts []string
+	// the transaction ID of the asynchronous replication job, 0 for a
+	// synchronous replication or if the job did not start
+	TransactionID int64
+	// the source epoch that replication copied data as of, if the source
+	// database reports it
+	ReplicatedEpoch *int64
+	// the objects matched by the replication that were not replicated
+	SkippedObjects []ReplicationSkippedObject
+	Err            error
+	Duration       time.Duration
+}
+
+// VReplicateDatabaseToTargets replicates the same objects of the source
+// database to each of options.TargetDBs, e.g., to fan out a schema to several
+// reporting clusters. The options of every target are validated and the source
+// database is prepared once, then the targets are replicated to concurrently,
+// each with its own ops, and a failure on one target does not stop the others.
+// The results are returned in the order of the targets. OnTransactionStarted
+// and OnProgress can be called concurrently for different targets.
+func (vcc VClusterCommands) VReplicateDatabaseToTargets(options *VReplicationDatabaseOptions) ([]ReplicationTargetResult, error) {
+	if len(options.TargetDBs) == 0 {
+		return nil, fmt.Errorf("must specify the target databases to replicate to")
+	}
+	concurrency := options.MaxTargetConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultReplicationTargetConcurrency
+	}
+
+	// validate the options of each target before any of them runs, as the
+	// validation resolves the hosts into the options
+	results := make([]ReplicationTargetResult, len(options.TargetDBs))
+	targetOptions := make([]*VReplicationDatabaseOptions, len(options.TargetDBs))
+	var sourceOptions *VReplicationDatabaseOptions
+	for i := range options.TargetDBs {
+		results[i].TargetDBName = options.TargetDBs[i].DBName
+		targetOpt := options.copyForTarget(&options.TargetDBs[i])
+		results[i].ReplicatedEpoch = targetOpt.ReplicatedEpoch
+		if err := targetOpt.validateAnalyzeOptions(vcc.Log); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].TargetHosts = util.CopySlice(targetOpt.TargetDB.Hosts)
+		targetOptions[i] = &targetOpt
+	}
+	// targets can share a database name, e.g., several reporting clusters,
+	// so the same target is recognized by its hosts
+	skipDuplicateReplicationTargets(targetOptions, results)
+	for i := range targetOptions {
+		if targetOptions[i] != nil {
+			sourceOptions = targetOptions[i]
+			break
+		}
+	}
+	if sourceOptions == nil {
+		return results, nil
+	}
+
+	// the source database is the same for all targets
+	vdb, err := vcc.prepareReplicationSource(sourceOptions)
+	if err != nil {
+		return nil, fmt.Errorf("fail to prepare the source database for replication: %w", err)
+	}
+
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range targetOptions {
+		if targetOptions[i] == nil {
+			continue
+		}
+		targetOptions[i].MatchedObjects = sourceOptions.MatchedObjects
+		targetVDB := vdb.copy(nil)
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			vcc.replicateToTarget(targetOptions[i], &targetVDB, &results[i])
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// skipDuplicateReplicationTargets fails the targets whose resolved hosts are
+// the same as those of an earlier target, so that a database is not replicated
+// to twice at the same time
+func skipDuplicateReplicationTargets(targetOptions []*VReplicationDatabaseOptions, results []ReplicationTargetResult) {
+	seen := make(map[string]int)
+	for i := range targetOptions {
+		if targetOptions[i] == nil {
+			continue
+		}
+		hosts := util.CopySlice(targetOptions[i].TargetDB.Hosts)
+		slices.Sort(hosts)
+		key := strings.Join(hosts, ",")
+		if first, found := seen[key]; found {
+			results[i].Err = fmt.Errorf("target database %s on hosts %v is the same as target %d",
+				targetOptions[i].TargetDB.DBName, hosts, first+1)
+			targetOptions[i] = nil
+			continue
+		}
+		seen[key] = i
+	}
+}
+
+// copyForTarget returns a copy of the options that replicates to targetDB.
+// The copy has its own maps and slices, which the validation and the ops write
+// to, so that the targets can be replicated to concurrently.
+func (options *VReplicationDatabaseOptions) copyForTarget(targetDB *DatabaseOptions) VReplicationDatabaseOptions {
+	targetOptions := *options
+	targetOptions.DatabaseOptions = options.DatabaseOptions.copyForConcurrentUse()
+	targetOptions.TargetDB = targetDB.copyForConcurrentUse()
+	targetOptions.TargetDBs = nil
+	targetOptions.SkippedObjects = nil
+	targetOptions.MatchedObjects = nil
+	if options.ReplicatedEpoch != nil {
+		targetOptions.ReplicatedEpoch = new(int64)
+	}
+	return targetOptions
+}
+
+// copyForConcurrentUse returns a copy of the options with its own host lists
+// and per-host maps
+func (opt *DatabaseOptions) copyForConcurrentUse() DatabaseOptions {
+	c := *opt
+	c.RawHosts = util.CopySlice(opt.RawHosts)
+	c.Hosts = util.CopySlice(opt.Hosts)
+	c.HostPorts = util.CopyMap(opt.HostPorts)
+	c.ControlAddresses = util.CopyMap(opt.ControlAddresses)
+	c.AddressMapping = util.CopyMap(opt.AddressMapping)
+	return c
+}
+
+// replicateToTarget runs the ops of the replication to one target database
+func (vcc VClusterCommands) replicateToTarget(options *VReplicationDatabaseOptions, vdb *VCoordinationDatabase,
+	result *ReplicationTargetResult) {
+	startTime := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = fmt.Errorf("replication to target database %s panicked: %v", options.TargetDB.DBName, r)
+		}
+		result.Duration = time.Since(startTime)
+	}()
+
+	// tag the log of each target with its name
+	vcc.Log = vcc.Log.WithName(options.TargetDB.DBName)
+	result.TransactionID, result.Err = vcc.replicateToPreparedTarget(options, vdb)
+	result.SkippedObjects = options.SkippedObjects
+}
+
+// FailedReplicationTargets returns the results of the target databases that
+// could not be replicated to
+func FailedReplicationTargets(results []ReplicationTargetResult) []ReplicationTargetResult {
+	var failed []ReplicationTargetResult
+	for i := range results {
+		if results[i].Err != nil {
+			failed = append(failed, results[i])
+		}
+	}
+	return failed
+}
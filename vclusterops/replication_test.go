@@ -0,0 +1,685 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestVReplicationDatabaseOptions_analyzeOptions(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.RawHosts = []string{"192.168.1.101", "192.168.1.102", "192.168.1.101"}
+	opt.TargetDB.Hosts = []string{"192.168.1.201", "192.168.1.201"}
+
+	// duplicate hosts on both sides are removed
+	err := opt.analyzeOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, opt.Hosts)
+	assert.Equal(t, []string{"192.168.1.201"}, opt.TargetDB.Hosts)
+
+	// negative: source and target hosts overlap
+	opt.TargetDB.Hosts = []string{"192.168.1.201", "192.168.1.102"}
+	err = opt.analyzeOptions()
+	assert.ErrorContains(t, err, "source and target host lists overlap on [192.168.1.102]")
+
+	// the hosts overlap when replicating within the same cluster
+	opt.AllowSameCluster = true
+	assert.NoError(t, opt.analyzeOptions())
+}
+
+func TestCheckDifferentDatabases(t *testing.T) {
+	source := makeVCoordinationDatabase()
+	target := makeVCoordinationDatabase()
+	source.Name = "source_db"
+	target.Name = "target_db"
+
+	// the UUIDs and communal storage locations are unknown, so the check is skipped
+	assert.NoError(t, checkDifferentDatabases(vlog.Printer{}, &source, &target))
+
+	// the UUID of the target is unknown, so the communal storage locations are compared
+	source.UUID = "45035996273704982"
+	source.CommunalStorageLocation = "s3://bucket/source_db"
+	target.CommunalStorageLocation = "s3://bucket/target_db"
+	assert.NoError(t, checkDifferentDatabases(vlog.Printer{}, &source, &target))
+	target.CommunalStorageLocation = source.CommunalStorageLocation
+	err := checkDifferentDatabases(vlog.Printer{}, &source, &target)
+	sameErr := &ReplicationTargetIsSourceError{}
+	assert.ErrorAs(t, err, &sameErr)
+	assert.ErrorContains(t, err, "same communal storage")
+
+	// the UUIDs take precedence over the communal storage locations
+	target.UUID = "45035996273705000"
+	assert.NoError(t, checkDifferentDatabases(vlog.Printer{}, &source, &target))
+
+	// negative: target points to the source database, e.g., through a load balancer
+	target.UUID = source.UUID
+	err = checkDifferentDatabases(vlog.Printer{}, &source, &target)
+	assert.ErrorAs(t, err, &sameErr)
+	assert.Equal(t, source.UUID, sameErr.DBUUID)
+}
+
+func TestVReplicationDatabaseOptions_validateTargetCredentials(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.UserName = "dbadmin"
+
+	// the source user maps to the same target user
+	assert.NoError(t, opt.validateTargetCredentials())
+	opt.TargetDB.UserName = "dbadmin"
+	assert.NoError(t, opt.validateTargetCredentials())
+
+	// negative: a different target user cannot be authenticated
+	opt.TargetDB.UserName = "repl_user"
+	assert.ErrorContains(t, opt.validateTargetCredentials(),
+		"cannot authenticate to the target database as user repl_user")
+
+	// a different target user with trust authentication, a password, or a TLS configuration
+	opt.TargetTrustAuth = true
+	assert.NoError(t, opt.validateTargetCredentials())
+	opt.TargetTrustAuth = false
+	opt.TargetDB.Password = new(string)
+	assert.NoError(t, opt.validateTargetCredentials())
+	opt.TargetDB.Password = nil
+	opt.SourceTLSConfig = "repl_tls"
+	assert.NoError(t, opt.validateTargetCredentials())
+
+	// negative: invalid target role
+	opt.TargetRole = "repl;role"
+	assert.ErrorContains(t, opt.validateTargetCredentials(), "invalid character in target role name")
+}
+
+func TestReplicationDeprecatedTargetCredentials(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	password := "secret"
+	opt.TargetUserName = "repl_user"
+	opt.TargetPassword = &password
+
+	// the deprecated options are moved to the target database options
+	opt.migrateDeprecatedOptions(&opt, vlog.Printer{})
+	assert.Equal(t, "repl_user", opt.TargetDB.UserName)
+	assert.Equal(t, &password, opt.TargetDB.Password)
+	assert.Empty(t, opt.TargetUserName)
+	assert.Nil(t, opt.TargetPassword)
+	report := opt.GetDeprecationReport()
+	assert.Len(t, report, 2)
+	assert.Equal(t, DeprecatedOption{Option: "TargetUserName", Replacement: "TargetDB.UserName", Migrated: true,
+		Message: "option TargetUserName is deprecated, its value is moved to option TargetDB.UserName"}, report[0])
+
+	// the target database options win when both are set
+	opt.TargetUserName = "old_user"
+	opt.migrateDeprecatedOptions(&opt, vlog.Printer{})
+	assert.Equal(t, "repl_user", opt.TargetDB.UserName)
+	assert.False(t, opt.GetDeprecationReport()[1].Migrated)
+}
+
+func TestVReplicationDatabaseOptions_credentialForwardingOptions(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.Hosts = []string{"192.168.1.201"}
+	opt.TargetDB.DBName = "target_db"
+
+	opt.FixCredentialForwarding = true
+	opt.RevertCredentialForwarding = true
+	assert.NoError(t, opt.validateExtraOptions())
+
+	// negative: revert without fix
+	opt.FixCredentialForwarding = false
+	assert.ErrorContains(t, opt.validateExtraOptions(), "requires the option to fix it")
+
+	// negative: fix in async replication
+	opt.FixCredentialForwarding = true
+	opt.Async = true
+	assert.ErrorContains(t, opt.validateExtraOptions(), "only supported in synchronous replication")
+
+	assert.True(t, isCredentialForwardingDisabledError(
+		errors.New("[HTTPSStartReplicationOp] EnableConnectCredentialForwarding is false")))
+	assert.False(t, isCredentialForwardingDisabledError(errors.New("authentication failed")))
+}
+
+func TestReplicationPatterns(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.IncludePatterns = []string{"sales.*", "finance.orders*"}
+	opt.ExcludePatterns = []string{"sales.tmp*", "*.backup"}
+	assert.NoError(t, opt.validateFineGrainedReplicationOptions())
+
+	// the patterns are sent as the comma-separated list the source database takes
+	assert.Equal(t, map[string]string{"include_pattern": "sales.*,finance.orders*",
+		"exclude_pattern": "sales.tmp*,*.backup"}, replicationObjectParams(&opt.ReplicationOptions))
+
+	// negative: an empty pattern among others
+	opt.IncludePatterns = []string{"sales.*", ""}
+	assert.ErrorContains(t, opt.validateFineGrainedReplicationOptions(), "the include patterns must not be empty")
+
+	// negative: an invalid pattern among others
+	opt.IncludePatterns = []string{"sales.*"}
+	opt.ExcludePatterns = []string{"sales.tmp*", "v_catalog.*"}
+	assert.ErrorContains(t, opt.validateFineGrainedReplicationOptions(), "invalid character in pattern v_catalog.*")
+}
+
+func TestReplicationObjectRenames(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.ObjectRenames = map[string]string{"prod": "prod_copy", "sales.orders": "sales.orders_copy"}
+	assert.NoError(t, opt.validateFineGrainedReplicationOptions())
+
+	// the renames are sent on both the NMA and the HTTPS path
+	data := nmaStartReplicationRequestData{ObjectRenames: normalizeReplicationRenames(opt.ObjectRenames)}
+	dataBytes, err := json.Marshal(data)
+	assert.NoError(t, err)
+	assert.Contains(t, string(dataBytes), `"object_renames":{"prod":"prod_copy","sales.orders":"sales.orders_copy"}`)
+	op := httpsStartReplicationOp{objectRenames: normalizeReplicationRenames(opt.ObjectRenames)}
+	assert.NoError(t, op.setupRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.101"],
+		`"object_renames":{"prod":"prod_copy","sales.orders":"sales.orders_copy"}`)
+
+	// no renames are omitted from the payload
+	assert.Nil(t, normalizeReplicationRenames(nil))
+
+	// negative: a schema renamed to a table
+	opt.ObjectRenames = map[string]string{"prod": "prod_copy.t1"}
+	assert.ErrorContains(t, opt.validateFineGrainedReplicationOptions(), "must both be schemas or both be tables")
+
+	// negative: a wildcard in a rename
+	opt.ObjectRenames = map[string]string{"prod*": "prod_copy"}
+	assert.ErrorContains(t, opt.validateFineGrainedReplicationOptions(), "invalid source object of rename prod*")
+
+	// negative: two objects renamed to the same target
+	opt.ObjectRenames = map[string]string{"prod": "prod_copy", "test": "prod_copy"}
+	assert.ErrorContains(t, opt.validateFineGrainedReplicationOptions(), "to prod_copy")
+}
+
+func TestCheckReplicationLocks(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.IncludePatterns = []string{"public.*"}
+	op, err := makeHTTPSCheckReplicationLocksOp([]string{"192.168.1.101"}, false, "", nil, "",
+		&opt.ReplicationOptions, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"include_pattern": "public.*"}, op.objectParams)
+	op.setLogger(vlog.Printer{})
+
+	// no session blocks replication
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS, content: `{"session_list": []}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+
+	// negative: a session holds a lock on an object to replicate
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS, content: `{"session_list": [{"session_id": "s1", ` +
+			`"user_name": "dbadmin", "object_name": "public.t1", "lock_mode": "O"}]}`},
+	}
+	err = op.processResult(nil)
+	blockedErr := &ReplicationBlockedError{}
+	assert.ErrorAs(t, err, &blockedErr)
+	assert.Len(t, blockedErr.Sessions, 1)
+	assert.ErrorContains(t, err, "blocking sessions: s1 (user dbadmin, O lock on public.t1)")
+
+	// negative: the lock wait timeout is negative
+	opt.TargetDB.Hosts = []string{"192.168.1.201"}
+	opt.TargetDB.DBName = "target_db"
+	opt.LockWaitTimeout = -1
+	assert.ErrorContains(t, opt.validateExtraOptions(), "lock wait timeout must not be negative")
+}
+
+func TestReplicationTargetResourcePool(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.Hosts = []string{"192.168.1.201"}
+	opt.TargetDB.DBName = "target_db"
+	opt.TargetResourcePool = "replication_pool"
+	assert.NoError(t, opt.validateExtraOptions())
+
+	// the pool is passed to the start-replication request
+	op, err := makeHTTPSStartReplicationOp("source_db", []string{"192.168.1.101"}, false, "", nil, false,
+		&opt.TargetDB, "192.168.1.201", "", opt.TargetResourcePool, "", "", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, op.setupRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.101"], `"resource_pool":"replication_pool"`)
+}
+
+func TestReplicationTargetTLSConfig(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.DBName = "target_db"
+
+	// each side gets its own TLS configuration in the start-replication requests
+	op, err := makeHTTPSStartReplicationOp("source_db", []string{"192.168.1.101"}, false, "", nil, false,
+		&opt.TargetDB, "192.168.1.201", "", "", "source_tls", "", nil)
+	assert.NoError(t, err)
+	op.targetTLSConfig = "target_tls"
+	assert.NoError(t, op.setupRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.101"], `"tls_config":"source_tls"`)
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.101"], `"target_tls_config":"target_tls"`)
+
+	nmaOp, err := makeNMAReplicationStartOp([]string{"192.168.1.101"}, false, false,
+		&nmaStartReplicationRequestData{TLSConfig: "source_tls", TargetTLSConfig: "target_tls"}, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, nmaOp.updateRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, nmaOp.hostRequestBodyMap["192.168.1.101"], `"target_tls_config":"target_tls"`)
+
+	// the target TLS configuration is omitted if not set
+	op.targetTLSConfig = ""
+	assert.NoError(t, op.setupRequestBody([]string{"192.168.1.101"}))
+	assert.NotContains(t, op.hostRequestBodyMap["192.168.1.101"], "target_tls_config")
+
+	// negative: invalid pool name
+	opt.TargetResourcePool = "pool;drop"
+	assert.Error(t, opt.validateExtraOptions())
+}
+
+func TestReplicationSourceSnapshot(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.Hosts = []string{"192.168.1.201"}
+	opt.TargetDB.DBName = "target_db"
+	opt.SourceSnapshot = ReplicationSnapshotCurrentEpoch
+	assert.NoError(t, opt.validateExtraOptions())
+
+	// negative: unknown snapshot
+	opt.SourceSnapshot = "yesterday"
+	assert.ErrorContains(t, opt.validateExtraOptions(), `invalid source snapshot "yesterday"`)
+
+	// the snapshot is sent, and the epoch it resolves to is reported
+	epoch := new(int64)
+	op, err := makeHTTPSStartReplicationOp("source_db", []string{"192.168.1.101"}, false, "", nil, false,
+		&opt.TargetDB, "192.168.1.201", "", "", "", "", nil)
+	assert.NoError(t, err)
+	op.sourceSnapshot = ReplicationSnapshotCurrentEpoch
+	op.replicatedEpoch = epoch
+	op.setLogger(vlog.Printer{})
+	assert.NoError(t, op.setupRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.101"], `"snapshot":"current_epoch"`)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS, content: `{"detail": "REPLICATE", "epoch": 4242}`},
+	}
+	assert.NoError(t, op.processResult(&opEngineExecContext{}))
+	assert.Equal(t, int64(4242), *epoch)
+
+	// the epoch is optional in the response of the NMA
+	*epoch = 0
+	execContext := opEngineExecContext{}
+	nmaOp, err := makeNMAReplicationStartOp([]string{"192.168.1.101"}, false, false,
+		&nmaStartReplicationRequestData{}, epoch, nil)
+	assert.NoError(t, err)
+	nmaOp.setLogger(vlog.Printer{})
+	nmaOp.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS},
+	}
+	assert.NoError(t, nmaOp.processResult(&execContext))
+	assert.Zero(t, *epoch)
+	nmaOp.clusterHTTPRequest.ResultCollection["192.168.1.101"] = hostHTTPResult{host: "192.168.1.101",
+		status: SUCCESS, content: `{"epoch": 17}`}
+	assert.NoError(t, nmaOp.processResult(&execContext))
+	assert.Equal(t, int64(17), *epoch)
+}
+
+func TestReplicationPointInTime(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.Hosts = []string{"192.168.1.201"}
+	opt.TargetDB.DBName = "target_db"
+	epoch := int64(4242)
+	opt.Epoch = &epoch
+	assert.NoError(t, opt.validateExtraOptions())
+
+	// negative: both an epoch and a time
+	opt.AtTime = "2024-06-01 12:00:00"
+	assert.ErrorContains(t, opt.validateExtraOptions(), "both an epoch and a time")
+
+	// negative: a point in time along with a source snapshot
+	opt.Epoch = nil
+	opt.SourceSnapshot = ReplicationSnapshotCurrentEpoch
+	assert.ErrorContains(t, opt.validateExtraOptions(), "with source snapshot current_epoch")
+	opt.SourceSnapshot = ""
+
+	for _, atTime := range []string{"2024-06-01 12:00:00", "2024-06-01T12:00:00Z", "2024-06-01"} {
+		opt.AtTime = atTime
+		assert.NoError(t, opt.validateExtraOptions())
+	}
+
+	// negative: invalid time
+	opt.AtTime = "yesterday"
+	assert.ErrorContains(t, opt.validateExtraOptions(), `invalid time "yesterday"`)
+
+	// negative: negative epoch
+	opt.AtTime = ""
+	epoch = -1
+	opt.Epoch = &epoch
+	assert.ErrorContains(t, opt.validateExtraOptions(), "must not be negative")
+
+	// the point in time is sent to the source database
+	epoch = 4242
+	op, err := makeHTTPSStartReplicationOp("source_db", []string{"192.168.1.101"}, false, "", nil, false,
+		&opt.TargetDB, "192.168.1.201", "", "", "", "", nil)
+	assert.NoError(t, err)
+	op.epoch = opt.Epoch
+	assert.NoError(t, op.setupRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, op.hostRequestBodyMap["192.168.1.101"], `"epoch":4242`)
+	assert.NotContains(t, op.hostRequestBodyMap["192.168.1.101"], "at_time")
+
+	nmaOp, err := makeNMAReplicationStartOp([]string{"192.168.1.101"}, false, false,
+		&nmaStartReplicationRequestData{AtTime: "2024-06-01 12:00:00"}, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, nmaOp.updateRequestBody([]string{"192.168.1.101"}))
+	assert.Contains(t, nmaOp.hostRequestBodyMap["192.168.1.101"], `"at_time":"2024-06-01 12:00:00"`)
+}
+
+func TestPickReplicationTargetHost(t *testing.T) {
+	latencies := map[string]time.Duration{"192.168.1.202": 5 * time.Millisecond, "192.168.1.203": time.Millisecond}
+	defaultProbe := probeTargetHost
+	probeTargetHost = func(_ VClusterCommands, _ *DatabaseOptions, host string) (time.Duration, error) {
+		if latency, ok := latencies[host]; ok {
+			return latency, nil
+		}
+		return 0, errors.New("connection refused")
+	}
+	defer func() { probeTargetHost = defaultProbe }()
+
+	vcc := VClusterCommands{}
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.DBName = "target_db_pick"
+	opt.TargetDB.Hosts = []string{"192.168.1.201", "192.168.1.202", "192.168.1.203"}
+
+	// the first target host is picked by default, whether it is up or not
+	host, err := vcc.pickReplicationTargetHost(&opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.201", host)
+
+	// the first target host is down, so the next one is picked
+	opt.TargetHostStrategy = ReplicationTargetHostFirstUp
+	host, err = vcc.pickReplicationTargetHost(&opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.202", host)
+
+	opt.TargetHostStrategy = ReplicationTargetHostLowestLatency
+	host, err = vcc.pickReplicationTargetHost(&opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.203", host)
+
+	// round robin skips the down host across calls
+	opt.TargetHostStrategy = ReplicationTargetHostRoundRobin
+	var picked []string
+	for i := 0; i < 3; i++ {
+		host, err = vcc.pickReplicationTargetHost(&opt)
+		assert.NoError(t, err)
+		picked = append(picked, host)
+	}
+	assert.Equal(t, []string{"192.168.1.202", "192.168.1.203", "192.168.1.202"}, picked)
+
+	// no target host can be probed, so the first one is picked
+	latencies = nil
+	opt.TargetHostStrategy = ReplicationTargetHostFirstUp
+	host, err = vcc.pickReplicationTargetHost(&opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.201", host)
+
+	// negative: unknown strategy
+	assert.ErrorContains(t, validateReplicationTargetHostStrategy("random"), `invalid target host strategy "random"`)
+}
+
+func TestCheckReplicationSupport(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TargetDB.Hosts = []string{"192.168.1.201"}
+	opt.TargetDB.DBName = "target_db"
+	opt.ProjectionMode = ReplicationProjectionsRebuild
+	assert.NoError(t, opt.validateExtraOptions())
+	opt.ProjectionMode = "drop"
+	assert.ErrorContains(t, opt.validateExtraOptions(), `invalid projection mode "drop"`)
+
+	op, err := makeHTTPSCheckReplicationSupportOp([]string{"192.168.1.101"}, false, "", nil, "",
+		ReplicationProjectionsRebuild, nil)
+	assert.NoError(t, err)
+	op.setLogger(vlog.Printer{})
+
+	// the source database supports rebuilding the projections
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS,
+			content: `{"projection_modes": ["replicate", "rebuild"]}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+
+	// negative: an older source database without the capabilities endpoint
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: FAILURE, statusCode: NotFoundCode, err: assert.AnError},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "does not support the projection mode rebuild")
+}
+
+func TestReplicationSkippedObjects(t *testing.T) {
+	op, err := makeNMAReplicationStartOp([]string{"192.168.1.101"}, false, false,
+		&nmaStartReplicationRequestData{}, nil, nil)
+	assert.NoError(t, err)
+	op.setLogger(vlog.Printer{})
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS, content: `{"skipped_objects": [` +
+			`{"type": "table", "name": "public.ext1", "reason": "unsupported_type"},` +
+			`{"type": "schema", "name": "secret", "reason": "permission_denied"}]}`},
+	}
+	execContext := opEngineExecContext{}
+	assert.NoError(t, op.processResult(&execContext))
+	expected := []ReplicationSkippedObject{
+		{Type: "table", Name: "public.ext1", Reason: ReplicationSkipUnsupportedType},
+		{Type: "schema", Name: "secret", Reason: ReplicationSkipPermissionDenied},
+	}
+	assert.Equal(t, expected, execContext.skippedObjects)
+
+	// the skipped objects are kept in the options
+	opt := VReplicationDatabaseFactory()
+	opt.reportSkippedObjects(VClusterCommands{}, execContext.skippedObjects)
+	assert.Equal(t, expected, opt.SkippedObjects)
+}
+
+func TestReplicationMatchedObjects(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.TableOrSchemaName = "sales"
+	opt.ExcludePatterns = []string{"sales.tmp*"}
+	var objects []string
+	op, err := makeHTTPSGetReplicationObjectsOp([]string{"192.168.1.101"}, false, "", nil, "",
+		&opt.ReplicationOptions, nil, &objects)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"object_name": "sales", "exclude_pattern": "sales.tmp*"}, op.objectParams)
+	op.setLogger(vlog.Printer{})
+
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: SUCCESS,
+			content: `{"object_list": ["sales.orders", "sales.customers"]}`},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Equal(t, []string{"sales.orders", "sales.customers"}, objects)
+
+	// negative: the source database cannot resolve the objects
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {host: "192.168.1.101", status: FAILURE, err: errors.New("internal error")},
+	}
+	assert.ErrorContains(t, op.processResult(nil), "internal error")
+
+	noMatchErr := &ReplicationNoMatchingObjectsError{TableOrSchemaName: "sales",
+		ExcludePatterns: []string{"sales.tmp*"}}
+	assert.Equal(t, `no objects of the source database match the table or schema name "sales" `+
+		`and exclude patterns ["sales.tmp*"]`, noMatchErr.Error())
+}
+
+func TestReplicateDatabaseToTargets(t *testing.T) {
+	vcc := VClusterCommands{}
+	opt := VReplicationDatabaseFactory()
+	opt.DBName = "source_db"
+	opt.RawHosts = []string{"192.168.1.101"}
+	opt.IsEon = true
+	password := "password"
+	opt.Password = &password
+
+	// negative: no targets
+	_, err := vcc.VReplicateDatabaseToTargets(&opt)
+	assert.ErrorContains(t, err, "must specify the target databases")
+
+	// each target fails on its own, and the results follow the order of the targets
+	opt.TargetDBs = []DatabaseOptions{{DBName: "report_db1"}, {DBName: "report_db2", Hosts: []string{"192.168.1.101"}}}
+	results, err := vcc.VReplicateDatabaseToTargets(&opt)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "report_db1", results[0].TargetDBName)
+	assert.ErrorContains(t, results[0].Err, "must specify a target host")
+	assert.Equal(t, "report_db2", results[1].TargetDBName)
+	assert.ErrorContains(t, results[1].Err, "source and target host lists overlap")
+	assert.Len(t, FailedReplicationTargets(results), 2)
+	// the options of the caller are left untouched
+	assert.Empty(t, opt.TargetDB.DBName)
+}
+
+func TestSkipDuplicateReplicationTargets(t *testing.T) {
+	makeTarget := func(dbName string, hosts ...string) *VReplicationDatabaseOptions {
+		opt := VReplicationDatabaseFactory()
+		opt.TargetDB.DBName = dbName
+		opt.TargetDB.Hosts = hosts
+		return &opt
+	}
+	// the same database name on different clusters is not a duplicate, the
+	// same hosts in another order are
+	targetOptions := []*VReplicationDatabaseOptions{
+		makeTarget("report_db", "192.168.1.201", "192.168.1.202"),
+		makeTarget("report_db", "192.168.1.211"),
+		nil,
+		makeTarget("report_db2", "192.168.1.202", "192.168.1.201"),
+	}
+	results := make([]ReplicationTargetResult, len(targetOptions))
+	skipDuplicateReplicationTargets(targetOptions, results)
+	assert.NotNil(t, targetOptions[0])
+	assert.NotNil(t, targetOptions[1])
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.Nil(t, targetOptions[3])
+	assert.ErrorContains(t, results[3].Err,
+		"target database report_db2 on hosts [192.168.1.201 192.168.1.202] is the same as target 1")
+}
+
+func TestReplicationCopyForTarget(t *testing.T) {
+	opt := VReplicationDatabaseFactory()
+	opt.RawHosts = []string{"192.168.1.101:8443"}
+	opt.HostPorts = map[string]HostPorts{"192.168.1.102": {HTTPSPort: 8444}}
+	opt.ControlAddresses = map[string]string{"192.168.1.101": "10.0.0.1"}
+	target := DatabaseOptions{DBName: "report_db", HostPorts: map[string]HostPorts{"192.168.1.201": {NMAPort: 5555}}}
+
+	// the per-host maps of the copies are written by the validation of each
+	// target, concurrently with the replication to the others
+	copy1 := opt.copyForTarget(&target)
+	copy2 := opt.copyForTarget(&target)
+	copy1.HostPorts["192.168.1.101"] = HostPorts{HTTPSPort: 8443}
+	copy1.ControlAddresses["192.168.1.101"] = "10.0.0.2"
+	copy1.TargetDB.HostPorts["192.168.1.202"] = HostPorts{}
+	assert.Len(t, opt.HostPorts, 1)
+	assert.Len(t, copy2.HostPorts, 1)
+	assert.Equal(t, "10.0.0.1", opt.ControlAddresses["192.168.1.101"])
+	assert.Len(t, target.HostPorts, 1)
+	assert.Equal(t, "report_db", copy2.TargetDB.DBName)
+}
+
+func TestReplicationFixCredentialForwarding(t *testing.T) {
+	const sourceHost = "127.0.0.1"
+	var mu sync.Mutex
+	var requests []string
+	credentialForwarding := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var response string
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/health"):
+			response = `{"healthy": "true"}`
+		case strings.HasSuffix(r.URL.Path, "/nodes"):
+			response = `{"node_list": [{"name": "v_test_db_node0001", "address": "` + sourceHost +
+				`", "state": "UP", "database": "test_db", "is_primary": true, "subcluster_name": "default_subcluster"}]}`
+		case strings.HasSuffix(r.URL.Path, "/namespaces"):
+			response = `{"namespace_list": [{"namespace_id": 1, "namespace_name": "default_namespace"}]}`
+		case strings.HasSuffix(r.URL.Path, "/configuration/set"):
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, credentialForwardingParam, body["config_parameter"])
+			credentialForwarding = body["value"] == "1"
+			response = `"Set parameter successfully"`
+		case strings.HasSuffix(r.URL.Path, "/replicate/start"):
+			if !credentialForwarding {
+				w.WriteHeader(http.StatusInternalServerError)
+				response = `{"detail": "` + credentialForwardingParam + ` is false"}`
+				break
+			}
+			response = `{"detail": "REPLICATE"}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	assert.NoError(t, err)
+	certPEM, keyPEM := generateTestCert(t, "dbadmin")
+
+	options := VReplicationDatabaseFactory()
+	options.DBName = "test_db"
+	options.Hosts = []string{sourceHost}
+	options.RawHosts = options.Hosts
+	options.UserName = "dbadmin"
+	password := "password"
+	options.Password = &password
+	options.Key = string(keyPEM)
+	options.Cert = string(certPEM)
+	options.CaCert = string(certPEM)
+	options.HostPorts = map[string]HostPorts{sourceHost: {NMAPort: port, HTTPSPort: port}}
+	// the target user is authenticated with the forwarded source credentials
+	options.TargetDB.DBName = "target_db"
+	options.TargetDB.Hosts = []string{"127.0.0.2"}
+	options.FixCredentialForwarding = true
+	options.RevertCredentialForwarding = true
+	options.SkipLockCheck = true
+
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap[sourceHost] = &VCoordinationNode{Name: "v_test_db_node0001", Address: sourceHost,
+		State: util.NodeUpState, Subcluster: "default_subcluster", IsPrimary: true}
+
+	vcc := VClusterCommands{}
+	_, err = vcc.replicateToPreparedTarget(&options, &vdb)
+	assert.NoError(t, err)
+
+	// replication is retried once credential forwarding is enabled, which is
+	// disabled again afterwards
+	assert.False(t, credentialForwarding)
+	var started, setConfig int
+	for _, request := range requests {
+		if strings.HasSuffix(request, "/replicate/start") {
+			started++
+		}
+		if strings.HasSuffix(request, "/configuration/set") {
+			setConfig++
+		}
+	}
+	assert.Equal(t, 2, started)
+	assert.Equal(t, 2, setConfig)
+	// the target database is not queried for its UUID with forwarded credentials
+	for _, request := range requests {
+		assert.False(t, strings.HasSuffix(request, "/cluster"), request)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+// headers that are attached to every NMA and HTTPS request, so that the
+// requests of a command can be correlated across the logs of the caller,
+// vcluster, and the servers
+const (
+	RequestIDHeader  = "X-Request-ID"
+	userAgentHeader  = "User-Agent"
+	DefaultUserAgent = "vclusterops"
+)
+
+type RequestIDError = vtypes.RequestIDError
+
+// GetRequestID returns the correlation ID of the command that returned an error
+func GetRequestID(err error) (string, bool) {
+	requestIDErr := &RequestIDError{}
+	if errors.As(err, &requestIDErr) {
+		return requestIDErr.RequestID, true
+	}
+	return "", false
+}
+
+// generateRequestID returns a random correlation ID
+func generateRequestID() string {
+	const idSize = 8
+	bytes := make([]byte, idSize)
+	if _, err := crand.Read(bytes); err != nil {
+		// crypto/rand does not fail on the platforms that we support
+		return "vc-unknown"
+	}
+	return "vc-" + hex.EncodeToString(bytes)
+}
+
+// wrapWithRequestID adds the correlation ID to an error, unless it already has one
+func wrapWithRequestID(err error, requestID string) error {
+	if err == nil || requestID == "" {
+		return err
+	}
+	if _, ok := GetRequestID(err); ok {
+		return err
+	}
+	return &RequestIDError{RequestID: requestID, Err: err}
+}
@@ -0,0 +1,148 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// for spilling a large response body to a temporary file instead of keeping
+// it in memory
+type responseBodySpiller struct {
+	logger vlog.Printer
+	// the size in bytes above which a response body is spilled
+	threshold int64
+	// the directory of the spill files, the default temporary directory if empty
+	dir string
+	// the spill file of the last response, if its body was spilled
+	spillFile string
+}
+
+// makeHTTPSpillAdapter creates an HTTP adapter which will write a
+// response body that is larger than threshold to a file in dir,
+// rather than copying the body to memory.
+func makeHTTPSpillAdapter(logger vlog.Printer, threshold int64, dir string) httpAdapter {
+	newHTTPAdapter := makeHTTPAdapter(logger)
+	newHTTPAdapter.respBodyHandler = &responseBodySpiller{
+		logger:    logger,
+		threshold: threshold,
+		dir:       dir,
+	}
+	return newHTTPAdapter
+}
+
+func (spiller *responseBodySpiller) processResponseBody(resp *http.Response) (bodyString string, err error) {
+	spiller.spillFile = ""
+	if !isSuccess(resp) {
+		// in case of error, we get a small RFC7807 error
+		return readResponseBody(resp)
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, spiller.threshold+1))
+	if err != nil {
+		return "", fmt.Errorf("fail to read the response body: %w", err)
+	}
+	if int64(len(head)) <= spiller.threshold {
+		return string(head), nil
+	}
+
+	file, err := os.CreateTemp(spiller.dir, "vcluster-response-*.json")
+	if err != nil {
+		return "", fmt.Errorf("fail to create a file to spill the response body to: %w", err)
+	}
+	defer file.Close()
+	bytesWritten, err := io.Copy(file, io.MultiReader(bytes.NewReader(head), resp.Body))
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("fail to spill the response body to file %s: %w", file.Name(), err)
+	}
+	spiller.spillFile = file.Name()
+	spiller.logger.Info("Response body spilled", "File", spiller.spillFile, "Bytes", bytesWritten)
+	return "", nil
+}
+
+// openContent returns a reader of the response body, whether it is held in
+// content or spilled to a file
+func (hostResult *hostHTTPResult) openContent() (io.ReadCloser, error) {
+	if hostResult.spillFile == "" {
+		return io.NopCloser(strings.NewReader(hostResult.content)), nil
+	}
+	return os.Open(hostResult.spillFile)
+}
+
+// isContentEmpty returns whether the response body is empty
+func (hostResult *hostHTTPResult) isContentEmpty() bool {
+	return hostResult.content == "" && hostResult.spillFile == ""
+}
+
+// removeSpillFile removes the spill file of the response body, if any, once
+// the body is processed
+func (hostResult *hostHTTPResult) removeSpillFile() {
+	if hostResult.spillFile != "" {
+		os.Remove(hostResult.spillFile)
+		hostResult.spillFile = ""
+	}
+}
+
+// parseAndCheckResultContent is parseAndCheckResponse for a result whose body
+// may be spilled to a file, which is decoded from the file rather than read
+// into memory as a whole
+func (op *opBase) parseAndCheckResultContent(host string, result *hostHTTPResult, responseObj any) error {
+	if result.spillFile == "" {
+		return op.parseAndCheckResponse(host, result.content, responseObj)
+	}
+	file, err := os.Open(result.spillFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = json.NewDecoder(file).Decode(responseObj); err != nil {
+		op.logger.Error(err, "fail to parse spilled response on host, detail", "host", host, "file", result.spillFile)
+		return err
+	}
+	op.logger.Info("JSON response parsed from spill file", "host", host, "file", result.spillFile)
+	if catalogResponse, ok := responseObj.(catalogAddressResponse); ok && len(op.externalAddresses) > 0 {
+		catalogResponse.mapAddresses(op.externalAddresses)
+	}
+	return nil
+}
+
+// decodeResultItems decodes the JSON array of a result one item at a time,
+// passing each item to itemHandler, so that a spilled array is never held in
+// memory as a whole
+func decodeResultItems[T any](result *hostHTTPResult, itemHandler func(item *T)) (itemCount int, err error) {
+	content, err := result.openContent()
+	if err != nil {
+		return 0, err
+	}
+	defer content.Close()
+	return decodeJSONArray(content, func(rawItem json.RawMessage) error {
+		var item T
+		if err := json.Unmarshal(rawItem, &item); err != nil {
+			return err
+		}
+		itemHandler(&item)
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func makeSpillTestResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestSpillResponseBody(t *testing.T) {
+	const body = `[{"name": "vertica.log"}, {"name": "dbLog"}]`
+	adapter := makeHTTPSpillAdapter(vlog.Printer{}, int64(len(body)), t.TempDir())
+
+	// a body within the threshold is kept in memory
+	result := adapter.generateResult(makeSpillTestResponse(body))
+	assert.True(t, result.isPassing())
+	assert.Equal(t, body, result.content)
+	assert.Empty(t, result.spillFile)
+
+	// a larger body is spilled to a file
+	result = adapter.generateResult(makeSpillTestResponse(body + " "))
+	assert.True(t, result.isPassing())
+	assert.Empty(t, result.content)
+	assert.FileExists(t, result.spillFile)
+	assert.False(t, result.isContentEmpty())
+
+	type stagedFile struct {
+		Name string `json:"name"`
+	}
+	var names []string
+	itemCount, err := decodeResultItems(&result, func(item *stagedFile) {
+		names = append(names, item.Name)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, itemCount)
+	assert.Equal(t, []string{"vertica.log", "dbLog"}, names)
+
+	// the spill files are removed with the results
+	spillFile := result.spillFile
+	httpRequest := clusterHTTPRequest{ResultCollection: map[string]hostHTTPResult{"192.168.1.101": result}}
+	httpRequest.releaseResults()
+	_, err = os.Stat(spillFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestParseSpilledResultContent(t *testing.T) {
+	adapter := makeHTTPSpillAdapter(vlog.Printer{}, 1, t.TempDir())
+	result := adapter.generateResult(makeSpillTestResponse(`{"name": "test_db", "versions": {"global": 12}}`))
+	assert.NotEmpty(t, result.spillFile)
+
+	op := opBase{}
+	nmaVDB := nmaVDatabase{}
+	assert.NoError(t, op.parseAndCheckResultContent("192.168.1.101", &result, &nmaVDB))
+	assert.Equal(t, "test_db", nmaVDB.Name)
+	assert.Equal(t, "12", nmaVDB.Versions.Global.String())
+	result.removeSpillFile()
+}
+
+func TestSetupForSpilling(t *testing.T) {
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+
+	// the responses are kept in memory without a spill threshold
+	dispatcher.setupForSpilling([]string{"192.168.1.101"})
+	adapter, ok := dispatcher.pool.connections["192.168.1.101"].(*httpAdapter)
+	assert.True(t, ok)
+	assert.IsType(t, &responseBodyReader{}, adapter.respBodyHandler)
+
+	dispatcher.spillThreshold = 1024
+	dispatcher.setupForSpilling([]string{"192.168.1.101"})
+	adapter, ok = dispatcher.pool.connections["192.168.1.101"].(*httpAdapter)
+	assert.True(t, ok)
+	assert.IsType(t, &responseBodySpiller{}, adapter.respBodyHandler)
+}
@@ -0,0 +1,181 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+// ResultCache keeps the responses of the GET requests of read-only commands,
+// with the catalog version of the host that returned them. Before a request
+// whose response is cached is sent, the catalog version of the host is fetched,
+// which is a small response, and the cached response is reused if the catalog
+// has not changed since, so that repeated polls, e.g., of monitoring
+// integrations, return quickly. The node states are not versioned by the
+// catalog, so the requests of the nodes endpoints are never cached. The cache
+// is not used by the commands that change the cluster. It is safe for
+// concurrent use, so it can be shared by the commands of a poller.
+type ResultCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+	hits    int
+	misses  int
+}
+
+type cachedResponse struct {
+	catalogVersion int64
+	content        string
+}
+
+func NewResultCache() *ResultCache {
+	return &ResultCache{entries: make(map[string]cachedResponse)}
+}
+
+// Hits returns the number of requests whose response was read from the cache
+// as the catalog version had not changed
+func (c *ResultCache) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// Misses returns the number of requests whose response was sent by the server
+func (c *ResultCache) Misses() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.misses
+}
+
+// Reset drops the cached responses and the counts
+func (c *ResultCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cachedResponse)
+	c.hits, c.misses = 0, 0
+}
+
+// the responses of a request are cached per user, as they depend on the
+// privileges of the user
+func resultCacheKey(service, requestURL, username string) string {
+	return fmt.Sprintf("%s|%s|%s", service, username, requestURL)
+}
+
+// hit returns the cached response of a request if it was cached at the given
+// catalog version
+func (c *ResultCache) hit(key string, catalogVersion int64) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || entry.catalogVersion != catalogVersion {
+		return "", false
+	}
+	c.hits++
+	return entry.content, true
+}
+
+// store caches the response of a request if it succeeded, otherwise the
+// cached response, if any, is dropped
+func (c *ResultCache) store(key string, catalogVersion int64, result *hostHTTPResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.misses++
+	if result.isPassing() {
+		c.entries[key] = cachedResponse{catalogVersion: catalogVersion, content: result.content}
+	} else {
+		delete(c.entries, key)
+	}
+}
+
+// cachedRequest is a request whose response is cached once it is received
+type cachedRequest struct {
+	key            string
+	catalogVersion int64
+}
+
+// isCacheableRequest returns whether the response of a request can be cached
+func (adapter *httpAdapter) isCacheableRequest(request *hostHTTPRequest) bool {
+	if _, ok := adapter.respBodyHandler.(*responseBodyReader); !ok || adapter.resultCache == nil {
+		return false
+	}
+	return request.Method == GetMethod && !request.IsNMACommand &&
+		!strings.HasPrefix(request.Endpoint, HTTPCurVersion+"nodes")
+}
+
+// lookupCachedResult fetches the catalog version of the host and returns the
+// cached response of req if the catalog has not changed. Otherwise, it returns
+// the request whose response is to be cached, or nil if the catalog version
+// cannot be fetched.
+func (adapter *httpAdapter) lookupCachedResult(client *http.Client, req *http.Request, service,
+	username string) (cached *cachedRequest, result hostHTTPResult, hit bool) {
+	catalogVersion, err := adapter.fetchCatalogVersion(client, req, service)
+	if err != nil {
+		adapter.logger.Info("Cannot fetch the catalog version, the response is not cached",
+			"host", adapter.host, "details", err.Error())
+		return nil, result, false
+	}
+	key := resultCacheKey(service, req.URL.String(), username)
+	if content, ok := adapter.resultCache.hit(key, catalogVersion); ok {
+		return nil, adapter.makeSuccessResult(content, http.StatusOK), true
+	}
+	return &cachedRequest{key: key, catalogVersion: catalogVersion}, result, false
+}
+
+// fetchCatalogVersion gets the catalog version of the host from the node
+// information of the host, with the credentials and headers of req
+func (adapter *httpAdapter) fetchCatalogVersion(client *http.Client, req *http.Request, service string) (int64, error) {
+	versionURL := fmt.Sprintf("https://%s/%s%s%s", service, HTTPCurVersion, util.NodesEndpoint, adapter.host)
+	versionReq, err := http.NewRequest(GetMethod, versionURL, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	versionReq.Header = req.Header.Clone()
+	versionReq.Close = true
+	resp, err := client.Do(versionReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if !isSuccess(resp) {
+		return 0, fmt.Errorf("GET %s returned status %d", versionURL, resp.StatusCode)
+	}
+	body, err := readResponseBody(resp)
+	if err != nil {
+		return 0, err
+	}
+	nodesStates := nodesStateInfo{}
+	err = json.Unmarshal([]byte(body), &nodesStates)
+	if err != nil {
+		return 0, fmt.Errorf("fail to parse the node information: %w", err)
+	}
+	if len(nodesStates.NodeList) != 1 {
+		return 0, fmt.Errorf("expect the information of one node, but got %d", len(nodesStates.NodeList))
+	}
+	return nodesStates.NodeList[0].CatalogVersion, nil
+}
+
+// generateCachedResult generates the result of a response and caches it at
+// the catalog version that was fetched before the request was sent
+func (adapter *httpAdapter) generateCachedResult(resp *http.Response, cached *cachedRequest) hostHTTPResult {
+	result := adapter.generateResult(resp)
+	adapter.resultCache.store(cached.key, cached.catalogVersion, &result)
+	return result
+}
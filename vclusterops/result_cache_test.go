@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestResultCache(t *testing.T) {
+	catalogVersion := 10
+	var requests []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		var response string
+		if strings.HasPrefix(r.URL.Path, "/v1/nodes/") {
+			// the node information of the host reports its catalog version
+			response = `{"node_list": [{"catalog_version": ` + strconv.Itoa(catalogVersion) + `}]}`
+		} else {
+			response = `{"subcluster_list": [], "requests": ` + strconv.Itoa(len(requests)) + `}`
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	assert.NoError(t, err)
+
+	cache := NewResultCache()
+	dispatcher := makeHTTPRequestDispatcher(vlog.Printer{})
+	dispatcher.resultCache = cache
+	dispatcher.setup([]string{serverURL.Hostname()})
+	adapter, ok := dispatcher.pool.connections[serverURL.Hostname()].(*httpAdapter)
+	assert.True(t, ok)
+	password := "password"
+	send := func(method, endpoint string) hostHTTPResult {
+		request := hostHTTPRequest{Method: method, Port: port, Password: &password, Endpoint: endpoint}
+		resultChannel := make(chan hostHTTPResult, 1)
+		adapter.sendRequest(&request, resultChannel)
+		return <-resultChannel
+	}
+
+	// the second request is answered from the cache, once the catalog version
+	// is found unchanged
+	for i := 0; i < 2; i++ {
+		result := send(GetMethod, "v1/subclusters")
+		assert.True(t, result.isPassing())
+		assert.Equal(t, `{"subcluster_list": [], "requests": 2}`, result.content)
+	}
+	assert.Equal(t, 1, cache.Hits())
+	assert.Equal(t, 1, cache.Misses())
+	assert.Equal(t, []string{"/v1/nodes/127.0.0.1", "/v1/subclusters", "/v1/nodes/127.0.0.1"}, requests)
+
+	// the response is fetched again once the catalog changed
+	catalogVersion = 11
+	result := send(GetMethod, "v1/subclusters")
+	assert.Equal(t, `{"subcluster_list": [], "requests": 5}`, result.content)
+	assert.Equal(t, 2, cache.Misses())
+
+	// the node states and other methods are not cached
+	requests = nil
+	send(GetMethod, "v1/nodes")
+	send(PostMethod, "v1/subclusters")
+	assert.Equal(t, []string{"/v1/nodes", "/v1/subclusters"}, requests)
+	assert.Equal(t, 2, cache.Misses())
+
+	cache.Reset()
+	assert.Zero(t, cache.Hits())
+	result = send(GetMethod, "v1/subclusters")
+	assert.Equal(t, `{"subcluster_list": [], "requests": 4}`, result.content)
+	assert.Equal(t, 1, cache.Misses())
+}
+
+func TestResultCacheReadOnlyCommands(t *testing.T) {
+	readOnlyOp := makeMockOp(false)
+	mutatingOp := httpsStopSCOp{}
+	opEngine := makeClusterOpEngine([]clusterOp{&readOnlyOp}, &DatabaseOptions{})
+	assert.False(t, opEngine.hasMutatingOp())
+	opEngine = makeClusterOpEngine([]clusterOp{&readOnlyOp, &mutatingOp}, &DatabaseOptions{})
+	assert.True(t, opEngine.hasMutatingOp())
+}
@@ -0,0 +1,82 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type HostRetries = vtypes.HostRetries
+
+// RetryReport keeps the retries of the requests of commands per host, so that
+// flapping hosts can be spotted when the commands succeed anyway. It is safe
+// to share between commands, which add up the retries of their ops.
+type RetryReport struct {
+	mu    sync.Mutex
+	hosts map[string]HostRetries
+}
+
+func (r *RetryReport) add(opName string, hostRetries map[string]HostRetries) {
+	if len(hostRetries) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hosts == nil {
+		r.hosts = make(map[string]HostRetries)
+	}
+	for host, retries := range hostRetries {
+		total := r.hosts[host]
+		total.Attempts += retries.Attempts
+		total.TotalDelay += retries.TotalDelay
+		for _, reason := range retries.Reasons {
+			total.Reasons = append(total.Reasons, fmt.Sprintf("[%s] %s", opName, reason))
+		}
+		r.hosts[host] = total
+	}
+}
+
+// Hosts returns a copy of the retries collected so far, keyed by host address.
+// Hosts whose requests were never retried are left out.
+func (r *RetryReport) Hosts() map[string]HostRetries {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hosts := make(map[string]HostRetries, len(r.hosts))
+	for host, retries := range r.hosts {
+		retries.Reasons = append([]string(nil), retries.Reasons...)
+		hosts[host] = retries
+	}
+	return hosts
+}
+
+// Reset drops the retries collected so far, e.g., before running the next command
+func (r *RetryReport) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hosts = nil
+}
+
+// addRetry records a retry of a request, the error that caused it, and how
+// long the retry took
+func addRetry(retries *HostRetries, reason error, delay time.Duration) {
+	retries.Attempts++
+	retries.Reasons = append(retries.Reasons, reason.Error())
+	retries.TotalDelay += delay
+}
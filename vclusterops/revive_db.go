@@ -16,11 +16,19 @@
 package vclusterops
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	ReviveOmittedObject            = vtypes.ReviveOmittedObject
+	PartialReviveNotSupportedError = vtypes.PartialReviveNotSupportedError
 )
 
 type VReviveDatabaseOptions struct {
@@ -43,6 +51,13 @@ type VReviveDatabaseOptions struct {
 	Sandbox string
 	// Revive db on main cluster only
 	MainCluster bool
+	// optional, revive only these namespaces and schemas, if the hosts support
+	// it. A schema is qualified by its namespace, e.g., ns1.s1, or is in the
+	// default namespace. The whole database is revived if both are empty.
+	Namespaces []string
+	Schemas    []string
+	// set by a partial revive, the objects that are not revived
+	OmittedObjects []ReviveOmittedObject
 }
 
 type RestorePointPolicy struct {
@@ -91,26 +106,7 @@ func (options *VReviveDatabaseOptions) findSpecifiedRestorePoint(allRestorePoint
 	return "", fmt.Errorf("found %d restore points instead of 1: %+v", len(foundRestorePoints), foundRestorePoints)
 }
 
-// ReviveDBRestorePointNotFoundError is the error that is returned when the retore point specified by the user
-// either via index or id is not found among all restore points in the specified archive. Either InvalidID or
-// InvalidIndex will be set depending on whether the user specified the retore point by index or id.
-type ReviveDBRestorePointNotFoundError struct {
-	Archive      string
-	InvalidID    string
-	InvalidIndex int
-}
-
-func (e *ReviveDBRestorePointNotFoundError) Error() string {
-	var indicator, value string
-	if e.InvalidID != "" {
-		indicator = "ID"
-		value = e.InvalidID
-	} else {
-		indicator = "index"
-		value = fmt.Sprintf("%d", e.InvalidIndex)
-	}
-	return fmt.Sprintf("restore point with %s %s not found in archive %q", indicator, value, e.Archive)
-}
+type ReviveDBRestorePointNotFoundError = vtypes.ReviveDBRestorePointNotFoundError
 
 func VReviveDBOptionsFactory() VReviveDatabaseOptions {
 	options := VReviveDatabaseOptions{}
@@ -155,6 +151,50 @@ func (options *VReviveDatabaseOptions) validateExtraOptions() error {
 			"not both or none")
 	}
 
+	return options.validatePartialRevive()
+}
+
+func (options *VReviveDatabaseOptions) isPartialRevive() bool {
+	return len(options.Namespaces) > 0 || len(options.Schemas) > 0
+}
+
+// validatePartialRevive checks the namespaces and schemas of a partial revive
+func (options *VReviveDatabaseOptions) validatePartialRevive() error {
+	if !options.isPartialRevive() {
+		return nil
+	}
+	if options.Sandbox != util.MainClusterSandbox {
+		return errors.New("cannot revive a subset of the namespaces and schemas of a sandbox")
+	}
+
+	namespaces := make(map[string]bool)
+	for _, namespace := range options.Namespaces {
+		if namespace == "" || strings.Contains(namespace, ".") {
+			return fmt.Errorf("invalid namespace name %q", namespace)
+		}
+		if namespaces[strings.ToLower(namespace)] {
+			return fmt.Errorf("namespace %s is specified more than once", namespace)
+		}
+		namespaces[strings.ToLower(namespace)] = true
+	}
+
+	schemas := make(map[string]bool)
+	for _, schema := range options.Schemas {
+		namespace, name, qualified := strings.Cut(schema, ".")
+		if !qualified {
+			name = namespace
+		}
+		if name == "" || namespace == "" || strings.Contains(name, ".") {
+			return fmt.Errorf("invalid schema name %q", schema)
+		}
+		if qualified && namespaces[strings.ToLower(namespace)] {
+			return fmt.Errorf("schema %s is in namespace %s, which is already revived", schema, namespace)
+		}
+		if schemas[strings.ToLower(schema)] {
+			return fmt.Errorf("schema %s is specified more than once", schema)
+		}
+		schemas[strings.ToLower(schema)] = true
+	}
 	return nil
 }
 
@@ -264,6 +304,9 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 	if err != nil {
 		return dbInfo, &vdb, fmt.Errorf("fail to revive database %w", err)
 	}
+	if options.isPartialRevive() {
+		options.reportOmittedObjects(vcc, clusterOpEngine.execContext.omittedObjects)
+	}
 	nmaVDB := clusterOpEngine.execContext.nmaVDatabase
 	// collect nodes indexed by node name, in case node address has changed.
 	nodeMap := make(map[string]*VCoordinationNode)
@@ -287,6 +330,19 @@ func (vcc VClusterCommands) VReviveDatabase(options *VReviveDatabaseOptions) (db
 	return dbInfo, &vdb, nil
 }
 
+// reportOmittedObjects keeps and displays the objects that a partial revive did not load
+func (options *VReviveDatabaseOptions) reportOmittedObjects(vcc VClusterCommands, omittedObjects []ReviveOmittedObject) {
+	options.OmittedObjects = omittedObjects
+	if len(omittedObjects) == 0 {
+		return
+	}
+	names := make([]string, 0, len(omittedObjects))
+	for _, object := range omittedObjects {
+		names = append(names, fmt.Sprintf("%s %s", object.Type, object.Name))
+	}
+	vcc.DisplayWarning("%d object(s) are not revived: %s", len(omittedObjects), strings.Join(names, ", "))
+}
+
 // revive db instructions are split into two parts:
 // 1. get terminated database info
 // 2. revive database using the info we got from step 1
@@ -346,7 +402,10 @@ func (vcc VClusterCommands) producePreReviveDBInstructions(options *VReviveDatab
 		}
 		// no matter display-only or not, list all restore points for later use
 		hosts := options.Hosts
-		initiator := getInitiator(hosts)
+		initiator, err := options.pickInitiator(hosts)
+		if err != nil {
+			return instructions, err
+		}
 		bootstrapHost := []string{initiator}
 		filterOptions := ShowRestorePointFilterOptions{}
 		filterOptions.ArchiveName = options.RestorePoint.Archive
@@ -428,21 +487,30 @@ func (vcc VClusterCommands) produceReviveDBInstructions(options *VReviveDatabase
 		hostNodeMap[host] = vnode
 	}
 
-	// prepare all directories
+	// prepare all directories, failing early if the OS user cannot write them
+	nmaCheckPathPermissionsOp, err := makeNMACheckPathPermissionsOp(hostNodeMap)
+	if err != nil {
+		return instructions, err
+	}
 	nmaPrepareDirectoriesOp, err := makeNMAPrepareDirectoriesOp(hostNodeMap, options.ForceRemoval, true /*for db revive*/)
 	if err != nil {
 		return instructions, err
 	}
 
 	nmaNetworkProfileOp := makeNMANetworkProfileOp(options.Hosts)
+	loadCatalogTimeout := options.adaptTimeout(int(options.LoadCatalogTimeout), util.DefaultLoadCatalogTimeoutSeconds,
+		len(options.Hosts))
 	nmaLoadRemoteCatalogOp := makeNMALoadRemoteCatalogWithSandboxOp(oldHosts, options.ConfigurationParameters,
-		&newVDB, options.LoadCatalogTimeout, &options.RestorePoint, options.Sandbox)
+		&newVDB, uint(loadCatalogTimeout), &options.RestorePoint, options.Sandbox)
+	nmaLoadRemoteCatalogOp.namespaces = options.Namespaces
+	nmaLoadRemoteCatalogOp.schemas = options.Schemas
 	nmaReadCatEdOp, err := makeNMAReadCatalogEditorOpWithInitiator(initiator, &newVDB)
 	if err != nil {
 		return instructions, err
 	}
 
 	instructions = append(instructions,
+		&nmaCheckPathPermissionsOp,
 		&nmaPrepareDirectoriesOp,
 		&nmaNetworkProfileOp,
 		&nmaLoadRemoteCatalogOp,
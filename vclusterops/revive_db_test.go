@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestFindSpecifiedRestorePoint(t *testing.T) {
@@ -55,3 +56,59 @@ func TestFindSpecifiedRestorePoint(t *testing.T) {
 	expectedErr = &ReviveDBRestorePointNotFoundError{Archive: "archive3", InvalidID: "id3"}
 	assert.EqualError(t, err, expectedErr.Error())
 }
+
+func TestValidatePartialRevive(t *testing.T) {
+	options := VReviveDatabaseOptions{}
+	assert.NoError(t, options.validatePartialRevive())
+
+	options.Namespaces = []string{"ns1", "ns2"}
+	options.Schemas = []string{"ns3.s1", "public"}
+	assert.NoError(t, options.validatePartialRevive())
+
+	// invalid or duplicate names
+	options.Namespaces = []string{"ns1", "NS1"}
+	assert.ErrorContains(t, options.validatePartialRevive(), "namespace NS1 is specified more than once")
+	options.Namespaces = []string{"ns1.s1"}
+	assert.ErrorContains(t, options.validatePartialRevive(), `invalid namespace name "ns1.s1"`)
+	options.Namespaces = []string{"ns1"}
+	options.Schemas = []string{"ns2.s1.t1"}
+	assert.ErrorContains(t, options.validatePartialRevive(), `invalid schema name "ns2.s1.t1"`)
+	// a schema in a namespace that is already revived
+	options.Schemas = []string{"ns1.s1"}
+	assert.ErrorContains(t, options.validatePartialRevive(), "which is already revived")
+
+	// sandboxes are revived whole
+	options.Schemas = nil
+	options.Sandbox = "sand1"
+	assert.ErrorContains(t, options.validatePartialRevive(), "sandbox")
+}
+
+func TestPartialReviveResult(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostList = []string{"192.168.1.101"}
+	vdb.HostNodeMap = vHostNodeMap{"192.168.1.101": &VCoordinationNode{Name: "v_test_db_node0001", IsPrimary: true}}
+	op := makeNMALoadRemoteCatalogOp([]string{"192.168.1.101"}, nil, &vdb, 0, nil)
+	op.namespaces = []string{"ns1"}
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+
+	// the omitted objects are reported by a host that supports partial revive
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"status": 0, "partial_revive": true,
+			"omitted_objects": [{"type": "namespace", "name": "ns2"}]}`},
+	}
+	assert.NoError(t, op.processResult(&execContext))
+	assert.Equal(t, []ReviveOmittedObject{{Type: "namespace", Name: "ns2"}}, execContext.omittedObjects)
+
+	// a host that revived the whole database does not support it
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"status": 0}`},
+	}
+	err := op.processResult(&execContext)
+	notSupportedErr := &PartialReviveNotSupportedError{}
+	assert.ErrorAs(t, err, &notSupportedErr)
+	assert.Equal(t, "192.168.1.101", notSupportedErr.Host)
+
+	// which is fine for a full revive
+	op.namespaces = nil
+	assert.NoError(t, op.processResult(&execContext))
+}
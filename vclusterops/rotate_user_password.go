@@ -0,0 +1,145 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VRotateUserPasswordOptions struct {
+	// basic db info, UserName and Password are the current credentials of
+	// the user that changes the password
+	DatabaseOptions
+	// name of the user whose password is changed, UserName if empty
+	TargetUserName string
+	// new password of the user
+	NewPassword *string
+}
+
+func VRotateUserPasswordOptionsFactory() VRotateUserPasswordOptions {
+	options := VRotateUserPasswordOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VRotateUserPasswordOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(RotateUserPasswordCmd, logger)
+	if err != nil {
+		return err
+	}
+	err = options.validateAuthOptions(RotateUserPasswordCmd.CmdString(), logger)
+	if err != nil {
+		return err
+	}
+
+	if options.TargetUserName != "" {
+		err = util.ValidateUserName(options.TargetUserName)
+		if err != nil {
+			return err
+		}
+	}
+	if options.NewPassword == nil || *options.NewPassword == "" {
+		return fmt.Errorf("must specify a new password")
+	}
+	return nil
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VRotateUserPasswordOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VRotateUserPasswordOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+	if err := options.setUsePassword(logger); err != nil {
+		return err
+	}
+	if err := options.validateUserName(logger); err != nil {
+		return err
+	}
+	if options.TargetUserName == "" {
+		options.TargetUserName = options.UserName
+	}
+	return nil
+}
+
+// VRotateUserPassword changes the password of a database user through the
+// HTTPS service. When the user changes its own password, Password is set to
+// the new password once it is changed, so that the options can be used for
+// the next commands.
+func (vcc VClusterCommands) VRotateUserPassword(options *VRotateUserPasswordOptions) error {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	instructions, err := vcc.produceRotateUserPasswordInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to change the password of user %s: %w", options.TargetUserName, runError)
+	}
+
+	if options.TargetUserName == options.UserName {
+		options.Password = options.NewPassword
+	}
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful rotate_user_password:
+//   - Retrieve VDB from HTTP endpoints
+//   - Change the password of the user on an up host of the main cluster
+func (vcc VClusterCommands) produceRotateUserPasswordInstructions(options *VRotateUserPasswordOptions) ([]clusterOp, error) {
+	initiator, err := vcc.getUpInitiatorFromRunningDB(&options.DatabaseOptions, util.MainClusterSandbox)
+	if err != nil {
+		return nil, err
+	}
+
+	httpsAlterUserPasswordOp, err := makeHTTPSAlterUserPasswordOp([]string{initiator}, options.usePassword,
+		options.UserName, options.Password, options.TargetUserName, options.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	return []clusterOp{&httpsAlterUserPasswordOp}, nil
+}
@@ -0,0 +1,59 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestRotateUserPasswordOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	opt := VRotateUserPasswordOptionsFactory()
+	opt.DBName = testDBName
+	opt.RawHosts = []string{"127.0.0.1"}
+	opt.UserName = "dbadmin"
+	password := "password"
+	opt.Password = &password
+
+	// negative: no new password
+	err := opt.validateAnalyzeOptions(logger)
+	assert.ErrorContains(t, err, "must specify a new password")
+
+	// the user changes its own password by default
+	newPassword := "new-password"
+	opt.NewPassword = &newPassword
+	assert.NoError(t, opt.validateAnalyzeOptions(logger))
+	assert.Equal(t, "dbadmin", opt.TargetUserName)
+
+	op, err := makeHTTPSAlterUserPasswordOp([]string{"127.0.0.1"}, true, opt.UserName, opt.Password,
+		opt.TargetUserName, opt.NewPassword)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"new-password"}`, op.requestBody)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	request := op.clusterHTTPRequest.RequestCollection["127.0.0.1"]
+	assert.Equal(t, PutMethod, request.Method)
+	assert.Equal(t, "v1/users/dbadmin/password", request.Endpoint)
+	assert.Equal(t, &password, request.Password)
+
+	// negative: invalid user name
+	opt.TargetUserName = "svc admin"
+	err = opt.validateParseOptions(logger)
+	assert.ErrorContains(t, err, "invalid character in user name")
+}
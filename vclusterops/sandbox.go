@@ -184,10 +184,16 @@ type sandboxInterface interface {
 
 // runCommand will produce instructions and run them
 func (options *VSandboxOptions) runCommand(vcc VClusterCommands) error {
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, SandboxSCCmd)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
 	// if the users want to do re-ip before sandboxing, we require them
 	// to provide some node information
 	if options.SandboxPrimaryUpHost != "" && len(options.NodeNameAddressMap) > 0 {
-		err := vcc.reIP(&options.DatabaseOptions, options.SCName, options.SandboxPrimaryUpHost,
+		err = vcc.reIP(&options.DatabaseOptions, options.SCName, options.SandboxPrimaryUpHost,
 			options.NodeNameAddressMap, true /*reload spread*/)
 		if err != nil {
 			return err
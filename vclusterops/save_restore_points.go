@@ -163,7 +163,11 @@ func (vcc VClusterCommands) produceSaveRestorePointsInstructions(options *VSaveR
 	nmaHealthOp := makeNMAHealthOp(options.Hosts)
 	// Trim host list
 	hosts = vdb.filterUpHostlist(hosts, options.Sandbox)
-	bootstrapHost := []string{getInitiator(hosts)}
+	initiator, err := options.pickInitiator(hosts)
+	if err != nil {
+		return instructions, err
+	}
+	bootstrapHost := []string{initiator}
 
 	requestData := saveRestorePointsRequestData{}
 	requestData.ArchiveName = options.ArchiveName
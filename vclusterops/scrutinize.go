@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
@@ -35,11 +36,13 @@ const scrutinizeLogFileName = "vcluster.log"
 const ScrutinizeLogMaxAgeHoursDefault = 24              // copy archived logs produced in most recent 24 hours
 const scrutinizeLogLimitBytes = 10 * 1024 * 1024 * 1024 // 10GB in bytes is the limit for individual log size
 const scrutinizeFileLimitBytes = 100 * 1024 * 1024      // 100 MB in bytes is the limit for individual misc file size
+const scrutinizePodLogLimitBytes = 100 * 1024 * 1024    // 100 MB in bytes is the limit for individual pod log size
 
 // batches are fixed, top level folders for each node's data
 const scrutinizeBatchNormal = "normal"
 const scrutinizeBatchContext = "context"
 const scrutinizeBatchSystemTables = "system_tables"
+const scrutinizeBatchContainer = "container"
 const scrutinizeSuffixSystemTables = "systables"
 
 type VScrutinizeOptions struct {
@@ -52,13 +55,30 @@ type VScrutinizeOptions struct {
 	IncludeExternalTableDetails bool
 	IncludeUDXDetails           bool
 	SkipCollectLibs             bool
+	// collect pod logs, resource limits, and OOM events into the container batch,
+	// for databases that run in Kubernetes
+	IncludeContainerDiagnostics bool
 	LogAgeOldestTime            string
 	LogAgeNewestTime            string
 	LogAgeHours                 int // max log age from input
-
-	timeFormats    []util.TimeFormat // generated by factory
-	logAgeMaxHours int               // calculated from exported log age options
-	logAgeMinHours int               // calculated from exported log age options
+	// pseudonymize the hosts, IP addresses, database and user names, and the
+	// extra terms in all collected files, so that the bundle can be shared
+	Anonymize      bool
+	AnonymizeTerms []string
+	// optional, the file to save the pseudonyms to, which must not be shared
+	AnonymizationMapPath string
+	// delete the staging data of the collections older than
+	// StagingRetentionHours from the hosts once the collection is done
+	CleanupStagingData    bool
+	StagingRetentionHours int
+	// the sandboxes whose nodes are collected in the same run as the main
+	// cluster, which is the only one collected by default
+	Sandboxes []string
+
+	manifest       *ScrutinizeManifest // generated by VScrutinize
+	timeFormats    []util.TimeFormat   // generated by factory
+	logAgeMaxHours int                 // calculated from exported log age options
+	logAgeMinHours int                 // calculated from exported log age options
 }
 
 func VScrutinizeOptionsFactory() VScrutinizeOptions {
@@ -74,6 +94,7 @@ func (options *VScrutinizeOptions) setDefaultValues() {
 	options.DatabaseOptions.setDefaultValues()
 
 	options.ID = generateScrutinizeID()
+	options.StagingRetentionHours = ScrutinizeStagingRetentionHoursDefault
 
 	// if these are changed, the help format string must also be changed
 	noTZFormat := util.TimeFormat{Layout: "2006-01-02 15", UseLocalTZ: true}
@@ -146,6 +167,10 @@ func (options *VScrutinizeOptions) validateRequiredOptions(logger vlog.Printer)
 		return err
 	}
 
+	if options.CleanupStagingData && options.StagingRetentionHours < 0 {
+		return fmt.Errorf("staging retention hours must not be negative")
+	}
+
 	// RawHosts is already required by the cmd parser, so no need to check here
 	// check if catalog prefix in user input is correct
 	return options.validateCatalogPath()
@@ -195,6 +220,10 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 		vcc.Log.Error(err, "validation of scrutinize arguments failed")
 		return err
 	}
+	err = vcc.selectHosts(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
 
 	// populate vdb with:
 	// 1. slice of nodes with NMA running
@@ -205,8 +234,13 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 		vcc.Log.Error(err, "failed to retrieve cluster info for scrutinize")
 		return err
 	}
+	err = vcc.selectScrutinizeSandboxes(options, &vdb)
+	if err != nil {
+		return err
+	}
 	// from now on, use hosts with healthy NMA
 	options.Hosts = vdb.HostList
+	options.manifest = makeScrutinizeManifest(options.ID, options.DBName, &vdb)
 
 	// prepare main instructions
 	instructions, err := vcc.produceScrutinizeInstructions(options, &vdb)
@@ -215,7 +249,12 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 		return err
 	}
 	err = options.runClusterOpEngine(vcc.Log, instructions)
-	if err != nil {
+	budgetErr := &CommandBudgetExceededError{}
+	if errors.As(err, &budgetErr) {
+		// the batches collected before the budget ran out are still bundled
+		vcc.Log.PrintWarning("Scrutinize stopped collecting batches: %s", budgetErr.Error())
+		options.manifest.BudgetExceeded = budgetErr.Error()
+	} else if err != nil {
 		vcc.Log.Error(err, "failed to run scrutinize operations")
 		return err
 	}
@@ -223,6 +262,18 @@ func (vcc VClusterCommands) VScrutinize(options *VScrutinizeOptions) error {
 	// add vcluster log to output
 	options.stageVclusterLog(options.ID, vcc.Log)
 
+	// add the manifest of the bundle to output
+	if err = options.manifest.write(); err != nil {
+		vcc.Log.PrintWarning("Unable to write scrutinize manifest: %s", err.Error())
+	}
+
+	if options.Anonymize {
+		if err = options.anonymizeOutput(vcc.Log); err != nil {
+			vcc.Log.Error(err, "failed to anonymize scrutinize output")
+			return err
+		}
+	}
+
 	// tar all results
 	if err = tarAndRemoveDirectory(options.TarballName, options.ID, vcc.Log); err != nil {
 		vcc.Log.Error(err, "failed to create final scrutinize output tarball")
@@ -255,6 +306,24 @@ func (options *VScrutinizeOptions) stageVclusterLog(id string, log vlog.Printer)
 	}
 }
 
+// anonymizeOutput pseudonymizes the collected files before they are packaged.
+// The files are not packaged if any of them cannot be anonymized.
+func (options *VScrutinizeOptions) anonymizeOutput(log vlog.Printer) error {
+	anonymizer := makeScrutinizeAnonymizer(options)
+	err := anonymizer.anonymizeOutput(options.ID)
+	if err != nil {
+		return err
+	}
+	if options.AnonymizationMapPath != "" {
+		err = anonymizer.writeMapping(options.AnonymizationMapPath)
+		if err != nil {
+			return fmt.Errorf("fail to save pseudonyms to %s, details: %w", options.AnonymizationMapPath, err)
+		}
+		log.PrintInfo("Pseudonyms of the scrutinize bundle saved to %s", options.AnonymizationMapPath)
+	}
+	return nil
+}
+
 // tarAndRemoveDirectory packages the final scrutinize output.
 func tarAndRemoveDirectory(tarballName, id string, log vlog.Printer) (err error) {
 	tarballPath := ScrutinizeOutputBasePath + "/" + tarballName + ".tar"
@@ -304,6 +373,47 @@ func (options *VScrutinizeOptions) getVDBForScrutinize(logger vlog.Printer,
 	return nil
 }
 
+// selectScrutinizeSandboxes records the sandbox of each node in vdb, and
+// removes the nodes of the sandboxes that are not collected. The sandboxes are
+// only known when the database is running, otherwise all the nodes are
+// collected.
+func (vcc VClusterCommands) selectScrutinizeSandboxes(options *VScrutinizeOptions, vdb *VCoordinationDatabase) error {
+	runningVDB := makeVCoordinationDatabase()
+	err := vcc.getVDBFromMainRunningDBContainsSandbox(&runningVDB, &options.DatabaseOptions)
+	if err != nil {
+		if len(options.Sandboxes) > 0 {
+			return fmt.Errorf("fail to retrieve the sandboxes of the nodes to scrutinize, %w", err)
+		}
+		vcc.Log.PrintWarning("Unable to retrieve the sandboxes of the nodes, scrutinize collects from all the hosts: %s",
+			err.Error())
+		return nil
+	}
+
+	found := make(map[string]bool)
+	vdb.HostList = []string{}
+	for host, vnode := range vdb.HostNodeMap {
+		if runningNode, ok := runningVDB.HostNodeMap[host]; ok {
+			vnode.Sandbox = runningNode.Sandbox
+		}
+		if vnode.Sandbox != util.MainClusterSandbox && !slices.Contains(options.Sandboxes, vnode.Sandbox) {
+			vcc.Log.Info("skip the node of a sandbox that is not collected", "host", host, "sandbox", vnode.Sandbox)
+			delete(vdb.HostNodeMap, host)
+			continue
+		}
+		found[vnode.Sandbox] = true
+		vdb.HostList = append(vdb.HostList, host)
+	}
+	for _, sandbox := range options.Sandboxes {
+		if !found[sandbox] {
+			return fmt.Errorf("no nodes of sandbox %s are among the hosts to scrutinize", sandbox)
+		}
+	}
+	if len(vdb.HostList) == 0 {
+		return fmt.Errorf("no hosts to scrutinize are left once the nodes of the sandboxes are skipped")
+	}
+	return nil
+}
+
 // produceScrutinizeInstructions will build a list of instructions to execute for
 // the scrutinize operation, after preliminary configuration retrieval ops.
 //
@@ -318,8 +428,10 @@ func (options *VScrutinizeOptions) getVDBForScrutinize(logger vlog.Printer,
 //   - Stage DC tables on all nodes
 //   - Tar and retrieve vertica logs and DC tables from all nodes (batch normal)
 //   - Tar and retrieve error report from all nodes (batch context)
+//   - (If applicable) Stage, tar, and retrieve container diagnostics from all nodes (batch container)
 //   - (If applicable) Poll for system table staging completion on task node
 //   - (If applicable) Tar and retrieve system tables from task node (batch system_tables)
+//   - (If applicable) Delete stale staging data from all nodes
 func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOptions,
 	vdb *VCoordinationDatabase) (instructions []clusterOp, err error) {
 	// extract needed info from vdb
@@ -328,8 +440,13 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 		return nil, fmt.Errorf("failed to process retrieved node info, details %w", err)
 	}
 
-	// Get up database nodes for the system table task
-	getUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, options.Hosts,
+	// Get up database nodes for the system table task, which is run in the
+	// main cluster if any of its nodes are collected
+	systemTableHosts := getMainClusterHosts(options.Hosts, vdb)
+	if len(systemTableHosts) == 0 {
+		systemTableHosts = options.Hosts
+	}
+	getUpNodesOp, err := makeHTTPSGetUpNodesOp(options.DBName, systemTableHosts,
 		options.usePassword, options.UserName, options.Password, ScrutinizeCmd)
 	if err != nil {
 		return nil, err
@@ -337,6 +454,13 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 	getUpNodesOp.allowNoUpHosts()
 	instructions = append(instructions, &getUpNodesOp)
 
+	// fail early if the staging directory cannot be written by the OS user
+	checkStagingPermissionsOp, err := makeNMACheckScrutinizeStagingPermissionsOp(options.ID, options.Hosts)
+	if err != nil {
+		return nil, err
+	}
+	instructions = append(instructions, &checkStagingPermissionsOp)
+
 	stageSystemTablesInstructions, err := getStageSystemTablesInstructions(vcc.Log, options, hostNodeNameMap)
 	if err != nil {
 		return nil, err
@@ -391,6 +515,7 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 	if err != nil {
 		return nil, err
 	}
+	getNormalTarballOp.useManifest(options.manifest)
 	instructions = append(instructions, &getNormalTarballOp)
 
 	// get 'context' batch tarball (inc. 'context' batch files)
@@ -399,8 +524,17 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 	if err != nil {
 		return nil, err
 	}
+	getContextTarballOp.useManifest(options.manifest)
 	instructions = append(instructions, &getContextTarballOp)
 
+	if options.IncludeContainerDiagnostics {
+		containerInstructions, err := getContainerDiagnosticsInstructions(vcc.Log, options, hostNodeNameMap, hostCatPathMap)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, containerInstructions...)
+	}
+
 	// get 'system_tables' batch tarball last, as staging systables can take a long time
 	getSystemTablesTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchSystemTables,
 		options.Hosts, hostNodeNameMap)
@@ -408,11 +542,32 @@ func (vcc VClusterCommands) produceScrutinizeInstructions(options *VScrutinizeOp
 		return nil, err
 	}
 	getSystemTablesTarballOp.useSingleHost()
+	getSystemTablesTarballOp.useManifest(options.manifest)
 	instructions = append(instructions, &getSystemTablesTarballOp)
 
+	// delete stale staging data once everything is retrieved
+	if options.CleanupStagingData {
+		cleanupScrutinizeOp, err := makeNMACleanupScrutinizeOp(options.Hosts, options.StagingRetentionHours,
+			make(map[string]ScrutinizeCleanup))
+		if err != nil {
+			return nil, err
+		}
+		cleanupScrutinizeOp.useBestEffort()
+		instructions = append(instructions, &cleanupScrutinizeOp)
+	}
+
 	return instructions, nil
 }
 
+func getMainClusterHosts(hosts []string, vdb *VCoordinationDatabase) (mainHosts []string) {
+	for _, host := range hosts {
+		if vnode := vdb.HostNodeMap[host]; vnode != nil && vnode.Sandbox == util.MainClusterSandbox {
+			mainHosts = append(mainHosts, host)
+		}
+	}
+	return mainHosts
+}
+
 func getNodeInfoForScrutinize(hosts []string, vdb *VCoordinationDatabase,
 ) (hostNodeNameMap, hostCatPathMap map[string]string, err error) {
 	hostNodeNameMap = make(map[string]string)
@@ -442,6 +597,38 @@ func getNodeInfoForScrutinize(hosts []string, vdb *VCoordinationDatabase,
 	return hostNodeNameMap, hostCatPathMap, allErrors
 }
 
+// getContainerDiagnosticsInstructions stages container runtime diagnostics on all
+// nodes and retrieves them as the 'container' batch
+func getContainerDiagnosticsInstructions(logger vlog.Printer, options *VScrutinizeOptions,
+	hostNodeNameMap, hostCatPathMap map[string]string) ([]clusterOp, error) {
+	stageContainerDiagnosticsOp, err := makeNMAStageContainerDiagnosticsOp(logger, options.ID,
+		options.Hosts, hostNodeNameMap, hostCatPathMap, scrutinizePodLogLimitBytes, options.logAgeMaxHours)
+	if err != nil {
+		return nil, err
+	}
+
+	getContainerTarballOp, err := makeNMAGetScrutinizeTarOp(options.ID, scrutinizeBatchContainer,
+		options.Hosts, hostNodeNameMap)
+	if err != nil {
+		return nil, err
+	}
+	getContainerTarballOp.useBestEffort()
+	getContainerTarballOp.useManifest(options.manifest)
+
+	return []clusterOp{&stageContainerDiagnosticsOp, &getContainerTarballOp}, nil
+}
+
+// makeNMACheckScrutinizeStagingPermissionsOp checks that the staging
+// directory of the scrutinize run can be written on each host
+func makeNMACheckScrutinizeStagingPermissionsOp(id string, hosts []string) (nmaCheckPathPermissionsOp, error) {
+	stagingDir := fmt.Sprintf("%s/%s", scrutinizeRemoteOutputPath, id)
+	hostPathsMap := make(map[string][]string, len(hosts))
+	for _, host := range hosts {
+		hostPathsMap[host] = []string{stagingDir}
+	}
+	return makeNMACheckPathPermissionsOpWithPaths(hostPathsMap)
+}
+
 func getStageSystemTablesInstructions(logger vlog.Printer, options *VScrutinizeOptions, hostNodeNameMap map[string]string,
 ) (instructions []clusterOp, err error) {
 	// Prepare directories for scrutinize staging system tables
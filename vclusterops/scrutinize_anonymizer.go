@@ -0,0 +1,339 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// kinds of identifiers that are pseudonymized, with the prefix of their pseudonyms
+const (
+	anonymizedDBPrefix   = "db_"
+	anonymizedUserPrefix = "user_"
+	anonymizedHostPrefix = "host-"
+	anonymizedTermPrefix = "term_"
+)
+
+// an IPv4 address, IPv6 addresses are pseudonymized when they are hosts of the database
+const ipv4Pattern = `(?:[0-9]{1,3}\.){3}[0-9]{1,3}`
+
+// the text right before a version number, e.g., "version: 24.1.0.0", which
+// looks like an IPv4 address
+var versionPrefixPattern = regexp.MustCompile(`(?i)(?:version|release|build)\s*[:=]?\s*$`)
+
+// scrutinizeAnonymizer consistently replaces the identifiers of a database, e.g.,
+// its hosts, IP addresses, database and user names, with pseudonyms, so that
+// the same identifier has the same pseudonym in all files of a bundle
+type scrutinizeAnonymizer struct {
+	pattern *regexp.Regexp
+	// known identifiers, keyed by lowercase, to their kind
+	kinds map[string]string
+	// identifiers, keyed by lowercase, to their pseudonyms
+	pseudonyms map[string]string
+	counters   map[string]int
+}
+
+func makeScrutinizeAnonymizer(options *VScrutinizeOptions) *scrutinizeAnonymizer {
+	anonymizer := &scrutinizeAnonymizer{
+		kinds:      make(map[string]string),
+		pseudonyms: make(map[string]string),
+		counters:   make(map[string]int),
+	}
+	anonymizer.addTerms(anonymizedDBPrefix, options.DBName)
+	anonymizer.addTerms(anonymizedUserPrefix, options.UserName)
+	anonymizer.addTerms(anonymizedHostPrefix, options.RawHosts...)
+	anonymizer.addTerms(anonymizedHostPrefix, options.Hosts...)
+	anonymizer.addTerms(anonymizedTermPrefix, options.AnonymizeTerms...)
+
+	// match longer identifiers first, so that an identifier is not replaced
+	// when it is only a part of another one
+	terms := make([]string, 0, len(anonymizer.kinds))
+	for term := range anonymizer.kinds {
+		terms = append(terms, regexp.QuoteMeta(term))
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+	terms = append(terms, ipv4Pattern)
+	anonymizer.pattern = regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+	return anonymizer
+}
+
+func (a *scrutinizeAnonymizer) addTerms(kind string, terms ...string) {
+	for _, term := range terms {
+		term = strings.ToLower(term)
+		if term == "" {
+			continue
+		}
+		if _, exist := a.kinds[term]; !exist {
+			a.kinds[term] = kind
+		}
+	}
+}
+
+// getPseudonym returns the pseudonym of an identifier, and creates one the
+// first time the identifier is seen
+func (a *scrutinizeAnonymizer) getPseudonym(identifier string) string {
+	key := strings.ToLower(identifier)
+	if pseudonym, ok := a.pseudonyms[key]; ok {
+		return pseudonym
+	}
+	ip := net.ParseIP(key)
+	kind := a.kinds[key]
+	if ip != nil {
+		kind = "ip"
+	}
+	a.counters[kind]++
+	n := a.counters[kind]
+
+	var pseudonym string
+	switch {
+	case ip != nil && ip.To4() != nil:
+		const byteMask = 0xff
+		pseudonym = fmt.Sprintf("10.%d.%d.%d", (n>>16)&byteMask, (n>>8)&byteMask, n&byteMask)
+	case ip != nil:
+		pseudonym = fmt.Sprintf("fd00::%x", n)
+	default:
+		pseudonym = fmt.Sprintf("%s%04d", kind, n)
+	}
+	a.pseudonyms[key] = pseudonym
+	return pseudonym
+}
+
+func isAlphanumeric(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || isDigit(b)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// isVersionNumber returns whether a match of ipv4Pattern in text is a version
+// number rather than an address: it follows a version keyword, or it is a part
+// of a longer number, e.g., 24.1.0.0.1 or 24.1.0.0-1
+func isVersionNumber(text []byte, start, end int) bool {
+	if start >= 2 && text[start-1] == '.' && isDigit(text[start-2]) {
+		return true
+	}
+	if end+1 < len(text) && (text[end] == '.' || text[end] == '-') && isDigit(text[end+1]) {
+		return true
+	}
+	const maxPrefixLen = 16
+	return versionPrefixPattern.Match(text[max(0, start-maxPrefixLen):start])
+}
+
+// anonymize replaces the identifiers in a piece of text. An identifier is only
+// replaced when it is not surrounded by letters or digits, e.g., the database
+// name is replaced in node names, but not in the middle of other words.
+func (a *scrutinizeAnonymizer) anonymize(text []byte) []byte {
+	matches := a.pattern.FindAllIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var result bytes.Buffer
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if (start > 0 && isAlphanumeric(text[start-1])) || (end < len(text) && isAlphanumeric(text[end])) {
+			continue
+		}
+		identifier := string(text[start:end])
+		if _, known := a.kinds[strings.ToLower(identifier)]; !known &&
+			(net.ParseIP(identifier) == nil || isVersionNumber(text, start, end)) {
+			// not a valid IPv4 address, e.g., a version number
+			continue
+		}
+		result.Write(text[last:start])
+		result.WriteString(a.getPseudonym(identifier))
+		last = end
+	}
+	result.Write(text[last:])
+	return result.Bytes()
+}
+
+// anonymizeStream copies the lines of a file, with the identifiers replaced
+func (a *scrutinizeAnonymizer) anonymizeStream(dst io.Writer, src io.Reader) error {
+	reader := bufio.NewReader(src)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if _, writeErr := dst.Write(a.anonymize(line)); writeErr != nil {
+				return writeErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// anonymizeTarball rewrites a gzipped tarball, with the identifiers replaced in
+// the names and contents of its files. The files are anonymized line by line
+// to a temporary file in tmpDir, as the size of a file in the tarball changes
+// with its content.
+func (a *scrutinizeAnonymizer) anonymizeTarball(dst io.Writer, src io.Reader, tmpDir string) error {
+	gzipReader, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+	gzipWriter := gzip.NewWriter(dst)
+	tarReader := tar.NewReader(gzipReader)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		header.Name = string(a.anonymize([]byte(header.Name)))
+		header.Linkname = string(a.anonymize([]byte(header.Linkname)))
+		if header.Typeflag != tar.TypeReg {
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := a.anonymizeTarEntry(tarWriter, header, tarReader, tmpDir); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// anonymizeTarEntry writes a file of a tarball with its content anonymized,
+// through a temporary file, so that the size of the file is known before its
+// content is written
+func (a *scrutinizeAnonymizer) anonymizeTarEntry(tarWriter *tar.Writer, header *tar.Header,
+	content io.Reader, tmpDir string) error {
+	tmpFile, err := os.CreateTemp(tmpDir, "anonymize-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+	}()
+
+	writer := bufio.NewWriter(tmpFile)
+	if err := a.anonymizeStream(writer, content); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	size, err := tmpFile.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	header.Size = size
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tarWriter, tmpFile)
+	return err
+}
+
+// anonymizeFile replaces a file in the output directory with its anonymized
+// copy, under an anonymized name
+func (a *scrutinizeAnonymizer) anonymizeFile(dir, fileName string) error {
+	srcPath := path.Join(dir, fileName)
+	dstPath := path.Join(dir, string(a.anonymize([]byte(fileName)))+".anonymized")
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	const anonymizedFilePerms = 0600
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, anonymizedFilePerms)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(fileName, ".tgz") {
+		err = a.anonymizeTarball(dst, src, dir)
+	} else {
+		err = a.anonymizeStream(dst, src)
+	}
+	err = errors.Join(err, dst.Close())
+	if err != nil {
+		return fmt.Errorf("fail to anonymize %s, details: %w", srcPath, err)
+	}
+
+	if err := os.Remove(srcPath); err != nil {
+		return err
+	}
+	return os.Rename(dstPath, strings.TrimSuffix(dstPath, ".anonymized"))
+}
+
+// anonymizeOutput anonymizes all collected files of a scrutinize run
+func (a *scrutinizeAnonymizer) anonymizeOutput(id string) error {
+	dir := path.Join(scrutinizeRemoteOutputPath, id)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := a.anonymizeFile(dir, entry.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMapping saves the pseudonyms, so that the owner of the database can
+// map the findings on an anonymized bundle back to the database. The mapping
+// must not be shared with the bundle.
+func (a *scrutinizeAnonymizer) writeMapping(mappingPath string) error {
+	data, err := json.MarshalIndent(a.pseudonyms, "", "  ")
+	if err != nil {
+		return err
+	}
+	const mappingFilePerms = 0600
+	return os.WriteFile(mappingPath, data, mappingFilePerms)
+}
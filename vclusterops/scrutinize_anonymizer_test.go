@@ -0,0 +1,93 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestAnonymizer() *scrutinizeAnonymizer {
+	options := VScrutinizeOptionsFactory()
+	options.DBName = "test_db"
+	options.UserName = "dbadmin"
+	options.RawHosts = []string{"vertica-0.example.com"}
+	options.Hosts = []string{"192.168.1.101"}
+	options.AnonymizeTerms = []string{"acme"}
+	return makeScrutinizeAnonymizer(&options)
+}
+
+func TestAnonymize(t *testing.T) {
+	anonymizer := makeTestAnonymizer()
+
+	line := "node v_test_db_node0001 of TEST_DB at 192.168.1.101 (vertica-0.example.com), user dbadmin of acme\n"
+	assert.Equal(t, "node v_db_0001_node0001 of db_0001 at 10.0.0.1 (host-0001), user user_0001 of term_0001\n",
+		string(anonymizer.anonymize([]byte(line))))
+
+	// pseudonyms are consistent, and new IP addresses get new pseudonyms
+	assert.Equal(t, "10.0.0.1 10.0.0.2 10.0.0.1",
+		string(anonymizer.anonymize([]byte("192.168.1.101 192.168.1.102 192.168.1.101"))))
+
+	// identifiers inside other words, and version numbers, are kept
+	assert.Equal(t, "latest_dbs acmecorp 24.1.0.1234",
+		string(anonymizer.anonymize([]byte("latest_dbs acmecorp 24.1.0.1234"))))
+	for _, line := range []string{"Vertica version 24.1.0.0", "version: 24.1.0.0", "build 24.1.0.0-1",
+		"24.1.0.0.1"} {
+		assert.Equal(t, line, string(anonymizer.anonymize([]byte(line))))
+	}
+	assert.Equal(t, "host 10.0.0.3", string(anonymizer.anonymize([]byte("host 24.1.0.0"))))
+}
+
+func TestAnonymizeTarball(t *testing.T) {
+	anonymizer := makeTestAnonymizer()
+
+	var src bytes.Buffer
+	gzipWriter := gzip.NewWriter(&src)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content := "Connected to test_db at 192.168.1.101\n"
+	assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "v_test_db_node0001/vertica.log",
+		Typeflag: tar.TypeReg, Mode: 0600, Size: int64(len(content))}))
+	_, err := tarWriter.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+
+	var dst bytes.Buffer
+	tmpDir := t.TempDir()
+	assert.NoError(t, anonymizer.anonymizeTarball(&dst, &src, tmpDir))
+
+	gzipReader, err := gzip.NewReader(&dst)
+	assert.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	header, err := tarReader.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "v_db_0001_node0001/vertica.log", header.Name)
+	anonymizedContent, err := io.ReadAll(tarReader)
+	assert.NoError(t, err)
+	assert.Equal(t, "Connected to db_0001 at 10.0.0.1\n", string(anonymizedContent))
+	assert.Equal(t, int64(len(anonymizedContent)), header.Size)
+
+	// the temporary files are removed
+	tmpFiles, err := os.ReadDir(tmpDir)
+	assert.NoError(t, err)
+	assert.Empty(t, tmpFiles)
+}
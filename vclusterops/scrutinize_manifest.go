@@ -0,0 +1,190 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const ScrutinizeManifestVersion = vtypes.ScrutinizeManifestVersion
+
+// ScrutinizeManifestFileName is the name of the manifest in the top level
+// folder of a scrutinize bundle
+const ScrutinizeManifestFileName = "manifest.json"
+
+// ScrutinizeManifest describes the content of a scrutinize bundle, so that
+// tools can analyze bundles without knowing how they are laid out
+type ScrutinizeManifest struct {
+	Version   int                       `json:"version"`
+	ID        string                    `json:"id"`
+	DBName    string                    `json:"db_name"`
+	StartTime time.Time                 `json:"start_time"`
+	EndTime   time.Time                 `json:"end_time"`
+	Nodes     []ScrutinizeManifestNode  `json:"nodes"`
+	Batches   []ScrutinizeManifestBatch `json:"batches"`
+	Errors    []ScrutinizeManifestError `json:"errors"`
+	// why the collection stopped before all the batches were collected, if
+	// the budget of the command ran out
+	BudgetExceeded string `json:"budget_exceeded,omitempty"`
+}
+
+// ScrutinizeManifestNode is a node of the cluster snapshot in a manifest
+type ScrutinizeManifestNode struct {
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	Subcluster  string `json:"subcluster"`
+	CatalogPath string `json:"catalog_path"`
+	IsPrimary   bool   `json:"is_primary"`
+	// empty for the nodes of the main cluster
+	Sandbox string `json:"sandbox,omitempty"`
+}
+
+// ScrutinizeManifestBatch is a batch tarball of a node in a manifest. The file
+// path is relative to the top level folder of the bundle.
+type ScrutinizeManifestBatch struct {
+	Batch     string        `json:"batch"`
+	NodeName  string        `json:"node_name"`
+	Host      string        `json:"host"`
+	FilePath  string        `json:"file_path"`
+	SizeBytes int64         `json:"size_bytes"`
+	Duration  time.Duration `json:"duration_ns"`
+}
+
+// ScrutinizeManifestError is a batch that could not be collected from a node
+type ScrutinizeManifestError struct {
+	Batch    string `json:"batch"`
+	NodeName string `json:"node_name"`
+	Host     string `json:"host"`
+	Message  string `json:"message"`
+}
+
+type UnsupportedManifestVersionError = vtypes.UnsupportedManifestVersionError
+
+func makeScrutinizeManifest(id, dbName string, vdb *VCoordinationDatabase) *ScrutinizeManifest {
+	manifest := ScrutinizeManifest{
+		Version:   ScrutinizeManifestVersion,
+		ID:        id,
+		DBName:    dbName,
+		StartTime: time.Now().UTC(),
+	}
+	for _, vnode := range vdb.HostNodeMap {
+		manifest.Nodes = append(manifest.Nodes, ScrutinizeManifestNode{
+			Name:        vnode.Name,
+			Address:     vnode.Address,
+			Subcluster:  vnode.Subcluster,
+			CatalogPath: vnode.CatalogPath,
+			IsPrimary:   vnode.IsPrimary,
+			Sandbox:     vnode.Sandbox,
+		})
+	}
+	sort.Slice(manifest.Nodes, func(i, j int) bool {
+		return manifest.Nodes[i].Name < manifest.Nodes[j].Name
+	})
+	return &manifest
+}
+
+// addBatch records a batch tarball that is downloaded to the output directory
+func (m *ScrutinizeManifest) addBatch(batch, nodeName, host, filePath string, duration time.Duration) {
+	entry := ScrutinizeManifestBatch{
+		Batch:    batch,
+		NodeName: nodeName,
+		Host:     host,
+		FilePath: path.Base(filePath),
+		Duration: duration,
+	}
+	if info, err := os.Stat(filePath); err == nil {
+		entry.SizeBytes = info.Size()
+	}
+	m.Batches = append(m.Batches, entry)
+}
+
+func (m *ScrutinizeManifest) addError(batch, nodeName, host string, err error) {
+	m.Errors = append(m.Errors, ScrutinizeManifestError{
+		Batch:    batch,
+		NodeName: nodeName,
+		Host:     host,
+		Message:  err.Error(),
+	})
+}
+
+// write saves the manifest in the output directory of a scrutinize run
+func (m *ScrutinizeManifest) write() error {
+	m.EndTime = time.Now().UTC()
+	sort.SliceStable(m.Batches, func(i, j int) bool {
+		return m.Batches[i].FilePath < m.Batches[j].FilePath
+	})
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to marshal the scrutinize manifest, details: %w", err)
+	}
+	const manifestFilePerms = 0600
+	manifestPath := fmt.Sprintf("%s/%s/%s", scrutinizeRemoteOutputPath, m.ID, ScrutinizeManifestFileName)
+	return os.WriteFile(manifestPath, data, manifestFilePerms)
+}
+
+// ParseScrutinizeManifest parses the manifest of a scrutinize bundle
+func ParseScrutinizeManifest(data []byte) (*ScrutinizeManifest, error) {
+	manifest := ScrutinizeManifest{}
+	err := json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse the scrutinize manifest, details: %w", err)
+	}
+	if manifest.Version <= 0 {
+		return nil, fmt.Errorf("the scrutinize manifest has no version")
+	}
+	if manifest.Version > ScrutinizeManifestVersion {
+		return nil, &UnsupportedManifestVersionError{Version: manifest.Version}
+	}
+	return &manifest, nil
+}
+
+// ReadScrutinizeManifest reads the manifest from a scrutinize tarball
+func ReadScrutinizeManifest(tarballPath string) (*ScrutinizeManifest, error) {
+	tarball, err := os.Open(tarballPath)
+	if err != nil {
+		return nil, err
+	}
+	defer tarball.Close()
+
+	reader := tar.NewReader(tarball)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("scrutinize tarball %s has no manifest", tarballPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fail to read scrutinize tarball %s, details: %w", tarballPath, err)
+		}
+		if path.Base(header.Name) != ScrutinizeManifestFileName {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read the manifest in %s, details: %w", tarballPath, err)
+		}
+		return ParseScrutinizeManifest(data)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrutinizeManifest(t *testing.T) {
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = makeVHostNodeMap()
+	vdb.HostNodeMap["192.168.1.102"] = &VCoordinationNode{Name: "v_test_db_node0002", Address: "192.168.1.102",
+		Sandbox: "sand"}
+	vdb.HostNodeMap["192.168.1.101"] = &VCoordinationNode{Name: "v_test_db_node0001", Address: "192.168.1.101",
+		IsPrimary: true}
+	manifest := makeScrutinizeManifest("VerticaScrutinize.20240101000000", "test_db", &vdb)
+	assert.Equal(t, ScrutinizeManifestVersion, manifest.Version)
+	assert.Equal(t, "v_test_db_node0001", manifest.Nodes[0].Name)
+	assert.True(t, manifest.Nodes[0].IsPrimary)
+	// the nodes of a sandbox are recorded with their sandbox
+	assert.Equal(t, "", manifest.Nodes[0].Sandbox)
+	assert.Equal(t, "sand", manifest.Nodes[1].Sandbox)
+	assert.Equal(t, []string{"192.168.1.101"}, getMainClusterHosts([]string{"192.168.1.101", "192.168.1.102"}, &vdb))
+
+	// the size of a batch is the size of its downloaded tarball
+	tarballPath := path.Join(t.TempDir(), "v_test_db_node0001-normal.tgz")
+	assert.NoError(t, os.WriteFile(tarballPath, []byte("tarball"), 0600))
+	manifest.addBatch(scrutinizeBatchNormal, "v_test_db_node0001", "192.168.1.101", tarballPath, time.Second)
+	manifest.addError(scrutinizeBatchNormal, "v_test_db_node0002", "192.168.1.102", errors.New("timed out"))
+	assert.Equal(t, ScrutinizeManifestBatch{Batch: "normal", NodeName: "v_test_db_node0001", Host: "192.168.1.101",
+		FilePath: "v_test_db_node0001-normal.tgz", SizeBytes: 7, Duration: time.Second}, manifest.Batches[0])
+
+	// the manifest is read back from a bundle
+	data, err := json.Marshal(manifest)
+	assert.NoError(t, err)
+	bundlePath := path.Join(t.TempDir(), "bundle.tar")
+	bundle, err := os.Create(bundlePath)
+	assert.NoError(t, err)
+	writer := tar.NewWriter(bundle)
+	assert.NoError(t, writer.WriteHeader(&tar.Header{Name: manifest.ID + "/" + ScrutinizeManifestFileName,
+		Mode: 0600, Size: int64(len(data))}))
+	_, err = writer.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, bundle.Close())
+
+	parsed, err := ReadScrutinizeManifest(bundlePath)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.Batches, parsed.Batches)
+	assert.Equal(t, "timed out", parsed.Errors[0].Message)
+
+	// negative: a manifest in a newer format
+	_, err = ParseScrutinizeManifest([]byte(`{"version": 2}`))
+	unsupportedErr := &UnsupportedManifestVersionError{}
+	assert.ErrorAs(t, err, &unsupportedErr)
+	_, err = ParseScrutinizeManifest([]byte(`{}`))
+	assert.ErrorContains(t, err, "has no version")
+}
@@ -54,28 +54,28 @@ func (op *scrutinizeOpBase) setupClusterHTTPRequest(hosts []string) error {
 
 // processeStagedItemsResult is a parameterized function which contains common logic
 // for processing the results of staging various types of items, e.g. vertica.log,
-// system tables, etc.
-func processStagedItemsResult[T any](op *scrutinizeOpBase, itemList []T) error {
+// system tables, etc. The item list only gives the type of the items, which are
+// decoded one at a time, as the response may be spilled to a file.
+func processStagedItemsResult[T any](op *scrutinizeOpBase, _ []T) error {
 	var allErrs error
 
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
 		if result.isPassing() {
-			if result.content == "" {
+			if result.isContentEmpty() {
 				// note that an empty response (nothing staged) is not an error
 				op.logger.Info("nothing staged on host", "Host", host)
 				continue
 			}
 			// the response is an array of item info structs
-			err := op.parseAndCheckResponse(host, result.content, &itemList)
+			_, err := decodeResultItems(&result, func(entry *T) {
+				op.logger.Info("item staged on host", "Host", host, "Item", *entry)
+			})
 			if err != nil {
 				err = fmt.Errorf("[%s] fail to parse result on host %s, details: %w", op.name, host, err)
 				allErrs = errors.Join(allErrs, err)
 				continue
 			}
-			for _, entry := range itemList {
-				op.logger.Info("item staged on host", "Host", host, "Item", entry)
-			}
 		} else {
 			allErrs = errors.Join(allErrs, result.err)
 		}
@@ -148,3 +148,35 @@ func TestSetLogAgeRange(t *testing.T) {
 	assert.ErrorContains(t, err, "invalid time range: max log age cannot be less than min log age")
 	assert.Contains(t, logBuf.String(), "invalid log age range")
 }
+
+func TestStageContainerDiagnostics(t *testing.T) {
+	var logBuf bytes.Buffer
+	logger := vlog.Printer{
+		Log: buflogr.NewWithBuffer(&logBuf),
+	}
+
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	hostNodeNameMap := map[string]string{hosts[0]: "v_test_db_node0001", hosts[1]: "v_test_db_node0002"}
+	hostCatPathMap := map[string]string{hosts[0]: "/catalog/node0001", hosts[1]: "/catalog/node0002"}
+	op, err := makeNMAStageContainerDiagnosticsOp(logger, "VerticaScrutinize.20240101000000",
+		hosts, hostNodeNameMap, hostCatPathMap, scrutinizePodLogLimitBytes, ScrutinizeLogMaxAgeHoursDefault)
+	assert.NoError(t, err)
+
+	op.setupBasicInfo()
+
+	// the diagnostics are staged in the container batch
+	assert.NoError(t, op.setupRequestBody(hosts))
+	assert.NoError(t, op.setupClusterHTTPRequest(hosts))
+	assert.Equal(t, NMACurVersion+"scrutinize/VerticaScrutinize.20240101000000/v_test_db_node0001/container/container",
+		op.clusterHTTPRequest.RequestCollection[hosts[0]].Endpoint)
+	assert.Contains(t, op.hostRequestBodyMap[hosts[1]], `"catalog_path":"/catalog/node0002"`)
+
+	// a host that cannot collect container diagnostics does not fail scrutinize
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {host: hosts[0], status: SUCCESS, statusCode: SuccessCode,
+			content: `[{"name": "pod.log", "size_bytes": 1024}]`},
+		hosts[1]: {host: hosts[1], status: FAILURE, statusCode: 404, err: fmt.Errorf("not found")},
+	}
+	assert.NoError(t, op.processResult(nil))
+	assert.Contains(t, logBuf.String(), "failed to stage container diagnostics")
+}
@@ -18,11 +18,40 @@ package vclusterops
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
+type (
+	ConfigValidationResult  = vtypes.ConfigValidationResult
+	ConfigCanaryFailedError = vtypes.ConfigCanaryFailedError
+)
+
+const (
+	// the level of a configuration parameter that is set on some nodes
+	ConfigParameterNodeLevel = "node"
+	// the value that clears a configuration parameter
+	ConfigParameterNullValue = "null"
+)
+
+// ConfigCanary sets a database configuration parameter on the up nodes of a
+// subcluster first, and runs validation queries on them. The parameter is set
+// on the database only if every query passes, otherwise it is cleared from the
+// canary nodes.
+type ConfigCanary struct {
+	// the subcluster whose nodes get the new value first
+	Subcluster string
+	// the queries that must pass on every canary node
+	ValidationQueries []string
+
+	// the up nodes of the subcluster and their hosts, set by the command
+	nodeNames []string
+	hosts     []string
+}
+
 type VSetConfigurationParameterOptions struct {
 	/* part 1: basic db info */
 	DatabaseOptions
@@ -33,6 +62,12 @@ type VSetConfigurationParameterOptions struct {
 	// set value literally to "null" to clear the value of a config parameter
 	Value string
 	Level string
+	// if set, the parameter is validated on canary nodes before it is set on
+	// the database. Only database-level parameters can have a canary.
+	Canary *ConfigCanary
+
+	// out: the results of the validation queries of the canary
+	CanaryResults []ConfigValidationResult
 }
 
 func VSetConfigurationParameterOptionsFactory() VSetConfigurationParameterOptions {
@@ -65,6 +100,26 @@ func (opt *VSetConfigurationParameterOptions) validateExtraOptions(logger vlog.P
 	}
 	// opt.Value could be empty (which is not equivalent to "null")
 	// opt.Level could be empty (which means database level)
+	if opt.Canary != nil {
+		return opt.Canary.validate(opt.Level)
+	}
+	return nil
+}
+
+func (canary *ConfigCanary) validate(level string) error {
+	if level != "" {
+		return fmt.Errorf("only database-level configuration parameters can have a canary, got level %s", level)
+	}
+	if canary.Subcluster == "" {
+		return fmt.Errorf("must specify the subcluster of the canary")
+	}
+	err := util.ValidateScName(canary.Subcluster)
+	if err != nil {
+		return err
+	}
+	if len(canary.ValidationQueries) == 0 {
+		return fmt.Errorf("must specify at least one validation query for the canary")
+	}
 	return nil
 }
 
@@ -104,6 +159,13 @@ func (vcc VClusterCommands) VSetConfigurationParameters(options *VSetConfigurati
 		return err
 	}
 
+	if options.Canary != nil {
+		err = vcc.runConfigCanary(options)
+		if err != nil {
+			return err
+		}
+	}
+
 	// produce set configuration parameters instructions
 	instructions, err := vcc.produceSetConfigurationParameterInstructions(options)
 	if err != nil {
@@ -116,6 +178,9 @@ func (vcc VClusterCommands) VSetConfigurationParameters(options *VSetConfigurati
 	// Give the instructions to the VClusterOpEngine to run
 	runError := clusterOpEngine.run(vcc.Log)
 	if runError != nil {
+		if options.Canary != nil {
+			runError = errors.Join(runError, vcc.rollbackConfigCanary(options))
+		}
 		return fmt.Errorf("fail to set configuration parameter: %w", runError)
 	}
 
@@ -159,5 +224,125 @@ func (vcc VClusterCommands) produceSetConfigurationParameterInstructions(
 		&nmaSetConfigOp,
 	)
 
+	// the canary nodes follow the database value from now on
+	if options.Canary != nil {
+		nmaClearCanaryOp, err := options.makeSetCanaryConfigOp(ConfigParameterNullValue)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaClearCanaryOp)
+	}
+
 	return instructions, nil
 }
+
+// runConfigCanary sets the parameter on the canary nodes and runs the
+// validation queries on them. It returns a ConfigCanaryFailedError, after
+// clearing the parameter from the canary nodes, if any query failed.
+func (vcc VClusterCommands) runConfigCanary(options *VSetConfigurationParameterOptions) error {
+	vdb := makeVCoordinationDatabase()
+	err := vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, options.Sandbox)
+	if err != nil {
+		return err
+	}
+	options.Canary.nodeNames, options.Canary.hosts = nil, nil
+	for host, vnode := range vdb.HostNodeMap {
+		if vnode.Subcluster == options.Canary.Subcluster && vnode.Sandbox == options.Sandbox &&
+			vnode.State == util.NodeUpState {
+			options.Canary.nodeNames = append(options.Canary.nodeNames, vnode.Name)
+			options.Canary.hosts = append(options.Canary.hosts, host)
+		}
+	}
+	sort.Strings(options.Canary.nodeNames)
+	sort.Strings(options.Canary.hosts)
+	if len(options.Canary.hosts) == 0 {
+		return fmt.Errorf("canary subcluster %s is not found or has no up nodes", options.Canary.Subcluster)
+	}
+
+	options.CanaryResults = nil
+	instructions, err := vcc.produceConfigCanaryInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	err = clusterOpEngine.run(vcc.Log)
+	if err == nil && allValidationQueriesPassed(options.CanaryResults) {
+		vcc.Log.PrintInfo("The canary of configuration parameter %s passed on nodes %v",
+			options.ConfigParameter, options.Canary.nodeNames)
+		return nil
+	}
+
+	rollbackErr := vcc.rollbackConfigCanary(options)
+	if rollbackErr != nil {
+		vcc.Log.PrintWarning("fail to clear configuration parameter %s from the canary nodes %v, details: %v",
+			options.ConfigParameter, options.Canary.nodeNames, rollbackErr)
+	}
+	if err != nil {
+		return errors.Join(fmt.Errorf("fail to run the canary of configuration parameter %s: %w",
+			options.ConfigParameter, err), rollbackErr)
+	}
+	return &ConfigCanaryFailedError{ConfigParameter: options.ConfigParameter, Results: options.CanaryResults,
+		RolledBack: rollbackErr == nil}
+}
+
+// rollbackConfigCanary clears the parameter from the canary nodes
+func (vcc VClusterCommands) rollbackConfigCanary(options *VSetConfigurationParameterOptions) error {
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesWithSandboxOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password,
+		SetConfigurationParameterCmd, options.Sandbox, options.Sandbox == "")
+	if err != nil {
+		return err
+	}
+	nmaClearCanaryOp, err := options.makeSetCanaryConfigOp(ConfigParameterNullValue)
+	if err != nil {
+		return err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&httpsGetUpNodesOp, &nmaClearCanaryOp}, options)
+	return clusterOpEngine.run(vcc.Log)
+}
+
+// The generated instructions will later perform the following operations necessary
+// for the canary of a configuration parameter:
+//   - Check NMA connectivity
+//   - Check UP nodes and sandboxes info
+//   - Set the configuration parameter on the canary nodes
+//   - Run the validation queries on the canary nodes
+func (vcc VClusterCommands) produceConfigCanaryInstructions(options *VSetConfigurationParameterOptions) ([]clusterOp, error) {
+	httpsGetUpNodesOp, err := makeHTTPSGetUpNodesWithSandboxOp(options.DBName, options.Hosts,
+		options.usePassword, options.UserName, options.Password,
+		SetConfigurationParameterCmd, options.Sandbox, options.Sandbox == "")
+	if err != nil {
+		return nil, err
+	}
+	nmaHealthOp := makeNMAHealthOp(options.Hosts)
+	nmaSetCanaryOp, err := options.makeSetCanaryConfigOp(options.Value)
+	if err != nil {
+		return nil, err
+	}
+	nmaValidationOp, err := makeNMARunValidationQueriesOp(options.Canary.hosts, options.Canary.ValidationQueries,
+		options.UserName, options.DBName, options.Password, options.usePassword, &options.CanaryResults)
+	if err != nil {
+		return nil, err
+	}
+	return []clusterOp{&nmaHealthOp, &httpsGetUpNodesOp, &nmaSetCanaryOp, &nmaValidationOp}, nil
+}
+
+// makeSetCanaryConfigOp sets the parameter to value on the canary nodes,
+// through one of the canary hosts
+func (options *VSetConfigurationParameterOptions) makeSetCanaryConfigOp(value string) (nmaSetConfigurationParameterOp, error) {
+	return makeNMASetNodesConfigurationParameterOp(options.Canary.hosts, options.Canary.nodeNames,
+		options.UserName, options.DBName, options.Sandbox, options.ConfigParameter, value,
+		options.Password, options.usePassword)
+}
+
+func allValidationQueriesPassed(results []ConfigValidationResult) bool {
+	if len(results) == 0 {
+		return false
+	}
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
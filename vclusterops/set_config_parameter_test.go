@@ -80,3 +80,37 @@ func TestVSetConfigurationParameterOptions_validateParseOptions(t *testing.T) {
 	err = opt.validateParseOptions(logger)
 	assert.Error(t, err)
 }
+
+func TestConfigCanary(t *testing.T) {
+	canary := ConfigCanary{Subcluster: "sc1", ValidationQueries: []string{"SELECT 1"}}
+	assert.NoError(t, canary.validate(""))
+	assert.ErrorContains(t, canary.validate(ConfigParameterNodeLevel), "only database-level")
+	assert.ErrorContains(t, (&ConfigCanary{Subcluster: "sc1"}).validate(""), "at least one validation query")
+	assert.ErrorContains(t, (&ConfigCanary{ValidationQueries: []string{"SELECT 1"}}).validate(""),
+		"must specify the subcluster")
+
+	var results []ConfigValidationResult
+	password := "canary-password"
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	op, err := makeNMARunValidationQueriesOp(hosts, []string{"SELECT 1", "SELECT 2"}, "dbadmin", "test_db",
+		&password, true, &results)
+	assert.NoError(t, err)
+	assert.False(t, op.getClassification().Mutating)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		hosts[0]: {status: SUCCESS, content: `[{"query": "SELECT 1", "passed": true},
+			{"query": "SELECT 2", "passed": false, "error": "division by zero"}]`},
+		hosts[1]: {status: FAILURE, err: assert.AnError},
+	}
+	assert.NoError(t, op.processResult(&opEngineExecContext{}))
+	assert.Len(t, results, 4)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "division by zero", results[1].Error)
+	assert.Equal(t, ConfigValidationResult{Host: hosts[1], Query: "SELECT 2", Error: assert.AnError.Error()}, results[3])
+	assert.False(t, allValidationQueriesPassed(results))
+	assert.True(t, allValidationQueriesPassed(results[:1]))
+	assert.False(t, allValidationQueriesPassed(nil))
+
+	canaryErr := &ConfigCanaryFailedError{ConfigParameter: "MaxClientSessions", Results: results[:2], RolledBack: true}
+	assert.EqualError(t, canaryErr, `the canary of configuration parameter MaxClientSessions failed, `+
+		`validation queries failed: "SELECT 2" on host 192.168.1.101: division by zero`)
+}
@@ -30,6 +30,11 @@ type VShowRestorePointsOptions struct {
 	// Optional arguments to list only restore points that
 	// meet the specified condition(s)
 	FilterOptions ShowRestorePointFilterOptions
+	// Optional, if set, each restore point is passed to this handler as it is
+	// received, and VShowRestorePoints returns no restore points. This bounds
+	// the memory used to list an archive with many restore points. Returning
+	// an error from the handler stops the listing.
+	RestorePointHandler func(RestorePoint) error
 }
 
 func VShowRestorePointsFactory() VShowRestorePointsOptions {
@@ -202,7 +207,10 @@ func (vcc VClusterCommands) produceShowRestorePointsInstructions(options *VShowR
 	var instructions []clusterOp
 
 	hosts := options.Hosts
-	initiator := getInitiator(hosts)
+	initiator, err := options.pickInitiator(hosts)
+	if err != nil {
+		return instructions, err
+	}
 	bootstrapHost := []string{initiator}
 
 	nmaHealthOp := makeNMAHealthOp(hosts)
@@ -212,6 +220,9 @@ func (vcc VClusterCommands) produceShowRestorePointsInstructions(options *VShowR
 
 	nmaShowRestorePointOp := makeNMAShowRestorePointsOpWithFilterOptions(vcc.Log, bootstrapHost, options.DBName,
 		options.CommunalStorageLocation, options.ConfigurationParameters, &options.FilterOptions)
+	if options.RestorePointHandler != nil {
+		nmaShowRestorePointOp.streamRestorePoints(options.RestorePointHandler)
+	}
 
 	instructions = append(instructions,
 		&nmaHealthOp,
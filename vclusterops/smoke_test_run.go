@@ -0,0 +1,140 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	SmokeTestResult      = vtypes.SmokeTestResult
+	SmokeTestStep        = vtypes.SmokeTestStep
+	SmokeTestFailedError = vtypes.SmokeTestFailedError
+)
+
+// DefaultSmokeTestRowCount is the number of rows that the smoke test loads
+const DefaultSmokeTestRowCount = 1000
+
+type VRunSmokeTestOptions struct {
+	DatabaseOptions
+	// the number of rows to load in the test table
+	RowCount int64
+}
+
+func VRunSmokeTestOptionsFactory() VRunSmokeTestOptions {
+	opt := VRunSmokeTestOptions{}
+	// set default values to the params
+	opt.setDefaultValues()
+	opt.RowCount = DefaultSmokeTestRowCount
+	return opt
+}
+
+// SmokeTestOptions are the options of the smoke test that a command runs
+// once it is done
+type SmokeTestOptions struct {
+	// whether to run the smoke test
+	RunSmokeTest bool
+	// the number of rows to load in the test table, DefaultSmokeTestRowCount if 0
+	RowCount int64
+	// set by the command, the result of the smoke test
+	Result *SmokeTestResult
+}
+
+func (opt *SmokeTestOptions) getRowCount() int64 {
+	if opt.RowCount <= 0 {
+		return DefaultSmokeTestRowCount
+	}
+	return opt.RowCount
+}
+
+// VRunSmokeTest checks that a running database is usable: it creates a table,
+// loads a few rows, queries them from every node, and drops the table. It
+// returns a SmokeTestFailedError with the result if a step failed.
+func (vcc VClusterCommands) VRunSmokeTest(options *VRunSmokeTestOptions) (SmokeTestResult, error) {
+	err := options.validateAnalyzeSQLOptions(RunSmokeTestCmd, vcc.Log)
+	if err != nil {
+		return SmokeTestResult{}, err
+	}
+	if options.RowCount <= 0 {
+		return SmokeTestResult{}, fmt.Errorf("the smoke test must load at least one row, got %d", options.RowCount)
+	}
+	return vcc.runSmokeTest(&options.DatabaseOptions, options.Hosts, options.RowCount, options.usePassword)
+}
+
+// runSmokeTest runs the smoke test on hosts, which should all be up
+func (vcc VClusterCommands) runSmokeTest(options *DatabaseOptions, hosts []string, rowCount int64,
+	useDBPassword bool) (SmokeTestResult, error) {
+	result := SmokeTestResult{}
+	instructions, err := produceSmokeTestInstructions(options, hosts, rowCount, useDBPassword, &result)
+	if err != nil {
+		return result, fmt.Errorf("fail to produce instructions, %w", err)
+	}
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+	err = clusterOpEngine.run(vcc.Log)
+	if err != nil {
+		return result, fmt.Errorf("fail to run the smoke test: %w", err)
+	}
+	if !result.Passed {
+		return result, &SmokeTestFailedError{Result: result}
+	}
+	vcc.Log.PrintInfo("The smoke test passed on %d host(s)", len(hosts))
+	return result, nil
+}
+
+// runCommandSmokeTest runs the smoke test of a command that is done, if asked
+// to, and keeps the result in the smoke test options
+func (vcc VClusterCommands) runCommandSmokeTest(smokeTest *SmokeTestOptions, options *DatabaseOptions,
+	hosts []string, useDBPassword bool) error {
+	if !smokeTest.RunSmokeTest {
+		return nil
+	}
+	result, err := vcc.runSmokeTest(options, hosts, smokeTest.getRowCount(), useDBPassword)
+	smokeTest.Result = &result
+	return err
+}
+
+// The generated instructions will later perform the following operations:
+//   - Create the test table and load it on the initiator
+//   - Query the test table on every host
+//   - Drop the test table on the initiator
+func produceSmokeTestInstructions(options *DatabaseOptions, hosts []string, rowCount int64,
+	useDBPassword bool, result *SmokeTestResult) ([]clusterOp, error) {
+	// the table is named after the time of the test, in case a test table
+	// was left by a failed cleanup
+	table := fmt.Sprintf("public.vcluster_smoke_test_%d", time.Now().UnixNano())
+	initiator := []string{getInitiator(hosts)}
+
+	var instructions []clusterOp
+	for _, step := range []struct {
+		name  string
+		hosts []string
+	}{
+		{smokeTestSetupStep, initiator},
+		{smokeTestQueryStep, hosts},
+		{smokeTestCleanupStep, initiator},
+	} {
+		op, err := makeNMASmokeTestOp(step.hosts, step.name, table, rowCount, options.UserName, options.DBName,
+			options.Password, useDBPassword, result)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, &op)
+	}
+	return instructions, nil
+}
@@ -0,0 +1,99 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestSmokeTestSteps(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	options.DBName = testDBName
+	options.UserName = "dbadmin"
+	password := "password"
+	options.Password = &password
+	hosts := []string{"192.168.1.101", "192.168.1.102"}
+	result := SmokeTestResult{}
+	instructions, err := produceSmokeTestInstructions(&options, hosts, 10, true, &result)
+	assert.NoError(t, err)
+	assert.Len(t, instructions, 3)
+	setupOp := instructions[0].(*nmaSmokeTestOp)
+	queryOp := instructions[1].(*nmaSmokeTestOp)
+	cleanupOp := instructions[2].(*nmaSmokeTestOp)
+	assert.Equal(t, []string{"192.168.1.101"}, setupOp.hosts)
+	assert.Equal(t, hosts, queryOp.hosts)
+	assert.Contains(t, setupOp.hostRequestBody, `"row_count":10`)
+	assert.NotContains(t, queryOp.hostRequestBody, "row_count")
+	assert.True(t, setupOp.getClassification().Mutating)
+	assert.False(t, queryOp.getClassification().Mutating)
+
+	// the setup loads the table on the initiator
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	setupOp.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"rows": 10}`},
+	}
+	assert.NoError(t, setupOp.processResult(&execContext))
+	assert.True(t, result.Passed)
+
+	// every host reads all rows, a host that does not fails the test
+	queryOp.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: SUCCESS, content: `{"rows": 10}`},
+		"192.168.1.102": {status: SUCCESS, content: `{"rows": 4}`},
+	}
+	assert.NoError(t, queryOp.processResult(&execContext))
+	assert.False(t, result.Passed)
+	step, failed := result.FirstFailedStep()
+	assert.True(t, failed)
+	assert.Equal(t, SmokeTestStep{Name: smokeTestQueryStep, Host: "192.168.1.102", Rows: 4,
+		Error: "expected 10 rows, got 4"}, step)
+	failedErr := &SmokeTestFailedError{Result: result}
+	assert.EqualError(t, failedErr, "the smoke test failed at step query on host 192.168.1.102: expected 10 rows, got 4")
+
+	// the table is still dropped
+	cleanupOp.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, cleanupOp.prepare(&execContext))
+	assert.False(t, cleanupOp.isSkipExecute())
+	assert.Len(t, result.Steps, 3)
+}
+
+func TestSmokeTestSkippedSteps(t *testing.T) {
+	options := DatabaseOptionsFactory()
+	options.DBName = testDBName
+	options.UserName = "dbadmin"
+	result := SmokeTestResult{}
+	instructions, err := produceSmokeTestInstructions(&options, []string{"192.168.1.101"}, 10, false, &result)
+	assert.NoError(t, err)
+
+	// the table is not dropped if no step ran
+	execContext := makeOpEngineExecContext(vlog.Printer{})
+	cleanupOp := instructions[2].(*nmaSmokeTestOp)
+	assert.NoError(t, cleanupOp.prepare(&execContext))
+	assert.True(t, cleanupOp.isSkipExecute())
+
+	// the table is not queried if the setup failed
+	setupOp := instructions[0].(*nmaSmokeTestOp)
+	setupOp.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{
+		"192.168.1.101": {status: FAILURE, statusCode: 500},
+	}
+	assert.NoError(t, setupOp.processResult(&execContext))
+	assert.False(t, result.Passed)
+	queryOp := instructions[1].(*nmaSmokeTestOp)
+	assert.NoError(t, queryOp.prepare(&execContext))
+	assert.True(t, queryOp.isSkipExecute())
+}
@@ -52,6 +52,10 @@ type VStartDatabaseOptions struct {
 
 	// whether input info is read from vcluster config file, used for quorum check
 	ReadFromConfig bool
+
+	// the smoke test to run once the database is started, e.g., the first time
+	// after revive
+	SmokeTest SmokeTestOptions
 }
 
 func VStartDatabaseOptionsFactory() VStartDatabaseOptions {
@@ -185,7 +189,8 @@ func (vcc VClusterCommands) VStartDatabase(options *VStartDatabaseOptions) (vdbP
 		return nil, err
 	}
 
-	return &updatedVDB, nil
+	err = vcc.runCommandSmokeTest(&options.SmokeTest, &options.DatabaseOptions, options.Hosts, options.usePassword)
+	return &updatedVDB, err
 }
 
 func (vcc VClusterCommands) runStartDBPrecheck(options *VStartDatabaseOptions, vdb *VCoordinationDatabase,
@@ -343,8 +348,9 @@ func (vcc VClusterCommands) produceStartDBInstructions(options *VStartDatabaseOp
 		nil /*sandbox name*/)
 
 	nmaStartNewNodesOp := makeNMAStartNodeOp(options.Hosts, options.StartUpConf)
+	pollingTimeout := options.adaptTimeout(options.StatePollingTimeout, util.DefaultStatePollingTimeout, len(options.Hosts))
 	httpsPollNodeStateOp, err := makeHTTPSPollNodeStateOp(options.Hosts,
-		options.usePassword, options.UserName, options.Password, options.StatePollingTimeout)
+		options.usePassword, options.UserName, options.Password, pollingTimeout)
 	if err != nil {
 		return instructions, err
 	}
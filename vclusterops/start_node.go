@@ -405,15 +405,17 @@ func (vcc VClusterCommands) produceStartNodesInstructions(startNodeInfo *VStartN
 	}
 	nmaStartNewNodesOp := makeNMAStartNodeOpWithVDB(startNodeInfo.HostsToStart, options.StartUpConf, vdb)
 	permanentNodes := make([]string, 0, len(startNodeInfo.HostsToStart))
+	pollingTimeout := options.adaptTimeout(options.StatePollingTimeout, util.DefaultStatePollingTimeout,
+		len(startNodeInfo.HostsToStart))
 	httpsPollNodeStateIndirectOp, err := makeHTTPSPollUnknownNodeStateOp(startNodeInfo.HostsToStart,
 		&permanentNodes, options.usePassword, options.UserName, options.Password,
-		options.StatePollingTimeout)
+		pollingTimeout)
 	if err != nil {
 		return instructions, err
 	}
 	httpsPollNodeStateOp, err := makeHTTPSPollPermanentNodeStateOp(startNodeInfo.HostsToStart,
 		&permanentNodes, options.usePassword, options.UserName, options.Password,
-		options.StatePollingTimeout)
+		pollingTimeout)
 	if err != nil {
 		return instructions, err
 	}
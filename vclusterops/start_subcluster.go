@@ -33,6 +33,12 @@ type VStartScOptions struct {
 	SCName      string   // subcluster to start
 	NewHostList []string // expected to be already resolved IP addresses of new hosts used only for re-ip
 	Sandbox     string
+	// if set, the cluster must pass the gate once the nodes are started, e.g.,
+	// to fail when the started nodes did not sync their catalog
+	PostStartHealthGate *HealthGate
+
+	// out: what the command did to each down node of the subcluster, sorted by name
+	NodeOutcomes []NodeOutcome
 }
 
 func VStartScOptionsFactory() VStartScOptions {
@@ -85,6 +91,10 @@ func (options *VStartScOptions) validateParseOptions(logger vlog.Printer) error
 	if err != nil {
 		return err
 	}
+
+	if options.PostStartHealthGate != nil {
+		return options.PostStartHealthGate.validate()
+	}
 	return nil
 }
 
@@ -113,9 +123,10 @@ func (options *VStartScOptions) validateAnalyzeOptions(logger vlog.Printer) erro
 }
 
 // VStartSubcluster start nodes in a subcluster. It returns any error encountered.
-// VStartSubcluster has two major phases:
+// VStartSubcluster has three major phases:
 //  1. Pre-check: check the subcluster name and get nodes for the subcluster.
 //  2. Start nodes: Optional. If there are any down nodes in the subcluster, runs VStartNodes.
+//  3. Post-check: Optional. Checks the health of the cluster with PostStartHealthGate.
 func (vcc VClusterCommands) VStartSubcluster(options *VStartScOptions) (VCoordinationDatabase, error) {
 	// retrieve database information to execute the command so we do not always rely on some user input
 	vdb := makeVCoordinationDatabase()
@@ -173,12 +184,65 @@ func (vcc VClusterCommands) VStartSubcluster(options *VStartScOptions) (VCoordin
 	vlog.DisplayColorInfo("Starting nodes %v in subcluster %s", maps.Keys(nodesToStart), options.SCName)
 	err = vcc.VStartNodes(&options.VStartNodesOptions)
 	if err != nil {
+		options.NodeOutcomes = makeStartSCNodeOutcomes(nodesToStart, nil, err)
 		return vdb, err
 	}
 	err = vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, AnySandbox)
+	if err != nil {
+		return vdb, err
+	}
+	options.NodeOutcomes = makeStartSCNodeOutcomes(nodesToStart, &vdb, nil)
+	if options.PostStartHealthGate != nil {
+		err = vcc.checkPostStartHealth(options)
+	}
 	return vdb, err
 }
 
+// makeStartSCNodeOutcomes tells which of the nodes to start are up in the
+// database. All of them failed to start if the command failed.
+func makeStartSCNodeOutcomes(nodesToStart map[string]string, vdb *VCoordinationDatabase, startError error) []NodeOutcome {
+	upNodes := make(map[string]bool)
+	if vdb != nil {
+		for _, vnode := range vdb.HostNodeMap {
+			if vnode.State == util.NodeUpState {
+				upNodes[vnode.Name] = true
+			}
+		}
+	}
+	outcomes := make([]NodeOutcome, 0, len(nodesToStart))
+	for name, address := range nodesToStart {
+		outcome := NodeOutcome{Name: name, Address: address, Outcome: NodeOutcomeStarted}
+		if startError != nil {
+			outcome.Outcome = NodeOutcomeFailed
+			outcome.Error = startError.Error()
+		} else if !upNodes[name] {
+			outcome.Outcome = NodeOutcomeFailed
+			outcome.Error = "the node is not up after the start"
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].Name < outcomes[j].Name
+	})
+	return outcomes
+}
+
+// checkPostStartHealth checks the health of the cluster, now that the nodes
+// of the subcluster are started, against the post-start health gate
+func (vcc VClusterCommands) checkPostStartHealth(options *VStartScOptions) error {
+	health, isRunning := ClusterHealth{}, false
+	healthCheckOp, err := options.makeHealthCheckOp(&health, &isRunning)
+	if err != nil {
+		return err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{healthCheckOp}, options)
+	err = clusterOpEngine.run(vcc.Log)
+	if err != nil {
+		return fmt.Errorf("fail to check the health of the cluster after starting subcluster %s: %w", options.SCName, err)
+	}
+	return options.PostStartHealthGate.check(vcc.Log, StartSubclusterCmd.CmdString(), &health)
+}
+
 // Collect all down hosts that in the subcluster that need to be started
 func (options *VStartScOptions) collectDownHosts(vdb *VCoordinationDatabase) (nodesToStart map[string]string) {
 	nodesToStart = make(map[string]string)
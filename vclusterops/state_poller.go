@@ -16,6 +16,7 @@
 package vclusterops
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
@@ -30,6 +31,8 @@ const (
 	PollingInterval          = 3 * OneSecond
 )
 
+var errPollingTimeout = errors.New("reached polling timeout")
+
 type statePoller interface {
 	getPollingTimeout() int
 	shouldStopPolling() (bool, error)
@@ -73,5 +76,5 @@ func pollState(poller statePoller, execContext *opEngineExecContext) error {
 		count++
 	}
 
-	return fmt.Errorf("reached polling timeout of %d seconds", timeout)
+	return fmt.Errorf("%w of %d seconds", errPollingTimeout, timeout)
 }
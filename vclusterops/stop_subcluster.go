@@ -17,9 +17,21 @@ package vclusterops
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type NodeOutcome = vtypes.NodeOutcome
+
+// the outcomes of the nodes of a subcluster that is stopped or started
+const (
+	NodeOutcomeStopped     = "stopped"
+	NodeOutcomeStarted     = "started"
+	NodeOutcomeAlreadyDown = "already_down"
+	NodeOutcomeFailed      = "failed"
 )
 
 type VStopSubclusterOptions struct {
@@ -30,6 +42,12 @@ type VStopSubclusterOptions struct {
 	DrainSeconds int    // time in seconds to wait for subcluster users' disconnection, its default value is 60
 	SCName       string // subcluster name
 	Force        bool   // force the subcluster to shutdown immediately even if users are connected
+	// whether to clear the depot of the subcluster when it stops, so that its
+	// nodes do not start with a stale cache. The depot is kept by default.
+	ClearDepot bool
+
+	// out: what the command did to each node of the subcluster, sorted by name
+	NodeOutcomes []NodeOutcome
 }
 
 func VStopSubclusterOptionsFactory() VStopSubclusterOptions {
@@ -141,6 +159,8 @@ func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) err
 
 	// Give the instructions to the VClusterOpEngine to run
 	runError := clusterOpEngine.run(vcc.Log)
+	options.NodeOutcomes = makeStopSCNodeOutcomes(clusterOpEngine.execContext.scNodesInfo,
+		clusterOpEngine.execContext.nodesInfo, runError)
 	if runError != nil {
 		return fmt.Errorf("failed to stop subcluster %s: %w", options.SCName, runError)
 	}
@@ -148,6 +168,33 @@ func (vcc VClusterCommands) VStopSubcluster(options *VStopSubclusterOptions) err
 	return nil
 }
 
+// makeStopSCNodeOutcomes tells which nodes of the subcluster were stopped, and
+// which were already down. The up nodes failed to stop if the command failed.
+func makeStopSCNodeOutcomes(scNodes, upNodes []NodeInfo, runError error) []NodeOutcome {
+	upNodeNames := make(map[string]bool, len(upNodes))
+	for i := range upNodes {
+		upNodeNames[upNodes[i].Name] = true
+	}
+	outcomes := make([]NodeOutcome, 0, len(scNodes))
+	for i := range scNodes {
+		outcome := NodeOutcome{Name: scNodes[i].Name, Address: scNodes[i].Address}
+		switch {
+		case !upNodeNames[outcome.Name]:
+			outcome.Outcome = NodeOutcomeAlreadyDown
+		case runError != nil:
+			outcome.Outcome = NodeOutcomeFailed
+			outcome.Error = runError.Error()
+		default:
+			outcome.Outcome = NodeOutcomeStopped
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].Name < outcomes[j].Name
+	})
+	return outcomes
+}
+
 // produceStopSCInstructions will build a list of instructions to execute for
 // the stop subcluster operation.
 //
@@ -188,7 +235,7 @@ func (vcc *VClusterCommands) produceStopSCInstructions(options *VStopSubclusterO
 	}
 
 	httpsStopSCOp, err := makeHTTPSStopSCOp(usePassword, options.UserName, options.Password,
-		options.SCName, options.DrainSeconds, options.Force)
+		options.SCName, options.DrainSeconds, options.Force, options.ClearDepot)
 	if err != nil {
 		return instructions, err
 	}
@@ -0,0 +1,76 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestStopSCNodeOutcomes(t *testing.T) {
+	scNodes := []NodeInfo{
+		{Name: "v_test_db_node0002", Address: "192.168.1.102"},
+		{Name: "v_test_db_node0001", Address: "192.168.1.101"},
+	}
+	upNodes := scNodes[:1]
+
+	outcomes := makeStopSCNodeOutcomes(scNodes, upNodes, nil)
+	assert.Equal(t, []NodeOutcome{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101", Outcome: NodeOutcomeAlreadyDown},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102", Outcome: NodeOutcomeStopped},
+	}, outcomes)
+
+	outcomes = makeStopSCNodeOutcomes(scNodes, upNodes, assert.AnError)
+	assert.Equal(t, NodeOutcomeAlreadyDown, outcomes[0].Outcome)
+	assert.Equal(t, NodeOutcomeFailed, outcomes[1].Outcome)
+	assert.Equal(t, assert.AnError.Error(), outcomes[1].Error)
+
+	op, err := makeHTTPSStopSCOp(false, "", nil, "sc1", 60, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"clear_depot": "true"}, op.requestParams)
+}
+
+func TestStartSCNodeOutcomes(t *testing.T) {
+	nodesToStart := map[string]string{"v_test_db_node0002": "192.168.1.102", "v_test_db_node0001": "192.168.1.101"}
+	vdb := makeVCoordinationDatabase()
+	vdb.HostNodeMap = vHostNodeMap{
+		"192.168.1.101": {Name: "v_test_db_node0001", State: util.NodeUpState},
+		"192.168.1.102": {Name: "v_test_db_node0002", State: util.NodeDownState},
+	}
+
+	outcomes := makeStartSCNodeOutcomes(nodesToStart, &vdb, nil)
+	assert.Equal(t, []NodeOutcome{
+		{Name: "v_test_db_node0001", Address: "192.168.1.101", Outcome: NodeOutcomeStarted},
+		{Name: "v_test_db_node0002", Address: "192.168.1.102", Outcome: NodeOutcomeFailed,
+			Error: "the node is not up after the start"},
+	}, outcomes)
+
+	outcomes = makeStartSCNodeOutcomes(nodesToStart, nil, assert.AnError)
+	for _, outcome := range outcomes {
+		assert.Equal(t, NodeOutcomeFailed, outcome.Outcome)
+	}
+
+	options := VStartScOptionsFactory()
+	options.DBName = testDBName
+	options.SCName = testSCName
+	options.RawHosts = []string{"192.168.1.101"}
+	options.IsEon = true
+	options.PostStartHealthGate = &HealthGate{MaxDownNodes: -1}
+	assert.ErrorContains(t, options.validateParseOptions(vlog.Printer{}), "max down nodes")
+}
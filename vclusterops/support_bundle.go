@@ -0,0 +1,346 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	SupportBundleManifestFileName = "manifest.json"
+	// the default number of the most recent command logs in a support bundle
+	DefaultSupportBundleCommandLogs = 20
+	// the default size in bytes above which a file is cut to its last bytes
+	DefaultSupportBundleMaxFileSize = 50 * 1024 * 1024
+
+	supportBundleEnvFileName = "environment.json"
+	supportBundleFilePerms   = 0600
+	redactedValue            = "<redacted>"
+)
+
+// the keys of the config file and the environment variables whose values are
+// not put in a support bundle
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|auth)`)
+
+// VSupportBundleOptions are the files of vcluster itself that a support bundle
+// packages, for troubleshooting vcluster rather than the database, which full
+// scrutinize is for. The files that are not set or do not exist are skipped.
+type VSupportBundleOptions struct {
+	// the path of the bundle, a gzipped tarball
+	OutputPath string
+	// the log of vcluster, with its rotated files next to it
+	LogPath string
+	// the config file of the cluster, whose secrets are redacted
+	ConfigPath string
+	// the directory of the logs of each command, see DatabaseOptions.CommandLogDir
+	CommandLogDir string
+	// the number of the most recent command logs to package
+	MaxCommandLogs int
+	// the size in bytes above which a file is cut to its last bytes, as the
+	// recent entries of a log matter most
+	MaxFileSize int64
+	// the version of the caller, e.g., of vcluster CLI
+	Version string
+}
+
+// SupportBundleManifest lists the files of a support bundle
+type SupportBundleManifest struct {
+	CreatedAt time.Time           `json:"created_at"`
+	Files     []SupportBundleFile `json:"files"`
+	// the files that could not be packaged, and why
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// SupportBundleFile is a file of a support bundle, whose path is relative to
+// the top level folder of the bundle
+type SupportBundleFile struct {
+	Path       string `json:"path"`
+	SourcePath string `json:"source_path,omitempty"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Redacted   bool   `json:"redacted,omitempty"`
+}
+
+// supportBundleEnvironment is the summary of the environment of vcluster
+type supportBundleEnvironment struct {
+	Version   string            `json:"version"`
+	GoVersion string            `json:"go_version"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	Hostname  string            `json:"hostname"`
+	User      string            `json:"user"`
+	NumCPU    int               `json:"num_cpu"`
+	Env       map[string]string `json:"env"`
+}
+
+func VSupportBundleFactory() VSupportBundleOptions {
+	return VSupportBundleOptions{
+		MaxCommandLogs: DefaultSupportBundleCommandLogs,
+		MaxFileSize:    DefaultSupportBundleMaxFileSize,
+	}
+}
+
+func (options *VSupportBundleOptions) validateOptions() error {
+	if options.OutputPath == "" {
+		return fmt.Errorf("must specify the path of the support bundle")
+	}
+	if options.MaxFileSize <= 0 {
+		options.MaxFileSize = DefaultSupportBundleMaxFileSize
+	}
+	return nil
+}
+
+// supportBundleWriter writes the files of a support bundle into its tarball
+type supportBundleWriter struct {
+	tarWriter *tar.Writer
+	topDir    string
+	maxSize   int64
+	manifest  SupportBundleManifest
+}
+
+// VCreateSupportBundle packages the diagnostics of vcluster itself into a
+// small bundle: its log and rotated logs, the config file with its secrets
+// redacted, the logs of the most recent commands, its version and a summary
+// of its environment. A file that cannot be read is listed in the manifest of
+// the bundle instead of failing the bundle.
+func (vcc VClusterCommands) VCreateSupportBundle(options *VSupportBundleOptions) (*SupportBundleManifest, error) {
+	if err := options.validateOptions(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(options.OutputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, supportBundleFilePerms)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create the support bundle %s: %w", options.OutputPath, err)
+	}
+	gzipWriter := gzip.NewWriter(file)
+	writer := supportBundleWriter{
+		tarWriter: tar.NewWriter(gzipWriter),
+		topDir:    "vcluster-support-" + time.Now().UTC().Format("20060102T150405Z"),
+		maxSize:   options.MaxFileSize,
+		manifest:  SupportBundleManifest{CreatedAt: time.Now().UTC(), Skipped: make(map[string]string)},
+	}
+
+	err = writer.writeBundle(options)
+	err = errors.Join(err, writer.tarWriter.Close(), gzipWriter.Close(), file.Close())
+	if err != nil {
+		os.Remove(options.OutputPath)
+		return nil, fmt.Errorf("fail to write the support bundle %s: %w", options.OutputPath, err)
+	}
+	for source, reason := range writer.manifest.Skipped {
+		vcc.Log.PrintWarning("%s is not in the support bundle: %s", source, reason)
+	}
+	vcc.Log.PrintInfo("Support bundle written to %s", options.OutputPath)
+	return &writer.manifest, nil
+}
+
+func (w *supportBundleWriter) writeBundle(options *VSupportBundleOptions) error {
+	if options.LogPath != "" {
+		for _, logPath := range findRotatedLogs(options.LogPath) {
+			if err := w.addFile(path.Join("logs", filepath.Base(logPath)), logPath); err != nil {
+				return err
+			}
+		}
+	}
+	if options.ConfigPath != "" {
+		if err := w.addConfigFile(options.ConfigPath); err != nil {
+			return err
+		}
+	}
+	if options.CommandLogDir != "" {
+		for _, logPath := range w.findCommandLogs(options.CommandLogDir, options.MaxCommandLogs) {
+			if err := w.addFile(path.Join("commands", filepath.Base(logPath)), logPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	envData, err := json.MarshalIndent(makeSupportBundleEnvironment(options.Version), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = w.addContent(supportBundleEnvFileName, envData); err != nil {
+		return err
+	}
+	w.manifest.Files = append(w.manifest.Files, SupportBundleFile{Path: supportBundleEnvFileName, SizeBytes: int64(len(envData))})
+
+	manifestData, err := json.MarshalIndent(&w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return w.addContent(SupportBundleManifestFileName, manifestData)
+}
+
+// findRotatedLogs returns the log and its rotated files, e.g., vcluster.log.1.gz
+func findRotatedLogs(logPath string) []string {
+	rotated, _ := filepath.Glob(logPath + ".*")
+	return append([]string{logPath}, rotated...)
+}
+
+// findCommandLogs returns the most recent logs of the commands in dir
+func (w *supportBundleWriter) findCommandLogs(dir string, maxLogs int) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.manifest.Skipped[dir] = err.Error()
+		return nil
+	}
+	type commandLog struct {
+		path    string
+		modTime time.Time
+	}
+	var logs []commandLog
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".log" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		logs = append(logs, commandLog{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()})
+	}
+	slices.SortFunc(logs, func(a, b commandLog) int { return b.modTime.Compare(a.modTime) })
+	if maxLogs > 0 && len(logs) > maxLogs {
+		logs = logs[:maxLogs]
+	}
+	paths := make([]string, len(logs))
+	for i := range logs {
+		paths[i] = logs[i].path
+	}
+	return paths
+}
+
+// addFile packages the file at sourcePath, cut to its last bytes if it is
+// larger than the maximum size. A file that cannot be read is skipped.
+func (w *supportBundleWriter) addFile(bundlePath, sourcePath string) error {
+	file, err := os.Open(sourcePath)
+	if err != nil {
+		w.manifest.Skipped[sourcePath] = err.Error()
+		return nil
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		w.manifest.Skipped[sourcePath] = err.Error()
+		return nil
+	}
+
+	entry := SupportBundleFile{Path: bundlePath, SourcePath: sourcePath, SizeBytes: info.Size()}
+	if info.Size() > w.maxSize {
+		if _, err = file.Seek(info.Size()-w.maxSize, io.SeekStart); err != nil {
+			w.manifest.Skipped[sourcePath] = err.Error()
+			return nil
+		}
+		entry.SizeBytes, entry.Truncated = w.maxSize, true
+	}
+
+	header := &tar.Header{
+		Name:    path.Join(w.topDir, bundlePath),
+		Mode:    supportBundleFilePerms,
+		Size:    entry.SizeBytes,
+		ModTime: info.ModTime(),
+	}
+	if err = w.tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err = io.CopyN(w.tarWriter, file, entry.SizeBytes); err != nil {
+		return err
+	}
+	w.manifest.Files = append(w.manifest.Files, entry)
+	return nil
+}
+
+// addConfigFile packages the config file with the values of its secret keys redacted
+func (w *supportBundleWriter) addConfigFile(configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		w.manifest.Skipped[configPath] = err.Error()
+		return nil
+	}
+	redacted := redactConfig(data)
+	bundlePath := path.Join("config", filepath.Base(configPath))
+	if err = w.addContent(bundlePath, redacted); err != nil {
+		return err
+	}
+	w.manifest.Files = append(w.manifest.Files, SupportBundleFile{Path: bundlePath, SourcePath: configPath,
+		SizeBytes: int64(len(redacted)), Redacted: !bytes.Equal(data, redacted)})
+	return nil
+}
+
+func (w *supportBundleWriter) addContent(bundlePath string, data []byte) error {
+	header := &tar.Header{
+		Name:    path.Join(w.topDir, bundlePath),
+		Mode:    supportBundleFilePerms,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := w.tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := w.tarWriter.Write(data)
+	return err
+}
+
+// redactConfig replaces the values of the YAML keys that hold secrets, e.g.,
+// "awsauth: id:secret" in the configuration parameters
+func redactConfig(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(value) == "" || !secretKeyPattern.MatchString(key) {
+			continue
+		}
+		lines[i] = key + ": " + redactedValue
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+func makeSupportBundleEnvironment(version string) supportBundleEnvironment {
+	env := supportBundleEnvironment{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Env:       make(map[string]string),
+	}
+	env.Hostname, _ = os.Hostname()
+	env.User = os.Getenv("USER")
+	for _, variable := range os.Environ() {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, "VCLUSTER_") {
+			continue
+		}
+		if secretKeyPattern.MatchString(name) {
+			value = redactedValue
+		}
+		env.Env[name] = value
+	}
+	return env
+}
@@ -0,0 +1,139 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// readSupportBundle returns the files of a bundle by their path below the top level folder
+func readSupportBundle(t *testing.T, bundlePath string) map[string]string {
+	file, err := os.Open(bundlePath)
+	require.NoError(t, err)
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+
+	files := make(map[string]string)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		_, name, _ := strings.Cut(header.Name, "/")
+		files[name] = string(data)
+	}
+	return files
+}
+
+func TestCreateSupportBundle(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "vcluster.log")
+	require.NoError(t, os.WriteFile(logPath, []byte("current log"), 0600))
+	require.NoError(t, os.WriteFile(logPath+".1.gz", []byte("rotated log"), 0600))
+	configPath := filepath.Join(dir, "vertica_cluster.yaml")
+	config := "db_name: test_db\nhosts:\n  - 10.0.0.1\nawsauth: id:secret\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0600))
+
+	commandDir := filepath.Join(dir, "commands")
+	require.NoError(t, os.Mkdir(commandDir, 0700))
+	now := time.Now()
+	for i, name := range []string{"start_db-a.log", "stop_db-b.log", "re_ip-c.log"} {
+		logFile := filepath.Join(commandDir, name)
+		require.NoError(t, os.WriteFile(logFile, []byte(name), 0600))
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(logFile, modTime, modTime))
+	}
+	t.Setenv("VCLUSTER_TLS_MODE", "verify-ca")
+	t.Setenv("VCLUSTER_PASSWORD", "dbadmin-password")
+
+	options := VSupportBundleFactory()
+	options.OutputPath = filepath.Join(dir, "bundle.tar.gz")
+	options.LogPath = logPath
+	options.ConfigPath = configPath
+	options.CommandLogDir = commandDir
+	options.MaxCommandLogs = 2
+	options.Version = "2.0.0"
+	vcc := VClusterCommands{VClusterCommandsLogger{Log: vlog.Printer{}}}
+	manifest, err := vcc.VCreateSupportBundle(&options)
+	require.NoError(t, err)
+
+	files := readSupportBundle(t, options.OutputPath)
+	assert.Equal(t, "current log", files["logs/vcluster.log"])
+	assert.Equal(t, "rotated log", files["logs/vcluster.log.1.gz"])
+	// only the most recent command logs are packaged
+	assert.Contains(t, files, "commands/re_ip-c.log")
+	assert.Contains(t, files, "commands/stop_db-b.log")
+	assert.NotContains(t, files, "commands/start_db-a.log")
+
+	// the secrets of the config file and the environment are redacted
+	assert.Contains(t, files["config/vertica_cluster.yaml"], "db_name: test_db")
+	assert.Contains(t, files["config/vertica_cluster.yaml"], "awsauth: "+redactedValue)
+	assert.NotContains(t, files["config/vertica_cluster.yaml"], "id:secret")
+	var env supportBundleEnvironment
+	require.NoError(t, json.Unmarshal([]byte(files[supportBundleEnvFileName]), &env))
+	assert.Equal(t, "2.0.0", env.Version)
+	assert.Equal(t, "verify-ca", env.Env["VCLUSTER_TLS_MODE"])
+	assert.Equal(t, redactedValue, env.Env["VCLUSTER_PASSWORD"])
+
+	var bundled SupportBundleManifest
+	require.NoError(t, json.Unmarshal([]byte(files[SupportBundleManifestFileName]), &bundled))
+	assert.Len(t, bundled.Files, len(manifest.Files))
+	assert.Len(t, manifest.Files, 6)
+	assert.Empty(t, manifest.Skipped)
+}
+
+func TestCreateSupportBundleSkipsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "vcluster.log")
+	require.NoError(t, os.WriteFile(logPath, []byte("0123456789"), 0600))
+
+	options := VSupportBundleFactory()
+	options.OutputPath = filepath.Join(dir, "bundle.tar.gz")
+	options.LogPath = logPath
+	options.ConfigPath = filepath.Join(dir, "missing.yaml")
+	options.MaxFileSize = 4
+	vcc := VClusterCommands{VClusterCommandsLogger{Log: vlog.Printer{}}}
+	manifest, err := vcc.VCreateSupportBundle(&options)
+	require.NoError(t, err)
+
+	// a missing file is listed in the manifest rather than failing the bundle
+	assert.Contains(t, manifest.Skipped, options.ConfigPath)
+	// a large file is cut to its last bytes
+	files := readSupportBundle(t, options.OutputPath)
+	assert.Equal(t, "6789", files["logs/vcluster.log"])
+	assert.True(t, manifest.Files[0].Truncated)
+
+	options.OutputPath = ""
+	_, err = vcc.VCreateSupportBundle(&options)
+	assert.Error(t, err)
+}
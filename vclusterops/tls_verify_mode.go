@@ -0,0 +1,81 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import "fmt"
+
+// the ways the NMA and HTTPS clients verify the certificates of the servers,
+// see DatabaseOptions.NMATLSVerifyMode and DatabaseOptions.HTTPSTLSVerifyMode
+const (
+	// the certificate must chain to the CA certificate and match the host
+	TLSVerifyFull = "verify-full"
+	// the certificate must chain to the CA certificate, whatever host it is for
+	TLSVerifyCAOnly = "verify-ca"
+	// the certificate must chain to the CA certificate or to a root
+	// certificate of the system, whatever host it is for, e.g., when some
+	// hosts have certificates of a public CA and the others of a private CA
+	TLSVerifyCAOrSystem = "verify-ca-or-system"
+	// the certificate is not verified
+	TLSVerifyInsecure = "insecure"
+)
+
+func validateTLSVerifyMode(mode, client string) error {
+	switch mode {
+	case "", TLSVerifyFull, TLSVerifyCAOnly, TLSVerifyCAOrSystem, TLSVerifyInsecure:
+		return nil
+	}
+	return fmt.Errorf("invalid %s TLS verification mode %q, must be %s, %s, %s, or %s", client, mode,
+		TLSVerifyFull, TLSVerifyCAOnly, TLSVerifyCAOrSystem, TLSVerifyInsecure)
+}
+
+func (opt *DatabaseOptions) validateTLSVerifyModes() error {
+	err := validateTLSVerifyMode(opt.NMATLSVerifyMode, "NMA")
+	if err != nil {
+		return err
+	}
+	return validateTLSVerifyMode(opt.HTTPSTLSVerifyMode, "HTTPS")
+}
+
+// getNMATLSVerifyMode returns the verification mode of the NMA client, which
+// is derived from DoVerifyNMAServerCert and DoVerifyPeerCertHostname if
+// NMATLSVerifyMode is not set
+func (opt *DatabaseOptions) getNMATLSVerifyMode() string {
+	if opt.NMATLSVerifyMode != "" {
+		return opt.NMATLSVerifyMode
+	}
+	return tlsVerifyModeFromSwitches(opt.DoVerifyNMAServerCert, opt.DoVerifyPeerCertHostname)
+}
+
+// getHTTPSTLSVerifyMode returns the verification mode of the HTTPS client,
+// which is derived from DoVerifyHTTPSServerCert and DoVerifyPeerCertHostname
+// if HTTPSTLSVerifyMode is not set
+func (opt *DatabaseOptions) getHTTPSTLSVerifyMode() string {
+	if opt.HTTPSTLSVerifyMode != "" {
+		return opt.HTTPSTLSVerifyMode
+	}
+	return tlsVerifyModeFromSwitches(opt.DoVerifyHTTPSServerCert, opt.DoVerifyPeerCertHostname)
+}
+
+func tlsVerifyModeFromSwitches(doVerify, doVerifyHostname bool) string {
+	switch {
+	case !doVerify:
+		return TLSVerifyInsecure
+	case doVerifyHostname:
+		return TLSVerifyFull
+	default:
+		return TLSVerifyCAOnly
+	}
+}
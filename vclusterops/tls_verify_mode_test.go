@@ -0,0 +1,51 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSVerifyModes(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+
+	// without modes, the switches apply to both clients
+	assert.Equal(t, &tlsModes{nmaVerifyMode: TLSVerifyInsecure, httpsVerifyMode: TLSVerifyInsecure}, opt.getTLSModes())
+	opt.DoVerifyNMAServerCert = true
+	opt.DoVerifyHTTPSServerCert = true
+	assert.Equal(t, &tlsModes{nmaVerifyMode: TLSVerifyCAOnly, httpsVerifyMode: TLSVerifyCAOnly}, opt.getTLSModes())
+	opt.DoVerifyPeerCertHostname = true
+	assert.Equal(t, &tlsModes{nmaVerifyMode: TLSVerifyFull, httpsVerifyMode: TLSVerifyFull}, opt.getTLSModes())
+
+	// a mode takes precedence over the switches for its client only
+	opt.NMATLSVerifyMode = TLSVerifyCAOrSystem
+	assert.NoError(t, opt.validateTLSVerifyModes())
+	modes := opt.getTLSModes()
+	assert.Equal(t, &tlsModes{nmaVerifyMode: TLSVerifyCAOrSystem, httpsVerifyMode: TLSVerifyFull}, modes)
+
+	nmaRequest := hostHTTPRequest{IsNMACommand: true}
+	nmaRequest.setTLSMode(modes)
+	assert.Equal(t, TLSVerifyCAOrSystem, nmaRequest.TLSVerifyMode)
+	httpsRequest := hostHTTPRequest{}
+	httpsRequest.setTLSMode(modes)
+	assert.Equal(t, TLSVerifyFull, httpsRequest.TLSVerifyMode)
+
+	// negative: unknown mode
+	opt.HTTPSTLSVerifyMode = "verify-some"
+	assert.ErrorContains(t, opt.validateTLSVerifyModes(), `invalid HTTPS TLS verification mode "verify-some"`)
+}
@@ -0,0 +1,204 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+const (
+	topologyLockFileName = "topology_lock.json"
+	// DefaultTopologyLockTTL is how long the topology lock is held when
+	// DatabaseOptions.TopologyLockTTL is not set
+	DefaultTopologyLockTTL = 30 * time.Minute
+	// the lease is renewed this many times per TTL while the command runs
+	topologyLockRenewalsPerTTL = 3
+)
+
+type (
+	TopologyLock          = vtypes.TopologyLock
+	TopologyLockHeldError = vtypes.TopologyLockHeldError
+)
+
+// getTopologyLockPath returns the path of the lease object of the topology lock:
+// {communal_storage_location}/metadata/{db_name}/topology_lock.json
+func (opt *DatabaseOptions) getTopologyLockPath() string {
+	lockPath := filepath.Join(opt.CommunalStorageLocation, descriptionFileMetadataFolder, opt.DBName, topologyLockFileName)
+	// filepath.Join() will change "://" of the remote communal storage path to ":/"
+	// as a result, we need to change the separator back to url format
+	return strings.Replace(lockPath, ":/", "://", 1)
+}
+
+// makeTopologyLock returns a new lease of this process for a command
+func (opt *DatabaseOptions) makeTopologyLock(cmdType CmdType) TopologyLock {
+	owner, err := util.GetCurrentUsername()
+	if err != nil {
+		owner = "unknown"
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	ttl := opt.TopologyLockTTL
+	if ttl <= 0 {
+		ttl = DefaultTopologyLockTTL
+	}
+	now := time.Now().UTC()
+	return TopologyLock{
+		Token:     generateRequestID(),
+		Owner:     owner,
+		Host:      host,
+		PID:       os.Getpid(),
+		Command:   cmdType.CmdString(),
+		RequestID: opt.getRequestID(),
+		StartTime: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// topologyLease is the topology lock held by a command. It is renewed in the
+// background until it is released, so that it does not lapse while the
+// command runs for longer than its TTL.
+type topologyLease struct {
+	vcc VClusterCommands
+	// the ops of the lease run with their own options, as they run at the
+	// same time as the ops of the command
+	options DatabaseOptions
+	ttl     time.Duration
+	stop    chan struct{}
+	done    chan struct{}
+
+	mu   sync.Mutex
+	lock TopologyLock
+}
+
+// takeTopologyLock takes the topology lock of the database for a command, if
+// the options ask for it. It fails with a TopologyLockHeldError when a command
+// of another process holds the lock. The lock is renewed until the returned
+// function releases it.
+func (vcc VClusterCommands) takeTopologyLock(options *DatabaseOptions, cmdType CmdType) (release func(), err error) {
+	lease, err := vcc.takeTopologyLease(options, cmdType)
+	if err != nil || lease == nil {
+		return func() {}, err
+	}
+	return lease.release, nil
+}
+
+// takeTopologyLease is takeTopologyLock for the commands that move the lease,
+// e.g., when they rename the database. The lease is nil if the options do
+// not ask for the lock.
+func (vcc VClusterCommands) takeTopologyLease(options *DatabaseOptions, cmdType CmdType) (*topologyLease, error) {
+	// nothing is changed in read-only mode
+	if !options.UseTopologyLock || options.isReadOnly() {
+		return nil, nil
+	}
+	if options.CommunalStorageLocation == "" {
+		return nil, errors.New("must specify the communal storage location to take the topology lock")
+	}
+
+	lease := &topologyLease{
+		vcc:     vcc,
+		options: options.copyForSideOps(),
+		lock:    options.makeTopologyLock(cmdType),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	lease.ttl = lease.lock.ExpiresAt.Sub(lease.lock.StartTime)
+	err := vcc.runTopologyLockOp(&lease.options, &lease.lock, false /*release*/)
+	if err != nil {
+		return nil, fmt.Errorf("fail to take the topology lock, %w", err)
+	}
+	vcc.Log.Info("topology lock taken", "lockPath", lease.options.getTopologyLockPath(), "expiresAt", lease.lock.ExpiresAt)
+
+	go lease.renew()
+	return lease, nil
+}
+
+// renew extends the lease every fraction of its TTL until it is released. It
+// stops if another command took the lock after the lease lapsed.
+func (lease *topologyLease) renew() {
+	defer close(lease.done)
+	ticker := time.NewTicker(lease.ttl / topologyLockRenewalsPerTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lease.stop:
+			return
+		case <-ticker.C:
+		}
+
+		lease.mu.Lock()
+		lease.lock.ExpiresAt = time.Now().UTC().Add(lease.ttl)
+		err := lease.vcc.runTopologyLockOp(&lease.options, &lease.lock, false /*release*/)
+		lease.mu.Unlock()
+
+		heldErr := &TopologyLockHeldError{}
+		if errors.As(err, &heldErr) {
+			lease.vcc.Log.PrintError("the topology lock lapsed and is now held by another command, details: %v", err)
+			return
+		}
+		if err != nil {
+			lease.vcc.Log.PrintWarning("fail to renew the topology lock, it will be retried, details: %v", err)
+			continue
+		}
+		lease.vcc.Log.Info("topology lock renewed", "expiresAt", lease.lock.ExpiresAt)
+	}
+}
+
+// moveTo points the lease to the lock of the database named dbName, after
+// the command moved the metadata of the database, and the lease with it
+func (lease *topologyLease) moveTo(dbName string) {
+	if lease == nil {
+		return
+	}
+	lease.mu.Lock()
+	defer lease.mu.Unlock()
+	lease.options.DBName = dbName
+}
+
+// release stops the renewal of the lease and releases it
+func (lease *topologyLease) release() {
+	if lease == nil {
+		return
+	}
+	close(lease.stop)
+	<-lease.done
+
+	if e := lease.vcc.runTopologyLockOp(&lease.options, &lease.lock, true /*release*/); e != nil {
+		lease.vcc.Log.PrintWarning("fail to release the topology lock, it will expire at %s, details: %v",
+			lease.lock.ExpiresAt.Format(time.RFC3339), e)
+	}
+}
+
+func (vcc VClusterCommands) runTopologyLockOp(options *DatabaseOptions, lock *TopologyLock, release bool) error {
+	nmaTopologyLockOp, err := makeNMATopologyLockOp(options.Hosts, options.DBName, options.getTopologyLockPath(),
+		lock, options.ConfigurationParameters, release)
+	if err != nil {
+		return err
+	}
+	clusterOpEngine := makeClusterOpEngine([]clusterOp{&nmaTopologyLockOp}, options)
+	return clusterOpEngine.run(vcc.Log)
+}
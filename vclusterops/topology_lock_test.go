@@ -0,0 +1,139 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestTopologyLockPath(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.DBName = testDBName
+	opt.CommunalStorageLocation = "s3://bucket/communal"
+	assert.Equal(t, "s3://bucket/communal/metadata/"+testDBName+"/topology_lock.json", opt.getTopologyLockPath())
+
+	opt.RequestID = "vc-0123456789abcdef"
+	lock := opt.makeTopologyLock(AddNodeCmd)
+	assert.Equal(t, "add_node", lock.Command)
+	assert.Equal(t, opt.RequestID, lock.RequestID)
+	assert.NotEmpty(t, lock.Token)
+	assert.Equal(t, DefaultTopologyLockTTL, lock.ExpiresAt.Sub(lock.StartTime))
+
+	opt.TopologyLockTTL = time.Minute
+	lock = opt.makeTopologyLock(AddNodeCmd)
+	assert.Equal(t, time.Minute, lock.ExpiresAt.Sub(lock.StartTime))
+}
+
+func TestNMATopologyLockOp(t *testing.T) {
+	const host = "192.168.1.101"
+	opt := DatabaseOptionsFactory()
+	opt.DBName = testDBName
+	opt.CommunalStorageLocation = "s3://bucket/communal"
+	lock := opt.makeTopologyLock(RemoveNodeCmd)
+
+	op, err := makeNMATopologyLockOp([]string{host}, testDBName, opt.getTopologyLockPath(), &lock, nil, false)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	request := op.clusterHTTPRequest.RequestCollection[host]
+	assert.Equal(t, PostMethod, request.Method)
+	requestData := topologyLockRequestData{}
+	assert.NoError(t, json.Unmarshal([]byte(request.RequestData), &requestData))
+	assert.Equal(t, lock.Token, requestData.Lock.Token)
+	assert.Positive(t, requestData.TTLSeconds)
+	// the lease is written to communal storage
+	assert.True(t, op.getClassification().Mutating)
+
+	// the lease in communal storage is ours
+	content, err := json.Marshal(lock)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: {status: SUCCESS, content: string(content)}}
+	assert.NoError(t, op.processResult(nil))
+
+	// negative: another process holds the lock
+	holder := lock
+	holder.Token = "vc-fedcba9876543210"
+	holder.Host = "admin-2"
+	content, err = json.Marshal(holder)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.ResultCollection = map[string]hostHTTPResult{host: {status: SUCCESS, content: string(content)}}
+	err = op.processResult(nil)
+	heldErr := &TopologyLockHeldError{}
+	assert.True(t, errors.As(err, &heldErr))
+	assert.Equal(t, "admin-2", heldErr.Holder.Host)
+	assert.ErrorContains(t, err, "the topology of database "+testDBName+" is locked by")
+
+	// the lock is released with its token only
+	op, err = makeNMATopologyLockOp([]string{host}, testDBName, opt.getTopologyLockPath(), &lock, nil, true)
+	assert.NoError(t, err)
+	op.clusterHTTPRequest.RequestCollection = map[string]hostHTTPRequest{}
+	assert.NoError(t, op.setupClusterHTTPRequest(op.hosts))
+	request = op.clusterHTTPRequest.RequestCollection[host]
+	assert.Equal(t, DeleteMethod, request.Method)
+	assert.NotContains(t, request.RequestData, `"lock"`)
+}
+
+func TestTakeTopologyLockOptions(t *testing.T) {
+	vcc := VClusterCommands{}
+	vcc.Log = vlog.Printer{}
+	opt := DatabaseOptionsFactory()
+	opt.DBName = testDBName
+
+	// the lock is not taken by default, or in read-only mode
+	_, err := vcc.takeTopologyLock(&opt, AddNodeCmd)
+	assert.NoError(t, err)
+	opt.UseTopologyLock = true
+	opt.ReadOnly = true
+	_, err = vcc.takeTopologyLock(&opt, AddNodeCmd)
+	assert.NoError(t, err)
+
+	// negative: the lock needs communal storage
+	opt.ReadOnly = false
+	_, err = vcc.takeTopologyLock(&opt, AddNodeCmd)
+	assert.ErrorContains(t, err, "must specify the communal storage location")
+}
+
+func TestTopologyLockOptions(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.DBName = testDBName
+	opt.CommunalStorageLocation = "s3://bucket/communal"
+	opt.Hosts = []string{"192.168.1.101"}
+	opt.ApprovedPlan = &ExecutionPlan{}
+	opt.HealthGate = &HealthGate{}
+	opt.MaintenancePolicy = &MaintenancePolicy{}
+	opt.Timings = &CommandTimings{}
+
+	// the lease is renewed while the command runs, without the gates of the
+	// command, and without sharing its hosts
+	lockOptions := opt.copyForSideOps()
+	assert.Nil(t, lockOptions.getApprovedPlan())
+	assert.Nil(t, lockOptions.getHealthGate())
+	assert.Nil(t, lockOptions.getMaintenancePolicy())
+	assert.Nil(t, lockOptions.getTimings())
+	opt.Hosts[0] = "192.168.1.102"
+	assert.Equal(t, []string{"192.168.1.101"}, lockOptions.Hosts)
+
+	// the lease follows the metadata of a renamed database
+	lease := &topologyLease{options: lockOptions}
+	lease.moveTo("newdbname")
+	assert.Equal(t, "s3://bucket/communal/metadata/newdbname/topology_lock.json", lease.options.getTopologyLockPath())
+}
@@ -0,0 +1,294 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	TopologySimulation = vtypes.TopologySimulation
+	SimulatedNode      = vtypes.SimulatedNode
+)
+
+// TopologyChange is a proposed change of the nodes of a database, whose
+// outcome can be predicted by SimulateTopologyChange
+type TopologyChange struct {
+	// the addresses of the nodes to remove
+	RemoveHosts []string
+	// the subclusters to remove along with all of their nodes
+	RemoveSubclusters []string
+	// the subclusters to add
+	AddSubclusters []SimulatedSubcluster
+}
+
+// SimulatedSubcluster is a subcluster that a TopologyChange adds
+type SimulatedSubcluster struct {
+	Name      string
+	Hosts     []string
+	IsPrimary bool
+	// the number of control nodes of the subcluster, see
+	// VAddSubclusterOptions.ControlSetSize. All the nodes of the subcluster
+	// are control nodes by default, within the limit of a large cluster.
+	ControlSetSize int
+}
+
+// SimulateTopologyChange predicts the K-safety, the shard subscriptions, and
+// the control nodes of the database in vdb after the change, e.g., for
+// capacity planning. The cluster is not touched: vdb is usually the result of
+// VFetchCoordinationDatabase. The prediction follows the rules of vcluster
+// and the default rebalancing of the shards, so the actual subscriptions can
+// differ once the shards are rebalanced by the database.
+func SimulateTopologyChange(vdb *VCoordinationDatabase, change *TopologyChange) (*TopologySimulation, error) {
+	if vdb == nil || len(vdb.HostNodeMap) == 0 {
+		return nil, fmt.Errorf("the database to simulate the change on has no nodes")
+	}
+	if vdb.IsEon && vdb.NumShards <= 0 {
+		return nil, fmt.Errorf("the number of shards of the Eon database is unknown")
+	}
+
+	nodes, err := applyTopologyChange(vdb, change)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &TopologySimulation{
+		KSafetyBefore: predictKSafety(vdb.HostList, vdb.ComputeNodes),
+		KSafety:       predictKSafety(simulatedAddresses(nodes), vdb.ComputeNodes),
+	}
+	for i := range nodes {
+		if nodes[i].IsPrimary {
+			sim.PrimaryNodes++
+		}
+	}
+	if sim.PrimaryNodes == 0 {
+		return nil, fmt.Errorf("the change leaves the database without a primary node")
+	}
+	if sim.KSafety < sim.KSafetyBefore {
+		sim.Warnings = append(sim.Warnings,
+			fmt.Sprintf("the K-safety of the design would be lowered from %d to %d", sim.KSafetyBefore, sim.KSafety))
+	}
+	if vdb.IsEon {
+		sim.Warnings = append(sim.Warnings, simulateShardSubscriptions(nodes, vdb.NumShards, sim.KSafety)...)
+	}
+	sim.ControlNodes = simulateControlNodes(vdb, nodes, change.AddSubclusters)
+	sim.Nodes = nodes
+	return sim, nil
+}
+
+// applyTopologyChange returns the nodes of vdb after the change, sorted by
+// subcluster and address
+func applyTopologyChange(vdb *VCoordinationDatabase, change *TopologyChange) ([]SimulatedNode, error) {
+	for _, host := range change.RemoveHosts {
+		if _, ok := vdb.HostNodeMap[host]; !ok {
+			return nil, fmt.Errorf("host %s to remove is not in the database", host)
+		}
+	}
+	subclusters := make(map[string]bool)
+	for _, vnode := range vdb.HostNodeMap {
+		subclusters[vnode.Subcluster] = true
+	}
+	for _, sc := range change.RemoveSubclusters {
+		if !subclusters[sc] {
+			return nil, fmt.Errorf("subcluster %s to remove is not in the database", sc)
+		}
+	}
+
+	var nodes []SimulatedNode
+	for host, vnode := range vdb.HostNodeMap {
+		if slices.Contains(change.RemoveHosts, host) || slices.Contains(change.RemoveSubclusters, vnode.Subcluster) {
+			continue
+		}
+		nodes = append(nodes, SimulatedNode{
+			Name:       vnode.Name,
+			Address:    host,
+			Subcluster: vnode.Subcluster,
+			IsPrimary:  vnode.IsPrimary,
+		})
+	}
+	for _, sc := range change.AddSubclusters {
+		if sc.Name == "" || len(sc.Hosts) == 0 {
+			return nil, fmt.Errorf("a subcluster to add must have a name and hosts")
+		}
+		if subclusters[sc.Name] && !slices.Contains(change.RemoveSubclusters, sc.Name) {
+			return nil, fmt.Errorf("subcluster %s to add is already in the database", sc.Name)
+		}
+		subclusters[sc.Name] = true
+		for _, host := range sc.Hosts {
+			if slices.ContainsFunc(nodes, func(n SimulatedNode) bool { return n.Address == host }) {
+				return nil, fmt.Errorf("host %s of subcluster %s is already in the database", host, sc.Name)
+			}
+			nodes = append(nodes, SimulatedNode{
+				Address:    host,
+				Subcluster: sc.Name,
+				IsPrimary:  sc.IsPrimary,
+				IsNew:      true,
+			})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Subcluster != nodes[j].Subcluster {
+			return nodes[i].Subcluster < nodes[j].Subcluster
+		}
+		return nodes[i].Address < nodes[j].Address
+	})
+	return nodes, nil
+}
+
+// predictKSafety returns the K-safety that vcluster marks the design with for
+// the hosts, as the compute nodes do not count
+func predictKSafety(hosts, computeNodes []string) int {
+	permanent := 0
+	for _, host := range hosts {
+		if !slices.Contains(computeNodes, host) {
+			permanent++
+		}
+	}
+	if permanent >= ksafetyThreshold {
+		return ksafeValueOne
+	}
+	return ksafeValueZero
+}
+
+// simulateShardSubscriptions spreads the segment shards over the nodes of
+// each subcluster, every shard being subscribed by K-safety+1 nodes of the
+// subcluster, and returns the warnings about the resulting layout
+func simulateShardSubscriptions(nodes []SimulatedNode, numShards, kSafety int) (warnings []string) {
+	bySubcluster := make(map[string][]int)
+	var subclusters []string
+	for i := range nodes {
+		sc := nodes[i].Subcluster
+		if _, ok := bySubcluster[sc]; !ok {
+			subclusters = append(subclusters, sc)
+		}
+		bySubcluster[sc] = append(bySubcluster[sc], i)
+	}
+
+	for _, sc := range subclusters {
+		members := bySubcluster[sc]
+		subscribers := min(kSafety+1, len(members))
+		if subscribers < kSafety+1 {
+			warnings = append(warnings, fmt.Sprintf("the shards of subcluster %s would have %d subscriber(s) at K-safety %d",
+				sc, subscribers, kSafety))
+		}
+		for shard := 0; shard < numShards; shard++ {
+			for r := 0; r < subscribers; r++ {
+				nodes[members[(shard*subscribers+r)%len(members)]].ShardSubscriptions++
+			}
+		}
+		for _, i := range members {
+			if nodes[i].ShardSubscriptions == 0 {
+				warnings = append(warnings, fmt.Sprintf("node %s of subcluster %s would subscribe to no shard, "+
+					"as the subcluster has more nodes than shard subscriptions", nodes[i].Address, sc))
+			}
+		}
+	}
+	return warnings
+}
+
+// simulateControlNodes assigns a control node to each of the nodes and
+// returns the addresses of the control nodes. The existing nodes keep their
+// control node unless it is removed, in which case they move to a control
+// node of their subcluster.
+func simulateControlNodes(vdb *VCoordinationDatabase, nodes []SimulatedNode, added []SimulatedSubcluster) []string {
+	// the control node of a node can be given by name or address
+	nameToAddress := make(map[string]string)
+	hasControlNodes := false
+	for host, vnode := range vdb.HostNodeMap {
+		nameToAddress[vnode.Name] = host
+		nameToAddress[host] = host
+		hasControlNodes = hasControlNodes || vnode.IsControlNode
+	}
+
+	isControl := make(map[string]bool)
+	for i := range nodes {
+		if nodes[i].IsNew {
+			continue
+		}
+		// every node is its own control node by default
+		vnode := vdb.HostNodeMap[nodes[i].Address]
+		if !hasControlNodes || vnode.IsControlNode {
+			isControl[nodes[i].Address] = true
+		}
+	}
+	for _, sc := range added {
+		controlSetSize := sc.ControlSetSize
+		if controlSetSize <= 0 {
+			controlSetSize = util.MaxLargeCluster - len(isControl)
+		}
+		hosts := slices.Clone(sc.Hosts)
+		slices.Sort(hosts)
+		for _, host := range hosts[:max(1, min(controlSetSize, len(hosts)))] {
+			isControl[host] = true
+		}
+	}
+
+	controlNodes := make([]string, 0, len(isControl))
+	for host := range isControl {
+		controlNodes = append(controlNodes, host)
+	}
+	slices.Sort(controlNodes)
+
+	// the nodes without a control node are spread over the control nodes of
+	// their subcluster, or of the database if the subcluster has none
+	for i := range nodes {
+		nodes[i].IsControlNode = isControl[nodes[i].Address]
+	}
+	next := make(map[string]int)
+	for i := range nodes {
+		node := &nodes[i]
+		if node.IsControlNode {
+			node.ControlNode = node.Address
+			continue
+		}
+		if !node.IsNew {
+			current := nameToAddress[vdb.HostNodeMap[node.Address].ControlNode]
+			if isControl[current] {
+				node.ControlNode = current
+				continue
+			}
+		}
+		candidates := subclusterControlNodes(nodes, node.Subcluster)
+		if len(candidates) == 0 {
+			candidates = controlNodes
+		}
+		node.ControlNode = candidates[next[node.Subcluster]%len(candidates)]
+		next[node.Subcluster]++
+	}
+	return controlNodes
+}
+
+func subclusterControlNodes(nodes []SimulatedNode, subcluster string) (controlNodes []string) {
+	for i := range nodes {
+		if nodes[i].Subcluster == subcluster && nodes[i].IsControlNode {
+			controlNodes = append(controlNodes, nodes[i].Address)
+		}
+	}
+	return controlNodes
+}
+
+func simulatedAddresses(nodes []SimulatedNode) []string {
+	addresses := make([]string, len(nodes))
+	for i := range nodes {
+		addresses[i] = nodes[i].Address
+	}
+	return addresses
+}
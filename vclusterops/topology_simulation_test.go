@@ -0,0 +1,104 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTopologySimulationVDB() *VCoordinationDatabase {
+	vdb := makeVCoordinationDatabase()
+	vdb.IsEon = true
+	vdb.NumShards = 6
+	vdb.HostNodeMap = map[string]*VCoordinationNode{
+		"10.0.0.1": {Name: "v_db_node0001", Address: "10.0.0.1", Subcluster: "default", IsPrimary: true, IsControlNode: true},
+		"10.0.0.2": {Name: "v_db_node0002", Address: "10.0.0.2", Subcluster: "default", IsPrimary: true, IsControlNode: true},
+		"10.0.0.3": {Name: "v_db_node0003", Address: "10.0.0.3", Subcluster: "default", IsPrimary: true, IsControlNode: true},
+		"10.0.0.4": {Name: "v_db_node0004", Address: "10.0.0.4", Subcluster: "etl", IsControlNode: true},
+		"10.0.0.5": {Name: "v_db_node0005", Address: "10.0.0.5", Subcluster: "etl", ControlNode: "v_db_node0004"},
+	}
+	vdb.HostList = []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5"}
+	return &vdb
+}
+
+func TestSimulateTopologyChange(t *testing.T) {
+	vdb := makeTopologySimulationVDB()
+
+	// no change keeps the layout
+	sim, err := SimulateTopologyChange(vdb, &TopologyChange{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sim.KSafetyBefore)
+	assert.Equal(t, 1, sim.KSafety)
+	assert.Equal(t, 3, sim.PrimaryNodes)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}, sim.ControlNodes)
+	assert.Empty(t, sim.Warnings)
+	for _, node := range sim.Nodes {
+		if node.Subcluster == "default" {
+			// 6 shards with 2 subscribers over 3 nodes
+			assert.Equal(t, 4, node.ShardSubscriptions)
+		} else {
+			assert.Equal(t, 6, node.ShardSubscriptions)
+		}
+	}
+	assert.Equal(t, "10.0.0.4", sim.Nodes[4].ControlNode)
+
+	// removing the control node of a subcluster moves its nodes to another one
+	sim, err = SimulateTopologyChange(vdb, &TopologyChange{RemoveHosts: []string{"10.0.0.3", "10.0.0.4"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sim.KSafety)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, sim.ControlNodes)
+	require.Len(t, sim.Nodes, 3)
+	assert.Equal(t, "10.0.0.5", sim.Nodes[2].Address)
+	assert.Equal(t, "10.0.0.1", sim.Nodes[2].ControlNode)
+	assert.Equal(t, []string{"the shards of subcluster etl would have 1 subscriber(s) at K-safety 1"}, sim.Warnings)
+
+	// too few nodes are left for K-safety 1
+	sim, err = SimulateTopologyChange(vdb, &TopologyChange{RemoveHosts: []string{"10.0.0.2", "10.0.0.3", "10.0.0.4"}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, sim.KSafety)
+	assert.Contains(t, sim.Warnings, "the K-safety of the design would be lowered from 1 to 0")
+
+	// a new subcluster with more nodes than shard subscriptions
+	hosts := []string{"10.0.1.1", "10.0.1.2", "10.0.1.3", "10.0.1.4", "10.0.1.5", "10.0.1.6", "10.0.1.7",
+		"10.0.1.8", "10.0.1.9", "10.0.1.10", "10.0.1.11", "10.0.1.12", "10.0.1.13"}
+	sim, err = SimulateTopologyChange(vdb, &TopologyChange{
+		RemoveSubclusters: []string{"etl"},
+		AddSubclusters:    []SimulatedSubcluster{{Name: "analytics", Hosts: hosts, ControlSetSize: 2}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, sim.Nodes, 16)
+	assert.Len(t, sim.ControlNodes, 5)
+	assert.Len(t, sim.Warnings, 1)
+	for _, node := range sim.Nodes {
+		if node.Subcluster == "analytics" {
+			assert.True(t, node.IsNew)
+			assert.Contains(t, []string{"10.0.1.1", "10.0.1.10"}, node.ControlNode)
+		}
+	}
+
+	// invalid changes
+	_, err = SimulateTopologyChange(vdb, &TopologyChange{RemoveHosts: []string{"10.0.0.9"}})
+	assert.ErrorContains(t, err, "host 10.0.0.9 to remove is not in the database")
+	_, err = SimulateTopologyChange(vdb, &TopologyChange{RemoveSubclusters: []string{"default"}})
+	assert.ErrorContains(t, err, "without a primary node")
+	_, err = SimulateTopologyChange(vdb, &TopologyChange{
+		AddSubclusters: []SimulatedSubcluster{{Name: "etl", Hosts: []string{"10.0.1.1"}}},
+	})
+	assert.ErrorContains(t, err, "subcluster etl to add is already in the database")
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/vertica/vcluster/rfc7807"
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
 )
 
 type VUnsandboxOptions struct {
@@ -107,15 +108,7 @@ func (options *VUnsandboxOptions) ValidateAnalyzeOptions(logger vlog.Printer) er
 	return options.analyzeOptions()
 }
 
-// SubclusterNotSandboxedError is the error that is returned when
-// the subcluster does not need unsandbox operation
-type SubclusterNotSandboxedError struct {
-	SCName string
-}
-
-func (e *SubclusterNotSandboxedError) Error() string {
-	return fmt.Sprintf(`cannot unsandbox a regular subcluster [%s]`, e.SCName)
-}
+type SubclusterNotSandboxedError = vtypes.SubclusterNotSandboxedError
 
 // unsandboxPreCheck will build a list of instructions to perform
 // unsandbox_subcluster pre-checks
@@ -294,10 +287,16 @@ func (vcc VClusterCommands) VUnsandbox(options *VUnsandboxOptions) error {
 
 // runCommand will produce instructions and run them
 func (options *VUnsandboxOptions) runCommand(vcc VClusterCommands) error {
+	releaseLock, err := vcc.takeTopologyLock(&options.DatabaseOptions, UnsandboxSCCmd)
+	if err != nil {
+		return err
+	}
+	defer releaseLock()
+
 	// if the users want to do re-ip before unsandboxing, we require them
 	// to provide some node information
 	if options.PrimaryUpHost != "" && len(options.NodeNameAddressMap) > 0 {
-		err := vcc.reIP(&options.DatabaseOptions, options.SCName, options.PrimaryUpHost,
+		err = vcc.reIP(&options.DatabaseOptions, options.SCName, options.PrimaryUpHost,
 			options.NodeNameAddressMap, true /*reload spread*/)
 		if err != nil {
 			return err
@@ -305,7 +304,7 @@ func (options *VUnsandboxOptions) runCommand(vcc VClusterCommands) error {
 	}
 
 	vdb := makeVCoordinationDatabase()
-	err := vcc.unsandboxPreCheck(&vdb, options)
+	err = vcc.unsandboxPreCheck(&vdb, options)
 	if err != nil {
 		return err
 	}
@@ -32,6 +32,7 @@ const (
 	DefaultLoadCatalogTimeoutSeconds = 3600
 	DefaultStatePollingTimeout       = 1200
 	DefaultLargeCluster              = -1
+	DefaultKSafety                   = -1
 	DefaultP2p                       = true
 	DefaultSpreadLoggingLevel        = -1
 	MaxLargeCluster                  = 120
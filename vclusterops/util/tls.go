@@ -46,3 +46,23 @@ func GenerateTLSVerifyFunc(rootCAs *x509.CertPool) func([][]byte, [][]*x509.Cert
 		return nil
 	}
 }
+
+// GenerateTLSVerifyFuncWithSystemRoots is like GenerateTLSVerifyFunc, but it
+// also accepts the certificates that chain to a root certificate of the system,
+// e.g., when some hosts have certificates of a public CA and the others of a
+// private CA.
+func GenerateTLSVerifyFuncWithSystemRoots(rootCAs *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	verifyWithRootCAs := GenerateTLSVerifyFunc(rootCAs)
+	// without roots, the verification uses the root certificates of the system
+	verifyWithSystemRoots := GenerateTLSVerifyFunc(nil)
+	return func(certificates [][]byte, verifiedChains [][]*x509.Certificate) error {
+		err := verifyWithRootCAs(certificates, verifiedChains)
+		if err == nil {
+			return nil
+		}
+		if verifyWithSystemRoots(certificates, verifiedChains) == nil {
+			return nil
+		}
+		return err
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"io/fs"
 	"log"
 	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -31,10 +32,12 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"golang.org/x/exp/constraints"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sys/unix"
+	"golang.org/x/text/unicode/norm"
 
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -67,6 +70,7 @@ const (
 	NodesEndpoint         = "nodes/"
 	DropEndpoint          = "/drop"
 	ArchiveEndpoint       = "archives"
+	UsersEndpoint         = "users/"
 )
 
 const (
@@ -359,8 +363,11 @@ func ResolveToOneIP(hostname string, ipv6 bool) (string, error) {
 }
 
 // resolve RawHosts to be IP addresses
+// The returned addresses are in canonical form and deduplicated, so two aliases of
+// the same host (e.g., a hostname and its IP) end up as a single address.
 func ResolveRawHostsToAddresses(rawHosts []string, ipv6 bool) ([]string, error) {
 	var hostAddresses []string
+	seen := make(map[string]struct{}, len(rawHosts))
 
 	for _, host := range rawHosts {
 		if host == "" {
@@ -373,6 +380,11 @@ func ResolveRawHostsToAddresses(rawHosts []string, ipv6 bool) ([]string, error)
 		if err != nil {
 			return hostAddresses, err
 		}
+		addr = CanonicalizeAddress(addr)
+		if _, found := seen[addr]; found {
+			continue
+		}
+		seen[addr] = struct{}{}
 		// use a list to respect user input order
 		hostAddresses = append(hostAddresses, addr)
 	}
@@ -380,6 +392,69 @@ func ResolveRawHostsToAddresses(rawHosts []string, ipv6 bool) ([]string, error)
 	return hostAddresses, nil
 }
 
+// SplitHostPort splits a host list entry of the form host, host:port, or
+// [host]:port. The returned port is 0 when the entry has no port. An IPv6
+// address with a port must be enclosed in square brackets, as a bare IPv6
+// address is taken as a host with no port.
+func SplitHostPort(rawHost string) (host string, port int, err error) {
+	if !strings.HasPrefix(rawHost, "[") && strings.Count(rawHost, ":") != 1 {
+		return rawHost, 0, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(rawHost)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid host %s: %w", rawHost, err)
+	}
+	port, err = strconv.Atoi(portStr)
+	if err != nil || ValidatePort(port, "port") != nil {
+		return "", 0, fmt.Errorf("invalid port %q in host %s", portStr, rawHost)
+	}
+	return host, port, nil
+}
+
+// ValidateSOCKS5ProxyURL checks that a proxy URL is in the form of
+// socks5://[user:password@]host:port, or socks5h:// to resolve host names
+// on the proxy side
+func ValidateSOCKS5ProxyURL(proxyURL string) error {
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if parsedURL.Scheme != "socks5" && parsedURL.Scheme != "socks5h" {
+		return fmt.Errorf("invalid proxy URL %s, the scheme must be socks5 or socks5h", parsedURL.Redacted())
+	}
+	if parsedURL.Hostname() == "" || parsedURL.Port() == "" {
+		return fmt.Errorf("invalid proxy URL %s, must specify the proxy host and port", parsedURL.Redacted())
+	}
+	return nil
+}
+
+// CanonicalizeAddress returns the canonical text form of an IP address, so that
+// different spellings of the same IPv6 address compare equal. Values that are not
+// IP addresses are returned unchanged.
+func CanonicalizeAddress(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return address
+	}
+	return ip.String()
+}
+
+// DedupHosts removes duplicate hosts from a list, keeping the first occurrence
+// of each host so that the user input order is respected
+func DedupHosts(hosts []string) []string {
+	var dedupHosts []string
+	seen := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		if _, found := seen[host]; found {
+			continue
+		}
+		seen[host] = struct{}{}
+		dedupHosts = append(dedupHosts, host)
+	}
+	return dedupHosts
+}
+
 // replace all '//' to be '/', trim the path string
 func GetCleanPath(path string) string {
 	if path == "" {
@@ -398,7 +473,7 @@ func AbsPathCheck(dirPath string) error {
 	return nil
 }
 
-// ParseHostList will trim spaces and convert all chars to lowercase in the hosts
+// ParseHostList will trim spaces, convert all chars to lowercase and remove duplicates in the hosts
 func ParseHostList(hosts *[]string) error {
 	var parsedHosts []string
 	for _, host := range *hosts {
@@ -411,7 +486,7 @@ func ParseHostList(hosts *[]string) error {
 		return fmt.Errorf("must specify a host or host list")
 	}
 
-	*hosts = parsedHosts
+	*hosts = DedupHosts(parsedHosts)
 	return nil
 }
 
@@ -533,42 +608,129 @@ func ValidateName(name, obj string, allowDash bool) error {
 	return nil
 }
 
-// ValidateQualifiedObjectNamePattern will validate the pattern of [.namespace].schema.table, separated by ","
-// Return nil when its valid, else will panic
+// ValidateQualifiedObjectNamePattern will validate the pattern of [.namespace].schema.table, separated by ",".
+// Each identifier in the pattern is either unquoted, or double-quoted to hold any unicode character
+// including dots, commas, and double quotes escaped as "". A '*' in a quoted identifier is literal,
+// not a wildcard.
+// Return nil when its valid, else will return an error
 func ValidateQualifiedObjectNamePattern(pattern string, allowAsterisk bool) error {
 	const maxPatternLen = 128
 
-	// Build a regex that matches any unsupported characters
+	if !utf8.ValidString(pattern) {
+		return fmt.Errorf("invalid pattern %q: it is not valid UTF-8", pattern)
+	}
+
+	// Build a regex that matches any unsupported characters in an unquoted identifier
 	disallowedChars := objectNameUnsupportedCharacters
 	if !allowAsterisk {
 		disallowedChars += "*"
 	}
-	disallowedCharsRegex := regexp.QuoteMeta(disallowedChars)
-
-	// Validates [.namespace].schema.table format and disallows any special characters
 	// Ref: https://docs.vertica.com/24.1.x/en/sql-reference/language-elements/identifiers/
-	qualifiedObjectNameRegex := fmt.Sprintf(`^(\.[^%s]+\.)?([^%s]+\.)?[^%s]+$`,
-		disallowedCharsRegex, disallowedCharsRegex, disallowedCharsRegex)
-	r := regexp.MustCompile(qualifiedObjectNameRegex)
+	r := regexp.MustCompile(fmt.Sprintf(`^[^%s]+$`, regexp.QuoteMeta(disallowedChars)))
 
-	objects := strings.Split(pattern, ",")
+	objects, err := splitOutsideQuotes(pattern, ',')
+	if err != nil {
+		return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
 	for _, obj := range objects {
 		// start with v_ is invalid
-		if strings.HasPrefix(obj, "v_") {
+		if strings.HasPrefix(obj, "v_") || strings.HasPrefix(obj, `"v_`) {
 			return fmt.Errorf("invalid character in pattern %s: %s", pattern, obj)
 		}
 		// len > 128 is invalid
-		if len([]rune(obj)) > maxPatternLen {
+		if utf8.RuneCountInString(obj) > maxPatternLen {
 			return fmt.Errorf("pattern is too long %s: %s", pattern, obj)
 		}
-		match := r.MatchString(obj)
-		if !match {
+		if !isValidQualifiedObjectName(obj, r) {
 			return fmt.Errorf("invalid pattern %s: %s", pattern, obj)
 		}
 	}
 	return nil
 }
 
+// SplitQualifiedObjectName splits a single object name of the format
+// [.namespace.][schema.]table into its identifiers, the namespace without its
+// leading period. It returns an error for a list of names or a wildcard.
+func SplitQualifiedObjectName(name string) ([]string, error) {
+	err := ValidateQualifiedObjectNamePattern(name, false)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := splitOutsideQuotes(name, ',')
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) > 1 {
+		return nil, fmt.Errorf("invalid object name %s: expected a single object", name)
+	}
+	return splitOutsideQuotes(strings.TrimPrefix(name, "."), '.')
+}
+
+// isValidQualifiedObjectName checks that obj has the format [.namespace.][schema.]table,
+// where the unquoted identifiers match unquotedRegex
+func isValidQualifiedObjectName(obj string, unquotedRegex *regexp.Regexp) bool {
+	const maxParts = 3
+	hasNamespace := strings.HasPrefix(obj, ".")
+	parts, err := splitOutsideQuotes(strings.TrimPrefix(obj, "."), '.')
+	if err != nil || len(parts) > maxParts || (hasNamespace != (len(parts) == maxParts)) {
+		return false
+	}
+	for _, part := range parts {
+		if isQuotedIdentifier(part) {
+			continue
+		}
+		if !unquotedRegex.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// isQuotedIdentifier checks whether s is a non-empty identifier in double quotes
+// whose embedded double quotes are escaped as ""
+func isQuotedIdentifier(s string) bool {
+	const minQuotedLen = 3
+	if len(s) < minQuotedLen || !strings.HasPrefix(s, `"`) || !strings.HasSuffix(s, `"`) {
+		return false
+	}
+	return !strings.Contains(strings.ReplaceAll(s[1:len(s)-1], `""`, ""), `"`)
+}
+
+// splitOutsideQuotes splits s around sep, ignoring any sep that is in a double-quoted identifier
+func splitOutsideQuotes(s string, sep rune) ([]string, error) {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+	for _, c := range s {
+		switch {
+		case c == '"':
+			// an escaped "" toggles twice and stays in the quoted identifier
+			inQuotes = !inQuotes
+		case c == sep && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quoted identifier in %s", s)
+	}
+	return append(parts, current.String()), nil
+}
+
+// EscapeIdentifier double-quotes an identifier so that it can be used in a qualified
+// object name pattern as is, even if it has unicode characters, dots, commas, or wildcards
+func EscapeIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// NormalizeObjectNamePattern converts a qualified object name pattern to unicode NFC form,
+// so that a name typed with combining characters matches the same object in the catalog
+func NormalizeObjectNamePattern(pattern string) string {
+	return norm.NFC.String(pattern)
+}
+
 func ValidateDBName(dbName string) error {
 	return ValidateName(dbName, "database", false)
 }
@@ -585,6 +747,14 @@ func ValidateArchiveName(archive string) error {
 	return ValidateName(archive, "archive", true)
 }
 
+func ValidateUserName(userName string) error {
+	return ValidateName(userName, "user", false)
+}
+
+func ValidateRoleName(role string) error {
+	return ValidateName(role, "role", false)
+}
+
 // suppress help message for hidden options
 func SetParserUsage(parser *flag.FlagSet, op string) {
 	fmt.Printf("Usage of %s:\n", op)
@@ -682,6 +852,18 @@ func ValidateCommunalStorageLocation(location string) error {
 	return nil
 }
 
+// GetS3BucketName returns the bucket of an S3 communal storage location, and
+// whether the location is on S3
+func GetS3BucketName(location string) (string, bool) {
+	// the host of an S3 url is its bucket
+	bucketAndPath, isS3 := strings.CutPrefix(location, "s3://")
+	if !isS3 {
+		return "", false
+	}
+	bucket, _, _ := strings.Cut(bucketAndPath, "/")
+	return bucket, true
+}
+
 // GetPathPrefix returns a path prefix for a (catalog/data/depot) path of a node
 func GetPathPrefix(path string) string {
 	if path == "" {
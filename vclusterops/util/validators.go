@@ -0,0 +1,231 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MinPort = 1
+	MaxPort = 65535
+)
+
+// ValidatePort checks that a port number is in the valid TCP port range
+func ValidatePort(port int, portName string) error {
+	if port < MinPort || port > MaxPort {
+		return fmt.Errorf("%s %d is invalid, it must be between %d and %d", portName, port, MinPort, MaxPort)
+	}
+	return nil
+}
+
+// The reasons of an UnsafePathError
+const (
+	PathNotAbsolute         = "not absolute"
+	PathHasTraversal        = "traversal"
+	PathHasControlChars     = "control characters"
+	PathOutsideAllowedRoots = "outside allowed roots"
+)
+
+// UnsafePathError is returned when a catalog, data, or depot path breaks the
+// path-safety rules that the NMA applies to the paths it is asked to create
+// or remove
+type UnsafePathError struct {
+	PathName     string
+	Path         string
+	Reason       string
+	AllowedRoots []string
+}
+
+func (e *UnsafePathError) Error() string {
+	switch e.Reason {
+	case PathNotAbsolute:
+		return fmt.Sprintf("must specify an absolute %s", e.PathName)
+	case PathHasTraversal:
+		return fmt.Sprintf("%s %s is invalid, it must not contain '..'", e.PathName, e.Path)
+	case PathHasControlChars:
+		return fmt.Sprintf("%s %q is invalid, it must not contain control characters", e.PathName, e.Path)
+	default:
+		return fmt.Sprintf("%s %s is invalid, it must be under one of %s", e.PathName, e.Path,
+			strings.Join(e.AllowedRoots, ", "))
+	}
+}
+
+// ValidateSafePath checks that a path is absolute, has no ".." segment to escape
+// from its parent directories, and has no control characters that could break
+// the commands or files it is written to
+func ValidateSafePath(path, pathName string) error {
+	return ValidateRemotePath(path, pathName, nil)
+}
+
+// ValidateRemotePath applies the same rules as the NMA to a path on the hosts,
+// so callers can reject a path locally before any request is sent. On top of
+// the rules of ValidateSafePath, the path must be one of allowedRoots or under
+// one of them if any root is given. The error is an *UnsafePathError.
+func ValidateRemotePath(path, pathName string, allowedRoots []string) error {
+	if path == "" || !filepath.IsAbs(path) {
+		return &UnsafePathError{PathName: pathName, Path: path, Reason: PathNotAbsolute}
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(path), "/") {
+		if segment == ".." {
+			return &UnsafePathError{PathName: pathName, Path: path, Reason: PathHasTraversal}
+		}
+	}
+	for _, c := range path {
+		if c < ' ' || c == 0x7f {
+			return &UnsafePathError{PathName: pathName, Path: path, Reason: PathHasControlChars}
+		}
+	}
+	if len(allowedRoots) == 0 {
+		return nil
+	}
+	cleanPath := filepath.Clean(path)
+	for _, root := range allowedRoots {
+		cleanRoot := filepath.Clean(root)
+		if cleanPath == cleanRoot || strings.HasPrefix(cleanPath, strings.TrimSuffix(cleanRoot, "/")+"/") {
+			return nil
+		}
+	}
+	return &UnsafePathError{PathName: pathName, Path: path, Reason: PathOutsideAllowedRoots, AllowedRoots: allowedRoots}
+}
+
+var sizeUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"K":  1 << 10,
+	"KB": 1 << 10,
+	"M":  1 << 20,
+	"MB": 1 << 20,
+	"G":  1 << 30,
+	"GB": 1 << 30,
+	"T":  1 << 40,
+	"TB": 1 << 40,
+}
+
+var sizeRegex = regexp.MustCompile(`^(\d+)\s*([A-Za-z]*)$`)
+
+// ParseSizeString parses a size like "10GB", "512M", or "1024" into bytes.
+// Units are case-insensitive and powers of 1024.
+func ParseSizeString(size string) (int64, error) {
+	matches := sizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("size %q is invalid, it must be a whole number with an optional unit of B, K[B], M[B], G[B], or T[B]", size)
+	}
+	unit, ok := sizeUnits[strings.ToUpper(matches[2])]
+	if !ok {
+		return 0, fmt.Errorf("size %q has an invalid unit %q, the units are B, K[B], M[B], G[B], and T[B]", size, matches[2])
+	}
+	value, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil || value > (1<<63-1)/unit {
+		return 0, fmt.Errorf("size %q is too large", size)
+	}
+	return value * unit, nil
+}
+
+// ParsePositiveDuration parses a duration string like "90s" or "1h30m", which
+// must be greater than 0
+func ParsePositiveDuration(duration, durationName string) (time.Duration, error) {
+	parsedDuration, err := time.ParseDuration(strings.TrimSpace(duration))
+	if err != nil {
+		return 0, fmt.Errorf("%s %q is invalid, it must be a duration like 90s or 1h30m", durationName, duration)
+	}
+	if parsedDuration <= 0 {
+		return 0, fmt.Errorf("%s %q is invalid, it must be greater than 0", durationName, duration)
+	}
+	return parsedDuration, nil
+}
+
+// arn:partition:service:region:account-id:resource
+var awsARNRegex = regexp.MustCompile(`^arn:aws[a-z-]*:[a-z0-9-]+:[a-z0-9-]*:(\d{12})?:.+$`)
+
+// ValidateAWSARN checks that a string is an Amazon Resource Name
+func ValidateAWSARN(arn string) error {
+	if !awsARNRegex.MatchString(arn) {
+		return fmt.Errorf("%q is not a valid AWS ARN of the format arn:partition:service:region:account-id:resource", arn)
+	}
+	return nil
+}
+
+const (
+	minS3BucketNameLen = 3
+	maxS3BucketNameLen = 63
+)
+
+var s3BucketNameCharsRegex = regexp.MustCompile(`^[a-z0-9.-]*$`)
+
+// ValidateS3BucketName checks a bucket name against the naming rules of new
+// AWS S3 buckets. Legacy buckets and S3-compatible storage can have names that
+// break these rules, so callers should not reject existing buckets with it.
+func ValidateS3BucketName(bucket string) error {
+	if len(bucket) < minS3BucketNameLen || len(bucket) > maxS3BucketNameLen {
+		return fmt.Errorf("bucket name %q is invalid, it must be %d to %d characters",
+			bucket, minS3BucketNameLen, maxS3BucketNameLen)
+	}
+	if strings.ToLower(bucket) != bucket {
+		return fmt.Errorf("bucket name %q is invalid, it must not contain uppercase letters", bucket)
+	}
+	if !s3BucketNameCharsRegex.MatchString(bucket) {
+		return fmt.Errorf("bucket name %q is invalid, it must only contain lowercase letters, digits, dots, and hyphens", bucket)
+	}
+	if strings.Trim(bucket, ".-") != bucket {
+		return fmt.Errorf("bucket name %q is invalid, it must begin and end with a letter or digit", bucket)
+	}
+	if strings.Contains(bucket, "..") {
+		return fmt.Errorf("bucket name %q is invalid, it must not contain two adjacent periods", bucket)
+	}
+	if net.ParseIP(bucket) != nil {
+		return fmt.Errorf("bucket name %q is invalid, it must not be formatted as an IP address", bucket)
+	}
+	return nil
+}
+
+const (
+	maxK8sDNSLabelLen     = 63
+	maxK8sDNSSubdomainLen = 253
+)
+
+var k8sDNSLabelRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidateK8sDNSLabel checks that a name is an RFC 1123 DNS label, as required
+// for the names of Kubernetes objects like namespaces and services
+func ValidateK8sDNSLabel(name, objName string) error {
+	if len(name) > maxK8sDNSLabelLen || !k8sDNSLabelRegex.MatchString(name) {
+		return fmt.Errorf("%s name %q is invalid, it must be at most %d characters of lowercase letters, digits, "+
+			"and '-', and begin and end with a letter or digit", objName, name, maxK8sDNSLabelLen)
+	}
+	return nil
+}
+
+// ValidateK8sDNSSubdomain checks that a name is an RFC 1123 DNS subdomain, as
+// required for the names of Kubernetes objects like secrets and config maps
+func ValidateK8sDNSSubdomain(name, objName string) error {
+	if name == "" || len(name) > maxK8sDNSSubdomainLen {
+		return fmt.Errorf("%s name %q is invalid, it must be 1 to %d characters", objName, name, maxK8sDNSSubdomainLen)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if err := ValidateK8sDNSLabel(label, objName); err != nil {
+			return fmt.Errorf("%s name %q is invalid, each of its '.' separated parts must be lowercase letters, "+
+				"digits, and '-', and begin and end with a letter or digit", objName, name)
+		}
+	}
+	return nil
+}
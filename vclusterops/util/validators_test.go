@@ -0,0 +1,124 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePort(t *testing.T) {
+	assert.NoError(t, ValidatePort(5554, "NMA port"))
+	assert.NoError(t, ValidatePort(MaxPort, "NMA port"))
+	assert.ErrorContains(t, ValidatePort(0, "NMA port"), "NMA port 0 is invalid")
+	assert.ErrorContains(t, ValidatePort(65536, "NMA port"), "NMA port 65536 is invalid")
+}
+
+func TestValidateSafePath(t *testing.T) {
+	assert.NoError(t, ValidateSafePath("/data/vertica", "data path"))
+	assert.NoError(t, ValidateSafePath("/data/..vertica", "data path"))
+	assert.ErrorContains(t, ValidateSafePath("", "data path"), "must specify an absolute data path")
+	assert.ErrorContains(t, ValidateSafePath("data", "data path"), "must specify an absolute data path")
+	assert.ErrorContains(t, ValidateSafePath("/data/../etc", "data path"), "must not contain '..'")
+	assert.ErrorContains(t, ValidateSafePath("/data/\nvertica", "data path"), "must not contain control characters")
+}
+
+func TestValidateRemotePath(t *testing.T) {
+	roots := []string{"/data", "/vertica/"}
+	assert.NoError(t, ValidateRemotePath("/data", "catalog path", roots))
+	assert.NoError(t, ValidateRemotePath("/vertica/db/catalog", "catalog path", roots))
+	assert.NoError(t, ValidateRemotePath("/home/dbadmin", "catalog path", nil))
+
+	err := ValidateRemotePath("/database/catalog", "catalog path", roots)
+	var pathErr *UnsafePathError
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, PathOutsideAllowedRoots, pathErr.Reason)
+	assert.ErrorContains(t, err, "must be under one of /data, /vertica/")
+
+	err = ValidateRemotePath("/data/../etc", "catalog path", roots)
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, PathHasTraversal, pathErr.Reason)
+
+	err = ValidateRemotePath("data", "catalog path", roots)
+	assert.ErrorAs(t, err, &pathErr)
+	assert.Equal(t, PathNotAbsolute, pathErr.Reason)
+}
+
+func TestParseSizeString(t *testing.T) {
+	sizes := map[string]int64{
+		"1024":  1024,
+		"1024B": 1024,
+		"10K":   10 << 10,
+		"10kb":  10 << 10,
+		"512M":  512 << 20,
+		"10GB":  10 << 30,
+		"2 TB":  2 << 40,
+	}
+	for size, expected := range sizes {
+		value, err := ParseSizeString(size)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, size)
+	}
+
+	_, err := ParseSizeString("-10GB")
+	assert.ErrorContains(t, err, "must be a whole number")
+	_, err = ParseSizeString("10PB")
+	assert.ErrorContains(t, err, "invalid unit \"PB\"")
+	_, err = ParseSizeString("99999999999999999999K")
+	assert.ErrorContains(t, err, "is too large")
+}
+
+func TestParsePositiveDuration(t *testing.T) {
+	duration, err := ParsePositiveDuration("1h30m", "timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, duration)
+
+	_, err = ParsePositiveDuration("30", "timeout")
+	assert.ErrorContains(t, err, "it must be a duration")
+	_, err = ParsePositiveDuration("0s", "timeout")
+	assert.ErrorContains(t, err, "it must be greater than 0")
+}
+
+func TestValidateAWSARN(t *testing.T) {
+	assert.NoError(t, ValidateAWSARN("arn:aws:iam::123456789012:role/vertica"))
+	assert.NoError(t, ValidateAWSARN("arn:aws-us-gov:s3:::my-bucket"))
+	assert.Error(t, ValidateAWSARN("aws:iam::123456789012:role/vertica"))
+	assert.Error(t, ValidateAWSARN("arn:aws:iam::1234:role/vertica"))
+}
+
+func TestValidateS3BucketName(t *testing.T) {
+	assert.NoError(t, ValidateS3BucketName("vertica-communal.backup"))
+	assert.ErrorContains(t, ValidateS3BucketName("ab"), "must be 3 to 63 characters")
+	assert.ErrorContains(t, ValidateS3BucketName("Vertica"), "must not contain uppercase letters")
+	assert.ErrorContains(t, ValidateS3BucketName("vertica_backup"), "must only contain lowercase letters")
+	assert.ErrorContains(t, ValidateS3BucketName("vertica-"), "must begin and end with a letter or digit")
+	assert.ErrorContains(t, ValidateS3BucketName("vertica..backup"), "two adjacent periods")
+	assert.ErrorContains(t, ValidateS3BucketName("192.168.1.101"), "formatted as an IP address")
+}
+
+func TestValidateK8sDNSNames(t *testing.T) {
+	assert.NoError(t, ValidateK8sDNSLabel("vertica-ns", "namespace"))
+	assert.ErrorContains(t, ValidateK8sDNSLabel("vertica.ns", "namespace"), "namespace name \"vertica.ns\" is invalid")
+	assert.Error(t, ValidateK8sDNSLabel("-vertica", "namespace"))
+	assert.Error(t, ValidateK8sDNSLabel(strings.Repeat("a", 64), "namespace"))
+
+	assert.NoError(t, ValidateK8sDNSSubdomain("vertica-tls.secret", "secret"))
+	assert.Error(t, ValidateK8sDNSSubdomain("", "secret"))
+	assert.ErrorContains(t, ValidateK8sDNSSubdomain("vertica_tls", "secret"), "'.' separated parts")
+}
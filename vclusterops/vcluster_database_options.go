@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 	"github.com/vertica/vcluster/vclusterops/vlog"
@@ -36,12 +37,123 @@ type DatabaseOptions struct {
 	Hosts []string
 	// whether using IPv6 for host addresses
 	IPv6 bool
+	// per-host overrides of the NMA and HTTPS ports, keyed by host address,
+	// hosts that are not in the map use the default ports. A host:port entry
+	// in RawHosts sets the HTTPS port of that host.
+	HostPorts map[string]HostPorts
+	// URL of the SOCKS5 proxy to reach the hosts through, in the form of
+	// socks5://[user:password@]host:port, e.g., a jump host in front of a DR cluster
+	SOCKS5Proxy string
+	// per-host control addresses, keyed by host address, for clusters with a
+	// dedicated private network between the nodes. The nodes talk to each other
+	// through their control addresses, and the NMA and HTTPS service are reached
+	// through the host addresses. Hosts that are not in the map use their host
+	// address for both.
+	ControlAddresses map[string]string
+	// per-host internal addresses, keyed by external address, for hosts behind
+	// NAT. The catalog reports the internal addresses, which are often not
+	// reachable from the machine running vcluster. Requests to an internal
+	// address are sent to its external address, and the node addresses returned
+	// by the catalog are replaced with their external addresses.
+	AddressMapping map[string]string
+	// optional, the aliases of the hosts, which the topology commands keep up to date
+	HostAliases *HostAliasMap
+	// optional, the correlation ID of the command, which is generated if empty.
+	// It is sent in the X-Request-ID header of every request, and is included in
+	// the log lines and the returned errors of the command.
+	RequestID string
+	// optional, the User-Agent header of every request, DefaultUserAgent if empty
+	UserAgent string
+	// optional, a directory where the log of the command is also written, to a
+	// file named after the command and its request ID, e.g., for post-mortem
+	// analysis of a single command on a busy host
+	CommandLogDir string
+	// optional, collects the prepare, execute and finalize time of every op of
+	// the command, and the time of the requests to each host
+	Timings *CommandTimings
+	// optional, collects how many times the requests of the command to each
+	// host were retried, why, and how long the retries took
+	Retries *RetryReport
+	// by default, the polling timeouts left at their default values grow with the
+	// number of nodes, and the request timeouts of the polling ops grow with the
+	// latency of the earlier requests of the command. Set this to use the
+	// default timeouts as is.
+	DisableAdaptiveTimeouts bool
+	// whether to refuse to run the ops that change the cluster. The command
+	// fails with a ReadOnlyModeError that holds the ops it would have run.
+	ReadOnly bool
+	// when set, the command refuses with a PlanDivergedError to run the ops
+	// that change the cluster if they or the state of the cluster differ from
+	// the plan. See VPlan and VExecutePlan.
+	ApprovedPlan *ExecutionPlan
+	// whether the user only has monitoring privileges. Only the monitoring
+	// commands can run, and they do not change the cluster, as in read-only
+	// mode. The ops that the user is denied access to are skipped with a
+	// warning, so the command returns what the user may see instead of failing.
+	MonitoringOnly bool
+	// when set, the commands that change the cluster only run in its
+	// maintenance windows, unless IgnoreMaintenanceWindow is set
+	MaintenancePolicy       *MaintenancePolicy
+	IgnoreMaintenanceWindow bool
+	// when set, the commands that change a running cluster first check its
+	// health, and are refused with an UnhealthyClusterError if it is below the
+	// threshold of the gate, unless IgnoreHealthGate is set
+	HealthGate       *HealthGate
+	IgnoreHealthGate bool
+	// when set, the ops can only call the endpoints that the policy allows.
+	// An op that would call another endpoint fails with an
+	// EndpointNotAllowedError before any of its requests is sent.
+	EndpointPolicy *EndpointPolicy
+	// optional, the requests, downloaded bytes and time that the command may
+	// use. Once one of them is used up, the next op fails with a
+	// CommandBudgetExceededError, and scrutinize returns what it collected.
+	Budget *CommandBudget
+	// optional, the size in bytes above which the large per-host responses,
+	// e.g., the scrutinize staging metadata and the catalog, are spilled to
+	// temporary files in SpillDir and processed as streams, to bound the peak
+	// memory of the process. The responses are kept in memory if 0.
+	SpillThreshold int64
+	// the directory of the spilled responses, the default directory for
+	// temporary files if empty
+	SpillDir string
+	// optional, the cached responses of the read-only commands, e.g., fetching
+	// the node states, which are revalidated with the server on each request
+	ResultCache *ResultCache
+	// optional, collects the connection failures, TLS errors and latency of
+	// the requests of the command to each host
+	ConnectionMetrics *ConnectionMetrics
+	// optional, what the command is running, e.g., for a UI to show its live
+	// activity while the command runs in another goroutine
+	Activity *CommandActivity
+	// optional, restarts the NMA service on the hosts where it does not respond
+	// to the health check of the command, before the command fails
+	NMARecovery *NMARecovery
+	// optional, the addresses of the hosts that the command sends its control
+	// requests through, in order of preference, e.g., when only designated
+	// admin nodes may be used. The command fails if none of them is eligible.
+	Initiators []string
+	// optional, an expression that selects the hosts of scrutinize and
+	// get_hardware_inventory among the nodes of the running database, e.g.,
+	// "subcluster == 'etl' && state == 'UP'". Hosts are then only used to
+	// reach the database. The other commands reject it. See HostSelector for
+	// the syntax.
+	HostSelector string
+	// whether the topology commands take the topology lock in communal storage,
+	// so that administrators on other machines cannot change the topology of
+	// the database at the same time. The lock is renewed while the command
+	// runs, and expires after TopologyLockTTL, DefaultTopologyLockTTL if not
+	// positive, in case the holder dies.
+	UseTopologyLock bool
+	TopologyLockTTL time.Duration
 	// path of catalog directory
 	CatalogPrefix string
 	// path of data directory
 	DataPrefix string
 	// File path to YAML config file
 	ConfigPath string
+	// the directories that the catalog, data, and depot paths must be under,
+	// as the NMA of the hosts is configured. Any directory if empty.
+	AllowedPathRoots []string
 
 	/* part 2: Eon database info */
 
@@ -66,12 +178,20 @@ type DatabaseOptions struct {
 	Cert string
 	// TLS CA Certificate
 	CaCert string
+	// Loads the TLS key and certificates from files before each op, so that
+	// rotated certificates are used. It takes precedence over Key, Cert, and CaCert.
+	CertReloader *CertReloader
 	// Whether to validate NMA server cert signature chain
 	DoVerifyNMAServerCert bool
 	// Whether to validate HTTPS server cert signature chain
 	DoVerifyHTTPSServerCert bool
 	// Whether to validate server cert hostname if signature validation is enabled
 	DoVerifyPeerCertHostname bool
+	// optional, how the NMA and HTTPS clients verify the server certificates,
+	// one of the TLSVerify values. They take precedence over the switches
+	// above, which still apply to the clients whose mode is not set.
+	NMATLSVerifyMode   string
+	HTTPSTLSVerifyMode string
 
 	/* part 4: other info */
 
@@ -79,6 +199,28 @@ type DatabaseOptions struct {
 	LogPath string
 	// whether use password
 	usePassword bool
+	// deprecated options that the command was called with
+	deprecatedOptions []DeprecatedOption
+	// the name of the command, which names its log file in CommandLogDir
+	commandName string
+	// whether ApprovedPlan was checked against the first op that changes the
+	// cluster. It is only checked once per command, as the plan holds the
+	// ops of the first op engine that changes the cluster.
+	planChecked bool
+	// whether the maintenance policy admitted the first op that changes the
+	// cluster, after which the command is not interrupted when the window
+	// closes, including in its later op engines
+	maintenanceAdmitted bool
+	// the correlation ID generated for a run of the command when RequestID
+	// is empty, and the options it was generated for
+	generatedRequestID string
+	requestIDOwner     *DatabaseOptions
+}
+
+// HostPorts is the NMA and HTTPS ports of a host. A port of 0 means the default port.
+type HostPorts struct {
+	NMAPort   int
+	HTTPSPort int
 }
 
 const (
@@ -106,21 +248,68 @@ func (opt *DatabaseOptions) validateBaseOptions(cmdType CmdType, log vlog.Printe
 	// get vcluster commands
 	commandName := cmdType.CmdString()
 	log.WithName(commandName)
+	opt.commandName = commandName
+	// every run of the options gets its own correlation ID, without changing
+	// RequestID of the caller, while the sub-commands that copy the options
+	// share the correlation ID of the command
+	if opt.RequestID == "" && (opt.generatedRequestID == "" || opt.requestIDOwner == opt) {
+		opt.generatedRequestID = generateRequestID()
+		opt.requestIDOwner = opt
+	}
+
+	err := opt.validateMonitoringMode(cmdType)
+	if err != nil {
+		return err
+	}
+
 	// database name
 	if opt.DBName == "" {
 		return fmt.Errorf("must specify a database name")
 	}
-	err := util.ValidateDBName(opt.DBName)
+	err = util.ValidateDBName(opt.DBName)
 	if err != nil {
 		return err
 	}
 
+	// commands with deprecated options of their own migrate those themselves
+	opt.migrateDeprecatedOptions(opt, log)
+
 	// raw hosts and password
 	err = opt.validateHostsAndPwd(commandName, log)
 	if err != nil {
 		return err
 	}
 
+	err = opt.validateProxy()
+	if err != nil {
+		return err
+	}
+
+	err = opt.validateControlAddresses()
+	if err != nil {
+		return err
+	}
+
+	err = opt.validateAddressMapping()
+	if err != nil {
+		return err
+	}
+
+	err = opt.validateInitiators()
+	if err != nil {
+		return err
+	}
+
+	err = opt.validateTLSVerifyModes()
+	if err != nil {
+		return err
+	}
+
+	err = opt.validateHostSelector(cmdType)
+	if err != nil {
+		return err
+	}
+
 	// paths
 	err = opt.validatePaths(commandName)
 	if err != nil {
@@ -151,11 +340,16 @@ func (opt *DatabaseOptions) validateBaseOptions(cmdType CmdType, log vlog.Printe
 // authentication information, either password or key and certs for TLS authentication;
 // key and certs may either be explicitly provided in the options or implicitly
 // loaded from the default locations in local file system
-func (opt *DatabaseOptions) validateAuthOptions(_ string, _ vlog.Printer) error {
+func (opt *DatabaseOptions) validateAuthOptions(_ string, log vlog.Printer) error {
 	// need to provide a password or key and certs
 	if opt.Password == nil && (opt.Cert == "" || opt.Key == "") {
 		// validate key and cert files in local file system
 		_, err := getCertFilePaths()
+		if err != nil && opt.MonitoringOnly {
+			// the endpoints that need authentication are skipped
+			log.PrintInfo("no password or key-certificate pair specified, using the endpoints that do not need them")
+			return nil
+		}
 		if err != nil {
 			// in case that the key or cert files do not exist
 			return fmt.Errorf("must provide either a password or a key-certificate pair")
@@ -164,6 +358,25 @@ func (opt *DatabaseOptions) validateAuthOptions(_ string, _ vlog.Printer) error
 	return nil
 }
 
+// validateMonitoringMode checks that the command can run in monitoring mode
+func (opt *DatabaseOptions) validateHostSelector(cmdType CmdType) error {
+	if opt.HostSelector == "" {
+		return nil
+	}
+	if !hostSelectorCmds[cmdType] {
+		return fmt.Errorf("%s does not support a host selector", cmdType.CmdString())
+	}
+	_, err := ParseHostSelector(opt.HostSelector)
+	return err
+}
+
+func (opt *DatabaseOptions) validateMonitoringMode(cmdType CmdType) error {
+	if opt.MonitoringOnly && !monitoringCmds[cmdType] {
+		return fmt.Errorf("%s is not supported in monitoring mode", cmdType.CmdString())
+	}
+	return nil
+}
+
 // validateHostsAndPwd will validate raw hosts and password
 func (opt *DatabaseOptions) validateHostsAndPwd(commandName string, log vlog.Printer) error {
 	// hosts
@@ -171,6 +384,11 @@ func (opt *DatabaseOptions) validateHostsAndPwd(commandName string, log vlog.Pri
 		return fmt.Errorf("must specify a host or host list")
 	}
 
+	err := opt.parseRawHostPorts()
+	if err != nil {
+		return err
+	}
+
 	// when we create db, we need to set password to "" if user did not provide one
 	if opt.Password == nil {
 		if commandName == CreateDBCmd.CmdString() {
@@ -181,6 +399,82 @@ func (opt *DatabaseOptions) validateHostsAndPwd(commandName string, log vlog.Pri
 	return nil
 }
 
+func (opt *DatabaseOptions) validateControlAddresses() error {
+	for host, controlAddress := range opt.ControlAddresses {
+		if err := util.AddressCheck(host, opt.IPv6); err != nil {
+			return fmt.Errorf("invalid host of control address %s: %w", controlAddress, err)
+		}
+		if err := util.AddressCheck(controlAddress, opt.IPv6); err != nil {
+			return fmt.Errorf("invalid control address of host %s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+func (opt *DatabaseOptions) validateAddressMapping() error {
+	internalAddresses := make(map[string]string, len(opt.AddressMapping))
+	for externalAddress, internalAddress := range opt.AddressMapping {
+		if err := util.AddressCheck(externalAddress, opt.IPv6); err != nil {
+			return fmt.Errorf("invalid external address of internal address %s: %w", internalAddress, err)
+		}
+		if err := util.AddressCheck(internalAddress, opt.IPv6); err != nil {
+			return fmt.Errorf("invalid internal address of external address %s: %w", externalAddress, err)
+		}
+		if otherAddress, ok := internalAddresses[internalAddress]; ok {
+			return fmt.Errorf("internal address %s is mapped from both %s and %s",
+				internalAddress, otherAddress, externalAddress)
+		}
+		internalAddresses[internalAddress] = externalAddress
+	}
+	return nil
+}
+
+func (opt *DatabaseOptions) validateProxy() error {
+	if opt.SOCKS5Proxy == "" {
+		return nil
+	}
+	return util.ValidateSOCKS5ProxyURL(opt.SOCKS5Proxy)
+}
+
+// parseRawHostPorts removes the ports from the host:port entries in RawHosts,
+// and records them as the HTTPS ports of the hosts
+func (opt *DatabaseOptions) parseRawHostPorts() (err error) {
+	if len(opt.RawHosts) == 0 {
+		return nil
+	}
+	opt.RawHosts, err = opt.splitHostPorts(opt.RawHosts)
+	return err
+}
+
+// splitHostPorts returns the hosts of a host list without their ports. The ports
+// are recorded in HostPorts as HTTPS ports, keyed by the resolved host address.
+func (opt *DatabaseOptions) splitHostPorts(rawHosts []string) ([]string, error) {
+	hosts := make([]string, 0, len(rawHosts))
+	for _, rawHost := range rawHosts {
+		host, port, err := util.SplitHostPort(rawHost)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+		if port == 0 {
+			continue
+		}
+
+		address, err := util.ResolveToOneIP(host, opt.IPv6)
+		if err != nil {
+			return nil, err
+		}
+		address = util.CanonicalizeAddress(address)
+		if opt.HostPorts == nil {
+			opt.HostPorts = make(map[string]HostPorts)
+		}
+		ports := opt.HostPorts[address]
+		ports.HTTPSPort = port
+		opt.HostPorts[address] = ports
+	}
+	return hosts, nil
+}
+
 // validate catalog, data, and depot paths
 func (opt *DatabaseOptions) validatePaths(commandName string) error {
 	// validate for the following commands only
@@ -198,7 +492,7 @@ func (opt *DatabaseOptions) validatePaths(commandName string) error {
 	// data prefix
 	// `manage_config recover` does not need the data-path
 	if commandName != ConfigRecoverCmd.CmdString() {
-		err = util.ValidateRequiredAbsPath(opt.DataPrefix, "data path")
+		err = util.ValidateRemotePath(opt.DataPrefix, "data path", opt.AllowedPathRoots)
 		if err != nil {
 			return err
 		}
@@ -206,7 +500,7 @@ func (opt *DatabaseOptions) validatePaths(commandName string) error {
 
 	// depot prefix
 	if opt.IsEon {
-		err = util.ValidateRequiredAbsPath(opt.DepotPrefix, "depot path")
+		err = util.ValidateRemotePath(opt.DepotPrefix, "depot path", opt.AllowedPathRoots)
 		if err != nil {
 			return err
 		}
@@ -216,7 +510,7 @@ func (opt *DatabaseOptions) validatePaths(commandName string) error {
 
 func (opt *DatabaseOptions) validateCatalogPath() error {
 	// catalog prefix path
-	return util.ValidateRequiredAbsPath(opt.CatalogPrefix, "catalog path")
+	return util.ValidateRemotePath(opt.CatalogPrefix, "catalog path", opt.AllowedPathRoots)
 }
 
 // validate config directory
@@ -386,6 +680,13 @@ func (opt *DatabaseOptions) getCurrConfigFilePath(sandbox string) string {
 	return descriptionFilePath
 }
 
+// getCommunalMetadataPath returns the metadata directory of a database in
+// communal storage, e.g., s3://tfminio/test_loc/metadata/test_db
+func getCommunalMetadataPath(communalStorageLocation, dbName string) string {
+	metadataPath := filepath.Join(communalStorageLocation, descriptionFileMetadataFolder, dbName)
+	return strings.Replace(metadataPath, ":/", "://", 1)
+}
+
 // getRestorePointConfigFilePath can make the restore point description file path using db name, archive name, restore point id,
 // and communal storage location in the options
 func (options *VReviveDatabaseOptions) getRestorePointConfigFilePath(validatedRestorePointID string) string {
@@ -429,19 +730,176 @@ func (opt *DatabaseOptions) runClusterOpEngine(log vlog.Printer, instructions []
 // the presence of a CA cert, as we want to support providing a cert
 // even when vclusterops isn't validating the peer cert.
 func (opt *DatabaseOptions) hasCerts() bool {
+	if opt.CertReloader != nil {
+		return true
+	}
 	return opt.Key != "" && opt.Cert != ""
 }
 
 func (opt *DatabaseOptions) getCerts() *httpsCerts {
+	if opt.CertReloader != nil {
+		return opt.CertReloader.getCerts()
+	}
 	return &httpsCerts{key: opt.Key, cert: opt.Cert, caCert: opt.CaCert}
 }
 
 func (opt *DatabaseOptions) getTLSModes() *tlsModes {
 	return &tlsModes{
-		doVerifyNMAServerCert:    opt.DoVerifyNMAServerCert,
-		doVerifyHTTPSServerCert:  opt.DoVerifyHTTPSServerCert,
-		doVerifyPeerCertHostname: opt.DoVerifyPeerCertHostname,
+		nmaVerifyMode:   opt.getNMATLSVerifyMode(),
+		httpsVerifyMode: opt.getHTTPSTLSVerifyMode(),
 	}
 }
 
 /* End opTLSOptions interface */
+
+/* Begin opNetworkOptions interface */
+
+func (opt *DatabaseOptions) getHostPorts() map[string]HostPorts {
+	return opt.HostPorts
+}
+
+func (opt *DatabaseOptions) getProxyURL() string {
+	return opt.SOCKS5Proxy
+}
+
+// updateHostAliases updates the host aliases, if any, from the nodes of a database
+func (opt *DatabaseOptions) updateHostAliases(vdb *VCoordinationDatabase) {
+	if opt.HostAliases != nil {
+		opt.HostAliases.updateFromVDB(vdb)
+	}
+}
+
+func (opt *DatabaseOptions) getCommandLogDir() string {
+	return opt.CommandLogDir
+}
+
+func (opt *DatabaseOptions) getCommandName() string {
+	return opt.commandName
+}
+
+func (opt *DatabaseOptions) getRequestID() string {
+	if opt.RequestID != "" {
+		return opt.RequestID
+	}
+	return opt.generatedRequestID
+}
+
+func (opt *DatabaseOptions) getUserAgent() string {
+	if opt.UserAgent == "" {
+		return DefaultUserAgent
+	}
+	return opt.UserAgent
+}
+
+func (opt *DatabaseOptions) getControlAddresses() map[string]string {
+	return opt.ControlAddresses
+}
+
+func (opt *DatabaseOptions) getAddressMapping() map[string]string {
+	return opt.AddressMapping
+}
+
+/* End opNetworkOptions interface */
+
+func (opt *DatabaseOptions) getTimings() *CommandTimings {
+	return opt.Timings
+}
+
+func (opt *DatabaseOptions) getRetryReport() *RetryReport {
+	return opt.Retries
+}
+
+func (opt *DatabaseOptions) getApprovedPlan() *ExecutionPlan {
+	if opt.planChecked {
+		return nil
+	}
+	return opt.ApprovedPlan
+}
+
+func (opt *DatabaseOptions) setPlanChecked() {
+	opt.planChecked = true
+}
+
+func (opt *DatabaseOptions) isReadOnly() bool {
+	return opt.ReadOnly || opt.MonitoringOnly
+}
+
+func (opt *DatabaseOptions) isMonitoringOnly() bool {
+	return opt.MonitoringOnly
+}
+
+func (opt *DatabaseOptions) getBudget() *CommandBudget {
+	return opt.Budget
+}
+
+func (opt *DatabaseOptions) getSpillThreshold() int64 {
+	return opt.SpillThreshold
+}
+
+func (opt *DatabaseOptions) getSpillDir() string {
+	return opt.SpillDir
+}
+
+func (opt *DatabaseOptions) getConnectionMetrics() *ConnectionMetrics {
+	return opt.ConnectionMetrics
+}
+
+func (opt *DatabaseOptions) getActivity() *CommandActivity {
+	return opt.Activity
+}
+
+func (opt *DatabaseOptions) getNMARecovery() *NMARecovery {
+	return opt.NMARecovery
+}
+
+func (opt *DatabaseOptions) getResultCache() *ResultCache {
+	return opt.ResultCache
+}
+
+func (opt *DatabaseOptions) getEndpointPolicy() *EndpointPolicy {
+	return opt.EndpointPolicy
+}
+
+func (opt *DatabaseOptions) getHealthGate() *HealthGate {
+	if opt.IgnoreHealthGate {
+		return nil
+	}
+	return opt.HealthGate
+}
+
+func (opt *DatabaseOptions) makeHealthCheckOp(health *ClusterHealth, isRunning *bool) (clusterOp, error) {
+	op, err := makeHTTPSGetClusterHealthOp(opt.Hosts, opt.usePassword, opt.UserName, opt.Password,
+		health, isRunning)
+	return &op, err
+}
+
+func (opt *DatabaseOptions) getMaintenancePolicy() *MaintenancePolicy {
+	if opt.IgnoreMaintenanceWindow || opt.maintenanceAdmitted {
+		return nil
+	}
+	return opt.MaintenancePolicy
+}
+
+func (opt *DatabaseOptions) setMaintenanceAdmitted() {
+	opt.maintenanceAdmitted = true
+}
+
+// copyForSideOps returns a copy of the options for the ops that run beside
+// the ops of the command, e.g., to renew the topology lock or to probe hosts.
+// The copy can be used at the same time as the options, and has none of the
+// plan, gates, and accounting of the command.
+func (opt *DatabaseOptions) copyForSideOps() DatabaseOptions {
+	sideOptions := opt.copyForConcurrentUse()
+	sideOptions.ConfigurationParameters = util.CopyMap(opt.ConfigurationParameters)
+	sideOptions.ApprovedPlan = nil
+	sideOptions.HealthGate = nil
+	sideOptions.MaintenancePolicy = nil
+	sideOptions.Timings = nil
+	sideOptions.Retries = nil
+	sideOptions.Budget = nil
+	sideOptions.ConnectionMetrics = nil
+	sideOptions.Activity = nil
+	sideOptions.NMARecovery = nil
+	sideOptions.ResultCache = nil
+	return sideOptions
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 func TestGetDescriptionFilePath(t *testing.T) {
@@ -66,3 +67,148 @@ func TestGetDescriptionFilePath(t *testing.T) {
 	path = opt.getCurrConfigFilePath(util.MainClusterSandbox)
 	assert.Equal(t, targetGCPPath, path)
 }
+
+func TestParseRawHostPorts(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.RawHosts = []string{"192.168.1.101:18443", "192.168.1.102"}
+
+	err := opt.parseRawHostPorts()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, opt.RawHosts)
+	assert.Equal(t, map[string]HostPorts{"192.168.1.101": {HTTPSPort: 18443}}, opt.HostPorts)
+
+	// the port is applied to HTTPS requests of the host only
+	op := opBase{}
+	op.setupBasicInfo()
+	nmaRequest := hostHTTPRequest{}
+	nmaRequest.buildNMAEndpoint("health")
+	httpsRequest := hostHTTPRequest{}
+	httpsRequest.buildHTTPSEndpoint("nodes")
+	op.clusterHTTPRequest.RequestCollection["192.168.1.101"] = httpsRequest
+	op.clusterHTTPRequest.RequestCollection["192.168.1.102"] = httpsRequest
+	op.applyNetworkOptions(&opt)
+	assert.Equal(t, 18443, op.clusterHTTPRequest.RequestCollection["192.168.1.101"].Port)
+	assert.Equal(t, 0, op.clusterHTTPRequest.RequestCollection["192.168.1.102"].Port)
+	op.clusterHTTPRequest.RequestCollection["192.168.1.101"] = nmaRequest
+	op.applyNetworkOptions(&opt)
+	assert.Equal(t, 0, op.clusterHTTPRequest.RequestCollection["192.168.1.101"].Port)
+
+	// negative: invalid port
+	opt.RawHosts = []string{"192.168.1.101:0"}
+	err = opt.parseRawHostPorts()
+	assert.ErrorContains(t, err, "invalid port")
+}
+
+type deprecationTestOptions struct {
+	DatabaseOptions
+	Timeout     int
+	OldTimeout  int      `deprecated:"Timeout"`
+	OldMode     string   `deprecated:""`
+	OldHostList []string `deprecated:"RawHosts"`
+}
+
+func TestMigrateDeprecatedOptions(t *testing.T) {
+	opt := deprecationTestOptions{DatabaseOptions: DatabaseOptionsFactory()}
+	opt.OldTimeout = 30
+	opt.OldMode = "legacy"
+	opt.OldHostList = []string{"192.168.1.101"}
+	opt.RawHosts = []string{"192.168.1.102"}
+
+	opt.migrateDeprecatedOptions(&opt, vlog.Printer{})
+	// the value is moved to the replacement
+	assert.Equal(t, 30, opt.Timeout)
+	assert.Equal(t, 0, opt.OldTimeout)
+	// the replacement is kept when both are set
+	assert.Equal(t, []string{"192.168.1.102"}, opt.RawHosts)
+
+	report := opt.GetDeprecationReport()
+	assert.Len(t, report, 3)
+	assert.Equal(t, DeprecatedOption{Option: "OldTimeout", Replacement: "Timeout", Migrated: true,
+		Message: "option OldTimeout is deprecated, its value is moved to option Timeout"}, report[0])
+	assert.Equal(t, "option OldMode is deprecated and ignored", report[1].Message)
+	assert.False(t, report[2].Migrated)
+
+	// checking the options again does not duplicate the report
+	opt.migrateDeprecatedOptions(&opt, vlog.Printer{})
+	assert.Len(t, opt.GetDeprecationReport(), 3)
+}
+
+func TestApplyEnvDefaults(t *testing.T) {
+	t.Setenv(EnvDBName, "env_db")
+	t.Setenv(EnvHosts, "192.168.1.101, 192.168.1.102,")
+	t.Setenv(EnvUserName, "env_user")
+	t.Setenv(EnvIPv6, "false")
+	t.Setenv(EnvKeyPath, "/certs/tls.key")
+	t.Setenv(EnvCertPath, "/certs/tls.crt")
+
+	// unset options are populated from the environment
+	opt := DatabaseOptionsFactory()
+	assert.NoError(t, opt.ApplyEnvDefaults())
+	assert.Equal(t, "env_db", opt.DBName)
+	assert.Equal(t, []string{"192.168.1.101", "192.168.1.102"}, opt.RawHosts)
+	assert.Equal(t, "env_user", opt.UserName)
+	assert.False(t, opt.IPv6)
+	assert.Equal(t, MakeCertReloader("/certs/tls.key", "/certs/tls.crt", ""), opt.CertReloader)
+
+	// options that are set take precedence
+	opt = DatabaseOptionsFactory()
+	opt.DBName = "test_db"
+	opt.RawHosts = []string{"192.168.1.201"}
+	opt.IPv6 = true
+	opt.Key = "key"
+	opt.Cert = "cert"
+	assert.NoError(t, opt.ApplyEnvDefaults())
+	assert.Equal(t, "test_db", opt.DBName)
+	assert.Equal(t, []string{"192.168.1.201"}, opt.RawHosts)
+	assert.True(t, opt.IPv6)
+	assert.Nil(t, opt.CertReloader)
+
+	// negative: invalid bool, or a key path without a cert path
+	opt = DatabaseOptionsFactory()
+	t.Setenv(EnvIPv6, "maybe")
+	assert.ErrorContains(t, opt.ApplyEnvDefaults(), "it must be true or false")
+	t.Setenv(EnvIPv6, "")
+	t.Setenv(EnvCertPath, "")
+	assert.ErrorContains(t, opt.ApplyEnvDefaults(), "must be set together")
+}
+
+func TestValidateControlAddresses(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.ControlAddresses = map[string]string{"192.168.1.101": "10.0.0.101"}
+	assert.NoError(t, opt.validateControlAddresses())
+
+	opt.ControlAddresses["192.168.1.102"] = "bad address"
+	assert.ErrorContains(t, opt.validateControlAddresses(), "invalid control address of host 192.168.1.102")
+
+	// the control addresses must match the IP version of the hosts
+	opt.ControlAddresses = map[string]string{"192.168.1.101": "fd00::101"}
+	assert.Error(t, opt.validateControlAddresses())
+}
+
+func TestValidateAddressMapping(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.AddressMapping = map[string]string{"203.0.113.1": "10.0.0.101"}
+	assert.NoError(t, opt.validateAddressMapping())
+
+	opt.AddressMapping["203.0.113.2"] = "bad address"
+	assert.ErrorContains(t, opt.validateAddressMapping(), "invalid internal address of external address 203.0.113.2")
+
+	// an internal address cannot be mapped from two external addresses
+	opt.AddressMapping["203.0.113.2"] = "10.0.0.101"
+	assert.ErrorContains(t, opt.validateAddressMapping(), "internal address 10.0.0.101 is mapped from both")
+}
+
+func TestValidateMonitoringMode(t *testing.T) {
+	opt := DatabaseOptionsFactory()
+	opt.MonitoringOnly = true
+	assert.NoError(t, opt.validateMonitoringMode(FetchNodesDetailsCmd))
+	assert.EqualError(t, opt.validateMonitoringMode(StopDBCmd), "stop_db is not supported in monitoring mode")
+
+	// a monitoring user may have no credentials
+	assert.NoError(t, opt.validateAuthOptions(FetchNodesDetailsCmd.CmdString(), vlog.Printer{}))
+	if _, err := getCertFilePaths(); err != nil {
+		opt.MonitoringOnly = false
+		assert.ErrorContains(t, opt.validateAuthOptions(StopDBCmd.CmdString(), vlog.Printer{}),
+			"must provide either a password or a key-certificate pair")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 const (
@@ -47,6 +49,8 @@ type Printer struct {
 	ForCli bool
 
 	Writer io.Writer
+	// optional, collects the warnings printed through the Printer
+	Warnings *WarningCollector
 }
 
 // WithName will construct a new printer with the logger set with an additional
@@ -57,6 +61,20 @@ func (p *Printer) WithName(logName string) Printer {
 		LogToFileOnly: p.LogToFileOnly,
 		ForCli:        p.ForCli,
 		Writer:        p.Writer,
+		Warnings:      p.Warnings,
+	}
+}
+
+// WithValues will construct a new printer with the logger set with additional
+// key/value pairs, which are included in every log line. The new printer
+// inherits state from the current Printer.
+func (p *Printer) WithValues(keysAndValues ...any) Printer {
+	return Printer{
+		Log:           p.Log.WithValues(keysAndValues...),
+		LogToFileOnly: p.LogToFileOnly,
+		ForCli:        p.ForCli,
+		Writer:        p.Writer,
+		Warnings:      p.Warnings,
 	}
 }
 
@@ -104,6 +122,7 @@ func (p *Printer) PrintWarning(msg string, v ...any) {
 	fmsg := fmt.Sprintf(msg, v...)
 	escapedFmsg := escapeSpecialCharacters(fmsg)
 	p.Log.Info(escapedFmsg)
+	p.collectWarning(fmsg)
 	p.printlnCond(WarningLog, fmsg)
 }
 
@@ -134,9 +153,17 @@ func (p *Printer) DisplayWarning(msg string, v ...any) {
 	fmsg = firstLetterToUpper(fmsg)
 	escapedFmsg := escapeSpecialCharacters(fmsg)
 	p.Log.Info(escapedFmsg)
+	p.collectWarning(fmsg)
 	p.println(WarningLog, fmsg)
 }
 
+// collectWarning records a warning message if the Printer collects warnings
+func (p *Printer) collectWarning(msg string) {
+	if p.Warnings != nil {
+		p.Warnings.add(msg)
+	}
+}
+
 // escapeSpecialCharacters will escape special characters (tabs or newlines) in the message.
 // Messages that are typically meant for the console could have tabs and newlines for alignment.
 // We want to escape those when writing the message to the log so that each log entry is exactly one line long.
@@ -240,8 +267,13 @@ func logMaskedArgParseHelper(inputArgv []string) (maskedPairs []string) {
 }
 
 // setupOrDie will setup the logging for vcluster CLI. On exit, p.Log will
-// be set.
+// be set. The log file is rotated with the DefaultRotationPolicy.
 func (p *Printer) SetupOrDie(logFile string) {
+	p.SetupWithRotationOrDie(logFile, DefaultRotationPolicy())
+}
+
+// SetupWithRotationOrDie is SetupOrDie with the given rotation of the log file
+func (p *Printer) SetupWithRotationOrDie(logFile string, policy RotationPolicy) {
 	// The vcluster library uses logr as the logging API. We use Uber's zap
 	// package to implement the logging API.
 	EncoderConfigWithoutCaller := zap.NewDevelopmentEncoderConfig()
@@ -261,12 +293,22 @@ func (p *Printer) SetupOrDie(logFile string) {
 		OutputPaths:      []string{"stderr"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
+	var opts []zap.Option
 	// If no log file is given, we just log to standard output
 	if logFile != "" {
 		p.LogToFileOnly = true
-		cfg.OutputPaths = []string{logFile}
+		file, err := OpenRotatingFile(logFile, policy)
+		if err != nil {
+			fmt.Printf("Failed to open the log file: %s", err.Error())
+			os.Exit(1)
+		}
+		// the entries are written to the rotating file instead of the output paths
+		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			fileCore := zapcore.NewCore(zapcore.NewConsoleEncoder(cfg.EncoderConfig), file, cfg.Level)
+			return zapcore.NewSamplerWithOptions(fileCore, time.Second, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+		}))
 	}
-	zapLg, err := cfg.Build()
+	zapLg, err := cfg.Build(opts...)
 	if err != nil {
 		fmt.Printf("Failed to setup the logger: %s", err.Error())
 		os.Exit(1)
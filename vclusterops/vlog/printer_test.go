@@ -52,3 +52,26 @@ func TestPasswordRedaction(t *testing.T) {
 	assert.Len(t, unmaskedArgs, 2)
 	assert.Equal(t, pw, unmaskedArgs[1])
 }
+
+func TestCollectWarnings(t *testing.T) {
+	p := Printer{LogToFileOnly: true, Warnings: &WarningCollector{}}
+	p.PrintInfo("not a warning")
+	p.PrintWarning("host %s is skipped", "192.168.1.101")
+
+	// printers derived from the Printer share the collector
+	opPrinter := p.WithName("op")
+	opPrinter.DisplayWarning("option %s is deprecated", "--foo")
+
+	assert.Equal(t, []Warning{
+		{Message: "host 192.168.1.101 is skipped"},
+		{Message: "Option --foo is deprecated"},
+	}, p.Warnings.Warnings())
+
+	p.Warnings.Reset()
+	assert.Empty(t, p.Warnings.Warnings())
+
+	// warnings are not collected without a collector
+	p = Printer{LogToFileOnly: true}
+	p.PrintWarning("host %s is skipped", "192.168.1.101")
+	assert.Nil(t, p.Warnings)
+}
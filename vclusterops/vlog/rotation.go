@@ -0,0 +1,242 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+const (
+	// the default size at which the log file is rotated
+	DefaultLogMaxSize = 100 * 1024 * 1024
+	// the default number of rotated log files that are kept
+	DefaultLogMaxBackups = 5
+
+	logFilePerm      = 0o644
+	compressedSuffix = ".gz"
+)
+
+// RotationPolicy is the size-based rotation and retention of a log file
+type RotationPolicy struct {
+	// the size in bytes at which the log file is rotated, no rotation if <= 0
+	MaxSize int64
+	// the number of rotated files that are kept, the oldest ones are removed
+	// first. All rotated files are kept if <= 0.
+	MaxBackups int
+	// whether to gzip the rotated files
+	Compress bool
+}
+
+// DefaultRotationPolicy returns the rotation of the log file of vcluster CLI
+func DefaultRotationPolicy() RotationPolicy {
+	return RotationPolicy{
+		MaxSize:    DefaultLogMaxSize,
+		MaxBackups: DefaultLogMaxBackups,
+		Compress:   true,
+	}
+}
+
+// RotatingFile is a log file that is rotated once it reaches the size of its
+// policy. The rotated files are named after the log file with a suffix of
+// their age, e.g., vcluster.log.1.gz is the newest rotated file. It is safe
+// for concurrent use, so embedders can plug it into their own logger.
+type RotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	policy RotationPolicy
+	file   *os.File
+	size   int64
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it if needed
+func OpenRotatingFile(path string, policy RotationPolicy) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, policy: policy}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p to the log file, rotating the file first if p would make
+// it exceed the maximum size. A write larger than the maximum size goes to a
+// file of its own. If the rotation fails, p is still appended to the log file
+// and the rotation error is returned.
+func (r *RotatingFile) Write(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, fs.ErrClosed
+	}
+	var rotateErr error
+	if r.policy.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.policy.MaxSize {
+		if rotateErr = r.rotate(); rotateErr != nil {
+			rotateErr = fmt.Errorf("fail to rotate log file %s: %w", r.path, rotateErr)
+			if r.file == nil {
+				return 0, rotateErr
+			}
+		}
+	}
+	n, err = r.file.Write(p)
+	r.size += int64(n)
+	return n, errors.Join(rotateErr, err)
+}
+
+// Sync flushes the log file to disk
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return fs.ErrClosed
+	}
+	return r.file.Sync()
+}
+
+// Close closes the log file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// rotate shifts the rotated files by one, moves the log file to the newest
+// rotated file, and starts a new log file. The rotated files beyond the
+// retention are removed. The log file is reopened even if the rotation fails,
+// so that logging goes on in the log file that could not be rotated.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	err := r.shiftFiles()
+	if openErr := r.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+// shiftFiles moves the log file and the rotated files to their next names
+func (r *RotatingFile) shiftFiles() error {
+	suffix := ""
+	if r.policy.Compress {
+		suffix = compressedSuffix
+	}
+	oldest := r.policy.MaxBackups
+	if oldest <= 0 {
+		oldest = r.countBackups() + 1
+	} else if err := removeIfExists(r.backupPath(oldest, suffix)); err != nil {
+		return err
+	}
+	for i := oldest - 1; i >= 1; i-- {
+		err := os.Rename(r.backupPath(i, suffix), r.backupPath(i+1, suffix))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	newest := r.backupPath(1, "")
+	if err := os.Rename(r.path, newest); err != nil {
+		return err
+	}
+	if r.policy.Compress {
+		// the rotated file is kept uncompressed if it cannot be compressed,
+		// which must not stop the logging
+		_ = compressFile(newest, newest+compressedSuffix)
+	}
+	return nil
+}
+
+func (r *RotatingFile) backupPath(i int, suffix string) string {
+	return fmt.Sprintf("%s.%d%s", r.path, i, suffix)
+}
+
+// countBackups returns the number of consecutive rotated files, which are all
+// kept without a retention
+func (r *RotatingFile) countBackups() int {
+	suffix := ""
+	if r.policy.Compress {
+		suffix = compressedSuffix
+	}
+	count := 0
+	for {
+		if _, err := os.Stat(r.backupPath(count+1, suffix)); err != nil {
+			return count
+		}
+		count++
+	}
+}
+
+// compressFile gzips src into dst and removes src
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, logFilePerm)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+func removeIfExists(path string) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readGzipFile(t *testing.T, path string) string {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestRotatingFile(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vcluster.log")
+	r, err := OpenRotatingFile(logFile, RotationPolicy{MaxSize: 10, MaxBackups: 2, Compress: true})
+	assert.NoError(t, err)
+
+	// every line fills the log file, so each write after the first rotates it
+	for _, line := range []string{"line1....\n", "line2....\n", "line3....\n", "line4....\n"} {
+		_, err = r.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, r.Close())
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "line4....\n", string(content))
+	assert.Equal(t, "line3....\n", readGzipFile(t, logFile+".1.gz"))
+	assert.Equal(t, "line2....\n", readGzipFile(t, logFile+".2.gz"))
+	// the oldest rotated file is beyond the retention
+	assert.NoFileExists(t, logFile+".3.gz")
+	assert.NoFileExists(t, logFile+".1")
+
+	// the size of an existing log file counts toward the rotation
+	r, err = OpenRotatingFile(logFile, RotationPolicy{MaxSize: 15})
+	assert.NoError(t, err)
+	_, err = r.Write([]byte("line5....\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+	content, err = os.ReadFile(logFile + ".1")
+	assert.NoError(t, err)
+	assert.Equal(t, "line4....\n", string(content))
+
+	// no rotation without a maximum size
+	r, err = OpenRotatingFile(logFile, RotationPolicy{})
+	assert.NoError(t, err)
+	_, err = r.Write([]byte(strings.Repeat("x", 100)))
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+	assert.NoFileExists(t, logFile+".2")
+
+	// negative: write after close
+	_, err = r.Write([]byte("line6....\n"))
+	assert.Error(t, err)
+}
+
+func TestRotatingFileRotationFailure(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vcluster.log")
+	// the oldest rotated file cannot be removed, so the rotation fails
+	const dirPerm = 0o755
+	assert.NoError(t, os.MkdirAll(filepath.Join(logFile+".1", "keep"), dirPerm))
+	r, err := OpenRotatingFile(logFile, RotationPolicy{MaxSize: 10, MaxBackups: 1})
+	assert.NoError(t, err)
+
+	_, err = r.Write([]byte("line1....\n"))
+	assert.NoError(t, err)
+	// the log file goes on after the failed rotation
+	n, err := r.Write([]byte("line2....\n"))
+	assert.ErrorContains(t, err, "fail to rotate log file")
+	assert.Equal(t, len("line2....\n"), n)
+	_, err = r.Write([]byte("line3....\n"))
+	assert.ErrorContains(t, err, "fail to rotate log file")
+	assert.NoError(t, r.Close())
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "line1....\nline2....\nline3....\n", string(content))
+}
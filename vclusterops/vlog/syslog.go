@@ -0,0 +1,147 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"io"
+	"log/syslog"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultSyslogTag is the tag of the entries that vcluster sends to syslog
+const DefaultSyslogTag = "vcluster"
+
+// SyslogConfig is where the log entries are sent to syslog. journald receives
+// the entries of the local syslog daemon through its /dev/log socket.
+type SyslogConfig struct {
+	// the network and address of a remote syslog daemon, e.g., "udp" and
+	// "logs.example.com:514". The local syslog daemon is used if empty.
+	Network string
+	Address string
+	// the tag of the entries, DefaultSyslogTag if empty
+	Tag string
+	// the facility of the entries, LOG_USER if 0
+	Facility syslog.Priority
+}
+
+// syslogWriter is the subset of *syslog.Writer that sends an entry with a
+// priority, so tests can capture the entries
+type syslogWriter interface {
+	Debug(m string) error
+	Info(m string) error
+	Err(m string) error
+	Close() error
+}
+
+// WithSyslog constructs a new printer that sends its log entries to syslog in
+// addition to the logger of the current Printer, e.g., for appliances that
+// collect the activity of vcluster in the native logging system of the host.
+// The priority of each entry follows its level: errors are sent as LOG_ERR,
+// info entries as LOG_INFO, and the verbose entries of logr as LOG_DEBUG.
+// The returned closer closes the connection to syslog once the new printer is
+// no longer used.
+func (p *Printer) WithSyslog(config SyslogConfig) (Printer, io.Closer, error) {
+	tag := config.Tag
+	if tag == "" {
+		tag = DefaultSyslogTag
+	}
+	facility := config.Facility
+	if facility == 0 {
+		facility = syslog.LOG_USER
+	}
+	writer, err := syslog.Dial(config.Network, config.Address, facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		return Printer{}, nil, err
+	}
+	return p.withSyslogWriter(writer), writer, nil
+}
+
+func (p *Printer) withSyslogWriter(writer syslogWriter) Printer {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	// syslog stamps the entries itself
+	encoderConfig.TimeKey = ""
+	encoderConfig.LevelKey = ""
+	encoderConfig.EncodeCaller = nil
+	core := &syslogCore{
+		LevelEnabler: zap.DebugLevel,
+		encoder:      zapcore.NewConsoleEncoder(encoderConfig),
+		writer:       writer,
+	}
+	syslogLog := zapr.NewLogger(zap.New(core))
+
+	sinks := []logr.LogSink{syslogLog.GetSink()}
+	if sink := p.Log.GetSink(); sink != nil {
+		sinks = append(sinks, sink)
+	}
+	return Printer{
+		Log:           logr.New(teeSink(sinks)),
+		LogToFileOnly: p.LogToFileOnly,
+		ForCli:        p.ForCli,
+		Writer:        p.Writer,
+		Warnings:      p.Warnings,
+	}
+}
+
+// syslogCore is a zap core that sends each entry to syslog with the priority
+// of its level
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  syslogWriter
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for i := range fields {
+		fields[i].AddTo(encoder)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, encoder: encoder, writer: c.writer}
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	switch {
+	case entry.Level >= zapcore.ErrorLevel:
+		return c.writer.Err(msg)
+	case entry.Level == zapcore.InfoLevel:
+		return c.writer.Info(msg)
+	default:
+		// the verbose entries of logr, e.g., V(1).Info
+		return c.writer.Debug(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type syslogEntry struct {
+	priority string
+	msg      string
+}
+
+type fakeSyslogWriter struct {
+	entries []syslogEntry
+}
+
+func (w *fakeSyslogWriter) add(priority, msg string) error {
+	w.entries = append(w.entries, syslogEntry{priority: priority, msg: msg})
+	return nil
+}
+
+func (w *fakeSyslogWriter) Debug(m string) error { return w.add("debug", m) }
+func (w *fakeSyslogWriter) Info(m string) error  { return w.add("info", m) }
+func (w *fakeSyslogWriter) Err(m string) error   { return w.add("err", m) }
+func (w *fakeSyslogWriter) Close() error         { return nil }
+
+func TestWithSyslog(t *testing.T) {
+	writer := &fakeSyslogWriter{}
+	base := Printer{LogToFileOnly: true}
+	p := base.withSyslogWriter(writer)
+	named := p.WithName("start_db")
+	logger := named.WithValues("requestID", "req-1")
+
+	logger.PrintInfo("starting %d nodes", 3)
+	logger.PrintError("node %s failed", "v_db_node0001")
+	logger.Error(errors.New("timeout"), "poll failed")
+	logger.V(1).Info("polling node states")
+
+	assert.Len(t, writer.entries, 4)
+	assert.Equal(t, "info", writer.entries[0].priority)
+	assert.Contains(t, writer.entries[0].msg, "start_db")
+	assert.Contains(t, writer.entries[0].msg, "starting 3 nodes")
+	assert.Contains(t, writer.entries[0].msg, `"requestID": "req-1"`)
+	assert.Equal(t, "err", writer.entries[1].priority)
+	assert.Equal(t, "err", writer.entries[2].priority)
+	assert.Contains(t, writer.entries[2].msg, "timeout")
+	assert.Equal(t, "debug", writer.entries[3].priority)
+}
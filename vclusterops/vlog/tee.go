@@ -0,0 +1,102 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import (
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLogFile constructs a new printer that writes its log entries to the
+// file at path in addition to the logger of the current Printer, e.g., to keep
+// the log of a single command apart. Entries are appended if the file exists.
+// The returned closer closes the file once the new printer is no longer used.
+func (p *Printer) WithLogFile(path string) (Printer, io.Closer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return Printer{}, nil, err
+	}
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeCaller = nil
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), zapcore.Lock(file), zap.InfoLevel)
+	fileLog := zapr.NewLogger(zap.New(core))
+
+	sinks := []logr.LogSink{fileLog.GetSink()}
+	if sink := p.Log.GetSink(); sink != nil {
+		sinks = append(sinks, sink)
+	}
+	return Printer{
+		Log:           logr.New(teeSink(sinks)),
+		LogToFileOnly: p.LogToFileOnly,
+		ForCli:        p.ForCli,
+		Writer:        p.Writer,
+		Warnings:      p.Warnings,
+	}, file, nil
+}
+
+// teeSink passes every log entry to each of its sinks that has the level of
+// the entry enabled
+type teeSink []logr.LogSink
+
+func (t teeSink) Init(info logr.RuntimeInfo) {
+	for _, sink := range t {
+		sink.Init(info)
+	}
+}
+
+func (t teeSink) Enabled(level int) bool {
+	for _, sink := range t {
+		if sink.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeSink) Info(level int, msg string, keysAndValues ...any) {
+	for _, sink := range t {
+		if sink.Enabled(level) {
+			sink.Info(level, msg, keysAndValues...)
+		}
+	}
+}
+
+func (t teeSink) Error(err error, msg string, keysAndValues ...any) {
+	for _, sink := range t {
+		sink.Error(err, msg, keysAndValues...)
+	}
+}
+
+func (t teeSink) WithValues(keysAndValues ...any) logr.LogSink {
+	sinks := make(teeSink, len(t))
+	for i, sink := range t {
+		sinks[i] = sink.WithValues(keysAndValues...)
+	}
+	return sinks
+}
+
+func (t teeSink) WithName(name string) logr.LogSink {
+	sinks := make(teeSink, len(t))
+	for i, sink := range t {
+		sinks[i] = sink.WithName(name)
+	}
+	return sinks
+}
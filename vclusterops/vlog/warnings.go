@@ -0,0 +1,54 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vlog
+
+import "sync"
+
+// Warning is a non-fatal issue hit by a command, e.g., a skipped host or a
+// deprecated option
+type Warning struct {
+	Message string `json:"message"`
+}
+
+// WarningCollector keeps the warnings printed through a Printer, so that
+// programmatic callers can surface them rather than parsing the log. It is
+// safe to use from the goroutines of a command.
+type WarningCollector struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+func (c *WarningCollector) add(msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, Warning{Message: msg})
+}
+
+// Warnings returns a copy of the warnings collected so far
+func (c *WarningCollector) Warnings() []Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	warnings := make([]Warning, len(c.warnings))
+	copy(warnings, c.warnings)
+	return warnings
+}
+
+// Reset drops the warnings collected so far, e.g., before running the next command
+func (c *WarningCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = nil
+}
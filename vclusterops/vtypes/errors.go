@@ -0,0 +1,616 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vtypes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReadOnlyModeError is returned when a command runs in read-only mode and
+// reaches an op that would change the cluster. Plan lists that op and the
+// ones after it, and StateFingerprint is the state of the cluster that the
+// command read up to that op.
+type ReadOnlyModeError struct {
+	Command          string
+	Plan             []PlannedOp
+	StateFingerprint string
+}
+
+// ExecutionPlan returns the unsigned plan of the refused ops, to be signed,
+// approved and executed later
+func (e *ReadOnlyModeError) ExecutionPlan() *ExecutionPlan {
+	return &ExecutionPlan{
+		Version:          ExecutionPlanVersion,
+		Command:          e.Command,
+		CreatedAt:        time.Now().UTC(),
+		StateFingerprint: e.StateFingerprint,
+		Ops:              e.Plan,
+	}
+}
+
+func (e *ReadOnlyModeError) Error() string {
+	return fmt.Sprintf("read-only mode: refused to run %s, which changes the cluster, and the %d op(s) after it",
+		e.Plan[0].Name, len(e.Plan)-1)
+}
+
+// ClusterNotFoundError is returned when a cluster name is not in the registry
+type ClusterNotFoundError struct {
+	Name string
+}
+
+func (e *ClusterNotFoundError) Error() string {
+	return fmt.Sprintf("cluster %s is not in the registry", e.Name)
+}
+
+// HostAliasNotFoundError is returned when an alias is not in the map, or its
+// node is not known yet
+type HostAliasNotFoundError struct {
+	Alias string
+}
+
+func (e *HostAliasNotFoundError) Error() string {
+	return fmt.Sprintf("host alias %s is not mapped to a node", e.Alias)
+}
+
+// HostSelectorSyntaxError is returned when a host selector is not a valid
+// expression. Offset is the byte offset of the error in the selector.
+type HostSelectorSyntaxError struct {
+	Selector string
+	Offset   int
+	Reason   string
+}
+
+func (e *HostSelectorSyntaxError) Error() string {
+	return fmt.Sprintf("invalid host selector %q at offset %d: %s", e.Selector, e.Offset, e.Reason)
+}
+
+// DBNameInUseError is returned when the communal storage location already has
+// a database with the given name
+type DBNameInUseError struct {
+	DBName                  string
+	CommunalStorageLocation string
+}
+
+func (e *DBNameInUseError) Error() string {
+	return fmt.Sprintf("database name %s is already in use on communal storage %s",
+		e.DBName, e.CommunalStorageLocation)
+}
+
+// OutsideMaintenanceWindowError is returned when a command would change the
+// cluster outside of the maintenance windows of its MaintenancePolicy
+type OutsideMaintenanceWindowError struct {
+	OpName string
+	// the next time a window opens, zero if no window opens in the next week
+	NextOpening time.Time
+}
+
+func (e *OutsideMaintenanceWindowError) Error() string {
+	if e.NextOpening.IsZero() {
+		return fmt.Sprintf("refused to run %s outside of the maintenance windows", e.OpName)
+	}
+	return fmt.Sprintf("refused to run %s outside of the maintenance windows, the next window opens at %s",
+		e.OpName, e.NextOpening.Format(time.RFC3339))
+}
+
+// UnhealthyClusterError is returned when a command would change a cluster
+// that is below the threshold of its HealthGate
+type UnhealthyClusterError struct {
+	OpName string
+	Health ClusterHealth
+	// why the cluster is below the threshold
+	Reasons []string
+}
+
+func (e *UnhealthyClusterError) Error() string {
+	return fmt.Sprintf("refused to run %s on an unhealthy cluster: %s", e.OpName, strings.Join(e.Reasons, "; "))
+}
+
+// the SQL states of the server errors that callers commonly react to
+const (
+	SQLStateInsufficientPrivilege = "42501"
+	SQLStateUndefinedObject       = "42704"
+	SQLStateUndefinedTable        = "42V01"
+	SQLStateUndefinedSchema       = "3F000"
+	SQLStateUndefinedFunction     = "42883"
+	SQLStateDuplicateObject       = "42710"
+)
+
+// ServerError is an error that the HTTPS service of a node returned, decoded
+// from the response so that callers can check its SQL state or error code
+// instead of matching the text of the response
+type ServerError struct {
+	Host       string
+	StatusCode int
+	SQLState   string
+	// the Vertica error code
+	ErrorCode int
+	// the node that raised the error
+	Node    string
+	Message string
+	// the RFC 7807 problem of the response, if any
+	Problem error
+}
+
+func (e *ServerError) Error() string {
+	msg := fmt.Sprintf("server error %d (SQLSTATE %s)", e.ErrorCode, e.SQLState)
+	if e.Node != "" {
+		msg += " on node " + e.Node
+	}
+	return fmt.Sprintf("%s, status code %d returned from host %s: %s", msg, e.StatusCode, e.Host, e.Message)
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Problem
+}
+
+// IsObjectNotFound returns whether the error is about an object, e.g., a
+// table or a schema, that does not exist
+func (e *ServerError) IsObjectNotFound() bool {
+	switch e.SQLState {
+	case SQLStateUndefinedObject, SQLStateUndefinedTable, SQLStateUndefinedSchema, SQLStateUndefinedFunction:
+		return true
+	}
+	return false
+}
+
+// IsInsufficientPrivilege returns whether the user lacks a privilege that the
+// request needs
+func (e *ServerError) IsInsufficientPrivilege() bool {
+	return e.SQLState == SQLStateInsufficientPrivilege
+}
+
+// HostErrorGroup is an error that happened on several hosts, with the
+// error of the first of them
+type HostErrorGroup struct {
+	Err   error
+	Hosts []string
+}
+
+// HostErrors is the failure of an op on several hosts. The errors that only
+// differ by their host, e.g., a wrong password on every host, are collapsed
+// into one group, and Errors keeps the full error of each host.
+type HostErrors struct {
+	Groups []HostErrorGroup
+	Errors map[string]error
+}
+
+func (e *HostErrors) Error() string {
+	msgs := make([]string, len(e.Groups))
+	for i, group := range e.Groups {
+		msgs[i] = group.Err.Error()
+		if len(group.Hosts) > 1 {
+			msgs[i] += fmt.Sprintf(" (on %d hosts: %s)", len(group.Hosts), strings.Join(group.Hosts, ", "))
+		}
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the error of each group, for errors.Is and errors.As
+func (e *HostErrors) Unwrap() []error {
+	errs := make([]error, len(e.Groups))
+	for i := range e.Groups {
+		errs[i] = e.Groups[i].Err
+	}
+	return errs
+}
+
+// RequestIDError is returned by a command that fails, with the correlation
+// ID of the command
+type RequestIDError struct {
+	RequestID string
+	Err       error
+}
+
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("%s (request ID: %s)", e.Err.Error(), e.RequestID)
+}
+
+func (e *RequestIDError) Unwrap() error {
+	return e.Err
+}
+
+// ScrutinizeManifestVersion is the version of the manifest format written into
+// scrutinize bundles. It is increased when a change to the format is not
+// backward compatible.
+const ScrutinizeManifestVersion = 1
+
+// UnsupportedManifestVersionError is returned when a manifest is written in a
+// newer format than this parser supports
+type UnsupportedManifestVersionError struct {
+	Version int
+}
+
+func (e *UnsupportedManifestVersionError) Error() string {
+	return fmt.Sprintf("scrutinize manifest version %d is not supported, the latest supported version is %d",
+		e.Version, ScrutinizeManifestVersion)
+}
+
+// CommandConflictError is returned when a command cannot run because other
+// commands that change the same database are running, or are queued before it
+type CommandConflictError struct {
+	DBName      string
+	Command     string
+	Conflicting []string
+}
+
+func (e *CommandConflictError) Error() string {
+	return fmt.Sprintf("cannot run %s on database %s because of %s",
+		e.Command, e.DBName, strings.Join(e.Conflicting, ", "))
+}
+
+// TopologyLockHeldError is returned when a command cannot take the topology
+// lock of a database because a command of another process holds it
+type TopologyLockHeldError struct {
+	DBName string
+	Holder TopologyLock
+}
+
+func (e *TopologyLockHeldError) Error() string {
+	return fmt.Sprintf("the topology of database %s is locked by %s running %s on %s (pid %d, request ID %s)"+
+		" since %s, the lock expires at %s",
+		e.DBName, e.Holder.Owner, e.Holder.Command, e.Holder.Host, e.Holder.PID, e.Holder.RequestID,
+		e.Holder.StartTime.Format(time.RFC3339), e.Holder.ExpiresAt.Format(time.RFC3339))
+}
+
+// the limits of a license that a LicenseLimitExceededError can report
+const (
+	LicenseNodeLimit = "nodes"
+	LicenseCPULimit  = "CPU sockets"
+	LicenseSizeLimit = "bytes of raw data"
+)
+
+// LicenseLimitExceededError is returned by the license pre-check of a command
+// when the database would exceed a limit of its license after the command
+type LicenseLimitExceededError struct {
+	Limit    string
+	Allowed  int64
+	Required int64
+}
+
+func (e *LicenseLimitExceededError) Error() string {
+	return fmt.Sprintf("the license allows %d %s, but the database would have %d", e.Allowed, e.Limit, e.Required)
+}
+
+// PartialReviveNotSupportedError is returned by revive_db when a subset of the
+// namespaces or schemas is requested from a host that can only revive the
+// whole database
+type PartialReviveNotSupportedError struct {
+	Host string
+}
+
+func (e *PartialReviveNotSupportedError) Error() string {
+	return fmt.Sprintf("host %s does not support reviving a subset of the namespaces and schemas,"+
+		" its catalog has all objects of the database", e.Host)
+}
+
+// CreateDBProfileConflictError is returned by create_db when an option is set
+// to a value other than the one of its creation profile
+type CreateDBProfileConflictError struct {
+	Profile      string
+	Option       string
+	ProfileValue string
+	OptionValue  string
+}
+
+func (e *CreateDBProfileConflictError) Error() string {
+	return fmt.Sprintf("option %s is set to %s, but creation profile %s sets it to %s",
+		e.Option, e.OptionValue, e.Profile, e.ProfileValue)
+}
+
+// SmokeTestFailedError is returned by a command whose smoke test failed, the
+// command itself succeeded
+type SmokeTestFailedError struct {
+	Result SmokeTestResult
+}
+
+func (e *SmokeTestFailedError) Error() string {
+	step, _ := e.Result.FirstFailedStep()
+	return fmt.Sprintf("the smoke test failed at step %s on host %s: %s", step.Name, step.Host, step.Error)
+}
+
+// ConfigCanaryFailedError is returned when a validation query failed on a
+// canary host of a configuration parameter change. The parameter is not set
+// on the database, and it is cleared from the canary nodes if RolledBack.
+type ConfigCanaryFailedError struct {
+	ConfigParameter string
+	Results         []ConfigValidationResult
+	RolledBack      bool
+}
+
+func (e *ConfigCanaryFailedError) Error() string {
+	var failed []string
+	for _, result := range e.Results {
+		if !result.Passed {
+			failed = append(failed, fmt.Sprintf("%q on host %s: %s", result.Query, result.Host, result.Error))
+		}
+	}
+	msg := fmt.Sprintf("the canary of configuration parameter %s failed, validation queries failed: %s",
+		e.ConfigParameter, strings.Join(failed, "; "))
+	if !e.RolledBack {
+		msg += ". The parameter could not be cleared from the canary nodes"
+	}
+	return msg
+}
+
+// ReplicationStalledError is returned when an asynchronous replication job
+// made no progress for the stall timeout. The job itself is not canceled.
+type ReplicationStalledError struct {
+	Status     ReplicationStatusResponse
+	StalledFor time.Duration
+	// the target host that the status was polled from
+	Host string
+	// nil until the diagnostics are collected
+	Diagnostics *ReplicationDiagnostics
+}
+
+func (e *ReplicationStalledError) Error() string {
+	msg := fmt.Sprintf("replication with transaction ID %d made no progress for %s in op %s on node %s",
+		e.Status.TransactionID, e.StalledFor.Round(time.Second), e.Status.OpName, e.Status.NodeName)
+	if e.Diagnostics != nil {
+		msg += fmt.Sprintf(", diagnostics were collected from %d hosts", len(e.Diagnostics.Hosts))
+	}
+	return msg
+}
+
+// PlanDivergedError is returned when an approved plan is executed, but the
+// command would now run other ops, or the state of the cluster changed since
+// the plan was computed. None of the ops that change the cluster are run.
+type PlanDivergedError struct {
+	Command string
+	Reason  string
+}
+
+func (e *PlanDivergedError) Error() string {
+	return fmt.Sprintf("refused to execute the approved plan of %s: %s", e.Command, e.Reason)
+}
+
+// EndpointNotAllowedError is returned when an op would call an endpoint that
+// the EndpointPolicy of the command does not allow. No request of the op is sent.
+type EndpointNotAllowedError struct {
+	Host     string
+	Service  string
+	Endpoint string
+}
+
+func (e *EndpointNotAllowedError) Error() string {
+	return fmt.Sprintf("endpoint %s of the %s service on host %s is not allowed by the endpoint policy",
+		e.Endpoint, e.Service, e.Host)
+}
+
+// The resources limited by a CommandBudget
+const (
+	BudgetRequests        = "requests"
+	BudgetBytesDownloaded = "bytes downloaded"
+	BudgetDuration        = "duration"
+)
+
+// CommandBudgetExceededError is returned when a command would use more of a
+// resource than its CommandBudget allows. The limit and the usage of the
+// duration are in nanoseconds.
+type CommandBudgetExceededError struct {
+	Resource string
+	Limit    int64
+	Used     int64
+}
+
+func (e *CommandBudgetExceededError) Error() string {
+	if e.Resource == BudgetDuration {
+		return fmt.Sprintf("the command exceeded its budget of %s, ran for %s", time.Duration(e.Limit), time.Duration(e.Used))
+	}
+	return fmt.Sprintf("the command exceeded its budget of %d %s, used %d", e.Limit, e.Resource, e.Used)
+}
+
+// SandboxRefreshNotSupportedError is returned by refresh_sandbox when an
+// incremental refresh is requested from a database that can only recreate
+// the sandbox
+type SandboxRefreshNotSupportedError struct {
+	Sandbox string
+}
+
+func (e *SandboxRefreshNotSupportedError) Error() string {
+	return fmt.Sprintf("the database does not support refreshing sandbox %s incrementally", e.Sandbox)
+}
+
+// SchemaMigrationVerificationError is returned by migrate_schema when the
+// tables of the migrated schema do not have the same rows in both databases
+type SchemaMigrationVerificationError struct {
+	Schema     string
+	Mismatches []SchemaRowCountMismatch
+}
+
+func (e *SchemaMigrationVerificationError) Error() string {
+	return fmt.Sprintf("the row counts of %d tables of schema %s differ between the source and target databases,"+
+		" the first one is table %s with %d source rows and %d target rows", len(e.Mismatches), e.Schema,
+		e.Mismatches[0].Table, e.Mismatches[0].SourceRows, e.Mismatches[0].TargetRows)
+}
+
+// ReplicationTargetIsSourceError is returned when the target database of a
+// replication resolves to the source database, e.g., when the target hosts are
+// a load balancer in front of the source cluster
+type ReplicationTargetIsSourceError struct {
+	DBName string
+	DBUUID string
+}
+
+func (e *ReplicationTargetIsSourceError) Error() string {
+	if e.DBUUID == "" {
+		return fmt.Sprintf("the target database %s is the source database (same communal storage), "+
+			"replicating a database to itself is not allowed", e.DBName)
+	}
+	return fmt.Sprintf("the target database %s is the source database (UUID %s), "+
+		"replicating a database to itself is not allowed", e.DBName, e.DBUUID)
+}
+
+// DBIsRunningError is an error to indicate we found the database still running.
+// This is emitted from this op. Callers can do type checking to perform an
+// action based on the error.
+type DBIsRunningError struct {
+	Detail string
+}
+
+// Error returns the message details. This is added so that it is compatible
+// with the error interface.
+func (e *DBIsRunningError) Error() string {
+	return e.Detail
+}
+
+// ReIPNoClusterQuorumError is an error to indicate
+// that cluster quorum was lost before a re-ip.
+// This is emitted from this op. Callers can do type checking to perform an
+// action based on the error.
+type ReIPNoClusterQuorumError struct {
+	Detail string
+}
+
+func (e *ReIPNoClusterQuorumError) Error() string {
+	return e.Detail
+}
+
+// ClusterLeaseNotExpiredError is returned when you attempt to access a
+// communal storage location when there is an active cluster lease on it.
+type ClusterLeaseNotExpiredError struct {
+	Expiration string
+}
+
+func (e *ClusterLeaseNotExpiredError) Error() string {
+	return fmt.Sprintf("revive database cannot continue because the communal storage location might still be in use."+
+		" The cluster lease will expire at %s(UTC)."+
+		" Please ensure that the other cluster has stopped and try revive_db after the cluster lease expiration",
+		e.Expiration)
+}
+
+// ReviveDBNodeCountMismatchError is the error that is returned when the number of
+// nodes in the revived cluster does not match the number of nodes in the original cluster.
+type ReviveDBNodeCountMismatchError struct {
+	ReviveDBStep  string
+	FailureHost   string
+	NumOfNewNodes int
+	NumOfOldNodes int
+}
+
+func (e *ReviveDBNodeCountMismatchError) Error() string {
+	return fmt.Sprintf(`[%s] nodes mismatch found on host %s: the number of the new nodes in --hosts is %d,`+
+		` but the number of primary nodes in the description file is %d`,
+		e.ReviveDBStep, e.FailureHost, e.NumOfNewNodes, e.NumOfOldNodes)
+}
+
+// ReviveDBRestorePointNotFoundError is the error that is returned when the retore point specified by the user
+// either via index or id is not found among all restore points in the specified archive. Either InvalidID or
+// InvalidIndex will be set depending on whether the user specified the retore point by index or id.
+type ReviveDBRestorePointNotFoundError struct {
+	Archive      string
+	InvalidID    string
+	InvalidIndex int
+}
+
+func (e *ReviveDBRestorePointNotFoundError) Error() string {
+	var indicator, value string
+	if e.InvalidID != "" {
+		indicator = "ID"
+		value = e.InvalidID
+	} else {
+		indicator = "index"
+		value = fmt.Sprintf("%d", e.InvalidIndex)
+	}
+	return fmt.Sprintf("restore point with %s %s not found in archive %q", indicator, value, e.Archive)
+}
+
+// SubclusterNotSandboxedError is the error that is returned when
+// the subcluster does not need unsandbox operation
+type SubclusterNotSandboxedError struct {
+	SCName string
+}
+
+func (e *SubclusterNotSandboxedError) Error() string {
+	return fmt.Sprintf(`cannot unsandbox a regular subcluster [%s]`, e.SCName)
+}
+
+// ReplicationBlockedError is returned when sessions hold catalog locks or run
+// DDL on the objects to replicate, on which the replication would stall
+type ReplicationBlockedError struct {
+	Sessions []BlockingSession
+	// how long the locks were waited for, in seconds
+	WaitedSeconds int
+}
+
+func (e *ReplicationBlockedError) Error() string {
+	sessions := make([]string, len(e.Sessions))
+	for i := range e.Sessions {
+		sessions[i] = fmt.Sprintf("%s (user %s, %s lock on %s)", e.Sessions[i].SessionID,
+			e.Sessions[i].UserName, e.Sessions[i].LockMode, e.Sessions[i].ObjectName)
+	}
+	msg := "replication is blocked by DDL or catalog locks on the objects to replicate"
+	if e.WaitedSeconds > 0 {
+		msg += fmt.Sprintf(" after waiting %d seconds", e.WaitedSeconds)
+	}
+	return fmt.Sprintf("%s, blocking sessions: %s", msg, strings.Join(sessions, ", "))
+}
+
+// ReplicationNoMatchingObjectsError is returned when the table or schema name
+// or the include pattern of a replication matches no objects of the source
+// database, so the replication would silently copy nothing
+type ReplicationNoMatchingObjectsError struct {
+	TableOrSchemaName string
+	IncludePatterns   []string
+	ExcludePatterns   []string
+}
+
+func (e *ReplicationNoMatchingObjectsError) Error() string {
+	var selectors []string
+	if e.TableOrSchemaName != "" {
+		selectors = append(selectors, fmt.Sprintf("table or schema name %q", e.TableOrSchemaName))
+	}
+	if len(e.IncludePatterns) > 0 {
+		selectors = append(selectors, fmt.Sprintf("include patterns %q", e.IncludePatterns))
+	}
+	if len(e.ExcludePatterns) > 0 {
+		selectors = append(selectors, fmt.Sprintf("exclude patterns %q", e.ExcludePatterns))
+	}
+	return fmt.Sprintf("no objects of the source database match the %s", strings.Join(selectors, " and "))
+}
+
+// PathPermissionError is returned when the OS user running the node
+// management agent cannot write a path that a command is about to create or
+// write on a host, e.g., because the path is owned by another user. It is
+// reported before anything is written, instead of a "permission denied"
+// failure halfway through the command.
+type PathPermissionError struct {
+	Host string
+	// the path to write, and the existing path that blocks the write, which
+	// is the path itself or its nearest existing parent directory
+	Path         string
+	BlockingPath string
+	// the OS user running the node management agent
+	UserName string
+	UID      int
+	GID      int
+	// the owner and the permission bits of the blocking path
+	OwnerUID int
+	OwnerGID int
+	Mode     string
+}
+
+func (e *PathPermissionError) Error() string {
+	msg := fmt.Sprintf("user %s (uid %d, gid %d) cannot write %s on host %s", e.UserName, e.UID, e.GID, e.Path, e.Host)
+	if e.BlockingPath != "" && e.BlockingPath != e.Path {
+		msg += fmt.Sprintf(": parent directory %s", e.BlockingPath)
+	} else {
+		msg += ": the path"
+	}
+	return msg + fmt.Sprintf(" is owned by uid %d, gid %d with mode %s", e.OwnerUID, e.OwnerGID, e.Mode)
+}
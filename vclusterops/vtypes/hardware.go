@@ -0,0 +1,268 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vtypes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HardwareInventory is the hardware of the hosts of a cluster, for capacity
+// planning and for checking that the hosts of a subcluster are alike
+type HardwareInventory struct {
+	Hosts []HostHardware `json:"hosts"`
+	// hardware that is not the same on every host
+	Differences []HardwareDifference `json:"differences"`
+}
+
+// HostHardware is the hardware of a host, as reported by its NMA
+type HostHardware struct {
+	Host        string     `json:"host"`
+	NodeName    string     `json:"node_name,omitempty"`
+	Subcluster  string     `json:"subcluster,omitempty"`
+	OS          OSInfo     `json:"os"`
+	CPU         CPUInfo    `json:"cpu"`
+	MemoryBytes int64      `json:"memory_bytes"`
+	Disks       []DiskInfo `json:"disks"`
+	NICs        []NICInfo  `json:"nics"`
+	NUMANodes   []NUMANode `json:"numa_nodes"`
+	// the clock settings of the host, which must agree across the hosts to
+	// correlate their logs and replication watermarks
+	Time TimeSettings `json:"time"`
+}
+
+type OSInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Kernel  string `json:"kernel"`
+}
+
+type TimeSettings struct {
+	TimeZone string `json:"time_zone"`
+	Locale   string `json:"locale"`
+	// the NTP server or pool that the clock of the host is synchronized with,
+	// empty if the clock is not synchronized
+	NTPSource string `json:"ntp_source"`
+}
+
+type CPUInfo struct {
+	Model   string `json:"model"`
+	Sockets int    `json:"sockets"`
+	Cores   int    `json:"cores"`
+	Threads int    `json:"threads"`
+}
+
+type DiskInfo struct {
+	Device     string `json:"device"`
+	MountPoint string `json:"mount_point"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Rotational bool   `json:"rotational"`
+}
+
+type NICInfo struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	SpeedMbps int    `json:"speed_mbps"`
+}
+
+type NUMANode struct {
+	ID          int   `json:"id"`
+	CPUs        []int `json:"cpus"`
+	MemoryBytes int64 `json:"memory_bytes"`
+}
+
+// HardwareDifference is a hardware property that is not the same on every
+// host, with the value of the property on each host
+type HardwareDifference struct {
+	Property string            `json:"property"`
+	Values   map[string]string `json:"values"`
+}
+
+// hardware properties that are compared across hosts
+const (
+	HardwareOS         = "os"
+	HardwareCPUModel   = "cpu_model"
+	HardwareCPUSockets = "cpu_sockets"
+	HardwareCPUCores   = "cpu_cores"
+	HardwareCPUThreads = "cpu_threads"
+	HardwareMemory     = "memory_gib"
+	HardwareDiskCount  = "disk_count"
+	HardwareDiskSize   = "disk_size_gib"
+	HardwareDiskType   = "disk_type"
+	HardwareNICSpeed   = "nic_speed_mbps"
+	HardwareNUMANodes  = "numa_nodes"
+	HardwareTimeZone   = "time_zone"
+	HardwareLocale     = "locale"
+)
+
+// toGiB rounds a size in bytes to the nearest GiB
+func toGiB(sizeBytes int64) int64 {
+	const bytesPerGiB = 1 << 30
+	return (sizeBytes + bytesPerGiB/2) / bytesPerGiB
+}
+
+// properties returns the hardware properties of a host that are compared
+// across hosts. Sizes are in GiB, so that a few MiB reserved by the kernel do
+// not make hosts differ.
+func (h *HostHardware) properties() map[string]string {
+	var diskSize int64
+	for _, disk := range h.Disks {
+		diskSize += disk.SizeBytes
+	}
+	nicSpeed := 0
+	for _, nic := range h.NICs {
+		nicSpeed = max(nicSpeed, nic.SpeedMbps)
+	}
+	return map[string]string{
+		HardwareOS:         strings.TrimSpace(h.OS.Name + " " + h.OS.Version),
+		HardwareCPUModel:   h.CPU.Model,
+		HardwareCPUSockets: fmt.Sprint(h.CPU.Sockets),
+		HardwareCPUCores:   fmt.Sprint(h.CPU.Cores),
+		HardwareCPUThreads: fmt.Sprint(h.CPU.Threads),
+		HardwareMemory:     fmt.Sprint(toGiB(h.MemoryBytes)),
+		HardwareDiskCount:  fmt.Sprint(len(h.Disks)),
+		HardwareDiskSize:   fmt.Sprint(toGiB(diskSize)),
+		HardwareDiskType:   h.diskType(),
+		HardwareNICSpeed:   fmt.Sprint(nicSpeed),
+		HardwareNUMANodes:  fmt.Sprint(len(h.NUMANodes)),
+		HardwareTimeZone:   h.Time.TimeZone,
+		HardwareLocale:     h.Time.Locale,
+	}
+}
+
+// FindDifferences returns the hardware properties that are not the same on
+// the given hosts, sorted by property
+func FindDifferences(hosts []HostHardware) []HardwareDifference {
+	hostProperties := make(map[string]map[string]string, len(hosts))
+	for i := range hosts {
+		hostProperties[hosts[i].Host] = hosts[i].properties()
+	}
+
+	differences := []HardwareDifference{}
+	if len(hosts) == 0 {
+		return differences
+	}
+	for property := range hostProperties[hosts[0].Host] {
+		values := make(map[string]string, len(hosts))
+		for host, properties := range hostProperties {
+			values[host] = properties[property]
+		}
+		for _, value := range values {
+			if value != values[hosts[0].Host] {
+				differences = append(differences, HardwareDifference{Property: property, Values: values})
+				break
+			}
+		}
+	}
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Property < differences[j].Property
+	})
+	return differences
+}
+
+// the types of the disks of a host
+const (
+	DiskTypeSSD   = "ssd"
+	DiskTypeHDD   = "hdd"
+	DiskTypeMixed = "mixed"
+)
+
+func (h *HostHardware) diskType() string {
+	if len(h.Disks) == 0 {
+		return ""
+	}
+	rotational := 0
+	for _, disk := range h.Disks {
+		if disk.Rotational {
+			rotational++
+		}
+	}
+	switch rotational {
+	case 0:
+		return DiskTypeSSD
+	case len(h.Disks):
+		return DiskTypeHDD
+	default:
+		return DiskTypeMixed
+	}
+}
+
+// HardwareThresholds are how much the hardware of a host can differ from the
+// reference host before a HardwareWarning is raised
+type HardwareThresholds struct {
+	// difference of the memory, in percent of the memory of the reference host
+	MemoryPercent float64
+	// difference of the CPU cores, in percent of the cores of the reference host
+	CorePercent float64
+}
+
+// HardwareWarning tells that the hardware or OS of a host is unlike the one
+// of the reference host, which causes imbalanced subclusters
+type HardwareWarning struct {
+	Host          string `json:"host"`
+	ReferenceHost string `json:"reference_host"`
+	Property      string `json:"property"`
+	Value         string `json:"value"`
+	Reference     string `json:"reference"`
+}
+
+func (w *HardwareWarning) String() string {
+	return fmt.Sprintf("host %s has %s %s, but host %s has %s",
+		w.Host, w.Property, w.Value, w.ReferenceHost, w.Reference)
+}
+
+// CompareHardware returns a warning for every property of the hosts that
+// differs from the reference host beyond the thresholds. The memory and cores
+// are compared with the thresholds, the OS and disk type must be the same.
+func CompareHardware(reference *HostHardware, hosts []HostHardware, thresholds HardwareThresholds) []HardwareWarning {
+	var warnings []HardwareWarning
+	referenceProperties := reference.properties()
+	for i := range hosts {
+		host := &hosts[i]
+		if host.Host == reference.Host {
+			continue
+		}
+		properties := host.properties()
+		exceeded := map[string]bool{
+			HardwareOS:       properties[HardwareOS] != referenceProperties[HardwareOS],
+			HardwareCPUCores: exceedsPercent(int64(host.CPU.Cores), int64(reference.CPU.Cores), thresholds.CorePercent),
+			HardwareMemory:   exceedsPercent(host.MemoryBytes, reference.MemoryBytes, thresholds.MemoryPercent),
+			HardwareDiskType: properties[HardwareDiskType] != referenceProperties[HardwareDiskType],
+		}
+		for _, property := range []string{HardwareOS, HardwareCPUCores, HardwareMemory, HardwareDiskType} {
+			if !exceeded[property] {
+				continue
+			}
+			warnings = append(warnings, HardwareWarning{
+				Host:          host.Host,
+				ReferenceHost: reference.Host,
+				Property:      property,
+				Value:         properties[property],
+				Reference:     referenceProperties[property],
+			})
+		}
+	}
+	return warnings
+}
+
+func exceedsPercent(value, reference int64, percent float64) bool {
+	diff := value - reference
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff)*100 > float64(reference)*percent
+}
@@ -0,0 +1,79 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vtypes
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExecutionPlanVersion is the version of the format of ExecutionPlan
+const ExecutionPlanVersion = 1
+
+// ExecutionPlan is the plan of the ops of a command that change the cluster,
+// computed in read-only mode for the command to be approved before it runs.
+// Signature is an HMAC-SHA256 of every other field, keyed with a secret that
+// only the planner and the executor share, so a plan edited after it was
+// signed is refused. StateFingerprint is the state of the cluster that the
+// plan was computed against.
+type ExecutionPlan struct {
+	Version          int         `json:"version"`
+	Command          string      `json:"command"`
+	CreatedAt        time.Time   `json:"created_at"`
+	StateFingerprint string      `json:"state_fingerprint"`
+	Ops              []PlannedOp `json:"ops"`
+	Signature        string      `json:"signature"`
+}
+
+// Sign sets the signature of the plan with key
+func (p *ExecutionPlan) Sign(key []byte) error {
+	if len(key) == 0 {
+		return errors.New("a key is required to sign the plan")
+	}
+	p.Signature = p.computeSignature(key)
+	return nil
+}
+
+// computeSignature returns the HMAC-SHA256 of the JSON of the plan without
+// its signature
+func (p *ExecutionPlan) computeSignature(key []byte) string {
+	unsigned := *p
+	unsigned.Signature = ""
+	// a plan only holds strings, numbers and booleans, it always marshals
+	data, _ := json.Marshal(&unsigned)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that the plan is of a known version and is signed with key
+func (p *ExecutionPlan) Verify(key []byte) error {
+	if len(key) == 0 {
+		return errors.New("a key is required to verify the plan")
+	}
+	if p.Version != ExecutionPlanVersion {
+		return fmt.Errorf("unsupported version %d of the plan, expected version %d", p.Version, ExecutionPlanVersion)
+	}
+	if !hmac.Equal([]byte(p.Signature), []byte(p.computeSignature(key))) {
+		return fmt.Errorf("the signature of the plan of %s does not match its content", p.Command)
+	}
+	return nil
+}
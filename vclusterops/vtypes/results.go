@@ -0,0 +1,617 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vtypes
+
+import "time"
+
+// OpClassification describes the effect of an op, so that policies can decide
+// whether to run it without knowing the op by name
+type OpClassification struct {
+	// whether the op changes the cluster
+	Mutating bool `json:"mutating"`
+	// whether the changes of the op cannot be undone, e.g., dropping a node
+	Destructive bool `json:"destructive"`
+	// whether running the op again after it succeeded changes nothing more
+	Idempotent bool `json:"idempotent"`
+}
+
+// PlannedOp is an op of a command that was not run
+type PlannedOp struct {
+	Name           string           `json:"name"`
+	Description    string           `json:"description"`
+	Classification OpClassification `json:"classification"`
+}
+
+// ActivityStatus is what a command is running at a point in time
+type ActivityStatus struct {
+	Command string `json:"command"`
+	// whether the op engine of the command is running
+	Running bool `json:"running"`
+	// the op being run, its index among the ops of the op engine, and its
+	// phase: prepare, execute or finalize
+	Op            string `json:"op"`
+	OpDescription string `json:"op_description"`
+	OpIndex       int    `json:"op_index"`
+	OpCount       int    `json:"op_count"`
+	Phase         string `json:"phase"`
+	// the hosts with a request in flight, sorted
+	HostsInFlight []string `json:"hosts_in_flight"`
+	// when the op engine and the op started, and how long the op has run
+	StartedAt   time.Time     `json:"started_at"`
+	OpStartedAt time.Time     `json:"op_started_at"`
+	OpElapsed   time.Duration `json:"op_elapsed_ns"`
+}
+
+// NodeInfo represents information to identify a node.
+type NodeInfo struct {
+	Address     string `json:"address"`
+	Name        string `json:"name"` // vnode name, e.g., v_dbname_node0001
+	State       string `json:"state"`
+	CatalogPath string `json:"catalog_path"`
+	Subcluster  string `json:"subcluster"`
+	Sandbox     string `json:"sandbox"`
+	IsPrimary   bool   `json:"is_primary"`
+	Version     string `json:"version"`
+}
+
+// NodeInfo does not contain Eon specific information
+type NodeInfoEnterprise struct {
+	Address     string `json:"address"`
+	Name        string `json:"name"` // vnode name, e.g., v_dbname_node0001
+	State       string `json:"state"`
+	CatalogPath string `json:"catalog_path"`
+	Version     string `json:"version"`
+}
+
+type DrainingStatus struct {
+	SubclusterName string `json:"subcluster_name"`
+	Status         string `json:"drain_status"`
+	RedirectTo     string `json:"redirect_to"`
+}
+
+type DrainingStatusList struct {
+	StatusList []DrainingStatus `json:"draining_status_list"`
+}
+
+type ReplicationStatusResponse struct {
+	// Time replication was started
+	StartTime string `json:"start_time"`
+
+	// End time, if replication has completed
+	EndTime string `json:"end_time"`
+
+	// Current replication operation name. Possible values in order:
+	// - 'load_snapshot_prep'
+	// - 'data_transfer' - optional if source and target communal storage
+	//    are the same
+	// - 'load_snapshot' - replication is complete if this op has a
+	//    status of 'completed', unless the projections are rebuilt
+	// - 'rebuild_projections' - only if the projection mode is 'rebuild',
+	//    replication is complete if this op has a status of 'completed'
+	OpName string `json:"op_name"`
+
+	// Current replication operation status. Possible values:
+	//   'started', 'failed', 'completed'
+	Status string `json:"status"`
+
+	// Node the current replication operation is on
+	NodeName string `json:"node_name"`
+
+	// Number of bytes transferred as part of replication
+	SentBytes int64 `json:"sent_bytes"`
+
+	// Number of rows transferred as part of replication
+	SentRows int64 `json:"sent_rows"`
+
+	// Total number of bytes to be transferred as part of replication
+	TotalBytes    int64 `json:"total_bytes"`
+	TransactionID int64 `json:"txn_id"`
+
+	// How the projections of the replicated tables are handled, 'replicate'
+	// or 'rebuild'. Empty if the target database does not report it.
+	ProjectionMode string `json:"projection_mode,omitempty"`
+
+	// Number of projections rebuilt so far, and in total, by the
+	// 'rebuild_projections' op
+	RebuiltProjections int64 `json:"rebuilt_projections"`
+	TotalProjections   int64 `json:"total_projections"`
+}
+
+// ReplicationJobStatus is the status of an asynchronous replication job, as
+// summarized from the statuses of its ops on the nodes of the target database
+type ReplicationJobStatus struct {
+	TransactionID int64  `json:"txn_id"`
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time"`
+	// the current stage of the job, see ReplicationStatusResponse.OpName
+	Stage string `json:"stage"`
+	// the status of the current stage: 'started', 'failed', 'completed', or
+	// 'canceled'
+	Status   string `json:"status"`
+	NodeName string `json:"node_name"`
+
+	SentBytes  int64 `json:"sent_bytes"`
+	TotalBytes int64 `json:"total_bytes"`
+	SentRows   int64 `json:"sent_rows"`
+	// the projections rebuilt so far, and in total, when the projections are
+	// rebuilt on the target database
+	CompletedObjects int64 `json:"completed_objects"`
+	TotalObjects     int64 `json:"total_objects"`
+
+	// the ops of the job that failed
+	Errors []ReplicationJobError `json:"errors,omitempty"`
+}
+
+// ReplicationJobError is an op of a replication job that failed on a node
+type ReplicationJobError struct {
+	NodeName  string `json:"node_name"`
+	Stage     string `json:"stage"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// ReplicationProgress is the progress of an asynchronous replication job
+// since the previous poll of its status, for dashboards to show its live
+// throughput. The throughput is 0 for the first poll.
+type ReplicationProgress struct {
+	Status ReplicationStatusResponse `json:"status"`
+	// the percent of the current op that is done, from the transferred bytes
+	// or the rebuilt projections. -1 if the op does not report its total.
+	Percent float64 `json:"percent"`
+	// the time since the job started
+	Elapsed time.Duration `json:"elapsed_ns"`
+	// the time since the previous poll
+	Interval       time.Duration `json:"interval_ns"`
+	IntervalBytes  int64         `json:"interval_bytes"`
+	IntervalRows   int64         `json:"interval_rows"`
+	BytesPerSecond float64       `json:"bytes_per_second"`
+	RowsPerSecond  float64       `json:"rows_per_second"`
+	// whether the job made no progress for the stall timeout, and for how long
+	Stalled    bool          `json:"stalled"`
+	StalledFor time.Duration `json:"stalled_for_ns"`
+}
+
+// ReplicationDiagnostics is a snapshot of the initiators of a stalled
+// replication job, collected to tell why the job stopped making progress
+type ReplicationDiagnostics struct {
+	CollectedAt time.Time                    `json:"collected_at"`
+	Hosts       []ReplicationHostDiagnostics `json:"hosts"`
+}
+
+// ReplicationHostDiagnostics is the diagnostics of the initiator host of the
+// source or the target database of a replication job
+type ReplicationHostDiagnostics struct {
+	Host string `json:"host"`
+	// "source" or "target"
+	Role string `json:"role"`
+	// the sessions of the replication job on the host
+	Sessions []ReplicationSession `json:"sessions"`
+	// the probe of the network from the host to the initiator of the other
+	// database, nil if that initiator is not known
+	Probe *NetworkProbe `json:"probe,omitempty"`
+	// the last lines of vertica.log on the host
+	LogTail []string `json:"log_tail"`
+	// why the diagnostics could not be collected from the host
+	Error string `json:"error,omitempty"`
+}
+
+// ReplicationSession is a session that runs a replication job
+type ReplicationSession struct {
+	SessionID  string `json:"session_id"`
+	UserName   string `json:"user_name"`
+	ClientHost string `json:"client_hostname"`
+	Statement  string `json:"current_statement"`
+	// what the statement waits for, e.g., a lock or the network, if anything
+	WaitReason     string `json:"wait_reason"`
+	RunningSeconds int64  `json:"running_seconds"`
+}
+
+// NetworkProbe is the result of probing the network between two hosts
+type NetworkProbe struct {
+	Peer      string  `json:"peer"`
+	Reachable bool    `json:"reachable"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type NodeState struct {
+	Name                     string   `json:"name"`
+	ID                       uint64   `json:"node_id"`
+	Address                  string   `json:"address"`
+	State                    string   `json:"state"`
+	Database                 string   `json:"database"`
+	IsPrimary                bool     `json:"is_primary"`
+	IsReadOnly               bool     `json:"is_readonly"`
+	CatalogPath              string   `json:"catalog_path"`
+	DataPath                 []string `json:"data_path"`
+	DepotPath                string   `json:"depot_path"`
+	SubclusterName           string   `json:"subcluster_name"`
+	SubclusterID             uint64   `json:"subcluster_id"`
+	LastMsgFromNodeAt        string   `json:"last_msg_from_node_at"`
+	DownSince                string   `json:"down_since"`
+	Version                  string   `json:"build_info"`
+	SandboxName              string   `json:"sandbox_name"`
+	NumberShardSubscriptions uint     `json:"number_shard_subscriptions"`
+}
+
+type StorageLocation struct {
+	Name        string `json:"name"`
+	ID          uint64 `json:"location_id"`
+	Label       string `json:"label"`
+	UsageType   string `json:"location_usage_type"`
+	Path        string `json:"location_path"`
+	SharingType string `json:"location_sharing_type"`
+	MaxSize     uint64 `json:"max_size"`
+	DiskPercent string `json:"disk_percent"`
+	HasCatalog  bool   `json:"has_catalog"`
+	Retired     bool   `json:"retired"`
+}
+
+type StorageLocations struct {
+	StorageLocList []StorageLocation `json:"storage_location_list"`
+}
+
+type NodeDetails struct {
+	NodeState
+	StorageLocations
+}
+
+type NodesDetails []NodeDetails
+
+// RestorePoint contains information about a single restore point.
+type RestorePoint struct {
+	// Name of the archive that this restore point was created in.
+	Archive string `json:"archive,omitempty"`
+	// The ID of the restore point. This is a form of a UID that is static for the restore point.
+	ID string `json:"id,omitempty"`
+	// The current index of this restore point. Lower value means it was taken more recently.
+	// This changes when new restore points are created.
+	Index int `json:"index,omitempty"`
+	// The timestamp when the restore point was created.
+	Timestamp string `json:"timestamp,omitempty"`
+	// The version of Vertica running when the restore point was created.
+	VerticaVersion string `json:"vertica_version,omitempty"`
+}
+
+// InstallPackageStatus provides status for each package install attempted.
+type InstallPackageStatus struct {
+	Packages []PackageStatus `json:"packages"`
+}
+
+// PackageStatus has install status for a single package.
+type PackageStatus struct {
+	// Name of the package this status is for
+	PackageName string `json:"package_name"`
+	// One word outcome of the install status:
+	// Skipped, Success or Failure
+	InstallStatus string `json:"install_status"`
+}
+
+// TopologyLock is the lease that a command holds while it changes the
+// topology of a database, so that commands of other vcluster processes do not
+// change it at the same time
+type TopologyLock struct {
+	// random token of the holder, which is needed to renew or release the lease
+	Token     string    `json:"token"`
+	Owner     string    `json:"owner"`
+	Host      string    `json:"host"`
+	PID       int       `json:"pid"`
+	Command   string    `json:"command"`
+	RequestID string    `json:"request_id"`
+	StartTime time.Time `json:"start_time"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LicenseLimits are the limits of a Vertica license, where 0 means unlimited
+type LicenseLimits struct {
+	NodeLimit      int   `json:"node_limit"`
+	CPULimit       int   `json:"cpu_limit"`
+	SizeLimitBytes int64 `json:"size_limit_bytes"`
+}
+
+// LicenseUsage is what a database counts against the limits of its license
+type LicenseUsage struct {
+	NodeCount        int   `json:"node_count"`
+	CPUCount         int   `json:"cpu_count"`
+	RawDataSizeBytes int64 `json:"raw_data_size_bytes"`
+}
+
+// DataCollectorPolicy is the retention policy of a Data Collector component
+type DataCollectorPolicy struct {
+	Component   string `json:"component"`
+	Description string `json:"description"`
+	// size of the memory buffer and of the data on disk of the component
+	MemoryBufferKB int64 `json:"memory_buffer_kb"`
+	DiskSizeKB     int64 `json:"disk_size_kb"`
+	// how long the data of the component is kept, 0 if it is only limited by size
+	IntervalSeconds int64 `json:"interval_seconds"`
+}
+
+// OpTiming is how long an op of a command took in each of its phases. The
+// request time is the time spent waiting for the hosts, and the process time
+// is the rest of the execute time, e.g., parsing the responses.
+type OpTiming struct {
+	Name     string        `json:"name"`
+	Prepare  time.Duration `json:"prepare_ns"`
+	Execute  time.Duration `json:"execute_ns"`
+	Request  time.Duration `json:"request_ns"`
+	Process  time.Duration `json:"process_ns"`
+	Finalize time.Duration `json:"finalize_ns"`
+	// how long the requests to each host took, keyed by host address
+	Hosts map[string]time.Duration `json:"hosts_ns,omitempty"`
+}
+
+// HostRetries is how many times the requests of a command to a host were
+// retried, why, and how long the retries took. A host with retries may be
+// flapping, even if the command eventually succeeded.
+type HostRetries struct {
+	Attempts int `json:"attempts"`
+	// the error that led to each attempt, prefixed with the name of the op
+	Reasons    []string      `json:"reasons"`
+	TotalDelay time.Duration `json:"total_delay_ns"`
+}
+
+// HostConnectionMetrics are the HTTP connections of commands to a host: how
+// many requests could not connect, how many of these failed the TLS
+// handshake, and how long the connected requests took to respond. They tell
+// networking issues apart from failures of the requests themselves.
+type HostConnectionMetrics struct {
+	Requests int `json:"requests"`
+	// the requests that could not connect, including the TLS errors
+	ConnectionFailures int `json:"connection_failures"`
+	TLSErrors          int `json:"tls_errors"`
+	// the time to the response headers of the connected requests
+	TotalLatency time.Duration `json:"total_latency_ns"`
+	MaxLatency   time.Duration `json:"max_latency_ns"`
+	// the last connection failure, if any
+	LastError string `json:"last_error,omitempty"`
+	// the number of requests by how the certificate of the host was verified,
+	// as the NMA and HTTPS clients can verify it in different ways
+	TLSVerifyModes map[string]int `json:"tls_verify_modes,omitempty"`
+}
+
+// AverageLatency returns the average latency of the connected requests
+func (m *HostConnectionMetrics) AverageLatency() time.Duration {
+	connected := m.Requests - m.ConnectionFailures
+	if connected <= 0 {
+		return 0
+	}
+	return m.TotalLatency / time.Duration(connected)
+}
+
+// ReviveOmittedObject is an object of the database that a partial revive did
+// not load, as it is outside of the requested namespaces and schemas
+type ReviveOmittedObject struct {
+	// the type of the object, e.g., namespace, schema or table
+	Type string `json:"type"`
+	// the qualified name of the object
+	Name string `json:"name"`
+}
+
+// ReplicationSkippedObject is an object matched by a replication that was not
+// replicated, and why
+type ReplicationSkippedObject struct {
+	// the type of the object, e.g., schema, table or projection
+	Type string `json:"type"`
+	// the qualified name of the object
+	Name string `json:"name"`
+	// why the object was skipped, e.g., unsupported_type, excluded_by_pattern
+	// or permission_denied
+	Reason string `json:"reason"`
+}
+
+// NodeOutcome is what a command on a subcluster did to one of its nodes
+type NodeOutcome struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	// e.g., stopped, started, already_down or failed
+	Outcome string `json:"outcome"`
+	// why the command failed on the node, if it did
+	Error string `json:"error,omitempty"`
+}
+
+// ConfigValidationResult is the result of a validation query that was run on
+// a canary host of a configuration parameter change
+type ConfigValidationResult struct {
+	Host   string `json:"host"`
+	Query  string `json:"query"`
+	Passed bool   `json:"passed"`
+	// why the query failed, if it did
+	Error string `json:"error,omitempty"`
+}
+
+// SmokeTestResult is the result of the smoke test of a database, which checks
+// that every node can run a query on a table that the test creates
+type SmokeTestResult struct {
+	Passed bool            `json:"passed"`
+	Steps  []SmokeTestStep `json:"steps"`
+}
+
+// SmokeTestStep is a step of the smoke test on one host
+type SmokeTestStep struct {
+	Name     string        `json:"name"`
+	Host     string        `json:"host"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration_ns"`
+	// the rows that were loaded or read by the step
+	Rows  int64  `json:"rows"`
+	Error string `json:"error,omitempty"`
+}
+
+// FirstFailedStep returns the first step of the smoke test that failed, if any
+func (r *SmokeTestResult) FirstFailedStep() (SmokeTestStep, bool) {
+	for _, step := range r.Steps {
+		if !step.Passed {
+			return step, true
+		}
+	}
+	return SmokeTestStep{}, false
+}
+
+// SandboxInfo is a sandbox of a database, as recorded in the catalog
+type SandboxInfo struct {
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create_time"`
+	// the version of the catalog of the main cluster when the sandbox was
+	// created, and the current version of the catalog of the sandbox, which
+	// is 0 if no node of the sandbox is up
+	SandboxedCatalogVersion int64               `json:"sandboxed_catalog_version"`
+	CatalogVersion          int64               `json:"catalog_version"`
+	Subclusters             []SandboxSubcluster `json:"subclusters"`
+}
+
+// SandboxSubcluster is a subcluster of a sandbox
+type SandboxSubcluster struct {
+	Name  string        `json:"name"`
+	Nodes []SandboxNode `json:"nodes"`
+}
+
+// SandboxNode is a node of a sandboxed subcluster
+type SandboxNode struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	State   string `json:"state"`
+}
+
+// SchemaRowCountMismatch is a table whose row count in the target database
+// of a schema migration differs from the source database. A table that is
+// missing in the target database has -1 target rows.
+type SchemaRowCountMismatch struct {
+	Table      string `json:"table"`
+	SourceRows int64  `json:"source_rows"`
+	TargetRows int64  `json:"target_rows"`
+}
+
+// ScrutinizeCleanup is the stale scrutinize staging data that was deleted
+// from a host
+type ScrutinizeCleanup struct {
+	Host string `json:"host"`
+	// the IDs of the scrutinize collections whose staging data was deleted
+	RemovedIDs []string `json:"removed_ids"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
+
+// ClusterHealth is the health of a running cluster, as checked by a
+// HealthGate before a command changes the cluster
+type ClusterHealth struct {
+	TotalNodes int      `json:"total_nodes"`
+	DownNodes  []string `json:"down_nodes"`
+	// the up nodes whose catalog version is behind the latest one
+	UnsyncedNodes []string `json:"unsynced_nodes"`
+}
+
+// TopologySimulation is the predicted layout of a database after a
+// topology change, as computed without touching the cluster
+type TopologySimulation struct {
+	// the K-safety of the design before and after the change
+	KSafetyBefore int `json:"ksafety_before"`
+	KSafety       int `json:"ksafety"`
+	// the number of primary nodes after the change
+	PrimaryNodes int `json:"primary_nodes"`
+	// the nodes after the change, sorted by subcluster and address
+	Nodes []SimulatedNode `json:"nodes"`
+	// the control nodes after the change, sorted by address
+	ControlNodes []string `json:"control_nodes"`
+	// the layouts that would degrade the database, e.g., a node that
+	// subscribes to no shard
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SimulatedNode is the predicted layout of a node after a topology change
+type SimulatedNode struct {
+	// the name of an existing node, empty for the nodes the change adds
+	Name       string `json:"name,omitempty"`
+	Address    string `json:"address"`
+	Subcluster string `json:"subcluster"`
+	IsPrimary  bool   `json:"is_primary"`
+	IsNew      bool   `json:"is_new"`
+	// the number of segment shards the node subscribes to, the replica shard
+	// is not counted. Always 0 for an Enterprise database.
+	ShardSubscriptions int  `json:"shard_subscriptions"`
+	IsControlNode      bool `json:"is_control_node"`
+	// the address of the control node of the node
+	ControlNode string `json:"control_node"`
+}
+
+// ClusterReadiness is whether a cluster meets the conditions of a readiness
+// gate, and what was checked
+type ClusterReadiness struct {
+	Ready     bool          `json:"ready"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Health    ClusterHealth `json:"health"`
+	// the lowest percent that the depot of an up node is filled to, -1 if
+	// the depots are not checked
+	MinDepotWarmPercent float64 `json:"min_depot_warm_percent"`
+	// the shard subscriptions that are not active, e.g., during a rebalance
+	InactiveSubscriptions int `json:"inactive_subscriptions"`
+	// the conditions that do not hold
+	Unmet []UnmetReadyCondition `json:"unmet,omitempty"`
+}
+
+// UnmetReadyCondition is a condition of a readiness gate that does not hold
+type UnmetReadyCondition struct {
+	Condition string `json:"condition"`
+	Reason    string `json:"reason"`
+}
+
+// the severities of the findings of the doctor command, from the most severe
+const (
+	DoctorSeverityCritical = "critical"
+	DoctorSeverityWarning  = "warning"
+	DoctorSeverityInfo     = "info"
+)
+
+// DoctorFinding is a problem found by the doctor command
+type DoctorFinding struct {
+	// a stable, machine-readable code of the problem, e.g., NODE_DOWN
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	// the check that found the problem
+	Check   string   `json:"check"`
+	Hosts   []string `json:"hosts,omitempty"`
+	Message string   `json:"message"`
+	// a suggested command or action that fixes the problem
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// DoctorReport is the findings of the doctor command, the most severe first
+type DoctorReport struct {
+	CheckedAt time.Time       `json:"checked_at"`
+	Findings  []DoctorFinding `json:"findings"`
+	// the checks that could not be run, with the reason
+	SkippedChecks map[string]string `json:"skipped_checks,omitempty"`
+}
+
+// HasCritical returns whether any finding of the report is critical
+func (r *DoctorReport) HasCritical() bool {
+	for i := range r.Findings {
+		if r.Findings[i].Severity == DoctorSeverityCritical {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockingSession is a session that holds a catalog lock or runs DDL on an
+// object that a replication would copy
+type BlockingSession struct {
+	SessionID  string `json:"session_id"`
+	UserName   string `json:"user_name"`
+	ObjectName string `json:"object_name"`
+	LockMode   string `json:"lock_mode"`
+	// the statement that the session is running, if any
+	Statement string `json:"statement"`
+}
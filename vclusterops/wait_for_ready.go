@@ -0,0 +1,233 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	"github.com/vertica/vcluster/vclusterops/vtypes"
+)
+
+type (
+	ClusterReadiness    = vtypes.ClusterReadiness
+	UnmetReadyCondition = vtypes.UnmetReadyCondition
+)
+
+// the conditions of VWaitForReady
+const (
+	ReadyConditionRunning     = "running"
+	ReadyConditionAllNodesUp  = "all_nodes_up"
+	ReadyConditionCatalogSync = "catalog_synced"
+	ReadyConditionDepotWarm   = "depot_warm"
+	ReadyConditionNoRebalance = "no_rebalance"
+)
+
+// DefaultReadyPollInterval is the default time in seconds between two
+// checks of the conditions of VWaitForReady
+const DefaultReadyPollInterval = 5
+
+type VWaitForReadyOptions struct {
+	DatabaseOptions
+	// the conditions that must all hold for the cluster to be ready, on top of
+	// the database running
+	AllNodesUp    bool
+	CatalogSynced bool
+	// the percent that the depot of every up node must be filled to, so that
+	// queries do not read from communal storage. 0 does not check the depots.
+	MinDepotWarmPercent int
+	// no shard subscription is being added or removed, e.g., by a rebalance
+	NoRebalance bool
+	// the time in seconds between two checks of the conditions
+	PollInterval int
+}
+
+func VWaitForReadyOptionsFactory() VWaitForReadyOptions {
+	options := VWaitForReadyOptions{}
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VWaitForReadyOptions) setDefaultValues() {
+	options.DatabaseOptions.setDefaultValues()
+	options.AllNodesUp = true
+	options.CatalogSynced = true
+	options.NoRebalance = true
+	options.PollInterval = DefaultReadyPollInterval
+}
+
+func (options *VWaitForReadyOptions) validateParseOptions(logger vlog.Printer) error {
+	err := options.validateBaseOptions(WaitForReadyCmd, logger)
+	if err != nil {
+		return err
+	}
+	const maxPercent = 100
+	if options.MinDepotWarmPercent < 0 || options.MinDepotWarmPercent > maxPercent {
+		return fmt.Errorf("the depot warm percent must be between 0 and 100, got %d", options.MinDepotWarmPercent)
+	}
+	if options.PollInterval <= 0 {
+		return fmt.Errorf("the poll interval must be positive, got %d", options.PollInterval)
+	}
+	return options.setUsePasswordAndValidateUsernameIfNeeded(logger)
+}
+
+func (options *VWaitForReadyOptions) analyzeOptions() (err error) {
+	// resolve RawHosts to be IP addresses
+	if len(options.RawHosts) > 0 {
+		options.Hosts, err = util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (options *VWaitForReadyOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VWaitForReady blocks until the cluster meets all the conditions of the
+// options, e.g., as the single readiness gate of a deployment pipeline. It
+// returns the readiness of the last check. If ctx is done first, the error
+// wraps the error of ctx along with the conditions that do not hold.
+func (vcc VClusterCommands) VWaitForReady(ctx context.Context, options *VWaitForReadyOptions) (*ClusterReadiness, error) {
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(options.PollInterval) * time.Second
+	for {
+		readiness := vcc.checkReadiness(options)
+		if readiness.Ready {
+			vcc.Log.PrintInfo("Database %s is ready", options.DBName)
+			return readiness, nil
+		}
+		reasons := make([]string, 0, len(readiness.Unmet))
+		for _, unmet := range readiness.Unmet {
+			reasons = append(reasons, unmet.Reason)
+		}
+		vcc.Log.Info("database is not ready yet", "reasons", reasons)
+
+		select {
+		case <-ctx.Done():
+			return readiness, fmt.Errorf("database %s is not ready: %s: %w", options.DBName,
+				strings.Join(reasons, "; "), ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// checkReadiness checks the conditions of the options once. A condition that
+// cannot be checked does not hold.
+func (vcc VClusterCommands) checkReadiness(options *VWaitForReadyOptions) *ClusterReadiness {
+	readiness := &ClusterReadiness{CheckedAt: time.Now().UTC(), MinDepotWarmPercent: -1}
+	unmet := func(condition, reason string, args ...any) {
+		readiness.Unmet = append(readiness.Unmet, UnmetReadyCondition{Condition: condition,
+			Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	isRunning := false
+	healthOp, err := makeHTTPSGetClusterHealthOp(options.Hosts, options.usePassword, options.UserName,
+		options.Password, &readiness.Health, &isRunning)
+	if err == nil {
+		err = options.runClusterOpEngine(vcc.Log, []clusterOp{&healthOp})
+	}
+	if err != nil || !isRunning {
+		unmet(ReadyConditionRunning, "the database is not running")
+		return readiness
+	}
+	if options.AllNodesUp && len(readiness.Health.DownNodes) > 0 {
+		unmet(ReadyConditionAllNodesUp, "nodes %s are down", strings.Join(readiness.Health.DownNodes, ", "))
+	}
+	if options.CatalogSynced && len(readiness.Health.UnsyncedNodes) > 0 {
+		unmet(ReadyConditionCatalogSync, "the catalog of nodes %s is not in sync",
+			strings.Join(readiness.Health.UnsyncedNodes, ", "))
+	}
+
+	if options.NoRebalance {
+		if reason := vcc.checkNoRebalance(options, readiness); reason != "" {
+			unmet(ReadyConditionNoRebalance, "%s", reason)
+		}
+	}
+	if options.MinDepotWarmPercent > 0 {
+		if reason := vcc.checkDepotWarm(options, readiness); reason != "" {
+			unmet(ReadyConditionDepotWarm, "%s", reason)
+		}
+	}
+	readiness.Ready = len(readiness.Unmet) == 0
+	return readiness
+}
+
+func (vcc VClusterCommands) checkNoRebalance(options *VWaitForReadyOptions, readiness *ClusterReadiness) string {
+	var subscriptions []subscriptionInfo
+	subscriptionsOp, err := makeHTTPSGetSubscriptionsOp(options.Hosts, options.usePassword, options.UserName,
+		options.Password, &subscriptions)
+	if err == nil {
+		err = options.runClusterOpEngine(vcc.Log, []clusterOp{&subscriptionsOp})
+	}
+	if err != nil {
+		return fmt.Sprintf("fail to get the shard subscriptions: %s", err)
+	}
+	for _, subscription := range subscriptions {
+		if subscription.SubscriptionState != ACTIVE {
+			readiness.InactiveSubscriptions++
+		}
+	}
+	if readiness.InactiveSubscriptions > 0 {
+		return fmt.Sprintf("%d shard subscriptions are not active", readiness.InactiveSubscriptions)
+	}
+	return ""
+}
+
+func (vcc VClusterCommands) checkDepotWarm(options *VWaitForReadyOptions, readiness *ClusterReadiness) string {
+	var depots []depotUsage
+	depotUsageOp, err := makeHTTPSGetDepotUsageOp(options.Hosts, options.usePassword, options.UserName,
+		options.Password, &depots)
+	if err == nil {
+		err = options.runClusterOpEngine(vcc.Log, []clusterOp{&depotUsageOp})
+	}
+	if err != nil {
+		return fmt.Sprintf("fail to get the depot usage: %s", err)
+	}
+	return checkDepotsWarm(depots, options.MinDepotWarmPercent, readiness)
+}
+
+// checkDepotsWarm records the lowest depot usage, and returns the nodes whose
+// depot is below the percent
+func checkDepotsWarm(depots []depotUsage, minPercent int, readiness *ClusterReadiness) string {
+	var cold []string
+	for i := range depots {
+		percent := depots[i].percent()
+		if readiness.MinDepotWarmPercent < 0 || percent < readiness.MinDepotWarmPercent {
+			readiness.MinDepotWarmPercent = percent
+		}
+		if percent < float64(minPercent) {
+			cold = append(cold, fmt.Sprintf("%s (%.0f%%)", depots[i].NodeName, percent))
+		}
+	}
+	if len(cold) > 0 {
+		return fmt.Sprintf("the depot of nodes %s is below %d%%", strings.Join(cold, ", "), minPercent)
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+/*
+ (c) Copyright [2023-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestWaitForReadyOptions(t *testing.T) {
+	options := VWaitForReadyOptionsFactory()
+	assert.True(t, options.AllNodesUp)
+	assert.True(t, options.CatalogSynced)
+	assert.True(t, options.NoRebalance)
+	assert.Equal(t, DefaultReadyPollInterval, options.PollInterval)
+
+	options.DBName = "test_db"
+	options.RawHosts = []string{"192.168.1.101"}
+	assert.NoError(t, options.validateAnalyzeOptions(vlog.Printer{}))
+
+	options.MinDepotWarmPercent = 101
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), "depot warm percent")
+	options.MinDepotWarmPercent = 80
+
+	options.PollInterval = 0
+	assert.ErrorContains(t, options.validateAnalyzeOptions(vlog.Printer{}), "poll interval")
+}
+
+func TestCheckDepotsWarm(t *testing.T) {
+	depots := []depotUsage{
+		{NodeName: "v_test_db_node0001", MaxSizeBytes: 100, UsedBytes: 90},
+		{NodeName: "v_test_db_node0002", MaxSizeBytes: 100, UsedBytes: 40},
+	}
+	readiness := &ClusterReadiness{MinDepotWarmPercent: -1}
+	reason := checkDepotsWarm(depots, 50, readiness)
+	assert.Equal(t, "the depot of nodes v_test_db_node0002 (40%) is below 50%", reason)
+	assert.Equal(t, float64(40), readiness.MinDepotWarmPercent)
+
+	readiness = &ClusterReadiness{MinDepotWarmPercent: -1}
+	assert.Empty(t, checkDepotsWarm(depots, 40, readiness))
+}
+
+func TestWaitForReadyCancelled(t *testing.T) {
+	vcc := VClusterCommands{}
+	options := VWaitForReadyOptionsFactory()
+	options.DBName = "test_db"
+	// the database cannot be reached, so it never gets ready
+	options.RawHosts = []string{"127.0.0.1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	readiness, err := vcc.VWaitForReady(ctx, &options)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, ReadyConditionRunning, readiness.Unmet[0].Condition)
+}